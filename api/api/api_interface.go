@@ -46,6 +46,8 @@ type TenantInterface interface {
 	TenantsGetByName(w http.ResponseWriter, r *http.Request)
 	SumTenants(w http.ResponseWriter, r *http.Request)
 	SingleTenantResources(w http.ResponseWriter, r *http.Request)
+	ImagePolicy(w http.ResponseWriter, r *http.Request)
+	MeshTLSPolicy(w http.ResponseWriter, r *http.Request)
 	GetSupportProtocols(w http.ResponseWriter, r *http.Request)
 	TransPlugins(w http.ResponseWriter, r *http.Request)
 	ServicesCount(w http.ResponseWriter, r *http.Request)
@@ -86,6 +88,13 @@ type ServiceInterface interface {
 	AddServiceMonitors(w http.ResponseWriter, r *http.Request)
 	DeleteServiceMonitors(w http.ResponseWriter, r *http.Request)
 	UpdateServiceMonitors(w http.ResponseWriter, r *http.Request)
+	ScheduledScalingPolicies(w http.ResponseWriter, r *http.Request)
+	UpdateScheduledScalingPolicy(w http.ResponseWriter, r *http.Request)
+	DeleteScheduledScalingPolicy(w http.ResponseWriter, r *http.Request)
+	Canary(w http.ResponseWriter, r *http.Request)
+	PromoteCanary(w http.ResponseWriter, r *http.Request)
+	Webhook(w http.ResponseWriter, r *http.Request)
+	TriggerWebhook(w http.ResponseWriter, r *http.Request)
 }
 
 //TenantInterfaceWithV1 funcs for both v2 and v1
@@ -204,4 +213,7 @@ type AppRestoreInterface interface {
 // PodInterface defines api methods about k8s pods.
 type PodInterface interface {
 	PodDetail(w http.ResponseWriter, r *http.Request)
+	WatchPods(w http.ResponseWriter, r *http.Request)
+	WatchServiceStatus(w http.ResponseWriter, r *http.Request)
+	WatchAppStatus(w http.ResponseWriter, r *http.Request)
 }
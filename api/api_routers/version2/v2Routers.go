@@ -47,6 +47,8 @@ func (v2 *V2) Routes() chi.Router {
 	r.Mount("/app", v2.appRouter())
 	r.Get("/health", controller.GetManager().Health)
 	r.Post("/alertmanager-webhook", controller.GetManager().AlertManagerWebHook)
+	// git push events, verified by the secret of the component webhook
+	r.Post("/webhooks/{service_id}", controller.GetManager().TriggerWebhook)
 	r.Get("/version", controller.GetManager().Version)
 	// deprecated use /gateway/ports
 	r.Mount("/port", v2.portRouter())
@@ -123,6 +125,12 @@ func (v2 *V2) tenantNameRouter() chi.Router {
 	r.Post("/servicecheck", controller.Check)
 	r.Get("/servicecheck/{uuid}", controller.GetServiceCheckInfo)
 	r.Get("/resources", controller.GetManager().SingleTenantResources)
+	r.Get("/image-policy", controller.GetManager().ImagePolicy)
+	r.Put("/image-policy", controller.GetManager().ImagePolicy)
+	r.Delete("/image-policy", controller.GetManager().ImagePolicy)
+	r.Get("/mesh-tls", controller.GetManager().MeshTLSPolicy)
+	r.Put("/mesh-tls", controller.GetManager().MeshTLSPolicy)
+	r.Delete("/mesh-tls", controller.GetManager().MeshTLSPolicy)
 	r.Get("/services", controller.GetManager().ServicesInfo)
 	// Create application
 	r.Post("/services", middleware.WrapEL(controller.GetManager().CreateService, dbmodel.TargetTypeService, "create-service", dbmodel.SYNEVENTTYPE))
@@ -150,6 +158,12 @@ func (v2 *V2) tenantNameRouter() chi.Router {
 	r.Delete("/groupapp/backups/{backup_id}", controller.DeleteBackup)
 	r.Post("/groupapp/backups/{backup_id}/restore", controller.Restore)
 	r.Get("/groupapp/backups/{backup_id}/restore/{restore_id}", controller.RestoreResult)
+	r.Get("/groupapp/volume-backups", controller.VolumeBackups)
+	r.Get("/groupapp/backup-schedules", controller.BackupSchedules)
+	r.Post("/groupapp/backup-schedules", controller.NewBackupSchedule)
+	r.Get("/groupapp/backup-schedules/{schedule_id}", controller.GetBackupSchedule)
+	r.Put("/groupapp/backup-schedules/{schedule_id}", controller.UpdateBackupSchedule)
+	r.Delete("/groupapp/backup-schedules/{schedule_id}", controller.DeleteBackupSchedule)
 	r.Post("/deployversions", controller.GetManager().GetManyDeployVersion)
 	// Team resource limit
 	r.Post("/limit_memory", controller.GetManager().LimitTenantMemory)
@@ -201,6 +215,7 @@ func (v2 *V2) serviceRouter() chi.Router {
 	r.Post("/upgrade", middleware.WrapEL(controller.GetManager().UpgradeService, dbmodel.TargetTypeService, "upgrade-service", dbmodel.ASYNEVENTTYPE))
 	// Application status acquisition (act)
 	r.Get("/status", controller.GetManager().StatusService)
+	r.Get("/status/watch", controller.GetManager().WatchServiceStatus)
 	// Build version list
 	r.Get("/build-list", controller.GetManager().BuildList)
 	// Build version operation
@@ -250,6 +265,7 @@ func (v2 *V2) serviceRouter() chi.Router {
 
 	// Get the application case (source)
 	r.Get("/pods", controller.GetManager().Pods)
+	r.Get("/pods/watch", controller.GetManager().WatchPods)
 
 	// Application probe addition, deletion and modification (source)
 	r.Post("/probe", middleware.WrapEL(controller.GetManager().Probe, dbmodel.TargetTypeService, "add-service-probe", dbmodel.SYNEVENTTYPE))
@@ -291,6 +307,18 @@ func (v2 *V2) serviceRouter() chi.Router {
 	r.Post("/xparules", middleware.WrapEL(controller.GetManager().AutoscalerRules, dbmodel.TargetTypeService, "add-app-autoscaler-rule", dbmodel.SYNEVENTTYPE))
 	r.Put("/xparules", middleware.WrapEL(controller.GetManager().AutoscalerRules, dbmodel.TargetTypeService, "update-app-autoscaler-rule", dbmodel.SYNEVENTTYPE))
 	r.Get("/xparecords", controller.GetManager().ScalingRecords)
+	r.Get("/scheduled-scaling-policies", controller.GetManager().ScheduledScalingPolicies)
+	r.Post("/scheduled-scaling-policies", middleware.WrapEL(controller.GetManager().ScheduledScalingPolicies, dbmodel.TargetTypeService, "add-app-scheduled-scaling-policy", dbmodel.SYNEVENTTYPE))
+	r.Put("/scheduled-scaling-policies/{policy_id}", middleware.WrapEL(controller.GetManager().UpdateScheduledScalingPolicy, dbmodel.TargetTypeService, "update-app-scheduled-scaling-policy", dbmodel.SYNEVENTTYPE))
+	r.Delete("/scheduled-scaling-policies/{policy_id}", middleware.WrapEL(controller.GetManager().DeleteScheduledScalingPolicy, dbmodel.TargetTypeService, "delete-app-scheduled-scaling-policy", dbmodel.SYNEVENTTYPE))
+	r.Get("/canary", controller.GetManager().Canary)
+	r.Post("/canary", middleware.WrapEL(controller.GetManager().Canary, dbmodel.TargetTypeService, "start-app-canary", dbmodel.ASYNEVENTTYPE))
+	r.Put("/canary", middleware.WrapEL(controller.GetManager().Canary, dbmodel.TargetTypeService, "update-app-canary", dbmodel.ASYNEVENTTYPE))
+	r.Delete("/canary", middleware.WrapEL(controller.GetManager().Canary, dbmodel.TargetTypeService, "abort-app-canary", dbmodel.ASYNEVENTTYPE))
+	r.Post("/canary/promote", middleware.WrapEL(controller.GetManager().PromoteCanary, dbmodel.TargetTypeService, "promote-app-canary", dbmodel.ASYNEVENTTYPE))
+	r.Get("/webhook", controller.GetManager().Webhook)
+	r.Put("/webhook", middleware.WrapEL(controller.GetManager().Webhook, dbmodel.TargetTypeService, "update-app-webhook", dbmodel.SYNEVENTTYPE))
+	r.Delete("/webhook", middleware.WrapEL(controller.GetManager().Webhook, dbmodel.TargetTypeService, "delete-app-webhook", dbmodel.SYNEVENTTYPE))
 
 	// Service monitor
 	r.Post("/service-monitors", middleware.WrapEL(controller.GetManager().AddServiceMonitors, dbmodel.TargetTypeService, "add-app-service-monitor", dbmodel.SYNEVENTTYPE))
@@ -316,6 +344,10 @@ func (v2 *V2) applicationRouter() chi.Router {
 
 	r.Put("/ports", controller.GetManager().BatchUpdateComponentPorts)
 	r.Put("/status", controller.GetManager().GetAppStatus)
+	r.Get("/status/watch", controller.GetManager().WatchAppStatus)
+	r.Get("/mesh-tls", controller.GetManager().MeshTLSPolicy)
+	r.Put("/mesh-tls", controller.GetManager().MeshTLSPolicy)
+	r.Delete("/mesh-tls", controller.GetManager().MeshTLSPolicy)
 
 	r.Delete("/configgroups/{config_group_name}", controller.GetManager().DeleteConfigGroup)
 	r.Get("/configgroups", controller.GetManager().ListConfigGroups)
@@ -28,6 +28,7 @@ import (
 	"github.com/gridworkz/kato/api/handler"
 	"github.com/gridworkz/kato/api/middleware"
 	"github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
 	"github.com/gridworkz/kato/db/errors"
 	httputil "github.com/gridworkz/kato/util/http"
 )
@@ -52,6 +53,10 @@ func (t *TenantStruct) addAutoscalerRule(w http.ResponseWriter, r *http.Request)
 	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
 	req.ServiceID = serviceID
 	if err := handler.GetServiceManager().AddAutoscalerRule(&req); err != nil {
+		if _, ok := err.(bcode.Coder); ok {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
 		if err == errors.ErrRecordAlreadyExist {
 			httputil.ReturnError(r, w, 400, err.Error())
 			return
@@ -72,6 +77,10 @@ func (t *TenantStruct) updAutoscalerRule(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := handler.GetServiceManager().UpdAutoscalerRule(&req); err != nil {
+		if _, ok := err.(bcode.Coder); ok {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
 		if err == errors.ErrRecordAlreadyExist {
 			httputil.ReturnError(r, w, 400, err.Error())
 			return
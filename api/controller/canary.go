@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	api_model "github.com/gridworkz/kato/api/model"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+//Canary gets, starts, adjusts or aborts the canary release of the component.
+func (t *TenantStruct) Canary(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		t.getCanary(w, r)
+	case "POST", "PUT":
+		t.applyCanary(w, r)
+	case "DELETE":
+		t.abortCanary(w, r)
+	}
+}
+
+func (t *TenantStruct) getCanary(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	canary, err := handler.GetServiceManager().GetCanary(serviceID)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, canary)
+}
+
+func (t *TenantStruct) applyCanary(w http.ResponseWriter, r *http.Request) {
+	var req api_model.CanaryReq
+	ok := httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil)
+	if !ok {
+		return
+	}
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	eventID := r.Context().Value(middleware.ContextKey("event_id")).(string)
+	apply := handler.GetServiceManager().UpdateCanary
+	if r.Method == "POST" {
+		apply = handler.GetServiceManager().StartCanary
+	}
+	canary, err := apply(serviceID, eventID, &req)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, canary)
+}
+
+func (t *TenantStruct) abortCanary(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	eventID := r.Context().Value(middleware.ContextKey("event_id")).(string)
+	canary, err := handler.GetServiceManager().AbortCanary(serviceID, eventID)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, canary)
+}
+
+//PromoteCanary upgrades the component to the canary version.
+func (t *TenantStruct) PromoteCanary(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	eventID := r.Context().Value(middleware.ContextKey("event_id")).(string)
+	canary, err := handler.GetServiceManager().PromoteCanary(serviceID, eventID)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, canary)
+}
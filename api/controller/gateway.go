@@ -32,6 +32,9 @@ import (
 	"github.com/gridworkz/kato/api/middleware"
 	api_model "github.com/gridworkz/kato/api/model"
 	"github.com/gridworkz/kato/cmd/api/option"
+	"github.com/gridworkz/kato/gateway/annotations/authreq"
+	"github.com/gridworkz/kato/gateway/annotations/ipaccess"
+	"github.com/gridworkz/kato/gateway/annotations/ratelimit"
 	"github.com/gridworkz/kato/mq/client"
 	httputil "github.com/gridworkz/kato/util/http"
 )
@@ -68,6 +71,40 @@ func validateDomain(domain string) []string {
 	return errs
 }
 
+func validateRateLimit(values url.Values, rps, burst, connections int, key string) {
+	if rps < 0 {
+		values["rate_limit_rps"] = []string{"The rate_limit_rps field can not be negative"}
+	}
+	if burst < 0 {
+		values["rate_limit_burst"] = []string{"The rate_limit_burst field can not be negative"}
+	}
+	if connections < 0 {
+		values["connection_limit"] = []string{"The connection_limit field can not be negative"}
+	}
+	if err := ratelimit.ValidateKey(key); err != nil {
+		values["rate_limit_key"] = []string{err.Error()}
+	}
+}
+
+func validateAccessPolicies(values url.Values, allow, deny, secret, forwardAuthURL string) {
+	if _, invalid := ipaccess.ParseSourceRange(allow); len(invalid) > 0 {
+		values["ip_allow_list"] = []string{fmt.Sprintf("Invalid ip or CIDR: %s", strings.Join(invalid, ","))}
+	}
+	if _, invalid := ipaccess.ParseSourceRange(deny); len(invalid) > 0 {
+		values["ip_deny_list"] = []string{fmt.Sprintf("Invalid ip or CIDR: %s", strings.Join(invalid, ","))}
+	}
+	if secret != "" {
+		if errs := k8svalidation.IsDNS1123Subdomain(secret); len(errs) > 0 {
+			values["basic_auth_secret"] = []string{"The basic_auth_secret field is invalid"}
+		}
+	}
+	if forwardAuthURL != "" {
+		if err := authreq.ValidateURL(forwardAuthURL); err != nil {
+			values["forward_auth_url"] = []string{err.Error()}
+		}
+	}
+}
+
 func (g *GatewayStruct) addHTTPRule(w http.ResponseWriter, r *http.Request) {
 	var req api_model.AddHTTPRuleStruct
 	ok := httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil)
@@ -93,6 +130,8 @@ func (g *GatewayStruct) addHTTPRule(w http.ResponseWriter, r *http.Request) {
 		logrus.Debugf("Invalid domain: %s", strings.Join(errs, ";"))
 		values["domain"] = []string{"The domain field is invalid"}
 	}
+	validateRateLimit(values, req.RateLimitRPS, req.RateLimitBurst, req.ConnectionLimit, req.RateLimitKey)
+	validateAccessPolicies(values, req.IPAllowList, req.IPDenyList, req.BasicAuthSecret, req.ForwardAuthURL)
 	if len(values) != 0 {
 		httputil.ReturnValidationError(r, w, values)
 		return
@@ -141,6 +180,8 @@ func (g *GatewayStruct) updateHTTPRule(w http.ResponseWriter, r *http.Request) {
 		logrus.Debugf("Invalid domain: %s", strings.Join(errs, ";"))
 		values["domain"] = []string{"The domain field is invalid"}
 	}
+	validateRateLimit(values, req.RateLimitRPS, req.RateLimitBurst, req.ConnectionLimit, req.RateLimitKey)
+	validateAccessPolicies(values, req.IPAllowList, req.IPDenyList, req.BasicAuthSecret, req.ForwardAuthURL)
 	if len(values) != 0 {
 		httputil.ReturnValidationError(r, w, values)
 		return
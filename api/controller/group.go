@@ -19,6 +19,8 @@
 package controller
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -118,15 +120,105 @@ func GetBackup(w http.ResponseWriter, r *http.Request) {
 //DeleteBackup delete backup
 func DeleteBackup(w http.ResponseWriter, r *http.Request) {
 	backupID := chi.URLParam(r, "backup_id")
+	// the object storage of the full-online backup created on demand, it is optional
+	var req struct {
+		S3Config *group.StorageConfig `json:"s3_config"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		httputil.ReturnError(r, w, 400, err.Error())
+		return
+	}
 
-	err := handler.GetAPPBackupHandler().DeleteBackup(backupID)
+	err := handler.GetAPPBackupHandler().DeleteBackup(backupID, req.S3Config)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			httputil.ReturnError(r, w, 404, "not found")
 			return
 		}
+		if err == group.ErrBackupStorageRequired {
+			httputil.ReturnError(r, w, 400, err.Error())
+			return
+		}
 		httputil.ReturnError(r, w, 500, err.Error())
 		return
 	}
 	httputil.ReturnSuccess(r, w, nil)
 }
+
+//BackupSchedules list the backup schedules of the group app
+func BackupSchedules(w http.ResponseWriter, r *http.Request) {
+	groupID := r.FormValue("group_id")
+	if groupID == "" {
+		httputil.ReturnError(r, w, 400, "group id can not be empty")
+		return
+	}
+	list, err := handler.GetAPPBackupHandler().ListBackupSchedules(groupID)
+	if err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, list)
+}
+
+//NewBackupSchedule create a backup schedule of the group app
+func NewBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	var req group.BackupSchedule
+	if !httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil) {
+		return
+	}
+	tenantID := r.Context().Value(middleware.ContextKey("tenant_id")).(string)
+	bean, err := handler.GetAPPBackupHandler().CreateBackupSchedule(tenantID, &req)
+	if err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, bean)
+}
+
+//GetBackupSchedule get one backup schedule
+func GetBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	bean, err := handler.GetAPPBackupHandler().GetBackupSchedule(chi.URLParam(r, "schedule_id"))
+	if err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, bean)
+}
+
+//UpdateBackupSchedule update the backup schedule
+func UpdateBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	var req group.BackupSchedule
+	if !httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil) {
+		return
+	}
+	bean, err := handler.GetAPPBackupHandler().UpdateBackupSchedule(chi.URLParam(r, "schedule_id"), &req)
+	if err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, bean)
+}
+
+//DeleteBackupSchedule delete the backup schedule
+func DeleteBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	if err := handler.GetAPPBackupHandler().DeleteBackupSchedule(chi.URLParam(r, "schedule_id")); err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, nil)
+}
+
+//VolumeBackups list the backup points of the volumes of the group app
+func VolumeBackups(w http.ResponseWriter, r *http.Request) {
+	groupID := r.FormValue("group_id")
+	if groupID == "" {
+		httputil.ReturnError(r, w, 400, "group id can not be empty")
+		return
+	}
+	list, err := handler.GetAPPBackupHandler().GetVolumeBackupHistory(groupID)
+	if err != nil {
+		err.Handle(r, w)
+		return
+	}
+	httputil.ReturnSuccess(r, w, list)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	api_model "github.com/gridworkz/kato/api/model"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+//ImagePolicy gets, sets or deletes the image policy of the tenant.
+func (t *TenantStruct) ImagePolicy(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		t.getImagePolicy(w, r)
+	case "PUT":
+		t.saveImagePolicy(w, r)
+	case "DELETE":
+		t.deleteImagePolicy(w, r)
+	}
+}
+
+func (t *TenantStruct) getImagePolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.ContextKey("tenant_id")).(string)
+	policy, err := handler.GetTenantManager().GetImagePolicy(tenantID)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policy)
+}
+
+func (t *TenantStruct) saveImagePolicy(w http.ResponseWriter, r *http.Request) {
+	var req api_model.ImagePolicyReq
+	ok := httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil)
+	if !ok {
+		return
+	}
+	tenantID := r.Context().Value(middleware.ContextKey("tenant_id")).(string)
+	policy, err := handler.GetTenantManager().SaveImagePolicy(tenantID, &req)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policy)
+}
+
+func (t *TenantStruct) deleteImagePolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.ContextKey("tenant_id")).(string)
+	if err := handler.GetTenantManager().DeleteImagePolicy(tenantID); err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, nil)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	api_model "github.com/gridworkz/kato/api/model"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+//MeshTLSPolicy gets, sets or deletes the mutual tls policy of the tenant, or of the application
+//if it is requested under the application router.
+func (t *TenantStruct) MeshTLSPolicy(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Context().Value(middleware.ContextKey("tenant_id")).(string)
+	appID, _ := r.Context().Value(middleware.ContextKey("app_id")).(string)
+	switch r.Method {
+	case "GET":
+		policy, err := handler.GetTenantManager().GetMeshTLSPolicy(tenantID, appID)
+		if err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, policy)
+	case "PUT":
+		var req api_model.MeshTLSPolicyReq
+		if !httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil) {
+			return
+		}
+		policy, err := handler.GetTenantManager().SaveMeshTLSPolicy(tenantID, appID, &req)
+		if err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, policy)
+	case "DELETE":
+		if err := handler.GetTenantManager().DeleteMeshTLSPolicy(tenantID, appID); err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, nil)
+	}
+}
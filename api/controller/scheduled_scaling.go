@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	api_model "github.com/gridworkz/kato/api/model"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+//ScheduledScalingPolicies lists or adds scheduled scaling policies
+func (t *TenantStruct) ScheduledScalingPolicies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		t.listScheduledScalingPolicies(w, r)
+	case "POST":
+		t.addScheduledScalingPolicy(w, r)
+	}
+}
+
+func (t *TenantStruct) listScheduledScalingPolicies(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	policies, err := handler.GetServiceManager().ListScheduledScalingPolicies(serviceID)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policies)
+}
+
+func (t *TenantStruct) addScheduledScalingPolicy(w http.ResponseWriter, r *http.Request) {
+	var req api_model.ScheduledScalingPolicyReq
+	ok := httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil)
+	if !ok {
+		return
+	}
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	policy, err := handler.GetServiceManager().AddScheduledScalingPolicy(serviceID, &req)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policy)
+}
+
+//UpdateScheduledScalingPolicy update scheduled scaling policy
+func (t *TenantStruct) UpdateScheduledScalingPolicy(w http.ResponseWriter, r *http.Request) {
+	var req api_model.ScheduledScalingPolicyReq
+	ok := httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil)
+	if !ok {
+		return
+	}
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	policy, err := handler.GetServiceManager().UpdateScheduledScalingPolicy(serviceID, chi.URLParam(r, "policy_id"), &req)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policy)
+}
+
+//DeleteScheduledScalingPolicy delete scheduled scaling policy
+func (t *TenantStruct) DeleteScheduledScalingPolicy(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	policy, err := handler.GetServiceManager().DeleteScheduledScalingPolicy(serviceID, chi.URLParam(r, "policy_id"))
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, policy)
+}
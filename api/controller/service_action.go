@@ -30,6 +30,7 @@ import (
 	"github.com/gridworkz/kato/api/handler"
 	"github.com/gridworkz/kato/api/middleware"
 	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
 	"github.com/gridworkz/kato/db"
 	dbmodel "github.com/gridworkz/kato/db/model"
 	"github.com/gridworkz/kato/event"
@@ -80,6 +81,10 @@ func (t *TenantStruct) StartService(w http.ResponseWriter, r *http.Request) {
 		TaskType:  "start",
 	}
 	if err := handler.GetServiceManager().StartStopService(startStopStruct); err != nil {
+		if err == bcode.ErrImagePolicyViolation {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
 		httputil.ReturnError(r, w, 500, "get service info error.")
 		return
 	}
@@ -173,6 +178,10 @@ func (t *TenantStruct) RestartService(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := handler.GetServiceManager().StartStopService(startStopStruct); err != nil {
+		if err == bcode.ErrImagePolicyViolation {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
 		httputil.ReturnError(r, w, 500, "get service info error.")
 		return
 	}
@@ -0,0 +1,232 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	httputil "github.com/gridworkz/kato/util/http"
+	"github.com/gridworkz/kato/worker/server/pb"
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatInterval keeps idle watch connections from being closed by proxies.
+var heartbeatInterval = 15 * time.Second
+
+// PodWatchEvent is the server-sent event of a pod change.
+type PodWatchEvent struct {
+	Type       string            `json:"type"`
+	ServiceID  string            `json:"service_id,omitempty"`
+	NewVersion bool              `json:"new_version"`
+	Pod        *pb.ServiceAppPod `json:"pod,omitempty"`
+}
+
+// StatusWatchEvent is the server-sent event of a component status change.
+type StatusWatchEvent struct {
+	Type      string `json:"type"`
+	ServiceID string `json:"service_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	AppStatus string `json:"app_status,omitempty"`
+}
+
+// WatchPods follows the pods of the component as server-sent events
+// swagger:operation GET /v2/tenants/{tenant_name}/services/{service_alias}/pods/watch v2 watchPods
+//
+// Stream pod changes of the component. Every event carries its resume token as
+// id, reconnect with the Last-Event-ID header or the resume_token query to
+// continue after it.
+//
+// ---
+// produces:
+// - text/event-stream
+//
+// responses:
+//   default:
+//     description: stream of pod events
+func (p *PodController) WatchPods(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	stream, err := handler.GetPodHandler().WatchPods(r.Context(), []string{serviceID}, resumeToken(r))
+	if err != nil {
+		httputil.ReturnError(r, w, 500, fmt.Sprintf("watch pods: %v", err))
+		return
+	}
+	serveEvents(w, r, func() (string, string, interface{}, error) {
+		evt, err := stream.Recv()
+		if err != nil {
+			return "", "", nil, err
+		}
+		return evt.ResumeToken, evt.Type.String(), &PodWatchEvent{
+			Type:       evt.Type.String(),
+			ServiceID:  evt.ServiceId,
+			NewVersion: evt.NewVersion,
+			Pod:        evt.Pod,
+		}, nil
+	})
+}
+
+// WatchServiceStatus follows the status of the component as server-sent events
+// swagger:operation GET /v2/tenants/{tenant_name}/services/{service_alias}/status/watch v2 watchServiceStatus
+//
+// Stream status changes of the component, the current status is sent first.
+//
+// ---
+// produces:
+// - text/event-stream
+//
+// responses:
+//   default:
+//     description: stream of status events
+func (p *PodController) WatchServiceStatus(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	p.watchStatus(w, r, "", []string{serviceID})
+}
+
+// WatchAppStatus follows the status of every component of the application as server-sent events
+// swagger:operation GET /v2/tenants/{tenant_name}/apps/{app_id}/status/watch v2 watchAppStatus
+//
+// Stream status changes of the application components, the current statuses
+// are sent first.
+//
+// ---
+// produces:
+// - text/event-stream
+//
+// responses:
+//   default:
+//     description: stream of status events
+func (p *PodController) WatchAppStatus(w http.ResponseWriter, r *http.Request) {
+	appID := r.Context().Value(middleware.ContextKey("app_id")).(string)
+	p.watchStatus(w, r, appID, nil)
+}
+
+func (p *PodController) watchStatus(w http.ResponseWriter, r *http.Request, appID string, serviceIDs []string) {
+	stream, err := handler.GetPodHandler().WatchStatus(r.Context(), appID, serviceIDs, resumeToken(r))
+	if err != nil {
+		httputil.ReturnError(r, w, 500, fmt.Sprintf("watch status: %v", err))
+		return
+	}
+	serveEvents(w, r, func() (string, string, interface{}, error) {
+		evt, err := stream.Recv()
+		if err != nil {
+			return "", "", nil, err
+		}
+		data := &StatusWatchEvent{
+			Type:      evt.Type.String(),
+			ServiceID: evt.ServiceId,
+			Status:    evt.Status,
+		}
+		if appID != "" && evt.Type != pb.WatchEventType_RESET {
+			data.AppStatus = evt.AppStatus.String()
+		}
+		return evt.ResumeToken, evt.Type.String(), data, nil
+	})
+}
+
+func resumeToken(r *http.Request) string {
+	if token := r.Header.Get("Last-Event-ID"); token != "" {
+		return token
+	}
+	return r.FormValue("resume_token")
+}
+
+type watchEvent struct {
+	id, name string
+	data     interface{}
+}
+
+// serveEvents writes the events returned by recv as server-sent events until
+// recv fails or the client goes away. A failed watch ends with a failed event,
+// the client is expected to reconnect with the id of the last event.
+func serveEvents(w http.ResponseWriter, r *http.Request, recv func() (id, name string, data interface{}, err error)) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httputil.ReturnError(r, w, 500, "streaming is not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// disable response buffering of nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := make(chan *watchEvent)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			id, name, data, err := recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- &watchEvent{id: id, name: strings.ToLower(name), data: data}:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case err := <-errs:
+			if r.Context().Err() == nil {
+				logrus.Warningf("watch %s: %v", r.URL.Path, err)
+				// not named "error", EventSource reserves it for connection errors
+				writeEvent(w, &watchEvent{name: "failed", data: map[string]string{"msg": err.Error()}})
+				flusher.Flush()
+			}
+			return
+		case evt := <-events:
+			if err := writeEvent(w, evt); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, evt *watchEvent) error {
+	data, err := json.Marshal(evt.data)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if evt.id != "" {
+		fmt.Fprintf(&b, "id: %s\n", evt.id)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", evt.name, data)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,63 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package controller
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServeEvents(t *testing.T) {
+	events := []*watchEvent{
+		{id: "a.1", name: "RESET", data: &PodWatchEvent{Type: "RESET"}},
+		{id: "a.2", name: "ADDED", data: &PodWatchEvent{Type: "ADDED", ServiceID: "sid"}},
+	}
+	recv := func() (string, string, interface{}, error) {
+		if len(events) == 0 {
+			return "", "", nil, errors.New("watch fell behind")
+		}
+		evt := events[0]
+		events = events[1:]
+		return evt.id, evt.name, evt.data, nil
+	}
+	req := httptest.NewRequest("GET", "/v2/tenants/t/services/s/pods/watch", nil)
+	w := httptest.NewRecorder()
+	serveEvents(w, req, recv)
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("want content type text/event-stream, got %s", ct)
+	}
+	want := "id: a.1\nevent: reset\ndata: {\"type\":\"RESET\",\"new_version\":false}\n\n" +
+		"id: a.2\nevent: added\ndata: {\"type\":\"ADDED\",\"service_id\":\"sid\",\"new_version\":false}\n\n" +
+		"event: failed\ndata: {\"msg\":\"watch fell behind\"}\n\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("unexpected stream:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestResumeToken(t *testing.T) {
+	req := httptest.NewRequest("GET", "/pods/watch?resume_token=b.2", nil)
+	if token := resumeToken(req); token != "b.2" {
+		t.Errorf("want the query token, got %s", token)
+	}
+	req.Header.Set("Last-Event-ID", "b.3")
+	if token := resumeToken(req); token != "b.3" {
+		t.Errorf("want Last-Event-ID to win, got %s", token)
+	}
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/middleware"
+	api_model "github.com/gridworkz/kato/api/model"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+//Webhook gets, creates, updates or deletes the git push webhook of the component.
+func (t *TenantStruct) Webhook(w http.ResponseWriter, r *http.Request) {
+	serviceID := r.Context().Value(middleware.ContextKey("service_id")).(string)
+	switch r.Method {
+	case "GET":
+		webhook, err := handler.GetServiceManager().GetWebhook(serviceID)
+		if err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, webhook)
+	case "PUT":
+		var req api_model.WebhookReq
+		if !httputil.ValidatorRequestStructAndErrorResponse(r, w, &req, nil) {
+			return
+		}
+		webhook, err := handler.GetServiceManager().SaveWebhook(serviceID, &req)
+		if err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, webhook)
+	case "DELETE":
+		if err := handler.GetServiceManager().DeleteWebhook(serviceID); err != nil {
+			httputil.ReturnBcodeError(r, w, err)
+			return
+		}
+		httputil.ReturnSuccess(r, w, nil)
+	}
+}
+
+//TriggerWebhook receives the push events of github, gitlab, gitea or the generic json payload,
+//and builds the component if the event matches the webhook.
+func (t *TenantStruct) TriggerWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 5<<20))
+	if err != nil {
+		httputil.ReturnError(r, w, 400, "read request body: "+err.Error())
+		return
+	}
+	result, err := handler.GetServiceManager().TriggerWebhook(chi.URLParam(r, "service_id"), r.Header, body)
+	if err != nil {
+		httputil.ReturnBcodeError(r, w, err)
+		return
+	}
+	httputil.ReturnSuccess(r, w, result)
+}
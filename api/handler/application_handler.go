@@ -162,6 +162,9 @@ func (a *ApplicationAction) DeleteApp(appID string) error {
 	if total != 0 {
 		return bcode.ErrDeleteDueToBindService
 	}
+	if err := db.GetManager().TenantMeshTLSPolicyDao().DeleteByAppID(appID); err != nil {
+		return err
+	}
 	return db.GetManager().ApplicationDao().DeleteApp(appID)
 }
 
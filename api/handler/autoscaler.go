@@ -0,0 +1,111 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package handler
+
+import (
+	"fmt"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the metric target types that each metric type supports.
+var supportedMetricTargetTypes = map[string][]string{
+	dbmodel.ResourceMetricsType: {dbmodel.UtilizationMetricTargetType, dbmodel.AverageValueMetricTargetType},
+	dbmodel.PodsMetricsType:     {dbmodel.AverageValueMetricTargetType},
+	dbmodel.ObjectMetricsType:   {dbmodel.ValueMetricTargetType, dbmodel.AverageValueMetricTargetType},
+	dbmodel.ExternalMetricsType: {dbmodel.ValueMetricTargetType, dbmodel.AverageValueMetricTargetType},
+}
+
+func validateAutoscalerRule(req *api_model.AutoscalerRuleReq) error {
+	if req.MinReplicas < 1 {
+		return bcode.NewBadRequest("min_replicas should be greater than 0")
+	}
+	if req.MaxReplicas < req.MinReplicas {
+		return bcode.NewBadRequest("max_replicas should not be less than min_replicas")
+	}
+	for idx := range req.Metrics {
+		if err := validateAutoscalerRuleMetric(&req.Metrics[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateAutoscalerRuleMetric(metric *api_model.AutoscalerRuleMetric) error {
+	targetTypes, ok := supportedMetricTargetTypes[metric.MetricsType]
+	if !ok {
+		return bcode.NewBadRequest(fmt.Sprintf("unsupported metric type: %s", metric.MetricsType))
+	}
+	if !util.StringArrayContains(targetTypes, metric.MetricTargetType) {
+		return bcode.NewBadRequest(fmt.Sprintf("metric type %s does not support target type '%s'", metric.MetricsType, metric.MetricTargetType))
+	}
+	if metric.MetricsName == "" {
+		return bcode.NewBadRequest("metric name can not be empty")
+	}
+	if metric.MetricTargetValue < 0 {
+		return bcode.NewBadRequest(fmt.Sprintf("metric %s: target value can not be negative", metric.MetricsName))
+	}
+
+	switch metric.MetricsType {
+	case dbmodel.ResourceMetricsType:
+		if metric.MetricsName != "cpu" && metric.MetricsName != "memory" {
+			return bcode.NewBadRequest(fmt.Sprintf("unsupported resource metric: %s", metric.MetricsName))
+		}
+		if metric.MetricSelector != "" {
+			return bcode.NewBadRequest("resource metrics do not support metric selector")
+		}
+	case dbmodel.ObjectMetricsType:
+		obj := metric.DescribedObject
+		if obj == nil || obj.Kind == "" || obj.Name == "" {
+			return bcode.NewBadRequest(fmt.Sprintf("object metric %s: described object kind and name are required", metric.MetricsName))
+		}
+	}
+	if metric.MetricsType != dbmodel.ObjectMetricsType && metric.DescribedObject != nil {
+		return bcode.NewBadRequest(fmt.Sprintf("metric type %s does not support described object", metric.MetricsType))
+	}
+
+	if metric.MetricSelector != "" {
+		if _, err := metav1.ParseToLabelSelector(metric.MetricSelector); err != nil {
+			return bcode.NewBadRequest(fmt.Sprintf("metric %s: invalid metric selector: %v", metric.MetricsName, err))
+		}
+	}
+
+	return nil
+}
+
+func autoscalerRuleMetricDBModel(ruleID string, metric *api_model.AutoscalerRuleMetric) *dbmodel.TenantServiceAutoscalerRuleMetrics {
+	m := &dbmodel.TenantServiceAutoscalerRuleMetrics{
+		RuleID:            ruleID,
+		MetricsType:       metric.MetricsType,
+		MetricsName:       metric.MetricsName,
+		MetricTargetType:  metric.MetricTargetType,
+		MetricTargetValue: metric.MetricTargetValue,
+		MetricSelector:    metric.MetricSelector,
+	}
+	if metric.DescribedObject != nil {
+		m.DescribedKind = metric.DescribedObject.Kind
+		m.DescribedName = metric.DescribedObject.Name
+		m.DescribedAPIVersion = metric.DescribedObject.APIVersion
+	}
+	return m
+}
@@ -0,0 +1,110 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package handler
+
+import (
+	"testing"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	dbmodel "github.com/gridworkz/kato/db/model"
+)
+
+func TestValidateAutoscalerRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics []api_model.AutoscalerRuleMetric
+		wantErr bool
+	}{
+		{
+			name: "cpu utilization",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.ResourceMetricsType, MetricsName: "cpu", MetricTargetType: dbmodel.UtilizationMetricTargetType, MetricTargetValue: 50},
+			},
+		},
+		{
+			name: "unknown resource",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.ResourceMetricsType, MetricsName: "gpu", MetricTargetType: dbmodel.UtilizationMetricTargetType, MetricTargetValue: 50},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pods metrics with selector",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.PodsMetricsType, MetricsName: "http_requests", MetricTargetType: dbmodel.AverageValueMetricTargetType, MetricTargetValue: 100, MetricSelector: "method=GET"},
+			},
+		},
+		{
+			name: "pods metrics with value target",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.PodsMetricsType, MetricsName: "http_requests", MetricTargetType: dbmodel.ValueMetricTargetType, MetricTargetValue: 100},
+			},
+			wantErr: true,
+		},
+		{
+			name: "object metrics",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{
+					MetricsType:       dbmodel.ObjectMetricsType,
+					MetricsName:       "requests_per_second",
+					MetricTargetType:  dbmodel.ValueMetricTargetType,
+					MetricTargetValue: 1000,
+					DescribedObject:   &api_model.AutoscalerDescribedObject{Kind: "Ingress", Name: "main-route", APIVersion: "networking.k8s.io/v1"},
+				},
+			},
+		},
+		{
+			name: "object metrics without described object",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.ObjectMetricsType, MetricsName: "requests_per_second", MetricTargetType: dbmodel.ValueMetricTargetType, MetricTargetValue: 1000},
+			},
+			wantErr: true,
+		},
+		{
+			name: "external metrics with invalid selector",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: dbmodel.ExternalMetricsType, MetricsName: "queue_depth", MetricTargetType: dbmodel.ValueMetricTargetType, MetricTargetValue: 30, MetricSelector: "queue in orders"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported metric type",
+			metrics: []api_model.AutoscalerRuleMetric{
+				{MetricsType: "foobar", MetricsName: "queue_depth", MetricTargetType: dbmodel.ValueMetricTargetType, MetricTargetValue: 30},
+			},
+			wantErr: true,
+		},
+	}
+
+	for idx := range tests {
+		tc := tests[idx]
+		t.Run(tc.name, func(t *testing.T) {
+			req := &api_model.AutoscalerRuleReq{
+				RuleID:      "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+				MinReplicas: 1,
+				MaxReplicas: 10,
+				Metrics:     tc.metrics,
+			}
+			err := validateAutoscalerRule(req)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("want error: %v, but got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
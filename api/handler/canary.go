@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
+	"github.com/gridworkz/kato/db"
+	dberr "github.com/gridworkz/kato/db/errors"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	gclient "github.com/gridworkz/kato/mq/client"
+	"github.com/gridworkz/kato/worker/discover/model"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpguts"
+)
+
+//GetCanary returns the canary release of the component.
+func (s *ServiceAction) GetCanary(serviceID string) (*dbmodel.TenantServiceCanary, error) {
+	return getCanary(serviceID)
+}
+
+//StartCanary runs the canary version side by side with the current deploy version.
+func (s *ServiceAction) StartCanary(serviceID, eventID string, req *api_model.CanaryReq) (*dbmodel.TenantServiceCanary, error) {
+	if err := validateCanary(req); err != nil {
+		return nil, err
+	}
+	service, err := db.GetManager().TenantServiceDao().GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if service.Kind == dbmodel.ServiceKindThirdParty.String() || service.IsState() {
+		return nil, bcode.ErrCanaryNotSupported
+	}
+	if req.CanaryVersion == "" || req.CanaryVersion == service.DeployVersion {
+		return nil, bcode.NewBadRequest("canary_version should be different from the current deploy version")
+	}
+	version, err := db.GetManager().VersionInfoDao().GetVersionByDeployVersion(req.CanaryVersion, serviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.NewBadRequest(fmt.Sprintf("canary version %s not found", req.CanaryVersion))
+		}
+		return nil, err
+	}
+	if version.FinalStatus != "success" {
+		return nil, bcode.NewBadRequest(fmt.Sprintf("canary version %s is not built successfully", req.CanaryVersion))
+	}
+	if err := checkImagePolicy(service.TenantID, serviceID, req.CanaryVersion, eventID); err != nil {
+		return nil, err
+	}
+	canary := &dbmodel.TenantServiceCanary{
+		ServiceID:     serviceID,
+		StableVersion: service.DeployVersion,
+		CanaryVersion: req.CanaryVersion,
+		Weight:        req.Weight,
+		Header:        req.Header,
+		Cookie:        req.Cookie,
+		Replicas:      canaryReplicas(req.Replicas),
+	}
+	if err := db.GetManager().TenantServiceCanaryDao().AddModel(canary); err != nil {
+		if err == dberr.ErrRecordAlreadyExist {
+			return nil, bcode.ErrCanaryExist
+		}
+		return nil, err
+	}
+	if err := s.sendCanaryTask(service, model.CanaryActionApply, eventID); err != nil {
+		_ = db.GetManager().TenantServiceCanaryDao().DeleteByServiceID(serviceID)
+		return nil, err
+	}
+	return canary, nil
+}
+
+//UpdateCanary adjusts the traffic routed to the canary version and its replicas.
+func (s *ServiceAction) UpdateCanary(serviceID, eventID string, req *api_model.CanaryReq) (*dbmodel.TenantServiceCanary, error) {
+	if err := validateCanary(req); err != nil {
+		return nil, err
+	}
+	canary, err := getCanary(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if req.CanaryVersion != "" && req.CanaryVersion != canary.CanaryVersion {
+		return nil, bcode.NewBadRequest("the canary version can not be changed, abort the canary release first")
+	}
+	service, err := db.GetManager().TenantServiceDao().GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	canary.Weight = req.Weight
+	canary.Header = req.Header
+	canary.Cookie = req.Cookie
+	canary.Replicas = canaryReplicas(req.Replicas)
+	if err := db.GetManager().TenantServiceCanaryDao().UpdateModel(canary); err != nil {
+		return nil, err
+	}
+	return canary, s.sendCanaryTask(service, model.CanaryActionApply, eventID)
+}
+
+//PromoteCanary upgrades the component to the canary version, then removes the canary version.
+func (s *ServiceAction) PromoteCanary(serviceID, eventID string) (*dbmodel.TenantServiceCanary, error) {
+	canary, err := getCanary(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	service, err := db.GetManager().TenantServiceDao().GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	stableVersion := service.DeployVersion
+	service.DeployVersion = canary.CanaryVersion
+	if err := s.finishCanary(service, canary, stableVersion, model.CanaryActionPromote, eventID); err != nil {
+		return nil, err
+	}
+	return canary, nil
+}
+
+//AbortCanary removes the canary version, all the requests are routed to the stable version again.
+func (s *ServiceAction) AbortCanary(serviceID, eventID string) (*dbmodel.TenantServiceCanary, error) {
+	canary, err := getCanary(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	service, err := db.GetManager().TenantServiceDao().GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return canary, s.finishCanary(service, canary, service.DeployVersion, model.CanaryActionAbort, eventID)
+}
+
+//finishCanary deletes the canary release and saves the deploy version of the component in a transaction.
+//The task is sent to the worker after the commit, so that the worker reads the committed state, and the
+//canary release and the stable version are restored if the task can not be sent.
+func (s *ServiceAction) finishCanary(service *dbmodel.TenantServices, canary *dbmodel.TenantServiceCanary, stableVersion, action, eventID string) error {
+	tx := db.GetManager().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("Unexpected panic occurred, rollback transaction: %v", r)
+			tx.Rollback()
+		}
+	}()
+	if err := db.GetManager().TenantServiceDaoTransactions(tx).UpdateModel(service); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := db.GetManager().TenantServiceCanaryDaoTransactions(tx).DeleteByServiceID(service.ServiceID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.sendCanaryTask(service, action, eventID); err != nil {
+		service.DeployVersion = stableVersion
+		if err := db.GetManager().TenantServiceDao().UpdateModel(service); err != nil {
+			logrus.Errorf("restore the deploy version of service %s failure %s", service.ServiceID, err.Error())
+		}
+		if err := db.GetManager().TenantServiceCanaryDao().AddModel(canary); err != nil {
+			logrus.Errorf("restore the canary release of service %s failure %s", service.ServiceID, err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *ServiceAction) sendCanaryTask(service *dbmodel.TenantServices, action, eventID string) error {
+	err := s.MQClient.SendBuilderTopic(gclient.TaskStruct{
+		TaskBody: model.CanaryTaskBody{
+			TenantID:  service.TenantID,
+			ServiceID: service.ServiceID,
+			Action:    action,
+			EventID:   eventID,
+		},
+		TaskType: "canary",
+		Topic:    gclient.WorkerTopic,
+	})
+	if err != nil {
+		logrus.Errorf("equque canary message error, %v", err)
+		return fmt.Errorf("send canary task: %v", err)
+	}
+	return nil
+}
+
+func getCanary(serviceID string) (*dbmodel.TenantServiceCanary, error) {
+	canary, err := db.GetManager().TenantServiceCanaryDao().GetByServiceID(serviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.ErrCanaryNotFound
+		}
+		return nil, err
+	}
+	return canary, nil
+}
+
+func canaryReplicas(replicas int) int {
+	if replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+func validateCanary(req *api_model.CanaryReq) error {
+	if req.Weight < 0 || req.Weight > 100 {
+		return bcode.NewBadRequest("weight should be between 0 and 100")
+	}
+	if req.Replicas < 0 {
+		return bcode.NewBadRequest("replicas can not be negative")
+	}
+	if err := validateCanaryMatch("header", req.Header); err != nil {
+		return err
+	}
+	return validateCanaryMatch("cookie", req.Cookie)
+}
+
+//validateCanaryMatch validates the conditions of the header or cookie, such as 'X-Canary=true;X-Region=us'.
+func validateCanaryMatch(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, item := range strings.Split(strings.Replace(value, " ", "", -1), ";") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[1] == "" || !httpguts.ValidHeaderFieldName(kv[0]) {
+			return bcode.NewBadRequest(fmt.Sprintf("invalid %s condition %q, it should be like 'key=value;key2=value2'", name, item))
+		}
+	}
+	return nil
+}
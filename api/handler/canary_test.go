@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"testing"
+
+	api_model "github.com/gridworkz/kato/api/model"
+)
+
+func TestValidateCanary(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     api_model.CanaryReq
+		wantErr bool
+	}{
+		{
+			name: "weight",
+			req:  api_model.CanaryReq{Weight: 10},
+		},
+		{
+			name: "header and cookie",
+			req:  api_model.CanaryReq{Header: "X-Canary=true; X-Region=us", Cookie: "canary=always"},
+		},
+		{
+			name:    "weight out of range",
+			req:     api_model.CanaryReq{Weight: 101},
+			wantErr: true,
+		},
+		{
+			name:    "negative replicas",
+			req:     api_model.CanaryReq{Replicas: -1},
+			wantErr: true,
+		},
+		{
+			name:    "header without value",
+			req:     api_model.CanaryReq{Header: "X-Canary"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid header name",
+			req:     api_model.CanaryReq{Header: "X Canary(1)=true"},
+			wantErr: true,
+		},
+	}
+	for idx := range tests {
+		tc := tests[idx]
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCanary(&tc.req)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("want error: %v, but got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -89,11 +89,19 @@ func (g *GatewayAction) CreateHTTPRule(tx *gorm.DB, req *apimodel.AddHTTPRuleStr
 			}
 			return req.Path
 		}(),
-		Header:        req.Header,
-		Cookie:        req.Cookie,
-		Weight:        req.Weight,
-		IP:            req.IP,
-		CertificateID: req.CertificateID,
+		Header:          req.Header,
+		Cookie:          req.Cookie,
+		Weight:          req.Weight,
+		IP:              req.IP,
+		CertificateID:   req.CertificateID,
+		RateLimitRPS:    req.RateLimitRPS,
+		RateLimitBurst:  req.RateLimitBurst,
+		RateLimitKey:    req.RateLimitKey,
+		ConnectionLimit: req.ConnectionLimit,
+		IPAllowList:     req.IPAllowList,
+		IPDenyList:      req.IPDenyList,
+		BasicAuthSecret: req.BasicAuthSecret,
+		ForwardAuthURL:  req.ForwardAuthURL,
 	}
 	if err := db.GetManager().HTTPRuleDaoTransactions(tx).AddModel(httpRule); err != nil {
 		return fmt.Errorf("create http rule: %v", err)
@@ -197,6 +205,14 @@ func (g *GatewayAction) UpdateHTTPRule(req *apimodel.UpdateHTTPRuleStruct) error
 	rule.Header = req.Header
 	rule.Cookie = req.Cookie
 	rule.Weight = req.Weight
+	rule.RateLimitRPS = req.RateLimitRPS
+	rule.RateLimitBurst = req.RateLimitBurst
+	rule.RateLimitKey = req.RateLimitKey
+	rule.ConnectionLimit = req.ConnectionLimit
+	rule.IPAllowList = req.IPAllowList
+	rule.IPDenyList = req.IPDenyList
+	rule.BasicAuthSecret = req.BasicAuthSecret
+	rule.ForwardAuthURL = req.ForwardAuthURL
 	if req.IP != "" {
 		rule.IP = req.IP
 	}
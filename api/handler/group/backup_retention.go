@@ -0,0 +1,96 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package group
+
+import (
+	"fmt"
+	"sort"
+
+	dbmodel "github.com/gridworkz/kato/db/model"
+)
+
+//RetentionPolicy decides which backups created by a schedule are kept.
+//A backup is kept if it is one of the last KeepLast backups, or it is the latest backup
+//of one of the last KeepDaily days, KeepWeekly weeks or KeepMonthly months.
+type RetentionPolicy struct {
+	KeepLast    int `json:"keep_last"`
+	KeepDaily   int `json:"keep_daily"`
+	KeepWeekly  int `json:"keep_weekly"`
+	KeepMonthly int `json:"keep_monthly"`
+}
+
+//IsEmpty returns true if the policy keeps all the backups
+func (p RetentionPolicy) IsEmpty() bool {
+	return p.KeepLast <= 0 && p.KeepDaily <= 0 && p.KeepWeekly <= 0 && p.KeepMonthly <= 0
+}
+
+//ExpiredBackups returns the backups out of the retention policy.
+//Only the successful backups are taken into account, the others are never expired.
+func ExpiredBackups(backups []*dbmodel.AppBackup, policy RetentionPolicy) []*dbmodel.AppBackup {
+	if policy.IsEmpty() {
+		return nil
+	}
+	var successful []*dbmodel.AppBackup
+	for _, backup := range backups {
+		if backup.Status == "success" {
+			successful = append(successful, backup)
+		}
+	}
+	sort.SliceStable(successful, func(i, j int) bool {
+		return successful[i].CreatedAt.After(successful[j].CreatedAt)
+	})
+
+	keep := make(map[string]struct{})
+	for i := 0; i < policy.KeepLast && i < len(successful); i++ {
+		keep[successful[i].BackupID] = struct{}{}
+	}
+	keepPeriods := func(limit int, period func(b *dbmodel.AppBackup) string) {
+		seen := make(map[string]struct{})
+		for _, backup := range successful {
+			if len(seen) >= limit {
+				return
+			}
+			key := period(backup)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			// the backups are sorted by time, the first one of a period is the latest one.
+			seen[key] = struct{}{}
+			keep[backup.BackupID] = struct{}{}
+		}
+	}
+	keepPeriods(policy.KeepDaily, func(b *dbmodel.AppBackup) string {
+		return b.CreatedAt.Format("2006-01-02")
+	})
+	keepPeriods(policy.KeepWeekly, func(b *dbmodel.AppBackup) string {
+		year, week := b.CreatedAt.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+	keepPeriods(policy.KeepMonthly, func(b *dbmodel.AppBackup) string {
+		return b.CreatedAt.Format("2006-01")
+	})
+
+	var expired []*dbmodel.AppBackup
+	for _, backup := range successful {
+		if _, ok := keep[backup.BackupID]; !ok {
+			expired = append(expired, backup)
+		}
+	}
+	return expired
+}
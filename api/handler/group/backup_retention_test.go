@@ -0,0 +1,121 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package group
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	dbmodel "github.com/gridworkz/kato/db/model"
+)
+
+func newBackup(id, status string, createdAt time.Time) *dbmodel.AppBackup {
+	backup := &dbmodel.AppBackup{BackupID: id, Status: status}
+	backup.CreatedAt = createdAt
+	return backup
+}
+
+func TestExpiredBackups(t *testing.T) {
+	base := time.Date(2021, 3, 31, 2, 0, 0, 0, time.UTC)
+	// a daily backup at 02:00 of the last 60 days, the newest one is b0.
+	var daily []*dbmodel.AppBackup
+	for i := 0; i < 60; i++ {
+		daily = append(daily, newBackup(fmt.Sprintf("b%d", i), "success", base.AddDate(0, 0, -i)))
+	}
+
+	tests := []struct {
+		name    string
+		backups []*dbmodel.AppBackup
+		policy  RetentionPolicy
+		kept    []string
+	}{
+		{
+			name:    "empty policy keeps all",
+			backups: daily,
+			kept:    idsOf(daily),
+		},
+		{
+			name:    "keep last",
+			backups: daily,
+			policy:  RetentionPolicy{KeepLast: 3},
+			kept:    []string{"b0", "b1", "b2"},
+		},
+		{
+			name: "keep daily keeps the latest one of a day",
+			backups: []*dbmodel.AppBackup{
+				newBackup("morning", "success", base.Add(-time.Hour)),
+				newBackup("night", "success", base.Add(20*time.Hour)),
+				newBackup("yesterday", "success", base.AddDate(0, 0, -1)),
+				newBackup("old", "success", base.AddDate(0, 0, -2)),
+			},
+			policy: RetentionPolicy{KeepDaily: 2},
+			kept:   []string{"night", "yesterday"},
+		},
+		{
+			// 2021-03-31 is Wednesday, the weeks begin at 03-29, 03-22, 03-15...
+			name:    "keep weekly and monthly",
+			backups: daily,
+			policy:  RetentionPolicy{KeepWeekly: 3, KeepMonthly: 3},
+			// b0: week 13 and March, b3: week 12, b10: week 11, b31: February, b59: January.
+			kept: []string{"b0", "b3", "b10", "b31", "b59"},
+		},
+		{
+			name: "unsuccessful backups are never expired",
+			backups: []*dbmodel.AppBackup{
+				newBackup("failed", "failed", base.AddDate(0, 0, -10)),
+				newBackup("starting", "starting", base.AddDate(0, 0, -9)),
+				newBackup("success", "success", base.AddDate(0, 0, -8)),
+				newBackup("latest", "success", base),
+			},
+			policy: RetentionPolicy{KeepLast: 1},
+			kept:   []string{"failed", "starting", "latest"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expired := make(map[string]struct{})
+			for _, backup := range ExpiredBackups(tc.backups, tc.policy) {
+				expired[backup.BackupID] = struct{}{}
+			}
+			var kept []string
+			for _, backup := range tc.backups {
+				if _, ok := expired[backup.BackupID]; !ok {
+					kept = append(kept, backup.BackupID)
+				}
+			}
+			sort.Strings(kept)
+			want := append([]string{}, tc.kept...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(kept, want) {
+				t.Errorf("want kept %v, but got %v", want, kept)
+			}
+		})
+	}
+}
+
+func idsOf(backups []*dbmodel.AppBackup) []string {
+	var ids []string
+	for _, backup := range backups {
+		ids = append(ids, backup.BackupID)
+	}
+	return ids
+}
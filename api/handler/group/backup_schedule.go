@@ -0,0 +1,416 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package group
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gridworkz/kato/api/util"
+	"github.com/gridworkz/kato/builder/cloudos"
+	"github.com/gridworkz/kato/db"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	core_util "github.com/gridworkz/kato/util"
+	"github.com/gridworkz/kato/util/cron"
+)
+
+//BackupSchedule creates the backups of a group app periodically
+type BackupSchedule struct {
+	GroupID string `json:"group_id" validate:"group_id|required"`
+	// five-field cron expression, such as '0 2 * * *'
+	Cron       string   `json:"cron" validate:"cron|required"`
+	Mode       string   `json:"mode" validate:"mode|required|in:full-online,full-offline"`
+	ServiceIDs []string `json:"service_ids" validate:"service_ids|required"`
+	Metadata   string   `json:"metadata" validate:"metadata|required"`
+	Force      bool     `json:"force"`
+	Enable     bool     `json:"enable"`
+	// the volume data is backed up incrementally
+	Incremental bool `json:"incremental"`
+
+	S3Config StorageConfig `json:"s3_config"`
+	// the backup archives are encrypted with the key in the secret of the kato namespace if it is not empty
+	EncryptionKeySecret string `json:"encryption_key_secret"`
+	RetentionPolicy
+}
+
+// nextTime returns the next activation time of the cron expression.
+func nextTime(spec string, now time.Time) (time.Time, error) {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron expression %s is never activated", spec)
+	}
+	return next, nil
+}
+
+func (b *BackupSchedule) validate() error {
+	if b.Mode == "full-online" {
+		if _, err := cloudos.Str2S3Provider(b.S3Config.Provider); err != nil {
+			return err
+		}
+	}
+	if b.KeepLast < 0 || b.KeepDaily < 0 || b.KeepWeekly < 0 || b.KeepMonthly < 0 {
+		return fmt.Errorf("the retention policy can not be negative")
+	}
+	_, err := nextTime(b.Cron, time.Now())
+	return err
+}
+
+func (b *BackupSchedule) applyTo(schedule *dbmodel.AppBackupSchedule) {
+	schedule.GroupID = b.GroupID
+	schedule.Cron = b.Cron
+	schedule.Mode = b.Mode
+	schedule.ServiceIDs = strings.Join(b.ServiceIDs, ",")
+	schedule.Metadata = b.Metadata
+	schedule.Force = b.Force
+	schedule.Enable = b.Enable
+	schedule.Incremental = b.Incremental
+	schedule.Provider = b.S3Config.Provider
+	schedule.Endpoint = b.S3Config.Endpoint
+	schedule.AccessKey = b.S3Config.AccessKey
+	schedule.SecretKey = b.S3Config.SecretKey
+	schedule.BucketName = b.S3Config.BucketName
+	schedule.UseSSL = b.S3Config.UseSSL
+	schedule.EncryptionKeySecret = b.EncryptionKeySecret
+	schedule.KeepLast = b.KeepLast
+	schedule.KeepDaily = b.KeepDaily
+	schedule.KeepWeekly = b.KeepWeekly
+	schedule.KeepMonthly = b.KeepMonthly
+	schedule.NextTime, _ = nextTime(b.Cron, time.Now())
+}
+
+func storageConfigOf(schedule *dbmodel.AppBackupSchedule) StorageConfig {
+	return StorageConfig{
+		Provider:   schedule.Provider,
+		Endpoint:   schedule.Endpoint,
+		AccessKey:  schedule.AccessKey,
+		SecretKey:  schedule.SecretKey,
+		BucketName: schedule.BucketName,
+		UseSSL:     schedule.UseSSL,
+	}
+}
+
+func retentionPolicyOf(schedule *dbmodel.AppBackupSchedule) RetentionPolicy {
+	return RetentionPolicy{
+		KeepLast:    schedule.KeepLast,
+		KeepDaily:   schedule.KeepDaily,
+		KeepWeekly:  schedule.KeepWeekly,
+		KeepMonthly: schedule.KeepMonthly,
+	}
+}
+
+//CreateBackupSchedule creates a backup schedule of the group app
+func (h *BackupHandle) CreateBackupSchedule(tenantID string, req *BackupSchedule) (*dbmodel.AppBackupSchedule, *util.APIHandleError) {
+	if err := req.validate(); err != nil {
+		return nil, util.CreateAPIHandleError(400, err)
+	}
+	schedule := &dbmodel.AppBackupSchedule{
+		ScheduleID: core_util.NewUUID(),
+		TenantID:   tenantID,
+	}
+	req.applyTo(schedule)
+	if err := db.GetManager().AppBackupScheduleDao().AddModel(schedule); err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("create backup schedule", err)
+	}
+	return schedule, nil
+}
+
+//UpdateBackupSchedule updates the backup schedule, the next time is recomputed from the cron expression
+func (h *BackupHandle) UpdateBackupSchedule(scheduleID string, req *BackupSchedule) (*dbmodel.AppBackupSchedule, *util.APIHandleError) {
+	if err := req.validate(); err != nil {
+		return nil, util.CreateAPIHandleError(400, err)
+	}
+	schedule, err := db.GetManager().AppBackupScheduleDao().GetByScheduleID(scheduleID)
+	if err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("get backup schedule", err)
+	}
+	req.applyTo(schedule)
+	if err := db.GetManager().AppBackupScheduleDao().UpdateModel(schedule); err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("update backup schedule", err)
+	}
+	return schedule, nil
+}
+
+//GetBackupSchedule get one backup schedule
+func (h *BackupHandle) GetBackupSchedule(scheduleID string) (*dbmodel.AppBackupSchedule, *util.APIHandleError) {
+	schedule, err := db.GetManager().AppBackupScheduleDao().GetByScheduleID(scheduleID)
+	if err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("get backup schedule", err)
+	}
+	return schedule, nil
+}
+
+//ListBackupSchedules lists the backup schedules of the group app
+func (h *BackupHandle) ListBackupSchedules(groupID string) ([]*dbmodel.AppBackupSchedule, *util.APIHandleError) {
+	schedules, err := db.GetManager().AppBackupScheduleDao().ListByGroupID(groupID)
+	if err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("list backup schedules", err)
+	}
+	return schedules, nil
+}
+
+//DeleteBackupSchedule deletes the backup schedule, the backups created by it are kept
+func (h *BackupHandle) DeleteBackupSchedule(scheduleID string) *util.APIHandleError {
+	if _, err := db.GetManager().AppBackupScheduleDao().GetByScheduleID(scheduleID); err != nil {
+		return util.CreateAPIHandleErrorFromDBError("get backup schedule", err)
+	}
+	if err := db.GetManager().AppBackupScheduleDao().DeleteByScheduleID(scheduleID); err != nil {
+		return util.CreateAPIHandleErrorFromDBError("delete backup schedule", err)
+	}
+	return nil
+}
+
+// defaultScheduleSyncPeriod the period of checking the backup schedules.
+// cron expressions are accurate to the minute.
+const defaultScheduleSyncPeriod = 30 * time.Second
+
+//BackupScheduler executes the backup schedules, and prunes the backups out of their retention policies.
+//It is safe to run a scheduler in every api instance, a run of a schedule is claimed by only one of them.
+type BackupScheduler struct {
+	handle    *BackupHandle
+	dbmanager db.Manager
+}
+
+//NewBackupScheduler creates a new backup scheduler.
+func NewBackupScheduler(handle *BackupHandle) *BackupScheduler {
+	return &BackupScheduler{
+		handle:    handle,
+		dbmanager: db.GetManager(),
+	}
+}
+
+//Run runs the scheduler until the ctx is done.
+func (s *BackupScheduler) Run(ctx context.Context) {
+	logrus.Info("start app backup scheduler")
+	ticker := time.NewTicker(defaultScheduleSyncPeriod)
+	defer ticker.Stop()
+	for {
+		s.sync(time.Now())
+		select {
+		case <-ctx.Done():
+			logrus.Info("stop app backup scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *BackupScheduler) sync(now time.Time) {
+	schedules, err := s.dbmanager.AppBackupScheduleDao().ListEnableOnes()
+	if err != nil {
+		logrus.Errorf("list app backup schedules: %v", err)
+		return
+	}
+	for _, schedule := range schedules {
+		s.checkLastBackup(schedule)
+		if now.Before(schedule.NextTime) {
+			continue
+		}
+		s.run(schedule, now)
+	}
+}
+
+// checkLastBackup checks the result of the last backup, notifies the failures,
+// and prunes the expired backups after a new backup succeeds.
+func (s *BackupScheduler) checkLastBackup(schedule *dbmodel.AppBackupSchedule) {
+	if schedule.LastStatus != "starting" || schedule.LastBackupID == "" {
+		return
+	}
+	status := "failed"
+	backup, err := s.dbmanager.AppBackupDao().GetAppBackup(schedule.LastBackupID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logrus.Warningf("schedule id: %s; get backup %s: %v", schedule.ScheduleID, schedule.LastBackupID, err)
+		return
+	}
+	if backup != nil && err == nil {
+		status = backup.Status
+	}
+	if status != "success" && status != "failed" {
+		return
+	}
+	changed, err := s.dbmanager.AppBackupScheduleDao().UpdateLastStatus(schedule.ScheduleID, schedule.LastBackupID, status)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; update last status: %v", schedule.ScheduleID, err)
+		return
+	}
+	if !changed {
+		// handled by another api instance
+		return
+	}
+	schedule.LastStatus = status
+	if status == "failed" {
+		schedule.FailureCount++
+		s.notify(schedule, schedule.FailureCount, fmt.Sprintf("backup %s failed", schedule.LastBackupID))
+		return
+	}
+	schedule.FailureCount = 0
+	s.prune(schedule)
+}
+
+// failUnfinishedBackup marks the last backup failed if it is still not finished when the next run starts,
+// otherwise it is overwritten by the new backup and its result is never checked.
+func (s *BackupScheduler) failUnfinishedBackup(schedule *dbmodel.AppBackupSchedule) {
+	if schedule.LastStatus != "starting" || schedule.LastBackupID == "" {
+		return
+	}
+	changed, err := s.dbmanager.AppBackupScheduleDao().UpdateLastStatus(schedule.ScheduleID, schedule.LastBackupID, "failed")
+	if err != nil {
+		logrus.Warningf("schedule id: %s; update last status: %v", schedule.ScheduleID, err)
+		return
+	}
+	if !changed {
+		return
+	}
+	schedule.LastStatus = "failed"
+	schedule.FailureCount++
+	backup, err := s.dbmanager.AppBackupDao().GetAppBackup(schedule.LastBackupID)
+	if err == nil && backup.Status == "starting" {
+		backup.Status = "failed"
+		if err := s.dbmanager.AppBackupDao().UpdateModel(backup); err != nil {
+			logrus.Warningf("schedule id: %s; mark backup %s failed: %v", schedule.ScheduleID, backup.BackupID, err)
+		}
+	}
+	s.notify(schedule, schedule.FailureCount, fmt.Sprintf("backup %s is not finished before the next scheduled backup", schedule.LastBackupID))
+}
+
+func (s *BackupScheduler) run(schedule *dbmodel.AppBackupSchedule, now time.Time) {
+	next, err := nextTime(schedule.Cron, now)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; next time: %v", schedule.ScheduleID, err)
+		return
+	}
+	claimed, err := s.dbmanager.AppBackupScheduleDao().UpdateNextTime(schedule.ScheduleID, schedule.NextTime, next)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; update next time: %v", schedule.ScheduleID, err)
+		return
+	}
+	if !claimed {
+		// executed by another api instance
+		return
+	}
+	s.failUnfinishedBackup(schedule)
+
+	backup, err := s.newBackup(schedule, now)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; create backup: %v", schedule.ScheduleID, err)
+		if err := s.dbmanager.AppBackupScheduleDao().UpdateLastBackup(schedule.ScheduleID, "", "failed"); err != nil {
+			logrus.Warningf("schedule id: %s; update last backup: %v", schedule.ScheduleID, err)
+		}
+		s.notify(schedule, schedule.FailureCount+1, err.Error())
+		return
+	}
+	logrus.Infof("schedule id: %s; backup %s of app %s is created", schedule.ScheduleID, backup.BackupID, schedule.GroupID)
+	if err := s.dbmanager.AppBackupScheduleDao().UpdateLastBackup(schedule.ScheduleID, backup.BackupID, "starting"); err != nil {
+		logrus.Warningf("schedule id: %s; update last backup: %v", schedule.ScheduleID, err)
+	}
+}
+
+func (s *BackupScheduler) newBackup(schedule *dbmodel.AppBackupSchedule, now time.Time) (*dbmodel.AppBackup, error) {
+	if schedule.ServiceIDs == "" {
+		return nil, fmt.Errorf("no component to backup")
+	}
+
+	var b Backup
+	b.Body.EventID = core_util.NewUUID()
+	b.Body.GroupID = schedule.GroupID
+	b.Body.Metadata = schedule.Metadata
+	b.Body.ServiceIDs = strings.Split(schedule.ServiceIDs, ",")
+	// the version is unique among all the backups
+	b.Body.Version = fmt.Sprintf("%s-%s", now.Format("20060102150405"), schedule.ScheduleID[:8])
+	b.Body.Mode = schedule.Mode
+	b.Body.Force = schedule.Force
+	b.Body.S3Config = storageConfigOf(schedule)
+	b.Body.EncryptionKeySecret = schedule.EncryptionKeySecret
+	b.Body.Incremental = schedule.Incremental
+	backup, apiErr := s.handle.newBackup(b, schedule.ScheduleID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return backup, nil
+}
+
+// prune deletes the backups out of the retention policy, including their packages.
+func (s *BackupScheduler) prune(schedule *dbmodel.AppBackupSchedule) {
+	policy := retentionPolicyOf(schedule)
+	if policy.IsEmpty() {
+		return
+	}
+	backups, err := s.dbmanager.AppBackupDao().ListByScheduleID(schedule.ScheduleID)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; list backups: %v", schedule.ScheduleID, err)
+		return
+	}
+	cfg := storageConfigOf(schedule)
+	for _, backup := range ExpiredBackups(backups, policy) {
+		if backup.BackupMode == "full-online" {
+			cloudoser, err := cfg.newCloudOSer()
+			if err != nil {
+				logrus.Warningf("schedule id: %s; create object storage client: %v", schedule.ScheduleID, err)
+				return
+			}
+			_, objkey := filepath.Split(backup.SourceDir)
+			if err := cloudoser.DeleteObject(objkey); err != nil && err != cloudos.ErrObjectNotFound {
+				logrus.Warningf("schedule id: %s; delete object %s: %v", schedule.ScheduleID, objkey, err)
+				continue
+			}
+		}
+		// the volume snapshots, and the local package of the full-offline backup are removed along with the record.
+		if err := s.handle.DeleteBackup(backup.BackupID, &cfg); err != nil {
+			logrus.Warningf("schedule id: %s; delete backup %s: %v", schedule.ScheduleID, backup.BackupID, err)
+			continue
+		}
+		logrus.Infof("schedule id: %s; expired backup %s is pruned", schedule.ScheduleID, backup.BackupID)
+	}
+}
+
+// notify raises a notification event of the failed scheduled backup.
+// The events of a schedule share the same hash, the count is the number of consecutive failures.
+func (s *BackupScheduler) notify(schedule *dbmodel.AppBackupSchedule, count int, reason string) {
+	message := fmt.Sprintf("scheduled backup of app %s failed: %s", schedule.GroupID, reason)
+	if len(message) > 200 {
+		message = message[:200]
+	}
+	tenant, err := s.dbmanager.TenantDao().GetTenantByUUID(schedule.TenantID)
+	if err != nil {
+		logrus.Warningf("schedule id: %s; get tenant %s: %v", schedule.ScheduleID, schedule.TenantID, err)
+		return
+	}
+	err = s.dbmanager.NotificationEventDao().AddModel(&dbmodel.NotificationEvent{
+		Kind:       "tenant",
+		KindID:     schedule.TenantID,
+		Hash:       "backup-schedule-" + schedule.ScheduleID,
+		Type:       "UnNormal",
+		Message:    message,
+		Reason:     "BackupFailed",
+		Count:      count,
+		TenantName: tenant.Name,
+	})
+	if err != nil {
+		logrus.Warningf("schedule id: %s; add notification event: %v", schedule.ScheduleID, err)
+	}
+}
@@ -20,6 +20,7 @@ package group
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -32,6 +33,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/gridworkz/kato/api/util"
+	"github.com/gridworkz/kato/builder/cloudos"
+	"github.com/gridworkz/kato/builder/volumebackup"
 	"github.com/gridworkz/kato/db"
 	"github.com/gridworkz/kato/event"
 	"github.com/gridworkz/kato/worker/client"
@@ -57,18 +60,47 @@ type Backup struct {
 		SourceDir  string   `json:"source_dir"`
 		BackupID   string   `json:"backup_id,omitempty"`
 
-		Mode     string `json:"mode" validate:"mode|required|in:full-online,full-offline"`
-		Force    bool   `json:"force"`
-		S3Config struct {
-			Provider   string `json:"provider"`
-			Endpoint   string `json:"endpoint"`
-			AccessKey  string `json:"access_key"`
-			SecretKey  string `json:"secret_key"`
-			BucketName string `json:"bucket_name"`
-		} `json:"s3_config"`
+		Mode     string        `json:"mode" validate:"mode|required|in:full-online,full-offline"`
+		Force    bool          `json:"force"`
+		S3Config StorageConfig `json:"s3_config"`
+		// the backup archive is encrypted with the key in the secret of the kato namespace if it is not empty,
+		// only the name of the secret is passed to the builder
+		EncryptionKeySecret string `json:"encryption_key_secret"`
+		// the volume data is backed up incrementally, only the data changed since
+		// the last backup of the volume is stored
+		Incremental bool `json:"incremental"`
 	}
 }
 
+//StorageConfig the object storage of the backup archives
+type StorageConfig struct {
+	// s3, alioss, minio, local or sftp
+	Provider string `json:"provider"`
+	// the address of the storage, host:port for sftp
+	Endpoint  string `json:"endpoint"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	// the bucket, or the directory for local and sftp
+	BucketName string `json:"bucket_name"`
+	UseSSL     bool   `json:"use_ssl"`
+}
+
+func (c *StorageConfig) newCloudOSer() (cloudos.CloudOSer, error) {
+	s3Provider, err := cloudos.Str2S3Provider(c.Provider)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &cloudos.Config{
+		ProviderType: s3Provider,
+		Endpoint:     c.Endpoint,
+		AccessKey:    c.AccessKey,
+		SecretKey:    c.SecretKey,
+		BucketName:   c.BucketName,
+		UseSSL:       c.UseSSL,
+	}
+	return cloudos.New(cfg)
+}
+
 //BackupHandle group app backup handle
 type BackupHandle struct {
 	mqcli     mqclient.MQClient
@@ -83,14 +115,20 @@ func CreateBackupHandle(MQClient mqclient.MQClient, statusCli *client.AppRuntime
 
 //NewBackup new backup task
 func (h *BackupHandle) NewBackup(b Backup) (*dbmodel.AppBackup, *util.APIHandleError) {
+	return h.newBackup(b, "")
+}
+
+func (h *BackupHandle) newBackup(b Backup, scheduleID string) (*dbmodel.AppBackup, *util.APIHandleError) {
 	logger := event.GetManager().GetLogger(b.Body.EventID)
 	var appBackup = dbmodel.AppBackup{
-		EventID:    b.Body.EventID,
-		BackupID:   core_util.NewUUID(),
-		GroupID:    b.Body.GroupID,
-		Status:     "starting",
-		Version:    b.Body.Version,
-		BackupMode: b.Body.Mode,
+		EventID:     b.Body.EventID,
+		BackupID:    core_util.NewUUID(),
+		GroupID:     b.Body.GroupID,
+		Status:      "starting",
+		Version:     b.Body.Version,
+		BackupMode:  b.Body.Mode,
+		ScheduleID:  scheduleID,
+		Incremental: b.Body.Incremental,
 	}
 	//check last backup task whether complete or version whether exist
 	if db.GetManager().AppBackupDao().CheckHistory(b.Body.GroupID, b.Body.Version) {
@@ -156,12 +194,26 @@ func (h *BackupHandle) GetBackup(backupID string) (*dbmodel.AppBackup, *util.API
 	return backup, nil
 }
 
-//DeleteBackup delete backup
-func (h *BackupHandle) DeleteBackup(backupID string) error {
+// ErrBackupStorageRequired is returned when the volume snapshots of the full-online backup created on demand
+// are deleted without the object storage, which is not recorded by the backup.
+var ErrBackupStorageRequired = errors.New("the object storage of the backup is required to delete its volume snapshots")
+
+//DeleteBackup delete backup, the storage is the object storage of the full-online backup created on demand,
+//the scheduled backups use the storage of the schedule.
+func (h *BackupHandle) DeleteBackup(backupID string, storage *StorageConfig) error {
 	backup, err := db.GetManager().AppBackupDao().GetAppBackup(backupID)
 	if err != nil {
 		return err
 	}
+	var onlineRepo *volumebackup.Repository
+	if backup.BackupMode == "full-online" && backup.Incremental {
+		cloudoser, err := backupStorageOf(backup, storage)
+		if err != nil {
+			return err
+		}
+		// forgetting the snapshots does not require the passphrase
+		onlineRepo = volumebackup.New(cloudoser, "")
+	}
 
 	tx := db.GetManager().Begin()
 	defer db.GetManager().EnsureEndTransactionFunc()(tx)
@@ -170,6 +222,10 @@ func (h *BackupHandle) DeleteBackup(backupID string) error {
 		tx.Rollback()
 		return fmt.Errorf("delete backup error: %v", err)
 	}
+	if err := db.GetManager().AppBackupVolumeSnapshotDaoTransactions(tx).DeleteByBackupID(backupID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete volume snapshots: %v", err)
+	}
 
 	if backup.BackupMode == "full-offline" {
 		logrus.Infof("delete from local: %s", backup.SourceDir)
@@ -177,11 +233,94 @@ func (h *BackupHandle) DeleteBackup(backupID string) error {
 			tx.Rollback()
 			return fmt.Errorf("remove backup directory: %v", err)
 		}
+		if backup.Incremental {
+			// forgetting the snapshots does not require the passphrase
+			repo, err := volumebackup.NewLocal(volumebackup.LocalRepository, "")
+			if err == nil {
+				err = forgetVolumeSnapshots(backup, repo)
+			}
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("forget volume snapshots: %v", err)
+			}
+		}
+	}
+	if onlineRepo != nil {
+		if err := forgetVolumeSnapshots(backup, onlineRepo); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("forget volume snapshots: %v", err)
+		}
 	}
 
 	return tx.Commit().Error
 }
 
+// backupStorageOf returns the object storage of the full-online backup.
+func backupStorageOf(backup *dbmodel.AppBackup, storage *StorageConfig) (cloudos.CloudOSer, error) {
+	if storage == nil || storage.Provider == "" {
+		if backup.ScheduleID == "" {
+			return nil, ErrBackupStorageRequired
+		}
+		schedule, err := db.GetManager().AppBackupScheduleDao().GetByScheduleID(backup.ScheduleID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, ErrBackupStorageRequired
+			}
+			return nil, fmt.Errorf("get backup schedule %s: %v", backup.ScheduleID, err)
+		}
+		cfg := storageConfigOf(schedule)
+		storage = &cfg
+	}
+	cloudoser, err := storage.newCloudOSer()
+	if err != nil {
+		return nil, fmt.Errorf("create object storage client: %v", err)
+	}
+	return cloudoser, nil
+}
+
+// forgetVolumeSnapshots removes the volume snapshots of the incremental backup from the repository,
+// and the chunks no longer referenced by the other backups.
+func forgetVolumeSnapshots(backup *dbmodel.AppBackup, repo *volumebackup.Repository) error {
+	snapshots, err := db.GetManager().AppBackupVolumeSnapshotDao().ListByBackupID(backup.BackupID)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err := repo.Forget(volumebackup.VolumeKey(snapshot.ServiceID, snapshot.VolumeName), backup.BackupID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//VolumeBackupHistory the backup points of a volume
+type VolumeBackupHistory struct {
+	ServiceID  string                             `json:"service_id"`
+	VolumeName string                             `json:"volume_name"`
+	Snapshots  []*dbmodel.AppBackupVolumeSnapshot `json:"snapshots"`
+}
+
+//GetVolumeBackupHistory returns the backup points of the volumes of the group app, the latest first
+func (h *BackupHandle) GetVolumeBackupHistory(groupID string) ([]*VolumeBackupHistory, *util.APIHandleError) {
+	snapshots, err := db.GetManager().AppBackupVolumeSnapshotDao().ListByGroupID(groupID)
+	if err != nil {
+		return nil, util.CreateAPIHandleErrorFromDBError("list volume snapshots", err)
+	}
+	var histories []*VolumeBackupHistory
+	volumes := make(map[string]*VolumeBackupHistory)
+	for _, snapshot := range snapshots {
+		key := snapshot.ServiceID + "/" + snapshot.VolumeName
+		history, ok := volumes[key]
+		if !ok {
+			history = &VolumeBackupHistory{ServiceID: snapshot.ServiceID, VolumeName: snapshot.VolumeName}
+			volumes[key] = history
+			histories = append(histories, history)
+		}
+		history.Snapshots = append(history.Snapshots, snapshot)
+	}
+	return histories, nil
+}
+
 //GetBackupByGroupID get some backup info by group id
 func (h *BackupHandle) GetBackupByGroupID(groupID string) ([]*dbmodel.AppBackup, *util.APIHandleError) {
 	backups, err := db.GetManager().AppBackupDao().GetAppBackups(groupID)
@@ -365,13 +504,12 @@ type BackupRestore struct {
 		//RestoreMode(od)     other datacenter
 		RestoreMode string `json:"restore_mode"`
 
-		S3Config struct {
-			Provider   string `json:"provider"`
-			Endpoint   string `json:"endpoint"`
-			AccessKey  string `json:"access_key"`
-			SecretKey  string `json:"secret_key"`
-			BucketName string `json:"bucket_name"`
-		} `json:"s3_config"`
+		S3Config StorageConfig `json:"s3_config"`
+		// the secret of the kato namespace holding the key decrypting the backup archive if it is encrypted
+		EncryptionKeySecret string `json:"encryption_key_secret"`
+		// the id of the incremental backup of the same app whose volume data is restored,
+		// the volume data of the restored backup is restored if it is empty
+		RestorePoint string `json:"restore_point"`
 	}
 }
 
@@ -408,17 +546,29 @@ func (h *BackupHandle) RestoreBackup(br BackupRestore) (*RestoreResult, *util.AP
 	if backup.Status != "success" || backup.SourceDir == "" || backup.SourceType == "" {
 		return nil, util.CreateAPIHandleErrorf(500, "backup can not be restored")
 	}
+	if br.Body.RestorePoint != "" && br.Body.RestorePoint != backup.BackupID {
+		point, err := h.GetBackup(br.Body.RestorePoint)
+		if err != nil {
+			return nil, err
+		}
+		if !backup.Incremental || !point.Incremental || point.Status != "success" ||
+			point.GroupID != backup.GroupID || point.BackupMode != backup.BackupMode {
+			return nil, util.CreateAPIHandleErrorf(400, "restore point %s is not a successful incremental backup of the same app", point.BackupID)
+		}
+	}
 	var restoreID string
 	if br.Body.EventID != "" {
 		restoreID = br.Body.EventID
 	}
 	restoreID = core_util.NewUUID()
 	var dataMap = map[string]interface{}{
-		"backup_id":    backup.BackupID,
-		"tenant_id":    br.Body.TenantID,
-		"restore_id":   restoreID,
-		"restore_mode": br.Body.RestoreMode,
-		"s3_config":    br.Body.S3Config,
+		"backup_id":             backup.BackupID,
+		"tenant_id":             br.Body.TenantID,
+		"restore_id":            restoreID,
+		"restore_mode":          br.Body.RestoreMode,
+		"s3_config":             br.Body.S3Config,
+		"encryption_key_secret": br.Body.EncryptionKeySecret,
+		"restore_point":         br.Body.RestorePoint,
 	}
 	err := h.mqcli.SendBuilderTopic(mqclient.TaskStruct{
 		TaskBody: dataMap,
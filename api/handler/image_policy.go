@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
+	"github.com/gridworkz/kato/builder/signature"
+	"github.com/gridworkz/kato/db"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/event"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+//GetImagePolicy returns the image policy of the tenant
+func (t *TenantAction) GetImagePolicy(tenantID string) (*dbmodel.TenantImagePolicy, error) {
+	policy, err := db.GetManager().TenantImagePolicyDao().GetByTenantID(tenantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.ErrImagePolicyNotFound
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+//SaveImagePolicy creates or updates the image policy of the tenant
+func (t *TenantAction) SaveImagePolicy(tenantID string, req *api_model.ImagePolicyReq) (*dbmodel.TenantImagePolicy, error) {
+	policy, err := db.GetManager().TenantImagePolicyDao().GetByTenantID(tenantID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &dbmodel.TenantImagePolicy{TenantID: tenantID}
+	}
+	policy.Mode = req.Mode
+	policy.RequireSignature = req.RequireSignature
+	policy.TrustedRegistries = req.TrustedRegistries
+	policy.PublicKeys = req.PublicKeys
+	if _, err := signature.NewPolicy(policy); err != nil {
+		return nil, bcode.NewBadRequest(err.Error())
+	}
+	if policy.ID == 0 {
+		err = db.GetManager().TenantImagePolicyDao().AddModel(policy)
+	} else {
+		err = db.GetManager().TenantImagePolicyDao().UpdateModel(policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+//DeleteImagePolicy deletes the image policy of the tenant, all images are allowed after that
+func (t *TenantAction) DeleteImagePolicy(tenantID string) error {
+	return db.GetManager().TenantImagePolicyDao().DeleteByTenantID(tenantID)
+}
+
+//checkImagePolicy checks the version to be deployed against the image policy of the tenant,
+//see signature.Policy.CheckVersion. The violation is written into the event log, and ErrImagePolicyViolation is returned if
+//the policy is enforced.
+func checkImagePolicy(tenantID, serviceID, deployVersion, eventID string) error {
+	p, err := db.GetManager().TenantImagePolicyDao().GetByTenantID(tenantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	policy, err := signature.NewPolicy(p)
+	if err != nil {
+		return err
+	}
+	version, err := db.GetManager().VersionInfoDao().GetVersionByDeployVersion(deployVersion, serviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	violation := policy.CheckVersion(version)
+	if violation == nil {
+		return nil
+	}
+	logger := event.GetManager().GetLogger(eventID)
+	defer event.GetManager().ReleaseLogger(logger)
+	msg := fmt.Sprintf("image %s of version %s violates the image policy: %s", violation.Image, version.BuildVersion, violation.Reason)
+	if policy.Enforced() {
+		logrus.Warningf("%s, the deployment is refused by the image policy of tenant %s", msg, tenantID)
+		logger.Error(msg+", the deployment is refused", map[string]string{"step": "image-policy", "status": "failure"})
+		return bcode.ErrImagePolicyViolation
+	}
+	logger.Error(msg+", it is allowed in audit mode", map[string]string{"step": "image-policy"})
+	return nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
+	"github.com/gridworkz/kato/db"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	gclient "github.com/gridworkz/kato/mq/client"
+	"github.com/gridworkz/kato/node/nodem/envoy/conver"
+	"github.com/gridworkz/kato/node/nodem/envoy/meshtls"
+	"github.com/gridworkz/kato/worker/discover/model"
+	"github.com/jinzhu/gorm"
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/sirupsen/logrus"
+)
+
+//GetMeshTLSPolicy returns the mutual tls policy of the application, or of the tenant if appID is empty
+func (t *TenantAction) GetMeshTLSPolicy(tenantID, appID string) (*dbmodel.TenantMeshTLSPolicy, error) {
+	policy, err := db.GetManager().TenantMeshTLSPolicyDao().GetByTenantAndApp(tenantID, appID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.ErrMeshTLSPolicyNotFound
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+//SaveMeshTLSPolicy creates or updates the mutual tls policy of the application, or of the tenant if appID is empty.
+//The mesh plugin configs of the affected components are applied again.
+func (t *TenantAction) SaveMeshTLSPolicy(tenantID, appID string, req *api_model.MeshTLSPolicyReq) (*dbmodel.TenantMeshTLSPolicy, error) {
+	sources, err := meshtls.ParsePatterns(req.AllowedSources)
+	if err != nil {
+		return nil, bcode.NewBadRequest(err.Error())
+	}
+	if req.Mode != api_model.MeshTLSModeDisabled {
+		if err := checkMeshTLSXDSVersion(tenantID, appID); err != nil {
+			return nil, err
+		}
+	}
+	policy, err := db.GetManager().TenantMeshTLSPolicyDao().GetByTenantAndApp(tenantID, appID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &dbmodel.TenantMeshTLSPolicy{TenantID: tenantID, AppID: appID}
+	}
+	policy.Mode = req.Mode
+	policy.AllowedSources = strings.Join(sources, ",")
+	if policy.ID == 0 {
+		err = db.GetManager().TenantMeshTLSPolicyDao().AddModel(policy)
+	} else {
+		err = db.GetManager().TenantMeshTLSPolicyDao().UpdateModel(policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.applyMeshTLSPolicy(tenantID, appID)
+	return policy, nil
+}
+
+//DeleteMeshTLSPolicy deletes the mutual tls policy of the application, or of the tenant if appID is empty.
+//The components of the application fall back to the policy of the tenant.
+func (t *TenantAction) DeleteMeshTLSPolicy(tenantID, appID string) error {
+	if err := db.GetManager().TenantMeshTLSPolicyDao().DeleteByTenantAndApp(tenantID, appID); err != nil {
+		return err
+	}
+	t.applyMeshTLSPolicy(tenantID, appID)
+	return nil
+}
+
+//applyMeshTLSPolicy asks the worker to apply the plugin configs of the components under the policy,
+//the sidecars calling them are updated by the node when the configs change
+func (t *TenantAction) applyMeshTLSPolicy(tenantID, appID string) {
+	services, err := listMeshTLSPolicyServices(tenantID, appID)
+	if err != nil {
+		logrus.Errorf("list the components under the mesh tls policy of tenant %s app %s failure %s", tenantID, appID, err.Error())
+		return
+	}
+	for _, service := range services {
+		err := t.MQClient.SendBuilderTopic(gclient.TaskStruct{
+			TaskType: "apply_plugin_config",
+			TaskBody: model.ApplyPluginConfigTaskBody{
+				ServiceID: service.ServiceID,
+				EventID:   "system",
+				Action:    "put",
+			},
+			Topic: gclient.WorkerTopic,
+		})
+		if err != nil {
+			logrus.Errorf("equque mq error, %v", err)
+		}
+	}
+}
+
+//listMeshTLSPolicyServices returns the components under the policy of the application, or of the tenant if appID is empty
+func listMeshTLSPolicyServices(tenantID, appID string) ([]*dbmodel.TenantServices, error) {
+	if appID != "" {
+		return db.GetManager().TenantServiceDao().ListByAppID(appID)
+	}
+	return db.GetManager().TenantServiceDao().GetServicesByTenantID(tenantID)
+}
+
+//checkMeshTLSXDSVersion rejects enabling mutual tls for components whose mesh plugin pins the xds v2 api,
+//only the xds v3 api supports mutual tls, the node would serve those sidecars on v3 only
+func checkMeshTLSXDSVersion(tenantID, appID string) error {
+	services, err := listMeshTLSPolicyServices(tenantID, appID)
+	if err != nil {
+		return err
+	}
+	var pinned []string
+	for _, service := range services {
+		configs, err := db.GetManager().TenantPluginVersionConfigDao().GetPluginConfigs(service.ServiceID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		for _, config := range configs {
+			var rs api_model.ResourceSpec
+			if err := ffjson.Unmarshal([]byte(config.ConfigStr), &rs); err != nil {
+				logrus.Warningf("parse the plugin config of service %s failure %s", service.ServiceID, err.Error())
+				continue
+			}
+			if conver.PinnedXDSVersion(&rs) == conver.XDSVersionV2 {
+				pinned = append(pinned, service.ServiceAlias)
+				break
+			}
+		}
+	}
+	if len(pinned) > 0 {
+		return bcode.NewBadRequest(fmt.Sprintf("mesh mutual tls requires the xds v3 api, the mesh plugins of %s pin %s to %s",
+			strings.Join(pinned, ","), conver.KeyXDSVersion, conver.XDSVersionV2))
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"github.com/gridworkz/kato/worker/server/pb"
 	"strings"
 
@@ -24,3 +25,13 @@ func (p *PodAction) PodDetail(serviceID, podName string) (*pb.PodDetail, error)
 	}
 	return pd, nil
 }
+
+// WatchPods watches the pods of the given components until ctx is done.
+func (p *PodAction) WatchPods(ctx context.Context, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchPodsClient, error) {
+	return p.statusCli.WatchServicePods(ctx, serviceIDs, resumeToken)
+}
+
+// WatchStatus watches the status of the given components, or of every component of appID, until ctx is done.
+func (p *PodAction) WatchStatus(ctx context.Context, appID string, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchAppStatusClient, error) {
+	return p.statusCli.WatchStatus(ctx, appID, serviceIDs, resumeToken)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"context"
+
 	"github.com/gridworkz/kato/worker/client"
 	"github.com/gridworkz/kato/worker/server/pb"
 )
@@ -8,6 +10,8 @@ import (
 // PodHandler defines handler methods about k8s pods.
 type PodHandler interface {
 	PodDetail(serviceID, podName string) (*pb.PodDetail, error)
+	WatchPods(ctx context.Context, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchPodsClient, error)
+	WatchStatus(ctx context.Context, appID string, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchAppStatusClient, error)
 }
 
 // NewPodHandler creates a new PodHandler.
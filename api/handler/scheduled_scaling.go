@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util/bcode"
+	"github.com/gridworkz/kato/db"
+	dberr "github.com/gridworkz/kato/db/errors"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util"
+	"github.com/gridworkz/kato/util/cron"
+	"github.com/jinzhu/gorm"
+)
+
+//ListScheduledScalingPolicies lists the scheduled scaling policies of the service
+func (s *ServiceAction) ListScheduledScalingPolicies(serviceID string) ([]*dbmodel.TenantServiceScheduledScalingPolicy, error) {
+	return db.GetManager().TenantServiceScheduledScalingPolicyDao().ListByServiceID(serviceID)
+}
+
+//AddScheduledScalingPolicy add scheduled scaling policy
+func (s *ServiceAction) AddScheduledScalingPolicy(serviceID string, req *api_model.ScheduledScalingPolicyReq) (*dbmodel.TenantServiceScheduledScalingPolicy, error) {
+	if err := validateScheduledScalingPolicy(req); err != nil {
+		return nil, err
+	}
+	if req.PolicyID == "" {
+		req.PolicyID = util.NewUUID()
+	}
+	policy := &dbmodel.TenantServiceScheduledScalingPolicy{
+		PolicyID:    req.PolicyID,
+		ServiceID:   serviceID,
+		Enable:      req.Enable,
+		StartCron:   req.StartCron,
+		EndCron:     req.EndCron,
+		Timezone:    req.Timezone,
+		MinReplicas: req.MinReplicas,
+		MaxReplicas: req.MaxReplicas,
+	}
+	if err := db.GetManager().TenantServiceScheduledScalingPolicyDao().AddModel(policy); err != nil {
+		if err == dberr.ErrRecordAlreadyExist {
+			return nil, bcode.ErrScheduledScalingPolicyExist
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+//UpdateScheduledScalingPolicy update scheduled scaling policy
+func (s *ServiceAction) UpdateScheduledScalingPolicy(serviceID, policyID string, req *api_model.ScheduledScalingPolicyReq) (*dbmodel.TenantServiceScheduledScalingPolicy, error) {
+	if err := validateScheduledScalingPolicy(req); err != nil {
+		return nil, err
+	}
+	policy, err := getScheduledScalingPolicy(serviceID, policyID)
+	if err != nil {
+		return nil, err
+	}
+	policy.Enable = req.Enable
+	policy.StartCron = req.StartCron
+	policy.EndCron = req.EndCron
+	policy.Timezone = req.Timezone
+	policy.MinReplicas = req.MinReplicas
+	policy.MaxReplicas = req.MaxReplicas
+	return policy, db.GetManager().TenantServiceScheduledScalingPolicyDao().UpdateModel(policy)
+}
+
+//DeleteScheduledScalingPolicy delete scheduled scaling policy.
+//The worker restores the replicas of the service if the policy is active.
+func (s *ServiceAction) DeleteScheduledScalingPolicy(serviceID, policyID string) (*dbmodel.TenantServiceScheduledScalingPolicy, error) {
+	policy, err := getScheduledScalingPolicy(serviceID, policyID)
+	if err != nil {
+		return nil, err
+	}
+	return policy, db.GetManager().TenantServiceScheduledScalingPolicyDao().DeleteByPolicyID(policyID)
+}
+
+func getScheduledScalingPolicy(serviceID, policyID string) (*dbmodel.TenantServiceScheduledScalingPolicy, error) {
+	policy, err := db.GetManager().TenantServiceScheduledScalingPolicyDao().GetByPolicyID(policyID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.ErrScheduledScalingPolicyNotFound
+		}
+		return nil, err
+	}
+	if policy.ServiceID != serviceID {
+		return nil, bcode.ErrScheduledScalingPolicyNotFound
+	}
+	return policy, nil
+}
+
+func validateScheduledScalingPolicy(req *api_model.ScheduledScalingPolicyReq) error {
+	if _, err := cron.Parse(req.StartCron); err != nil {
+		return bcode.NewBadRequest(fmt.Sprintf("invalid start_cron: %v", err))
+	}
+	if _, err := cron.Parse(req.EndCron); err != nil {
+		return bcode.NewBadRequest(fmt.Sprintf("invalid end_cron: %v", err))
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return bcode.NewBadRequest(fmt.Sprintf("invalid timezone: %v", err))
+		}
+	}
+	if req.MinReplicas < 0 {
+		return bcode.NewBadRequest("min_replicas can not be negative")
+	}
+	if req.MaxReplicas < 1 || req.MaxReplicas < req.MinReplicas {
+		return bcode.NewBadRequest("max_replicas should be greater than 0 and not less than min_replicas")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"testing"
+
+	api_model "github.com/gridworkz/kato/api/model"
+)
+
+func TestValidateScheduledScalingPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     api_model.ScheduledScalingPolicyReq
+		wantErr bool
+	}{
+		{
+			name: "working hours",
+			req:  api_model.ScheduledScalingPolicyReq{StartCron: "0 8 * * 1-5", EndCron: "0 20 * * 1-5", Timezone: "UTC", MinReplicas: 10, MaxReplicas: 20},
+		},
+		{
+			name: "scale to zero at night",
+			req:  api_model.ScheduledScalingPolicyReq{StartCron: "0 0 * * *", EndCron: "0 6 * * *", MinReplicas: 0, MaxReplicas: 1},
+		},
+		{
+			name:    "invalid cron",
+			req:     api_model.ScheduledScalingPolicyReq{StartCron: "0 25 * * *", EndCron: "0 6 * * *", MaxReplicas: 1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			req:     api_model.ScheduledScalingPolicyReq{StartCron: "0 0 * * *", EndCron: "0 6 * * *", Timezone: "Mars/Olympus", MaxReplicas: 1},
+			wantErr: true,
+		},
+		{
+			name:    "max replicas less than min replicas",
+			req:     api_model.ScheduledScalingPolicyReq{StartCron: "0 0 * * *", EndCron: "0 6 * * *", MinReplicas: 3, MaxReplicas: 2},
+			wantErr: true,
+		},
+	}
+	for idx := range tests {
+		tc := tests[idx]
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateScheduledScalingPolicy(&tc.req)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("want error: %v, but got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -76,6 +76,23 @@ type dCfg struct {
 	Password string   `json:"password"`
 }
 
+func (d *dCfg) validate() error {
+	switch strings.ToLower(d.Type) {
+	case dbmodel.DiscorveryTypeEtcd.String(), dbmodel.DiscorveryTypeConsul.String():
+		if len(d.Servers) == 0 {
+			return fmt.Errorf("servers can not be empty for %s discovery", d.Type)
+		}
+	case dbmodel.DiscorveryTypeDNS.String():
+		// use the system resolver if servers is empty
+	default:
+		return fmt.Errorf("unsupported discovery type: %s", d.Type)
+	}
+	if d.Key == "" {
+		return fmt.Errorf("key can not be empty for %s discovery", d.Type)
+	}
+	return nil
+}
+
 //CreateManager create Manger
 func CreateManager(conf option.Config, mqClient gclient.MQClient,
 	etcdCli * clientv3.Client, statusCli * client.AppRuntimeSyncClient, prometheusCli prometheus.Interface) * ServiceAction {
@@ -178,6 +195,7 @@ func (s *ServiceAction) buildFromImage(r *api_model.BuildServiceStruct, service
 	body["namespace"] = service.Namespace
 	body["operator"] = r.Body.Operator
 	body["event_id"] = r.Body.EventID
+	body["tenant_id"] = service.TenantID
 	body["tenant_name"] = r.Body.TenantName
 	body["service_alias"] = r.Body.ServiceAlias
 	body["action"] = r.Body.Action
@@ -346,6 +364,12 @@ func (s *ServiceAction) StartStopService(sss *api_model.StartStopStruct) error {
 		logrus.Errorf("get service by id error, %v", err)
 		return err
 	}
+	// the deploy version is started by the tasks but stop
+	if sss.TaskType != "stop" {
+		if err := checkImagePolicy(services.TenantID, services.ServiceID, services.DeployVersion, sss.EventID); err != nil {
+			return err
+		}
+	}
 	TaskBody := model.StopTaskBody{
 		TenantID:      sss.TenantID,
 		ServiceID:     sss.ServiceID,
@@ -726,6 +750,10 @@ func (s *ServiceAction) ServiceCreate(sc *api_model.ServiceStruct) error {
 				tx.Rollback()
 				return err
 			}
+			if err := cfg.validate(); err != nil {
+				tx.Rollback()
+				return err
+			}
 			c := &dbmodel.ThirdPartySvcDiscoveryCfg{
 				ServiceID: sc.ServiceID,
 				Type:      cfg.Type,
@@ -2257,6 +2285,9 @@ func (s *ServiceAction) delServiceMetadata(serviceID string) error {
 		db.GetManager().ServiceEventDaoTransactions(tx).DelEventByServiceID,
 		db.GetManager().TenantServiceMonitorDaoTransactions(tx).DeleteServiceMonitorByServiceID,
 		db.GetManager().AppConfigGroupServiceDaoTransactions(tx).DeleteEffectiveServiceByServiceID,
+		db.GetManager().TenantServiceScheduledScalingPolicyDaoTransactions(tx).DeleteByServiceID,
+		db.GetManager().TenantServiceCanaryDaoTransactions(tx).DeleteByServiceID,
+		db.GetManager().TenantServiceWebhookDaoTransactions(tx).DeleteByServiceID,
 	}
 	if err := GetGatewayHandler().DeleteTCPRuleByServiceIDWithTransaction(serviceID, tx); err != nil {
 		tx.Rollback()
@@ -2362,6 +2393,10 @@ func (s *ServiceAction) ListVersionInfo(serviceID string) (*api_model.BuildListR
 
 // AddAutoscalerRule -
 func (s *ServiceAction) AddAutoscalerRule(req *api_model.AutoscalerRuleReq) error {
+	if err := validateAutoscalerRule(req); err != nil {
+		return err
+	}
+
 	tx := db.GetManager().Begin()
 	defer db.GetManager().EnsureEndTransactionFunc()
 
@@ -2378,14 +2413,8 @@ func (s *ServiceAction) AddAutoscalerRule(req *api_model.AutoscalerRuleReq) erro
 		return err
 	}
 
-	for _, metric := range req.Metrics {
-		m := &dbmodel.TenantServiceAutoscalerRuleMetrics{
-			RuleID:            req.RuleID,
-			MetricsType:       metric.MetricsType,
-			MetricsName:       metric.MetricsName,
-			MetricTargetType:  metric.MetricTargetType,
-			MetricTargetValue: metric.MetricTargetValue,
-		}
+	for idx := range req.Metrics {
+		m := autoscalerRuleMetricDBModel(req.RuleID, &req.Metrics[idx])
 		if err := db.GetManager().TenantServceAutoscalerRuleMetricsDaoTransactions(tx).AddModel(m); err != nil {
 			tx.Rollback()
 			return err
@@ -2411,6 +2440,10 @@ func (s *ServiceAction) AddAutoscalerRule(req *api_model.AutoscalerRuleReq) erro
 
 // UpdAutoscalerRule -
 func (s *ServiceAction) UpdAutoscalerRule(req *api_model.AutoscalerRuleReq) error {
+	if err := validateAutoscalerRule(req); err != nil {
+		return err
+	}
+
 	rule, err := db.GetManager().TenantServceAutoscalerRulesDao().GetByRuleID(req.RuleID)
 	if err != nil {
 		return err
@@ -2435,14 +2468,8 @@ func (s *ServiceAction) UpdAutoscalerRule(req *api_model.AutoscalerRuleReq) erro
 		return err
 	}
 
-	for _, metric := range req.Metrics {
-		m := &dbmodel.TenantServiceAutoscalerRuleMetrics{
-			RuleID:            req.RuleID,
-			MetricsType:       metric.MetricsType,
-			MetricsName:       metric.MetricsName,
-			MetricTargetType:  metric.MetricTargetType,
-			MetricTargetValue: metric.MetricTargetValue,
-		}
+	for idx := range req.Metrics {
+		m := autoscalerRuleMetricDBModel(req.RuleID, &req.Metrics[idx])
 		if err := db.GetManager().TenantServceAutoscalerRuleMetricsDaoTransactions(tx).AddModel(m); err != nil {
 			tx.Rollback()
 			return err
@@ -2750,6 +2777,9 @@ func (s *ServiceAction) SyncComponentPlugins(tx *gorm.DB, app *dbmodel.Applicati
 			}
 
 			if configs := plugin.ConfigEnvs.ComplexEnvs; configs != nil {
+				if err := checkResourceSpec(configs); err != nil {
+					return err
+				}
 				portConfigComponentIDs = append(portConfigComponentIDs, component.ComponentBase.ComponentID)
 				if configs.BasePorts != nil && checkPluginHaveInbound(plugin.PluginModel) {
 					psPorts := s.handlePluginMappingPort(app.TenantID, component.ComponentBase.ComponentID, plugin.PluginModel, configs.BasePorts)
@@ -19,6 +19,8 @@
 package handler
 
 import (
+	"net/http"
+
 	api_model "github.com/gridworkz/kato/api/model"
 	"github.com/gridworkz/kato/api/util"
 	"github.com/gridworkz/kato/builder/exector"
@@ -84,4 +86,17 @@ type ServiceHandler interface {
 	UpdateServiceMonitor(tenantID, serviceID, name string, update api_model.UpdateServiceMonitorRequestStruct) (*dbmodel.TenantServiceMonitor, error)
 	DeleteServiceMonitor(tenantID, serviceID, name string) (*dbmodel.TenantServiceMonitor, error)
 	AddServiceMonitor(tenantID, serviceID string, add api_model.AddServiceMonitorRequestStruct) (*dbmodel.TenantServiceMonitor, error)
+	ListScheduledScalingPolicies(serviceID string) ([]*dbmodel.TenantServiceScheduledScalingPolicy, error)
+	AddScheduledScalingPolicy(serviceID string, req *api_model.ScheduledScalingPolicyReq) (*dbmodel.TenantServiceScheduledScalingPolicy, error)
+	UpdateScheduledScalingPolicy(serviceID, policyID string, req *api_model.ScheduledScalingPolicyReq) (*dbmodel.TenantServiceScheduledScalingPolicy, error)
+	DeleteScheduledScalingPolicy(serviceID, policyID string) (*dbmodel.TenantServiceScheduledScalingPolicy, error)
+	GetCanary(serviceID string) (*dbmodel.TenantServiceCanary, error)
+	StartCanary(serviceID, eventID string, req *api_model.CanaryReq) (*dbmodel.TenantServiceCanary, error)
+	UpdateCanary(serviceID, eventID string, req *api_model.CanaryReq) (*dbmodel.TenantServiceCanary, error)
+	PromoteCanary(serviceID, eventID string) (*dbmodel.TenantServiceCanary, error)
+	AbortCanary(serviceID, eventID string) (*dbmodel.TenantServiceCanary, error)
+	GetWebhook(serviceID string) (*dbmodel.TenantServiceWebhook, error)
+	SaveWebhook(serviceID string, req *api_model.WebhookReq) (*dbmodel.TenantServiceWebhook, error)
+	DeleteWebhook(serviceID string) error
+	TriggerWebhook(serviceID string, header http.Header, body []byte) (*api_model.WebhookResult, error)
 }
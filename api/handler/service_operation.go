@@ -81,6 +81,11 @@ func (o *OperationHandler) Build(buildInfo model.BuildInfoRequestStruct) (re Ope
 	if buildInfo.CodeInfo.Cmd != "" {
 		version.Cmd = buildInfo.CodeInfo.Cmd
 	}
+	if buildInfo.Kind == model.FromCodeBuildKing {
+		version.CodeBranch = buildInfo.CodeInfo.Branch
+		version.CodeVersion = buildInfo.CodeInfo.CodeVersion
+		version.CommitMsg = buildInfo.CodeInfo.CommitMsg
+	}
 	serviceID := buildInfo.ServiceID
 	err = db.GetManager().VersionInfoDao().AddModel(&version)
 	if err != nil {
@@ -179,6 +184,10 @@ func (o *OperationHandler) Start(startInfo model.StartOrStopInfoRequestStruct) (
 		return
 	}
 	re.EventID = startInfo.EventID
+	if err := checkImagePolicy(service.TenantID, service.ServiceID, service.DeployVersion, startInfo.EventID); err != nil {
+		re.ErrMsg = err.Error()
+		return
+	}
 	TaskBody := dmodel.StartTaskBody{
 		TenantID:              service.TenantID,
 		ServiceID:             service.ServiceID,
@@ -228,6 +237,10 @@ func (o *OperationHandler) Upgrade(ru model.UpgradeInfoRequestStruct) (re Operat
 		re.ErrMsg = fmt.Sprintf("get service %s version %s failure", ru.ServiceID, ru.UpgradeVersion)
 		return
 	}
+	if err := checkImagePolicy(services.TenantID, services.ServiceID, ru.UpgradeVersion, ru.EventID); err != nil {
+		re.ErrMsg = err.Error()
+		return
+	}
 	oldDeployVersion := services.DeployVersion
 	var rollback = func() {
 		services.DeployVersion = oldDeployVersion
@@ -281,6 +294,10 @@ func (o *OperationHandler) RollBack(rollback model.RollbackInfoRequestStruct) (r
 		re.ErrMsg = fmt.Sprintf("service %s is thirdpart service", rollback.ServiceID)
 		return
 	}
+	if err := checkImagePolicy(service.TenantID, service.ServiceID, rollback.RollBackVersion, rollback.EventID); err != nil {
+		re.ErrMsg = err.Error()
+		return
+	}
 	oldDeployVersion := service.DeployVersion
 	var rollbackFunc = func() {
 		service.DeployVersion = oldDeployVersion
@@ -357,6 +374,7 @@ func (o *OperationHandler) buildFromImage(r model.BuildInfoRequestStruct, servic
 	body["deploy_version"] = r.DeployVersion
 	body["namespace"] = service.Namespace
 	body["event_id"] = r.EventID
+	body["tenant_id"] = service.TenantID
 	body["tenant_name"] = r.TenantName
 	body["service_alias"] = service.ServiceAlias
 	body["action"] = r.Action
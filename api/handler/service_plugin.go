@@ -30,6 +30,7 @@ import (
 	"github.com/gridworkz/kato/db"
 	dbmodel "github.com/gridworkz/kato/db/model"
 	gclient "github.com/gridworkz/kato/mq/client"
+	envoyv2 "github.com/gridworkz/kato/node/core/envoy/v2"
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/sirupsen/logrus"
 )
@@ -87,6 +88,9 @@ func (s *ServiceAction) TenantServiceDeletePluginRelation(tenantID, serviceID, p
 
 //SetTenantServicePluginRelation
 func (s *ServiceAction) SetTenantServicePluginRelation(tenantID, serviceID string, pss *api_model.PluginSetStruct) (*dbmodel.TenantServicePluginRelation, *util.APIHandleError) {
+	if err := checkResourceSpec(pss.Body.ConfigEnvs.ComplexEnvs); err != nil {
+		return nil, err
+	}
 	plugin, err := db.GetManager().TenantPluginDao().GetPluginByID(pss.Body.PluginID, tenantID)
 	if err != nil {
 		return nil, util.CreateAPIHandleErrorFromDBError("get plugin by plugin id", err)
@@ -215,6 +219,9 @@ func checkPluginHaveInbound(model string) bool {
 
 //UpdateVersionEnv
 func (s *ServiceAction) UpdateVersionEnv(uve *api_model.SetVersionEnv) *util.APIHandleError {
+	if err := checkResourceSpec(uve.Body.ConfigEnvs.ComplexEnvs); err != nil {
+		return err
+	}
 	plugin, err := db.GetManager().TenantPluginDao().GetPluginByID(uve.PluginID, uve.Body.TenantID)
 	if err != nil {
 		return util.CreateAPIHandleErrorFromDBError("get plugin by plugin id", err)
@@ -282,6 +289,19 @@ func (s *ServiceAction) upNormalEnvs(tx *gorm.DB, uve *api_model.SetVersionEnv)
 	return nil
 }
 
+//checkResourceSpec checks the retry, timeout and fault injection options of the dependencies
+func checkResourceSpec(config *api_model.ResourceSpec) *util.APIHandleError {
+	if config == nil {
+		return nil
+	}
+	for _, service := range config.BaseServices {
+		if err := envoyv2.ValidateOptions(service.Options); err != nil {
+			return util.CreateAPIHandleError(400, fmt.Errorf("invalid options of dependency %s: %v", service.DependServiceAlias, err))
+		}
+	}
+	return nil
+}
+
 //SavePluginConfig save plugin dynamic discovery config
 func (s *ServiceAction) SavePluginConfig(serviceID, pluginID string, config *api_model.ResourceSpec) *util.APIHandleError {
 	if config == nil {
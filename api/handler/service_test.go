@@ -112,3 +112,22 @@ func TestGetServicesDisk(t *testing.T) {
 	disk := GetServicesDiskDeprecated([]string{"ef75e1d5e3df412a8af06129dae42869"}, prometheusCli)
 	t.Log(disk)
 }
+
+func TestDiscoveryCfgValidate(t *testing.T) {
+	tests := []struct {
+		cfg     dCfg
+		wantErr bool
+	}{
+		{cfg: dCfg{Type: "etcd", Servers: []string{"127.0.0.1:2379"}, Key: "/foobar/eps"}},
+		{cfg: dCfg{Type: "Consul", Servers: []string{"127.0.0.1:8500"}, Key: "orders?tag=v1"}},
+		{cfg: dCfg{Type: "dns", Key: "_http._tcp.orders.example"}},
+		{cfg: dCfg{Type: "consul", Key: "orders"}, wantErr: true},
+		{cfg: dCfg{Type: "dns"}, wantErr: true},
+		{cfg: dCfg{Type: "zookeeper", Servers: []string{"127.0.0.1:2181"}, Key: "/orders"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := tc.cfg.validate(); (err != nil) != tc.wantErr {
+			t.Errorf("%+v: want error: %v, but got %v", tc.cfg, tc.wantErr, err)
+		}
+	}
+}
@@ -46,4 +46,10 @@ type TenantHandler interface {
 	UpdateTenant(*dbmodel.Tenants) error
 	DeleteTenant(tenantID string) error
 	GetClusterResource() *ClusterResourceStats
+	GetImagePolicy(tenantID string) (*dbmodel.TenantImagePolicy, error)
+	SaveImagePolicy(tenantID string, req *api_model.ImagePolicyReq) (*dbmodel.TenantImagePolicy, error)
+	DeleteImagePolicy(tenantID string) error
+	GetMeshTLSPolicy(tenantID, appID string) (*dbmodel.TenantMeshTLSPolicy, error)
+	SaveMeshTLSPolicy(tenantID, appID string, req *api_model.MeshTLSPolicyReq) (*dbmodel.TenantMeshTLSPolicy, error)
+	DeleteMeshTLSPolicy(tenantID, appID string) error
 }
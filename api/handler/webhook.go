@@ -0,0 +1,470 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/api/util"
+	"github.com/gridworkz/kato/api/util/bcode"
+	"github.com/gridworkz/kato/db"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	core_util "github.com/gridworkz/kato/util"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// the providers of the push events
+const (
+	WebhookProviderGithub  = "github"
+	WebhookProviderGitlab  = "gitlab"
+	WebhookProviderGitea   = "gitea"
+	WebhookProviderGeneric = "generic"
+)
+
+// zeroCommit is the commit of a deleted branch
+const zeroCommit = "0000000000000000000000000000000000000000"
+
+//GetWebhook returns the git push webhook of the component.
+func (s *ServiceAction) GetWebhook(serviceID string) (*dbmodel.TenantServiceWebhook, error) {
+	return getWebhook(serviceID)
+}
+
+//SaveWebhook creates or updates the git push webhook of the component.
+func (s *ServiceAction) SaveWebhook(serviceID string, req *api_model.WebhookReq) (*dbmodel.TenantServiceWebhook, error) {
+	if err := validateWebhook(req); err != nil {
+		return nil, err
+	}
+	envs, err := json.Marshal(req.BuildEnvs)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := db.GetManager().TenantServiceWebhookDao().GetByServiceID(serviceID)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		webhook = &dbmodel.TenantServiceWebhook{ServiceID: serviceID}
+	}
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = core_util.NewUUID()
+	}
+	// the password is not returned, keep the old one if it is not specified
+	if req.Password != "" || req.User != webhook.User {
+		webhook.Password = req.Password
+	}
+	webhook.Branches = req.Branches
+	webhook.Paths = req.Paths
+	webhook.RepoURL = req.RepoURL
+	webhook.Branch = req.Branch
+	if webhook.Branch == "" {
+		webhook.Branch = "master"
+	}
+	webhook.ServerType = req.ServerType
+	if webhook.ServerType == "" {
+		webhook.ServerType = "git"
+	}
+	webhook.Lang = req.Lang
+	webhook.Runtime = req.Runtime
+	webhook.User = req.User
+	webhook.BuildEnvs = string(envs)
+	webhook.Action = req.Action
+	webhook.Enabled = req.Enabled
+	if webhook.ID == 0 {
+		err = db.GetManager().TenantServiceWebhookDao().AddModel(webhook)
+	} else {
+		err = db.GetManager().TenantServiceWebhookDao().UpdateModel(webhook)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+//DeleteWebhook deletes the git push webhook of the component.
+func (s *ServiceAction) DeleteWebhook(serviceID string) error {
+	if _, err := getWebhook(serviceID); err != nil {
+		return err
+	}
+	return db.GetManager().TenantServiceWebhookDao().DeleteByServiceID(serviceID)
+}
+
+//TriggerWebhook verifies the push event and builds the component if the event matches the webhook.
+//Push events of github, gitlab, gitea and the generic json payload are supported.
+func (s *ServiceAction) TriggerWebhook(serviceID string, header http.Header, body []byte) (*api_model.WebhookResult, error) {
+	webhook, err := getWebhook(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, bcode.ErrWebhookDisabled
+	}
+	provider, event := webhookEvent(header)
+	if !verifyWebhook(provider, header, body, webhook.Secret) {
+		return nil, bcode.ErrWebhookSignature
+	}
+	result := &api_model.WebhookResult{Provider: provider}
+	if event != "push" {
+		result.Reason = fmt.Sprintf("%s event is ignored", event)
+		return result, nil
+	}
+	push, err := parsePushEvent(body)
+	if err != nil {
+		return nil, bcode.NewBadRequest(fmt.Sprintf("invalid push event: %v", err))
+	}
+	push.Provider = provider
+	result.Branch, result.Commit = push.Branch, push.Commit
+	if reason := filterPushEvent(webhook, push); reason != "" {
+		result.Reason = reason
+		return result, nil
+	}
+	if push.Commit != "" {
+		ok, err := db.GetManager().TenantServiceWebhookDao().UpdateLastCommit(serviceID, push.Commit)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			result.Reason = fmt.Sprintf("commit %s has been built", push.Commit)
+			return result, nil
+		}
+	}
+	res, err := s.buildByWebhook(webhook, push)
+	if err != nil {
+		// the commit can be delivered again
+		if _, uerr := db.GetManager().TenantServiceWebhookDao().UpdateLastCommit(serviceID, webhook.LastCommit); uerr != nil {
+			logrus.Warningf("restore the last commit of webhook %s: %v", serviceID, uerr)
+		}
+		return nil, err
+	}
+	result.Triggered = true
+	result.EventID = res.EventID
+	result.DeployVersion = res.DeployVersion
+	return result, nil
+}
+
+func (s *ServiceAction) buildByWebhook(webhook *dbmodel.TenantServiceWebhook, push *pushEvent) (*OperationResult, error) {
+	service, err := db.GetManager().TenantServiceDao().GetServiceByID(webhook.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := db.GetManager().TenantDao().GetTenantByUUID(service.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	envs := make(map[string]string)
+	if webhook.BuildEnvs != "" {
+		if err := json.Unmarshal([]byte(webhook.BuildEnvs), &envs); err != nil {
+			logrus.Warningf("unmarshal build envs of webhook %s: %v", webhook.ServiceID, err)
+		}
+	}
+	reqBody := fmt.Sprintf(`{"provider":%q,"branch":%q,"commit":%q}`, push.Provider, push.Branch, push.Commit)
+	event, err := util.CreateEvent(dbmodel.TargetTypeService, "build-service", service.ServiceID, service.TenantID, reqBody, push.Author, dbmodel.ASYNEVENTTYPE)
+	if err != nil {
+		return nil, fmt.Errorf("create build event: %v", err)
+	}
+	res := GetOperationHandler().Build(api_model.BuildInfoRequestStruct{
+		BuildENVs: envs,
+		Kind:      api_model.FromCodeBuildKing,
+		Action:    webhook.Action,
+		EventID:   event.EventID,
+		Operator:  truncate(push.Author, 40),
+		CodeInfo: api_model.BuildCodeInfo{
+			RepoURL:     webhook.RepoURL,
+			Branch:      push.Branch,
+			Lang:        webhook.Lang,
+			ServerType:  webhook.ServerType,
+			Runtime:     webhook.Runtime,
+			User:        webhook.User,
+			Password:    webhook.Password,
+			CodeVersion: push.Commit,
+			CommitMsg:   truncate(push.Message, 1024),
+		},
+		TenantName: tenant.Name,
+		ServiceID:  service.ServiceID,
+	})
+	if res.Status != "success" {
+		util.UpdateEvent(event.EventID, 500)
+		return nil, errors.New(res.ErrMsg)
+	}
+	return &res, nil
+}
+
+func getWebhook(serviceID string) (*dbmodel.TenantServiceWebhook, error) {
+	webhook, err := db.GetManager().TenantServiceWebhookDao().GetByServiceID(serviceID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, bcode.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func validateWebhook(req *api_model.WebhookReq) error {
+	if req.Action != "" && req.Action != "upgrade" {
+		return bcode.NewBadRequest("action should be empty or 'upgrade'")
+	}
+	for _, pattern := range splitPatterns(req.Branches) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return bcode.NewBadRequest(fmt.Sprintf("invalid branch pattern %q", pattern))
+		}
+	}
+	for _, pattern := range splitPatterns(req.Paths) {
+		for _, elem := range strings.Split(pattern, "/") {
+			if _, err := path.Match(elem, ""); err != nil {
+				return bcode.NewBadRequest(fmt.Sprintf("invalid path pattern %q", pattern))
+			}
+		}
+	}
+	return nil
+}
+
+//webhookEvent detects the provider and the event type from the headers.
+func webhookEvent(header http.Header) (provider, event string) {
+	switch {
+	case header.Get("X-Gitea-Event") != "":
+		return WebhookProviderGitea, header.Get("X-Gitea-Event")
+	case header.Get("X-GitHub-Event") != "":
+		return WebhookProviderGithub, header.Get("X-GitHub-Event")
+	case header.Get("X-Gitlab-Event") != "":
+		if header.Get("X-Gitlab-Event") == "Push Hook" {
+			return WebhookProviderGitlab, "push"
+		}
+		return WebhookProviderGitlab, header.Get("X-Gitlab-Event")
+	}
+	return WebhookProviderGeneric, "push"
+}
+
+//verifyWebhook verifies the HMAC-SHA256 signature of the body, or the token for gitlab.
+//The generic payload is signed in the 'X-Kato-Signature' header, such as 'sha256=<hex>',
+//or carries the secret in the 'X-Kato-Token' header.
+func verifyWebhook(provider string, header http.Header, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	switch provider {
+	case WebhookProviderGithub:
+		return verifySignature(strings.TrimPrefix(header.Get("X-Hub-Signature-256"), "sha256="), body, secret)
+	case WebhookProviderGitea:
+		return verifySignature(header.Get("X-Gitea-Signature"), body, secret)
+	case WebhookProviderGitlab:
+		return hmac.Equal([]byte(header.Get("X-Gitlab-Token")), []byte(secret))
+	}
+	if signature := header.Get("X-Kato-Signature"); signature != "" {
+		return verifySignature(strings.TrimPrefix(signature, "sha256="), body, secret)
+	}
+	return hmac.Equal([]byte(header.Get("X-Kato-Token")), []byte(secret))
+}
+
+func verifySignature(signature string, body []byte, secret string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+//pushEvent is the push event normalized from the payloads of the providers.
+type pushEvent struct {
+	Provider string
+	Branch   string
+	Commit   string
+	Message  string
+	Author   string
+	Deleted  bool
+	// Files is nil if the changed files are unknown
+	Files []string
+}
+
+type pushCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  struct {
+		Name string `json:"name"`
+	} `json:"author"`
+	Added    []string `json:"added"`
+	Modified []string `json:"modified"`
+	Removed  []string `json:"removed"`
+}
+
+//pushPayload is compatible with the push payloads of github, gitlab and gitea.
+//The generic payload may use branch, commit, message, author and files instead.
+type pushPayload struct {
+	Ref        string       `json:"ref"`
+	After      string       `json:"after"`
+	Deleted    bool         `json:"deleted"`
+	HeadCommit *pushCommit  `json:"head_commit"`
+	Commits    []pushCommit `json:"commits"`
+	// gitlab only sends the first 20 commits
+	TotalCommitsCount int    `json:"total_commits_count"`
+	UserName          string `json:"user_name"`
+
+	Branch  string   `json:"branch"`
+	Commit  string   `json:"commit"`
+	Message string   `json:"message"`
+	Author  string   `json:"author"`
+	Files   []string `json:"files"`
+}
+
+func parsePushEvent(body []byte) (*pushEvent, error) {
+	var payload pushPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	push := &pushEvent{
+		Branch:  payload.Branch,
+		Commit:  payload.Commit,
+		Message: payload.Message,
+		Author:  payload.Author,
+		Deleted: payload.Deleted,
+	}
+	if push.Branch == "" {
+		if !strings.HasPrefix(payload.Ref, "refs/heads/") {
+			if payload.Ref == "" {
+				return nil, fmt.Errorf("neither ref nor branch is specified")
+			}
+			// tags are not built
+			return push, nil
+		}
+		push.Branch = strings.TrimPrefix(payload.Ref, "refs/heads/")
+	}
+	if push.Commit == "" {
+		push.Commit = payload.After
+	}
+	if push.Commit == zeroCommit {
+		push.Commit = ""
+		push.Deleted = true
+	}
+	head := payload.HeadCommit
+	if head == nil && len(payload.Commits) > 0 {
+		head = &payload.Commits[len(payload.Commits)-1]
+		for i := range payload.Commits {
+			if payload.Commits[i].ID == push.Commit {
+				head = &payload.Commits[i]
+				break
+			}
+		}
+	}
+	if head != nil {
+		if push.Commit == "" {
+			push.Commit = head.ID
+		}
+		if push.Message == "" {
+			push.Message = head.Message
+		}
+		if push.Author == "" {
+			push.Author = head.Author.Name
+		}
+	}
+	if push.Author == "" {
+		push.Author = payload.UserName
+	}
+	if len(payload.Commits) == 0 && payload.Files == nil {
+		return push, nil
+	}
+	if payload.TotalCommitsCount > len(payload.Commits) {
+		return push, nil
+	}
+	push.Files = append([]string{}, payload.Files...)
+	for _, commit := range payload.Commits {
+		push.Files = append(push.Files, commit.Added...)
+		push.Files = append(push.Files, commit.Modified...)
+		push.Files = append(push.Files, commit.Removed...)
+	}
+	return push, nil
+}
+
+//filterPushEvent returns the reason if the push event should not trigger a build.
+func filterPushEvent(webhook *dbmodel.TenantServiceWebhook, push *pushEvent) string {
+	if push.Branch == "" {
+		return "not a branch push"
+	}
+	if push.Deleted {
+		return fmt.Sprintf("branch %s is deleted", push.Branch)
+	}
+	branches := splitPatterns(webhook.Branches)
+	if len(branches) == 0 {
+		branches = []string{webhook.Branch}
+	}
+	if !matchAny(branches, push.Branch, path.Match) {
+		return fmt.Sprintf("branch %s does not match %s", push.Branch, strings.Join(branches, ","))
+	}
+	paths := splitPatterns(webhook.Paths)
+	if len(paths) == 0 || push.Files == nil {
+		return ""
+	}
+	for _, file := range push.Files {
+		if matchAny(paths, file, matchPath) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("no changed file matches %s", strings.Join(paths, ","))
+}
+
+func splitPatterns(s string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(s, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func matchAny(patterns []string, name string, match func(pattern, name string) (bool, error)) bool {
+	for _, pattern := range patterns {
+		if ok, _ := match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+//matchPath matches the file against the pattern like .gitignore does, '**' matches any directories,
+//and the pattern without '/', such as '*.go', matches the files in all the directories.
+func matchPath(pattern, file string) (bool, error) {
+	if !strings.Contains(pattern, "/") {
+		return path.Match(pattern, path.Base(file))
+	}
+	return matchElems(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(file, "/"))
+}
+
+func matchElems(patterns, elems []string) (bool, error) {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(elems); i++ {
+				if ok, err := matchElems(patterns[1:], elems[i:]); ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(elems) == 0 {
+			return false, nil
+		}
+		if ok, err := path.Match(patterns[0], elems[0]); !ok || err != nil {
+			return false, err
+		}
+		patterns, elems = patterns[1:], elems[1:]
+	}
+	return len(elems) == 0, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"testing"
+
+	dbmodel "github.com/gridworkz/kato/db/model"
+)
+
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	secret := "secret"
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   bool
+	}{
+		{
+			name:   "github",
+			header: map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(body, secret)},
+			want:   true,
+		},
+		{
+			name:   "github with wrong secret",
+			header: map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(body, "wrong")},
+		},
+		{
+			name:   "github without signature",
+			header: map[string]string{"X-GitHub-Event": "push"},
+		},
+		{
+			name:   "gitea",
+			header: map[string]string{"X-Gitea-Event": "push", "X-GitHub-Event": "push", "X-Gitea-Signature": sign(body, secret)},
+			want:   true,
+		},
+		{
+			name:   "gitlab",
+			header: map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": secret},
+			want:   true,
+		},
+		{
+			name:   "gitlab with wrong token",
+			header: map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "wrong"},
+		},
+		{
+			name:   "generic signature",
+			header: map[string]string{"X-Kato-Signature": "sha256=" + sign(body, secret)},
+			want:   true,
+		},
+		{
+			name:   "generic token",
+			header: map[string]string{"X-Kato-Token": secret},
+			want:   true,
+		},
+		{
+			name: "generic without token",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tc.header {
+				header.Set(k, v)
+			}
+			provider, _ := webhookEvent(header)
+			if got := verifyWebhook(provider, header, body, secret); got != tc.want {
+				t.Errorf("want %v, but got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParsePushEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *pushEvent
+	}{
+		{
+			name: "github",
+			body: `{"ref":"refs/heads/master","after":"b2","deleted":false,
+				"head_commit":{"id":"b2","message":"fix","author":{"name":"bob"}},
+				"commits":[{"id":"b1","added":["a.go"]},{"id":"b2","modified":["b.go"],"removed":["c.go"]}]}`,
+			want: &pushEvent{Branch: "master", Commit: "b2", Message: "fix", Author: "bob", Files: []string{"a.go", "b.go", "c.go"}},
+		},
+		{
+			name: "gitlab with truncated commits",
+			body: `{"ref":"refs/heads/dev","after":"c1","user_name":"alice","total_commits_count":30,
+				"commits":[{"id":"c1","message":"feat","author":{"name":"carol"},"added":["a.go"]}]}`,
+			want: &pushEvent{Branch: "dev", Commit: "c1", Message: "feat", Author: "carol"},
+		},
+		{
+			name: "deleted branch",
+			body: `{"ref":"refs/heads/dev","after":"0000000000000000000000000000000000000000"}`,
+			want: &pushEvent{Branch: "dev", Deleted: true},
+		},
+		{
+			name: "tag",
+			body: `{"ref":"refs/tags/v1.0.0","after":"d1"}`,
+			want: &pushEvent{},
+		},
+		{
+			name: "generic",
+			body: `{"branch":"master","commit":"e1","message":"release","author":"dave","files":["go.mod"]}`,
+			want: &pushEvent{Branch: "master", Commit: "e1", Message: "release", Author: "dave", Files: []string{"go.mod"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parsePushEvent([]byte(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want %+v, but got %+v", tc.want, got)
+			}
+		})
+	}
+	if _, err := parsePushEvent([]byte(`{"after":"f1"}`)); err == nil {
+		t.Error("want an error for the payload without ref or branch")
+	}
+}
+
+func TestFilterPushEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook dbmodel.TenantServiceWebhook
+		push    pushEvent
+		want    bool
+	}{
+		{
+			name:    "default branch",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master"},
+			push:    pushEvent{Branch: "master"},
+			want:    true,
+		},
+		{
+			name:    "other branch",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master"},
+			push:    pushEvent{Branch: "dev"},
+		},
+		{
+			name:    "branch pattern",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master", Branches: "master, release-*"},
+			push:    pushEvent{Branch: "release-1.0"},
+			want:    true,
+		},
+		{
+			name:    "deleted branch",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master"},
+			push:    pushEvent{Branch: "master", Deleted: true},
+		},
+		{
+			name:    "matched path",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master", Paths: "src/**,*.mod"},
+			push:    pushEvent{Branch: "master", Files: []string{"docs/README.md", "src/pkg/main.go"}},
+			want:    true,
+		},
+		{
+			name:    "matched base name",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master", Paths: "src/**,*.mod"},
+			push:    pushEvent{Branch: "master", Files: []string{"tools/go.mod"}},
+			want:    true,
+		},
+		{
+			name:    "unmatched path",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master", Paths: "src/**,*.mod"},
+			push:    pushEvent{Branch: "master", Files: []string{"docs/README.md"}},
+		},
+		{
+			name:    "unknown files",
+			webhook: dbmodel.TenantServiceWebhook{Branch: "master", Paths: "src/**"},
+			push:    pushEvent{Branch: "master"},
+			want:    true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := filterPushEvent(&tc.webhook, &tc.push)
+			if got := reason == ""; got != tc.want {
+				t.Errorf("want %v, but got %v: %s", tc.want, got, reason)
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"src/**", "src/main.go", true},
+		{"src/**", "src", true},
+		{"src/**", "app/src/main.go", false},
+		{"**/test/*.go", "a/b/test/x.go", true},
+		{"**/test/*.go", "test/x.go", true},
+		{"**/test/*.go", "test/y/x.go", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/a/b.md", false},
+		{"Dockerfile", "build/Dockerfile", true},
+	}
+	for _, tc := range tests {
+		if got, _ := matchPath(tc.pattern, tc.file); got != tc.want {
+			t.Errorf("pattern %s, file %s: want %v, but got %v", tc.pattern, tc.file, tc.want, got)
+		}
+	}
+}
@@ -22,30 +22,42 @@ package model
 type AutoscalerRuleReq struct {
 	RuleID      string `json:"rule_id" validate:"rule_id|required"`
 	ServiceID   string
-	Enable      bool   `json:"enable" validate:"enable|required"`
-	XPAType     string `json:"xpa_type" validate:"xpa_type|required"`
-	MinReplicas int    `json:"min_replicas" validate:"min_replicas|required"`
-	MaxReplicas int    `json:"max_replicas" validate:"min_replicas|required"`
-	Metrics     []struct {
-		MetricsType       string `json:"metric_type"`
-		MetricsName       string `json:"metric_name"`
-		MetricTargetType  string `json:"metric_target_type"`
-		MetricTargetValue int    `json:"metric_target_value"`
-	} `json:"metrics"`
+	Enable      bool                   `json:"enable" validate:"enable|required"`
+	XPAType     string                 `json:"xpa_type" validate:"xpa_type|required"`
+	MinReplicas int                    `json:"min_replicas" validate:"min_replicas|required"`
+	MaxReplicas int                    `json:"max_replicas" validate:"min_replicas|required"`
+	Metrics     []AutoscalerRuleMetric `json:"metrics"`
 }
 
 // AutoscalerRuleResp -
 type AutoscalerRuleResp struct {
-	RuleID      string `json:"rule_id"`
-	ServiceID   string `json:"service_id"`
-	Enable      bool   `json:"enable"`
-	XPAType     string `json:"xpa_type"`
-	MinReplicas int    `json:"min_replicas"`
-	MaxReplicas int    `json:"max_replicas"`
-	Metrics     []struct {
-		MetricsType       string `json:"metric_type"`
-		MetricsName       string `json:"metric_name"`
-		MetricTargetType  string `json:"metric_target_type"`
-		MetricTargetValue int    `json:"metric_target_value"`
-	} `json:"metrics"`
+	RuleID      string                 `json:"rule_id"`
+	ServiceID   string                 `json:"service_id"`
+	Enable      bool                   `json:"enable"`
+	XPAType     string                 `json:"xpa_type"`
+	MinReplicas int                    `json:"min_replicas"`
+	MaxReplicas int                    `json:"max_replicas"`
+	Metrics     []AutoscalerRuleMetric `json:"metrics"`
+}
+
+// AutoscalerRuleMetric -
+type AutoscalerRuleMetric struct {
+	// resource_metrics, pods_metrics, object_metrics or external_metrics
+	MetricsType string `json:"metric_type"`
+	MetricsName string `json:"metric_name"`
+	// utilization, average_value or value
+	MetricTargetType  string `json:"metric_target_type"`
+	MetricTargetValue int    `json:"metric_target_value"`
+	// MetricSelector is a label selector, such as 'queue=orders,env in (prod)'.
+	// Optional for pods_metrics, object_metrics and external_metrics.
+	MetricSelector string `json:"metric_selector,omitempty"`
+	// DescribedObject is required for object_metrics.
+	DescribedObject *AutoscalerDescribedObject `json:"described_object,omitempty"`
+}
+
+// AutoscalerDescribedObject the kubernetes object described by an object metric.
+type AutoscalerDescribedObject struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"api_version"`
 }
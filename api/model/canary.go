@@ -0,0 +1,27 @@
+package model
+
+// CanaryReq starts or adjusts the canary release of the component.
+// The canary version runs side by side with the current deploy version, and the
+// requests selected by Weight, Header or Cookie are routed to it by the gateway.
+type CanaryReq struct {
+	// the build version to be released, required when starting the canary release
+	// in: body
+	// required: false
+	CanaryVersion string `json:"canary_version"`
+	// percentage of the requests routed to the canary version, 0 to 100
+	// in: body
+	// required: false
+	Weight int `json:"weight"`
+	// requests with the headers are routed to the canary version, such as 'X-Canary=true'
+	// in: body
+	// required: false
+	Header string `json:"header"`
+	// requests with the cookies are routed to the canary version, such as 'canary=always'
+	// in: body
+	// required: false
+	Cookie string `json:"cookie"`
+	// replicas of the canary version, 1 if empty
+	// in: body
+	// required: false
+	Replicas int `json:"replicas"`
+}
@@ -33,6 +33,21 @@ type AddHTTPRuleStruct struct {
 	Certificate    string                 `json:"certificate"`
 	PrivateKey     string                 `json:"private_key"`
 	RuleExtensions []*RuleExtensionStruct `json:"rule_extensions"`
+	// rate limit of the rule, 0 means unlimited
+	RateLimitRPS   int `json:"rate_limit_rps"`
+	RateLimitBurst int `json:"rate_limit_burst"`
+	// ip or header:<name>, default is ip
+	RateLimitKey    string `json:"rate_limit_key"`
+	ConnectionLimit int    `json:"connection_limit"`
+	// comma separated ips or CIDRs
+	IPAllowList string `json:"ip_allow_list"`
+	IPDenyList  string `json:"ip_deny_list"`
+	// the secret in the namespace of the service, it should be labeled with creator=Kato
+	// and contains the htpasswd users in the key auth
+	BasicAuthSecret string `json:"basic_auth_secret"`
+	// the requests are allowed if the forward auth url returns 2xx,
+	// the host of the url should be a fully qualified domain name or an ip
+	ForwardAuthURL string `json:"forward_auth_url"`
 }
 
 //UpdateHTTPRuleStruct is used to update http rule, certificate and rule extensions
@@ -50,6 +65,21 @@ type UpdateHTTPRuleStruct struct {
 	Certificate    string                 `json:"certificate"`
 	PrivateKey     string                 `json:"private_key"`
 	RuleExtensions []*RuleExtensionStruct `json:"rule_extensions"`
+	// rate limit of the rule, 0 means unlimited
+	RateLimitRPS   int `json:"rate_limit_rps"`
+	RateLimitBurst int `json:"rate_limit_burst"`
+	// ip or header:<name>, default is ip
+	RateLimitKey    string `json:"rate_limit_key"`
+	ConnectionLimit int    `json:"connection_limit"`
+	// comma separated ips or CIDRs
+	IPAllowList string `json:"ip_allow_list"`
+	IPDenyList  string `json:"ip_deny_list"`
+	// the secret in the namespace of the service, it should be labeled with creator=Kato
+	// and contains the htpasswd users in the key auth
+	BasicAuthSecret string `json:"basic_auth_secret"`
+	// the requests are allowed if the forward auth url returns 2xx,
+	// the host of the url should be a fully qualified domain name or an ip
+	ForwardAuthURL string `json:"forward_auth_url"`
 }
 
 //DeleteHTTPRuleStruct contains the id of http rule that will be deleted
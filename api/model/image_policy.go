@@ -0,0 +1,25 @@
+package model
+
+// ImagePolicyReq sets the image policy of the tenant.
+// The images built from a registry out of TrustedRegistries, or not signed by one of the PublicKeys
+// while RequireSignature is set, violate the policy. The violations are refused in enforce mode,
+// and only written into the event log in audit mode.
+type ImagePolicyReq struct {
+	// audit or enforce
+	// in: body
+	// required: true
+	Mode string `json:"mode" validate:"mode|required|in:audit,enforce"`
+	// whether the images must be signed before deployment
+	// in: body
+	// required: false
+	RequireSignature bool `json:"require_signature"`
+	// patterns of the trusted registries separated by commas, such as 'goodrain.me,docker.io/library/*',
+	// all registries are trusted if empty
+	// in: body
+	// required: false
+	TrustedRegistries string `json:"trusted_registries"`
+	// cosign public keys in PEM trusted besides the platform key
+	// in: body
+	// required: false
+	PublicKeys string `json:"public_keys"`
+}
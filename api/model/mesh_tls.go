@@ -0,0 +1,38 @@
+package model
+
+// the modes of the mutual tls between the mesh connected components
+const (
+	// MeshTLSModeDisabled keeps the traffic between the components in plaintext
+	MeshTLSModeDisabled = "disabled"
+	// MeshTLSModePermissive accepts both mutual tls and plaintext on the inbound ports,
+	// it is used to migrate the components one by one
+	MeshTLSModePermissive = "permissive"
+	// MeshTLSModeStrict accepts mutual tls only, the plaintext connections are refused,
+	// including those from the gateway and the components without the mesh plugin
+	MeshTLSModeStrict = "strict"
+)
+
+// KeyMeshTLSConfig is the key of MeshTLSConfig in the configmap of the mesh plugin
+const KeyMeshTLSConfig = "mesh-tls"
+
+// MeshTLSPolicyReq sets the mutual tls policy of the tenant or of an application.
+// The policy of the application takes precedence over the policy of the tenant.
+type MeshTLSPolicyReq struct {
+	// disabled, permissive or strict
+	// in: body
+	// required: true
+	Mode string `json:"mode" validate:"mode|required|in:disabled,permissive,strict"`
+	// identities allowed to call the components over mutual tls separated by commas,
+	// an identity is 'tenant/app/component', such as 'mytenant/*/web,othertenant'.
+	// The missing segments match any value. All the identities of the mesh are allowed if empty.
+	// in: body
+	// required: false
+	AllowedSources string `json:"allowed_sources"`
+}
+
+// MeshTLSConfig is the mutual tls config of a component, the worker writes it into the
+// configmap of the mesh plugin, and the node configures the envoy sidecar with it.
+type MeshTLSConfig struct {
+	Mode           string   `json:"mode"`
+	AllowedSources []string `json:"allowed_sources,omitempty"`
+}
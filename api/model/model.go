@@ -1468,6 +1468,9 @@ type BuildCodeInfo struct {
 	Password   string `json:"password" validate:"password"`
 	//for .netcore source type, need cmd
 	Cmd string `json:"cmd"`
+	// the commit to be built and its message, which are known before building if the build is triggered by a push event
+	CodeVersion string `json:"code_version"`
+	CommitMsg   string `json:"commit_msg"`
 }
 
 //BuildSlugInfo -
@@ -0,0 +1,35 @@
+package model
+
+// ScheduledScalingPolicyReq adds or updates a scheduled scaling policy of the component.
+// From the activation time of StartCron to the activation time of EndCron,
+// the replicas of the component are kept in [MinReplicas, MaxReplicas].
+type ScheduledScalingPolicyReq struct {
+	// policy_id, generated if empty
+	// in: body
+	// required: false
+	PolicyID string `json:"policy_id"`
+	// enable
+	// in: body
+	// required: false
+	Enable bool `json:"enable"`
+	// cron expression of the beginning of the window, such as '0 8 * * 1-5'
+	// in: body
+	// required: true
+	StartCron string `json:"start_cron" validate:"start_cron|required"`
+	// cron expression of the end of the window, such as '0 20 * * 1-5'
+	// in: body
+	// required: true
+	EndCron string `json:"end_cron" validate:"end_cron|required"`
+	// IANA time zone of the cron expressions, such as 'Asia/Shanghai'. The local time zone of the worker if empty.
+	// in: body
+	// required: false
+	Timezone string `json:"timezone"`
+	// replica floor during the window
+	// in: body
+	// required: false
+	MinReplicas int `json:"min_replicas"`
+	// replica ceiling during the window
+	// in: body
+	// required: true
+	MaxReplicas int `json:"max_replicas" validate:"max_replicas|required"`
+}
@@ -0,0 +1,53 @@
+package model
+
+// WebhookReq creates or updates the git push webhook of the component.
+// A push event that matches the branches and the paths builds the component from source code.
+type WebhookReq struct {
+	// the secret used to verify the signature or the token of the push events, generated if empty
+	// in: body
+	// required: false
+	Secret string `json:"secret"`
+	// comma separated glob patterns of the branches to be built, such as 'master,release-*'.
+	// only the branch of the webhook is built if empty
+	// in: body
+	// required: false
+	Branches string `json:"branches"`
+	// comma separated glob patterns of the changed files, such as 'src/**,*.go'. all the files are matched if empty
+	// in: body
+	// required: false
+	Paths string `json:"paths"`
+	// git address
+	// in: body
+	// required: true
+	RepoURL string `json:"repo_url" validate:"repo_url|required"`
+	// the default branch, master if empty
+	// in: body
+	// required: false
+	Branch string `json:"branch"`
+	// code server type, git if empty
+	// in: body
+	// required: false
+	ServerType string            `json:"server_type"`
+	Lang       string            `json:"lang"`
+	Runtime    string            `json:"runtime"`
+	User       string            `json:"user"`
+	Password   string            `json:"password"`
+	BuildEnvs  map[string]string `json:"build_envs"`
+	// the action after building, 'upgrade' upgrades the component automatically, build only if empty
+	// in: body
+	// required: false
+	Action  string `json:"action" validate:"action|in:upgrade"`
+	Enabled bool   `json:"enabled"`
+}
+
+// WebhookResult is the result of a push event.
+type WebhookResult struct {
+	Triggered bool `json:"triggered"`
+	// the reason why the push event is ignored
+	Reason        string `json:"reason,omitempty"`
+	Provider      string `json:"provider"`
+	Branch        string `json:"branch,omitempty"`
+	Commit        string `json:"commit,omitempty"`
+	EventID       string `json:"event_id,omitempty"`
+	DeployVersion string `json:"deploy_version,omitempty"`
+}
@@ -0,0 +1,69 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package region
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gridworkz/kato/api/util"
+	"github.com/gridworkz/kato/builder/cache"
+	utilhttp "github.com/gridworkz/kato/util/http"
+)
+
+//BuilderInterface builder api
+type BuilderInterface interface {
+	ListBuildCaches(tenantID, serviceID, lang string) ([]*cache.Entry, *util.APIHandleError)
+	PurgeBuildCaches(tenantID, serviceID, lang string) ([]*cache.Entry, *util.APIHandleError)
+}
+
+func (r *regionImpl) Builder() BuilderInterface {
+	return &builder{prefix: "/v2/builder", regionImpl: *r}
+}
+
+type builder struct {
+	regionImpl
+	prefix string
+}
+
+func (b *builder) ListBuildCaches(tenantID, serviceID, lang string) ([]*cache.Entry, *util.APIHandleError) {
+	return b.doCacheRequest("GET", tenantID, serviceID, lang)
+}
+
+func (b *builder) PurgeBuildCaches(tenantID, serviceID, lang string) ([]*cache.Entry, *util.APIHandleError) {
+	return b.doCacheRequest("DELETE", tenantID, serviceID, lang)
+}
+
+func (b *builder) doCacheRequest(method, tenantID, serviceID, lang string) ([]*cache.Entry, *util.APIHandleError) {
+	query := url.Values{}
+	query.Set("tenant_id", tenantID)
+	query.Set("service_id", serviceID)
+	query.Set("lang", lang)
+	var entries []*cache.Entry
+	var decode utilhttp.ResponseBody
+	decode.List = &entries
+	code, err := b.DoRequest(b.prefix+"/cache/?"+query.Encode(), method, nil, &decode)
+	if err == nil && code >= 300 && decode.Msg != "" {
+		err = errors.New(decode.Msg)
+	}
+	if err != nil || code >= 300 {
+		return nil, handleErrAndCode(err, code)
+	}
+	return entries, nil
+}
@@ -43,6 +43,8 @@ type ServiceInterface interface {
 	Stop(eventID string) (string, *util.APIHandleError)
 	Start(eventID string) (string, *util.APIHandleError)
 	EventLog(eventID, level string) ([]*model.MessageData, *util.APIHandleError)
+	WatchPods(handle func(*WatchEvent) error) error
+	WatchStatus(handle func(*WatchEvent) error) error
 }
 
 func (s *services) Pods() ([]*podInfo, *util.APIHandleError) {
@@ -124,6 +126,16 @@ func (s *services) Start(eventID string) (string, *util.APIHandleError) {
 	return eventID, handleAPIResult(code, res)
 }
 
+//WatchPods follows the pod events of the service until handle returns an error
+func (s *services) WatchPods(handle func(*WatchEvent) error) error {
+	return s.watch(s.prefix+"/pods/watch", handle)
+}
+
+//WatchStatus follows the status events of the service until handle returns an error
+func (s *services) WatchStatus(handle func(*WatchEvent) error) error {
+	return s.watch(s.prefix+"/status/watch", handle)
+}
+
 //GetDeployInfo get service deploy info
 func (s *services) GetDeployInfo() (*ServiceDeployInfo, *util.APIHandleError) {
 	var deployInfo ServiceDeployInfo
@@ -0,0 +1,143 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package region
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WatchEvent is a server-sent event of a watch.
+type WatchEvent struct {
+	// ID is the resume token of the event.
+	ID    string
+	Event string
+	Data  []byte
+}
+
+// watchRetryInterval is the pause before a broken watch is resumed.
+var watchRetryInterval = time.Second
+
+// watch follows the server-sent events of path until handle returns an
+// error. Broken connections are resumed after the last event received.
+func (r *regionImpl) watch(path string, handle func(*WatchEvent) error) error {
+	client := *r.Client
+	// the client timeout covers the whole response, a watch never ends
+	client.Timeout = 0
+	var lastID string
+	for {
+		err := r.watchOnce(&client, path, lastID, func(evt *WatchEvent) error {
+			if evt.ID != "" {
+				lastID = evt.ID
+			}
+			return handle(evt)
+		})
+		if _, ok := err.(*watchError); !ok {
+			return err
+		}
+		time.Sleep(watchRetryInterval)
+	}
+}
+
+// watchError is a failure of the watch connection, the watch can be resumed.
+type watchError struct {
+	err error
+}
+
+func (e *watchError) Error() string {
+	return e.err.Error()
+}
+
+func (r *regionImpl) watchOnce(client *http.Client, path, lastID string, handle func(*WatchEvent) error) error {
+	request, err := http.NewRequest("GET", r.GetEndpoint()+path, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+	if r.Token != "" {
+		request.Header.Set("Authorization", "Token "+r.Token)
+	}
+	if lastID != "" {
+		request.Header.Set("Last-Event-ID", lastID)
+	}
+	res, err := client.Do(request)
+	if err != nil {
+		return &watchError{err: err}
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 500 {
+		return &watchError{err: fmt.Errorf("watch %s with code %d", path, res.StatusCode)}
+	}
+	if res.StatusCode != 200 {
+		return fmt.Errorf("watch %s with code %d", path, res.StatusCode)
+	}
+	if err := readEvents(res.Body, func(evt *WatchEvent) error {
+		if evt.Event == "failed" {
+			// the server gave up on this connection, resume after the last event
+			return &watchError{err: fmt.Errorf("%s", evt.Data)}
+		}
+		return handle(evt)
+	}); err != nil {
+		return err
+	}
+	return &watchError{err: io.ErrUnexpectedEOF}
+}
+
+// readEvents parses a text/event-stream, comments and retry fields are skipped.
+func readEvents(body io.Reader, handle func(*WatchEvent) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	evt := &WatchEvent{}
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if data != nil {
+				evt.Data = []byte(strings.Join(data, "\n"))
+				if err := handle(evt); err != nil {
+					return err
+				}
+			}
+			evt, data = &WatchEvent{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			evt.ID = value
+		case "event":
+			evt.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return &watchError{err: err}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package region
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadEvents(t *testing.T) {
+	stream := ": ping\n\n" +
+		"id: a.1\nevent: added\ndata: {\"pod\":1}\n\n" +
+		"retry: 100\n\n" +
+		"event: reset\ndata: line1\ndata: line2\n\n" +
+		"id: a.3\ndata: last\n"
+	var events []*WatchEvent
+	err := readEvents(strings.NewReader(stream), func(evt *WatchEvent) error {
+		events = append(events, evt)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the last event is not terminated by a blank line
+	if len(events) != 2 {
+		t.Fatalf("want 2 events, got %d", len(events))
+	}
+	if events[0].ID != "a.1" || events[0].Event != "added" || string(events[0].Data) != `{"pod":1}` {
+		t.Errorf("unexpected event %+v", events[0])
+	}
+	if events[1].ID != "" || events[1].Event != "reset" || string(events[1].Data) != "line1\nline2" {
+		t.Errorf("unexpected event %+v", events[1])
+	}
+}
+
+func TestWatchResume(t *testing.T) {
+	watchRetryInterval = 10 * time.Millisecond
+	var lastIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastID := r.Header.Get("Last-Event-ID")
+		lastIDs = append(lastIDs, lastID)
+		switch lastID {
+		case "":
+			fmt.Fprint(w, "id: 1\nevent: added\ndata: {}\n\n")
+		case "1":
+			// the server gave up on the connection
+			fmt.Fprint(w, "id: 2\nevent: modified\ndata: {}\n\nevent: failed\ndata: {\"msg\":\"lagged\"}\n\n")
+		default:
+			fmt.Fprint(w, "id: 3\nevent: deleted\ndata: {}\n\n")
+		}
+	}))
+	defer srv.Close()
+
+	r := &regionImpl{APIConf: APIConf{Endpoints: []string{srv.URL}}, Client: http.DefaultClient}
+	done := errors.New("done")
+	var events []string
+	err := r.watch("/pods/watch", func(evt *WatchEvent) error {
+		events = append(events, evt.ID+":"+evt.Event)
+		if evt.Event == "deleted" {
+			return done
+		}
+		return nil
+	})
+	if err != done {
+		t.Fatalf("want the handle error, got %v", err)
+	}
+	if strings.Join(events, ",") != "1:added,2:modified,3:deleted" {
+		t.Errorf("unexpected events %v", events)
+	}
+	if strings.Join(lastIDs, ",") != ",1,2" {
+		t.Errorf("unexpected resume tokens %v", lastIDs)
+	}
+}
+
+func TestWatchNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	r := &regionImpl{APIConf: APIConf{Endpoints: []string{srv.URL}}, Client: http.DefaultClient}
+	if err := r.watch("/pods/watch", func(*WatchEvent) error { return nil }); err == nil {
+		t.Fatal("want an error for a missing watch")
+	}
+}
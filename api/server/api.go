@@ -107,7 +107,7 @@ func (m *Manager) SetMiddleware() {
 	//Gracefully absorb panics and prints the stack trace
 	r.Use(middleware.Recoverer)
 	//request time out
-	r.Use(middleware.Timeout(time.Second * 5))
+	r.Use(apimiddleware.Timeout(time.Second * 5))
 	//simple authz
 	if os.Getenv("TOKEN") != "" {
 		r.Use(apimiddleware.FullToken)
@@ -8,4 +8,20 @@ var (
 	ErrServiceMonitorNotFound = newByMessage(404, 10101, "service monitor not found")
 	//ErrServiceMonitorNameExist -
 	ErrServiceMonitorNameExist = newByMessage(400, 10102, "service monitor name exists")
+	//ErrScheduledScalingPolicyNotFound -
+	ErrScheduledScalingPolicyNotFound = newByMessage(404, 10201, "scheduled scaling policy not found")
+	//ErrScheduledScalingPolicyExist -
+	ErrScheduledScalingPolicyExist = newByMessage(400, 10202, "scheduled scaling policy already exists")
+	//ErrCanaryNotFound -
+	ErrCanaryNotFound = newByMessage(404, 10301, "canary release not found")
+	//ErrCanaryExist -
+	ErrCanaryExist = newByMessage(400, 10302, "canary release already exists")
+	//ErrCanaryNotSupported -
+	ErrCanaryNotSupported = newByMessage(400, 10303, "canary release is not supported by stateful or third-party components")
+	//ErrWebhookNotFound -
+	ErrWebhookNotFound = newByMessage(404, 10401, "webhook not found")
+	//ErrWebhookDisabled -
+	ErrWebhookDisabled = newByMessage(403, 10402, "webhook is disabled")
+	//ErrWebhookSignature -
+	ErrWebhookSignature = newByMessage(401, 10403, "invalid webhook signature or token")
 )
@@ -0,0 +1,11 @@
+package bcode
+
+// tenant 12000~12099
+var (
+	//ErrImagePolicyNotFound -
+	ErrImagePolicyNotFound = newByMessage(404, 12001, "image policy not found")
+	//ErrImagePolicyViolation -
+	ErrImagePolicyViolation = newByMessage(403, 12002, "the image violates the image policy of the tenant")
+	//ErrMeshTLSPolicyNotFound -
+	ErrMeshTLSPolicyNotFound = newByMessage(404, 12003, "mesh tls policy not found")
+)
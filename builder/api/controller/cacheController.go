@@ -0,0 +1,65 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gridworkz/kato/builder/cache"
+	httputil "github.com/gridworkz/kato/util/http"
+)
+
+func cacheFilter(r *http.Request) cache.Filter {
+	return cache.Filter{
+		TenantID:  r.URL.Query().Get("tenant_id"),
+		ServiceID: r.URL.Query().Get("service_id"),
+		Lang:      r.URL.Query().Get("lang"),
+	}
+}
+
+//ListBuildCaches lists the build caches selected by tenant_id, service_id and lang in the query
+func ListBuildCaches(w http.ResponseWriter, r *http.Request) {
+	f := cacheFilter(r)
+	if err := f.Validate(); err != nil {
+		httputil.ReturnError(r, w, 400, err.Error())
+		return
+	}
+	entries, err := cache.GetManager().List(f)
+	if err != nil {
+		httputil.ReturnError(r, w, 500, err.Error())
+		return
+	}
+	httputil.ReturnSuccess(r, w, entries)
+}
+
+//PurgeBuildCaches removes the build caches selected by tenant_id, service_id and lang in the query,
+//the caches in use are skipped
+func PurgeBuildCaches(w http.ResponseWriter, r *http.Request) {
+	f := cacheFilter(r)
+	if err := f.Validate(); err != nil {
+		httputil.ReturnError(r, w, 400, err.Error())
+		return
+	}
+	entries, err := cache.GetManager().Purge(f)
+	if err != nil {
+		httputil.ReturnError(r, w, 500, err.Error())
+		return
+	}
+	httputil.ReturnSuccess(r, w, entries)
+}
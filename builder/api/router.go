@@ -60,6 +60,10 @@ func APIServer() *chi.Mux {
 		r.Route("/event", func(r chi.Router) {
 			r.Get("/", controller.GetEventsByIds)
 		})
+		r.Route("/cache", func(r chi.Router) {
+			r.Get("/", controller.ListBuildCaches)
+			r.Delete("/", controller.PurgeBuildCaches)
+		})
 		r.Route("/health", func(r chi.Router) {
 			r.Get("/", controller.CheckHalth)
 		})
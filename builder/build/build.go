@@ -72,31 +72,33 @@ type Response struct {
 
 //Request build input
 type Request struct {
-	RbdNamespace  string
-	GRDataPVCName string
-	CachePVCName  string
-	CacheMode     string
-	CachePath     string
-	TenantID      string
-	SourceDir     string
-	CacheDir      string
-	TGZDir        string
-	RepositoryURL string
-	Branch        string
-	ServiceAlias  string
-	ServiceID     string
-	DeployVersion string
-	Runtime       string
-	ServerType    string
-	Commit        Commit
-	Lang          code.Lang
-	BuildEnvs     map[string]string
-	Logger        event.Logger
-	DockerClient  *client.Client
-	KubeClient    kubernetes.Interface
-	ExtraHosts    []string
-	HostAlias     []HostAlias
-	Ctx           context.Context
+	RbdNamespace      string
+	GRDataPVCName     string
+	CachePVCName      string
+	CacheMode         string
+	CachePath         string
+	DockerfileBuilder string
+	Platforms         []string
+	TenantID          string
+	SourceDir         string
+	CacheDir          string
+	TGZDir            string
+	RepositoryURL     string
+	Branch            string
+	ServiceAlias      string
+	ServiceID         string
+	DeployVersion     string
+	Runtime           string
+	ServerType        string
+	Commit            Commit
+	Lang              code.Lang
+	BuildEnvs         map[string]string
+	Logger            event.Logger
+	DockerClient      *client.Client
+	KubeClient        kubernetes.Interface
+	ExtraHosts        []string
+	HostAlias         []HostAlias
+	Ctx               context.Context
 }
 
 // HostAlias holds the mapping between IP and hostnames that will be injected as an entry in the
@@ -65,7 +65,7 @@ func (s *slugBuild) Build(re *Request) (*Response, error) {
 	packageName := fmt.Sprintf("%s/%s.tgz", s.tgzDir, re.DeployVersion)
 	//Stops previous build tasks for the same component
 	//If an error occurs, it does not affect the current build task
-	if err := s.stopPreBuildJob(re); err != nil {
+	if err := stopPreBuildJob(re); err != nil {
 		logrus.Errorf("stop pre build job for service %s failure %s", re.ServiceID, err.Error())
 	}
 	if err := s.runBuildJob(re); err != nil {
@@ -146,12 +146,29 @@ func (s *slugBuild) buildRunnerImage(slugPackage string) (string, error) {
 	} else {
 		runbuildOptions.NoCache = false
 	}
+	platforms, err := GetPlatforms(s.re)
+	if err != nil {
+		return "", err
+	}
+	if len(platforms) > 1 {
+		if err := buildMultiPlatformImage(s.re, cacheDir, imageName, runbuildOptions, platforms, 30); err != nil {
+			s.re.Logger.Error(fmt.Sprintf("build image %s of new version failure", imageName), map[string]string{"step": "builder-exector", "status": "failure"})
+			logrus.Errorf("build multi-platform image error: %s", err.Error())
+			return "", err
+		}
+		s.re.Logger.Info("push image of new version success", map[string]string{"step": "builder-exector"})
+		return imageName, nil
+	}
+	if len(platforms) == 1 {
+		runbuildOptions.Platform = platforms[0]
+		runbuildOptions.PullParent = true
+	}
 	// pull image runner
 	if _, err := sources.ImagePull(s.re.DockerClient, builder.RUNNERIMAGENAME, builder.REGISTRYUSER, builder.REGISTRYPASS, s.re.Logger, 30); err != nil {
 		return "", fmt.Errorf("pull image %s: %v", builder.RUNNERIMAGENAME, err)
 	}
 	logrus.Infof("pull image %s successfully.", builder.RUNNERIMAGENAME)
-	_, err := sources.ImageBuild(s.re.DockerClient, cacheDir, runbuildOptions, s.re.Logger, 30)
+	_, err = sources.ImageBuild(s.re.DockerClient, cacheDir, runbuildOptions, s.re.Logger, 30)
 	if err != nil {
 		s.re.Logger.Error(fmt.Sprintf("build image %s of new version failure", imageName), map[string]string{"step": "builder-exector", "status": "failure"})
 		logrus.Errorf("build image error: %s", err.Error())
@@ -248,7 +265,7 @@ func (s *slugBuild) getSourceCodeTarFile(re *Request) (string, error) {
 
 //stopPreBuildJob Stops previous build tasks for the same component
 //The same component retains only one build task to perform
-func (s *slugBuild) stopPreBuildJob(re *Request) error {
+func stopPreBuildJob(re *Request) error {
 	jobList, err := jobc.GetJobController().GetServiceJobs(re.ServiceID)
 	if err != nil {
 		logrus.Errorf("get pre build job for service %s failure ,%s", re.ServiceID, err.Error())
@@ -410,20 +427,7 @@ func (s *slugBuild) runBuildJob(re *Request) error {
 	}
 	podSpec := corev1.PodSpec{RestartPolicy: corev1.RestartPolicyOnFailure} // only support never and onfailure
 	// schedule builder
-	if re.CacheMode == "hostpath" {
-		logrus.Debugf("builder cache mode using hostpath, schedule job into current node")
-		hostIP := os.Getenv("HOST_IP")
-		if hostIP != "" {
-			podSpec.NodeSelector = map[string]string{
-				"kubernetes.io/hostname": hostIP,
-			}
-			podSpec.Tolerations = []corev1.Toleration{
-				{
-					Operator: "Exists",
-				},
-			}
-		}
-	}
+	setHostPathNodeSelector(re, &podSpec)
 	logrus.Debugf("request is: %+v", re)
 
 	volumes, mounts := s.createVolumeAndMount(re, sourceTarFileName)
@@ -483,7 +487,7 @@ func (s *slugBuild) runBuildJob(re *Request) error {
 		podSpec.HostAliases = append(podSpec.HostAliases, corev1.HostAlias{IP: ha.IP, Hostnames: ha.Hostnames})
 	}
 	job.Spec = podSpec
-	s.setImagePullSecretsForPod(&job)
+	setImagePullSecretsForPod(&job)
 	writer := re.Logger.GetWriter("builder", "info")
 	reChan := channels.NewRingChannel(10)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -498,10 +502,10 @@ func (s *slugBuild) runBuildJob(re *Request) error {
 	logrus.Infof("create build job %s for service %s build version %s", job.Name, re.ServiceID, re.DeployVersion)
 	// delete job after complete
 	defer jobc.GetJobController().DeleteJob(job.Name)
-	return s.waitingComplete(re, reChan)
+	return waitingComplete(re, reChan)
 }
 
-func (s *slugBuild) waitingComplete(re *Request, reChan *channels.RingChannel) (err error) {
+func waitingComplete(re *Request, reChan *channels.RingChannel) (err error) {
 	var logComplete = false
 	var jobComplete = false
 	timeout := time.NewTimer(time.Minute * 60)
@@ -541,7 +545,7 @@ func (s *slugBuild) waitingComplete(re *Request, reChan *channels.RingChannel) (
 	}
 }
 
-func (s *slugBuild) setImagePullSecretsForPod(pod *corev1.Pod) {
+func setImagePullSecretsForPod(pod *corev1.Pod) {
 	imagePullSecretName := os.Getenv("IMAGE_PULL_SECRET")
 	if imagePullSecretName == "" {
 		return
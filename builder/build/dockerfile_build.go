@@ -39,6 +39,9 @@ type dockerfileBuild struct {
 }
 
 func (d *dockerfileBuild) Build(re *Request) (*Response, error) {
+	if re.DockerfileBuilder == DockerfileBuilderJob {
+		return (&dockerfileJobBuild{}).Build(re)
+	}
 	filepath := path.Join(re.SourceDir, "Dockerfile")
 	re.Logger.Info("Start parse Dockerfile", map[string]string{"step": "builder-exector"})
 	_, err := sources.ParseFile(filepath)
@@ -65,6 +68,27 @@ func (d *dockerfileBuild) Build(re *Request) (*Response, error) {
 	if timeout < 10 {
 		timeout = 60
 	}
+	platforms, err := GetPlatforms(re)
+	if err != nil {
+		re.Logger.Error(fmt.Sprintf("Invalid build platforms: %s", err.Error()), map[string]string{"step": "builder-exector", "status": "failure"})
+		return nil, err
+	}
+	if len(platforms) > 1 {
+		if err := buildMultiPlatformImage(re, re.SourceDir, buildImageName, buildOptions, platforms, timeout); err != nil {
+			re.Logger.Error(fmt.Sprintf("build image %s failure", buildImageName), map[string]string{"step": "builder-exector", "status": "failure"})
+			logrus.Errorf("build multi-platform image error: %s", err.Error())
+			return nil, err
+		}
+		re.Logger.Info("The image is pushed to the warehouse successfully", map[string]string{"step": "builder-exector"})
+		return &Response{
+			MediumPath: buildImageName,
+			MediumType: ImageMediumType,
+		}, nil
+	}
+	if len(platforms) == 1 {
+		buildOptions.Platform = platforms[0]
+		buildOptions.PullParent = true
+	}
 	_, err = sources.ImageBuild(re.DockerClient, re.SourceDir, buildOptions, re.Logger, timeout)
 	if err != nil {
 		re.Logger.Error(fmt.Sprintf("build image %s failure", buildImageName), map[string]string{"step": "builder-exector", "status": "failure"})
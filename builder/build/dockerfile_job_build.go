@@ -0,0 +1,332 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package build
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/eapache/channels"
+	"github.com/gridworkz/kato/builder"
+	jobc "github.com/gridworkz/kato/builder/job"
+	"github.com/gridworkz/kato/builder/sources"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//DockerfileBuilderDocker builds the dockerfile with the docker daemon of the current node
+const DockerfileBuilderDocker = "docker"
+
+//DockerfileBuilderJob builds the dockerfile in a kubernetes job with kaniko, no docker daemon is required
+const DockerfileBuilderJob = "job"
+
+const kanikoWorkspace = "/workspace"
+
+// the CA certificate of the registry is mounted into the kaniko job from the key of the registry cert secret
+const (
+	registryCertKey = "cert"
+	registryCertDir = "/kaniko/registry-certs"
+)
+
+type dockerfileJobBuild struct {
+}
+
+func (d *dockerfileJobBuild) Build(re *Request) (*Response, error) {
+	filepath := path.Join(re.SourceDir, "Dockerfile")
+	re.Logger.Info("Start parse Dockerfile", map[string]string{"step": "builder-exector"})
+	if _, err := sources.ParseFile(filepath); err != nil {
+		logrus.Error("parse dockerfile error.", err.Error())
+		re.Logger.Error("Parse dockerfile error", map[string]string{"step": "builder-exector"})
+		return nil, err
+	}
+	buildImageName := CreateImageName(re.ServiceID, re.DeployVersion)
+	if err := BuildDockerfileByJob(re, buildImageName); err != nil {
+		re.Logger.Error(fmt.Sprintf("build image %s failure", buildImageName), map[string]string{"step": "builder-exector", "status": "failure"})
+		logrus.Errorf("build image %s by job error: %s", buildImageName, err.Error())
+		return nil, err
+	}
+	re.Logger.Info("The image is built and pushed to the warehouse successfully", map[string]string{"step": "builder-exector"})
+	return &Response{
+		MediumPath: buildImageName,
+		MediumType: ImageMediumType,
+	}, nil
+}
+
+//BuildDockerfileByJob builds the Dockerfile in re.SourceDir with kaniko jobs and pushes the image to the registry.
+//The source dir must be located in the cache volume, which is mounted into the job as the build context.
+//If several platforms are required, the image of each platform is built in the node of its architecture,
+//then imageName is pushed as the manifest list of them.
+func BuildDockerfileByJob(re *Request, imageName string) error {
+	platforms, err := GetPlatforms(re)
+	if err != nil {
+		return err
+	}
+	if err := stopPreBuildJob(re); err != nil {
+		logrus.Errorf("stop pre build job for service %s failure %s", re.ServiceID, err.Error())
+	}
+	name := fmt.Sprintf("%s-%s", re.ServiceID, re.DeployVersion)
+	if len(platforms) <= 1 {
+		var platform string
+		if len(platforms) == 1 {
+			platform = platforms[0]
+		}
+		return runKanikoJob(re, name, imageName, platform)
+	}
+	platformImages := make(map[string]string, len(platforms))
+	for _, platform := range platforms {
+		platformImage := PlatformImageName(imageName, platform)
+		re.Logger.Info(fmt.Sprintf("Start build image for platform %s", platform), map[string]string{"step": "builder-exector"})
+		if err := runKanikoJob(re, name+"-"+strings.Replace(platform, "/", "-", -1), platformImage, platform); err != nil {
+			return fmt.Errorf("build image for platform %s: %v", platform, err)
+		}
+		platformImages[platform] = platformImage
+	}
+	return createManifestList(re, imageName, platformImages)
+}
+
+func runKanikoJob(re *Request, name, imageName, platform string) error {
+	job := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: re.RbdNamespace,
+			Labels: map[string]string{
+				"service": re.ServiceID,
+				"job":     "dockerfilebuild",
+			},
+		},
+	}
+	podSpec := corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}
+	setHostPathNodeSelector(re, &podSpec)
+	container := corev1.Container{
+		Name:  name,
+		Image: builder.KANIKOIMAGENAME,
+		Args:  kanikoArgs(re, imageName),
+	}
+	if platform != "" {
+		arch := platformArch(platform)
+		// kaniko can not emulate other architectures, so the job runs in the node of the architecture
+		if re.CacheMode == "hostpath" && arch != runtime.GOARCH {
+			return fmt.Errorf("platform %s is not supported when cache mode is hostpath, the build context is only in the current node", platform)
+		}
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = make(map[string]string)
+		}
+		podSpec.NodeSelector["kubernetes.io/arch"] = arch
+		container.Args = append(container.Args, "--custom-platform="+platform)
+	}
+	sourceSubPath := strings.TrimPrefix(re.SourceDir, "/cache/")
+	if re.CacheMode == "hostpath" {
+		hostPathType := corev1.HostPathDirectory
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "source",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: path.Join(re.CachePath, sourceSubPath),
+					Type: &hostPathType,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "source",
+			MountPath: kanikoWorkspace,
+			ReadOnly:  true,
+		})
+	} else {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: re.CachePVCName,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "source",
+			MountPath: kanikoWorkspace,
+			SubPath:   sourceSubPath,
+			ReadOnly:  true,
+		})
+	}
+	// kaniko reads the registry credentials from /kaniko/.docker/config.json
+	if builder.REGISTRYUSER != "" {
+		secret, err := createRegistrySecret(re, name+"-registry")
+		if err != nil {
+			return fmt.Errorf("create registry secret for build job: %v", err)
+		}
+		defer deleteRegistrySecret(re, secret.Name)
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "registry",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+					Items:      []corev1.KeyToPath{{Key: corev1.DockerConfigJsonKey, Path: "config.json"}},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "registry",
+			MountPath: "/kaniko/.docker",
+			ReadOnly:  true,
+		})
+	}
+	// the registry is usually signed by the self-signed CA of the cluster, which kaniko does not trust
+	setRegistryCert(re, &podSpec, &container)
+	podSpec.Containers = append(podSpec.Containers, container)
+	for _, ha := range re.HostAlias {
+		podSpec.HostAliases = append(podSpec.HostAliases, corev1.HostAlias{IP: ha.IP, Hostnames: ha.Hostnames})
+	}
+	job.Spec = podSpec
+	setImagePullSecretsForPod(&job)
+	re.Logger.Info("Start build image from dockerfile in job", map[string]string{"step": "builder-exector"})
+	writer := re.Logger.GetWriter("builder", "info")
+	reChan := channels.NewRingChannel(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logrus.Debugf("create job[name: %s; namespace: %s]", job.Name, job.Namespace)
+	if err := jobc.GetJobController().ExecJob(ctx, &job, writer, reChan); err != nil {
+		logrus.Errorf("create new job:%s failed: %s", name, err.Error())
+		return err
+	}
+	logrus.Infof("create dockerfile build job %s for service %s build version %s", job.Name, re.ServiceID, re.DeployVersion)
+	// delete job after complete
+	defer jobc.GetJobController().DeleteJob(job.Name)
+	return waitingComplete(re, reChan)
+}
+
+//kanikoArgs returns the executor args, build args are sorted so that the job spec is stable
+func kanikoArgs(re *Request, imageName string) []string {
+	args := []string{
+		"--dockerfile=" + path.Join(kanikoWorkspace, "Dockerfile"),
+		"--context=dir://" + kanikoWorkspace,
+		"--destination=" + imageName,
+	}
+	buildArgs := GetARGs(re.BuildEnvs)
+	var keys []string
+	for k := range buildArgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, fmt.Sprintf("--build-arg=%s=%s", k, *buildArgs[k]))
+	}
+	if _, ok := re.BuildEnvs["NO_CACHE"]; ok {
+		args = append(args, "--cache=false")
+	} else {
+		args = append(args, "--cache=true")
+	}
+	if builder.REGISTRYINSECURE {
+		args = append(args, "--skip-tls-verify-registry="+strings.Split(builder.REGISTRYDOMAIN, "/")[0])
+	}
+	return args
+}
+
+//setRegistryCert mounts the CA certificate of the registry into the kaniko job and makes kaniko trust it,
+//nothing is done if the registry cert secret is not configured or does not hold the certificate.
+func setRegistryCert(re *Request, podSpec *corev1.PodSpec, container *corev1.Container) {
+	if builder.REGISTRYCERTSECRET == "" {
+		return
+	}
+	secret, err := re.KubeClient.CoreV1().Secrets(re.RbdNamespace).Get(builder.REGISTRYCERTSECRET, metav1.GetOptions{})
+	if err != nil {
+		logrus.Warningf("get registry cert secret %s/%s: %v", re.RbdNamespace, builder.REGISTRYCERTSECRET, err)
+		return
+	}
+	if _, ok := secret.Data[registryCertKey]; !ok {
+		logrus.Warningf("registry cert secret %s/%s do not contain cert info", re.RbdNamespace, builder.REGISTRYCERTSECRET)
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "registry-cert",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secret.Name,
+				Items:      []corev1.KeyToPath{{Key: registryCertKey, Path: "ca.crt"}},
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "registry-cert",
+		MountPath: registryCertDir,
+		ReadOnly:  true,
+	})
+	registry := strings.Split(builder.REGISTRYDOMAIN, "/")[0]
+	container.Args = append(container.Args, fmt.Sprintf("--registry-certificate=%s=%s", registry, path.Join(registryCertDir, "ca.crt")))
+}
+
+func createRegistrySecret(re *Request, name string) (*corev1.Secret, error) {
+	registry := strings.Split(builder.REGISTRYDOMAIN, "/")[0]
+	auth := base64.StdEncoding.EncodeToString([]byte(builder.REGISTRYUSER + ":" + builder.REGISTRYPASS))
+	config, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{"auth": auth},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: re.RbdNamespace,
+			Labels: map[string]string{
+				"service": re.ServiceID,
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: config},
+	}
+	created, err := re.KubeClient.CoreV1().Secrets(re.RbdNamespace).Create(secret)
+	if err != nil && k8sErrors.IsAlreadyExists(err) {
+		return re.KubeClient.CoreV1().Secrets(re.RbdNamespace).Update(secret)
+	}
+	return created, err
+}
+
+func deleteRegistrySecret(re *Request, name string) {
+	if err := re.KubeClient.CoreV1().Secrets(re.RbdNamespace).Delete(name, &metav1.DeleteOptions{}); err != nil && !k8sErrors.IsNotFound(err) {
+		logrus.Warningf("delete registry secret %s: %v", name, err)
+	}
+}
+
+//setHostPathNodeSelector schedules the job into the current node when the cache is a host path
+func setHostPathNodeSelector(re *Request, podSpec *corev1.PodSpec) {
+	if re.CacheMode != "hostpath" {
+		return
+	}
+	logrus.Debugf("builder cache mode using hostpath, schedule job into current node")
+	hostIP := os.Getenv("HOST_IP")
+	if hostIP != "" {
+		podSpec.NodeSelector = map[string]string{
+			"kubernetes.io/hostname": hostIP,
+		}
+		podSpec.Tolerations = []corev1.Toleration{
+			{
+				Operator: "Exists",
+			},
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package build
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gridworkz/kato/builder"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKanikoArgs(t *testing.T) {
+	re := &Request{
+		BuildEnvs: map[string]string{
+			"ARG_VERSION": "1.0",
+			"ARG_NAME":    "app-${VERSION}",
+			"BUILD_ENV":   "ignored",
+		},
+	}
+	want := []string{
+		"--dockerfile=/workspace/Dockerfile",
+		"--context=dir:///workspace",
+		"--destination=goodrain.me/app:1",
+		"--build-arg=NAME=app-1.0",
+		"--build-arg=VERSION=1.0",
+		"--cache=true",
+	}
+	if got := kanikoArgs(re, "goodrain.me/app:1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, but returned %v", want, got)
+	}
+
+	re.BuildEnvs["NO_CACHE"] = "true"
+	got := kanikoArgs(re, "goodrain.me/app:1")
+	if last := got[len(got)-1]; last != "--cache=false" {
+		t.Errorf("expected --cache=false when NO_CACHE is set, but returned %s", last)
+	}
+}
+
+func TestKanikoArgsInsecureRegistry(t *testing.T) {
+	builder.REGISTRYINSECURE = true
+	defer func() { builder.REGISTRYINSECURE = false }()
+	got := kanikoArgs(&Request{}, "goodrain.me/app:1")
+	if last := got[len(got)-1]; last != "--skip-tls-verify-registry="+builder.REGISTRYDOMAIN {
+		t.Errorf("expected the registry to skip tls verify, but returned %s", last)
+	}
+}
+
+func TestSetRegistryCert(t *testing.T) {
+	builder.REGISTRYCERTSECRET = "rbd-hub-credentials"
+	defer func() { builder.REGISTRYCERTSECRET = "" }()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rbd-hub-credentials", Namespace: "rbd-system"},
+		Data:       map[string][]byte{"cert": []byte("ca")},
+	}
+	re := &Request{RbdNamespace: "rbd-system", KubeClient: fake.NewSimpleClientset(secret)}
+	var podSpec corev1.PodSpec
+	var container corev1.Container
+	setRegistryCert(re, &podSpec, &container)
+	if len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Secret == nil || podSpec.Volumes[0].Secret.SecretName != "rbd-hub-credentials" {
+		t.Fatalf("expected the registry cert secret to be mounted, but got volumes %+v", podSpec.Volumes)
+	}
+	if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != registryCertDir {
+		t.Errorf("unexpected volume mounts %+v", container.VolumeMounts)
+	}
+	want := []string{"--registry-certificate=" + builder.REGISTRYDOMAIN + "=/kaniko/registry-certs/ca.crt"}
+	if !reflect.DeepEqual(container.Args, want) {
+		t.Errorf("expected %v, but returned %v", want, container.Args)
+	}
+
+	// the secret without the cert is not mounted, or the job can not start
+	secret.Data = map[string][]byte{"other": []byte("ca")}
+	re.KubeClient = fake.NewSimpleClientset(secret)
+	podSpec, container = corev1.PodSpec{}, corev1.Container{}
+	setRegistryCert(re, &podSpec, &container)
+	if len(podSpec.Volumes) != 0 || len(container.Args) != 0 {
+		t.Errorf("expected nothing to be mounted, but got volumes %+v args %v", podSpec.Volumes, container.Args)
+	}
+}
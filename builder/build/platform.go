@@ -0,0 +1,99 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/gridworkz/kato/builder"
+	"github.com/gridworkz/kato/builder/sources"
+	"github.com/sirupsen/logrus"
+)
+
+//GetPlatforms returns the platforms that the image is built for, such as linux/amd64 and linux/arm64.
+//BUILD_PLATFORMS of the component takes precedence over the platforms of the cluster,
+//no platform means the image is built for the platform of the builder.
+func GetPlatforms(re *Request) ([]string, error) {
+	platforms := re.Platforms
+	if value := strings.TrimSpace(re.BuildEnvs["BUILD_PLATFORMS"]); value != "" {
+		platforms = strings.Split(value, ",")
+	}
+	var result []string
+	exists := make(map[string]bool)
+	for _, platform := range platforms {
+		platform = strings.ToLower(strings.TrimSpace(platform))
+		if platform == "" || exists[platform] {
+			continue
+		}
+		if _, err := sources.ParsePlatform(platform); err != nil {
+			return nil, err
+		}
+		exists[platform] = true
+		result = append(result, platform)
+	}
+	return result, nil
+}
+
+//PlatformImageName returns the name of the image built for the platform, which is referenced by the manifest list
+func PlatformImageName(imageName, platform string) string {
+	return fmt.Sprintf("%s-%s", imageName, strings.Replace(platform, "/", "-", -1))
+}
+
+//platformArch returns the architecture of the platform, such as arm64 of linux/arm64/v8
+func platformArch(platform string) string {
+	spec, err := sources.ParsePlatform(platform)
+	if err != nil {
+		return ""
+	}
+	return spec.Architecture
+}
+
+//buildMultiPlatformImage builds and pushes the image of every platform with docker daemon,
+//then pushes the manifest list imageName that references them.
+//Building the image for other architectures needs the emulators(binfmt_misc) to be registered in the node.
+func buildMultiPlatformImage(re *Request, contextDir, imageName string, options types.ImageBuildOptions, platforms []string, timeout int) error {
+	platformImages := make(map[string]string, len(platforms))
+	for _, platform := range platforms {
+		platformImage := PlatformImageName(imageName, platform)
+		options.Tags = []string{platformImage}
+		options.Platform = platform
+		options.PullParent = true
+		re.Logger.Info(fmt.Sprintf("Start build image for platform %s", platform), map[string]string{"step": "builder-exector"})
+		if _, err := sources.ImageBuild(re.DockerClient, contextDir, options, re.Logger, timeout); err != nil {
+			return fmt.Errorf("build image for platform %s: %v", platform, err)
+		}
+		if err := sources.ImagePush(re.DockerClient, platformImage, builder.REGISTRYUSER, builder.REGISTRYPASS, re.Logger, 20); err != nil {
+			return fmt.Errorf("push image %s: %v", platformImage, err)
+		}
+		if err := sources.ImageRemove(re.DockerClient, platformImage); err != nil {
+			logrus.Errorf("remove image %s failure %s", platformImage, err.Error())
+		}
+		platformImages[platform] = platformImage
+	}
+	return createManifestList(re, imageName, platformImages)
+}
+
+func createManifestList(re *Request, imageName string, platformImages map[string]string) error {
+	re.Logger.Info(fmt.Sprintf("Start push manifest list %s", imageName), map[string]string{"step": "builder-exector"})
+	if err := sources.CreateManifestList(imageName, platformImages, builder.REGISTRYUSER, builder.REGISTRYPASS); err != nil {
+		return fmt.Errorf("create manifest list %s: %v", imageName, err)
+	}
+	return nil
+}
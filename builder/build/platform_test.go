@@ -0,0 +1,60 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package build
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetPlatforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		platforms []string
+		envs      map[string]string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "default", want: nil},
+		{name: "cluster", platforms: []string{"linux/amd64", "linux/arm64"}, want: []string{"linux/amd64", "linux/arm64"}},
+		{
+			name:      "component takes precedence",
+			platforms: []string{"linux/amd64"},
+			envs:      map[string]string{"BUILD_PLATFORMS": " linux/ARM64, linux/amd64,linux/arm64,"},
+			want:      []string{"linux/arm64", "linux/amd64"},
+		},
+		{name: "invalid", envs: map[string]string{"BUILD_PLATFORMS": "arm64"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GetPlatforms(&Request{Platforms: tc.platforms, BuildEnvs: tc.envs})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, but returned %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPlatformImageName(t *testing.T) {
+	if got := PlatformImageName("goodrain.me/app:1", "linux/arm64/v8"); got != "goodrain.me/app:1-linux-arm64-v8" {
+		t.Errorf("unexpected platform image name %s", got)
+	}
+}
@@ -0,0 +1,489 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//DefaultRoot is the directory of the build caches, the cache of a component is <root>/<tenant id>/cache/<service id>/<lang>
+const DefaultRoot = "/cache/build"
+
+// the ids of the tenants and the components are uuids without dashes, see util.NewUUID
+var idPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// the characters not allowed in the cache directory of a language
+var langReplacer = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+//ValidID checks the id of the tenant or the component, the ids are joined into the paths of the caches
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+func validIDs(tenantID, serviceID string) error {
+	if !ValidID(tenantID) {
+		return fmt.Errorf("invalid tenant id %q", tenantID)
+	}
+	if !ValidID(serviceID) {
+		return fmt.Errorf("invalid service id %q", serviceID)
+	}
+	return nil
+}
+
+//LangDir returns the name of the cache directory of the language, such as 'Java-maven'
+func LangDir(lang string) string {
+	dir := langReplacer.ReplaceAllString(lang, "_")
+	if dir == "" || dir == "." || dir == ".." {
+		return "default"
+	}
+	return dir
+}
+
+//Entry is the build cache of a component in a language. The cache is shared by all the branches of the component,
+//the languages are cached separately, so that switching the language of the component does not mix the caches.
+type Entry struct {
+	TenantID  string    `json:"tenant_id"`
+	ServiceID string    `json:"service_id"`
+	Lang      string    `json:"lang"`
+	Branches  []string  `json:"branches"`
+	Size      int64     `json:"size"`
+	Hits      int       `json:"hits"`
+	Misses    int       `json:"misses"`
+	LastUsed  time.Time `json:"last_used"`
+	InUse     bool      `json:"in_use"`
+	// the cache of the component is in the layout without languages
+	legacy bool
+}
+
+//Filter selects the build caches, the empty fields match all.
+type Filter struct {
+	TenantID  string
+	ServiceID string
+	Lang      string
+}
+
+//Validate checks the ids of the filter
+func (f Filter) Validate() error {
+	if f.TenantID != "" && !ValidID(f.TenantID) {
+		return fmt.Errorf("invalid tenant id %q", f.TenantID)
+	}
+	if f.ServiceID != "" && !ValidID(f.ServiceID) {
+		return fmt.Errorf("invalid service id %q", f.ServiceID)
+	}
+	return nil
+}
+
+func (f Filter) match(e *Entry) bool {
+	return (f.TenantID == "" || f.TenantID == e.TenantID) &&
+		(f.ServiceID == "" || f.ServiceID == e.ServiceID) &&
+		(f.Lang == "" || strings.EqualFold(f.Lang, e.Lang))
+}
+
+//Manager manages the build caches of the components. The total size of the caches of a component and of a tenant
+//are limited by the quotas, the least recently used caches are evicted if exceeded. The cache just built is evicted
+//only if it exceeds the quota alone, the build is not cached in that case.
+type Manager struct {
+	root           string
+	componentQuota int64
+	tenantQuota    int64
+	lock           sync.Mutex
+	inUse          map[string]int
+}
+
+var defaultManager *Manager
+
+//CreateManager creates the default cache manager, the quotas are in bytes and 0 means unlimited.
+func CreateManager(root string, componentQuota, tenantQuota int64) *Manager {
+	defaultManager = NewManager(root, componentQuota, tenantQuota)
+	return defaultManager
+}
+
+//GetManager returns the default cache manager
+func GetManager() *Manager {
+	return defaultManager
+}
+
+//NewManager creates a cache manager
+func NewManager(root string, componentQuota, tenantQuota int64) *Manager {
+	return &Manager{
+		root:           root,
+		componentQuota: componentQuota,
+		tenantQuota:    tenantQuota,
+		inUse:          make(map[string]int),
+	}
+}
+
+//Dir returns the cache directory of the component in the language
+func (m *Manager) Dir(tenantID, serviceID, lang string) string {
+	return path.Join(m.serviceDir(tenantID, serviceID), LangDir(lang))
+}
+
+func (m *Manager) serviceDir(tenantID, serviceID string) string {
+	return path.Join(m.root, tenantID, "cache", serviceID)
+}
+
+// the metadata is saved beside the cache directory, so that the builds can not change it.
+func (m *Manager) metaFile(tenantID, serviceID, lang string) string {
+	return m.Dir(tenantID, serviceID, lang) + ".json"
+}
+
+// the metadata of the cache in the layout without languages
+func (m *Manager) legacyMetaFile(tenantID, serviceID string) string {
+	return m.serviceDir(tenantID, serviceID) + ".json"
+}
+
+//Acquire marks the cache of the component in the language in use until it is released, and reports whether the cache hits.
+//The cache directory is created, the cache in the layout without languages is removed.
+func (m *Manager) Acquire(tenantID, serviceID, lang, branch string) (*Entry, bool, error) {
+	if err := validIDs(tenantID, serviceID); err != nil {
+		return nil, false, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.isLegacy(tenantID, serviceID) {
+		logrus.Infof("remove the build cache of service %s without languages", serviceID)
+		if err := m.remove(m.loadLegacy(tenantID, serviceID)); err != nil {
+			return nil, false, err
+		}
+	}
+	e := m.load(tenantID, serviceID, lang)
+	dir := m.Dir(tenantID, serviceID, lang)
+	hit := !isEmptyDir(dir)
+	if hit {
+		e.Hits++
+	} else {
+		e.Misses++
+	}
+	if branch != "" && !contains(e.Branches, branch) {
+		e.Branches = append(e.Branches, branch)
+	}
+	e.LastUsed = time.Now()
+	m.inUse[key(tenantID, serviceID, lang)]++
+	e.InUse = true
+	if err := m.save(e); err != nil {
+		return nil, false, err
+	}
+	return e, hit, os.MkdirAll(dir, 0755)
+}
+
+//Release updates the size of the cache after the build, then evicts the caches exceeding the quotas.
+//It reports whether the cache of the component in the language is evicted.
+func (m *Manager) Release(tenantID, serviceID, lang string) (*Entry, bool, error) {
+	if err := validIDs(tenantID, serviceID); err != nil {
+		return nil, false, err
+	}
+	size := dirSize(m.Dir(tenantID, serviceID, lang))
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	k := key(tenantID, serviceID, lang)
+	if m.inUse[k]--; m.inUse[k] <= 0 {
+		delete(m.inUse, k)
+	}
+	e := m.load(tenantID, serviceID, lang)
+	e.Size = size
+	e.LastUsed = time.Now()
+	if err := m.save(e); err != nil {
+		return nil, false, err
+	}
+	if m.componentQuota > 0 {
+		evicted, err := m.evict(Filter{TenantID: tenantID, ServiceID: serviceID}, m.componentQuota, e)
+		if err != nil || evicted {
+			return e, evicted, err
+		}
+	}
+	if m.tenantQuota > 0 {
+		evicted, err := m.evict(Filter{TenantID: tenantID}, m.tenantQuota, e)
+		return e, evicted, err
+	}
+	return e, false, nil
+}
+
+//List lists the build caches, the least recently used first.
+func (m *Manager) List(f Filter) ([]*Entry, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.list(f)
+}
+
+//Purge removes the build caches, except the ones in use.
+func (m *Manager) Purge(f Filter) ([]*Entry, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	entries, err := m.list(f)
+	if err != nil {
+		return nil, err
+	}
+	var purged []*Entry
+	for _, e := range entries {
+		if e.InUse {
+			logrus.Infof("build cache of service %s in %s is in use, skip purging it", e.ServiceID, e.Lang)
+			continue
+		}
+		if err := m.remove(e); err != nil {
+			return purged, err
+		}
+		purged = append(purged, e)
+	}
+	return purged, nil
+}
+
+//evict removes the least recently used caches selected by the filter until the total size is within the quota.
+//The current cache is removed last, only if the quota is still exceeded without the others. It reports whether
+//the current cache is evicted.
+func (m *Manager) evict(f Filter, quota int64, current *Entry) (bool, error) {
+	entries, err := m.list(f)
+	if err != nil {
+		return false, err
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+	}
+	scope := "tenant " + f.TenantID
+	if f.ServiceID != "" {
+		scope = "service " + f.ServiceID
+	}
+	isCurrent := func(e *Entry) bool {
+		return e.TenantID == current.TenantID && e.ServiceID == current.ServiceID && !e.legacy && LangDir(e.Lang) == LangDir(current.Lang)
+	}
+	for _, e := range entries {
+		if total <= quota {
+			return false, nil
+		}
+		if e.InUse || isCurrent(e) {
+			continue
+		}
+		logrus.Infof("build caches of %s are %d bytes, exceed the quota %d, evict the cache of service %s in %s", scope, total, quota, e.ServiceID, e.Lang)
+		if err := m.remove(e); err != nil {
+			return false, err
+		}
+		total -= e.Size
+	}
+	if total <= quota || current.InUse {
+		return false, nil
+	}
+	logrus.Infof("build cache of service %s in %s is %d bytes, exceeds the quota %d alone, skip caching the build", current.ServiceID, current.Lang, current.Size, quota)
+	return true, m.remove(current)
+}
+
+func (m *Manager) list(f Filter) ([]*Entry, error) {
+	var tenants []string
+	if f.TenantID != "" {
+		tenants = []string{f.TenantID}
+	} else {
+		infos, err := ioutil.ReadDir(m.root)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, info := range infos {
+			if info.IsDir() && ValidID(info.Name()) {
+				tenants = append(tenants, info.Name())
+			}
+		}
+	}
+	var entries []*Entry
+	for _, tenantID := range tenants {
+		infos, err := ioutil.ReadDir(path.Join(m.root, tenantID, "cache"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, info := range infos {
+			if !info.IsDir() || !ValidID(info.Name()) || (f.ServiceID != "" && f.ServiceID != info.Name()) {
+				continue
+			}
+			serviceEntries, err := m.listService(tenantID, info)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range serviceEntries {
+				if f.match(e) {
+					entries = append(entries, e)
+				}
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+	return entries, nil
+}
+
+//listService lists the caches of the component in the languages
+func (m *Manager) listService(tenantID string, service os.FileInfo) ([]*Entry, error) {
+	serviceID := service.Name()
+	if m.isLegacy(tenantID, serviceID) {
+		e := m.loadLegacy(tenantID, serviceID)
+		if e.LastUsed.IsZero() {
+			// the cache is created before the cache manager
+			e.LastUsed = service.ModTime()
+		}
+		return []*Entry{e}, nil
+	}
+	infos, err := ioutil.ReadDir(m.serviceDir(tenantID, serviceID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entries []*Entry
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		e := m.load(tenantID, serviceID, info.Name())
+		if e.LastUsed.IsZero() {
+			e.Size = dirSize(path.Join(m.serviceDir(tenantID, serviceID), info.Name()))
+			e.LastUsed = info.ModTime()
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+//isLegacy checks if the cache of the component is in the layout without languages, which has the metadata beside
+//the directory of the component, or no metadata at all if it is created before the cache manager.
+func (m *Manager) isLegacy(tenantID, serviceID string) bool {
+	if _, err := os.Stat(m.legacyMetaFile(tenantID, serviceID)); err == nil {
+		return true
+	}
+	infos, err := ioutil.ReadDir(m.serviceDir(tenantID, serviceID))
+	if err != nil || len(infos) == 0 {
+		return false
+	}
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+			continue
+		}
+		var e Entry
+		data, err := ioutil.ReadFile(path.Join(m.serviceDir(tenantID, serviceID), info.Name()))
+		if err == nil && json.Unmarshal(data, &e) == nil && e.ServiceID == serviceID {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Manager) load(tenantID, serviceID, lang string) *Entry {
+	e := &Entry{Lang: lang}
+	if data, err := ioutil.ReadFile(m.metaFile(tenantID, serviceID, lang)); err == nil {
+		if err := json.Unmarshal(data, e); err != nil {
+			logrus.Warningf("unmarshal build cache metadata of service %s: %v", serviceID, err)
+		}
+	}
+	e.TenantID, e.ServiceID = tenantID, serviceID
+	e.InUse = m.inUse[key(tenantID, serviceID, e.Lang)] > 0
+	return e
+}
+
+func (m *Manager) loadLegacy(tenantID, serviceID string) *Entry {
+	e := &Entry{}
+	if data, err := ioutil.ReadFile(m.legacyMetaFile(tenantID, serviceID)); err == nil {
+		if err := json.Unmarshal(data, e); err != nil {
+			logrus.Warningf("unmarshal build cache metadata of service %s: %v", serviceID, err)
+		}
+	}
+	e.TenantID, e.ServiceID, e.InUse, e.legacy = tenantID, serviceID, false, true
+	if e.Size == 0 {
+		e.Size = dirSize(m.serviceDir(tenantID, serviceID))
+	}
+	return e
+}
+
+func (m *Manager) save(e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.serviceDir(e.TenantID, e.ServiceID), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(m.metaFile(e.TenantID, e.ServiceID, e.Lang), data, 0644)
+}
+
+func (m *Manager) remove(e *Entry) error {
+	dir, meta := m.Dir(e.TenantID, e.ServiceID, e.Lang), m.metaFile(e.TenantID, e.ServiceID, e.Lang)
+	if e.legacy {
+		dir, meta = m.serviceDir(e.TenantID, e.ServiceID), m.legacyMetaFile(e.TenantID, e.ServiceID)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.Remove(meta); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// the directory of the component is removed with the last language
+	os.Remove(m.serviceDir(e.TenantID, e.ServiceID))
+	return nil
+}
+
+func key(tenantID, serviceID, lang string) string {
+	return tenantID + "/" + serviceID + "/" + LangDir(lang)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyDir(dir string) bool {
+	f, err := os.Open(dir)
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+	names, _ := f.Readdirnames(1)
+	return len(names) == 0
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
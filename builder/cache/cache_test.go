@@ -0,0 +1,228 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the ids of the tenants and the components in the tests
+const (
+	t1 = "a0000000000000000000000000000001"
+	t2 = "a0000000000000000000000000000002"
+	s1 = "b0000000000000000000000000000001"
+	s2 = "b0000000000000000000000000000002"
+	s3 = "b0000000000000000000000000000003"
+)
+
+func newTestManager(t *testing.T, componentQuota, tenantQuota int64) *Manager {
+	root, err := ioutil.TempDir("", "build-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(root, componentQuota, tenantQuota)
+}
+
+func writeCache(t *testing.T, dir string, size int) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(dir, "data"), make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func build(t *testing.T, m *Manager, tenantID, serviceID string, size int) (hit, evicted bool) {
+	return buildLang(t, m, tenantID, serviceID, "Java-maven", size)
+}
+
+func buildLang(t *testing.T, m *Manager, tenantID, serviceID, lang string, size int) (hit, evicted bool) {
+	_, hit, err := m.Acquire(tenantID, serviceID, lang, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeCache(t, m.Dir(tenantID, serviceID, lang), size)
+	_, evicted, err = m.Release(tenantID, serviceID, lang)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hit, evicted
+}
+
+func TestHitAndMiss(t *testing.T) {
+	m := newTestManager(t, 0, 0)
+	defer os.RemoveAll(m.root)
+	if hit, _ := build(t, m, t1, s1, 10); hit {
+		t.Error("want miss for the first build")
+	}
+	if hit, _ := build(t, m, t1, s1, 10); !hit {
+		t.Error("want hit for the second build")
+	}
+	entries, err := m.List(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("want 1 cache, but got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Hits != 1 || e.Misses != 1 || e.Size != 10 || e.Lang != "Java-maven" || e.InUse {
+		t.Errorf("unexpected cache %+v", e)
+	}
+}
+
+func TestComponentQuota(t *testing.T) {
+	m := newTestManager(t, 100, 0)
+	defer os.RemoveAll(m.root)
+	if _, evicted := build(t, m, t1, s1, 50); evicted {
+		t.Error("the cache within the quota should not be evicted")
+	}
+	time.Sleep(10 * time.Millisecond)
+	// the older cache in another language is evicted first
+	if _, evicted := buildLang(t, m, t1, s1, "Node.js", 80); evicted {
+		t.Error("the cache within the quota alone should not be evicted")
+	}
+	if _, err := os.Stat(m.Dir(t1, s1, "Java-maven")); !os.IsNotExist(err) {
+		t.Errorf("the cache in the older language should be removed, %v", err)
+	}
+	if hit, evicted := buildLang(t, m, t1, s1, "Node.js", 80); !hit || evicted {
+		t.Errorf("the cache within the quota should be kept, hit %v evicted %v", hit, evicted)
+	}
+	// the build is not cached if the cache exceeds the quota alone
+	if _, evicted := build(t, m, t1, s1, 200); !evicted {
+		t.Error("the cache exceeding the quota should be evicted")
+	}
+	if _, err := os.Stat(m.Dir(t1, s1, "Java-maven")); !os.IsNotExist(err) {
+		t.Errorf("cache dir should be removed, %v", err)
+	}
+}
+
+func TestTenantQuota(t *testing.T) {
+	m := newTestManager(t, 0, 100)
+	defer os.RemoveAll(m.root)
+	build(t, m, t1, s1, 40)
+	time.Sleep(10 * time.Millisecond)
+	build(t, m, t1, s2, 40)
+	time.Sleep(10 * time.Millisecond)
+	build(t, m, t2, s3, 90)
+	time.Sleep(10 * time.Millisecond)
+	if _, evicted := build(t, m, t1, s3, 40); evicted {
+		t.Error("the latest cache should not be evicted")
+	}
+	entries, err := m.List(Filter{TenantID: t1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ServiceID != s2 || entries[1].ServiceID != s3 {
+		t.Errorf("the least recently used cache s1 should be evicted, but got %+v", entries)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	m := newTestManager(t, 0, 0)
+	defer os.RemoveAll(m.root)
+	build(t, m, t1, s1, 10)
+	build(t, m, t1, s2, 10)
+	// the cache created before the cache manager, in the layout without languages
+	writeCache(t, m.serviceDir(t2, s3), 10)
+	if _, _, err := m.Acquire(t1, s2, "Node.js", "dev"); err != nil {
+		t.Fatal(err)
+	}
+	purged, err := m.Purge(Filter{Lang: "java-maven"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 2 || purged[0].ServiceID != s1 || purged[1].ServiceID != s2 {
+		t.Errorf("want the java caches of s1 and s2 purged, but got %+v", purged)
+	}
+	purged, err = m.Purge(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0].ServiceID != s3 || purged[0].Size != 10 {
+		t.Errorf("want s3 purged and s2 in use skipped, but got %+v", purged)
+	}
+}
+
+func TestInvalidID(t *testing.T) {
+	m := newTestManager(t, 0, 0)
+	defer os.RemoveAll(m.root)
+	for _, f := range []Filter{{TenantID: "../../etc"}, {TenantID: t1, ServiceID: "s1/.."}, {ServiceID: strings.ToUpper(s1)}} {
+		if _, err := m.Purge(f); err == nil {
+			t.Errorf("filter %+v should be invalid", f)
+		}
+	}
+	if _, _, err := m.Acquire(t1, "../"+s1, "Java-maven", "master"); err == nil {
+		t.Error("the invalid service id should be refused")
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	m := newTestManager(t, 0, 0)
+	defer os.RemoveAll(m.root)
+	build(t, m, t1, s1, 10)
+	if hit, _ := buildLang(t, m, t1, s1, "Node.js", 10); hit {
+		t.Error("want miss for the first build in another language")
+	}
+	if hit, _ := build(t, m, t1, s1, 10); !hit {
+		t.Error("want hit for the language cached before")
+	}
+	entries, err := m.List(Filter{ServiceID: s1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("want the caches of 2 languages, but got %+v", entries)
+	}
+	for lang, want := range map[string]string{"Java-maven": "Java-maven", ".NetCore": ".NetCore", "": "default", "..": "default", "a/../b": "a_.._b"} {
+		if got := LangDir(lang); got != want {
+			t.Errorf("lang %q: want dir %s, got %s", lang, want, got)
+		}
+	}
+}
+
+func TestLegacyLayout(t *testing.T) {
+	m := newTestManager(t, 0, 0)
+	defer os.RemoveAll(m.root)
+	writeCache(t, m.serviceDir(t1, s1), 10)
+	if err := ioutil.WriteFile(m.legacyMetaFile(t1, s1), []byte(`{"lang":"Java-maven"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := m.List(Filter{Lang: "java-maven"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Size != 10 {
+		t.Fatalf("want the legacy cache listed, but got %+v", entries)
+	}
+	if hit, _ := build(t, m, t1, s1, 10); hit {
+		t.Error("the legacy cache should be removed before the build")
+	}
+	if _, err := os.Stat(m.legacyMetaFile(t1, s1)); !os.IsNotExist(err) {
+		t.Errorf("the legacy metadata should be removed, %v", err)
+	}
+	if _, err := os.Stat(path.Join(m.serviceDir(t1, s1), "data")); !os.IsNotExist(err) {
+		t.Errorf("the legacy cache should be removed, %v", err)
+	}
+}
@@ -2,6 +2,9 @@ package cloudos
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
+
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
 
@@ -48,7 +51,7 @@ func (a *aliOSS) GetObject(objectKey, filePath string) error {
 		return fmt.Errorf("failed to gets the bucket instance: %v", err)
 	}
 
-	err = bucket.GetObjectToFile(objectKey, filePath)
+	body, err := bucket.GetObject(objectKey)
 	if err != nil {
 		svcErr, ok := err.(oss.ServiceError)
 		if !ok {
@@ -56,7 +59,8 @@ func (a *aliOSS) GetObject(objectKey, filePath string) error {
 		}
 		return svcErrToS3SDKError(svcErr)
 	}
-	return nil
+	defer body.Close()
+	return writeFile(filePath, body)
 }
 
 func (a *aliOSS) DeleteObject(objkey string) error {
@@ -68,6 +72,64 @@ func (a *aliOSS) DeleteObject(objkey string) error {
 	return bucket.DeleteObject(objkey)
 }
 
+func (a *aliOSS) ListObjects(prefix string) ([]ObjectInfo, error) {
+	bucket, err := a.Bucket(a.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to gets the bucket instance: %v", err)
+	}
+
+	var objects []ObjectInfo
+	marker := ""
+	for {
+		res, err := bucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker))
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range res.Objects {
+			objects = append(objects, ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified, ETag: obj.ETag})
+		}
+		if !res.IsTruncated {
+			return objects, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+func (a *aliOSS) StatObject(objkey string) (*ObjectInfo, error) {
+	bucket, err := a.Bucket(a.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to gets the bucket instance: %v", err)
+	}
+
+	header, err := bucket.GetObjectDetailedMeta(objkey)
+	if err != nil {
+		if svcErr, ok := err.(oss.ServiceError); ok && svcErr.StatusCode == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	size, _ := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	lastModified, _ := http.ParseTime(header.Get("Last-Modified"))
+	return &ObjectInfo{Key: objkey, Size: size, LastModified: lastModified, ETag: header.Get("ETag")}, nil
+}
+
+func (a *aliOSS) MultipartUpload(objkey, filepath string) error {
+	bucket, err := a.Bucket(a.BucketName)
+	if err != nil {
+		return fmt.Errorf("failed to gets the bucket instance: %v", err)
+	}
+
+	// the checkpoint file next to the file records the uploaded parts, so that the upload can be resumed.
+	err = bucket.UploadFile(objkey, filepath, a.partSize(), oss.Checkpoint(true, filepath+".cp"))
+	if err != nil {
+		if svcErr, ok := err.(oss.ServiceError); ok {
+			return svcErrToS3SDKError(svcErr)
+		}
+		return fmt.Errorf("failed to upload file: %v", err)
+	}
+	return nil
+}
+
 func svcErrToS3SDKError(svcErr oss.ServiceError) S3SDKError {
 	return S3SDKError{
 		Code:       svcErr.Code,
@@ -2,13 +2,23 @@ package cloudos
 
 import (
 	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 var (
 	// ErrUnsupportedS3Provider -
 	ErrUnsupportedS3Provider = errors.New("unsupported s3 provider")
+	// ErrObjectNotFound -
+	ErrObjectNotFound = errors.New("object not found")
 )
 
+// DefaultPartSize is the default size of the parts of the multipart upload.
+const DefaultPartSize int64 = 16 << 20
+
 // S3Provider -
 type S3Provider string
 
@@ -17,6 +27,12 @@ var (
 	S3ProviderS3 S3Provider = "s3"
 	// S3ProviderAliOSS -
 	S3ProviderAliOSS S3Provider = "alioss"
+	// S3ProviderMinIO is the s3 compatible storage, such as minio or ceph rgw.
+	S3ProviderMinIO S3Provider = "minio"
+	// S3ProviderLocal stores the objects as files in the local directory, such as a nfs mount.
+	S3ProviderLocal S3Provider = "local"
+	// S3ProviderSFTP stores the objects as files in the directory of the sftp server.
+	S3ProviderSFTP S3Provider = "sftp"
 )
 
 func (p S3Provider) String() string {
@@ -30,6 +46,12 @@ func Str2S3Provider(value string) (S3Provider, error) {
 		return S3ProviderS3, nil
 	case S3ProviderAliOSS.String():
 		return S3ProviderAliOSS, nil
+	case S3ProviderMinIO.String():
+		return S3ProviderMinIO, nil
+	case S3ProviderLocal.String():
+		return S3ProviderLocal, nil
+	case S3ProviderSFTP.String():
+		return S3ProviderSFTP, nil
 	default:
 		return "", ErrUnsupportedS3Provider
 	}
@@ -40,6 +62,21 @@ type CloudOSer interface {
 	PutObject(objkey, filepath string) error
 	GetObject(objectKey, filePath string) error
 	DeleteObject(objkey string) error
+	// ListObjects returns the objects whose keys start with the prefix.
+	ListObjects(prefix string) ([]ObjectInfo, error)
+	// StatObject returns the information of the object, ErrObjectNotFound if it does not exist.
+	StatObject(objkey string) (*ObjectInfo, error)
+	// MultipartUpload uploads the file in parts, the parts uploaded by the last
+	// interrupted upload of the same object are skipped.
+	MultipartUpload(objkey, filepath string) error
+}
+
+// ObjectInfo is the information of the object.
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	ETag         string    `json:"etag"`
 }
 
 // New returns a new CloudOSer.
@@ -47,8 +84,12 @@ func New(cfg *Config) (CloudOSer, error) {
 	switch cfg.ProviderType {
 	case S3ProviderAliOSS:
 		return newAliOSS(cfg)
-	case S3ProviderS3:
+	case S3ProviderS3, S3ProviderMinIO:
 		return newS3(cfg)
+	case S3ProviderLocal:
+		return newLocal(cfg)
+	case S3ProviderSFTP:
+		return newSFTP(cfg)
 	default:
 		return nil, ErrUnsupportedS3Provider
 	}
@@ -63,6 +104,39 @@ type Config struct {
 	SecretKey string
 	UseSSL    bool
 
+	// BucketName is the directory of the objects for the local and sftp providers.
 	BucketName string
 	Location   string
+	// PartSize is the size of the parts of the multipart upload, DefaultPartSize if it is 0.
+	PartSize int64
+}
+
+func (c *Config) partSize() int64 {
+	if c.PartSize <= 0 {
+		return DefaultPartSize
+	}
+	return c.PartSize
+}
+
+// writeFile writes the downloaded object into a temp file beside filePath and renames it to filePath,
+// no partial file is left at filePath if the download fails.
+func writeFile(filePath string, r io.Reader) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
 }
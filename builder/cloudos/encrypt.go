@@ -0,0 +1,185 @@
+package cloudos
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// The encrypted archive starts with the magic, the salt of the key and the nonce prefix, followed by
+// the chunks sealed by AES-256-GCM. The nonce of a chunk is the prefix and the chunk number, and the
+// last chunk is marked by the additional data, so that the reordered or truncated chunks are detected.
+var encryptMagic = []byte("KATOENC1")
+
+const (
+	encryptChunkSize = 64 << 10
+	encryptSaltSize  = 16
+	noncePrefixSize  = 8
+)
+
+// ErrDecrypt is returned if the passphrase is wrong or the archive is corrupted.
+var ErrDecrypt = errors.New("decrypt failure, the passphrase is wrong or the archive is corrupted")
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, n uint32) []byte {
+	nonce := make([]byte, noncePrefixSize+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], n)
+	return nonce
+}
+
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// Encrypt encrypts the content read from r with the passphrase, and writes it to w.
+func Encrypt(w io.Writer, r io.Reader, passphrase string) error {
+	if passphrase == "" {
+		return errors.New("the passphrase is empty")
+	}
+	header := make([]byte, len(encryptMagic)+encryptSaltSize+noncePrefixSize)
+	copy(header, encryptMagic)
+	if _, err := io.ReadFull(rand.Reader, header[len(encryptMagic):]); err != nil {
+		return err
+	}
+	salt := header[len(encryptMagic) : len(encryptMagic)+encryptSaltSize]
+	prefix := header[len(encryptMagic)+encryptSaltSize:]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	buf := make([]byte, encryptChunkSize)
+	for n := uint32(0); ; n++ {
+		size, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := size < encryptChunkSize
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+		if _, err := w.Write(gcm.Seal(nil, chunkNonce(prefix, n), buf[:size], chunkAD(last))); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// Decrypt decrypts the content encrypted by Encrypt, ErrDecrypt is returned if it can not be authenticated.
+func Decrypt(w io.Writer, r io.Reader, passphrase string) error {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(encryptMagic)+encryptSaltSize+noncePrefixSize)
+	if _, err := io.ReadFull(br, header); err != nil || !bytes.Equal(header[:len(encryptMagic)], encryptMagic) {
+		return errors.New("not an encrypted archive")
+	}
+	salt := header[len(encryptMagic) : len(encryptMagic)+encryptSaltSize]
+	prefix := header[len(encryptMagic)+encryptSaltSize:]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, encryptChunkSize+gcm.Overhead())
+	for n := uint32(0); ; n++ {
+		size, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		last := size < len(buf)
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+		plain, err := gcm.Open(nil, chunkNonce(prefix, n), buf[:size], chunkAD(last))
+		if err != nil {
+			return ErrDecrypt
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// EncryptFile encrypts the file src to the file dst with the passphrase.
+func EncryptFile(src, dst, passphrase string) error {
+	return transformFile(src, dst, passphrase, Encrypt)
+}
+
+// DecryptFile decrypts the file src encrypted by EncryptFile to the file dst.
+func DecryptFile(src, dst, passphrase string) error {
+	return transformFile(src, dst, passphrase, Decrypt)
+}
+
+func transformFile(src, dst, passphrase string, transform func(io.Writer, io.Reader, string) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(out)
+	if err := transform(bw, in, passphrase); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// FileChecksum returns the hex encoded sha256 of the file.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
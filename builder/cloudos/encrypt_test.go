@@ -0,0 +1,51 @@
+package cloudos
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	for _, size := range []int{0, 10, encryptChunkSize, encryptChunkSize*2 + 100} {
+		plain := make([]byte, size)
+		if _, err := io.ReadFull(rand.Reader, plain); err != nil {
+			t.Fatal(err)
+		}
+		var encrypted bytes.Buffer
+		if err := Encrypt(&encrypted, bytes.NewReader(plain), "passphrase"); err != nil {
+			t.Fatal(err)
+		}
+		var decrypted bytes.Buffer
+		if err := Decrypt(&decrypted, bytes.NewReader(encrypted.Bytes()), "passphrase"); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !bytes.Equal(decrypted.Bytes(), plain) {
+			t.Errorf("size %d: the decrypted content is different", size)
+		}
+
+		if err := Decrypt(&bytes.Buffer{}, bytes.NewReader(encrypted.Bytes()), "wrong"); err != ErrDecrypt {
+			t.Errorf("size %d: want ErrDecrypt with the wrong passphrase, got %v", size, err)
+		}
+		tampered := append([]byte{}, encrypted.Bytes()...)
+		tampered[len(tampered)-1] ^= 1
+		if err := Decrypt(&bytes.Buffer{}, bytes.NewReader(tampered), "passphrase"); err != ErrDecrypt {
+			t.Errorf("size %d: want ErrDecrypt with the tampered content, got %v", size, err)
+		}
+	}
+}
+
+func TestDecryptTruncated(t *testing.T) {
+	plain := make([]byte, encryptChunkSize*2)
+	var encrypted bytes.Buffer
+	if err := Encrypt(&encrypted, bytes.NewReader(plain), "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	// drop the last chunk
+	header := len(encryptMagic) + encryptSaltSize + noncePrefixSize
+	truncated := encrypted.Bytes()[:header+encryptChunkSize+16]
+	if err := Decrypt(&bytes.Buffer{}, bytes.NewReader(truncated), "passphrase"); err != ErrDecrypt {
+		t.Errorf("want ErrDecrypt with the truncated content, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package cloudos
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// uploadingSuffix is the suffix of the file being uploaded, it is renamed to the object after uploading.
+const uploadingSuffix = ".uploading"
+
+// fileSystem is the file system storing the objects as files.
+type fileSystem interface {
+	Stat(name string) (os.FileInfo, error)
+	ReadDir(dir string) ([]os.FileInfo, error)
+	MkdirAll(dir string) error
+	OpenFile(name string, flag int) (file, error)
+	Rename(oldname, newname string) error
+	Remove(name string) error
+	Close() error
+}
+
+type file interface {
+	io.ReadWriteSeeker
+	io.Closer
+}
+
+// fsDriver stores the objects in the directory of the file system, the object key is the path relative to the directory.
+type fsDriver struct {
+	root string
+	// connect returns the file system, which is closed after every operation.
+	connect func() (fileSystem, error)
+}
+
+func (d *fsDriver) path(objkey string) string {
+	return path.Join(d.root, objkey)
+}
+
+func (d *fsDriver) PutObject(objkey, filepath string) error {
+	return d.upload(objkey, filepath, false)
+}
+
+func (d *fsDriver) MultipartUpload(objkey, filepath string) error {
+	return d.upload(objkey, filepath, true)
+}
+
+// upload copies the file to a temporary file, which is renamed to the object when the copy completes.
+// The temporary file is named by the size and the modification time of the source file, if resume is true
+// and the temporary file of the same source exists, the copy continues from the end of it.
+func (d *fsDriver) upload(objkey, filepath string, resume bool) error {
+	src, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	stat, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	fs, err := d.connect()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	dst := d.path(objkey)
+	if err := fs.MkdirAll(path.Dir(dst)); err != nil {
+		return fmt.Errorf("create directory %s: %v", path.Dir(dst), err)
+	}
+	uploading := fmt.Sprintf("%s.%d-%d%s", dst, stat.Size(), stat.ModTime().Unix(), uploadingSuffix)
+	removeStale(fs, dst, uploading)
+
+	var offset int64
+	if fi, err := fs.Stat(uploading); resume && err == nil && fi.Size() <= stat.Size() {
+		offset = fi.Size()
+	}
+	flag := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flag |= os.O_TRUNC
+	}
+	out, err := fs.OpenFile(uploading, flag)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return fmt.Errorf("copy %s to %s: %v", filepath, uploading, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// rename does not overwrite the existing file on some sftp servers
+	if err := fs.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return fs.Rename(uploading, dst)
+}
+
+// removeStale removes the temporary files of the object uploaded from other sources.
+func removeStale(fs fileSystem, dst, uploading string) {
+	infos, err := fs.ReadDir(path.Dir(dst))
+	if err != nil {
+		return
+	}
+	prefix := path.Base(dst) + "."
+	for _, info := range infos {
+		name := info.Name()
+		if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, uploadingSuffix) && name != path.Base(uploading) {
+			_ = fs.Remove(path.Join(path.Dir(dst), name))
+		}
+	}
+}
+
+func (d *fsDriver) GetObject(objkey, filePath string) error {
+	fs, err := d.connect()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	src, err := fs.OpenFile(d.path(objkey), os.O_RDONLY)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrObjectNotFound
+		}
+		return err
+	}
+	defer src.Close()
+	return writeFile(filePath, src)
+}
+
+func (d *fsDriver) DeleteObject(objkey string) error {
+	fs, err := d.connect()
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	if err := fs.Remove(d.path(objkey)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (d *fsDriver) ListObjects(prefix string) ([]ObjectInfo, error) {
+	fs, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	var objects []ObjectInfo
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		infos, err := fs.ReadDir(d.path(dir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, info := range infos {
+			key := path.Join(dir, info.Name())
+			if info.IsDir() {
+				// only walk into the directories which may contain the prefix
+				if strings.HasPrefix(key+"/", prefix) || strings.HasPrefix(prefix, key+"/") {
+					if err := walk(key); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !strings.HasPrefix(key, prefix) || strings.HasSuffix(key, uploadingSuffix) {
+				continue
+			}
+			objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+func (d *fsDriver) StatObject(objkey string) (*ObjectInfo, error) {
+	fs, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+
+	info, err := fs.Stat(d.path(objkey))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, ErrObjectNotFound
+	}
+	return &ObjectInfo{Key: objkey, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
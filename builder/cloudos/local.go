@@ -0,0 +1,52 @@
+package cloudos
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+// localFS is the local file system, the directory may be a mounted network file system.
+type localFS struct{}
+
+func newLocal(cfg *Config) (CloudOSer, error) {
+	if cfg.BucketName == "" {
+		return nil, errors.New("the directory of the local storage is required")
+	}
+	return &fsDriver{
+		root:    cfg.BucketName,
+		connect: func() (fileSystem, error) { return localFS{}, nil },
+	}, nil
+}
+
+func (localFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (localFS) ReadDir(dir string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(dir)
+}
+
+func (localFS) MkdirAll(dir string) error {
+	return os.MkdirAll(dir, 0755)
+}
+
+func (localFS) OpenFile(name string, flag int) (file, error) {
+	f, err := os.OpenFile(name, flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (localFS) Rename(oldname, newname string) error {
+	return os.Rename(oldname, newname)
+}
+
+func (localFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (localFS) Close() error {
+	return nil
+}
@@ -0,0 +1,178 @@
+package cloudos
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "backup.zip")
+	if err := ioutil.WriteFile(src, []byte("backup package"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cloudoser, err := New(&Config{ProviderType: S3ProviderLocal, BucketName: filepath.Join(dir, "bucket")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cloudoser.StatObject("groupbackup/backup.zip"); err != ErrObjectNotFound {
+		t.Fatalf("want ErrObjectNotFound, got %v", err)
+	}
+	if err := cloudoser.PutObject("groupbackup/backup.zip", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := cloudoser.MultipartUpload("other.zip", src); err != nil {
+		t.Fatal(err)
+	}
+	info, err := cloudoser.StatObject("groupbackup/backup.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len("backup package")) {
+		t.Errorf("want size %d, got %d", len("backup package"), info.Size)
+	}
+	objects, err := cloudoser.ListObjects("group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "groupbackup/backup.zip" {
+		t.Errorf("unexpected objects %v", objects)
+	}
+
+	dst := filepath.Join(dir, "download.zip")
+	if err := cloudoser.GetObject("groupbackup/backup.zip", dst); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ioutil.ReadFile(dst); string(content) != "backup package" {
+		t.Errorf("unexpected content %s", content)
+	}
+	if err := cloudoser.DeleteObject("groupbackup/backup.zip"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cloudoser.DeleteObject("groupbackup/backup.zip"); err != nil {
+		t.Errorf("deleting the object not found should succeed, got %v", err)
+	}
+	objects, err = cloudoser.ListObjects("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].Key != "other.zip" {
+		t.Errorf("unexpected objects %v", objects)
+	}
+}
+
+func TestLocalMultipartUploadResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "backup.zip")
+	if err := ioutil.WriteFile(src, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stat, err := os.Stat(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucket := filepath.Join(dir, "bucket")
+	if err := os.MkdirAll(bucket, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// the interrupted upload of the same source, and the stale one of another source
+	uploading := filepath.Join(bucket, "backup.zip.10-"+itoa(stat.ModTime().Unix())+uploadingSuffix)
+	if err := ioutil.WriteFile(uploading, []byte("01234"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(bucket, "backup.zip.3-1"+uploadingSuffix)
+	if err := ioutil.WriteFile(stale, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cloudoser, err := New(&Config{ProviderType: S3ProviderLocal, BucketName: bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the source is changed after the interrupted upload, only the rest is appended
+	if err := ioutil.WriteFile(src, []byte("ABCDE56789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(src, stat.ModTime(), stat.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cloudoser.MultipartUpload("backup.zip", src); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(bucket, "backup.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "0123456789" {
+		t.Errorf("the upload should be resumed, got %s", content)
+	}
+	files, err := ioutil.ReadDir(bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"backup.zip"}) {
+		t.Errorf("the temporary files should be removed, got %v", names)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestGetObjectNoPartialFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cloudos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cloudoser, err := New(&Config{ProviderType: S3ProviderLocal, BucketName: filepath.Join(dir, "bucket")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	download := filepath.Join(dir, "download")
+	if err := os.Mkdir(download, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(download, "backup.zip")
+	if err := cloudoser.GetObject("groupbackup/backup.zip", dst); err != ErrObjectNotFound {
+		t.Fatalf("want ErrObjectNotFound, got %v", err)
+	}
+	if err := writeFile(dst, io.MultiReader(strings.NewReader("partial"), errReader{})); err == nil {
+		t.Fatal("want the error of the reader")
+	}
+	if files, _ := ioutil.ReadDir(download); len(files) != 0 {
+		t.Errorf("no file should be left after the failed download, got %s", files[0].Name())
+	}
+	if err := writeFile(dst, strings.NewReader("backup package")); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := ioutil.ReadFile(dst); string(content) != "backup package" {
+		t.Errorf("unexpected content %s", content)
+	}
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
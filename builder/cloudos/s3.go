@@ -1,11 +1,18 @@
 package cloudos
 
 import (
-	"io/ioutil"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -17,11 +24,15 @@ type s3Driver struct {
 }
 
 func newS3(cfg *Config) (CloudOSer, error) {
+	region := "us-east-1"
+	if cfg.Location != "" {
+		region = cfg.Location
+	}
 	s3Config := &aws.Config{
 		Credentials:      credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
 		Endpoint:         aws.String(cfg.Endpoint),
-		Region:           aws.String("us-east-1"),
-		DisableSSL:       aws.Bool(true),
+		Region:           aws.String(region),
+		DisableSSL:       aws.Bool(!cfg.UseSSL),
 		S3ForcePathStyle: aws.Bool(true),
 	}
 	sess := session.New(s3Config)
@@ -59,14 +70,9 @@ func (s *s3Driver) GetObject(objkey, filePath string) error {
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	ioutil.WriteFile(filePath, b, os.ModePerm)
-	return nil
+	return writeFile(filePath, resp.Body)
 }
 
 func (s *s3Driver) DeleteObject(objkey string) error {
@@ -76,3 +82,155 @@ func (s *s3Driver) DeleteObject(objkey string) error {
 	})
 	return err
 }
+
+func (s *s3Driver) ListObjects(prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	err := s.s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.BucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+				ETag:         aws.StringValue(obj.ETag),
+			})
+		}
+		return true
+	})
+	return objects, err
+}
+
+func (s *s3Driver) StatObject(objkey string) (*ObjectInfo, error) {
+	resp, err := s.s3.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.BucketName),
+		Key:    aws.String(objkey),
+	})
+	if err != nil {
+		if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Key:          objkey,
+		Size:         aws.Int64Value(resp.ContentLength),
+		LastModified: aws.TimeValue(resp.LastModified),
+		ETag:         aws.StringValue(resp.ETag),
+	}, nil
+}
+
+func (s *s3Driver) MultipartUpload(objkey, filepath string) error {
+	fp, err := os.Open(filepath)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	stat, err := fp.Stat()
+	if err != nil {
+		return err
+	}
+
+	uploadID, uploaded, err := s.lastUpload(objkey)
+	if err != nil {
+		return err
+	}
+	if uploadID == "" {
+		resp, err := s.s3.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+			Bucket: aws.String(s.BucketName),
+			Key:    aws.String(objkey),
+		})
+		if err != nil {
+			return err
+		}
+		uploadID = aws.StringValue(resp.UploadId)
+	}
+
+	partSize := s.partSize()
+	var parts []*s3.CompletedPart
+	for num, offset := int64(1), int64(0); offset < stat.Size(); num, offset = num+1, offset+partSize {
+		size := partSize
+		if offset+size > stat.Size() {
+			size = stat.Size() - offset
+		}
+		section := io.NewSectionReader(fp, offset, size)
+		// the part uploaded by the last upload is reused if the content is the same
+		if part, ok := uploaded[num]; ok && aws.Int64Value(part.Size) == size && sameETag(section, aws.StringValue(part.ETag)) {
+			parts = append(parts, &s3.CompletedPart{ETag: part.ETag, PartNumber: part.PartNumber})
+			continue
+		}
+		if _, err := section.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		resp, err := s.s3.UploadPart(&s3.UploadPartInput{
+			Bucket:        aws.String(s.BucketName),
+			Key:           aws.String(objkey),
+			UploadId:      aws.String(uploadID),
+			PartNumber:    aws.Int64(num),
+			Body:          section,
+			ContentLength: aws.Int64(size),
+		})
+		if err != nil {
+			return fmt.Errorf("upload part %d: %v", num, err)
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: resp.ETag, PartNumber: aws.Int64(num)})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.Int64Value(parts[i].PartNumber) < aws.Int64Value(parts[j].PartNumber)
+	})
+	_, err = s.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.BucketName),
+		Key:             aws.String(objkey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
+// lastUpload returns the id and the uploaded parts of the latest unfinished upload of the object.
+func (s *s3Driver) lastUpload(objkey string) (string, map[int64]*s3.Part, error) {
+	resp, err := s.s3.ListMultipartUploads(&s3.ListMultipartUploadsInput{
+		Bucket: aws.String(s.BucketName),
+		Prefix: aws.String(objkey),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	var last *s3.MultipartUpload
+	for _, upload := range resp.Uploads {
+		if aws.StringValue(upload.Key) != objkey {
+			continue
+		}
+		if last == nil || aws.TimeValue(upload.Initiated).After(aws.TimeValue(last.Initiated)) {
+			last = upload
+		}
+	}
+	if last == nil {
+		return "", nil, nil
+	}
+	parts := make(map[int64]*s3.Part)
+	err = s.s3.ListPartsPages(&s3.ListPartsInput{
+		Bucket:   aws.String(s.BucketName),
+		Key:      aws.String(objkey),
+		UploadId: last.UploadId,
+	}, func(page *s3.ListPartsOutput, lastPage bool) bool {
+		for _, part := range page.Parts {
+			parts[aws.Int64Value(part.PartNumber)] = part
+		}
+		return true
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return aws.StringValue(last.UploadId), parts, nil
+}
+
+// sameETag checks if the etag of the part is the md5 of the content.
+func sameETag(content io.Reader, etag string) bool {
+	h := md5.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return false
+	}
+	return hex.EncodeToString(h.Sum(nil)) == strings.Trim(etag, `"`)
+}
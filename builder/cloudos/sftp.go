@@ -0,0 +1,57 @@
+package cloudos
+
+import (
+	"errors"
+	"net"
+
+	"github.com/gridworkz/kato/builder/sources"
+)
+
+// sftpFS is the file system of the sftp server, the endpoint is host:port, the access key
+// and the secret key are the username and the password.
+type sftpFS struct {
+	*sources.SFTPClient
+}
+
+func newSFTP(cfg *Config) (CloudOSer, error) {
+	if cfg.BucketName == "" {
+		return nil, errors.New("the directory of the sftp storage is required")
+	}
+	host, port, err := net.SplitHostPort(cfg.Endpoint)
+	if err != nil {
+		host, port = cfg.Endpoint, "22"
+	}
+	return &fsDriver{
+		root: cfg.BucketName,
+		connect: func() (fileSystem, error) {
+			client, err := sources.NewSFTPClient(cfg.AccessKey, cfg.SecretKey, host, port)
+			if err != nil {
+				return nil, err
+			}
+			return sftpFS{client}, nil
+		},
+	}, nil
+}
+
+func (s sftpFS) MkdirAll(dir string) error {
+	if info, err := s.Stat(dir); err == nil {
+		if !info.IsDir() {
+			return errors.New("not a directory")
+		}
+		return nil
+	}
+	return s.SFTPClient.MkdirAll(dir)
+}
+
+func (s sftpFS) OpenFile(name string, flag int) (file, error) {
+	f, err := s.SFTPClient.OpenFile(name, flag)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s sftpFS) Close() error {
+	s.SFTPClient.Close()
+	return nil
+}
@@ -84,6 +84,11 @@ func (t *TaskManager) callback(task *pb.TaskMessage) {
 	})
 	if err != nil {
 		logrus.Errorf("callback task to mq failure %s", err.Error())
+		return
+	}
+	// the task has been sent back, the original one is finished
+	if err := t.client.AckTask(t.config.Topic, task); err != nil {
+		logrus.Errorf("ack task %s: %v", task.TaskId, err)
 	}
 	logrus.Infof("The build controller returns an indigestible task(%s) to the messaging system", task.TaskId)
 }
@@ -124,8 +129,16 @@ func (t *TaskManager) Do(errChan chan error) {
 			}
 			err = t.exec.AddTask(data)
 			if err != nil {
-				t.callbackChan <- data
 				logrus.Error("add task error:", err.Error())
+				if data.Receipt != "" {
+					// the task is not acknowledged, it will be redelivered after the visibility timeout
+					continue
+				}
+				t.callbackChan <- data
+				continue
+			}
+			if err := t.client.AckTask(t.config.Topic, data); err != nil {
+				logrus.Errorf("ack task %s: %v", data.TaskId, err)
 			}
 		}
 	}
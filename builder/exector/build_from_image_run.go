@@ -26,11 +26,13 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/gridworkz/kato/builder"
 	"github.com/gridworkz/kato/builder/build"
+	"github.com/gridworkz/kato/builder/signature"
 	"github.com/gridworkz/kato/builder/sources"
 	"github.com/gridworkz/kato/db"
 	"github.com/gridworkz/kato/event"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	"k8s.io/client-go/kubernetes"
 )
 
 //ImageBuildItem ImageBuildItem
@@ -43,6 +45,9 @@ type ImageBuildItem struct {
 	Logger        event.Logger `json:"logger"`
 	EventID       string       `json:"event_id"`
 	DockerClient  *client.Client
+	KubeClient    kubernetes.Interface
+	RbdNamespace  string
+	SigningSecret string
 	TenantID      string
 	ServiceID     string
 	DeployVersion string
@@ -60,6 +65,7 @@ func NewImageBuildItem(in []byte) *ImageBuildItem {
 		Namespace:     gjson.GetBytes(in, "namespace").String(),
 		TenantName:    gjson.GetBytes(in, "tenant_name").String(),
 		ServiceAlias:  gjson.GetBytes(in, "service_alias").String(),
+		TenantID:      gjson.GetBytes(in, "tenant_id").String(),
 		ServiceID:     gjson.GetBytes(in, "service_id").String(),
 		Image:         gjson.GetBytes(in, "image").String(),
 		DeployVersion: gjson.GetBytes(in, "deploy_version").String(),
@@ -75,16 +81,26 @@ func NewImageBuildItem(in []byte) *ImageBuildItem {
 //Run
 func (i *ImageBuildItem) Run(timeout time.Duration) error {
 	user, pass := builder.GetImageUserInfoV2(i.Image, i.HubUser, i.HubPassword)
-	_, err := sources.ImagePull(i.DockerClient, i.Image, user, pass, i.Logger, 30)
+	signer := loadSigner(i.KubeClient, i.RbdNamespace, i.SigningSecret, i.Logger)
+	violated, verified, signerKey, err := i.checkImagePolicy(user, pass, signer)
 	if err != nil {
-		logrus.Errorf("pull image %s error: %s", i.Image, err.Error())
-		i.Logger.Error(fmt.Sprintf("get specified image: %s failure", i.Image), map[string]string{"step": "builder-exector", "status": "failure"})
+		return err
+	}
+	// the verified digest is pulled, the tag may be moved to another image after the verification
+	image := i.Image
+	if verified != "" {
+		image = verified
+	}
+	_, err = sources.ImagePull(i.DockerClient, image, user, pass, i.Logger, 30)
+	if err != nil {
+		logrus.Errorf("pull image %s error: %s", image, err.Error())
+		i.Logger.Error(fmt.Sprintf("get specified image: %s failure", image), map[string]string{"step": "builder-exector", "status": "failure"})
 		return err
 	}
 	localImageURL := build.CreateImageName(i.ServiceID, i.DeployVersion)
-	if err := sources.ImageTag(i.DockerClient, i.Image, localImageURL, i.Logger, 1); err != nil {
+	if err := sources.ImageTag(i.DockerClient, image, localImageURL, i.Logger, 1); err != nil {
 		logrus.Errorf("change image tag error: %s", err.Error())
-		i.Logger.Error(fmt.Sprintf("modify mirror tag: %s -> %s failure", i.Image, localImageURL), map[string]string{"step": "builder-exector", "status": "failure"})
+		i.Logger.Error(fmt.Sprintf("modify mirror tag: %s -> %s failure", image, localImageURL), map[string]string{"step": "builder-exector", "status": "failure"})
 		return err
 	}
 	err = sources.ImagePush(i.DockerClient, localImageURL, builder.REGISTRYUSER, builder.REGISTRYPASS, i.Logger, 30)
@@ -93,17 +109,22 @@ func (i *ImageBuildItem) Run(timeout time.Duration) error {
 		i.Logger.Error("failed to push the image to the mirror warehouse", map[string]string{"step": "builder-exector", "status": "failure"})
 		return err
 	}
+	// the image violating the policy in audit mode is not signed, so that it is reported when deploying.
+	if violated {
+		signer = nil
+	}
+	dgst, signed := secureImage(localImageURL, signer, i.Logger)
 
 	if err := sources.ImageRemove(i.DockerClient, localImageURL); err != nil {
 		logrus.Errorf("remove image %s failure %s", localImageURL, err.Error())
 	}
 
 	if os.Getenv("DISABLE_IMAGE_CACHE") == "true" {
-		if err := sources.ImageRemove(i.DockerClient, i.Image); err != nil {
-			logrus.Errorf("remove image %s failure %s", i.Image, err.Error())
+		if err := sources.ImageRemove(i.DockerClient, image); err != nil {
+			logrus.Errorf("remove image %s failure %s", image, err.Error())
 		}
 	}
-	if err := i.StorageVersionInfo(localImageURL); err != nil {
+	if err := i.StorageVersionInfo(localImageURL, dgst.String(), signed, signerKey); err != nil {
 		logrus.Errorf("storage version info error, ignor it: %s", err.Error())
 		i.Logger.Error("failed to update app version information", map[string]string{"step": "builder-exector", "status": "failure"})
 		return err
@@ -111,8 +132,40 @@ func (i *ImageBuildItem) Run(timeout time.Duration) error {
 	return nil
 }
 
+//checkImagePolicy checks the image against the image policy of the tenant, the violation is written into
+//the event log. An error is returned if the policy is enforced, or it returns whether the image violates the policy,
+//with the verified image pinned to its digest and the signer verifying it, the signer is checked against the policy
+//again when deploying.
+func (i *ImageBuildItem) checkImagePolicy(user, pass string, signer *signature.Signer) (bool, string, string, error) {
+	policy, err := loadImagePolicy(i.TenantID)
+	if err != nil {
+		logrus.Errorf("load image policy of tenant %s: %v", i.TenantID, err)
+		i.Logger.Error("failed to load the image policy of the tenant", map[string]string{"step": "image-policy", "status": "failure"})
+		return false, "", "", err
+	}
+	if policy == nil {
+		return false, "", "", nil
+	}
+	violation, verified, signerKey, err := checkImagePolicy(policy, i.Image, user, pass, signer)
+	if err != nil {
+		logrus.Errorf("verify image %s: %v", i.Image, err)
+		i.Logger.Error(fmt.Sprintf("verify the signature of image %s failure: %v", i.Image, err), map[string]string{"step": "image-policy", "status": "failure"})
+		return false, "", "", err
+	}
+	if violation == nil {
+		i.Logger.Info(fmt.Sprintf("image %s passes the image policy", i.Image), map[string]string{"step": "image-policy"})
+		return false, verified, signerKey, nil
+	}
+	if policy.Enforced() {
+		i.Logger.Error(violation.Error()+", the image is refused", map[string]string{"step": "image-policy", "status": "failure"})
+		return true, "", "", violation
+	}
+	i.Logger.Error(violation.Error()+", it is allowed in audit mode", map[string]string{"step": "image-policy"})
+	return true, "", "", nil
+}
+
 //StorageVersionInfo
-func (i *ImageBuildItem) StorageVersionInfo(imageURL string) error {
+func (i *ImageBuildItem) StorageVersionInfo(imageURL, imageDigest string, signed bool, signerKey string) error {
 	version, err := db.GetManager().VersionInfoDao().GetVersionByDeployVersion(i.DeployVersion, i.ServiceID)
 	if err != nil {
 		return err
@@ -121,6 +174,9 @@ func (i *ImageBuildItem) StorageVersionInfo(imageURL string) error {
 	version.DeliveredPath = imageURL
 	version.ImageName = imageURL
 	version.RepoURL = i.Image
+	version.ImageDigest = imageDigest
+	version.Signed = signed
+	version.SignerKey = signerKey
 	version.FinalStatus = "success"
 	version.FinishTime = time.Now()
 	if err := db.GetManager().VersionInfoDao().UpdateModel(version); err != nil {
@@ -29,8 +29,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/docker/docker/client"
+	units "github.com/docker/go-units"
 	"github.com/gridworkz/kato/builder"
 	"github.com/gridworkz/kato/builder/build"
+	"github.com/gridworkz/kato/builder/cache"
 	"github.com/gridworkz/kato/builder/parser"
 	"github.com/gridworkz/kato/builder/parser/code"
 	"github.com/gridworkz/kato/builder/sources"
@@ -38,6 +40,7 @@ import (
 	dbmodel "github.com/gridworkz/kato/db/model"
 	"github.com/gridworkz/kato/event"
 	"github.com/gridworkz/kato/util"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson" //"github.com/docker/docker/api/types"
@@ -47,24 +50,27 @@ import (
 
 //SourceCodeBuildItem SouceCodeBuildItem
 type SourceCodeBuildItem struct {
-	Namespace     string       `json:"namespace"`
-	TenantName    string       `json:"tenant_name"`
-	GRDataPVCName string       `json:"gr_data_pvc_name"`
-	CachePVCName  string       `json:"cache_pvc_name"`
-	CacheMode     string       `json:"cache_mode"`
-	CachePath     string       `json:"cache_path"`
-	ServiceAlias  string       `json:"service_alias"`
-	Action        string       `json:"action"`
-	DestImage     string       `json:"dest_image"`
-	Logger        event.Logger `json:"logger"`
-	EventID       string       `json:"event_id"`
-	CacheDir      string       `json:"cache_dir"`
+	Namespace         string       `json:"namespace"`
+	TenantName        string       `json:"tenant_name"`
+	GRDataPVCName     string       `json:"gr_data_pvc_name"`
+	CachePVCName      string       `json:"cache_pvc_name"`
+	CacheMode         string       `json:"cache_mode"`
+	CachePath         string       `json:"cache_path"`
+	DockerfileBuilder string       `json:"dockerfile_builder"`
+	Platforms         []string     `json:"platforms"`
+	ServiceAlias      string       `json:"service_alias"`
+	Action            string       `json:"action"`
+	DestImage         string       `json:"dest_image"`
+	Logger            event.Logger `json:"logger"`
+	EventID           string       `json:"event_id"`
+	CacheDir          string       `json:"cache_dir"`
 	//SourceDir     string       `json:"source_dir"`
 	TGZDir        string `json:"tgz_dir"`
 	DockerClient  *client.Client
 	KubeClient    kubernetes.Interface
 	RbdNamespace  string
 	RbdRepoName   string
+	SigningSecret string
 	TenantID      string
 	ServiceID     string
 	DeployVersion string
@@ -119,7 +125,7 @@ func NewSouceCodeBuildItem(in []byte) *SourceCodeBuildItem {
 		Configs:       gjson.GetBytes(in, "configs").Map(),
 		BuildEnvs:     be,
 	}
-	scb.CacheDir = fmt.Sprintf("/cache/build/%s/cache/%s", scb.TenantID, scb.ServiceID)
+	scb.CacheDir = scb.cacheDir()
 	//scb.SourceDir = scb.CodeSouceInfo.GetCodeSourceDir()
 	scb.TGZDir = fmt.Sprintf("/grdata/build/tenant/%s/slug/%s", scb.TenantID, scb.ServiceID)
 	return scb
@@ -205,6 +211,14 @@ func (i *SourceCodeBuildItem) Run(timeout time.Duration) error {
 			return err
 		}
 		i.Lang = string(lang)
+		i.CacheDir = i.cacheDir()
+	}
+
+	defer i.acquireBuildCache()()
+	if err := i.prepareCacheDir(); err != nil {
+		logrus.Errorf("prepare build cache error: %s", err.Error())
+		i.Logger.Error(fmt.Sprintf("failed to prepare source code build"), map[string]string{"step": "builder-exector", "status": "failure"})
+		return err
 	}
 
 	i.Logger.Info("pull or clone code successfully, start code build", map[string]string{"step": "codee-version"})
@@ -223,6 +237,42 @@ func (i *SourceCodeBuildItem) Run(timeout time.Duration) error {
 	return nil
 }
 
+//cacheDir returns the build cache directory of the component in the language, see cache.DefaultRoot
+func (i *SourceCodeBuildItem) cacheDir() string {
+	return fmt.Sprintf("/cache/build/%s/cache/%s/%s", i.TenantID, i.ServiceID, cache.LangDir(i.Lang))
+}
+
+//acquireBuildCache reports the cache hit or miss in the build log, and returns the func
+//which updates the cache size and evicts the caches exceeding the quotas after building.
+func (i *SourceCodeBuildItem) acquireBuildCache() func() {
+	m := cache.GetManager()
+	if m == nil || m.Dir(i.TenantID, i.ServiceID, i.Lang) != i.CacheDir {
+		return func() {}
+	}
+	entry, hit, err := m.Acquire(i.TenantID, i.ServiceID, i.Lang, i.CodeSouceInfo.Branch)
+	if err != nil {
+		logrus.Warningf("acquire build cache of service %s: %v", i.ServiceID, err)
+		return func() {}
+	}
+	if hit {
+		i.Logger.Info(fmt.Sprintf("Build cache hit, %s cached by the last build", units.HumanSize(float64(entry.Size))), map[string]string{"step": "build-cache"})
+	} else {
+		i.Logger.Info("Build cache miss, the dependencies will be downloaded", map[string]string{"step": "build-cache"})
+	}
+	return func() {
+		entry, evicted, err := m.Release(i.TenantID, i.ServiceID, i.Lang)
+		if err != nil {
+			logrus.Warningf("release build cache of service %s: %v", i.ServiceID, err)
+			return
+		}
+		msg := fmt.Sprintf("Build cache size is %s", units.HumanSize(float64(entry.Size)))
+		if evicted {
+			msg += ", it is evicted because the cache quota is exceeded"
+		}
+		i.Logger.Info(msg, map[string]string{"step": "build-cache"})
+	}
+}
+
 func (i *SourceCodeBuildItem) codeBuild() (*build.Response, error) {
 	codeBuild, err := build.GetBuild(code.Lang(i.Lang))
 	if err != nil {
@@ -236,30 +286,32 @@ func (i *SourceCodeBuildItem) codeBuild() (*build.Response, error) {
 		return nil, err
 	}
 	buildReq := &build.Request{
-		RbdNamespace:  i.RbdNamespace,
-		SourceDir:     i.RepoInfo.GetCodeBuildAbsPath(),
-		CacheDir:      i.CacheDir,
-		TGZDir:        i.TGZDir,
-		RepositoryURL: i.RepoInfo.RepostoryURL,
-		ServiceAlias:  i.ServiceAlias,
-		ServiceID:     i.ServiceID,
-		TenantID:      i.TenantID,
-		ServerType:    i.CodeSouceInfo.ServerType,
-		Runtime:       i.Runtime,
-		Branch:        i.CodeSouceInfo.Branch,
-		DeployVersion: i.DeployVersion,
-		Commit:        build.Commit{User: i.commit.Author, Message: i.commit.Message, Hash: i.commit.Hash},
-		Lang:          code.Lang(i.Lang),
-		BuildEnvs:     i.BuildEnvs,
-		Logger:        i.Logger,
-		DockerClient:  i.DockerClient,
-		KubeClient:    i.KubeClient,
-		HostAlias:     hostAlias,
-		Ctx:           i.Ctx,
-		GRDataPVCName: i.GRDataPVCName,
-		CachePVCName:  i.CachePVCName,
-		CacheMode:     i.CacheMode,
-		CachePath:     i.CachePath,
+		RbdNamespace:      i.RbdNamespace,
+		SourceDir:         i.RepoInfo.GetCodeBuildAbsPath(),
+		CacheDir:          i.CacheDir,
+		TGZDir:            i.TGZDir,
+		RepositoryURL:     i.RepoInfo.RepostoryURL,
+		ServiceAlias:      i.ServiceAlias,
+		ServiceID:         i.ServiceID,
+		TenantID:          i.TenantID,
+		ServerType:        i.CodeSouceInfo.ServerType,
+		Runtime:           i.Runtime,
+		Branch:            i.CodeSouceInfo.Branch,
+		DeployVersion:     i.DeployVersion,
+		Commit:            build.Commit{User: i.commit.Author, Message: i.commit.Message, Hash: i.commit.Hash},
+		Lang:              code.Lang(i.Lang),
+		BuildEnvs:         i.BuildEnvs,
+		Logger:            i.Logger,
+		DockerClient:      i.DockerClient,
+		KubeClient:        i.KubeClient,
+		HostAlias:         hostAlias,
+		Ctx:               i.Ctx,
+		GRDataPVCName:     i.GRDataPVCName,
+		CachePVCName:      i.CachePVCName,
+		CacheMode:         i.CacheMode,
+		CachePath:         i.CachePath,
+		DockerfileBuilder: i.DockerfileBuilder,
+		Platforms:         i.Platforms,
 	}
 	res, err := codeBuild.Build(buildReq)
 	return res, err
@@ -306,9 +358,6 @@ func (i *SourceCodeBuildItem) IsDockerfile() bool {
 }
 
 func (i *SourceCodeBuildItem) prepare() error {
-	if err := util.CheckAndCreateDir(i.CacheDir); err != nil {
-		return err
-	}
 	if err := util.CheckAndCreateDir(i.TGZDir); err != nil {
 		return err
 	}
@@ -319,6 +368,18 @@ func (i *SourceCodeBuildItem) prepare() error {
 		if !util.DirIsEmpty(i.RepoInfo.GetCodeHome()) {
 			os.RemoveAll(i.RepoInfo.GetCodeHome())
 		}
+	}
+	os.Chown(i.TGZDir, 200, 200)
+	return nil
+}
+
+//prepareCacheDir creates the build cache directory after the language of the code is known,
+//the cache in the language is cleared if NO_CACHE is set
+func (i *SourceCodeBuildItem) prepareCacheDir() error {
+	if err := util.CheckAndCreateDir(i.CacheDir); err != nil {
+		return err
+	}
+	if _, ok := i.BuildEnvs["NO_CACHE"]; ok {
 		if err := os.RemoveAll(i.CacheDir); err != nil {
 			logrus.Error("remove cache dir error", err.Error())
 		}
@@ -327,7 +388,6 @@ func (i *SourceCodeBuildItem) prepare() error {
 		}
 	}
 	os.Chown(i.CacheDir, 200, 200)
-	os.Chown(i.TGZDir, 200, 200)
 	return nil
 }
 
@@ -353,6 +413,8 @@ func (i *SourceCodeBuildItem) UpdateVersionInfo(vi *dbmodel.VersionInfo) error {
 	version.Author = vi.Author
 	version.CodeVersion = vi.CodeVersion
 	version.CodeBranch = vi.CodeBranch
+	version.ImageDigest = vi.ImageDigest
+	version.Signed = vi.Signed
 	version.FinishTime = time.Now()
 	if err := db.GetManager().VersionInfoDao().UpdateModel(version); err != nil {
 		return err
@@ -362,6 +424,12 @@ func (i *SourceCodeBuildItem) UpdateVersionInfo(vi *dbmodel.VersionInfo) error {
 
 //UpdateBuildVersionInfo update service build version info to db
 func (i *SourceCodeBuildItem) UpdateBuildVersionInfo(res *build.Response) error {
+	var imageDigest digest.Digest
+	var signed bool
+	if res.MediumType == build.ImageMediumType {
+		signer := loadSigner(i.KubeClient, i.RbdNamespace, i.SigningSecret, i.Logger)
+		imageDigest, signed = secureImage(res.MediumPath, signer, i.Logger)
+	}
 	vi := &dbmodel.VersionInfo{
 		DeliveredType: string(res.MediumType),
 		DeliveredPath: res.MediumPath,
@@ -372,6 +440,8 @@ func (i *SourceCodeBuildItem) UpdateBuildVersionInfo(res *build.Response) error
 		CodeVersion:   i.commit.Hash,
 		CommitMsg:     i.commit.Message,
 		Author:        i.commit.Author,
+		ImageDigest:   imageDigest.String(),
+		Signed:        signed,
 		FinishTime:    time.Now(),
 	}
 	if err := i.UpdateVersionInfo(vi); err != nil {
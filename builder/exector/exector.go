@@ -276,6 +276,9 @@ func (e *exectorManager) exec(task *pb.TaskMessage) error {
 func (e *exectorManager) buildFromImage(task *pb.TaskMessage) {
 	i := NewImageBuildItem(task.TaskBody)
 	i.DockerClient = e.DockerClient
+	i.KubeClient = e.KubeClient
+	i.RbdNamespace = e.cfg.RbdNamespace
+	i.SigningSecret = e.cfg.ImageSigningSecret
 	i.Logger.Info("Start with the image build application task", map[string]string{"step": "builder-exector", "status": "starting"})
 	defer event.GetManager().ReleaseLogger(i.Logger)
 	defer func() {
@@ -333,6 +336,9 @@ func (e *exectorManager) buildFromSourceCode(task *pb.TaskMessage) {
 	i.GRDataPVCName = e.cfg.GRDataPVCName
 	i.CacheMode = e.cfg.CacheMode
 	i.CachePath = e.cfg.CachePath
+	i.DockerfileBuilder = e.cfg.DockerfileBuilder
+	i.Platforms = e.cfg.BuildPlatforms
+	i.SigningSecret = e.cfg.ImageSigningSecret
 	i.Logger.Info("Build app version from source code start", map[string]string{"step": "builder-exector", "status": "starting"})
 	start := time.Now()
 	defer event.GetManager().ReleaseLogger(i.Logger)
@@ -441,6 +447,10 @@ func (e *exectorManager) sendAction(tenantID, serviceID, eventID, newVersion, ac
 	logger.Info("Build success", map[string]string{"step": "last", "status": "success"})
 	switch actionType {
 	case "upgrade":
+		// the versions built by the webhooks are deployed without the api, which checks the image policy
+		if err := checkDeployPolicy(tenantID, serviceID, newVersion, logger); err != nil {
+			return err
+		}
 		//add upgrade event
 		event := &dbmodel.ServiceEvent{
 			EventID:   util.NewUUID(),
@@ -566,6 +576,8 @@ func (e *exectorManager) garbageCollection(task *pb.TaskMessage) {
 		gci.delLogFile ()
 		// volume data
 		gci.delVolumeData ()
+		// build cache
+		gci.delBuildCache()
 	}()
 }
 
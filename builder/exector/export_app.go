@@ -93,6 +93,11 @@ func (i *ExportApp) Run(timeout time.Duration) error {
 			i.updateStatus("failed", "")
 			return err
 		}
+		if err := i.exportMultiArchImages(ram, re.PackagePath); err != nil {
+			logrus.Errorf("export images of multiple platforms failure %s", err.Error())
+			i.updateStatus("failed", "")
+			return err
+		}
 	} else if i.Format == "docker-compose" {
 		re, err = i.exportDockerCompose(*ram)
 		if err != nil {
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/gridworkz/kato/builder/cache"
 	"github.com/gridworkz/kato/cmd/builder/option"
 	eventutil "github.com/gridworkz/kato/eventlog/util"
 	"github.com/pquerna/ffjson/ffjson"
@@ -75,3 +76,15 @@ func (g *GarbageCollectionItem) delVolumeData() {
 		logrus.Warningf("dir: %s; remove volume data: %v", dir, err)
 	}
 }
+
+// delBuildCache deletes the build cache of the service.
+func (g *GarbageCollectionItem) delBuildCache() {
+	m := cache.GetManager()
+	if m == nil {
+		return
+	}
+	logrus.Infof("service id: %s; delete build cache.", g.ServiceID)
+	if _, err := m.Purge(cache.Filter{TenantID: g.TenantID, ServiceID: g.ServiceID}); err != nil {
+		logrus.Warningf("service id: %s; remove build cache: %v", g.ServiceID, err)
+	}
+}
@@ -36,11 +36,14 @@ import (
 
 	"github.com/docker/docker/client"
 	"github.com/gridworkz/kato/builder/cloudos"
+	"github.com/gridworkz/kato/builder/volumebackup"
 	dbmodel "github.com/gridworkz/kato/db/model"
 	"github.com/gridworkz/kato/event"
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -67,14 +70,85 @@ type BackupAPPNew struct {
 	DockerClient *client.Client
 
 	//full-online,full-offline
-	Mode     string `json:"mode"`
-	S3Config struct {
-		Provider   string `json:"provider"`
-		Endpoint   string `json:"endpoint"`
-		AccessKey  string `json:"access_key"`
-		SecretKey  string `json:"secret_key"`
-		BucketName string `json:"bucket_name"`
-	} `json:"s3_config"`
+	Mode     string        `json:"mode"`
+	S3Config StorageConfig `json:"s3_config"`
+	// the backup archive is encrypted with the key in the secret if it is not empty
+	EncryptionKeySecret string `json:"encryption_key_secret"`
+	// the volume data is backed up incrementally as the volume snapshots instead of the archive
+	Incremental     bool `json:"incremental"`
+	kubeClient      kubernetes.Interface
+	namespace       string
+	encryptionKey   string
+	checksum        string
+	encrypted       bool
+	volumeRepo      *volumebackup.Repository
+	volumeSnapshots []*dbmodel.AppBackupVolumeSnapshot
+}
+
+//StorageConfig the object storage of the backup archives
+type StorageConfig struct {
+	// s3, alioss, minio, local or sftp
+	Provider string `json:"provider"`
+	// the address of the storage, host:port for sftp
+	Endpoint  string `json:"endpoint"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	// the bucket, or the directory for local and sftp
+	BucketName string `json:"bucket_name"`
+	UseSSL     bool   `json:"use_ssl"`
+}
+
+func (c *StorageConfig) newCloudOSer() (cloudos.CloudOSer, error) {
+	s3Provider, err := cloudos.Str2S3Provider(c.Provider)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &cloudos.Config{
+		ProviderType: s3Provider,
+		Endpoint:     c.Endpoint,
+		AccessKey:    c.AccessKey,
+		SecretKey:    c.SecretKey,
+		BucketName:   c.BucketName,
+		UseSSL:       c.UseSSL,
+	}
+	cloudoser, err := cloudos.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating clouduser: %v", err)
+	}
+	return cloudoser, nil
+}
+
+// the key of the backup encryption key in the secret
+const encryptionKeySecretKey = "key"
+
+// loadEncryptionKey reads the backup encryption key from the secret, only the name of the secret
+// is passed in the task body, which is written into the message log of the mq.
+func loadEncryptionKey(cli kubernetes.Interface, namespace, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	secret, err := cli.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get secret %s/%s: %v", namespace, name, err)
+	}
+	key := secret.Data[encryptionKeySecretKey]
+	if len(key) == 0 {
+		return "", fmt.Errorf("no %s in secret %s/%s", encryptionKeySecretKey, namespace, name)
+	}
+	return string(key), nil
+}
+
+// newVolumeRepository creates the repository of the incremental volume backups, which is
+// the object storage for the full-online backups, or the local directory for the others.
+func newVolumeRepository(mode string, cfg StorageConfig, passphrase string) (*volumebackup.Repository, error) {
+	if mode != "full-online" {
+		return volumebackup.NewLocal(volumebackup.LocalRepository, passphrase)
+	}
+	cloudoser, err := cfg.newCloudOSer()
+	if err != nil {
+		return nil, err
+	}
+	return volumebackup.New(cloudoser, passphrase), nil
 }
 
 func init() {
@@ -89,6 +163,8 @@ func BackupAPPNewCreater(in []byte, m *exectorManager) (TaskWorker, error) {
 		Logger:       logger,
 		EventID:      eventID,
 		DockerClient: m.DockerClient,
+		kubeClient:   m.KubeClient,
+		namespace:    m.cfg.RbdNamespace,
 	}
 	if err := ffjson.Unmarshal(in, &backupNew); err != nil {
 		return nil, err
@@ -127,6 +203,12 @@ type RegionServiceSnapshot struct {
 
 //Run
 func (b *BackupAPPNew) Run(timeout time.Duration) error {
+	encryptionKey, err := loadEncryptionKey(b.kubeClient, b.namespace, b.EncryptionKeySecret)
+	if err != nil {
+		b.Logger.Error("Failed to load the backup encryption key", map[string]string{"step": "backup_builder", "status": "failure"})
+		return err
+	}
+	b.encryptionKey = encryptionKey
 	//read region group app metadata
 	metadata, err := ioutil.ReadFile(fmt.Sprintf("%s/region_apps_metadata.json", b.SourceDir))
 	if err != nil {
@@ -176,11 +258,26 @@ func (b *BackupAPPNew) Run(timeout time.Duration) error {
 		logrus.Warningf("error removing temporary direcotry: %v", err)
 	}
 	b.SourceDir = fmt.Sprintf("%s.zip", b.SourceDir)
+	if err := b.encryptPkg(); err != nil {
+		b.Logger.Error("Failed to encrypt the backup package", map[string]string{"step": "backup_builder", "status": "failure"})
+		return fmt.Errorf("error encrypting backup package: %v", err)
+	}
+	checksum, err := cloudos.FileChecksum(b.SourceDir)
+	if err != nil {
+		return fmt.Errorf("error computing checksum of backup package: %v", err)
+	}
+	b.checksum = checksum
 
 	if err := b.uploadPkg(); err != nil {
 		return fmt.Errorf("error upload backup package: %v", err)
 	}
 
+	for _, snapshot := range b.volumeSnapshots {
+		if err := db.GetManager().AppBackupVolumeSnapshotDao().AddModel(snapshot); err != nil {
+			return fmt.Errorf("error saving volume snapshot: %v", err)
+		}
+	}
+
 	if err := b.updateBackupStatu("success"); err != nil {
 		return err
 	}
@@ -198,28 +295,35 @@ func (b *BackupAPPNew) uploadPkg() error {
 		}
 	}()
 
-	s3Provider, err := cloudos.Str2S3Provider(b.S3Config.Provider)
+	cloudoser, err := b.S3Config.newCloudOSer()
 	if err != nil {
 		return err
 	}
-	cfg := &cloudos.Config{
-		ProviderType: s3Provider,
-		Endpoint:     b.S3Config.Endpoint,
-		AccessKey:    b.S3Config.AccessKey,
-		SecretKey:    b.S3Config.SecretKey,
-		BucketName:   b.S3Config.BucketName,
-	}
-	cloudoser, err := cloudos.New(cfg)
-	if err != nil {
-		return fmt.Errorf("error creating clouduser: %v", err)
-	}
 	_, filename := filepath.Split(b.SourceDir)
-	if err := cloudoser.PutObject(filename, b.SourceDir); err != nil {
+	if err := cloudoser.MultipartUpload(filename, b.SourceDir); err != nil {
 		return fmt.Errorf("object key: %s; filepath: %s; error putting object: %v", filename, b.SourceDir, err)
 	}
 	return nil
 }
 
+//encryptPkg encrypts the backup package if the encryption key is set
+func (b *BackupAPPNew) encryptPkg() error {
+	if b.encryptionKey == "" {
+		return nil
+	}
+	encrypted := b.SourceDir + ".enc"
+	if err := cloudos.EncryptFile(b.SourceDir, encrypted, b.encryptionKey); err != nil {
+		return err
+	}
+	b.BackupSize += util.GetFileSize(encrypted) - util.GetFileSize(b.SourceDir)
+	if err := os.Remove(b.SourceDir); err != nil {
+		logrus.Warningf("error removing unencrypted backup package: %v", err)
+	}
+	b.SourceDir = encrypted
+	b.encrypted = true
+	return nil
+}
+
 // judging whether the metadata structure is old or new, the new version is v5.1.8 and later
 func judgeMetadataVersion(metadata []byte) (string, error) {
 	var appSnapshot AppSnapshot
@@ -277,6 +381,14 @@ func (b *BackupAPPNew) backupServiceInfo(serviceInfos []*RegionServiceSnapshot)
 		}
 
 		b.Logger.Info(fmt.Sprintf("Start backup application(%s) persistent data", app.Service.ServiceAlias), map[string]string{"step": "backup_builder", "status": "starting"})
+		if b.Incremental {
+			if err := b.snapshotVolumes(app); err != nil {
+				b.Logger.Error(fmt.Sprintf("Failed to backup application(%s) persistent data incrementally", app.Service.ServiceAlias), map[string]string{"step": "backup_builder", "status": "failure"})
+				return err
+			}
+			b.Logger.Info(fmt.Sprintf("Complete backup application(%s) persistent data", app.Service.ServiceAlias), map[string]string{"step": "backup_builder", "status": "success"})
+			continue
+		}
 		//backup app data,The overall data of the direct backup service
 		if len(app.ServiceVolume) > 0 {
 			dstDir := fmt.Sprintf("%s/data_%s/%s.zip", b.SourceDir, app.Service.ServiceID, "__all_data")
@@ -304,6 +416,53 @@ func (b *BackupAPPNew) backupServiceInfo(serviceInfos []*RegionServiceSnapshot)
 	return nil
 }
 
+// snapshotVolumes backs up the volumes of the component incrementally, the files unchanged
+// since the last backup point of the volume are not read again, and the existing chunks are not uploaded.
+func (b *BackupAPPNew) snapshotVolumes(app *RegionServiceSnapshot) error {
+	if b.volumeRepo == nil {
+		repo, err := newVolumeRepository(b.Mode, b.S3Config, b.encryptionKey)
+		if err != nil {
+			return fmt.Errorf("create volume repository: %v", err)
+		}
+		b.volumeRepo = repo
+	}
+	for _, volume := range app.ServiceVolume {
+		if volume.HostPath == "" || util.DirIsEmpty(volume.HostPath) {
+			continue
+		}
+		var parent string
+		if latest, err := db.GetManager().AppBackupVolumeSnapshotDao().GetLatest(app.ServiceID, volume.VolumeName); err == nil {
+			parent = latest.BackupID
+		}
+		snapshot, err := b.volumeRepo.Backup(volumebackup.VolumeKey(app.ServiceID, volume.VolumeName), b.BackupID, volume.HostPath, parent)
+		if err != nil {
+			return fmt.Errorf("backup service(%s) volume(%s) data: %v", app.ServiceID, volume.VolumeName, err)
+		}
+		logrus.Infof("backup service(%s) volume(%s) data, %d bytes of %d bytes are added", app.ServiceID, volume.VolumeName, snapshot.AddedSize, snapshot.Size)
+		b.BackupSize += snapshot.AddedSize
+		b.volumeSnapshots = append(b.volumeSnapshots, &dbmodel.AppBackupVolumeSnapshot{
+			BackupID:   b.BackupID,
+			GroupID:    b.GroupID,
+			ServiceID:  app.ServiceID,
+			VolumeName: volume.VolumeName,
+			ParentID:   snapshot.Parent,
+			FileCount:  len(snapshot.Files),
+			Size:       snapshot.Size,
+			AddedSize:  snapshot.AddedSize,
+		})
+	}
+	return nil
+}
+
+// forgetVolumeSnapshots removes the volume snapshots of the failed backup.
+func (b *BackupAPPNew) forgetVolumeSnapshots() {
+	for _, snapshot := range b.volumeSnapshots {
+		if err := b.volumeRepo.Forget(volumebackup.VolumeKey(snapshot.ServiceID, snapshot.VolumeName), b.BackupID); err != nil {
+			logrus.Warningf("forget service(%s) volume(%s) snapshot %s: %v", snapshot.ServiceID, snapshot.VolumeName, b.BackupID, err)
+		}
+	}
+}
+
 func (b *BackupAPPNew) backupPluginInfo(appSnapshot *AppSnapshot) error {
 	b.Logger.Info(fmt.Sprintf("Start backup plugin"), map[string]string{"step": "backup_builder", "status": "starting"})
 	for _, pv := range appSnapshot.PluginBuildVersions {
@@ -331,7 +490,7 @@ func (b *BackupAPPNew) checkVersionExist(version *dbmodel.VersionInfo) (bool, er
 			logrus.Errorf("new registry client error %s", err.Error())
 			return false, err
 		}
-		_, err = reg.Manifest(imageInfo.Name, imageInfo.Tag)
+		_, _, err = reg.ManifestRaw(imageInfo.Name, imageInfo.Tag)
 		if err != nil {
 			logrus.Errorf("get image %s manifest info failure, it could be not exist", version.DeliveredPath)
 			return false, err
@@ -369,6 +528,15 @@ func (b *BackupAPPNew) saveSlugPkg(app *RegionServiceSnapshot, version *dbmodel.
 func (b *BackupAPPNew) saveImagePkg(app *RegionServiceSnapshot, version *dbmodel.VersionInfo) error {
 	dstDir := fmt.Sprintf("%s/app_%s/image_%s.tar", b.SourceDir, app.ServiceID, version.BuildVersion)
 	util.CheckAndCreateDir(filepath.Dir(dstDir))
+	if ok, _ := sources.IsManifestList(version.DeliveredPath, builder.REGISTRYUSER, builder.REGISTRYPASS); ok {
+		// docker save only keeps the image of the current platform
+		if err := sources.ImageSaveOCI(version.DeliveredPath, dstDir, builder.REGISTRYUSER, builder.REGISTRYPASS); err != nil {
+			b.Logger.Error(util.Translation("save image to local dir error"), map[string]string{"step": "backup_builder", "status": "failure"})
+			logrus.Errorf("save image(%s) to local dir error when backup app, %s", version.DeliveredPath, err.Error())
+			return err
+		}
+		return nil
+	}
 	if _, err := sources.ImagePull(b.DockerClient, version.DeliveredPath, builder.REGISTRYUSER, builder.REGISTRYPASS, b.Logger, 20); err != nil {
 		b.Logger.Error(util.Translation("error pulling image"), map[string]string{"step": "backup_builder", "status": "failure"})
 		logrus.Errorf(fmt.Sprintf("image: %s; error pulling image: %v", version.DeliveredPath, err), version.DeliveredPath, err.Error())
@@ -402,6 +570,10 @@ func (b *BackupAPPNew) ErrorCallBack(err error) {
 		logrus.Errorf("backup group app failure %s", err)
 		b.Logger.Error(util.Translation("backup group app failure"), map[string]string{"step": "callback", "status": "failure"})
 		b.updateBackupStatu("failed")
+		b.forgetVolumeSnapshots()
+		if err := db.GetManager().AppBackupVolumeSnapshotDao().DeleteByBackupID(b.BackupID); err != nil {
+			logrus.Warningf("error deleting volume snapshots: %v", err)
+		}
 	}
 }
 
@@ -415,6 +587,8 @@ func (b *BackupAPPNew) updateBackupStatu(status string) error {
 	backupstatus.SourceDir = b.SourceDir
 	backupstatus.SourceType = b.SourceType
 	backupstatus.BuckupSize = b.BackupSize
+	backupstatus.Checksum = b.checksum
+	backupstatus.Encrypted = b.encrypted
 	return db.GetManager().AppBackupDao().UpdateModel(backupstatus)
 }
 
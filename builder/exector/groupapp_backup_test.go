@@ -24,6 +24,10 @@ import (
 	"github.com/gridworkz/kato/util"
 	"strings"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestUploadPkg(t *testing.T) {
@@ -74,3 +78,28 @@ func TestBackupServiceVolume(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadEncryptionKey(t *testing.T) {
+	cli := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-key", Namespace: "rbd-system"},
+		Data:       map[string][]byte{"key": []byte("passphrase")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "rbd-system"},
+	})
+	tests := []struct {
+		secret  string
+		key     string
+		wantErr bool
+	}{
+		{secret: "", key: ""},
+		{secret: "backup-key", key: "passphrase"},
+		{secret: "empty", wantErr: true},
+		{secret: "notfound", wantErr: true},
+	}
+	for _, tc := range tests {
+		key, err := loadEncryptionKey(cli, "rbd-system", tc.secret)
+		if (err != nil) != tc.wantErr || key != tc.key {
+			t.Errorf("secret %q: want key %q and error %v, got %q and %v", tc.secret, tc.key, tc.wantErr, key, err)
+		}
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/gridworkz/kato/builder/cloudos"
 	"github.com/gridworkz/kato/builder/parser"
 	"github.com/gridworkz/kato/builder/sources"
+	"github.com/gridworkz/kato/builder/volumebackup"
 	"github.com/gridworkz/kato/db"
 	"github.com/gridworkz/kato/db/errors"
 	dbmodel "github.com/gridworkz/kato/db/model"
@@ -43,6 +44,7 @@ import (
 	"github.com/pquerna/ffjson/ffjson"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	"k8s.io/client-go/kubernetes"
 )
 
 //BackupAPPRestore restrore the  group app backup
@@ -64,13 +66,15 @@ type BackupAPPRestore struct {
 	volumeIDMap   map[uint]uint
 	etcdcli       *clientv3.Client
 
-	S3Config struct {
-		Provider   string `json:"provider"`
-		Endpoint   string `json:"endpoint"`
-		AccessKey  string `json:"access_key"`
-		SecretKey  string `json:"secret_key"`
-		BucketName string `json:"bucket_name"`
-	} `json:"s3_config"`
+	S3Config StorageConfig `json:"s3_config"`
+	// the secret holding the key decrypting the backup archive if it is encrypted
+	EncryptionKeySecret string `json:"encryption_key_secret"`
+	// the backup point whose volume snapshots are restored, the restored backup if it is empty
+	RestorePoint  string `json:"restore_point"`
+	volumeRepo    *volumebackup.Repository
+	kubeClient    kubernetes.Interface
+	namespace     string
+	encryptionKey string
 }
 
 //Info service cache info
@@ -94,6 +98,8 @@ func BackupAPPRestoreCreater(in []byte, m *exectorManager) (TaskWorker, error) {
 		EventID:       eventID,
 		DockerClient:  m.DockerClient,
 		etcdcli:       m.EtcdCli,
+		kubeClient:    m.KubeClient,
+		namespace:     m.cfg.RbdNamespace,
 		serviceChange: make(map[string]*Info, 0),
 		volumeIDMap:   make(map[uint]uint),
 	}
@@ -113,6 +119,12 @@ func (b *BackupAPPRestore) Run(timeout time.Duration) error {
 	if backup.Status != "success" || backup.SourceDir == "" || backup.BackupMode == "" {
 		return fmt.Errorf("backup can not be restore")
 	}
+	encryptionKey, err := loadEncryptionKey(b.kubeClient, b.namespace, b.EncryptionKeySecret)
+	if err != nil {
+		b.Logger.Error("Failed to load the backup encryption key", map[string]string{"step": "backup_builder", "status": "failure"})
+		return err
+	}
+	b.encryptionKey = encryptionKey
 
 	cacheDir := fmt.Sprintf("/grdata/cache/tmp/%s/%s", b.BackupID, util.NewUUID())
 	if err := util.CheckAndCreateDir(cacheDir); err != nil {
@@ -121,11 +133,13 @@ func (b *BackupAPPRestore) Run(timeout time.Duration) error {
 	b.cacheDir = cacheDir
 	switch backup.BackupMode {
 	case "full-online":
-		if err := b.downloadFromS3(backup.SourceDir); err != nil {
+		if err := b.downloadFromS3(backup); err != nil {
 			return fmt.Errorf("error downloading file from s3: %v", err)
 		}
 	default:
-		b.downloadFromLocal(backup)
+		if err := b.downloadFromLocal(backup); err != nil {
+			return err
+		}
 	}
 
 	//read metadata file
@@ -220,7 +234,19 @@ func (b *BackupAPPRestore) restoreVersionAndData(backup *dbmodel.AppBackup, appS
 				continue
 			}
 			var tmpDir string
-			if !allDataRestore {
+			if backup.Incremental {
+				tmpDir = fmt.Sprintf("/grdata/tmp/%s_%d", volume.ServiceID, volume.ID)
+				restored, err := b.restoreVolumeSnapshot(backup, app, volume, tmpDir)
+				if err != nil {
+					logrus.Errorf("restore service(%s) volume(%s) data error.%s", app.ServiceID, volume.VolumeName, err.Error())
+					return err
+				}
+				if !restored {
+					//the volume is empty when it is backed up
+					os.MkdirAll(volume.HostPath, 0777)
+					continue
+				}
+			} else if !allDataRestore {
 				dstDir := fmt.Sprintf("%s/data_%s/%s.zip", b.cacheDir, b.getOldServiceID(app.ServiceID), strings.Replace(volume.VolumeName, "/", "", -1))
 				tmpDir = fmt.Sprintf("/grdata/tmp/%s_%d", volume.ServiceID, volume.ID)
 				logrus.Infof("unzip %s to %s", dstDir, tmpDir)
@@ -333,6 +359,43 @@ func (b *BackupAPPRestore) restoreVersionAndData(backup *dbmodel.AppBackup, appS
 	return nil
 }
 
+// restoreVolumeSnapshot restores the volume snapshot of the restore point into the tmpDir, in the same layout
+// as the volume archive. It returns false if the volume is empty at the restore point, that is the backup point
+// records no snapshot of the volume, a recorded snapshot missing in the repository fails the restore.
+func (b *BackupAPPRestore) restoreVolumeSnapshot(backup *dbmodel.AppBackup, app *RegionServiceSnapshot, volume *dbmodel.TenantServiceVolume, tmpDir string) (bool, error) {
+	if b.volumeRepo == nil {
+		repo, err := newVolumeRepository(backup.BackupMode, b.S3Config, b.encryptionKey)
+		if err != nil {
+			return false, fmt.Errorf("create volume repository: %v", err)
+		}
+		b.volumeRepo = repo
+	}
+	point := b.RestorePoint
+	if point == "" {
+		point = backup.BackupID
+	}
+	oldServiceID := b.getOldServiceID(app.ServiceID)
+	volumeKey := volumebackup.VolumeKey(oldServiceID, volume.VolumeName)
+	logrus.Infof("restore volume %s of backup point %s to %s", volumeKey, point, tmpDir)
+	if _, err := b.volumeRepo.Restore(volumeKey, point, tmpDir); err != nil {
+		if err != volumebackup.ErrSnapshotNotFound {
+			return false, err
+		}
+		snapshots, err := db.GetManager().AppBackupVolumeSnapshotDao().ListByBackupID(point)
+		if err != nil {
+			return false, fmt.Errorf("list volume snapshots of backup point %s: %v", point, err)
+		}
+		for _, snapshot := range snapshots {
+			if snapshot.ServiceID == oldServiceID && snapshot.VolumeName == volume.VolumeName {
+				return false, fmt.Errorf("the snapshot of volume %s at backup point %s is missing in the repository", volumeKey, point)
+			}
+		}
+		// the empty volume is not backed up
+		return false, nil
+	}
+	return true, nil
+}
+
 func (b *BackupAPPRestore) getOldServiceID(new string) string {
 	for k, v := range b.serviceChange {
 		if v.ServiceID == new {
@@ -353,16 +416,25 @@ func (b *BackupAPPRestore) downloadSlug(backup *dbmodel.AppBackup, app *RegionSe
 
 func (b *BackupAPPRestore) downloadImage(backup *dbmodel.AppBackup, app *RegionServiceSnapshot, version *dbmodel.VersionInfo) error {
 	dstDir := fmt.Sprintf("%s/app_%s/image_%s.tar", b.cacheDir, b.getOldServiceID(app.ServiceID), version.BuildVersion)
-	if err := sources.ImageLoad(b.DockerClient, dstDir, b.Logger); err != nil {
-		b.Logger.Error(util.Translation("load image to local hub error"), map[string]string{"step": "restore_builder", "status": "failure"})
-		logrus.Errorf("load image to local hub error when restore backup app, %s", err.Error())
-		return err
-	}
 	imageName := version.ImageName
 	if imageName == "" {
 		imageName = version.DeliveredPath
 	}
 	newImageName := getNewImageName(imageName)
+	if sources.IsOCIArchive(dstDir) {
+		// the image of multiple platforms is pushed to the local hub directly
+		if err := sources.ImageLoadOCI(dstDir, newImageName, builder.REGISTRYUSER, builder.REGISTRYPASS); err != nil {
+			b.Logger.Error(util.Translation("push image to local hub error"), map[string]string{"step": "restore_builder", "status": "failure"})
+			logrus.Errorf("push image to local hub error when restore backup app, %s", err.Error())
+			return err
+		}
+		return nil
+	}
+	if err := sources.ImageLoad(b.DockerClient, dstDir, b.Logger); err != nil {
+		b.Logger.Error(util.Translation("load image to local hub error"), map[string]string{"step": "restore_builder", "status": "failure"})
+		logrus.Errorf("load image to local hub error when restore backup app, %s", err.Error())
+		return err
+	}
 	if newImageName != imageName {
 		if err := sources.ImageTag(b.DockerClient, imageName, newImageName, b.Logger, 3); err != nil {
 			b.Logger.Error(util.Translation("change image tag error"), map[string]string{"step": "restore_builder", "status": "failure"})
@@ -680,60 +752,69 @@ func (b *BackupAPPRestore) restoreMetadata(appSnapshot *AppSnapshot) error {
 }
 
 func (b *BackupAPPRestore) downloadFromLocal(backup *dbmodel.AppBackup) error {
-	sourceDir := backup.SourceDir
-	err := util.Unzip(sourceDir, b.cacheDir)
-	if err != nil {
-		b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
-		logrus.Errorf("unzip file error when restore backup app , %s", err.Error())
-		return err
-	}
-	dirs, err := util.GetDirNameList(b.cacheDir, 1)
-	if err != nil || len(dirs) < 1 {
-		b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
-		return fmt.Errorf("find metadata cache dir error after unzip file")
-	}
-	b.cacheDir = filepath.Join(b.cacheDir, dirs[0])
-	return nil
+	return b.unpack(backup, backup.SourceDir)
 }
 
-func (b *BackupAPPRestore) downloadFromS3(sourceDir string) error {
-	s3Provider, err := cloudos.Str2S3Provider(b.S3Config.Provider)
+func (b *BackupAPPRestore) downloadFromS3(backup *dbmodel.AppBackup) error {
+	cloudoser, err := b.S3Config.newCloudOSer()
 	if err != nil {
 		return err
 	}
-	cfg := &cloudos.Config{
-		ProviderType: s3Provider,
-		Endpoint:     b.S3Config.Endpoint,
-		AccessKey:    b.S3Config.AccessKey,
-		SecretKey:    b.S3Config.SecretKey,
-		BucketName:   b.S3Config.BucketName,
-	}
-	cloudoser, err := cloudos.New(cfg)
-	if err != nil {
-		return fmt.Errorf("error creating clouduser: %v", err)
-	}
 
-	_, objectKey := filepath.Split(sourceDir)
+	_, objectKey := filepath.Split(backup.SourceDir)
+	if _, err := cloudoser.StatObject(objectKey); err != nil {
+		if err == cloudos.ErrObjectNotFound {
+			b.Logger.Error("The backup package is not found in the object storage", map[string]string{"step": "backup_builder", "status": "failure"})
+		}
+		return fmt.Errorf("object key: %s; error getting object: %v", objectKey, err)
+	}
 	disDir := path.Join(b.cacheDir, objectKey)
 	logrus.Debugf("object key: %s; file path: %s; start downloading backup file.", objectKey, disDir)
 	if err := cloudoser.GetObject(objectKey, disDir); err != nil {
 		return fmt.Errorf("object key: %s; file path: %s; error downloading file for object storage: %v", objectKey, disDir, err)
 	}
 	logrus.Debugf("successfully downloading backup file: %s", disDir)
+	defer os.Remove(disDir)
+	return b.unpack(backup, disDir)
+}
 
-	err = util.Unzip(disDir, b.cacheDir)
-	if err != nil {
-		// b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
+//unpack verifies the checksum of the backup package, decrypts it if it is encrypted, and unzips it into the cache dir
+func (b *BackupAPPRestore) unpack(backup *dbmodel.AppBackup, pkg string) error {
+	// the backups created before the checksum is introduced have no checksum
+	if backup.Checksum != "" {
+		checksum, err := cloudos.FileChecksum(pkg)
+		if err != nil {
+			return fmt.Errorf("error computing checksum of backup package: %v", err)
+		}
+		if checksum != backup.Checksum {
+			b.Logger.Error("The checksum of the backup package mismatches, it may be corrupted", map[string]string{"step": "backup_builder", "status": "failure"})
+			return fmt.Errorf("checksum of backup package %s mismatches, expected %s, got %s", pkg, backup.Checksum, checksum)
+		}
+	}
+	if backup.Encrypted {
+		if b.encryptionKey == "" {
+			b.Logger.Error("The backup package is encrypted, the encryption key is required", map[string]string{"step": "backup_builder", "status": "failure"})
+			return fmt.Errorf("the encryption key of backup %s is required", backup.BackupID)
+		}
+		decrypted := path.Join(b.cacheDir, strings.TrimSuffix(filepath.Base(pkg), ".enc")+".dec")
+		if err := cloudos.DecryptFile(pkg, decrypted, b.encryptionKey); err != nil {
+			b.Logger.Error("Failed to decrypt the backup package, the encryption key may be wrong", map[string]string{"step": "backup_builder", "status": "failure"})
+			return fmt.Errorf("error decrypting backup package: %v", err)
+		}
+		defer os.Remove(decrypted)
+		pkg = decrypted
+	}
+
+	if err := util.Unzip(pkg, b.cacheDir); err != nil {
+		b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
 		logrus.Errorf("error unzipping backup file: %v", err)
 		return err
 	}
-
 	dirs, err := util.GetDirNameList(b.cacheDir, 1)
 	if err != nil || len(dirs) < 1 {
-		// b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
+		b.Logger.Error(util.Translation("unzip metadata file error"), map[string]string{"step": "backup_builder", "status": "failure"})
 		return fmt.Errorf("find metadata cache dir error after unzip file")
 	}
-
 	b.cacheDir = filepath.Join(b.cacheDir, dirs[0])
 	return nil
 }
@@ -0,0 +1,149 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package exector
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/gridworkz/kato/builder"
+	"github.com/gridworkz/kato/builder/signature"
+	"github.com/gridworkz/kato/builder/sources"
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/event"
+	"github.com/jinzhu/gorm"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+//loadImagePolicy returns the image policy of the tenant, nil if the tenant has no policy
+func loadImagePolicy(tenantID string) (*signature.Policy, error) {
+	p, err := db.GetManager().TenantImagePolicyDao().GetByTenantID(tenantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return signature.NewPolicy(p)
+}
+
+//checkDeployPolicy checks the version built for the component against the image policy of the tenant before
+//the builder upgrades the component with it, as the api does when the component is deployed by the user.
+//The violation is written into the event log, and an error is returned if the policy is enforced.
+func checkDeployPolicy(tenantID, serviceID, deployVersion string, logger event.Logger) error {
+	policy, err := loadImagePolicy(tenantID)
+	if err != nil {
+		return fmt.Errorf("load image policy of tenant %s: %v", tenantID, err)
+	}
+	if policy == nil {
+		return nil
+	}
+	version, err := db.GetManager().VersionInfoDao().GetVersionByDeployVersion(deployVersion, serviceID)
+	if err != nil {
+		return fmt.Errorf("get version %s of component %s: %v", deployVersion, serviceID, err)
+	}
+	violation := policy.CheckVersion(version)
+	if violation == nil {
+		return nil
+	}
+	if policy.Enforced() {
+		logger.Error(violation.Error()+", the deployment is refused", map[string]string{"step": "image-policy", "status": "failure"})
+		return violation
+	}
+	logger.Error(violation.Error()+", it is allowed in audit mode", map[string]string{"step": "image-policy"})
+	return nil
+}
+
+//loadSigner loads the platform signing key, nil is returned if the key is not configured or invalid,
+//the images are not signed in that case.
+func loadSigner(kubeClient kubernetes.Interface, namespace, secret string, logger event.Logger) *signature.Signer {
+	if kubeClient == nil || secret == "" {
+		return nil
+	}
+	signer, err := signature.LoadSigner(kubeClient, namespace, secret)
+	if err != nil {
+		if err != signature.ErrNoSigningKey {
+			logrus.Errorf("load image signing key: %v", err)
+			logger.Error(fmt.Sprintf("load image signing key failure: %v", err), map[string]string{"step": "image-sign"})
+		}
+		return nil
+	}
+	return signer
+}
+
+//checkImagePolicy checks the image against the image policy of the tenant, the violation is nil if the image
+//passes. The public key of the platform signer is trusted besides the keys of the tenant.
+//The verified image is returned as 'repository@digest' with the signer verifying it, see signature.Policy.TrustsSigner,
+//they are empty if no signature is required.
+func checkImagePolicy(policy *signature.Policy, image, user, password string, signer *signature.Signer) (*signature.Violation, string, string, error) {
+	if !policy.IsTrustedRegistry(image) {
+		return &signature.Violation{Image: image, Reason: "the registry is not trusted"}, "", "", nil
+	}
+	if !policy.RequireSignature {
+		return nil, "", "", nil
+	}
+	keys := append([]*ecdsa.PublicKey{}, policy.Keys...)
+	if signer != nil {
+		keys = append(keys, signer.PublicKey())
+	}
+	dgst, key, err := sources.VerifyImage(image, user, password, keys)
+	switch err {
+	case nil:
+	case signature.ErrNotSigned, signature.ErrUntrusted:
+		return &signature.Violation{Image: image, Reason: err.Error()}, "", "", nil
+	default:
+		return nil, "", "", err
+	}
+	verified, err := imageWithDigest(image, dgst)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if signer != nil && key == signer.PublicKey() {
+		return nil, verified, signature.PlatformSigner, nil
+	}
+	return nil, verified, signature.KeyFingerprint(key), nil
+}
+
+//imageWithDigest returns the reference of the image pinned to the digest, such as 'docker.io/library/nginx@sha256:<hex>'
+func imageWithDigest(image string, dgst digest.Digest) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := reference.WithDigest(reference.TrimNamed(named), dgst)
+	if err != nil {
+		return "", err
+	}
+	return canonical.String(), nil
+}
+
+//secureImage attaches the SBOM to the image pushed into the platform registry and signs it,
+//the image digest is returned with whether the image is signed.
+func secureImage(image string, signer *signature.Signer, logger event.Logger) (digest.Digest, bool) {
+	dgst, err := sources.SecureImage(image, builder.REGISTRYUSER, builder.REGISTRYPASS, signer, logger)
+	if err != nil {
+		logrus.Errorf("secure image %s: %v", image, err)
+		logger.Error(fmt.Sprintf("sign image %s failure: %v", image, err), map[string]string{"step": "image-sign"})
+		return dgst, false
+	}
+	return dgst, signer != nil
+}
@@ -0,0 +1,116 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package exector
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/db/dao"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/event"
+	mqclient "github.com/gridworkz/kato/mq/client"
+)
+
+type fakeDBManager struct {
+	db.Manager
+	policy   *dbmodel.TenantImagePolicy
+	versions map[string]*dbmodel.VersionInfo
+}
+
+func (m *fakeDBManager) TenantImagePolicyDao() dao.TenantImagePolicyDao {
+	return &fakeImagePolicyDao{policy: m.policy}
+}
+
+func (m *fakeDBManager) VersionInfoDao() dao.VersionInfoDao {
+	return &fakeVersionInfoDao{versions: m.versions}
+}
+
+func (m *fakeDBManager) ServiceEventDao() dao.EventDao {
+	return &fakeEventDao{}
+}
+
+type fakeImagePolicyDao struct {
+	dao.TenantImagePolicyDao
+	policy *dbmodel.TenantImagePolicy
+}
+
+func (d *fakeImagePolicyDao) GetByTenantID(tenantID string) (*dbmodel.TenantImagePolicy, error) {
+	return d.policy, nil
+}
+
+type fakeVersionInfoDao struct {
+	dao.VersionInfoDao
+	versions map[string]*dbmodel.VersionInfo
+}
+
+func (d *fakeVersionInfoDao) GetVersionByDeployVersion(version, serviceID string) (*dbmodel.VersionInfo, error) {
+	return d.versions[version], nil
+}
+
+type fakeEventDao struct {
+	dao.EventDao
+}
+
+func (d *fakeEventDao) AddModel(m dbmodel.Interface) error {
+	return nil
+}
+
+type fakeMQClient struct {
+	mqclient.MQClient
+	tasks []mqclient.TaskStruct
+}
+
+func (c *fakeMQClient) SendBuilderTopic(t mqclient.TaskStruct) error {
+	c.tasks = append(c.tasks, t)
+	return nil
+}
+
+func TestSendActionImagePolicy(t *testing.T) {
+	versions := map[string]*dbmodel.VersionInfo{
+		// built by the webhook while the image signing key is not configured
+		"unsigned": {Kind: "build_from_source_code", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:unsigned"},
+		"signed":   {Kind: "build_from_source_code", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:signed", Signed: true},
+	}
+	tests := []struct {
+		name     string
+		mode     string
+		version  string
+		upgraded bool
+	}{
+		{name: "unsigned build under enforce", mode: dbmodel.ImagePolicyModeEnforce, version: "unsigned"},
+		{name: "signed build under enforce", mode: dbmodel.ImagePolicyModeEnforce, version: "signed", upgraded: true},
+		{name: "unsigned build under audit", mode: dbmodel.ImagePolicyModeAudit, version: "unsigned", upgraded: true},
+	}
+	for _, tc := range tests {
+		db.SetTestManager(&fakeDBManager{
+			policy:   &dbmodel.TenantImagePolicy{TenantID: "tenant1", Mode: tc.mode, RequireSignature: true},
+			versions: versions,
+		})
+		mqClient := &fakeMQClient{}
+		e := &exectorManager{mqClient: mqClient}
+		err := e.sendAction("tenant1", "service1", "event1", tc.version, "upgrade", nil, event.GetTestLogger())
+		if tc.upgraded != (err == nil) {
+			t.Errorf("%s: want upgraded %v, got error %v", tc.name, tc.upgraded, err)
+		}
+		if tc.upgraded != (len(mqClient.tasks) == 1) {
+			t.Errorf("%s: want upgraded %v, got tasks %+v", tc.name, tc.upgraded, mqClient.tasks)
+		}
+	}
+}
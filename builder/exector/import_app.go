@@ -136,6 +136,11 @@ func (i *ImportApp) importApp() error {
 				i.updateStatusForApp(app, "failed")
 				return
 			}
+			if err := i.importMultiArchImages(appFile, tmpDir, ram); err != nil {
+				logrus.Errorf("Failed to load images of multiple platforms of app %s: %v", appFile, err)
+				i.updateStatusForApp(app, "failed")
+				return
+			}
 			os.Rename(appFile, appFile+".success")
 			datas = append(datas, *ram)
 			logrus.Infof("Successful import app: %s", appFile)
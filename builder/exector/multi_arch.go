@@ -0,0 +1,394 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package exector
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gridworkz/kato-oam/pkg/ram/v1alpha1"
+	"github.com/gridworkz/kato/builder"
+	"github.com/gridworkz/kato/builder/sources"
+	"github.com/sirupsen/logrus"
+)
+
+//multiArchDir the app package exported by kato-oam saves the images with docker save, which only keeps the image
+//of the current platform, so the images of multiple platforms are saved in this directory of the package
+//in the OCI image layout, with the index file multiArchIndex.
+const (
+	multiArchDir   = "multi-arch"
+	multiArchIndex = "index.json"
+)
+
+//multiArchImage is the image of multiple platforms saved in the app package
+type multiArchImage struct {
+	// component or plugin
+	Kind string `json:"kind"`
+	// the index of the component or plugin in the app metadata
+	Index int    `json:"index"`
+	Image string `json:"image"`
+	File  string `json:"file"`
+}
+
+//exportMultiArchImages saves the images of multiple platforms of the app into the package
+func (i *ExportApp) exportMultiArchImages(ram *v1alpha1.KatoApplicationConfig, packagePath string) error {
+	dir := filepath.Join(i.SourceDir, multiArchDir)
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var images []multiArchImage
+	save := func(kind string, index int, image, user, pass string) error {
+		if image == "" {
+			return nil
+		}
+		if ok, err := sources.IsManifestList(image, user, pass); err != nil || !ok {
+			if err != nil {
+				logrus.Warningf("check the manifest list of image %s: %v", image, err)
+			}
+			return nil
+		}
+		file := fmt.Sprintf("%s-%d.tar", kind, index)
+		if err := sources.ImageSaveOCI(image, filepath.Join(dir, file), user, pass); err != nil {
+			return fmt.Errorf("save image %s of multiple platforms: %v", image, err)
+		}
+		i.Logger.Info(fmt.Sprintf("Save image %s of multiple platforms", image), map[string]string{"step": "export-app"})
+		images = append(images, multiArchImage{Kind: kind, Index: index, Image: image, File: file})
+		return nil
+	}
+	for index, com := range ram.Components {
+		if err := save("component", index, com.ShareImage, com.AppImage.HubUser, com.AppImage.HubPassword); err != nil {
+			return err
+		}
+	}
+	for index, plugin := range ram.Plugins {
+		if err := save("plugin", index, plugin.ShareImage, plugin.PluginImage.HubUser, plugin.PluginImage.HubPassword); err != nil {
+			return err
+		}
+	}
+	if len(images) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(images)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, multiArchIndex), body, 0644); err != nil {
+		return err
+	}
+	return addToPackage(packagePath, dir)
+}
+
+//importMultiArchImages pushes the images of multiple platforms saved in the package over the images
+//imported by kato-oam, which only contain the current platform.
+func (i *ImportApp) importMultiArchImages(appFile, tmpDir string, ram *v1alpha1.KatoApplicationConfig) error {
+	dir := filepath.Join(tmpDir, multiArchDir)
+	defer os.RemoveAll(dir)
+	if err := extractFromPackage(appFile, dir); err != nil {
+		return err
+	}
+	body, err := ioutil.ReadFile(filepath.Join(dir, multiArchIndex))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var images []multiArchImage
+	if err := json.Unmarshal(body, &images); err != nil {
+		return fmt.Errorf("parse the index of images of multiple platforms: %v", err)
+	}
+	for _, image := range images {
+		var target string
+		switch {
+		case image.Kind == "component" && image.Index >= 0 && image.Index < len(ram.Components):
+			target = ram.Components[image.Index].ShareImage
+		case image.Kind == "plugin" && image.Index >= 0 && image.Index < len(ram.Plugins):
+			target = ram.Plugins[image.Index].ShareImage
+		}
+		if target == "" {
+			return fmt.Errorf("the %s of image %s is not found in the app", image.Kind, image.Image)
+		}
+		user, pass := builder.GetImageUserInfoV2(target, i.ServiceImage.HubUser, i.ServiceImage.HubPassword)
+		if err := sources.ImageLoadOCI(filepath.Join(dir, path.Base(image.File)), target, user, pass); err != nil {
+			return fmt.Errorf("push image %s of multiple platforms: %v", target, err)
+		}
+		logrus.Infof("push image %s of multiple platforms as %s", image.Image, target)
+	}
+	return nil
+}
+
+//addToPackage rewrites the app package with the files in dir added into the multiArchDir, which is put into
+//the top directory of the package if all the files of the package are in it. The package is a zip or a tar file.
+func addToPackage(packagePath, dir string) error {
+	format, err := packageFormat(packagePath)
+	if err != nil {
+		return err
+	}
+	tmp := packagePath + ".tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer dst.Close()
+	var w packageWriter
+	switch format {
+	case "zip":
+		w = newZipPackageWriter(dst)
+	default:
+		w = newTarPackageWriter(dst, format == "tar.gz")
+	}
+	var top string
+	topDir, first := true, true
+	err = walkPackage(packagePath, func(name string, info os.FileInfo, r io.Reader) error {
+		parts := strings.SplitN(strings.TrimPrefix(path.Clean(name), "./"), "/", 2)
+		if first {
+			top, first = parts[0], false
+		}
+		if parts[0] != top || (len(parts) == 1 && !info.IsDir()) {
+			topDir = false
+		}
+		return w.add(name, info, r)
+	})
+	if err != nil {
+		return fmt.Errorf("rewrite package %s: %v", packagePath, err)
+	}
+	prefix := multiArchDir
+	if topDir && top != "" && top != "." {
+		prefix = path.Join(top, multiArchDir)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range files {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if err := addFileToPackage(w, filepath.Join(dir, info.Name()), prefix+"/"+info.Name(), info); err != nil {
+			return err
+		}
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, packagePath)
+}
+
+func addFileToPackage(w packageWriter, file, name string, info os.FileInfo) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return w.add(name, info, f)
+}
+
+//extractFromPackage extracts the files in the multiArchDir of the app package into dir
+func extractFromPackage(packagePath, dir string) error {
+	return walkPackage(packagePath, func(name string, info os.FileInfo, r io.Reader) error {
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		parts := strings.Split(strings.TrimPrefix(path.Clean(name), "./"), "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[len(parts)-2] != multiArchDir {
+			return nil
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		// only the base name is used, so the files can not be written out of dir
+		f, err := os.Create(filepath.Join(dir, parts[len(parts)-1]))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, r)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	})
+}
+
+//packageFormat returns zip, tar.gz or tar by the magic number of the app package
+func packageFormat(packagePath string) (string, error) {
+	f, err := os.Open(packagePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return "", fmt.Errorf("read package %s: %v", packagePath, err)
+	}
+	switch {
+	case string(magic) == "PK\x03\x04":
+		return "zip", nil
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		return "tar.gz", nil
+	default:
+		return "tar", nil
+	}
+}
+
+//walkPackage calls fn with every entry of the app package in order
+func walkPackage(packagePath string, fn func(name string, info os.FileInfo, r io.Reader) error) error {
+	format, err := packageFormat(packagePath)
+	if err != nil {
+		return err
+	}
+	if format == "zip" {
+		zr, err := zip.OpenReader(packagePath)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		for _, file := range zr.File {
+			rc, err := file.Open()
+			if err != nil {
+				return err
+			}
+			err = fn(file.Name, file.FileInfo(), rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	f, err := os.Open(packagePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if format == "tar.gz" {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(header.Name, header.FileInfo(), tr); err != nil {
+			return err
+		}
+	}
+}
+
+//packageWriter writes the entries of the rewritten app package
+type packageWriter interface {
+	add(name string, info os.FileInfo, r io.Reader) error
+	close() error
+}
+
+type tarPackageWriter struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+func newTarPackageWriter(w io.Writer, gzipped bool) *tarPackageWriter {
+	p := &tarPackageWriter{}
+	if gzipped {
+		p.gw = gzip.NewWriter(w)
+		w = p.gw
+	}
+	p.tw = tar.NewWriter(w)
+	return p
+}
+
+func (p *tarPackageWriter) add(name string, info os.FileInfo, r io.Reader) error {
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := p.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if info.Mode().IsRegular() {
+		_, err = io.Copy(p.tw, r)
+	}
+	return err
+}
+
+func (p *tarPackageWriter) close() error {
+	if err := p.tw.Close(); err != nil {
+		return err
+	}
+	if p.gw != nil {
+		return p.gw.Close()
+	}
+	return nil
+}
+
+type zipPackageWriter struct {
+	zw *zip.Writer
+}
+
+func newZipPackageWriter(w io.Writer) *zipPackageWriter {
+	return &zipPackageWriter{zw: zip.NewWriter(w)}
+}
+
+func (p *zipPackageWriter) add(name string, info os.FileInfo, r io.Reader) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name = strings.TrimSuffix(name, "/") + "/"
+	} else {
+		header.Method = zip.Deflate
+	}
+	w, err := p.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if info.Mode().IsRegular() {
+		_, err = io.Copy(w, r)
+	}
+	return err
+}
+
+func (p *zipPackageWriter) close() error {
+	return p.zw.Close()
+}
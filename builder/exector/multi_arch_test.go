@@ -0,0 +1,135 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package exector
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMultiArchPackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "multiarch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	images := filepath.Join(dir, "images")
+	if err := os.MkdirAll(images, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(images, multiArchIndex), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(images, "component-0.tar"), []byte("oci"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]string{"app/metadata.json": "{}", "app/images/component.tar": "docker"}
+	writeTar := func(file string, gzipped bool) {
+		f, err := os.Create(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var w io.Writer = f
+		if gzipped {
+			gw := gzip.NewWriter(f)
+			defer gw.Close()
+			w = gw
+		}
+		tw := tar.NewWriter(w)
+		defer tw.Close()
+		tw.WriteHeader(&tar.Header{Name: "app/", Typeflag: tar.TypeDir, Mode: 0755})
+		for name, content := range entries {
+			tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+			tw.Write([]byte(content))
+		}
+	}
+	writeZip := func(file string) {
+		f, err := os.Create(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		zw := zip.NewWriter(f)
+		defer zw.Close()
+		zw.Create("app/")
+		for name, content := range entries {
+			w, _ := zw.Create(name)
+			w.Write([]byte(content))
+		}
+	}
+	tests := []struct {
+		name   string
+		format string
+		write  func(file string)
+	}{
+		{name: "app.tar.gz", format: "tar.gz", write: func(file string) { writeTar(file, true) }},
+		{name: "app.tar", format: "tar", write: func(file string) { writeTar(file, false) }},
+		{name: "app.zip", format: "zip", write: writeZip},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(dir, tc.name)
+			tc.write(file)
+			if err := addToPackage(file, images); err != nil {
+				t.Fatal(err)
+			}
+			if format, _ := packageFormat(file); format != tc.format {
+				t.Errorf("want format %s, but got %s", tc.format, format)
+			}
+			got := make(map[string]string)
+			err := walkPackage(file, func(name string, info os.FileInfo, r io.Reader) error {
+				if info.Mode().IsRegular() {
+					content, err := ioutil.ReadAll(r)
+					got[name] = string(content)
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"app/multi-arch/index.json": "[]", "app/multi-arch/component-0.tar": "oci"}
+			for name, content := range entries {
+				want[name] = content
+			}
+			if len(got) != len(want) {
+				t.Errorf("want entries %v, but got %v", want, got)
+			}
+			for name, content := range want {
+				if got[name] != content {
+					t.Errorf("want %s with %q, but got %q", name, content, got[name])
+				}
+			}
+			out := filepath.Join(dir, tc.name+"-out")
+			if err := extractFromPackage(file, out); err != nil {
+				t.Fatal(err)
+			}
+			if content, err := ioutil.ReadFile(filepath.Join(out, "component-0.tar")); err != nil || string(content) != "oci" {
+				t.Errorf("want the extracted image, but got %q, %v", content, err)
+			}
+		})
+	}
+}
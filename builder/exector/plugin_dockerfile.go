@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/gridworkz/kato/builder"
+	"github.com/gridworkz/kato/builder/build"
 	"github.com/gridworkz/kato/builder/sources"
 	"github.com/gridworkz/kato/util"
 
@@ -105,6 +106,12 @@ func (e *exectorManager) runD(t *model.BuildPluginTaskBody, logger event.Logger)
 	mm := strings.Split(t.GitURL, "/")
 	n1 := strings.Split(mm[len(mm)-1], ".")[0]
 	buildImageName := fmt.Sprintf(builder.REGISTRYDOMAIN+"/plugin_%s_%s:%s", n1, t.PluginID, t.DeployVersion)
+	if e.cfg.DockerfileBuilder == build.DockerfileBuilderJob {
+		if err := e.runDInJob(t, sourceDir, buildImageName, logger); err != nil {
+			return err
+		}
+		return e.completePluginBuild(t, buildImageName, logger)
+	}
 	buildOptions := types.ImageBuildOptions{
 		Tags:   []string{buildImageName},
 		Remove: true,
@@ -129,6 +136,41 @@ func (e *exectorManager) runD(t *model.BuildPluginTaskBody, logger event.Logger)
 		return err
 	}
 	logger.Info("push image success", map[string]string{"step": "build-exector"})
+	return e.completePluginBuild(t, buildImageName, logger)
+}
+
+//runDInJob builds the plugin dockerfile in a kubernetes job, which does not need docker daemon
+func (e *exectorManager) runDInJob(t *model.BuildPluginTaskBody, sourceDir, buildImageName string, logger event.Logger) error {
+	buildEnvs := make(map[string]string)
+	if noCache := os.Getenv("NO_CACHE"); noCache != "" {
+		buildEnvs["NO_CACHE"] = noCache
+	}
+	re := &build.Request{
+		RbdNamespace:  e.cfg.RbdNamespace,
+		CachePVCName:  e.cfg.CachePVCName,
+		CacheMode:     e.cfg.CacheMode,
+		CachePath:     e.cfg.CachePath,
+		TenantID:      t.TenantID,
+		SourceDir:     sourceDir,
+		ServiceID:     t.PluginID,
+		DeployVersion: t.DeployVersion,
+		BuildEnvs:     buildEnvs,
+		Platforms:     e.cfg.BuildPlatforms,
+		Logger:        logger,
+		KubeClient:    e.KubeClient,
+		Ctx:           e.ctx,
+	}
+	logger.Info("start build image in job", map[string]string{"step": "builder-exector"})
+	if err := build.BuildDockerfileByJob(re, buildImageName); err != nil {
+		logger.Error(fmt.Sprintf("build image %s failure", buildImageName), map[string]string{"step": "builder-exector", "status": "failure"})
+		logrus.Errorf("[plugin]build image in job error: %s", err.Error())
+		return err
+	}
+	logger.Info("build image and push it to local image registry success", map[string]string{"step": "build-exector"})
+	return nil
+}
+
+func (e *exectorManager) completePluginBuild(t *model.BuildPluginTaskBody, buildImageName string, logger event.Logger) error {
 	version, err := db.GetManager().TenantPluginBuildVersionDao().GetBuildVersionByDeployVersion(t.PluginID, t.VersionID, t.DeployVersion)
 	if err != nil {
 		logrus.Errorf("get version error, %v", err)
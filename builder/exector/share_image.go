@@ -80,6 +80,9 @@ func NewImageShareItem(in []byte, DockerClient *client.Client, EtcdCli *clientv3
 //ShareService
 func (i *ImageShareItem) ShareService() error {
 	hubuser, hubpass := builder.GetImageUserInfoV2(i.LocalImageName, i.LocalImageUsername, i.LocalImagePassword)
+	if ok, _ := sources.IsManifestList(i.LocalImageName, hubuser, hubpass); ok {
+		return i.copyManifestList(hubuser, hubpass)
+	}
 	_, err := sources.ImagePull(i.DockerClient, i.LocalImageName, hubuser, hubpass, i.Logger, 20)
 	if err != nil {
 		logrus.Errorf("pull image %s error: %s", i.LocalImageName, err.Error())
@@ -109,6 +112,25 @@ func (i *ImageShareItem) ShareService() error {
 	return nil
 }
 
+//copyManifestList docker only pulls the image of the current platform, so the image of multiple platforms
+//is copied between the registries directly, the repository of the trusted registry is checked like TrustedImagePush
+func (i *ImageShareItem) copyManifestList(hubuser, hubpass string) error {
+	user, pass := builder.GetImageUserInfoV2(i.ImageName, i.ShareInfo.ImageInfo.HubUser, i.ShareInfo.ImageInfo.HubPassword)
+	if i.ShareInfo.ImageInfo.IsTrust {
+		if err := sources.CheckTrustedRepositories(i.ImageName, user, pass); err != nil {
+			logrus.Errorf("check trusted repository of image %s error: %s", i.ImageName, err.Error())
+			i.Logger.Error("Failed to push the image to the mirror warehouse", map[string]string{"step": "builder-exector", "status": "failure"})
+			return err
+		}
+	}
+	if err := sources.CopyImage(i.LocalImageName, i.ImageName, hubuser, hubpass, user, pass, i.Logger); err != nil {
+		logrus.Errorf("copy image %s to %s error: %s", i.LocalImageName, i.ImageName, err.Error())
+		i.Logger.Error("Failed to push the image to the mirror warehouse", map[string]string{"step": "builder-exector", "status": "failure"})
+		return err
+	}
+	return nil
+}
+
 //ShareStatus
 type ShareStatus struct {
 	ShareID string `json:"share_id,omitempty"`
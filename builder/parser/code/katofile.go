@@ -21,21 +21,40 @@ package code
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/gridworkz/kato/util"
 	"github.com/sirupsen/logrus"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// the versions of katofile. Version 1 only supports the language, build path, ports, envs, cmd and services,
+// version 2 declares the whole component, including probes, resources, volumes, config files, dependencies and domains.
+const (
+	KatoFileV1 = "1"
+	KatoFileV2 = "2"
 )
 
 //KatoFileConfig - source code configuration file
 type KatoFileConfig struct {
+	Version   string                 `yaml:"version"`
 	Language  string                 `yaml:"language"`
 	BuildPath string                 `yaml:"buildpath"`
 	Ports     []Port                 `yaml:"ports"`
 	Envs      map[string]interface{} `yaml:"envs"`
 	Cmd       string                 `yaml:"cmd"`
 	Services  []*Service             `yaml:"services"`
+
+	ComponentSpec `yaml:",inline"`
+
+	// Warnings are the problems of the katofile of version 1, they do not fail the build,
+	// the katofile of version 1 is used as before the versions are introduced.
+	Warnings []string `yaml:"-"`
 }
 
 // Service contains
@@ -43,6 +62,8 @@ type Service struct {
 	Name  string            `yaml:"name"`
 	Ports []Port            `yaml:"ports"`
 	Envs  map[string]string `yaml:"envs"`
+
+	ComponentSpec `yaml:",inline"`
 }
 
 //Port
@@ -51,6 +72,100 @@ type Port struct {
 	Protocol string `yaml:"protocol"`
 }
 
+// ComponentSpec describes how the component runs, it is supported since version 2.
+type ComponentSpec struct {
+	Probes       []Probe      `yaml:"probes"`
+	Resources    *Resources   `yaml:"resources"`
+	Volumes      []Volume     `yaml:"volumes"`
+	ConfigFiles  []ConfigFile `yaml:"configfiles"`
+	Dependencies []string     `yaml:"dependencies"`
+	Domains      []Domain     `yaml:"domains"`
+}
+
+// IsEmpty checks if nothing is declared in the spec
+func (c *ComponentSpec) IsEmpty() bool {
+	return len(c.Probes) == 0 && c.Resources == nil && len(c.Volumes) == 0 &&
+		len(c.ConfigFiles) == 0 && len(c.Dependencies) == 0 && len(c.Domains) == 0
+}
+
+// Probe is the health check of the component
+type Probe struct {
+	// readiness or liveness, default readiness
+	Mode string `yaml:"mode"`
+	// tcp, http or cmd, default tcp
+	Scheme           string `yaml:"scheme"`
+	Port             int    `yaml:"port"`
+	Path             string `yaml:"path"`
+	Cmd              string `yaml:"cmd"`
+	InitialDelay     int    `yaml:"initial_delay"`
+	Period           int    `yaml:"period"`
+	Timeout          int    `yaml:"timeout"`
+	FailureThreshold int    `yaml:"failure_threshold"`
+	SuccessThreshold int    `yaml:"success_threshold"`
+}
+
+// Resources is the resource requests of the component, such as 'cpu: 500m' and 'memory: 1Gi'
+type Resources struct {
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+}
+
+// CPUMilli returns the cpu in millicores
+func (r *Resources) CPUMilli() int {
+	if r == nil || r.CPU == "" {
+		return 0
+	}
+	q, err := resource.ParseQuantity(r.CPU)
+	if err != nil {
+		return 0
+	}
+	return int(q.MilliValue())
+}
+
+// MemoryMB returns the memory in MB
+func (r *Resources) MemoryMB() int {
+	if r == nil || r.Memory == "" {
+		return 0
+	}
+	q, err := resource.ParseQuantity(r.Memory)
+	if err != nil {
+		return 0
+	}
+	return int(q.Value() / 1024 / 1024)
+}
+
+// Volume is the persistent volume of the component
+type Volume struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+	// share-file, local or memoryfs, default share-file
+	Type string `yaml:"type"`
+	// the capacity in GB, 0 means the default capacity of the storage
+	Capacity int64 `yaml:"capacity"`
+}
+
+// ConfigFile is mounted to the path of the component, its content is inline or read from the file in the repository
+type ConfigFile struct {
+	Name    string `yaml:"name"`
+	Path    string `yaml:"path"`
+	Content string `yaml:"content"`
+	File    string `yaml:"file"`
+}
+
+// Domain is the gateway domain bound to the port of the component
+type Domain struct {
+	Domain string `yaml:"domain"`
+	Port   int    `yaml:"port"`
+	Path   string `yaml:"path"`
+}
+
+// ValidationError is returned if the katofile is invalid, every item describes a problem.
+type ValidationError []string
+
+func (v ValidationError) Error() string {
+	return "invalid katofile: " + strings.Join(v, "; ")
+}
+
 //ReadKatoFile - read cloud help code configuration
 func ReadKatoFile(homepath string) (*KatoFileConfig, error) {
 	if ok, _ := util.FileExists(path.Join(homepath, "katofile")); !ok {
@@ -61,10 +176,295 @@ func ReadKatoFile(homepath string) (*KatoFileConfig, error) {
 		logrus.Error("read kato file error,", err.Error())
 		return nil, fmt.Errorf("read kato file error")
 	}
-	var rbdfile KatoFileConfig
-	if err := yaml.Unmarshal(body, &rbdfile); err != nil {
+	return parseKatoFile(homepath, body)
+}
+
+func parseKatoFile(homepath string, body []byte) (*KatoFileConfig, error) {
+	var version struct {
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(body, &version); err != nil {
 		logrus.Error("marshal kato file error,", err.Error())
 		return nil, fmt.Errorf("marshal kato file error")
 	}
+	var rbdfile KatoFileConfig
+	switch version.Version {
+	case "", KatoFileV1:
+		if err := yaml.Unmarshal(body, &rbdfile); err != nil {
+			logrus.Error("marshal kato file error,", err.Error())
+			return nil, fmt.Errorf("marshal kato file error")
+		}
+	case KatoFileV2:
+		// the unknown fields are reported in version 2, so that the typos do not take no effect silently
+		if err := yaml.UnmarshalStrict(body, &rbdfile); err != nil {
+			return nil, ValidationError{err.Error()}
+		}
+	default:
+		return nil, ValidationError{fmt.Sprintf("version: unsupported version %q, supported versions are %s and %s", version.Version, KatoFileV1, KatoFileV2)}
+	}
+	errs := rbdfile.Validate()
+	if rbdfile.Version != KatoFileV2 {
+		// only version 2 is validated strictly, the problems of version 1 are reported as warnings,
+		// and the fields of version 2 are ignored as before.
+		rbdfile.Warnings = errs
+		rbdfile.ComponentSpec = ComponentSpec{}
+		for _, svc := range rbdfile.Services {
+			if svc != nil {
+				svc.ComponentSpec = ComponentSpec{}
+			}
+		}
+		return &rbdfile, nil
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if err := rbdfile.loadConfigFiles(homepath); err != nil {
+		return nil, err
+	}
 	return &rbdfile, nil
 }
+
+// Validate validates the katofile, and returns all the problems found.
+func (k *KatoFileConfig) Validate() ValidationError {
+	var errs ValidationError
+	v2 := k.Version == KatoFileV2
+	errs = append(errs, validatePorts("ports", k.Ports)...)
+	errs = append(errs, k.ComponentSpec.validate("", v2, k.Ports)...)
+	names := make(map[string]bool, len(k.Services))
+	for i, svc := range k.Services {
+		field := fmt.Sprintf("services[%d]", i)
+		if svc == nil || svc.Name == "" {
+			errs = append(errs, field+".name: required")
+			continue
+		}
+		if names[svc.Name] {
+			errs = append(errs, fmt.Sprintf("%s.name: duplicate service %s", field, svc.Name))
+		}
+		names[svc.Name] = true
+		errs = append(errs, validatePorts(field+".ports", svc.Ports)...)
+		errs = append(errs, svc.ComponentSpec.validate(field+".", v2, append(svc.Ports, k.Ports...))...)
+	}
+	for i, svc := range k.Services {
+		if svc == nil {
+			continue
+		}
+		for j, dep := range svc.Dependencies {
+			if dep == svc.Name {
+				errs = append(errs, fmt.Sprintf("services[%d].dependencies[%d]: the service can not depend on itself", i, j))
+			}
+		}
+	}
+	return errs
+}
+
+func validatePorts(field string, ports []Port) (errs []string) {
+	for i, port := range ports {
+		if port.Port < 1 || port.Port > 65535 {
+			errs = append(errs, fmt.Sprintf("%s[%d].port: %d is not between 1 and 65535", field, i, port.Port))
+		}
+		switch strings.ToLower(port.Protocol) {
+		case "", "http", "tcp", "udp", "mysql", "grpc":
+		default:
+			errs = append(errs, fmt.Sprintf("%s[%d].protocol: unsupported protocol %s", field, i, port.Protocol))
+		}
+	}
+	return errs
+}
+
+func (c *ComponentSpec) validate(prefix string, v2 bool, ports []Port) (errs []string) {
+	if !v2 {
+		if !c.IsEmpty() {
+			errs = append(errs, fmt.Sprintf("%sprobes, resources, volumes, configfiles, dependencies and domains require 'version: %s', they are ignored", prefix, KatoFileV2))
+		}
+		return errs
+	}
+	declared := make(map[int]bool, len(ports))
+	for _, port := range ports {
+		declared[port.Port] = true
+	}
+	validPort := func(field string, port int) {
+		if port < 1 || port > 65535 {
+			errs = append(errs, fmt.Sprintf("%s: %d is not between 1 and 65535", field, port))
+		} else if len(declared) > 0 && !declared[port] {
+			errs = append(errs, fmt.Sprintf("%s: port %d is not declared in ports", field, port))
+		}
+	}
+	modes := make(map[string]bool, len(c.Probes))
+	for i := range c.Probes {
+		probe := &c.Probes[i]
+		field := fmt.Sprintf("%sprobes[%d]", prefix, i)
+		if probe.Mode == "" {
+			probe.Mode = "readiness"
+		}
+		if probe.Scheme == "" {
+			probe.Scheme = "tcp"
+		}
+		if probe.Mode != "readiness" && probe.Mode != "liveness" {
+			errs = append(errs, fmt.Sprintf("%s.mode: %s should be readiness or liveness", field, probe.Mode))
+		}
+		if modes[probe.Mode] {
+			errs = append(errs, fmt.Sprintf("%s.mode: duplicate %s probe", field, probe.Mode))
+		}
+		modes[probe.Mode] = true
+		switch probe.Scheme {
+		case "tcp":
+			validPort(field+".port", probe.Port)
+		case "http":
+			validPort(field+".port", probe.Port)
+			if !strings.HasPrefix(probe.Path, "/") {
+				errs = append(errs, field+".path: should start with /")
+			}
+		case "cmd":
+			if strings.TrimSpace(probe.Cmd) == "" {
+				errs = append(errs, field+".cmd: required for the cmd probe")
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("%s.scheme: %s should be tcp, http or cmd", field, probe.Scheme))
+		}
+		if probe.InitialDelay < 0 || probe.Period < 0 || probe.Timeout < 0 || probe.FailureThreshold < 0 || probe.SuccessThreshold < 0 {
+			errs = append(errs, field+": the delay, period, timeout and thresholds can not be negative")
+		}
+	}
+	if r := c.Resources; r != nil {
+		if r.CPU != "" {
+			if q, err := resource.ParseQuantity(r.CPU); err != nil || q.Sign() <= 0 {
+				errs = append(errs, fmt.Sprintf("%sresources.cpu: invalid quantity %s, it should be like 500m or 2", prefix, r.CPU))
+			}
+		}
+		if r.Memory != "" {
+			if q, err := resource.ParseQuantity(r.Memory); err != nil || q.Value() < 1024*1024 {
+				errs = append(errs, fmt.Sprintf("%sresources.memory: invalid quantity %s, it should be like 512Mi or 1Gi", prefix, r.Memory))
+			}
+		}
+	}
+	paths := make(map[string]string)
+	checkPath := func(field, p string) {
+		if !path.IsAbs(p) {
+			errs = append(errs, fmt.Sprintf("%s.path: %q should be an absolute path", field, p))
+			return
+		}
+		p = path.Clean(p)
+		if other, ok := paths[p]; ok {
+			errs = append(errs, fmt.Sprintf("%s.path: %s is already used by %s", field, p, other))
+		}
+		paths[p] = field
+	}
+	volumeNames := make(map[string]bool, len(c.Volumes))
+	for i, volume := range c.Volumes {
+		field := fmt.Sprintf("%svolumes[%d]", prefix, i)
+		if volume.Name == "" {
+			errs = append(errs, field+".name: required")
+		} else if msgs := k8svalidation.IsDNS1123Label(volume.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Sprintf("%s.name: %s", field, strings.Join(msgs, ", ")))
+		} else if volumeNames[volume.Name] {
+			errs = append(errs, fmt.Sprintf("%s.name: duplicate volume %s", field, volume.Name))
+		}
+		volumeNames[volume.Name] = true
+		checkPath(field, volume.Path)
+		switch volume.Type {
+		case "", "share-file", "local", "memoryfs":
+		default:
+			errs = append(errs, fmt.Sprintf("%s.type: %s should be share-file, local or memoryfs", field, volume.Type))
+		}
+		if volume.Capacity < 0 {
+			errs = append(errs, field+".capacity: can not be negative")
+		}
+	}
+	for i, cf := range c.ConfigFiles {
+		field := fmt.Sprintf("%sconfigfiles[%d]", prefix, i)
+		checkPath(field, cf.Path)
+		if (cf.Content == "") == (cf.File == "") {
+			errs = append(errs, field+": one of content and file is required")
+		}
+		if cf.File != "" && (path.IsAbs(cf.File) || strings.HasPrefix(path.Clean(cf.File), "..")) {
+			errs = append(errs, fmt.Sprintf("%s.file: %s should be a relative path in the repository", field, cf.File))
+		}
+	}
+	for i, dep := range c.Dependencies {
+		if strings.TrimSpace(dep) == "" {
+			errs = append(errs, fmt.Sprintf("%sdependencies[%d]: can not be empty", prefix, i))
+		}
+	}
+	for i, domain := range c.Domains {
+		field := fmt.Sprintf("%sdomains[%d]", prefix, i)
+		var msgs []string
+		if domain.Domain == "" {
+			msgs = []string{"required"}
+		} else if strings.Contains(domain.Domain, "*") {
+			msgs = k8svalidation.IsWildcardDNS1123Subdomain(domain.Domain)
+		} else {
+			msgs = k8svalidation.IsDNS1123Subdomain(domain.Domain)
+		}
+		if len(msgs) > 0 {
+			errs = append(errs, fmt.Sprintf("%s.domain: %s", field, strings.Join(msgs, ", ")))
+		}
+		validPort(field+".port", domain.Port)
+		if domain.Path != "" && !strings.HasPrefix(domain.Path, "/") {
+			errs = append(errs, field+".path: should start with /")
+		}
+	}
+	return errs
+}
+
+// loadConfigFiles reads the content of the config files from the repository
+func (k *KatoFileConfig) loadConfigFiles(homepath string) error {
+	specs := []*ComponentSpec{&k.ComponentSpec}
+	for _, svc := range k.Services {
+		specs = append(specs, &svc.ComponentSpec)
+	}
+	var errs ValidationError
+	var root string
+	for _, spec := range specs {
+		for i := range spec.ConfigFiles {
+			cf := &spec.ConfigFiles[i]
+			if cf.Name == "" {
+				cf.Name = path.Base(cf.Path)
+			}
+			if cf.File == "" {
+				continue
+			}
+			if root == "" {
+				var err error
+				if root, err = filepath.EvalSymlinks(filepath.Clean(homepath)); err != nil {
+					return ValidationError{fmt.Sprintf("configfiles: resolve the repository: %v", err)}
+				}
+			}
+			file, err := resolveRepoFile(root, cf.File)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("configfiles: read %s: %v", cf.File, err))
+				continue
+			}
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("configfiles: read %s: %v", cf.File, err))
+				continue
+			}
+			cf.Content = string(content)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// resolveRepoFile resolves the symlinks of the file in the repository, the file must be a regular file
+// and can not point outside of the repository, or the files of the builder could be read.
+func resolveRepoFile(root, file string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(file)))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("the file is outside of the repository")
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("not a regular file")
+	}
+	return resolved, nil
+}
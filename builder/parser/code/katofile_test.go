@@ -19,6 +19,11 @@
 package code
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,3 +34,218 @@ func TestReadKatoFile(t *testing.T) {
 	}
 	t.Log(rbdfile)
 }
+
+func TestParseKatoFileV2(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katofile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "nginx.conf"), []byte("worker_processes 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	body := `
+version: "2"
+language: Go
+ports:
+  - port: 8080
+    protocol: http
+probes:
+  - scheme: http
+    port: 8080
+    path: /healthz
+  - mode: liveness
+    port: 8080
+resources:
+  cpu: 500m
+  memory: 1Gi
+volumes:
+  - name: data
+    path: /data
+    capacity: 10
+configfiles:
+  - path: /etc/nginx/nginx.conf
+    file: nginx.conf
+dependencies:
+  - mysql
+domains:
+  - domain: www.example.com
+    port: 8080
+`
+	rbdfile, err := parseKatoFile(dir, []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rbdfile.Probes) != 2 || rbdfile.Probes[0].Mode != "readiness" || rbdfile.Probes[1].Scheme != "tcp" {
+		t.Errorf("unexpected probes %+v", rbdfile.Probes)
+	}
+	if cpu, memory := rbdfile.Resources.CPUMilli(), rbdfile.Resources.MemoryMB(); cpu != 500 || memory != 1024 {
+		t.Errorf("want 500m cpu and 1024M memory, but got %d and %d", cpu, memory)
+	}
+	want := ConfigFile{Name: "nginx.conf", Path: "/etc/nginx/nginx.conf", File: "nginx.conf", Content: "worker_processes 1;"}
+	if len(rbdfile.ConfigFiles) != 1 || !reflect.DeepEqual(rbdfile.ConfigFiles[0], want) {
+		t.Errorf("want config file %+v, but got %+v", want, rbdfile.ConfigFiles)
+	}
+	if len(rbdfile.Volumes) != 1 || len(rbdfile.Dependencies) != 1 || len(rbdfile.Domains) != 1 {
+		t.Errorf("unexpected katofile %+v", rbdfile)
+	}
+}
+
+func TestParseKatoFileV1(t *testing.T) {
+	body := `
+language: Go
+ports:
+  - port: 8080
+    protocol: http
+  - port: 9090
+    protocol: websocket
+resources:
+  cpu: 1
+`
+	rbdfile, err := parseKatoFile("", []byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rbdfile.Ports) != 2 || rbdfile.Resources != nil {
+		t.Errorf("want the ports kept and the resources ignored, but got %+v", rbdfile)
+	}
+	want := []string{"ports[1].protocol: unsupported protocol websocket", "require 'version: 2', they are ignored"}
+	if len(rbdfile.Warnings) != len(want) {
+		t.Fatalf("want %d warnings, but got %v", len(want), rbdfile.Warnings)
+	}
+	for i := range want {
+		if !strings.Contains(rbdfile.Warnings[i], want[i]) {
+			t.Errorf("want warning %q, but got %q", want[i], rbdfile.Warnings[i])
+		}
+	}
+	if _, err := parseKatoFile("", []byte("ports: [")); err == nil {
+		t.Error("want error for the invalid yaml")
+	} else if _, ok := err.(ValidationError); ok {
+		t.Errorf("the yaml error of version 1 should not be a ValidationError: %v", err)
+	}
+}
+
+func TestParseKatoFileSymlink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "katofile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	repo := filepath.Join(dir, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, "conf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(repo, "conf", "app.conf"), []byte("app"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../secret", filepath.Join(repo, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("conf/app.conf", filepath.Join(repo, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(repo, "parent")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file    string
+		content string
+		err     string
+	}{
+		{file: "link", content: "app"},
+		{file: "escape", err: "outside of the repository"},
+		{file: "parent/secret", err: "outside of the repository"},
+		{file: "conf", err: "not a regular file"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.file, func(t *testing.T) {
+			body := "version: \"2\"\nconfigfiles:\n  - path: /etc/app.conf\n    file: " + tc.file
+			rbdfile, err := parseKatoFile(repo, []byte(body))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("want error %q, but got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rbdfile.ConfigFiles[0].Content; got != tc.content {
+				t.Errorf("want content %q, but got %q", tc.content, got)
+			}
+		})
+	}
+}
+
+func TestParseKatoFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "unsupported version",
+			body: "version: \"3\"",
+			want: []string{"version: unsupported version"},
+		},
+		{
+			name: "unknown field",
+			body: "version: \"2\"\nprobe:\n  - port: 80",
+			want: []string{"field probe not found"},
+		},
+		{
+			name: "invalid spec",
+			body: `
+version: "2"
+ports:
+  - port: 8080
+probes:
+  - scheme: http
+    port: 9090
+resources:
+  memory: 1x
+volumes:
+  - name: Data
+    path: data
+configfiles:
+  - path: /data
+    content: a
+domains:
+  - domain: -bad
+    port: 8080
+services:
+  - name: api
+    dependencies: [api]
+`,
+			want: []string{
+				"probes[0].port: port 9090 is not declared in ports",
+				"probes[0].path: should start with /",
+				"resources.memory: invalid quantity 1x",
+				"volumes[0].name:",
+				`volumes[0].path: "data" should be an absolute path`,
+				"domains[0].domain:",
+				"services[0].dependencies[0]: the service can not depend on itself",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseKatoFile("", []byte(tc.body))
+			verrs, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("want ValidationError, but got %v", err)
+			}
+			if len(verrs) != len(tc.want) {
+				t.Errorf("want %d errors, but got %d: %v", len(tc.want), len(verrs), verrs)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(verrs.Error(), want) {
+					t.Errorf("want error %q in %v", want, verrs)
+				}
+			}
+		})
+	}
+}
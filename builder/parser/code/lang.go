@@ -251,6 +251,9 @@ func golang(homepath string) Lang {
 	if ok, _ := util.FileExists(path.Join(homepath, "go.mod")); ok {
 		return Golang
 	}
+	if ok, _ := util.FileExists(path.Join(homepath, "go.work")); ok {
+		return Golang
+	}
 	if ok, _ := util.FileExists(path.Join(homepath, "Gopkg.lock")); ok {
 		return Golang
 	}
@@ -278,6 +281,12 @@ func gradle(homepath string) Lang {
 	if ok, _ := util.FileExists(path.Join(homepath, "settings.gradle")); ok {
 		return Gradle
 	}
+	if ok, _ := util.FileExists(path.Join(homepath, "build.gradle.kts")); ok {
+		return Gradle
+	}
+	if ok, _ := util.FileExists(path.Join(homepath, "settings.gradle.kts")); ok {
+		return Gradle
+	}
 	return NO
 }
 func grails(homepath string) Lang {
@@ -0,0 +1,165 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package multi
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gridworkz/kato/builder/parser/types"
+)
+
+var (
+	goModule = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+	goMain   = regexp.MustCompile(`(?m)^package\s+main\b`)
+	// ":8080" or "0.0.0.0:8080" in the main package
+	goPort = regexp.MustCompile(`"(?:0\.0\.0\.0)?:(\d{2,5})"`)
+)
+
+// goModules is an implementation of ServiceInterface for the go workspaces and the repositories
+// with several go modules.
+type goModules struct {
+}
+
+// NewGoModules creates a new ServiceInterface for go modules
+func NewGoModules() ServiceInterface {
+	return &goModules{}
+}
+
+// ListModules lists the main packages of the go modules in go.work, or the go modules found in
+// the project if there is no go.work. The main package is the root of the module or the sub dirs of cmd.
+func (g *goModules) ListModules(homepath string) ([]*types.Service, error) {
+	dirs, err := goWorkUses(path.Join(homepath, "go.work"))
+	if err != nil {
+		return nil, err
+	}
+	if dirs == nil {
+		dirs = findFiles(homepath, "go.mod", 3)
+	}
+	sort.Strings(dirs)
+	var res []*types.Service
+	for _, dir := range dirs {
+		content, err := ioutil.ReadFile(path.Join(homepath, dir, "go.mod"))
+		if err != nil {
+			continue
+		}
+		match := goModule.FindSubmatch(content)
+		if match == nil {
+			continue
+		}
+		module := string(match[1])
+		buildPath := dir
+		if dir == "." {
+			buildPath = ""
+		}
+		if files := goMainFiles(path.Join(homepath, dir)); len(files) > 0 {
+			svc := newService(dir, map[string]string{
+				"BUILD_PROCFILE": fmt.Sprintf("web: bin/%s", path.Base(module)),
+			}, findPorts(goPort, files...)...)
+			if dir == "." {
+				svc.Name, svc.Cname = path.Base(module), path.Base(module)
+			}
+			svc.BuildPath = buildPath
+			res = append(res, svc)
+		}
+		cmds, _ := ioutil.ReadDir(path.Join(homepath, dir, "cmd"))
+		for _, cmd := range cmds {
+			if !cmd.IsDir() {
+				continue
+			}
+			files := goMainFiles(path.Join(homepath, dir, "cmd", cmd.Name()))
+			if len(files) == 0 {
+				continue
+			}
+			svc := newService(path.Join(dir, "cmd", cmd.Name()), map[string]string{
+				"BUILD_GO_INSTALL_PACKAGE_SPEC": "./cmd/" + cmd.Name(),
+				"BUILD_PROCFILE":                fmt.Sprintf("web: bin/%s", cmd.Name()),
+			}, findPorts(goPort, files...)...)
+			svc.BuildPath = buildPath
+			res = append(res, svc)
+		}
+	}
+	return res, nil
+}
+
+// goWorkUses returns the dirs in the use directives of go.work, or nil if go.work does not exist.
+func goWorkUses(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	dirs := []string{}
+	block := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case block && line == ")":
+			block = false
+			continue
+		case block:
+		case line == "use (":
+			block = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use"))
+		default:
+			continue
+		}
+		if line = strings.Trim(line, `"`); line != "" {
+			dirs = append(dirs, filepath.ToSlash(path.Clean(line)))
+		}
+	}
+	return dirs, scanner.Err()
+}
+
+// goMainFiles returns the go files of the main package in the dir.
+func goMainFiles(dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".go") || strings.HasSuffix(info.Name(), "_test.go") {
+			continue
+		}
+		file := path.Join(dir, info.Name())
+		content, err := ioutil.ReadFile(file)
+		if err == nil && goMain.Match(content) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
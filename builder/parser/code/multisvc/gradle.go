@@ -0,0 +1,122 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package multi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gridworkz/kato/builder/parser/types"
+)
+
+var (
+	// include 'app', ':services:api' or include("app", ":services:api"), which may span several lines
+	gradleInclude = regexp.MustCompile(`(?m)^\s*include\s*\(?((?:\s*['"][^'"]+['"]\s*,?)+)\)?`)
+	gradleQuoted  = regexp.MustCompile(`['"]([^'"]+)['"]`)
+	springBoot    = regexp.MustCompile(`org\.springframework\.boot`)
+	gradleApp     = regexp.MustCompile(`(?m)(['"]application['"]|^\s*application\s*$)`)
+	gradleWar     = regexp.MustCompile(`(?m)(['"]war['"]|^\s*war\s*$)`)
+	springPort    = regexp.MustCompile(`(?m)^\s*server\.port\s*[=:]\s*(\d+)|^server:\s*\n(?:[ \t]+.*\n)*?[ \t]+port:\s*(\d+)`)
+)
+
+// gradle is an implementation of ServiceInterface for gradle multi-project builds.
+type gradle struct {
+}
+
+// NewGradle creates a new ServiceInterface for gradle multi-project builds
+func NewGradle() ServiceInterface {
+	return &gradle{}
+}
+
+// ListModules lists the deployable sub projects included in settings.gradle, which apply
+// the spring boot, application or war plugin. All the modules are built in the root project.
+func (g *gradle) ListModules(homepath string) ([]*types.Service, error) {
+	settings, err := readFirst(homepath, "settings.gradle", "settings.gradle.kts")
+	if err != nil {
+		return nil, nil
+	}
+	var res []*types.Service
+	for _, project := range gradleProjects(settings) {
+		dir := strings.Replace(strings.Trim(project, ":"), ":", "/", -1)
+		buildFile, err := readFirst(path.Join(homepath, dir), "build.gradle", "build.gradle.kts")
+		if err != nil {
+			continue
+		}
+		task := ":" + strings.Trim(project, ":") + ":"
+		name := path.Base(dir)
+		var svc *types.Service
+		switch {
+		case springBoot.Match(buildFile):
+			resources := path.Join(homepath, dir, "src/main/resources")
+			ports := findPorts(springPort, path.Join(resources, "application.properties"),
+				path.Join(resources, "application.yml"), path.Join(resources, "application.yaml"))
+			if len(ports) == 0 {
+				ports = []int{8080}
+			}
+			svc = newService(dir, map[string]string{
+				"BUILD_GRADLE_TASK": task + "bootJar",
+				"BUILD_PROCFILE":    fmt.Sprintf("web: java $JAVA_OPTS -jar %s/build/libs/*.jar", dir),
+			}, ports...)
+			svc.Packaging = "jar"
+		case gradleWar.Match(buildFile):
+			svc = newService(dir, map[string]string{
+				"BUILD_GRADLE_TASK": task + "war",
+				"BUILD_PROCFILE":    fmt.Sprintf("web: java $JAVA_OPTS -jar /opt/webapp-runner.jar --port $PORT %s/build/libs/*.war", dir),
+			})
+			svc.Packaging = "war"
+		case gradleApp.Match(buildFile):
+			svc = newService(dir, map[string]string{
+				"BUILD_GRADLE_TASK": task + "installDist",
+				"BUILD_PROCFILE":    fmt.Sprintf("web: %s/build/install/%s/bin/%s", dir, name, name),
+			})
+			svc.Packaging = "jar"
+		default:
+			// libraries
+			continue
+		}
+		res = append(res, svc)
+	}
+	return res, nil
+}
+
+// gradleProjects returns the paths of the projects included in settings.gradle, such as ':services:api'.
+func gradleProjects(settings []byte) []string {
+	var projects []string
+	for _, include := range gradleInclude.FindAllSubmatch(settings, -1) {
+		for _, quoted := range gradleQuoted.FindAllSubmatch(include[1], -1) {
+			projects = append(projects, string(quoted[1]))
+		}
+	}
+	return projects
+}
+
+// readFirst reads the first existing file of the names in the dir
+func readFirst(dir string, names ...string) ([]byte, error) {
+	var err error
+	for _, name := range names {
+		var content []byte
+		if content, err = ioutil.ReadFile(path.Join(dir, name)); err == nil {
+			return content, nil
+		}
+	}
+	return nil, err
+}
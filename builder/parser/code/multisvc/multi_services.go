@@ -19,7 +19,16 @@
 package multi
 
 import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/gridworkz/kato/builder/parser/types"
+	"github.com/gridworkz/kato/util"
 )
 
 // ServiceInterface is the interface that wraps the required methods to gather information
@@ -33,6 +42,87 @@ func NewMultiServiceI(lang string) ServiceInterface {
 	switch lang {
 	case "Java-maven":
 		return NewMaven()
+	case "Gradle":
+		return NewGradle()
+	case "Node.js":
+		return NewNodeWorkspaces()
+	case "Go":
+		return NewGoModules()
 	}
 	return nil
 }
+
+// newService creates a service of the module in the dir relative to the project root.
+func newService(dir string, envs map[string]string, ports ...int) *types.Service {
+	svc := &types.Service{
+		ID:    util.NewUUID(),
+		Name:  dir,
+		Cname: path.Base(dir),
+		Envs:  make(map[string]*types.Env),
+	}
+	for name, value := range envs {
+		svc.Envs[name] = &types.Env{Name: name, Value: value}
+	}
+	for _, port := range ports {
+		if svc.Ports == nil {
+			svc.Ports = make(map[int]*types.Port)
+		}
+		svc.Ports[port] = &types.Port{ContainerPort: port, Protocol: "http"}
+	}
+	return svc
+}
+
+// skipDirs are not searched for modules
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"testdata":     true,
+	"build":        true,
+	"target":       true,
+}
+
+// findFiles finds the files with the name in the dir and its sub dirs within the depth,
+// and returns the dirs of the files relative to the dir.
+func findFiles(dir, name string, depth int) []string {
+	var dirs []string
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		if info.IsDir() {
+			if rel != "." && (strings.HasPrefix(info.Name(), ".") || skipDirs[info.Name()] || strings.Count(rel, string(filepath.Separator)) >= depth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == name {
+			dirs = append(dirs, filepath.ToSlash(filepath.Dir(rel)))
+		}
+		return nil
+	})
+	return dirs
+}
+
+// findPorts returns the ports matched by the first group of the regular expression in the files.
+func findPorts(re *regexp.Regexp, files ...string) []int {
+	var ports []int
+	seen := make(map[int]bool)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, match := range re.FindAllSubmatch(content, -1) {
+			for _, group := range match[1:] {
+				port, err := strconv.Atoi(string(group))
+				if err != nil || port <= 0 || port > 65535 || seen[port] {
+					continue
+				}
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
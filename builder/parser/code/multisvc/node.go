@@ -0,0 +1,144 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package multi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/gridworkz/kato/builder/parser/types"
+	"github.com/gridworkz/kato/util"
+)
+
+var (
+	// --port 3000, -p 3000 or PORT=3000 in the scripts
+	nodePort = regexp.MustCompile(`(?:--port|-p)[= ]+(\d+)|PORT=(\d+)`)
+	// the frameworks listening on 3000 by default
+	nodeDefaultPort = regexp.MustCompile(`\b(next|nuxt|remix-serve) start\b`)
+)
+
+// packageJSON represents a package.json file
+type packageJSON struct {
+	Name       string            `json:"name"`
+	Scripts    map[string]string `json:"scripts"`
+	Workspaces json.RawMessage   `json:"workspaces"`
+}
+
+// workspaces returns the workspace patterns, which is an array or an object with packages
+func (p *packageJSON) workspaces() []string {
+	if len(p.Workspaces) == 0 {
+		return nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(p.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(p.Workspaces, &obj); err == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// nodeWorkspaces is an implementation of ServiceInterface for yarn and npm workspaces.
+type nodeWorkspaces struct {
+}
+
+// NewNodeWorkspaces creates a new ServiceInterface for yarn and npm workspaces
+func NewNodeWorkspaces() ServiceInterface {
+	return &nodeWorkspaces{}
+}
+
+// ListModules lists the workspaces that have a start script. All the workspaces are installed in the
+// root project, and the start command of the workspace is used as the Procfile.
+func (n *nodeWorkspaces) ListModules(homepath string) ([]*types.Service, error) {
+	root, err := readPackageJSON(path.Join(homepath, "package.json"))
+	if err != nil {
+		return nil, nil
+	}
+	yarn, _ := util.FileExists(path.Join(homepath, "yarn.lock"))
+	var dirs []string
+	for _, pattern := range root.workspaces() {
+		matches, err := filepath.Glob(path.Join(homepath, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace pattern %s: %v", pattern, err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(homepath, match)
+			if err == nil {
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+		}
+	}
+	sort.Strings(dirs)
+	var res []*types.Service
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		pkg, err := readPackageJSON(path.Join(homepath, dir, "package.json"))
+		if err != nil || pkg.Scripts["start"] == "" {
+			continue
+		}
+		procfile := fmt.Sprintf("web: npm run start --workspace=%s", dir)
+		if yarn {
+			procfile = fmt.Sprintf("web: yarn workspace %s start", pkg.Name)
+		}
+		svc := newService(dir, map[string]string{"BUILD_PROCFILE": procfile}, nodePorts(pkg.Scripts["start"])...)
+		res = append(res, svc)
+	}
+	return res, nil
+}
+
+func nodePorts(script string) []int {
+	var ports []int
+	for _, match := range nodePort.FindAllStringSubmatch(script, -1) {
+		for _, group := range match[1:] {
+			if port, err := strconv.Atoi(group); err == nil && port > 0 && port <= 65535 {
+				ports = append(ports, port)
+			}
+		}
+	}
+	if len(ports) == 0 && nodeDefaultPort.MatchString(script) {
+		ports = append(ports, 3000)
+	}
+	return ports
+}
+
+func readPackageJSON(file string) (*packageJSON, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
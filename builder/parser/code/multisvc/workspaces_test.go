@@ -0,0 +1,183 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package multi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/gridworkz/kato/builder/parser/types"
+)
+
+// writeFiles creates a project with the files in a temporary dir.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "multisvc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+type wantService struct {
+	name      string
+	buildPath string
+	envs      map[string]string
+	ports     []int
+}
+
+func checkServices(t *testing.T, got []*types.Service, want []wantService) {
+	if len(got) != len(want) {
+		t.Fatalf("want %d services, but got %d", len(want), len(got))
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+	for i, w := range want {
+		svc := got[i]
+		if svc.Name != w.name {
+			t.Errorf("want service %s, but got %s", w.name, svc.Name)
+		}
+		if svc.BuildPath != w.buildPath {
+			t.Errorf("%s: want build path %q, but got %q", w.name, w.buildPath, svc.BuildPath)
+		}
+		for name, value := range w.envs {
+			if env := svc.Envs[name]; env == nil || env.Value != value {
+				t.Errorf("%s: want env %s=%s, but got %+v", w.name, name, value, env)
+			}
+		}
+		if len(svc.Ports) != len(w.ports) {
+			t.Errorf("%s: want ports %v, but got %d ports", w.name, w.ports, len(svc.Ports))
+		}
+		for _, port := range w.ports {
+			if svc.Ports[port] == nil {
+				t.Errorf("%s: port %d not found", w.name, port)
+			}
+		}
+	}
+}
+
+func TestGradle_ListModules(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"settings.gradle":  "rootProject.name = 'shop'\ninclude 'api',\n  'common'\ninclude(\":web\")\n",
+		"api/build.gradle": "plugins {\n  id 'org.springframework.boot' version '2.5.0'\n}\n",
+		"api/src/main/resources/application.properties": "server.port=8081\n",
+		"common/build.gradle":                           "plugins {\n  id 'java-library'\n}\n",
+		"web/build.gradle.kts":                          "plugins {\n  application\n}\n",
+	})
+	defer os.RemoveAll(dir)
+	services, err := NewGradle().ListModules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkServices(t, services, []wantService{
+		{
+			name:  "api",
+			envs:  map[string]string{"BUILD_GRADLE_TASK": ":api:bootJar"},
+			ports: []int{8081},
+		},
+		{
+			name: "web",
+			envs: map[string]string{"BUILD_GRADLE_TASK": ":web:installDist", "BUILD_PROCFILE": "web: web/build/install/web/bin/web"},
+		},
+	})
+}
+
+func TestNodeWorkspaces_ListModules(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"package.json":              `{"private":true,"workspaces":{"packages":["packages/*"]}}`,
+		"yarn.lock":                 "",
+		"packages/web/package.json": `{"name":"@shop/web","scripts":{"start":"next start"}}`,
+		"packages/api/package.json": `{"name":"@shop/api","scripts":{"start":"PORT=4000 node index.js"}}`,
+		"packages/ui/package.json":  `{"name":"@shop/ui","scripts":{"build":"tsc"}}`,
+	})
+	defer os.RemoveAll(dir)
+	services, err := NewNodeWorkspaces().ListModules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkServices(t, services, []wantService{
+		{
+			name:  "packages/api",
+			envs:  map[string]string{"BUILD_PROCFILE": "web: yarn workspace @shop/api start"},
+			ports: []int{4000},
+		},
+		{
+			name:  "packages/web",
+			envs:  map[string]string{"BUILD_PROCFILE": "web: yarn workspace @shop/web start"},
+			ports: []int{3000},
+		},
+	})
+}
+
+func TestGoModules_ListModules(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"go.work":             "go 1.18\n\nuse (\n\t./api // the api server\n\t./tools\n)\n",
+		"api/go.mod":          "module github.com/shop/api\n\ngo 1.18\n",
+		"api/main.go":         "package main\n\nfunc main() { http.ListenAndServe(\":8080\", nil) }\n",
+		"api/cmd/worker/a.go": "package main\n\nfunc main() {}\n",
+		"api/cmd/README.md":   "",
+		"tools/go.mod":        "module github.com/shop/tools\n",
+		"tools/tools.go":      "package tools\n",
+		"unused/go.mod":       "module github.com/shop/unused\n",
+		"unused/main.go":      "package main\n",
+	})
+	defer os.RemoveAll(dir)
+	services, err := NewGoModules().ListModules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkServices(t, services, []wantService{
+		{
+			name:      "api",
+			buildPath: "api",
+			envs:      map[string]string{"BUILD_PROCFILE": "web: bin/api"},
+			ports:     []int{8080},
+		},
+		{
+			name:      "api/cmd/worker",
+			buildPath: "api",
+			envs:      map[string]string{"BUILD_GO_INSTALL_PACKAGE_SPEC": "./cmd/worker", "BUILD_PROCFILE": "web: bin/worker"},
+		},
+	})
+}
+
+func TestGoWorkUses(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"go.work": "go 1.18\nuse ./a\nuse \"b\"\n"})
+	defer os.RemoveAll(dir)
+	dirs, err := goWorkUses(filepath.Join(dir, "go.work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || dirs[0] != "a" || dirs[1] != "b" {
+		t.Errorf("want [a b], but got %v", dirs)
+	}
+	if dirs, _ := goWorkUses(filepath.Join(dir, "missing")); dirs != nil {
+		t.Errorf("want nil, but got %v", dirs)
+	}
+}
@@ -0,0 +1,97 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gridworkz/kato/util/consul"
+)
+
+// consulDiscoverier implements Discoverier
+type consulDiscoverier struct {
+	cli     *consul.Client
+	servers []string
+	key     string
+	token   string
+}
+
+// NewConsul creates a new Discorvery which implemeted by consul.
+// The key is the name of the consul service, which can be followed by
+// the query parameters tag and dc, such as orders?tag=v1&dc=dc1.
+func NewConsul(info *Info) Discoverier {
+	return &consulDiscoverier{
+		servers: info.Servers,
+		key:     info.Key,
+		token:   info.Password,
+	}
+}
+
+// Connect creates a consul client with a given configuration.
+func (c *consulDiscoverier) Connect() error {
+	cli, err := consul.NewClient(c.servers, c.token)
+	if err != nil {
+		return err
+	}
+	c.cli = cli
+	return nil
+}
+
+// Fetch fetches the instances of the service from consul.
+func (c *consulDiscoverier) Fetch() ([]*Endpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if c.cli == nil {
+		return nil, fmt.Errorf("can't fetching data from consul without consul client")
+	}
+	service, opts := c.key, &consul.QueryOptions{}
+	if idx := strings.Index(c.key, "?"); idx >= 0 {
+		service = c.key[:idx]
+		query, err := url.ParseQuery(c.key[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid consul key %s: %v", c.key, err)
+		}
+		opts.Tag = query.Get("tag")
+		opts.Datacenter = query.Get("dc")
+	}
+	entries, _, err := c.cli.HealthService(ctx, strings.Trim(service, "/ "), opts)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching endpoints from consul: %v", err)
+	}
+	var res []*Endpoint
+	for _, entry := range entries {
+		res = append(res, &Endpoint{
+			Ep:       net.JoinHostPort(entry.Address(), strconv.Itoa(entry.Service.Port)),
+			IsOnline: entry.Passing(),
+		})
+	}
+	return res, nil
+}
+
+// Close -
+func (c *consulDiscoverier) Close() error {
+	return nil
+}
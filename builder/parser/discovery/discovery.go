@@ -34,6 +34,10 @@ func NewDiscoverier(info *Info) Discoverier {
 	switch strings.ToUpper(info.Type) {
 	case "ETCD":
 		return NewEtcd(info)
+	case "CONSUL":
+		return NewConsul(info)
+	case "DNS":
+		return NewDNS(info)
 	}
 	return nil
 }
@@ -0,0 +1,78 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gridworkz/kato/util/dns"
+)
+
+// dnsDiscoverier implements Discoverier
+type dnsDiscoverier struct {
+	resolver *net.Resolver
+	servers  []string
+	name     string
+}
+
+// NewDNS creates a new Discorvery which implemeted by DNS.
+// The key is the name of the SRV records, such as _http._tcp.example.com,
+// or a domain name with port, such as example.com:8080.
+func NewDNS(info *Info) Discoverier {
+	return &dnsDiscoverier{
+		servers: info.Servers,
+		name:    info.Key,
+	}
+}
+
+// Connect creates a resolver with the given DNS servers.
+func (d *dnsDiscoverier) Connect() error {
+	d.resolver = dns.NewResolver(d.servers)
+	return nil
+}
+
+// Fetch resolves the endpoints from the DNS records.
+func (d *dnsDiscoverier) Fetch() ([]*Endpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if d.resolver == nil {
+		return nil, fmt.Errorf("can't resolving %s without dns resolver", d.name)
+	}
+	endpoints, err := dns.Lookup(ctx, d.resolver, d.name)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving endpoints from dns: %v", err)
+	}
+	var res []*Endpoint
+	for _, ep := range endpoints {
+		res = append(res, &Endpoint{
+			Ep:       ep.Address(),
+			IsOnline: true,
+		})
+	}
+	return res, nil
+}
+
+// Close -
+func (d *dnsDiscoverier) Close() error {
+	return nil
+}
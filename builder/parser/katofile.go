@@ -0,0 +1,103 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package parser
+
+import (
+	"github.com/gridworkz/kato/builder/parser/code"
+	"github.com/gridworkz/kato/builder/parser/types"
+)
+
+// applyComponentSpec sets the probes, resources, volumes, config files, dependencies and domains
+// declared by katofile to the service.
+func applyComponentSpec(svc *types.Service, spec *code.ComponentSpec) {
+	for _, probe := range spec.Probes {
+		svc.Probes = append(svc.Probes, types.Probe{
+			Mode:               probe.Mode,
+			Scheme:             probe.Scheme,
+			Port:               probe.Port,
+			Path:               probe.Path,
+			Cmd:                probe.Cmd,
+			InitialDelaySecond: probe.InitialDelay,
+			PeriodSecond:       probe.Period,
+			TimeoutSecond:      probe.Timeout,
+			FailureThreshold:   probe.FailureThreshold,
+			SuccessThreshold:   probe.SuccessThreshold,
+		})
+	}
+	if cpu := spec.Resources.CPUMilli(); cpu > 0 {
+		svc.CPU = cpu
+	}
+	if memory := spec.Resources.MemoryMB(); memory > 0 {
+		svc.Memory = memory
+	}
+	for _, volume := range spec.Volumes {
+		volumeType := volume.Type
+		if volumeType == "" {
+			volumeType = "share-file"
+		}
+		svc.Volumes = append(svc.Volumes, types.Volume{
+			VolumeName:     volume.Name,
+			VolumePath:     volume.Path,
+			VolumeType:     volumeType,
+			VolumeCapacity: volume.Capacity,
+		})
+	}
+	for _, cf := range spec.ConfigFiles {
+		svc.ConfigFiles = append(svc.ConfigFiles, types.ConfigFile{
+			Name:       cf.Name,
+			VolumePath: cf.Path,
+			Content:    cf.Content,
+		})
+	}
+	svc.Depends = append(svc.Depends, spec.Dependencies...)
+	for _, domain := range spec.Domains {
+		svc.Domains = append(svc.Domains, types.Domain{
+			Domain: domain.Domain,
+			Port:   domain.Port,
+			Path:   domain.Path,
+		})
+	}
+}
+
+// moduleComponentSpec returns the spec of the module in a multi-module project. The probes and
+// resources of the project are the defaults of the module, but the volumes, config files,
+// dependencies and domains only belong to the module which declares them.
+func moduleComponentSpec(project, module *code.ComponentSpec) *code.ComponentSpec {
+	spec := *module
+	if len(spec.Probes) == 0 {
+		spec.Probes = project.Probes
+	}
+	if spec.Resources == nil {
+		spec.Resources = project.Resources
+	}
+	return &spec
+}
+
+// applyComponent sets the spec declared by katofile to the service info.
+func (s *ServiceInfo) applyComponent(svc *types.Service) {
+	s.Probes = svc.Probes
+	s.CPU = svc.CPU
+	if svc.Memory > 0 {
+		s.Memory = svc.Memory
+	}
+	s.Volumes = append(append([]types.Volume{}, s.Volumes...), svc.Volumes...)
+	s.ConfigFiles = svc.ConfigFiles
+	s.DependServices = append(append([]string{}, s.DependServices...), svc.Depends...)
+	s.Domains = svc.Domains
+}
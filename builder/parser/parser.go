@@ -191,6 +191,13 @@ type ServiceInfo struct {
 	Name      string `json:"name,omitempty"`  // module name
 	Cname     string `json:"cname,omitempty"` // service cname
 	Packaging string `json:"packaging,omitempty"`
+	// the dir relative to the project root where the module is built
+	BuildPath string `json:"build_path,omitempty"`
+	//declared by katofile
+	Probes      []types.Probe      `json:"probes,omitempty"`
+	CPU         int                `json:"cpu,omitempty"`
+	ConfigFiles []types.ConfigFile `json:"config_files,omitempty"`
+	Domains     []types.Domain     `json:"domains,omitempty"`
 }
 
 //GetServiceInfo
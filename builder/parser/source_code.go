@@ -61,6 +61,8 @@ type SourceCodeParse struct {
 
 	isMulti  bool
 	services []*types.Service
+	// the spec declared by katofile
+	component types.Service
 }
 
 //CreateSourceCodeParse
@@ -194,7 +196,7 @@ func (d *SourceCodeParse) Parse() ParseErrorList {
 		return nil
 	}
 	logrus.Debugf("start get service code by %s server type", csi.ServerType)
-	
+
 	//Get the code repository
 	switch csi.ServerType {
 	case "git":
@@ -224,10 +226,21 @@ func (d *SourceCodeParse) Parse() ParseErrorList {
 	//read katofile
 	rbdfileConfig, err := code.ReadKatoFile(buildInfo.GetCodeBuildAbsPath())
 	if err != nil {
+		if verrs, ok := err.(code.ValidationError); ok {
+			for _, verr := range verrs {
+				d.errappend(ErrorAndSolve(FatalError, fmt.Sprintf("The katofile definition is incorrect: %s", verr), "You can refer to the document description to configure this file to define application attributes"))
+			}
+			return d.errors
+		}
 		if err != code.ErrKatoFileNotFound {
 			d.errappend(ErrorAndSolve(NegligibleError, "The katofile definition format is incorrect", "You can refer to the document description to configure this file to define application attributes"))
 		}
 	}
+	if rbdfileConfig != nil {
+		for _, warning := range rbdfileConfig.Warnings {
+			d.errappend(ErrorAndSolve(NegligibleError, fmt.Sprintf("The katofile definition is incorrect: %s", warning), "You can refer to the document description to configure this file to define application attributes"))
+		}
+	}
 	//Judgment target directory
 	var buildPath = buildInfo.GetCodeBuildAbsPath()
 	//Parse code type
@@ -316,6 +329,7 @@ func (d *SourceCodeParse) Parse() ParseErrorList {
 			}
 			for _, svc := range rbdfileConfig.Services {
 				if item := mm[svc.Name]; item != nil {
+					applyComponentSpec(item, moduleComponentSpec(&rbdfileConfig.ComponentSpec, &svc.ComponentSpec))
 					for k, v := range svc.Envs {
 						if item.Envs == nil {
 							item.Envs = make(map[string]*types.Env, len(rbdfileConfig.Envs))
@@ -377,6 +391,9 @@ func (d *SourceCodeParse) Parse() ParseErrorList {
 		if rbdfileConfig.Cmd != "" {
 			d.args = strings.Split(rbdfileConfig.Cmd, " ")
 		}
+		if !d.isMulti {
+			applyComponentSpec(&d.component, &rbdfileConfig.ComponentSpec)
+		}
 	}
 	return d.errors
 }
@@ -497,6 +514,8 @@ func (d *SourceCodeParse) GetServiceInfo() []ServiceInfo {
 			info.Name = svc.Name
 			info.Cname = svc.Cname
 			info.Packaging = svc.Packaging
+			info.BuildPath = svc.BuildPath
+			info.applyComponent(svc)
 			for i := range svc.Envs {
 				info.Envs = append(info.Envs, *svc.Envs[i])
 			}
@@ -508,6 +527,7 @@ func (d *SourceCodeParse) GetServiceInfo() []ServiceInfo {
 	} else {
 		serviceInfo.Envs = d.GetEnvs()
 		serviceInfo.Ports = d.GetPorts()
+		serviceInfo.applyComponent(&d.component)
 		res = []ServiceInfo{serviceInfo}
 	}
 
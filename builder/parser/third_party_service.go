@@ -63,6 +63,12 @@ func (t *ThirdPartyServiceParse) Parse() ParseErrorList {
 	// TODO: validate data
 
 	d := discovery.NewDiscoverier(&info)
+	if d == nil {
+		t.logger.Error("unsupported discovery type", map[string]string{"step": "parse"})
+		t.errors = append(t.errors, ParseError{FatalError, "unsupported discovery type " + info.Type, "supported " +
+			"types are etcd, consul and dns."})
+		return t.errors
+	}
 	err := d.Connect()
 	if err != nil {
 		t.logger.Error("error connecting discovery center", map[string]string{"step": "parse"})
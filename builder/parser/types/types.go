@@ -26,6 +26,17 @@ type Service struct {
 	Packaging string          `json:"packaging"`
 	Envs      map[string]*Env `json:"envs,omitempty"`
 	Ports     map[int]*Port   `json:"ports,omitempty"`
+	// BuildPath is the dir relative to the project root where the module is built, empty for the root
+	BuildPath string `json:"build_path,omitempty"`
+
+	// declared by katofile
+	Probes      []Probe      `json:"probes,omitempty"`
+	CPU         int          `json:"cpu,omitempty"`
+	Memory      int          `json:"memory,omitempty"`
+	Volumes     []Volume     `json:"volumes,omitempty"`
+	ConfigFiles []ConfigFile `json:"config_files,omitempty"`
+	Depends     []string     `json:"depends,omitempty"`
+	Domains     []Domain     `json:"domains,omitempty"`
 }
 
 //Port -
@@ -36,8 +47,10 @@ type Port struct {
 
 //Volume -
 type Volume struct {
-	VolumePath string `json:"volume_path"`
-	VolumeType string `json:"volume_type"`
+	VolumeName     string `json:"volume_name,omitempty"`
+	VolumePath     string `json:"volume_path"`
+	VolumeType     string `json:"volume_type"`
+	VolumeCapacity int64  `json:"volume_capacity,omitempty"`
 }
 
 //Env env desc
@@ -45,3 +58,31 @@ type Env struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
 }
+
+//Probe -
+type Probe struct {
+	Mode               string `json:"mode"`
+	Scheme             string `json:"scheme"`
+	Port               int    `json:"port,omitempty"`
+	Path               string `json:"path,omitempty"`
+	Cmd                string `json:"cmd,omitempty"`
+	InitialDelaySecond int    `json:"initial_delay_second,omitempty"`
+	PeriodSecond       int    `json:"period_second,omitempty"`
+	TimeoutSecond      int    `json:"timeout_second,omitempty"`
+	FailureThreshold   int    `json:"failure_threshold,omitempty"`
+	SuccessThreshold   int    `json:"success_threshold,omitempty"`
+}
+
+//ConfigFile -
+type ConfigFile struct {
+	Name       string `json:"name"`
+	VolumePath string `json:"volume_path"`
+	Content    string `json:"content"`
+}
+
+//Domain -
+type Domain struct {
+	Domain string `json:"domain"`
+	Port   int    `json:"port"`
+	Path   string `json:"path,omitempty"`
+}
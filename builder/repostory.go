@@ -36,6 +36,12 @@ func init() {
 	if os.Getenv("BUILD_IMAGE_REPOSTORY_PASS") != "" {
 		REGISTRYPASS = os.Getenv("BUILD_IMAGE_REPOSTORY_PASS")
 	}
+	if os.Getenv("RBD_DOCKER_SECRET") != "" {
+		REGISTRYCERTSECRET = os.Getenv("RBD_DOCKER_SECRET")
+	}
+	if os.Getenv("BUILD_IMAGE_REPOSTORY_INSECURE") == "true" {
+		REGISTRYINSECURE = true
+	}
 	RUNNERIMAGENAME = "/runner"
 	if os.Getenv("RUNNER_IMAGE_NAME") != "" {
 		RUNNERIMAGENAME = os.Getenv("RUNNER_IMAGE_NAME")
@@ -47,6 +53,11 @@ func init() {
 	}
 
 	BUILDERIMAGENAME = path.Join(REGISTRYDOMAIN, BUILDERIMAGENAME)
+	KANIKOIMAGENAME = "kaniko-executor"
+	if os.Getenv("KANIKO_IMAGE_NAME") != "" {
+		KANIKOIMAGENAME = os.Getenv("KANIKO_IMAGE_NAME")
+	}
+	KANIKOIMAGENAME = path.Join(REGISTRYDOMAIN, KANIKOIMAGENAME)
 }
 
 // GetImageUserInfoV2 -
@@ -77,8 +88,18 @@ var REGISTRYUSER = ""
 //REGISTRYPASS REGISTRY PASSWORD
 var REGISTRYPASS = ""
 
+//REGISTRYCERTSECRET the secret of the rbd namespace holding the CA certificate of the registry in the key 'cert',
+//which is the same secret the nodes sync the certificate of docker and containerd from
+var REGISTRYCERTSECRET = ""
+
+//REGISTRYINSECURE the registry is pushed to without verifying its certificate in the kaniko jobs
+var REGISTRYINSECURE = false
+
 //RUNNERIMAGENAME runner image name
 var RUNNERIMAGENAME string
 
 //BUILDERIMAGENAME builder image name
 var BUILDERIMAGENAME string
+
+//KANIKOIMAGENAME kaniko executor image name, used to build dockerfile without docker daemon
+var KANIKOIMAGENAME string
@@ -0,0 +1,85 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sbom
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/gridworkz/kato/builder/sources/registry"
+	"github.com/opencontainers/go-digest"
+)
+
+//Generate generates the SPDX document of the image in the registry. The packages of the linux/amd64
+//image, or the first image if there is no linux/amd64 image, are listed for a manifest list.
+func Generate(reg *registry.Registry, repository string, dgst digest.Digest, image string) (*Document, error) {
+	manifest, _, err := reg.ManifestRaw(repository, dgst.String())
+	if err != nil {
+		return nil, fmt.Errorf("get manifest: %v", err)
+	}
+	if list, ok := manifest.(*manifestlist.DeserializedManifestList); ok {
+		if len(list.Manifests) == 0 {
+			return nil, fmt.Errorf("manifest list %s is empty", dgst)
+		}
+		desc := list.Manifests[0]
+		for _, m := range list.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				desc = m
+				break
+			}
+		}
+		if manifest, _, err = reg.ManifestRaw(repository, desc.Digest.String()); err != nil {
+			return nil, fmt.Errorf("get manifest of platform %s/%s: %v", desc.Platform.OS, desc.Platform.Architecture, err)
+		}
+	}
+	scanner := NewScanner()
+	for _, layer := range layers(manifest) {
+		if err := addLayer(scanner, reg, repository, layer); err != nil {
+			return nil, fmt.Errorf("read layer %s: %v", layer.Digest, err)
+		}
+	}
+	return NewDocument(image, dgst.String(), scanner.Packages()), nil
+}
+
+//layers returns the layers of the image, the first reference of the manifest is the config
+func layers(manifest distribution.Manifest) []distribution.Descriptor {
+	refs := manifest.References()
+	if len(refs) < 2 {
+		return nil
+	}
+	var res []distribution.Descriptor
+	for _, ref := range refs[1:] {
+		// foreign layers are not stored in the registry
+		if len(ref.URLs) > 0 {
+			continue
+		}
+		res = append(res, ref)
+	}
+	return res
+}
+
+func addLayer(scanner *Scanner, reg *registry.Registry, repository string, layer distribution.Descriptor) error {
+	blob, err := reg.DownloadBlob(repository, layer.Digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	return scanner.AddLayer(blob)
+}
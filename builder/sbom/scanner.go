@@ -0,0 +1,303 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sbom
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// the files larger than this are not read, the package databases are much smaller
+const maxFileSize = 32 << 20
+
+//Package is the software package installed in the image
+type Package struct {
+	Name    string
+	Version string
+	// the type of the package url, deb, apk, npm or pypi
+	Type string
+	// the namespace of the package url, such as debian or alpine
+	Namespace string
+	License   string
+}
+
+//PURL returns the package url of the package, see https://github.com/package-url/purl-spec
+func (p *Package) PURL() string {
+	name := p.Name
+	if strings.HasPrefix(name, "@") {
+		name = "%40" + name[1:]
+	}
+	if p.Namespace != "" {
+		name = p.Namespace + "/" + name
+	}
+	purl := "pkg:" + p.Type + "/" + name
+	if p.Version != "" {
+		purl += "@" + p.Version
+	}
+	return purl
+}
+
+//Scanner finds the packages in the layers of the image. The layers are added from the lowest one,
+//so that the files of the upper layers overwrite or delete the files of the lower layers.
+type Scanner struct {
+	files map[string][]byte
+}
+
+//NewScanner creates a scanner
+func NewScanner() *Scanner {
+	return &Scanner{files: make(map[string][]byte)}
+}
+
+//AddLayer reads the files of the layer, which is a tar archive compressed by gzip or not.
+func (s *Scanner) AddLayer(layer io.Reader) error {
+	br := bufio.NewReader(layer)
+	var r io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		dir, base := path.Split(name)
+		if base == ".wh..wh..opq" {
+			s.remove(strings.TrimSuffix(dir, "/"), false)
+			continue
+		}
+		if strings.HasPrefix(base, ".wh.") {
+			s.remove(path.Join(dir, strings.TrimPrefix(base, ".wh.")), true)
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		if !interesting(name) || hdr.Size > maxFileSize {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		s.files[name] = content
+	}
+}
+
+//remove removes the files in the dir, and the dir itself if self is true
+func (s *Scanner) remove(dir string, self bool) {
+	for name := range s.files {
+		if (self && name == dir) || strings.HasPrefix(name, dir+"/") {
+			delete(s.files, name)
+		}
+	}
+}
+
+func interesting(name string) bool {
+	switch {
+	case name == "var/lib/dpkg/status", name == "lib/apk/db/installed":
+		return true
+	case name == "etc/os-release", name == "usr/lib/os-release":
+		return true
+	case strings.HasPrefix(name, "var/lib/dpkg/status.d/") && !strings.HasSuffix(name, ".md5sums"):
+		return true
+	case strings.HasSuffix(name, ".dist-info/METADATA"), strings.HasSuffix(name, ".egg-info/PKG-INFO"):
+		return true
+	case strings.HasSuffix(name, "/package.json"):
+		return isNodeModule(name)
+	}
+	return false
+}
+
+//isNodeModule checks if the file is node_modules/name/package.json or node_modules/@scope/name/package.json
+func isNodeModule(name string) bool {
+	parts := strings.Split(path.Dir(name), "/")
+	n := len(parts)
+	if n >= 2 && parts[n-2] == "node_modules" && !strings.HasPrefix(parts[n-1], "@") {
+		return true
+	}
+	return n >= 3 && parts[n-3] == "node_modules" && strings.HasPrefix(parts[n-2], "@")
+}
+
+//Packages returns the packages found in the layers, sorted by the type and name
+func (s *Scanner) Packages() []Package {
+	distro := s.distro()
+	var pkgs []Package
+	for name, content := range s.files {
+		switch {
+		case name == "var/lib/dpkg/status", strings.HasPrefix(name, "var/lib/dpkg/status.d/"):
+			pkgs = append(pkgs, parseDpkg(content, distro)...)
+		case name == "lib/apk/db/installed":
+			pkgs = append(pkgs, parseApk(content, distro)...)
+		case strings.HasSuffix(name, "/package.json"):
+			if pkg := parseNpm(content); pkg != nil {
+				pkgs = append(pkgs, *pkg)
+			}
+		case strings.HasSuffix(name, "/METADATA"), strings.HasSuffix(name, "/PKG-INFO"):
+			if pkg := parsePython(content); pkg != nil {
+				pkgs = append(pkgs, *pkg)
+			}
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Type != pkgs[j].Type {
+			return pkgs[i].Type < pkgs[j].Type
+		}
+		if pkgs[i].Name != pkgs[j].Name {
+			return pkgs[i].Name < pkgs[j].Name
+		}
+		return pkgs[i].Version < pkgs[j].Version
+	})
+	return dedup(pkgs)
+}
+
+func dedup(pkgs []Package) []Package {
+	var res []Package
+	for i, pkg := range pkgs {
+		if i > 0 && pkg == pkgs[i-1] {
+			continue
+		}
+		res = append(res, pkg)
+	}
+	return res
+}
+
+//distro returns the ID in os-release, such as debian, ubuntu or alpine
+func (s *Scanner) distro() string {
+	content, ok := s.files["etc/os-release"]
+	if !ok {
+		content = s.files["usr/lib/os-release"]
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, "ID=") {
+			return strings.Trim(strings.TrimPrefix(line, "ID="), `"'`)
+		}
+	}
+	return ""
+}
+
+//paragraphs splits the content by the blank lines, and returns the fields of every paragraph.
+//The continuation lines starting with a space are ignored.
+func paragraphs(content []byte, sep string) []map[string]string {
+	var res []map[string]string
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(fields) > 0 {
+				res = append(res, fields)
+				fields = make(map[string]string)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if kv := strings.SplitN(line, sep, 2); len(kv) == 2 {
+			fields[kv[0]] = strings.TrimSpace(kv[1])
+		}
+	}
+	if len(fields) > 0 {
+		res = append(res, fields)
+	}
+	return res
+}
+
+func parseDpkg(content []byte, distro string) []Package {
+	if distro == "" {
+		distro = "debian"
+	}
+	var pkgs []Package
+	for _, fields := range paragraphs(content, ":") {
+		// the packages removed but not purged are left in the status file
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		if fields["Package"] == "" {
+			continue
+		}
+		pkgs = append(pkgs, Package{Name: fields["Package"], Version: fields["Version"], Type: "deb", Namespace: distro})
+	}
+	return pkgs
+}
+
+func parseApk(content []byte, distro string) []Package {
+	if distro == "" {
+		distro = "alpine"
+	}
+	var pkgs []Package
+	for _, fields := range paragraphs(content, ":") {
+		if fields["P"] == "" {
+			continue
+		}
+		pkgs = append(pkgs, Package{Name: fields["P"], Version: fields["V"], Type: "apk", Namespace: distro, License: fields["L"]})
+	}
+	return pkgs
+}
+
+func parseNpm(content []byte) *Package {
+	var pkg struct {
+		Name    string          `json:"name"`
+		Version string          `json:"version"`
+		License json.RawMessage `json:"license"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil || pkg.Name == "" {
+		return nil
+	}
+	// the license is a string, or an object with the type in the legacy packages
+	var license string
+	if err := json.Unmarshal(pkg.License, &license); err != nil {
+		var legacy struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(pkg.License, &legacy); err == nil {
+			license = legacy.Type
+		}
+	}
+	return &Package{Name: pkg.Name, Version: pkg.Version, Type: "npm", License: license}
+}
+
+func parsePython(content []byte) *Package {
+	// the headers end with the first blank line, the description follows
+	metadata := paragraphs(content, ":")
+	if len(metadata) == 0 || metadata[0]["Name"] == "" {
+		return nil
+	}
+	fields := metadata[0]
+	return &Package{Name: fields["Name"], Version: fields["Version"], Type: "pypi", License: fields["License"]}
+}
@@ -0,0 +1,123 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+)
+
+func layer(t *testing.T, compress bool, files map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	var tw *tar.Writer
+	var gw *gzip.Writer
+	if compress {
+		gw = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gw)
+	} else {
+		tw = tar.NewWriter(&buf)
+	}
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+func TestScanner(t *testing.T) {
+	base := layer(t, true, map[string]string{
+		"etc/os-release": "NAME=\"Debian GNU/Linux\"\nID=debian\n",
+		"var/lib/dpkg/status": `Package: libc6
+Status: install ok installed
+Version: 2.31-13
+Description: GNU C Library
+ continuation line
+
+Package: removed
+Status: deinstall ok config-files
+Version: 1.0
+`,
+		"app/node_modules/express/package.json":                       `{"name":"express","version":"4.17.1","license":"MIT"}`,
+		"app/node_modules/@types/node/package.json":                   `{"name":"@types/node","version":"14.0.0","license":{"type":"MIT"}}`,
+		"app/node_modules/lodash/package.json":                        `{"name":"lodash","version":"4.17.20"}`,
+		"usr/lib/python3/site-packages/six-1.15.0.dist-info/METADATA": "Metadata-Version: 2.1\nName: six\nVersion: 1.15.0\nLicense: MIT\n\nSix is a Python 2 and 3 compatibility library.\n",
+	})
+	// the upper layer deletes lodash and updates express
+	upper := layer(t, false, map[string]string{
+		"app/node_modules/.wh.lodash":           "",
+		"app/node_modules/express/package.json": `{"name":"express","version":"4.17.2","license":"MIT"}`,
+	})
+	s := NewScanner()
+	if err := s.AddLayer(base); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddLayer(upper); err != nil {
+		t.Fatal(err)
+	}
+	var purls []string
+	for _, pkg := range s.Packages() {
+		purls = append(purls, pkg.PURL())
+	}
+	want := []string{
+		"pkg:deb/debian/libc6@2.31-13",
+		"pkg:npm/%40types/node@14.0.0",
+		"pkg:npm/express@4.17.2",
+		"pkg:pypi/six@1.15.0",
+	}
+	if !reflect.DeepEqual(purls, want) {
+		t.Errorf("want %v, got %v", want, purls)
+	}
+}
+
+func TestScannerApk(t *testing.T) {
+	s := NewScanner()
+	err := s.AddLayer(layer(t, false, map[string]string{
+		"etc/os-release":            "ID=alpine\n",
+		"lib/apk/db/installed":      "P:musl\nV:1.2.2-r0\nL:MIT\n\nP:busybox\nV:1.32.1-r6\nL:GPL-2.0-only\n",
+		"usr/share/doc/ignored.txt": "not a package",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs := s.Packages()
+	if len(pkgs) != 2 {
+		t.Fatalf("want 2 packages, got %v", pkgs)
+	}
+	for _, pkg := range pkgs {
+		if pkg.Type != "apk" || pkg.Namespace != "alpine" || pkg.License == "" {
+			t.Errorf("unexpected package %+v", pkg)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sbom
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gridworkz/kato/util"
+)
+
+//SPDXMediaType is the media type of the SPDX document attached to the image
+const SPDXMediaType = "text/spdx+json"
+
+const noAssertion = "NOASSERTION"
+
+// the license which is not a valid SPDX license expression is reported as NOASSERTION
+var licenseExpression = regexp.MustCompile(`^[A-Za-z0-9.+-]+( (AND|OR|WITH) [A-Za-z0-9.+-]+)*$`)
+
+//Document is the SPDX 2.3 document in json, see https://spdx.github.io/spdx-spec/v2.3/
+type Document struct {
+	SPDXVersion       string         `json:"spdxVersion"`
+	DataLicense       string         `json:"dataLicense"`
+	SPDXID            string         `json:"SPDXID"`
+	Name              string         `json:"name"`
+	DocumentNamespace string         `json:"documentNamespace"`
+	CreationInfo      CreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage  `json:"packages"`
+	Relationships     []Relationship `json:"relationships"`
+}
+
+//CreationInfo -
+type CreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+//SPDXPackage -
+type SPDXPackage struct {
+	SPDXID           string        `json:"SPDXID"`
+	Name             string        `json:"name"`
+	VersionInfo      string        `json:"versionInfo,omitempty"`
+	DownloadLocation string        `json:"downloadLocation"`
+	FilesAnalyzed    bool          `json:"filesAnalyzed"`
+	LicenseConcluded string        `json:"licenseConcluded"`
+	LicenseDeclared  string        `json:"licenseDeclared"`
+	CopyrightText    string        `json:"copyrightText"`
+	ExternalRefs     []ExternalRef `json:"externalRefs,omitempty"`
+}
+
+//ExternalRef -
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+//Relationship -
+type Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+//NewDocument creates the SPDX document of the image, which contains the packages.
+func NewDocument(image, digest string, pkgs []Package) *Document {
+	doc := &Document{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              image,
+		DocumentNamespace: fmt.Sprintf("https://kato.io/spdx/%s", util.NewUUID()),
+		CreationInfo: CreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: kato-builder"},
+		},
+	}
+	doc.Packages = append(doc.Packages, SPDXPackage{
+		SPDXID:           "SPDXRef-Image",
+		Name:             image,
+		VersionInfo:      digest,
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+	})
+	doc.Relationships = append(doc.Relationships, Relationship{
+		SPDXElementID:      doc.SPDXID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: "SPDXRef-Image",
+	})
+	for i, pkg := range pkgs {
+		license := noAssertion
+		if licenseExpression.MatchString(pkg.License) {
+			license = pkg.License
+		}
+		id := fmt.Sprintf("SPDXRef-Package-%s-%d", pkg.Type, i)
+		doc.Packages = append(doc.Packages, SPDXPackage{
+			SPDXID:           id,
+			Name:             pkg.Name,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    noAssertion,
+			ExternalRefs: []ExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  pkg.PURL(),
+			}},
+		})
+		doc.Relationships = append(doc.Relationships, Relationship{
+			SPDXElementID:      "SPDXRef-Image",
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: id,
+		})
+	}
+	return doc
+}
@@ -0,0 +1,297 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/gridworkz/kato/builder/sources/registry"
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// the media types and annotations used by cosign, see https://github.com/sigstore/cosign/blob/main/specs/SIGNATURE_SPEC.md
+const (
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	SignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	signatureType          = "cosign container image signature"
+)
+
+// the errors of the verification
+var (
+	ErrNotSigned = errors.New("the image is not signed")
+	ErrUntrusted = errors.New("the image is not signed by a trusted key")
+)
+
+//Payload is the simple signing payload signed by cosign
+type Payload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+//Signer signs the images with the ECDSA key
+type Signer struct {
+	key *ecdsa.PrivateKey
+}
+
+//NewSigner creates a signer
+func NewSigner(key *ecdsa.PrivateKey) *Signer {
+	return &Signer{key: key}
+}
+
+//PublicKey returns the public key which verifies the signatures of the signer
+func (s *Signer) PublicKey() *ecdsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+//Sign signs the image, the signature is pushed to the tag 'sha256-<hex>.sig' in the same repository
+//as cosign does, so that it can be verified by 'cosign verify --key cosign.pub <image>'.
+//The dockerReference is the repository of the image with the registry domain.
+func (s *Signer) Sign(reg *registry.Registry, repository string, dgst digest.Digest, dockerReference string) error {
+	var payload Payload
+	payload.Critical.Identity.DockerReference = dockerReference
+	payload.Critical.Image.DockerManifestDigest = dgst.String()
+	payload.Critical.Type = signatureType
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	sig, err := s.sign(content)
+	if err != nil {
+		return err
+	}
+	layer := distribution.Descriptor{
+		MediaType:   SimpleSigningMediaType,
+		Digest:      digest.FromBytes(content),
+		Size:        int64(len(content)),
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	}
+	// the previous signature of the same payload by this key is replaced
+	replaced := func(desc distribution.Descriptor) bool {
+		return desc.Digest == layer.Digest && verifyAnnotation(desc, content, []*ecdsa.PublicKey{s.PublicKey()}) != nil
+	}
+	return attach(reg, repository, Tag(dgst, "sig"), layer, content, replaced)
+}
+
+//sign signs the sha256 digest of the content, the signature is ASN.1 encoded
+func (s *Signer) sign(content []byte) ([]byte, error) {
+	hash := sha256.Sum256(content)
+	r, ss, err := ecdsa.Sign(rand.Reader, s.key, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ecdsaSignature{R: r, S: ss})
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+//Verify verifies the image is signed by one of the keys, and returns the key. ErrNotSigned is returned if there is no
+//signature, ErrUntrusted is returned if no signature is signed by the keys.
+func Verify(reg *registry.Registry, repository string, dgst digest.Digest, keys []*ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	m, err := attachment(reg, repository, Tag(dgst, "sig"))
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || len(m.Layers) == 0 {
+		return nil, ErrNotSigned
+	}
+	for _, layer := range m.Layers {
+		if layer.MediaType != SimpleSigningMediaType {
+			continue
+		}
+		content, err := download(reg, repository, layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("download signature payload: %v", err)
+		}
+		var payload Payload
+		if err := json.Unmarshal(content, &payload); err != nil {
+			continue
+		}
+		// the signature of another image can not be copied to this image
+		if payload.Critical.Image.DockerManifestDigest != dgst.String() {
+			continue
+		}
+		if key := verifyAnnotation(layer, content, keys); key != nil {
+			return key, nil
+		}
+	}
+	return nil, ErrUntrusted
+}
+
+//verifyAnnotation returns the key the signature in the annotation of the layer is signed by, nil if none
+func verifyAnnotation(layer distribution.Descriptor, content []byte, keys []*ecdsa.PublicKey) *ecdsa.PublicKey {
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[SignatureAnnotation])
+	if err != nil {
+		return nil
+	}
+	var es ecdsaSignature
+	if _, err := asn1.Unmarshal(sig, &es); err != nil {
+		return nil
+	}
+	hash := sha256.Sum256(content)
+	for _, key := range keys {
+		if ecdsa.Verify(key, hash[:], es.R, es.S) {
+			return key
+		}
+	}
+	return nil
+}
+
+//AttachSBOM pushes the SBOM of the image to the tag 'sha256-<hex>.sbom' as 'cosign attach sbom' does,
+//and returns the digest of the manifest of the SBOM, which can be signed too.
+func AttachSBOM(reg *registry.Registry, repository string, dgst digest.Digest, sbom []byte, mediaType string) (digest.Digest, error) {
+	layer := distribution.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(sbom),
+		Size:      int64(len(sbom)),
+	}
+	// there is only one SBOM for an image
+	replaced := func(distribution.Descriptor) bool { return true }
+	tag := Tag(dgst, "sbom")
+	if err := attach(reg, repository, tag, layer, sbom, replaced); err != nil {
+		return "", err
+	}
+	return reg.ManifestDigest(repository, tag)
+}
+
+//Tag returns the tag of the attachment of the image, such as 'sha256-<hex>.sig'
+func Tag(dgst digest.Digest, suffix string) string {
+	return fmt.Sprintf("%s-%s.%s", dgst.Algorithm(), dgst.Hex(), suffix)
+}
+
+//attachment returns the manifest of the attachment, nil if the tag does not exist
+func attachment(reg *registry.Registry, repository, tag string) (*ocischema.DeserializedManifest, error) {
+	tags, err := reg.Tags(repository)
+	if err != nil {
+		return nil, fmt.Errorf("list tags of %s: %v", repository, err)
+	}
+	found := false
+	for _, t := range tags {
+		if t == tag {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	m, _, err := reg.ManifestRaw(repository, tag)
+	if err != nil {
+		return nil, fmt.Errorf("get manifest %s:%s: %v", repository, tag, err)
+	}
+	oci, ok := m.(*ocischema.DeserializedManifest)
+	if !ok {
+		return nil, fmt.Errorf("%s:%s is not an OCI image manifest", repository, tag)
+	}
+	return oci, nil
+}
+
+//attach pushes the layer to the manifest of the tag, the layers of the manifest are kept unless they are replaced.
+func attach(reg *registry.Registry, repository, tag string, layer distribution.Descriptor, content []byte, replaced func(distribution.Descriptor) bool) error {
+	existing, err := attachment(reg, repository, tag)
+	if err != nil {
+		return err
+	}
+	var layers []distribution.Descriptor
+	if existing != nil {
+		for _, l := range existing.Layers {
+			if !replaced(l) {
+				layers = append(layers, l)
+			}
+		}
+	}
+	layers = append(layers, layer)
+	if err := upload(reg, repository, layer.Digest, content); err != nil {
+		return err
+	}
+	config := v1.Image{RootFS: v1.RootFS{Type: "layers"}}
+	for _, l := range layers {
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, l.Digest)
+	}
+	configContent, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	configDesc := distribution.Descriptor{
+		MediaType: v1.MediaTypeImageConfig,
+		Digest:    digest.FromBytes(configContent),
+		Size:      int64(len(configContent)),
+	}
+	if err := upload(reg, repository, configDesc.Digest, configContent); err != nil {
+		return err
+	}
+	m, err := ocischema.FromStruct(ocischema.Manifest{
+		Versioned: manifest.Versioned{SchemaVersion: 2, MediaType: v1.MediaTypeImageManifest},
+		Config:    configDesc,
+		Layers:    layers,
+	})
+	if err != nil {
+		return err
+	}
+	return reg.PutManifestRaw(repository, tag, m)
+}
+
+func upload(reg *registry.Registry, repository string, dgst digest.Digest, content []byte) error {
+	exist, err := reg.HasBlob(repository, dgst)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	return reg.UploadBlob(repository, dgst, bytes.NewReader(content), int64(len(content)))
+}
+
+func download(reg *registry.Registry, repository string, dgst digest.Digest) ([]byte, error) {
+	blob, err := reg.DownloadBlob(repository, dgst)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(blob); err != nil {
+		return nil, err
+	}
+	if digest.FromBytes(buf.Bytes()) != dgst {
+		return nil, fmt.Errorf("digest of blob %s mismatch", dgst)
+	}
+	return buf.Bytes(), nil
+}
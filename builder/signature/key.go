@@ -0,0 +1,179 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// the keys in the secret of the image signing key, which are the same as the secret created by
+// 'cosign generate-key-pair k8s://<namespace>/<name>'
+const (
+	SecretPrivateKey = "cosign.key"
+	SecretPassword   = "cosign.password"
+	SecretPublicKey  = "cosign.pub"
+)
+
+//DefaultSecretName is the default name of the secret of the image signing key
+const DefaultSecretName = "kato-image-signing"
+
+//ErrNoSigningKey the image signing key is not configured
+var ErrNoSigningKey = errors.New("image signing key is not configured")
+
+//LoadSigner loads the signing key from the secret, ErrNoSigningKey is returned if the secret does not exist.
+func LoadSigner(cli kubernetes.Interface, namespace, name string) (*Signer, error) {
+	secret, err := cli.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, ErrNoSigningKey
+		}
+		return nil, fmt.Errorf("get secret %s/%s: %v", namespace, name, err)
+	}
+	data, ok := secret.Data[SecretPrivateKey]
+	if !ok {
+		return nil, ErrNoSigningKey
+	}
+	key, err := ParsePrivateKey(data, secret.Data[SecretPassword])
+	if err != nil {
+		return nil, fmt.Errorf("parse the private key in secret %s/%s: %v", namespace, name, err)
+	}
+	return &Signer{key: key}, nil
+}
+
+//ParsePrivateKey parses the ECDSA private key in PEM, the encrypted private key of cosign is supported.
+func ParsePrivateKey(data, password []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	var der []byte
+	switch block.Type {
+	case "ENCRYPTED COSIGN PRIVATE KEY", "ENCRYPTED SIGSTORE PRIVATE KEY":
+		decrypted, err := decrypt(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		der = decrypted
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		der = block.Bytes
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T, only ECDSA is supported", key)
+	}
+	return ecKey, nil
+}
+
+//encryptedKey is the private key encrypted by cosign with scrypt and nacl/secretbox
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func decrypt(data, password []byte) ([]byte, error) {
+	var enc encryptedKey
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("invalid encrypted key: %v", err)
+	}
+	if enc.KDF.Name != "scrypt" || enc.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported kdf %s or cipher %s", enc.KDF.Name, enc.Cipher.Name)
+	}
+	if len(enc.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid nonce")
+	}
+	secret, err := scrypt.Key(password, enc.KDF.Salt, enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], secret)
+	copy(nonce[:], enc.Cipher.Nonce)
+	decrypted, ok := secretbox.Open(nil, enc.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, errors.New("decrypt the private key failure, the password may be wrong")
+	}
+	return decrypted, nil
+}
+
+//KeyFingerprint returns the sha256 fingerprint of the public key, such as 'sha256:<hex>'
+func KeyFingerprint(key *ecdsa.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ""
+	}
+	return digest.FromBytes(der).String()
+}
+
+//ParsePublicKeys parses the ECDSA public keys in PEM, there may be several keys in the data.
+func ParsePublicKeys(data []byte) ([]*ecdsa.PublicKey, error) {
+	var keys []*ecdsa.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported public key %T, only ECDSA is supported", key)
+		}
+		keys = append(keys, ecKey)
+	}
+	return keys, nil
+}
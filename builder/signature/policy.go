@@ -0,0 +1,151 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	dbmodel "github.com/gridworkz/kato/db/model"
+)
+
+//PlatformSigner is the signer recorded for the images verified by the platform signing key
+const PlatformSigner = "platform"
+
+//Policy is the image policy of the tenant
+type Policy struct {
+	Mode              string
+	RequireSignature  bool
+	TrustedRegistries []string
+	Keys              []*ecdsa.PublicKey
+}
+
+//NewPolicy creates the policy from the image policy of the tenant
+func NewPolicy(p *dbmodel.TenantImagePolicy) (*Policy, error) {
+	policy := &Policy{
+		Mode:             p.Mode,
+		RequireSignature: p.RequireSignature,
+	}
+	switch policy.Mode {
+	case dbmodel.ImagePolicyModeAudit, dbmodel.ImagePolicyModeEnforce:
+	default:
+		return nil, fmt.Errorf("unsupported mode %q, it should be %s or %s", p.Mode, dbmodel.ImagePolicyModeAudit, dbmodel.ImagePolicyModeEnforce)
+	}
+	for _, pattern := range strings.Split(p.TrustedRegistries, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid trusted registry pattern %q: %v", pattern, err)
+		}
+		policy.TrustedRegistries = append(policy.TrustedRegistries, pattern)
+	}
+	keys, err := ParsePublicKeys([]byte(p.PublicKeys))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public keys: %v", err)
+	}
+	policy.Keys = keys
+	return policy, nil
+}
+
+//Enforced returns true if the images violating the policy are refused, or they are reported only.
+func (p *Policy) Enforced() bool {
+	return p.Mode == dbmodel.ImagePolicyModeEnforce
+}
+
+//IsTrustedRegistry checks if the repository of the image matches one of the trusted registries.
+//A pattern without '/' matches the registry domain, such as 'goodrain.me', others match the
+//repository with the domain, such as 'docker.io/library/*'.
+func (p *Policy) IsTrustedRegistry(image string) bool {
+	if len(p.TrustedRegistries) == 0 {
+		return true
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false
+	}
+	domain, repository := reference.Domain(named), named.Name()
+	for _, pattern := range p.TrustedRegistries {
+		target := repository
+		if !strings.Contains(pattern, "/") {
+			target = domain
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+//TrustsSigner checks if the signer recorded when the image was verified is trusted by the policy,
+//the signer is the platform or the fingerprint of one of the keys of the tenant, see KeyFingerprint.
+func (p *Policy) TrustsSigner(signer string) bool {
+	if signer == "" {
+		return false
+	}
+	if signer == PlatformSigner {
+		return true
+	}
+	for _, key := range p.Keys {
+		if KeyFingerprint(key) == signer {
+			return true
+		}
+	}
+	return false
+}
+
+//CheckVersion checks the version to be deployed against the policy, nil is returned if it is allowed.
+//Only the images are checked, the versions built from source code into slugs are allowed.
+//The policy may have changed since the version was built, so the registry of the source image
+//and the signer verifying it are checked against the policy again.
+func (p *Policy) CheckVersion(version *dbmodel.VersionInfo) *Violation {
+	if version.DeliveredType != "image" {
+		return nil
+	}
+	// the source image is recorded as the repo url of the version built from image
+	fromImage := version.Kind == "build_from_image"
+	var reason string
+	switch {
+	case fromImage && !p.IsTrustedRegistry(version.RepoURL):
+		reason = fmt.Sprintf("the registry of image %s is not trusted", version.RepoURL)
+	case !p.RequireSignature:
+		return nil
+	case !version.Signed:
+		reason = "it is not signed"
+	case fromImage && !p.TrustsSigner(version.SignerKey):
+		reason = fmt.Sprintf("image %s is not verified by the keys of the image policy", version.RepoURL)
+	default:
+		return nil
+	}
+	return &Violation{Image: version.DeliveredPath, Reason: reason}
+}
+
+//Violation is the reason why the image is refused by the policy
+type Violation struct {
+	Image  string
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("image %s violates the image policy: %s", v.Image, v.Reason)
+}
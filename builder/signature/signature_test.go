@@ -0,0 +1,246 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/docker/distribution"
+	dbmodel "github.com/gridworkz/kato/db/model"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func publicKeyPEM(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// encrypt encrypts the private key as 'cosign generate-key-pair' does
+func encrypt(t *testing.T, key *ecdsa.PrivateKey, password []byte) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enc encryptedKey
+	enc.KDF.Name = "scrypt"
+	enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P = 1024, 8, 1
+	enc.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	enc.Cipher.Name = "nacl/secretbox"
+	enc.Cipher.Nonce = []byte("0123456789abcdef01234567")
+	secret, err := scrypt.Key(password, enc.KDF.Salt, 1024, 8, 1, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var k [32]byte
+	var nonce [24]byte
+	copy(k[:], secret)
+	copy(nonce[:], enc.Cipher.Nonce)
+	enc.Ciphertext = secretbox.Seal(nil, der, &nonce, &k)
+	data, err := json.Marshal(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED COSIGN PRIVATE KEY", Bytes: data})
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := generateKey(t)
+	ecDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		data     []byte
+		password string
+		wantErr  bool
+	}{
+		{name: "ec", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})},
+		{name: "pkcs8", data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER})},
+		{name: "cosign", data: encrypt(t, key, []byte("secret")), password: "secret"},
+		{name: "wrong password", data: encrypt(t, key, []byte("secret")), password: "wrong", wantErr: true},
+		{name: "not pem", data: []byte("foobar"), wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePrivateKey(tc.data, []byte(tc.password))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && got.D.Cmp(key.D) != 0 {
+				t.Errorf("the parsed key is different")
+			}
+		})
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	key1, key2 := generateKey(t), generateKey(t)
+	keys, err := ParsePublicKeys([]byte(publicKeyPEM(t, key1) + publicKeyPEM(t, key2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].X.Cmp(key1.X) != 0 || keys[1].X.Cmp(key2.X) != 0 {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
+
+func TestSignatureAnnotation(t *testing.T) {
+	signer := NewSigner(generateKey(t))
+	content := []byte(`{"critical":{"type":"cosign container image signature"}}`)
+	sig, err := signer.sign(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := distribution.Descriptor{
+		MediaType:   SimpleSigningMediaType,
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	}
+	other := generateKey(t)
+	if verifyAnnotation(layer, content, []*ecdsa.PublicKey{&other.PublicKey, signer.PublicKey()}) != signer.PublicKey() {
+		t.Errorf("the signature should be verified by the key of the signer")
+	}
+	if verifyAnnotation(layer, content, []*ecdsa.PublicKey{&other.PublicKey}) != nil {
+		t.Errorf("the signature should not be verified by other keys")
+	}
+	if verifyAnnotation(layer, []byte("tampered"), []*ecdsa.PublicKey{signer.PublicKey()}) != nil {
+		t.Errorf("the signature should not be verified with the tampered payload")
+	}
+}
+
+func TestTag(t *testing.T) {
+	dgst := digest.FromString("foo")
+	if got, want := Tag(dgst, "sig"), "sha256-"+dgst.Hex()+".sig"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	policy, err := NewPolicy(&dbmodel.TenantImagePolicy{
+		Mode:              dbmodel.ImagePolicyModeEnforce,
+		TrustedRegistries: "goodrain.me, docker.io/library/*,",
+		PublicKeys:        publicKeyPEM(t, generateKey(t)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.Enforced() || len(policy.Keys) != 1 {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+	if !policy.TrustsSigner(KeyFingerprint(policy.Keys[0])) || !policy.TrustsSigner(PlatformSigner) {
+		t.Errorf("the keys of the policy and the platform should be trusted")
+	}
+	if policy.TrustsSigner("") || policy.TrustsSigner(KeyFingerprint(&generateKey(t).PublicKey)) {
+		t.Errorf("the signer removed from the policy should not be trusted")
+	}
+	tests := map[string]bool{
+		"goodrain.me/foo/bar:v1":   true,
+		"nginx:1.19":               true,
+		"docker.io/library/redis":  true,
+		"bitnami/redis:6.0":        false,
+		"quay.io/coreos/etcd:3.4":  false,
+		"goodrain.me.evil.com/foo": false,
+	}
+	for image, want := range tests {
+		if got := policy.IsTrustedRegistry(image); got != want {
+			t.Errorf("image %s: want %v, got %v", image, want, got)
+		}
+	}
+
+	for _, p := range []*dbmodel.TenantImagePolicy{
+		{Mode: "block"},
+		{Mode: dbmodel.ImagePolicyModeAudit, TrustedRegistries: "[a-"},
+		{Mode: dbmodel.ImagePolicyModeAudit, PublicKeys: "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"},
+	} {
+		if _, err := NewPolicy(p); err == nil {
+			t.Errorf("policy %+v should be invalid", p)
+		}
+	}
+}
+
+func TestPolicyCheckVersion(t *testing.T) {
+	policy, err := NewPolicy(&dbmodel.TenantImagePolicy{
+		Mode:              dbmodel.ImagePolicyModeEnforce,
+		RequireSignature:  true,
+		TrustedRegistries: "goodrain.me",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		version *dbmodel.VersionInfo
+		allowed bool
+	}{
+		{
+			name:    "slug",
+			version: &dbmodel.VersionInfo{Kind: "build_from_source_code", DeliveredType: "slug"},
+			allowed: true,
+		},
+		{
+			name:    "unsigned build",
+			version: &dbmodel.VersionInfo{Kind: "build_from_source_code", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:v1"},
+		},
+		{
+			name:    "signed build",
+			version: &dbmodel.VersionInfo{Kind: "build_from_source_code", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:v1", Signed: true},
+			allowed: true,
+		},
+		{
+			name: "untrusted registry",
+			version: &dbmodel.VersionInfo{Kind: "build_from_image", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:v1",
+				RepoURL: "quay.io/foo:v1", Signed: true, SignerKey: PlatformSigner},
+		},
+		{
+			name: "signer removed from the policy",
+			version: &dbmodel.VersionInfo{Kind: "build_from_image", DeliveredType: "image", DeliveredPath: "goodrain.me/foo:v1",
+				RepoURL: "goodrain.me/foo:v1", Signed: true, SignerKey: "removed"},
+		},
+	}
+	for _, tc := range tests {
+		violation := policy.CheckVersion(tc.version)
+		if tc.allowed != (violation == nil) {
+			t.Errorf("%s: want allowed %v, got violation %v", tc.name, tc.allowed, violation)
+		}
+	}
+}
@@ -19,11 +19,19 @@
 package sources
 
 import (
+	"archive/tar"
 	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/gridworkz/kato/builder/sources/registry"
+	"github.com/gridworkz/kato/event"
 
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/sirupsen/logrus"
 )
 
@@ -51,27 +59,14 @@ func ImageExist(imageName, user, password string) (bool, error) {
 		logrus.Errorf("reference parse image name error: %s", err.Error())
 		return false, err
 	}
-	domain := reference.Domain(name)
-	if domain == "docker.io" {
-		domain = "registry-1.docker.io"
-	}
 	retry := 2
 	var rerr error
 	for retry > 0 {
 		retry--
-		reg, err := registry.New(domain, user, password)
+		reg, err := newRegistry(name, user, password)
 		if err != nil {
-			logrus.Debugf("new registry client failure %s", err.Error())
-			reg, err = registry.NewInsecure(domain, user, password)
-			if err != nil {
-				logrus.Debugf("new insecure registry client failure %s", err.Error())
-				reg, err = registry.NewInsecure("http://"+domain, user, password)
-				if err != nil {
-					logrus.Errorf("new insecure registry http or https client all failure %s", err.Error())
-					rerr = err
-					continue
-				}
-			}
+			rerr = err
+			continue
 		}
 		tag := GetTagFromNamedRef(name)
 		if err := reg.CheckManifest(reference.Path(name), tag); err != nil {
@@ -82,3 +77,192 @@ func ImageExist(imageName, user, password string) (bool, error) {
 	}
 	return false, rerr
 }
+
+//newRegistry creates the registry client of the image, https is tried before http
+func newRegistry(name reference.Named, user, password string) (*registry.Registry, error) {
+	domain := reference.Domain(name)
+	if domain == "docker.io" {
+		domain = "registry-1.docker.io"
+	}
+	reg, err := registry.New(domain, user, password)
+	if err != nil {
+		logrus.Debugf("new registry client failure %s", err.Error())
+		reg, err = registry.NewInsecure(domain, user, password)
+		if err != nil {
+			logrus.Debugf("new insecure registry client failure %s", err.Error())
+			reg, err = registry.NewInsecure("http://"+domain, user, password)
+			if err != nil {
+				logrus.Errorf("new insecure registry http or https client all failure %s", err.Error())
+				return nil, err
+			}
+		}
+	}
+	return reg, nil
+}
+
+//parseImage returns the registry client, the repository and the tag or digest of the image
+func parseImage(imageName, user, password string) (*registry.Registry, string, string, error) {
+	name, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("parse image name %s: %v", imageName, err)
+	}
+	reg, err := newRegistry(name, user, password)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return reg, reference.Path(name), GetTagFromNamedRef(name), nil
+}
+
+//IsManifestList checks if the image is a manifest list or an OCI index, which contains the images of several platforms
+func IsManifestList(imageName, user, password string) (bool, error) {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return false, err
+	}
+	if _, err := reg.ManifestList(repo, tag); err != nil {
+		if err == registry.ErrNotManifestList {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+//CopyImage copies the image between registries without docker daemon, manifest lists are copied with all the platforms
+func CopyImage(src, dst, srcUser, srcPassword, dstUser, dstPassword string, logger event.Logger) error {
+	srcReg, srcRepo, srcTag, err := parseImage(src, srcUser, srcPassword)
+	if err != nil {
+		return err
+	}
+	dstReg, dstRepo, dstTag, err := parseImage(dst, dstUser, dstPassword)
+	if err != nil {
+		return err
+	}
+	if logger != nil {
+		logger.Info(fmt.Sprintf("Start copy image %s to %s", src, dst), map[string]string{"step": "builder-exector"})
+	}
+	if err := registry.CopyImage(srcReg, srcRepo, srcTag, dstReg, dstRepo, dstTag); err != nil {
+		return fmt.Errorf("copy image %s to %s: %v", src, dst, err)
+	}
+	if logger != nil {
+		logger.Info(fmt.Sprintf("Copy image %s to %s success", src, dst), map[string]string{"step": "builder-exector"})
+	}
+	return nil
+}
+
+//CreateManifestList creates the manifest list imageName from the images of each platform,
+//the images of platforms must be pushed into the same repository of imageName.
+func CreateManifestList(imageName string, platformImages map[string]string, user, password string) error {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return err
+	}
+	var platforms []string
+	for platform := range platformImages {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	var descriptors []manifestlist.ManifestDescriptor
+	for _, platform := range platforms {
+		_, platformRepo, platformTag, err := parseImage(platformImages[platform], user, password)
+		if err != nil {
+			return err
+		}
+		if platformRepo != repo {
+			return fmt.Errorf("image %s is not in the repository %s", platformImages[platform], repo)
+		}
+		_, desc, err := reg.ManifestRaw(repo, platformTag)
+		if err != nil {
+			return fmt.Errorf("get manifest of %s: %v", platformImages[platform], err)
+		}
+		spec, err := ParsePlatform(platform)
+		if err != nil {
+			return err
+		}
+		descriptors = append(descriptors, manifestlist.ManifestDescriptor{Descriptor: desc, Platform: spec})
+	}
+	list, err := manifestlist.FromDescriptors(descriptors)
+	if err != nil {
+		return err
+	}
+	return reg.PutManifestRaw(repo, tag, list)
+}
+
+//ParsePlatform parses the platform like linux/amd64 or linux/arm64/v8
+func ParsePlatform(platform string) (manifestlist.PlatformSpec, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(platform)), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return manifestlist.PlatformSpec{}, fmt.Errorf("invalid platform %q, it should be like linux/amd64", platform)
+	}
+	spec := manifestlist.PlatformSpec{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		spec.Variant = parts[2]
+	}
+	return spec, nil
+}
+
+//ImageSaveOCI saves the image from the registry into a tar file of OCI image layout,
+//unlike ImageSave the images of all platforms of a manifest list are saved.
+func ImageSaveOCI(imageName, destination, user, password string) error {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return err
+	}
+	layoutDir := destination + ".oci"
+	defer os.RemoveAll(layoutDir)
+	if err := reg.SaveImage(repo, tag, layoutDir); err != nil {
+		return fmt.Errorf("save image %s: %v", imageName, err)
+	}
+	rc, err := archive.TarWithOptions(layoutDir, &archive.TarOptions{Compression: archive.Uncompressed})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return CopyToFile(destination, rc)
+}
+
+//ImageLoadOCI pushes the image in the tar file saved by ImageSaveOCI into the registry as imageName
+func ImageLoadOCI(tarFile, imageName, user, password string) error {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(tarFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	layoutDir := tarFile + ".oci"
+	defer os.RemoveAll(layoutDir)
+	if err := archive.Untar(file, layoutDir, &archive.TarOptions{NoLchown: true}); err != nil {
+		return fmt.Errorf("untar %s: %v", tarFile, err)
+	}
+	if err := reg.LoadImage(layoutDir, repo, tag); err != nil {
+		return fmt.Errorf("load image %s: %v", imageName, err)
+	}
+	return nil
+}
+
+//IsOCIArchive checks if the tar file is saved by ImageSaveOCI rather than docker save
+func IsOCIArchive(tarFile string) bool {
+	file, err := os.Open(tarFile)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	var layout, dockerManifest bool
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		switch path.Clean(header.Name) {
+		case "oci-layout":
+			layout = true
+		case "manifest.json":
+			dockerManifest = true
+		}
+	}
+	return layout && !dockerManifest
+}
@@ -0,0 +1,157 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package registry
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+// HasBlob checks if the blob exists in the repository.
+func (registry *Registry) HasBlob(repository string, digest digest.Digest) (bool, error) {
+	url := registry.url("/v2/%s/blobs/%s", repository, digest)
+	registry.Logf("registry.blob.head url=%s repository=%s digest=%s", url, repository, digest)
+
+	resp, err := registry.Client.Head(url)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		if e, ok := err.(*HttpStatusError); ok && e.Response.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return resp.StatusCode == http.StatusOK, checkResponse(resp)
+}
+
+// DownloadBlob returns the content of the blob, the caller should close it.
+func (registry *Registry) DownloadBlob(repository string, digest digest.Digest) (io.ReadCloser, error) {
+	url := registry.url("/v2/%s/blobs/%s", repository, digest)
+	registry.Logf("registry.blob.download url=%s repository=%s digest=%s", url, repository, digest)
+
+	resp, err := registry.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// UploadBlob uploads the content of the blob in a single request.
+func (registry *Registry) UploadBlob(repository string, digest digest.Digest, content io.Reader, size int64) error {
+	location, err := registry.initiateUpload(repository, nil)
+	if err != nil {
+		return err
+	}
+	uploadURL, err := registry.uploadURL(location, digest)
+	if err != nil {
+		return err
+	}
+	registry.Logf("registry.blob.upload url=%s repository=%s digest=%s", uploadURL, repository, digest)
+	req, err := http.NewRequest("PUT", uploadURL, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := registry.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+// MountBlob mounts the blob from another repository of the same registry,
+// false is returned if the registry does not support cross repository mount.
+func (registry *Registry) MountBlob(repository, from string, digest digest.Digest) (bool, error) {
+	query := url.Values{}
+	query.Set("mount", digest.String())
+	query.Set("from", from)
+	location, err := registry.initiateUpload(repository, query)
+	if err != nil {
+		return false, err
+	}
+	return location == "", nil
+}
+
+// initiateUpload starts an upload session and returns its location, the location is empty if the blob is mounted.
+func (registry *Registry) initiateUpload(repository string, query url.Values) (string, error) {
+	url := registry.url("/v2/%s/blobs/uploads/", repository)
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	registry.Logf("registry.blob.upload.initiate url=%s repository=%s", url, repository)
+	resp, err := registry.Client.Post(url, "", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return "", nil
+	case http.StatusAccepted:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return "", fmt.Errorf("registry returns no upload location")
+		}
+		return location, nil
+	default:
+		return "", fmt.Errorf("unexpected status %d when initiating blob upload", resp.StatusCode)
+	}
+}
+
+// uploadURL resolves the upload location, which could be relative, and adds the digest to it.
+func (registry *Registry) uploadURL(location string, digest digest.Digest) (string, error) {
+	base, err := url.Parse(registry.URL + "/")
+	if err != nil {
+		return "", err
+	}
+	u, err := base.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("digest", digest.String())
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// checkResponse returns the error of the response, the ErrorTransport is only used if the registry needs authentication.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	return &HttpStatusError{Response: resp, Body: body}
+}
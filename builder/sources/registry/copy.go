@@ -0,0 +1,75 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package registry
+
+import (
+	"fmt"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+)
+
+// CopyImage copies the image from the source repository to the destination repository.
+// A manifest list is copied with all the images of its platforms, so that the digest is kept.
+func CopyImage(src *Registry, srcRepo, srcRef string, dst *Registry, dstRepo, dstRef string) error {
+	manifest, _, err := src.ManifestRaw(srcRepo, srcRef)
+	if err != nil {
+		return fmt.Errorf("get manifest %s:%s: %v", srcRepo, srcRef, err)
+	}
+	if list, ok := manifest.(*manifestlist.DeserializedManifestList); ok {
+		for _, desc := range list.Manifests {
+			ref := desc.Digest.String()
+			if err := CopyImage(src, srcRepo, ref, dst, dstRepo, ref); err != nil {
+				return fmt.Errorf("copy image of platform %s/%s: %v", desc.Platform.OS, desc.Platform.Architecture, err)
+			}
+		}
+		return dst.PutManifestRaw(dstRepo, dstRef, manifest)
+	}
+	for _, desc := range manifest.References() {
+		if err := copyBlob(src, srcRepo, dst, dstRepo, desc); err != nil {
+			return fmt.Errorf("copy blob %s: %v", desc.Digest, err)
+		}
+	}
+	return dst.PutManifestRaw(dstRepo, dstRef, manifest)
+}
+
+func copyBlob(src *Registry, srcRepo string, dst *Registry, dstRepo string, desc distribution.Descriptor) error {
+	// foreign layers are not stored in the registry
+	if len(desc.URLs) > 0 {
+		return nil
+	}
+	exist, err := dst.HasBlob(dstRepo, desc.Digest)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	if src.URL == dst.URL {
+		mounted, err := dst.MountBlob(dstRepo, srcRepo, desc.Digest)
+		if err == nil && mounted {
+			return nil
+		}
+	}
+	content, err := src.DownloadBlob(srcRepo, desc.Digest)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	return dst.UploadBlob(dstRepo, desc.Digest, content, desc.Size)
+}
@@ -0,0 +1,189 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// SaveImage saves the image into dir in the OCI image layout, a manifest list is saved with the images of all platforms.
+func (registry *Registry) SaveImage(repository, reference, dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", string(digest.Canonical)), 0755); err != nil {
+		return err
+	}
+	desc, err := registry.saveManifest(repository, reference, dir)
+	if err != nil {
+		return err
+	}
+	index := v1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []v1.Descriptor{
+			{
+				MediaType:   desc.MediaType,
+				Digest:      desc.Digest,
+				Size:        desc.Size,
+				Annotations: map[string]string{v1.AnnotationRefName: reference},
+			},
+		},
+	}
+	if err := writeJSON(filepath.Join(dir, "index.json"), index); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, v1.ImageLayoutFile), v1.ImageLayout{Version: v1.ImageLayoutVersion})
+}
+
+func (registry *Registry) saveManifest(repository, reference, dir string) (distribution.Descriptor, error) {
+	manifest, desc, err := registry.ManifestRaw(repository, reference)
+	if err != nil {
+		return desc, fmt.Errorf("get manifest %s:%s: %v", repository, reference, err)
+	}
+	if list, ok := manifest.(*manifestlist.DeserializedManifestList); ok {
+		for _, m := range list.Manifests {
+			if _, err := registry.saveManifest(repository, m.Digest.String(), dir); err != nil {
+				return desc, err
+			}
+		}
+	} else {
+		for _, ref := range manifest.References() {
+			if len(ref.URLs) > 0 {
+				continue
+			}
+			if err := registry.saveBlob(repository, ref.Digest, dir); err != nil {
+				return desc, fmt.Errorf("save blob %s: %v", ref.Digest, err)
+			}
+		}
+	}
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return desc, err
+	}
+	return desc, ioutil.WriteFile(blobPath(dir, desc.Digest), payload, 0644)
+}
+
+func (registry *Registry) saveBlob(repository string, dgst digest.Digest, dir string) error {
+	target := blobPath(dir, dgst)
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	content, err := registry.DownloadBlob(repository, dgst)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	file, err := os.Create(target + ".tmp")
+	if err != nil {
+		return err
+	}
+	verifier := dgst.Verifier()
+	_, err = io.Copy(io.MultiWriter(file, verifier), content)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && !verifier.Verified() {
+		err = fmt.Errorf("digest mismatch")
+	}
+	if err != nil {
+		os.Remove(target + ".tmp")
+		return err
+	}
+	return os.Rename(target+".tmp", target)
+}
+
+// LoadImage pushes the image saved in dir by SaveImage into the repository with the given reference.
+func (registry *Registry) LoadImage(dir, repository, reference string) error {
+	var index v1.Index
+	body, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, &index); err != nil {
+		return fmt.Errorf("parse index.json: %v", err)
+	}
+	if len(index.Manifests) == 0 {
+		return fmt.Errorf("no image found in %s", dir)
+	}
+	desc := index.Manifests[0]
+	return registry.loadManifest(dir, repository, reference, desc.MediaType, desc.Digest)
+}
+
+func (registry *Registry) loadManifest(dir, repository, reference, mediaType string, dgst digest.Digest) error {
+	payload, err := ioutil.ReadFile(blobPath(dir, dgst))
+	if err != nil {
+		return err
+	}
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, payload)
+	if err != nil {
+		return fmt.Errorf("parse manifest %s: %v", dgst, err)
+	}
+	if list, ok := manifest.(*manifestlist.DeserializedManifestList); ok {
+		for _, m := range list.Manifests {
+			if err := registry.loadManifest(dir, repository, m.Digest.String(), m.MediaType, m.Digest); err != nil {
+				return err
+			}
+		}
+		return registry.PutManifestRaw(repository, reference, manifest)
+	}
+	for _, ref := range manifest.References() {
+		if len(ref.URLs) > 0 {
+			continue
+		}
+		if err := registry.loadBlob(dir, repository, ref); err != nil {
+			return fmt.Errorf("load blob %s: %v", ref.Digest, err)
+		}
+	}
+	return registry.PutManifestRaw(repository, reference, manifest)
+}
+
+func (registry *Registry) loadBlob(dir, repository string, desc distribution.Descriptor) error {
+	exist, err := registry.HasBlob(repository, desc.Digest)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	file, err := os.Open(blobPath(dir, desc.Digest))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return registry.UploadBlob(repository, desc.Digest, file, desc.Size)
+}
+
+func blobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, "blobs", dgst.Algorithm().String(), dgst.Hex())
+}
+
+func writeJSON(file string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, body, 0644)
+}
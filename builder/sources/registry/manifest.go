@@ -23,13 +23,27 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	_ "github.com/docker/distribution/manifest/ocischema" // register OCI image manifest
 	manifestV1 "github.com/docker/distribution/manifest/schema1"
 	manifestV2 "github.com/docker/distribution/manifest/schema2"
 	digest "github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/sirupsen/logrus"
 )
 
+// manifestMediaTypes the manifest types accepted when the manifest is read as it is stored
+var manifestMediaTypes = []string{
+	manifestlist.MediaTypeManifestList,
+	v1.MediaTypeImageIndex,
+	manifestV2.MediaTypeManifest,
+	v1.MediaTypeImageManifest,
+	manifestV1.MediaTypeSignedManifest,
+}
+
 // Manifest -
 func (registry *Registry) Manifest(repository, reference string) (*manifestV1.SignedManifest, error) {
 	url := registry.url("/v2/%s/manifests/%s", repository, reference)
@@ -161,3 +175,68 @@ func (registry *Registry) PutManifest(repository, reference string, signedManife
 	}
 	return err
 }
+
+// ManifestRaw returns the manifest as it is stored in the registry, it could be a manifest list or an OCI index,
+// a single image manifest of schema2 or OCI, or a signed schema1 manifest.
+func (registry *Registry) ManifestRaw(repository, reference string) (distribution.Manifest, distribution.Descriptor, error) {
+	url := registry.url("/v2/%s/manifests/%s", repository, reference)
+	registry.Logf("registry.manifest.get url=%s repository=%s reference=%s", url, repository, reference)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, distribution.Descriptor{}, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	resp, err := registry.Client.Do(req)
+	if err != nil {
+		return nil, distribution.Descriptor{}, fmt.Errorf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, distribution.Descriptor{}, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, distribution.Descriptor{}, err
+	}
+	mediaType := resp.Header.Get("Content-Type")
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = strings.TrimSpace(mediaType[:i])
+	}
+	return distribution.UnmarshalManifest(mediaType, body)
+}
+
+// ManifestList returns the manifest list or OCI index, ErrNotManifestList is returned if the reference is a single image.
+func (registry *Registry) ManifestList(repository, reference string) (*manifestlist.DeserializedManifestList, error) {
+	manifest, _, err := registry.ManifestRaw(repository, reference)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := manifest.(*manifestlist.DeserializedManifestList)
+	if !ok {
+		return nil, ErrNotManifestList
+	}
+	return list, nil
+}
+
+// PutManifestRaw pushes the manifest of any type, the payload is sent as it is so that the digest does not change.
+func (registry *Registry) PutManifestRaw(repository, reference string, manifest distribution.Manifest) error {
+	url := registry.url("/v2/%s/manifests/%s", repository, reference)
+	registry.Logf("registry.manifest.put url=%s repository=%s reference=%s", url, repository, reference)
+
+	mediaType, payload, err := manifest.Payload()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mediaType)
+	resp, err := registry.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
@@ -271,3 +271,28 @@ func (s *SFTPClient) MkdirAll(dirpath string) error {
 	}
 	return nil
 }
+
+//Stat returns the file info of the path
+func (s *SFTPClient) Stat(path string) (os.FileInfo, error) {
+	return s.sftpClient.Stat(path)
+}
+
+//ReadDir returns the file infos in the directory
+func (s *SFTPClient) ReadDir(dir string) ([]os.FileInfo, error) {
+	return s.sftpClient.ReadDir(dir)
+}
+
+//OpenFile opens the file with the flags of os.OpenFile
+func (s *SFTPClient) OpenFile(path string, flag int) (*sftp.File, error) {
+	return s.sftpClient.OpenFile(path, flag)
+}
+
+//Rename renames the file
+func (s *SFTPClient) Rename(oldpath, newpath string) error {
+	return s.sftpClient.Rename(oldpath, newpath)
+}
+
+//Remove removes the file or the empty directory
+func (s *SFTPClient) Remove(path string) error {
+	return s.sftpClient.Remove(path)
+}
@@ -0,0 +1,95 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package sources
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/distribution/reference"
+	"github.com/gridworkz/kato/builder/sbom"
+	"github.com/gridworkz/kato/builder/signature"
+	"github.com/gridworkz/kato/builder/sources/registry"
+	"github.com/gridworkz/kato/event"
+	digest "github.com/opencontainers/go-digest"
+)
+
+//SecureImage generates the SBOM of the image in the registry and attaches it to the image, then signs the image
+//and the SBOM if the signer is not nil. The digest of the image is returned, the SBOM is skipped with a warning
+//in the event log if it can not be generated.
+func SecureImage(imageName, user, password string, signer *signature.Signer, logger event.Logger) (digest.Digest, error) {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return "", err
+	}
+	dgst, err := reg.ManifestDigest(repo, tag)
+	if err != nil {
+		return "", fmt.Errorf("get digest of image %s: %v", imageName, err)
+	}
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", err
+	}
+	sbomDigest, err := attachSBOM(reg, repo, dgst, imageName)
+	if err != nil {
+		logger.Error(fmt.Sprintf("generate the SBOM of image %s failure: %v", imageName, err), map[string]string{"step": "sbom"})
+	} else {
+		logger.Info(fmt.Sprintf("the SBOM is attached to image %s", imageName), map[string]string{"step": "sbom"})
+	}
+	if signer == nil {
+		return dgst, nil
+	}
+	if err := signer.Sign(reg, repo, dgst, named.Name()); err != nil {
+		return dgst, fmt.Errorf("sign image %s: %v", imageName, err)
+	}
+	if sbomDigest != "" {
+		if err := signer.Sign(reg, repo, sbomDigest, named.Name()); err != nil {
+			return dgst, fmt.Errorf("sign the SBOM of image %s: %v", imageName, err)
+		}
+	}
+	logger.Info(fmt.Sprintf("image %s@%s is signed", imageName, dgst), map[string]string{"step": "image-sign"})
+	return dgst, nil
+}
+
+func attachSBOM(reg *registry.Registry, repo string, dgst digest.Digest, imageName string) (digest.Digest, error) {
+	doc, err := sbom.Generate(reg, repo, dgst, imageName)
+	if err != nil {
+		return "", err
+	}
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return signature.AttachSBOM(reg, repo, dgst, content, sbom.SPDXMediaType)
+}
+
+//VerifyImage verifies the image is signed by one of the keys, and returns the digest of the image with the key.
+func VerifyImage(imageName, user, password string, keys []*ecdsa.PublicKey) (digest.Digest, *ecdsa.PublicKey, error) {
+	reg, repo, tag, err := parseImage(imageName, user, password)
+	if err != nil {
+		return "", nil, err
+	}
+	dgst, err := reg.ManifestDigest(repo, tag)
+	if err != nil {
+		return "", nil, fmt.Errorf("get digest of image %s: %v", imageName, err)
+	}
+	key, err := signature.Verify(reg, repo, dgst, keys)
+	return dgst, key, err
+}
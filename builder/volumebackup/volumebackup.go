@@ -0,0 +1,792 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package volumebackup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gridworkz/kato/builder/cloudos"
+	"golang.org/x/crypto/scrypt"
+)
+
+// DefaultChunkSize is the size of the chunks the files are split into.
+const DefaultChunkSize = 1 << 20
+
+// LocalRepository is the directory of the repository storing the full-offline volume backups.
+const LocalRepository = "/grdata/groupbackup/repository"
+
+// ErrSnapshotNotFound is returned if the snapshot does not exist in the repository.
+var ErrSnapshotNotFound = errors.New("volume snapshot not found")
+
+// the kinds of the locks of a volume, a backup and the removal of the unreferenced chunks
+// exclude each other, they may run in the builder and the api at the same time.
+const (
+	lockBackup = "backup"
+	lockForget = "forget"
+)
+
+var (
+	// lockTTL is the age after which a lock is considered left by an interrupted operation.
+	lockTTL = 24 * time.Hour
+	// lockPollInterval is the interval a backup checks whether the chunks are still being removed.
+	lockPollInterval = 2 * time.Second
+	// lockWaitTimeout is the maximum time a backup waits for the removal of the chunks.
+	lockWaitTimeout = 30 * time.Minute
+)
+
+// Repository stores the volume data as content-addressed chunks in the object storage, a snapshot of
+// the volume only uploads the chunks which are not stored yet, so the backups of a volume are incremental.
+//
+// The objects of a volume are:
+//
+//	volumes/<volume>/salt
+//	volumes/<volume>/chunks/<key id>/<sha256 of the chunk>
+//	volumes/<volume>/snapshots/<snapshot id>.json
+//	volumes/<volume>/locks/<backup|forget>-<snapshot id>
+//
+// The chunks are sealed by AES-256-GCM if the passphrase is not empty, the key is derived from the
+// passphrase and the salt of the volume. The chunks sealed by different keys are stored apart, the key
+// id is "plain" for the chunks which are not encrypted. The snapshots are not encrypted, so that the
+// unreferenced chunks can be removed without the passphrase, but they are authenticated by the HMAC
+// derived from the same key.
+type Repository struct {
+	store      cloudos.CloudOSer
+	passphrase string
+	chunkSize  int
+	keys       map[string]*volumeKey
+}
+
+type volumeKey struct {
+	id   string
+	aead cipher.AEAD
+	// the key authenticating the snapshots, nil if the snapshots are not encrypted
+	mac []byte
+}
+
+// New creates a repository on the object storage.
+func New(store cloudos.CloudOSer, passphrase string) *Repository {
+	return &Repository{
+		store:      store,
+		passphrase: passphrase,
+		chunkSize:  DefaultChunkSize,
+		keys:       make(map[string]*volumeKey),
+	}
+}
+
+// NewLocal creates a repository in the directory of the local file system.
+func NewLocal(dir, passphrase string) (*Repository, error) {
+	store, err := cloudos.New(&cloudos.Config{ProviderType: cloudos.S3ProviderLocal, BucketName: dir})
+	if err != nil {
+		return nil, err
+	}
+	return New(store, passphrase), nil
+}
+
+// VolumeKey returns the key of the volume of the component in the repository.
+func VolumeKey(serviceID, volumeName string) string {
+	return path.Join(serviceID, strings.Replace(volumeName, "/", "", -1))
+}
+
+func chunkKey(volume, keyID, hash string) string {
+	return path.Join("volumes", volume, "chunks", keyID, hash)
+}
+
+func snapshotKey(volume, id string) string {
+	return path.Join("volumes", volume, "snapshots", id+".json")
+}
+
+func lockKey(volume, kind, id string) string {
+	return path.Join("volumes", volume, "locks", kind+"-"+id)
+}
+
+// Snapshot is a backup point of the volume.
+type Snapshot struct {
+	ID     string `json:"id"`
+	Volume string `json:"volume"`
+	// the id of the key sealing the chunks
+	KeyID string `json:"key_id"`
+	// the snapshot whose unchanged files are not read again
+	Parent string `json:"parent,omitempty"`
+	// the base name of the volume directory
+	Root  string    `json:"root"`
+	Time  time.Time `json:"time"`
+	Files []*File   `json:"files"`
+	// the total size of the files
+	Size int64 `json:"size"`
+	// the size of the chunks uploaded by the snapshot
+	AddedSize   int64 `json:"added_size"`
+	ChunkCount  int   `json:"chunk_count"`
+	AddedChunks int   `json:"added_chunks"`
+	// the hex encoded HMAC-SHA256 of the snapshot without it, empty if the chunks are not encrypted
+	MAC string `json:"mac,omitempty"`
+}
+
+// File is a file, directory or symbolic link in the volume.
+type File struct {
+	// slash-separated path relative to the volume directory, empty for the directory itself
+	Path    string      `json:"path"`
+	Mode    os.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+	Size    int64       `json:"size,omitempty"`
+	UID     int         `json:"uid"`
+	GID     int         `json:"gid"`
+	// the target of the symbolic link
+	Link   string   `json:"link,omitempty"`
+	Chunks []string `json:"chunks,omitempty"`
+}
+
+func (f *File) unchanged(other *File) bool {
+	return other != nil && other.Mode == f.Mode && other.Size == f.Size && other.ModTime.Equal(f.ModTime)
+}
+
+// Backup creates the snapshot of the directory. The files of the parent snapshot whose size
+// and modification time are not changed are not read again. The parent is ignored if it does not exist.
+func (r *Repository) Backup(volume, id, dir, parent string) (*Snapshot, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	key, err := r.key(volume)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := r.lock(volume, lockBackup, id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	// the chunks listed below must not be removed before the snapshot referencing them is saved
+	if err := r.waitUnlocked(volume, lockForget); err != nil {
+		return nil, err
+	}
+
+	parentFiles := make(map[string]*File)
+	if parent != "" {
+		p, err := r.Snapshot(volume, parent)
+		if err != nil && err != ErrSnapshotNotFound {
+			return nil, fmt.Errorf("load parent snapshot %s: %v", parent, err)
+		}
+		// the chunks of the parent sealed by the other key can not be reused
+		if p != nil && p.KeyID == key.id {
+			if err := p.verify(key); err != nil {
+				return nil, fmt.Errorf("load parent snapshot %s: %v", parent, err)
+			}
+			for _, f := range p.Files {
+				parentFiles[f.Path] = f
+			}
+		} else {
+			parent = ""
+		}
+	}
+	known, err := r.listChunks(volume, key.id)
+	if err != nil {
+		return nil, fmt.Errorf("list chunks: %v", err)
+	}
+
+	snapshot := &Snapshot{
+		ID:     id,
+		Volume: volume,
+		KeyID:  key.id,
+		Parent: parent,
+		Root:   filepath.Base(dir),
+		Time:   time.Now(),
+	}
+	err = filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		f := &File{
+			Path:    filepath.ToSlash(rel),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			f.UID, f.GID = int(stat.Uid), int(stat.Gid)
+		}
+		switch {
+		case info.IsDir():
+		case info.Mode()&os.ModeSymlink != 0:
+			if f.Link, err = os.Readlink(name); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			f.Size = info.Size()
+			if old := parentFiles[f.Path]; f.unchanged(old) && containsAll(known, old.Chunks) {
+				f.Chunks = old.Chunks
+				break
+			}
+			if f.Chunks, err = r.storeFile(snapshot, key, known, name); err != nil {
+				return fmt.Errorf("store %s: %v", name, err)
+			}
+		default:
+			// sockets, pipes and devices are not backed up
+			return nil
+		}
+		snapshot.Size += f.Size
+		snapshot.ChunkCount += len(f.Chunks)
+		snapshot.Files = append(snapshot.Files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := snapshot.sign(key); err != nil {
+		return nil, err
+	}
+	if err := r.putJSON(snapshotKey(volume, id), snapshot); err != nil {
+		return nil, fmt.Errorf("save snapshot: %v", err)
+	}
+	return snapshot, nil
+}
+
+// sign sets the MAC of the snapshot if the key authenticates the snapshots.
+func (s *Snapshot) sign(key *volumeKey) error {
+	if key.mac == nil {
+		return nil
+	}
+	mac, err := s.mac(key)
+	if err != nil {
+		return err
+	}
+	s.MAC = hex.EncodeToString(mac)
+	return nil
+}
+
+// verify checks the MAC of the snapshot if the key authenticates the snapshots.
+func (s *Snapshot) verify(key *volumeKey) error {
+	if key.mac == nil {
+		return nil
+	}
+	want, err := s.mac(key)
+	if err != nil {
+		return err
+	}
+	got, err := hex.DecodeString(s.MAC)
+	if err != nil || !hmac.Equal(want, got) {
+		return fmt.Errorf("snapshot %s is not authenticated by the key, it is tampered or the passphrase is wrong", s.ID)
+	}
+	return nil
+}
+
+func (s *Snapshot) mac(key *volumeKey) ([]byte, error) {
+	sum := s.MAC
+	s.MAC = ""
+	data, err := json.Marshal(s)
+	s.MAC = sum
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key.mac)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// lock writes the lock of the operation on the volume, it returns the func releasing the lock.
+func (r *Repository) lock(volume, kind, id string) (func(), error) {
+	key := lockKey(volume, kind, id)
+	err := r.put(key, func(w io.Writer) error {
+		_, err := io.WriteString(w, time.Now().Format(time.RFC3339))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lock volume %s: %v", volume, err)
+	}
+	return func() {
+		_ = r.store.DeleteObject(key)
+	}, nil
+}
+
+// locked returns whether the volume is locked by an operation of the kind.
+func (r *Repository) locked(volume, kind string) (bool, error) {
+	objects, err := r.store.ListObjects(lockKey(volume, kind, ""))
+	if err != nil {
+		return false, fmt.Errorf("list locks: %v", err)
+	}
+	for _, object := range objects {
+		if time.Since(object.LastModified) < lockTTL {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// waitUnlocked waits until the volume is not locked by an operation of the kind.
+func (r *Repository) waitUnlocked(volume, kind string) error {
+	deadline := time.Now().Add(lockWaitTimeout)
+	for {
+		locked, err := r.locked(volume, kind)
+		if err != nil || !locked {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("volume %s is locked by %s for more than %s", volume, kind, lockWaitTimeout)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+func containsAll(set map[string]struct{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := set[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// storeFile splits the file into chunks, and uploads the chunks which are not known.
+func (r *Repository) storeFile(snapshot *Snapshot, key *volumeKey, known map[string]struct{}, name string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var chunks []string
+	buf := make([]byte, r.chunkSize)
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			sum := sha256.Sum256(buf[:n])
+			hash := hex.EncodeToString(sum[:])
+			if _, ok := known[hash]; !ok {
+				if err := r.putChunk(snapshot.Volume, key, hash, buf[:n]); err != nil {
+					return nil, err
+				}
+				known[hash] = struct{}{}
+				snapshot.AddedSize += int64(n)
+				snapshot.AddedChunks++
+			}
+			chunks = append(chunks, hash)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// key returns the key sealing the chunks of the volume, it is derived once for a volume.
+func (r *Repository) key(volume string) (*volumeKey, error) {
+	if key, ok := r.keys[volume]; ok {
+		return key, nil
+	}
+	key := &volumeKey{id: "plain"}
+	if r.passphrase != "" {
+		salt, err := r.salt(volume)
+		if err != nil {
+			return nil, fmt.Errorf("get salt: %v", err)
+		}
+		dk, err := scrypt.Key([]byte(r.passphrase), salt, 1<<15, 8, 1, 32)
+		if err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(dk)
+		if err != nil {
+			return nil, err
+		}
+		if key.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+		h := hmac.New(sha256.New, dk)
+		h.Write([]byte("snapshot"))
+		key.mac = h.Sum(nil)
+		sum := sha256.Sum256(dk)
+		key.id = hex.EncodeToString(sum[:8])
+	}
+	r.keys[volume] = key
+	return key, nil
+}
+
+// salt returns the salt of the volume, it is created by the first encrypted snapshot.
+func (r *Repository) salt(volume string) ([]byte, error) {
+	key := path.Join("volumes", volume, "salt")
+	var salt []byte
+	_, err := r.store.StatObject(key)
+	if err == cloudos.ErrObjectNotFound {
+		salt = make([]byte, 16)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		return salt, r.put(key, func(w io.Writer) error {
+			_, err := w.Write(salt)
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = r.get(key, func(rd io.Reader) error {
+		salt, err = ioutil.ReadAll(rd)
+		return err
+	})
+	return salt, err
+}
+
+// putChunk uploads the chunk, the sealed chunk is the random nonce followed by the ciphertext,
+// and the hash of the chunk is the additional data.
+func (r *Repository) putChunk(volume string, key *volumeKey, hash string, data []byte) error {
+	return r.put(chunkKey(volume, key.id, hash), func(w io.Writer) error {
+		if key.aead != nil {
+			nonce := make([]byte, key.aead.NonceSize())
+			if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+				return err
+			}
+			data = key.aead.Seal(nonce, nonce, data, []byte(hash))
+		}
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func (r *Repository) getChunk(volume string, key *volumeKey, hash string) ([]byte, error) {
+	var data []byte
+	err := r.get(chunkKey(volume, key.id, hash), func(rd io.Reader) error {
+		var err error
+		data, err = ioutil.ReadAll(rd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if key.aead != nil {
+		size := key.aead.NonceSize()
+		if len(data) < size {
+			return nil, fmt.Errorf("chunk %s is corrupted", hash)
+		}
+		if data, err = key.aead.Open(nil, data[:size], data[size:], []byte(hash)); err != nil {
+			return nil, fmt.Errorf("chunk %s is corrupted: %v", hash, err)
+		}
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("chunk %s is corrupted", hash)
+	}
+	return data, nil
+}
+
+func (r *Repository) putJSON(key string, v interface{}) error {
+	return r.put(key, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(v)
+	})
+}
+
+// put writes the object to a temporary file, and uploads it.
+func (r *Repository) put(key string, write func(w io.Writer) error) error {
+	tmp, err := ioutil.TempFile("", "volumebackup")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return r.store.PutObject(key, tmp.Name())
+}
+
+// get downloads the object to a temporary file, and reads it.
+func (r *Repository) get(key string, read func(r io.Reader) error) error {
+	tmp, err := ioutil.TempFile("", "volumebackup")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := r.store.GetObject(key, tmp.Name()); err != nil {
+		return err
+	}
+	file, err := os.Open(tmp.Name())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return read(file)
+}
+
+// listChunks returns the hashes of the chunks sealed by the key, or the chunks sealed by all the keys
+// with the key id prefixed if the key id is empty.
+func (r *Repository) listChunks(volume, keyID string) (map[string]struct{}, error) {
+	prefix := chunkKey(volume, keyID, "") + "/"
+	objects, err := r.store.ListObjects(prefix)
+	if err != nil {
+		return nil, err
+	}
+	chunks := make(map[string]struct{}, len(objects))
+	for _, object := range objects {
+		chunks[strings.TrimPrefix(object.Key, prefix)] = struct{}{}
+	}
+	return chunks, nil
+}
+
+// Snapshot returns the snapshot of the volume, ErrSnapshotNotFound if it does not exist.
+func (r *Repository) Snapshot(volume, id string) (*Snapshot, error) {
+	key := snapshotKey(volume, id)
+	if _, err := r.store.StatObject(key); err != nil {
+		if err == cloudos.ErrObjectNotFound {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	var snapshot Snapshot
+	err := r.get(key, func(rd io.Reader) error {
+		return json.NewDecoder(rd).Decode(&snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// loadSnapshot returns the snapshot of the volume authenticated by the key.
+func (r *Repository) loadSnapshot(volume, id string, key *volumeKey) (*Snapshot, error) {
+	snapshot, err := r.Snapshot(volume, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.KeyID != key.id {
+		return nil, fmt.Errorf("snapshot %s is sealed by the other key, the passphrase is wrong", id)
+	}
+	if err := snapshot.verify(key); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Snapshots returns the ids of the snapshots of the volume.
+func (r *Repository) Snapshots(volume string) ([]string, error) {
+	objects, err := r.store.ListObjects(path.Join("volumes", volume, "snapshots") + "/")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, object := range objects {
+		ids = append(ids, strings.TrimSuffix(path.Base(object.Key), ".json"))
+	}
+	return ids, nil
+}
+
+// Restore restores the snapshot of the volume into the directory dst/<the base name of the volume directory>,
+// it returns the restored directory. The files are never written outside the restored directory, the symbolic
+// links are created after the other files, and a path resolved outside the directory is rejected.
+func (r *Repository) Restore(volume, id, dst string) (string, error) {
+	key, err := r.key(volume)
+	if err != nil {
+		return "", err
+	}
+	snapshot, err := r.loadSnapshot(volume, id, key)
+	if err != nil {
+		return "", err
+	}
+	root := filepath.Join(dst, snapshot.Root)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", err
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	var dirs, links []*File
+	for _, f := range snapshot.Files {
+		name, err := restorePath(root, realRoot, f.Path)
+		if err != nil {
+			return "", fmt.Errorf("invalid path %s in snapshot %s: %v", f.Path, id, err)
+		}
+		switch {
+		case f.Mode.IsDir():
+			if err := os.MkdirAll(name, 0755); err != nil {
+				return "", err
+			}
+			// the mode and the modification time of the directory are restored after the files in it
+			dirs = append(dirs, f)
+		case f.Mode&os.ModeSymlink != 0:
+			links = append(links, f)
+			continue
+		default:
+			if err := r.restoreFile(volume, key, f, name); err != nil {
+				return "", fmt.Errorf("restore %s: %v", f.Path, err)
+			}
+		}
+		// the owner can not be changed without the privilege, it is best effort.
+		_ = os.Lchown(name, f.UID, f.GID)
+	}
+	for _, f := range links {
+		// the links created before may redirect the parent of the link
+		name, err := restorePath(root, realRoot, f.Path)
+		if err != nil {
+			return "", fmt.Errorf("invalid path %s in snapshot %s: %v", f.Path, id, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return "", err
+		}
+		if err := os.Symlink(f.Link, name); err != nil {
+			return "", err
+		}
+		_ = os.Lchown(name, f.UID, f.GID)
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		name := filepath.Join(root, filepath.FromSlash(dirs[i].Path))
+		if err := os.Chmod(name, dirs[i].Mode.Perm()); err != nil {
+			return "", err
+		}
+		if err := os.Chtimes(name, dirs[i].ModTime, dirs[i].ModTime); err != nil {
+			return "", err
+		}
+	}
+	return root, nil
+}
+
+// restorePath returns the path of the file in the restored directory, the nearest existing
+// parent of the file must be resolved inside the restored directory.
+func restorePath(root, realRoot, rel string) (string, error) {
+	name := filepath.Join(root, filepath.FromSlash(rel))
+	if name == root {
+		return name, nil
+	}
+	if !strings.HasPrefix(name, root+string(filepath.Separator)) {
+		return "", errors.New("the path is outside the volume")
+	}
+	parent := filepath.Dir(name)
+	for {
+		if _, err := os.Lstat(parent); err == nil || parent == filepath.Dir(parent) {
+			break
+		}
+		parent = filepath.Dir(parent)
+	}
+	real, err := filepath.EvalSymlinks(parent)
+	if err != nil {
+		return "", err
+	}
+	if real != realRoot && !strings.HasPrefix(real, realRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("the parent is resolved to %s outside the volume", real)
+	}
+	return name, nil
+}
+
+func (r *Repository) restoreFile(volume string, key *volumeKey, f *File, name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	// the existing file or link is replaced rather than written through
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, f.Mode.Perm())
+	if err != nil {
+		return err
+	}
+	for _, hash := range f.Chunks {
+		data, err := r.getChunk(volume, key, hash)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(name, f.Mode.Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(name, f.ModTime, f.ModTime)
+}
+
+// Forget deletes the snapshot of the volume, and removes the chunks which are not referenced by the
+// other snapshots. The chunks are kept if a backup of the volume is running, they may be reused by it,
+// and are removed by the next Forget.
+func (r *Repository) Forget(volume, id string) error {
+	if err := r.store.DeleteObject(snapshotKey(volume, id)); err != nil && err != cloudos.ErrObjectNotFound {
+		return fmt.Errorf("delete snapshot: %v", err)
+	}
+	unlock, err := r.lock(volume, lockForget, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// the backup waits for the lock above before listing the chunks, so the running one is seen here
+	if running, err := r.locked(volume, lockBackup); err != nil || running {
+		return err
+	}
+	ids, err := r.Snapshots(volume)
+	if err != nil {
+		return fmt.Errorf("list snapshots: %v", err)
+	}
+	referenced := make(map[string]struct{})
+	for _, id := range ids {
+		snapshot, err := r.Snapshot(volume, id)
+		if err != nil {
+			return fmt.Errorf("load snapshot %s: %v", id, err)
+		}
+		for _, f := range snapshot.Files {
+			for _, hash := range f.Chunks {
+				referenced[path.Join(snapshot.KeyID, hash)] = struct{}{}
+			}
+		}
+	}
+	chunks, err := r.listChunks(volume, "")
+	if err != nil {
+		return fmt.Errorf("list chunks: %v", err)
+	}
+	for chunk := range chunks {
+		if _, ok := referenced[chunk]; ok {
+			continue
+		}
+		if err := r.store.DeleteObject(path.Join("volumes", volume, "chunks", chunk)); err != nil && err != cloudos.ErrObjectNotFound {
+			return fmt.Errorf("delete chunk %s: %v", chunk, err)
+		}
+	}
+	return nil
+}
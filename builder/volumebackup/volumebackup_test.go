@@ -0,0 +1,331 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package volumebackup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readTree reads the files and the symbolic links in the directory.
+func readTree(t *testing.T, dir string) map[string]string {
+	tree := make(map[string]string)
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, name)
+		switch {
+		case info.IsDir():
+			tree[rel+"/"] = info.Mode().Perm().String()
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(name)
+			if err != nil {
+				return err
+			}
+			tree[rel] = "-> " + link
+		default:
+			content, err := ioutil.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			tree[rel] = info.Mode().Perm().String() + " " + string(content)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func writeFile(t *testing.T, name, content string, mode os.FileMode) {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	for _, passphrase := range []string{"", "secret"} {
+		t.Run("passphrase="+passphrase, func(t *testing.T) {
+			testBackupAndRestore(t, passphrase)
+		})
+	}
+}
+
+func testBackupAndRestore(t *testing.T, passphrase string) {
+	dir, err := ioutil.TempDir("", "volumebackup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "data")
+	big := strings.Repeat("0123456789abcdef", 4) + "tail"
+	writeFile(t, filepath.Join(src, "a.txt"), "hello", 0644)
+	writeFile(t, filepath.Join(src, "big.bin"), big, 0600)
+	writeFile(t, filepath.Join(src, "sub", "dir", "c.txt"), "nested", 0644)
+	// the same content as a.txt, the chunk is shared
+	writeFile(t, filepath.Join(src, "copy.txt"), "hello", 0644)
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	want1 := readTree(t, src)
+
+	repo, err := NewLocal(filepath.Join(dir, "repo"), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.chunkSize = 16
+	volume := VolumeKey("service", "/data")
+
+	s1, err := repo.Backup(volume, "b1", src, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// big.bin: 4 identical chunks and the tail, a.txt and copy.txt share a chunk, c.txt
+	if s1.ChunkCount != 8 || s1.AddedChunks != 4 {
+		t.Errorf("want 8 chunks and 4 added chunks, got %d and %d", s1.ChunkCount, s1.AddedChunks)
+	}
+
+	// change the last chunk of big.bin, add a file and remove one
+	writeFile(t, filepath.Join(src, "big.bin"), strings.Repeat("0123456789abcdef", 4)+"TAIL", 0600)
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(src, "big.bin"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(src, "sub", "new.txt"), "new file", 0644)
+	if err := os.Remove(filepath.Join(src, "copy.txt")); err != nil {
+		t.Fatal(err)
+	}
+	want2 := readTree(t, src)
+
+	s2, err := repo.Backup(volume, "b2", src, "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2.Parent != "b1" || s2.AddedChunks != 2 || s2.AddedSize != int64(len("TAIL")+len("new file")) {
+		t.Errorf("want 2 added chunks of the parent b1, got %d chunks(%d bytes) of the parent %s", s2.AddedChunks, s2.AddedSize, s2.Parent)
+	}
+
+	for id, want := range map[string]map[string]string{"b1": want1, "b2": want2} {
+		root, err := repo.Restore(volume, id, filepath.Join(dir, "restore-"+id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != filepath.Join(dir, "restore-"+id, "data") {
+			t.Errorf("unexpected restored directory %s", root)
+		}
+		if got := readTree(t, root); !reflect.DeepEqual(want, got) {
+			t.Errorf("snapshot %s: want %v, got %v", id, want, got)
+		}
+	}
+
+	if passphrase != "" {
+		chunks, err := filepath.Glob(filepath.Join(dir, "repo", "volumes", volume, "chunks", "*", "*"))
+		if err != nil || len(chunks) == 0 {
+			t.Fatalf("no chunk is found: %v", err)
+		}
+		for _, chunk := range chunks {
+			content, _ := ioutil.ReadFile(chunk)
+			if bytes.Contains(content, []byte("hello")) || bytes.Contains(content, []byte("0123456789abcdef")) {
+				t.Errorf("chunk %s is not encrypted", chunk)
+			}
+		}
+	}
+
+	if err := repo.Forget(volume, "b1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Snapshot(volume, "b1"); err != ErrSnapshotNotFound {
+		t.Errorf("want ErrSnapshotNotFound, got %v", err)
+	}
+	chunks, err := repo.listChunks(volume, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the chunk of the old tail is removed
+	if len(chunks) != 5 {
+		t.Errorf("want 5 chunks after forgetting b1, got %d", len(chunks))
+	}
+	if ids, err := repo.Snapshots(volume); err != nil || !reflect.DeepEqual(ids, []string{"b2"}) {
+		t.Errorf("want snapshots [b2], got %v(%v)", ids, err)
+	}
+	root, err := repo.Restore(volume, "b2", filepath.Join(dir, "restore-again"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readTree(t, root); !reflect.DeepEqual(want2, got) {
+		t.Errorf("want %v, got %v", want2, got)
+	}
+
+	// the snapshot can not be restored with the other passphrase
+	other, err := NewLocal(filepath.Join(dir, "repo"), passphrase+"other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Restore(volume, "b2", filepath.Join(dir, "restore-other")); err == nil {
+		t.Error("want an error restoring with the other passphrase")
+	}
+
+	// the parent is ignored if it does not exist
+	if s3, err := repo.Backup(volume, "b3", src, "b1"); err != nil || s3.Parent != "" || s3.AddedChunks != 0 {
+		t.Errorf("unexpected snapshot %+v(%v)", s3, err)
+	}
+}
+
+func TestForgetDuringBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumebackup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "data")
+	writeFile(t, filepath.Join(src, "a.txt"), "hello", 0644)
+	repo, err := NewLocal(filepath.Join(dir, "repo"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	volume := VolumeKey("service", "/data")
+	if _, err := repo.Backup(volume, "b1", src, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// the chunks may be reused by the running backup
+	unlock, err := repo.lock(volume, lockBackup, "b2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Forget(volume, "b1"); err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _ := repo.listChunks(volume, ""); len(chunks) != 1 {
+		t.Errorf("want the chunk kept during the backup, got %d chunks", len(chunks))
+	}
+	unlock()
+	if err := repo.Forget(volume, "b1"); err != nil {
+		t.Fatal(err)
+	}
+	if chunks, _ := repo.listChunks(volume, ""); len(chunks) != 0 {
+		t.Errorf("want the unreferenced chunk removed, got %d chunks", len(chunks))
+	}
+
+	// the backup waits for the removal of the chunks
+	unlock, err = repo.lock(volume, lockForget, "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	lockPollInterval, lockWaitTimeout = time.Millisecond, 10*time.Millisecond
+	defer func() {
+		lockPollInterval, lockWaitTimeout = 2*time.Second, 30*time.Minute
+	}()
+	if _, err := repo.Backup(volume, "b3", src, ""); err == nil {
+		t.Error("want an error backing up the volume locked by forget")
+	}
+}
+
+func TestRestoreTamperedSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumebackup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "data")
+	outside := filepath.Join(dir, "outside")
+	writeFile(t, filepath.Join(src, "a.txt"), "hello", 0644)
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(src, "out")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, passphrase := range []string{"", "secret"} {
+		repo, err := NewLocal(filepath.Join(dir, "repo"+passphrase), passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		volume := VolumeKey("service", "/data")
+		snapshot, err := repo.Backup(volume, "b1", src, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a file written through the link
+		var chunks []string
+		for _, f := range snapshot.Files {
+			if f.Path == "a.txt" {
+				chunks = f.Chunks
+			}
+		}
+		snapshot.Files = append(snapshot.Files, &File{Path: "out/evil.txt", Mode: 0644, Chunks: chunks})
+		if err := repo.putJSON(snapshotKey(volume, "b1"), snapshot); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repo.Restore(volume, "b1", filepath.Join(dir, "restore"+passphrase)); err == nil {
+			t.Errorf("passphrase=%s: want an error restoring the tampered snapshot", passphrase)
+		}
+		if _, err := os.Stat(filepath.Join(outside, "evil.txt")); !os.IsNotExist(err) {
+			t.Fatalf("passphrase=%s: the file is written outside the volume", passphrase)
+		}
+	}
+}
+
+func TestRestorePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "volumebackup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	for rel, valid := range map[string]bool{
+		"":                true,
+		"a.txt":           true,
+		"sub/a.txt":       true,
+		"new/dir/a.txt":   true,
+		"link":            true,
+		"../a.txt":        false,
+		"link/a.txt":      false,
+		"link/new/a.txt":  false,
+		"sub/../../a.txt": false,
+	} {
+		if _, err := restorePath(root, root, rel); (err == nil) != valid {
+			t.Errorf("%s: want valid %v, got %v", rel, valid, err)
+		}
+	}
+}
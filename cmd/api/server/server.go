@@ -28,6 +28,7 @@ import (
 	"github.com/gridworkz/kato/api/db"
 	"github.com/gridworkz/kato/api/discover"
 	"github.com/gridworkz/kato/api/handler"
+	"github.com/gridworkz/kato/api/handler/group"
 	"github.com/gridworkz/kato/api/server"
 	"github.com/gridworkz/kato/cmd/api/option"
 	"github.com/gridworkz/kato/event"
@@ -104,6 +105,8 @@ func Run(s *option.APIServer) error {
 		logrus.Errorf("init all handle error, %v", err)
 		return err
 	}
+	//Execute the app backup schedules
+	go group.NewBackupScheduler(handler.GetAPPBackupHandler()).Run(ctx)
 	//Create v2Router manager
 	if err := controller.CreateV2RouterManager(s.Config, cli); err != nil {
 		logrus.Errorf("create v2 route manager error, %v", err)
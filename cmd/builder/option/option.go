@@ -55,6 +55,11 @@ type Config struct {
 	CachePVCName         string
 	CacheMode            string
 	CachePath            string
+	DockerfileBuilder    string
+	BuildPlatforms       []string
+	CacheComponentQuota  int
+	CacheTenantQuota     int
+	ImageSigningSecret   string
 }
 
 //Builder server
@@ -98,6 +103,11 @@ func (a *Builder) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&a.CachePVCName, "pvc-cache-name", "cache", "pvc name of cache")
 	fs.StringVar(&a.CacheMode, "cache-mode", "sharefile", "volume cache mount type, can be hostpath and sharefile, default is sharefile, which mount using pvc")
 	fs.StringVar(&a.CachePath, "cache-path", "/cache", "volume cache mount path, when cache-mode using hostpath, default path is /cache")
+	fs.StringVar(&a.DockerfileBuilder, "dockerfile-builder", "docker", "how to build dockerfile, can be docker and job, job builds dockerfile in a kubernetes job with kaniko, which does not need docker daemon")
+	fs.StringSliceVar(&a.BuildPlatforms, "build-platforms", nil, "the platforms that images are built for, such as linux/amd64,linux/arm64, a manifest list is pushed if there are several platforms, default is the platform of the builder")
+	fs.IntVar(&a.CacheComponentQuota, "cache-component-quota", 0, "the max size(MB) of the build caches of a component in all languages, the least recently used languages are evicted if exceeded, and the build is not cached if its cache exceeds it alone, default is 0, which means unlimited")
+	fs.IntVar(&a.CacheTenantQuota, "cache-tenant-quota", 0, "the max size(MB) of the build caches of a tenant, the least recently used caches are evicted if exceeded, default is 0, which means unlimited")
+	fs.StringVar(&a.ImageSigningSecret, "image-signing-secret", "kato-image-signing", "the secret in rbd-namespace holding the cosign key which signs the images built, images are not signed if the secret does not exist")
 }
 
 //SetLog
@@ -115,6 +125,9 @@ func (a *Builder) CheckConfig() error {
 	if a.Topic != client.BuilderTopic && a.Topic != client.WindowsBuilderTopic {
 		return fmt.Errorf("Topic is only suppory `%s` and `%s`", client.BuilderTopic, client.WindowsBuilderTopic)
 	}
+	if a.DockerfileBuilder != "docker" && a.DockerfileBuilder != "job" {
+		return fmt.Errorf("dockerfile builder is only support `docker` and `job`")
+	}
 	if runtime.GOOS == "windows" {
 		a.Topic = "windows_builder"
 	}
@@ -23,6 +23,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/gridworkz/kato/builder/cache"
 	"github.com/gridworkz/kato/builder/discover"
 	"github.com/gridworkz/kato/builder/exector"
 	"github.com/gridworkz/kato/builder/monitor"
@@ -74,6 +75,7 @@ func Run(s *option.Builder) error {
 		logrus.Errorf("new Mq client error, %v", err)
 		return err
 	}
+	cache.CreateManager(cache.DefaultRoot, int64(s.Config.CacheComponentQuota)<<20, int64(s.Config.CacheTenantQuota)<<20)
 	exec, err := exector.NewManager(s.Config, client)
 	if err != nil {
 		return err
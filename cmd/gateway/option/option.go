@@ -77,6 +77,10 @@ type Config struct {
 	ShareMemory       uint64
 	SyncRateLimit     float32
 	EnableSSLStapling bool
+	// IngressClass is the ingress class handled by the gateway, all classes are handled if empty
+	IngressClass string
+	// GatewayName is the gateway api Gateway(namespace/name or name) whose routes are handled by the gateway
+	GatewayName string
 }
 
 // ListenPorts describe the ports required to run the gateway controller
@@ -125,6 +129,8 @@ func (g *GWServer) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&g.EnableSSLStapling, "enable-ssl-stapling", false, "enable ssl stapling")
 	fs.Uint64Var(&g.ShareMemory, "max-config-share-memory", 128, "Nginx maximum Shared memory size, which should be increased for larger clusters.")
 	fs.Float32Var(&g.SyncRateLimit, "sync-rate-limit", 0.3, "Define the sync frequency upper limit")
+	fs.StringVar(&g.IngressClass, "ingress-class", "", "the ingress class handled by the gateway, the ingresses without ingress class are always handled. All ingresses are handled if empty")
+	fs.StringVar(&g.GatewayName, "gateway-name", "", "the name of the gateway api Gateway, in the form of namespace/name or name, whose HTTPRoute and TCPRoute are handled by the gateway, the backends of the routes must be the services created by kato. The gateway api is disabled if empty")
 	fs.StringArrayVar(&g.IgnoreInterface, "ignore-interface", []string{"docker0", "tunl0", "cni0", "kube-ipvs0", "flannel"}, "The network interface name that ignore by gateway")
 }
 
@@ -33,6 +33,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/gridworkz/kato/cmd/gateway/option"
@@ -61,6 +62,13 @@ func Run(s *option.GWServer) error {
 	if err != nil {
 		return err
 	}
+	var dynamicClient dynamic.Interface
+	if s.Config.GatewayName != "" {
+		dynamicClient, err = dynamic.NewForConfig(config)
+		if err != nil {
+			return err
+		}
+	}
 
 	etcdClientArgs := &etcdutil.ClientArgs{
 		Endpoints:   s.Config.EtcdEndpoint,
@@ -97,7 +105,7 @@ func Run(s *option.GWServer) error {
 	}
 	mc.Start()
 
-	gwc, err := controller.NewGWController(ctx, clientset, &s.Config, mc, node)
+	gwc, err := controller.NewGWController(ctx, clientset, dynamicClient, &s.Config, mc, node)
 	if err != nil {
 		return err
 	}
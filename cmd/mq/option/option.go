@@ -36,6 +36,14 @@ type Config struct {
 	RunMode              string //http grpc
 	HostIP               string
 	HostName             string
+	// Backend the message queue storage, etcd or log
+	Backend string
+	// DataDir the directory of the message log, only used by the log backend
+	DataDir string
+	// VisibilityTimeout seconds, a message that is not acknowledged in time will be redelivered
+	VisibilityTimeout int
+	// MaxRedelivery a message that has been redelivered more than MaxRedelivery times is moved to the dead letter topic
+	MaxRedelivery int
 }
 
 //MQServer lb worker server
@@ -63,6 +71,10 @@ func (a *MQServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&a.PrometheusMetricPath, "metric", "/metrics", "prometheus metrics path")
 	fs.StringVar(&a.HostIP, "hostIP", "", "Current node Intranet IP")
 	fs.StringVar(&a.HostName, "hostName", "", "Current node host name")
+	fs.StringVar(&a.Backend, "backend", "etcd", "the message queue storage backend, etcd or log")
+	fs.StringVar(&a.DataDir, "data-dir", "/grdata/mq", "the directory of the message log, only used by the log backend")
+	fs.IntVar(&a.VisibilityTimeout, "visibility-timeout", 300, "seconds, a message that is not acknowledged in time will be redelivered, only used by the log backend")
+	fs.IntVar(&a.MaxRedelivery, "max-redelivery", 5, "a message that has been redelivered more than max-redelivery times is moved to the dead letter topic, only used by the log backend")
 }
 
 //SetLog
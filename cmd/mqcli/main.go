@@ -38,6 +38,7 @@ var taskbody string
 var taskfile string
 var tasktype string
 var mode string
+var receipt string
 
 func main() {
 	AddFlags(pflag.CommandLine)
@@ -80,6 +81,32 @@ func main() {
 		}
 		logrus.Info(re.String())
 	}
+	if mode == "deadletters" {
+		re, err := c.DeadLetters(context.Background(), &pb.DeadLetterRequest{
+			Topic: topic,
+		})
+		if err != nil {
+			logrus.Error("list dead letters error.", err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%d dead letters in topic %s\n", len(re.Messages), topic)
+		for _, m := range re.Messages {
+			fmt.Printf("receipt:%s task_id:%s task_type:%s create_time:%s redelivery:%d\n",
+				m.Receipt, m.TaskId, m.TaskType, m.CreateTime, m.RedeliveryCount)
+			fmt.Println("taskbody:" + string(m.TaskBody))
+		}
+	}
+	if mode == "requeue" {
+		re, err := c.Requeue(context.Background(), &pb.RequeueRequest{
+			Topic:   topic,
+			Receipt: receipt,
+		})
+		if err != nil {
+			logrus.Error("requeue error.", err.Error())
+			os.Exit(1)
+		}
+		logrus.Info(re.String())
+	}
 
 }
 
@@ -89,5 +116,6 @@ func AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&taskbody, "task-body", "", "mq task body")
 	fs.StringVar(&taskfile, "task-file", "", "mq task body file")
 	fs.StringVar(&tasktype, "task-type", "", "mq task type")
-	fs.StringVar(&mode, "mode", "enqueue", "mq task type")
+	fs.StringVar(&mode, "mode", "enqueue", "enqueue, dequeue, deadletters or requeue")
+	fs.StringVar(&receipt, "receipt", "", "the receipt of the dead letter to requeue, requeue all the dead letters of the topic if it is empty")
 }
@@ -27,8 +27,8 @@ import (
 	"time"
 
 	client "github.com/coreos/etcd/clientv3"
-	dockercli "github.com/docker/docker/client"
 	"github.com/fsnotify/fsnotify"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 	"github.com/gridworkz/kato/util"
 	etcdutil "github.com/gridworkz/kato/util/etcd"
 	"github.com/sirupsen/logrus"
@@ -100,9 +100,14 @@ type Conf struct {
 	AutoRegistNode  bool
 	//enable collect docker container log
 	EnableCollectLog bool
-	DockerCli        *dockercli.Client
 	EtcdCli          *client.Client
 
+	// ContainerRuntimeType is the container runtime of the node, docker or containerd
+	ContainerRuntimeType string
+	// RuntimeEndpoint is the cri endpoint of the containerd runtime
+	RuntimeEndpoint  string
+	ContainerRuntime containerruntime.ContainerRuntime
+
 	LicPath   string
 	LicSoPath string
 
@@ -127,6 +132,11 @@ type Conf struct {
 	ImageRepositoryHost string
 	GatewayVIP          string
 	HostsFile           string
+
+	// MeshCASecret is the secret in RbdNamespace storing the ca of the mesh mutual tls
+	MeshCASecret string
+	// MeshCertTTL is the validity of the certificates issued to the components
+	MeshCertTTL time.Duration
 }
 
 //StatsdConfig
@@ -188,8 +198,12 @@ func (a *Conf) AddFlags(fs *pflag.FlagSet) {
 	fs.Int32Var(&a.ImageGCHighThresholdPercent, "image-gc-high-threshold", 90, "The percent of disk usage after which image garbage collection is always run. Values must be within the range [0, 100], To disable image garbage collection, set to 100. ")
 	fs.Int32Var(&a.ImageGCLowThresholdPercent, "image-gc-low-threshold", 75, "The percent of disk usage before which image garbage collection is never run. Lowest disk usage to garbage collect to. Values must be within the range [0, 100] and should not be larger than that of --image-gc-high-threshold.")
 	fs.StringVar(&a.RbdNamespace, "rbd-ns", "rbd-system", "The namespace of kato applications.")
+	fs.StringVar(&a.MeshCASecret, "mesh-ca-secret", "kato-mesh-ca", "The secret storing the ca which issues the mutual tls certificates of the mesh connected components.")
+	fs.DurationVar(&a.MeshCertTTL, "mesh-cert-ttl", 24*time.Hour, "The validity of the mutual tls certificates of the components, they are rotated after two thirds of it.")
 	fs.StringVar(&a.ImageRepositoryHost, "image-repo-host", "gridworkz", "The host of image repository")
 	fs.StringVar(&a.GatewayVIP, "gateway-vip", "", "The vip of gateway")
+	fs.StringVar(&a.ContainerRuntimeType, "container-runtime", containerruntime.RuntimeDocker, "The container runtime of the node, could be 'docker' or 'containerd'.")
+	fs.StringVar(&a.RuntimeEndpoint, "runtime-endpoint", containerruntime.DefaultRuntimeEndpoint, "The cri endpoint of the container runtime, only used by containerd.")
 	fs.StringVar(&a.HostsFile, "hostsfile", "/newetc/hosts", "/etc/hosts mapped path in the container. eg. /etc/hosts:/tmp/hosts. Do not set hostsfile to /etc/hosts")
 }
 
@@ -217,9 +231,9 @@ func (a *Conf) SetLog() {
 
 //ParseClient handle config and create some api
 func (a *Conf) ParseClient(ctx context.Context, etcdClientArgs *etcdutil.ClientArgs) (err error) {
-	a.DockerCli, err = dockercli.NewEnvClient()
+	a.ContainerRuntime, err = containerruntime.New(a.ContainerRuntimeType, a.RuntimeEndpoint)
 	if err != nil {
-		return err
+		return fmt.Errorf("create %s container runtime: %v", a.ContainerRuntimeType, err)
 	}
 	logrus.Infof("begin create etcd client: %s", a.EtcdEndpoints)
 	for {
@@ -249,6 +263,10 @@ func (a *Conf) parse() error {
 		}
 		a.HostIP = localIP.String()
 	}
+	// the container runtime is docker if it is not specified, which the registry cert is synced for
+	if a.ContainerRuntimeType == "" {
+		a.ContainerRuntimeType = containerruntime.RuntimeDocker
+	}
 	//init api listen port, can not custom
 	if a.APIAddr == "" {
 		a.APIAddr = ":6100"
@@ -37,6 +37,7 @@ import (
 	"github.com/gridworkz/kato/node/kubecache"
 	"github.com/gridworkz/kato/node/masterserver"
 	"github.com/gridworkz/kato/node/nodem"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 	"github.com/gridworkz/kato/node/nodem/docker"
 	"github.com/gridworkz/kato/node/nodem/envoy"
 	etcdutil "github.com/gridworkz/kato/util/etcd"
@@ -114,8 +115,15 @@ func Run(cfg *option.Conf) error {
 
 		logrus.Debugf("rbd-namespace=%s; rbd-docker-secret=%s", os.Getenv("RBD_NAMESPACE"), os.Getenv("RBD_DOCKER_SECRET"))
 		// sync docker inscure registries cert info into all kato node
-		if err = docker.SyncDockerCertFromSecret(clientset, os.Getenv("RBD_NAMESPACE"), os.Getenv("RBD_DOCKER_SECRET")); err != nil { // TODO gridworkz namespace secretname
-			return fmt.Errorf("sync docker cert from secret error: %s", err.Error())
+		switch cfg.ContainerRuntimeType {
+		case containerruntime.RuntimeDocker:
+			if err = docker.SyncDockerCertFromSecret(clientset, os.Getenv("RBD_NAMESPACE"), os.Getenv("RBD_DOCKER_SECRET")); err != nil { // TODO gridworkz namespace secretname
+				return fmt.Errorf("sync docker cert from secret error: %s", err.Error())
+			}
+		case containerruntime.RuntimeContainerd:
+			if err = docker.SyncContainerdCertFromSecret(clientset, os.Getenv("RBD_NAMESPACE"), os.Getenv("RBD_DOCKER_SECRET")); err != nil {
+				return fmt.Errorf("sync containerd cert from secret error: %s", err.Error())
+			}
 		}
 
 		// init etcd client
@@ -482,6 +482,28 @@ type AppBackupDao interface {
 	GetAppBackup(backupID string) (*model.AppBackup, error)
 	GetDeleteAppBackup(backupID string) (*model.AppBackup, error)
 	GetDeleteAppBackups() ([]*model.AppBackup, error)
+	ListByScheduleID(scheduleID string) ([]*model.AppBackup, error)
+}
+
+//AppBackupScheduleDao group app backup schedule
+type AppBackupScheduleDao interface {
+	Dao
+	GetByScheduleID(scheduleID string) (*model.AppBackupSchedule, error)
+	ListByGroupID(groupID string) ([]*model.AppBackupSchedule, error)
+	ListEnableOnes() ([]*model.AppBackupSchedule, error)
+	DeleteByScheduleID(scheduleID string) error
+	UpdateNextTime(scheduleID string, nextTime, newNextTime time.Time) (bool, error)
+	UpdateLastBackup(scheduleID, backupID, status string) error
+	UpdateLastStatus(scheduleID, backupID, status string) (bool, error)
+}
+
+//AppBackupVolumeSnapshotDao the incremental backups of the volumes
+type AppBackupVolumeSnapshotDao interface {
+	Dao
+	ListByGroupID(groupID string) ([]*model.AppBackupVolumeSnapshot, error)
+	ListByBackupID(backupID string) ([]*model.AppBackupVolumeSnapshot, error)
+	GetLatest(serviceID, volumeName string) (*model.AppBackupVolumeSnapshot, error)
+	DeleteByBackupID(backupID string) error
 }
 
 //ServiceSourceDao service source dao
@@ -593,6 +615,47 @@ type TenantServiceScalingRecordsDao interface {
 	CountByServiceID(serviceID string) (int, error)
 }
 
+// TenantServiceScheduledScalingPolicyDao -
+type TenantServiceScheduledScalingPolicyDao interface {
+	Dao
+	GetByPolicyID(policyID string) (*model.TenantServiceScheduledScalingPolicy, error)
+	ListByServiceID(serviceID string) ([]*model.TenantServiceScheduledScalingPolicy, error)
+	ListEnableOnes() ([]*model.TenantServiceScheduledScalingPolicy, error)
+	DeleteByPolicyID(policyID string) error
+	DeleteByServiceID(serviceID string) error
+}
+
+// TenantServiceCanaryDao -
+type TenantServiceCanaryDao interface {
+	Dao
+	GetByServiceID(serviceID string) (*model.TenantServiceCanary, error)
+	DeleteByServiceID(serviceID string) error
+}
+
+// TenantServiceWebhookDao -
+type TenantServiceWebhookDao interface {
+	Dao
+	GetByServiceID(serviceID string) (*model.TenantServiceWebhook, error)
+	DeleteByServiceID(serviceID string) error
+	UpdateLastCommit(serviceID, commit string) (bool, error)
+}
+
+// TenantImagePolicyDao -
+type TenantImagePolicyDao interface {
+	Dao
+	GetByTenantID(tenantID string) (*model.TenantImagePolicy, error)
+	DeleteByTenantID(tenantID string) error
+}
+
+// TenantMeshTLSPolicyDao -
+type TenantMeshTLSPolicyDao interface {
+	Dao
+	GetByTenantAndApp(tenantID, appID string) (*model.TenantMeshTLSPolicy, error)
+	DeleteByTenantAndApp(tenantID, appID string) error
+	DeleteByTenantID(tenantID string) error
+	DeleteByAppID(appID string) error
+}
+
 // TenantServiceMonitorDao -
 type TenantServiceMonitorDao interface {
 	Dao
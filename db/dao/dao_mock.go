@@ -4915,6 +4915,285 @@ func (mr *MockAppBackupDaoMockRecorder) GetDeleteAppBackups() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteAppBackups", reflect.TypeOf((*MockAppBackupDao)(nil).GetDeleteAppBackups))
 }
 
+// ListByScheduleID mocks base method.
+func (m *MockAppBackupDao) ListByScheduleID(scheduleID string) ([]*model.AppBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByScheduleID", scheduleID)
+	ret0, _ := ret[0].([]*model.AppBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByScheduleID indicates an expected call of ListByScheduleID.
+func (mr *MockAppBackupDaoMockRecorder) ListByScheduleID(scheduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByScheduleID", reflect.TypeOf((*MockAppBackupDao)(nil).ListByScheduleID), scheduleID)
+}
+
+// MockAppBackupScheduleDao is a mock of AppBackupScheduleDao interface.
+type MockAppBackupScheduleDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppBackupScheduleDaoMockRecorder
+}
+
+// MockAppBackupScheduleDaoMockRecorder is the mock recorder for MockAppBackupScheduleDao.
+type MockAppBackupScheduleDaoMockRecorder struct {
+	mock *MockAppBackupScheduleDao
+}
+
+// NewMockAppBackupScheduleDao creates a new mock instance.
+func NewMockAppBackupScheduleDao(ctrl *gomock.Controller) *MockAppBackupScheduleDao {
+	mock := &MockAppBackupScheduleDao{ctrl: ctrl}
+	mock.recorder = &MockAppBackupScheduleDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppBackupScheduleDao) EXPECT() *MockAppBackupScheduleDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockAppBackupScheduleDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockAppBackupScheduleDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockAppBackupScheduleDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockAppBackupScheduleDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).UpdateModel), arg0)
+}
+
+// GetByScheduleID mocks base method.
+func (m *MockAppBackupScheduleDao) GetByScheduleID(scheduleID string) (*model.AppBackupSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByScheduleID", scheduleID)
+	ret0, _ := ret[0].(*model.AppBackupSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByScheduleID indicates an expected call of GetByScheduleID.
+func (mr *MockAppBackupScheduleDaoMockRecorder) GetByScheduleID(scheduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByScheduleID", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).GetByScheduleID), scheduleID)
+}
+
+// ListByGroupID mocks base method.
+func (m *MockAppBackupScheduleDao) ListByGroupID(groupID string) ([]*model.AppBackupSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByGroupID", groupID)
+	ret0, _ := ret[0].([]*model.AppBackupSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByGroupID indicates an expected call of ListByGroupID.
+func (mr *MockAppBackupScheduleDaoMockRecorder) ListByGroupID(groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByGroupID", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).ListByGroupID), groupID)
+}
+
+// ListEnableOnes mocks base method.
+func (m *MockAppBackupScheduleDao) ListEnableOnes() ([]*model.AppBackupSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnableOnes")
+	ret0, _ := ret[0].([]*model.AppBackupSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnableOnes indicates an expected call of ListEnableOnes.
+func (mr *MockAppBackupScheduleDaoMockRecorder) ListEnableOnes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnableOnes", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).ListEnableOnes))
+}
+
+// DeleteByScheduleID mocks base method.
+func (m *MockAppBackupScheduleDao) DeleteByScheduleID(scheduleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByScheduleID", scheduleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByScheduleID indicates an expected call of DeleteByScheduleID.
+func (mr *MockAppBackupScheduleDaoMockRecorder) DeleteByScheduleID(scheduleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByScheduleID", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).DeleteByScheduleID), scheduleID)
+}
+
+// UpdateNextTime mocks base method.
+func (m *MockAppBackupScheduleDao) UpdateNextTime(scheduleID string, nextTime time.Time, newNextTime time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNextTime", scheduleID, nextTime, newNextTime)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNextTime indicates an expected call of UpdateNextTime.
+func (mr *MockAppBackupScheduleDaoMockRecorder) UpdateNextTime(scheduleID interface{}, nextTime interface{}, newNextTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNextTime", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).UpdateNextTime), scheduleID, nextTime, newNextTime)
+}
+
+// UpdateLastBackup mocks base method.
+func (m *MockAppBackupScheduleDao) UpdateLastBackup(scheduleID string, backupID string, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastBackup", scheduleID, backupID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastBackup indicates an expected call of UpdateLastBackup.
+func (mr *MockAppBackupScheduleDaoMockRecorder) UpdateLastBackup(scheduleID interface{}, backupID interface{}, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastBackup", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).UpdateLastBackup), scheduleID, backupID, status)
+}
+
+// UpdateLastStatus mocks base method.
+func (m *MockAppBackupScheduleDao) UpdateLastStatus(scheduleID string, backupID string, status string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastStatus", scheduleID, backupID, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLastStatus indicates an expected call of UpdateLastStatus.
+func (mr *MockAppBackupScheduleDaoMockRecorder) UpdateLastStatus(scheduleID interface{}, backupID interface{}, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastStatus", reflect.TypeOf((*MockAppBackupScheduleDao)(nil).UpdateLastStatus), scheduleID, backupID, status)
+}
+
+// MockAppBackupVolumeSnapshotDao is a mock of AppBackupVolumeSnapshotDao interface.
+type MockAppBackupVolumeSnapshotDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppBackupVolumeSnapshotDaoMockRecorder
+}
+
+// MockAppBackupVolumeSnapshotDaoMockRecorder is the mock recorder for MockAppBackupVolumeSnapshotDao.
+type MockAppBackupVolumeSnapshotDaoMockRecorder struct {
+	mock *MockAppBackupVolumeSnapshotDao
+}
+
+// NewMockAppBackupVolumeSnapshotDao creates a new mock instance.
+func NewMockAppBackupVolumeSnapshotDao(ctrl *gomock.Controller) *MockAppBackupVolumeSnapshotDao {
+	mock := &MockAppBackupVolumeSnapshotDao{ctrl: ctrl}
+	mock.recorder = &MockAppBackupVolumeSnapshotDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAppBackupVolumeSnapshotDao) EXPECT() *MockAppBackupVolumeSnapshotDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).UpdateModel), arg0)
+}
+
+// ListByGroupID mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) ListByGroupID(groupID string) ([]*model.AppBackupVolumeSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByGroupID", groupID)
+	ret0, _ := ret[0].([]*model.AppBackupVolumeSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByGroupID indicates an expected call of ListByGroupID.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) ListByGroupID(groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByGroupID", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).ListByGroupID), groupID)
+}
+
+// ListByBackupID mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) ListByBackupID(backupID string) ([]*model.AppBackupVolumeSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByBackupID", backupID)
+	ret0, _ := ret[0].([]*model.AppBackupVolumeSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByBackupID indicates an expected call of ListByBackupID.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) ListByBackupID(backupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByBackupID", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).ListByBackupID), backupID)
+}
+
+// GetLatest mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) GetLatest(serviceID string, volumeName string) (*model.AppBackupVolumeSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatest", serviceID, volumeName)
+	ret0, _ := ret[0].(*model.AppBackupVolumeSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatest indicates an expected call of GetLatest.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) GetLatest(serviceID interface{}, volumeName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatest", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).GetLatest), serviceID, volumeName)
+}
+
+// DeleteByBackupID mocks base method.
+func (m *MockAppBackupVolumeSnapshotDao) DeleteByBackupID(backupID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByBackupID", backupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByBackupID indicates an expected call of DeleteByBackupID.
+func (mr *MockAppBackupVolumeSnapshotDaoMockRecorder) DeleteByBackupID(backupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByBackupID", reflect.TypeOf((*MockAppBackupVolumeSnapshotDao)(nil).DeleteByBackupID), backupID)
+}
+
 // MockServiceSourceDao is a mock of ServiceSourceDao interface.
 type MockServiceSourceDao struct {
 	ctrl     *gomock.Controller
@@ -6018,6 +6297,493 @@ func (mr *MockTenantServiceScalingRecordsDaoMockRecorder) CountByServiceID(servi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByServiceID", reflect.TypeOf((*MockTenantServiceScalingRecordsDao)(nil).CountByServiceID), serviceID)
 }
 
+// MockTenantServiceScheduledScalingPolicyDao is a mock of TenantServiceScheduledScalingPolicyDao interface.
+type MockTenantServiceScheduledScalingPolicyDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceScheduledScalingPolicyDaoMockRecorder
+}
+
+// MockTenantServiceScheduledScalingPolicyDaoMockRecorder is the mock recorder for MockTenantServiceScheduledScalingPolicyDao.
+type MockTenantServiceScheduledScalingPolicyDaoMockRecorder struct {
+	mock *MockTenantServiceScheduledScalingPolicyDao
+}
+
+// NewMockTenantServiceScheduledScalingPolicyDao creates a new mock instance.
+func NewMockTenantServiceScheduledScalingPolicyDao(ctrl *gomock.Controller) *MockTenantServiceScheduledScalingPolicyDao {
+	mock := &MockTenantServiceScheduledScalingPolicyDao{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceScheduledScalingPolicyDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantServiceScheduledScalingPolicyDao) EXPECT() *MockTenantServiceScheduledScalingPolicyDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).UpdateModel), arg0)
+}
+
+// GetByPolicyID mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) GetByPolicyID(policyID string) (*model.TenantServiceScheduledScalingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPolicyID", policyID)
+	ret0, _ := ret[0].(*model.TenantServiceScheduledScalingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPolicyID indicates an expected call of GetByPolicyID.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) GetByPolicyID(policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPolicyID", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).GetByPolicyID), policyID)
+}
+
+// ListByServiceID mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) ListByServiceID(serviceID string) ([]*model.TenantServiceScheduledScalingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByServiceID", serviceID)
+	ret0, _ := ret[0].([]*model.TenantServiceScheduledScalingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByServiceID indicates an expected call of ListByServiceID.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) ListByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByServiceID", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).ListByServiceID), serviceID)
+}
+
+// ListEnableOnes mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) ListEnableOnes() ([]*model.TenantServiceScheduledScalingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnableOnes")
+	ret0, _ := ret[0].([]*model.TenantServiceScheduledScalingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnableOnes indicates an expected call of ListEnableOnes.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) ListEnableOnes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnableOnes", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).ListEnableOnes))
+}
+
+// DeleteByPolicyID mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) DeleteByPolicyID(policyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByPolicyID", policyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByPolicyID indicates an expected call of DeleteByPolicyID.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) DeleteByPolicyID(policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByPolicyID", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).DeleteByPolicyID), policyID)
+}
+
+// DeleteByServiceID mocks base method.
+func (m *MockTenantServiceScheduledScalingPolicyDao) DeleteByServiceID(serviceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByServiceID", serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByServiceID indicates an expected call of DeleteByServiceID.
+func (mr *MockTenantServiceScheduledScalingPolicyDaoMockRecorder) DeleteByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByServiceID", reflect.TypeOf((*MockTenantServiceScheduledScalingPolicyDao)(nil).DeleteByServiceID), serviceID)
+}
+
+// MockTenantServiceCanaryDao is a mock of TenantServiceCanaryDao interface.
+type MockTenantServiceCanaryDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceCanaryDaoMockRecorder
+}
+
+// MockTenantServiceCanaryDaoMockRecorder is the mock recorder for MockTenantServiceCanaryDao.
+type MockTenantServiceCanaryDaoMockRecorder struct {
+	mock *MockTenantServiceCanaryDao
+}
+
+// NewMockTenantServiceCanaryDao creates a new mock instance.
+func NewMockTenantServiceCanaryDao(ctrl *gomock.Controller) *MockTenantServiceCanaryDao {
+	mock := &MockTenantServiceCanaryDao{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceCanaryDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantServiceCanaryDao) EXPECT() *MockTenantServiceCanaryDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockTenantServiceCanaryDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockTenantServiceCanaryDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockTenantServiceCanaryDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockTenantServiceCanaryDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockTenantServiceCanaryDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockTenantServiceCanaryDao)(nil).UpdateModel), arg0)
+}
+
+// GetByServiceID mocks base method.
+func (m *MockTenantServiceCanaryDao) GetByServiceID(serviceID string) (*model.TenantServiceCanary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByServiceID", serviceID)
+	ret0, _ := ret[0].(*model.TenantServiceCanary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByServiceID indicates an expected call of GetByServiceID.
+func (mr *MockTenantServiceCanaryDaoMockRecorder) GetByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByServiceID", reflect.TypeOf((*MockTenantServiceCanaryDao)(nil).GetByServiceID), serviceID)
+}
+
+// DeleteByServiceID mocks base method.
+func (m *MockTenantServiceCanaryDao) DeleteByServiceID(serviceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByServiceID", serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByServiceID indicates an expected call of DeleteByServiceID.
+func (mr *MockTenantServiceCanaryDaoMockRecorder) DeleteByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByServiceID", reflect.TypeOf((*MockTenantServiceCanaryDao)(nil).DeleteByServiceID), serviceID)
+}
+
+// MockTenantServiceWebhookDao is a mock of TenantServiceWebhookDao interface.
+type MockTenantServiceWebhookDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantServiceWebhookDaoMockRecorder
+}
+
+// MockTenantServiceWebhookDaoMockRecorder is the mock recorder for MockTenantServiceWebhookDao.
+type MockTenantServiceWebhookDaoMockRecorder struct {
+	mock *MockTenantServiceWebhookDao
+}
+
+// NewMockTenantServiceWebhookDao creates a new mock instance.
+func NewMockTenantServiceWebhookDao(ctrl *gomock.Controller) *MockTenantServiceWebhookDao {
+	mock := &MockTenantServiceWebhookDao{ctrl: ctrl}
+	mock.recorder = &MockTenantServiceWebhookDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantServiceWebhookDao) EXPECT() *MockTenantServiceWebhookDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockTenantServiceWebhookDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockTenantServiceWebhookDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockTenantServiceWebhookDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockTenantServiceWebhookDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockTenantServiceWebhookDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockTenantServiceWebhookDao)(nil).UpdateModel), arg0)
+}
+
+// GetByServiceID mocks base method.
+func (m *MockTenantServiceWebhookDao) GetByServiceID(serviceID string) (*model.TenantServiceWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByServiceID", serviceID)
+	ret0, _ := ret[0].(*model.TenantServiceWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByServiceID indicates an expected call of GetByServiceID.
+func (mr *MockTenantServiceWebhookDaoMockRecorder) GetByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByServiceID", reflect.TypeOf((*MockTenantServiceWebhookDao)(nil).GetByServiceID), serviceID)
+}
+
+// DeleteByServiceID mocks base method.
+func (m *MockTenantServiceWebhookDao) DeleteByServiceID(serviceID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByServiceID", serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByServiceID indicates an expected call of DeleteByServiceID.
+func (mr *MockTenantServiceWebhookDaoMockRecorder) DeleteByServiceID(serviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByServiceID", reflect.TypeOf((*MockTenantServiceWebhookDao)(nil).DeleteByServiceID), serviceID)
+}
+
+// UpdateLastCommit mocks base method.
+func (m *MockTenantServiceWebhookDao) UpdateLastCommit(serviceID, commit string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastCommit", serviceID, commit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLastCommit indicates an expected call of UpdateLastCommit.
+func (mr *MockTenantServiceWebhookDaoMockRecorder) UpdateLastCommit(serviceID, commit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastCommit", reflect.TypeOf((*MockTenantServiceWebhookDao)(nil).UpdateLastCommit), serviceID, commit)
+}
+
+// MockTenantImagePolicyDao is a mock of TenantImagePolicyDao interface.
+type MockTenantImagePolicyDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantImagePolicyDaoMockRecorder
+}
+
+// MockTenantImagePolicyDaoMockRecorder is the mock recorder for MockTenantImagePolicyDao.
+type MockTenantImagePolicyDaoMockRecorder struct {
+	mock *MockTenantImagePolicyDao
+}
+
+// NewMockTenantImagePolicyDao creates a new mock instance.
+func NewMockTenantImagePolicyDao(ctrl *gomock.Controller) *MockTenantImagePolicyDao {
+	mock := &MockTenantImagePolicyDao{ctrl: ctrl}
+	mock.recorder = &MockTenantImagePolicyDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantImagePolicyDao) EXPECT() *MockTenantImagePolicyDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockTenantImagePolicyDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockTenantImagePolicyDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockTenantImagePolicyDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockTenantImagePolicyDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockTenantImagePolicyDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockTenantImagePolicyDao)(nil).UpdateModel), arg0)
+}
+
+// GetByTenantID mocks base method.
+func (m *MockTenantImagePolicyDao) GetByTenantID(tenantID string) (*model.TenantImagePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTenantID", tenantID)
+	ret0, _ := ret[0].(*model.TenantImagePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTenantID indicates an expected call of GetByTenantID.
+func (mr *MockTenantImagePolicyDaoMockRecorder) GetByTenantID(tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTenantID", reflect.TypeOf((*MockTenantImagePolicyDao)(nil).GetByTenantID), tenantID)
+}
+
+// DeleteByTenantID mocks base method.
+func (m *MockTenantImagePolicyDao) DeleteByTenantID(tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTenantID", tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByTenantID indicates an expected call of DeleteByTenantID.
+func (mr *MockTenantImagePolicyDaoMockRecorder) DeleteByTenantID(tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTenantID", reflect.TypeOf((*MockTenantImagePolicyDao)(nil).DeleteByTenantID), tenantID)
+}
+
+// MockTenantMeshTLSPolicyDao is a mock of TenantMeshTLSPolicyDao interface.
+type MockTenantMeshTLSPolicyDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantMeshTLSPolicyDaoMockRecorder
+}
+
+// MockTenantMeshTLSPolicyDaoMockRecorder is the mock recorder for MockTenantMeshTLSPolicyDao.
+type MockTenantMeshTLSPolicyDaoMockRecorder struct {
+	mock *MockTenantMeshTLSPolicyDao
+}
+
+// NewMockTenantMeshTLSPolicyDao creates a new mock instance.
+func NewMockTenantMeshTLSPolicyDao(ctrl *gomock.Controller) *MockTenantMeshTLSPolicyDao {
+	mock := &MockTenantMeshTLSPolicyDao{ctrl: ctrl}
+	mock.recorder = &MockTenantMeshTLSPolicyDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTenantMeshTLSPolicyDao) EXPECT() *MockTenantMeshTLSPolicyDaoMockRecorder {
+	return m.recorder
+}
+
+// AddModel mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) AddModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddModel indicates an expected call of AddModel.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) AddModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddModel", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).AddModel), arg0)
+}
+
+// UpdateModel mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) UpdateModel(arg0 model.Interface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateModel indicates an expected call of UpdateModel.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) UpdateModel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModel", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).UpdateModel), arg0)
+}
+
+// GetByTenantAndApp mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) GetByTenantAndApp(tenantID, appID string) (*model.TenantMeshTLSPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTenantAndApp", tenantID, appID)
+	ret0, _ := ret[0].(*model.TenantMeshTLSPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTenantAndApp indicates an expected call of GetByTenantAndApp.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) GetByTenantAndApp(tenantID interface{}, appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTenantAndApp", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).GetByTenantAndApp), tenantID, appID)
+}
+
+// DeleteByTenantAndApp mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) DeleteByTenantAndApp(tenantID, appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTenantAndApp", tenantID, appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByTenantAndApp indicates an expected call of DeleteByTenantAndApp.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) DeleteByTenantAndApp(tenantID interface{}, appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTenantAndApp", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).DeleteByTenantAndApp), tenantID, appID)
+}
+
+// DeleteByTenantID mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) DeleteByTenantID(tenantID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByTenantID", tenantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByTenantID indicates an expected call of DeleteByTenantID.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) DeleteByTenantID(tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByTenantID", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).DeleteByTenantID), tenantID)
+}
+
+// DeleteByAppID mocks base method.
+func (m *MockTenantMeshTLSPolicyDao) DeleteByAppID(appID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByAppID", appID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByAppID indicates an expected call of DeleteByAppID.
+func (mr *MockTenantMeshTLSPolicyDaoMockRecorder) DeleteByAppID(appID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByAppID", reflect.TypeOf((*MockTenantMeshTLSPolicyDao)(nil).DeleteByAppID), appID)
+}
+
 // MockTenantServiceMonitorDao is a mock of TenantServiceMonitorDao interface.
 type MockTenantServiceMonitorDao struct {
 	ctrl     *gomock.Controller
@@ -108,6 +108,10 @@ type Manager interface {
 	NotificationEventDao() dao.NotificationEventDao
 	AppBackupDao() dao.AppBackupDao
 	AppBackupDaoTransactions(db *gorm.DB) dao.AppBackupDao
+	AppBackupScheduleDao() dao.AppBackupScheduleDao
+	AppBackupScheduleDaoTransactions(db *gorm.DB) dao.AppBackupScheduleDao
+	AppBackupVolumeSnapshotDao() dao.AppBackupVolumeSnapshotDao
+	AppBackupVolumeSnapshotDaoTransactions(db *gorm.DB) dao.AppBackupVolumeSnapshotDao
 	ServiceSourceDao() dao.ServiceSourceDao
 
 	// gateway
@@ -134,6 +138,16 @@ type Manager interface {
 	TenantServceAutoscalerRuleMetricsDaoTransactions(db *gorm.DB) dao.TenantServceAutoscalerRuleMetricsDao
 	TenantServiceScalingRecordsDao() dao.TenantServiceScalingRecordsDao
 	TenantServiceScalingRecordsDaoTransactions(db *gorm.DB) dao.TenantServiceScalingRecordsDao
+	TenantServiceScheduledScalingPolicyDao() dao.TenantServiceScheduledScalingPolicyDao
+	TenantServiceScheduledScalingPolicyDaoTransactions(db *gorm.DB) dao.TenantServiceScheduledScalingPolicyDao
+	TenantServiceCanaryDao() dao.TenantServiceCanaryDao
+	TenantServiceCanaryDaoTransactions(db *gorm.DB) dao.TenantServiceCanaryDao
+	TenantServiceWebhookDao() dao.TenantServiceWebhookDao
+	TenantServiceWebhookDaoTransactions(db *gorm.DB) dao.TenantServiceWebhookDao
+	TenantImagePolicyDao() dao.TenantImagePolicyDao
+	TenantImagePolicyDaoTransactions(db *gorm.DB) dao.TenantImagePolicyDao
+	TenantMeshTLSPolicyDao() dao.TenantMeshTLSPolicyDao
+	TenantMeshTLSPolicyDaoTransactions(db *gorm.DB) dao.TenantMeshTLSPolicyDao
 
 	TenantServiceMonitorDao() dao.TenantServiceMonitorDao
 	TenantServiceMonitorDaoTransactions(db *gorm.DB) dao.TenantServiceMonitorDao
@@ -145,7 +159,7 @@ var supportDrivers map[string]struct{}
 
 func init() {
 	supportDrivers = map[string]struct{}{
-		"mysql":       {},
+		"mysql":      {},
 		"yugabytedb": {},
 	}
 }
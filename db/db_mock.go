@@ -838,6 +838,54 @@ func (mr *MockManagerMockRecorder) AppBackupDaoTransactions(db interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppBackupDaoTransactions", reflect.TypeOf((*MockManager)(nil).AppBackupDaoTransactions), db)
 }
 
+// AppBackupScheduleDao mocks base method
+func (m *MockManager) AppBackupScheduleDao() dao.AppBackupScheduleDao {
+	ret := m.ctrl.Call(m, "AppBackupScheduleDao")
+	ret0, _ := ret[0].(dao.AppBackupScheduleDao)
+	return ret0
+}
+
+// AppBackupScheduleDao indicates an expected call of AppBackupScheduleDao
+func (mr *MockManagerMockRecorder) AppBackupScheduleDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppBackupScheduleDao", reflect.TypeOf((*MockManager)(nil).AppBackupScheduleDao))
+}
+
+// AppBackupScheduleDaoTransactions mocks base method
+func (m *MockManager) AppBackupScheduleDaoTransactions(db *gorm.DB) dao.AppBackupScheduleDao {
+	ret := m.ctrl.Call(m, "AppBackupScheduleDaoTransactions", db)
+	ret0, _ := ret[0].(dao.AppBackupScheduleDao)
+	return ret0
+}
+
+// AppBackupScheduleDaoTransactions indicates an expected call of AppBackupScheduleDaoTransactions
+func (mr *MockManagerMockRecorder) AppBackupScheduleDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppBackupScheduleDaoTransactions", reflect.TypeOf((*MockManager)(nil).AppBackupScheduleDaoTransactions), db)
+}
+
+// AppBackupVolumeSnapshotDao mocks base method
+func (m *MockManager) AppBackupVolumeSnapshotDao() dao.AppBackupVolumeSnapshotDao {
+	ret := m.ctrl.Call(m, "AppBackupVolumeSnapshotDao")
+	ret0, _ := ret[0].(dao.AppBackupVolumeSnapshotDao)
+	return ret0
+}
+
+// AppBackupVolumeSnapshotDao indicates an expected call of AppBackupVolumeSnapshotDao
+func (mr *MockManagerMockRecorder) AppBackupVolumeSnapshotDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppBackupVolumeSnapshotDao", reflect.TypeOf((*MockManager)(nil).AppBackupVolumeSnapshotDao))
+}
+
+// AppBackupVolumeSnapshotDaoTransactions mocks base method
+func (m *MockManager) AppBackupVolumeSnapshotDaoTransactions(db *gorm.DB) dao.AppBackupVolumeSnapshotDao {
+	ret := m.ctrl.Call(m, "AppBackupVolumeSnapshotDaoTransactions", db)
+	ret0, _ := ret[0].(dao.AppBackupVolumeSnapshotDao)
+	return ret0
+}
+
+// AppBackupVolumeSnapshotDaoTransactions indicates an expected call of AppBackupVolumeSnapshotDaoTransactions
+func (mr *MockManagerMockRecorder) AppBackupVolumeSnapshotDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppBackupVolumeSnapshotDaoTransactions", reflect.TypeOf((*MockManager)(nil).AppBackupVolumeSnapshotDaoTransactions), db)
+}
+
 // ServiceSourceDao mocks base method
 func (m *MockManager) ServiceSourceDao() dao.ServiceSourceDao {
 	ret := m.ctrl.Call(m, "ServiceSourceDao")
@@ -1090,6 +1138,126 @@ func (mr *MockManagerMockRecorder) TenantServiceScalingRecordsDaoTransactions(db
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceScalingRecordsDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantServiceScalingRecordsDaoTransactions), db)
 }
 
+// TenantServiceScheduledScalingPolicyDao mocks base method
+func (m *MockManager) TenantServiceScheduledScalingPolicyDao() dao.TenantServiceScheduledScalingPolicyDao {
+	ret := m.ctrl.Call(m, "TenantServiceScheduledScalingPolicyDao")
+	ret0, _ := ret[0].(dao.TenantServiceScheduledScalingPolicyDao)
+	return ret0
+}
+
+// TenantServiceScheduledScalingPolicyDao indicates an expected call of TenantServiceScheduledScalingPolicyDao
+func (mr *MockManagerMockRecorder) TenantServiceScheduledScalingPolicyDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceScheduledScalingPolicyDao", reflect.TypeOf((*MockManager)(nil).TenantServiceScheduledScalingPolicyDao))
+}
+
+// TenantServiceScheduledScalingPolicyDaoTransactions mocks base method
+func (m *MockManager) TenantServiceScheduledScalingPolicyDaoTransactions(db *gorm.DB) dao.TenantServiceScheduledScalingPolicyDao {
+	ret := m.ctrl.Call(m, "TenantServiceScheduledScalingPolicyDaoTransactions", db)
+	ret0, _ := ret[0].(dao.TenantServiceScheduledScalingPolicyDao)
+	return ret0
+}
+
+// TenantServiceScheduledScalingPolicyDaoTransactions indicates an expected call of TenantServiceScheduledScalingPolicyDaoTransactions
+func (mr *MockManagerMockRecorder) TenantServiceScheduledScalingPolicyDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceScheduledScalingPolicyDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantServiceScheduledScalingPolicyDaoTransactions), db)
+}
+
+// TenantServiceCanaryDao mocks base method
+func (m *MockManager) TenantServiceCanaryDao() dao.TenantServiceCanaryDao {
+	ret := m.ctrl.Call(m, "TenantServiceCanaryDao")
+	ret0, _ := ret[0].(dao.TenantServiceCanaryDao)
+	return ret0
+}
+
+// TenantServiceCanaryDao indicates an expected call of TenantServiceCanaryDao
+func (mr *MockManagerMockRecorder) TenantServiceCanaryDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceCanaryDao", reflect.TypeOf((*MockManager)(nil).TenantServiceCanaryDao))
+}
+
+// TenantServiceCanaryDaoTransactions mocks base method
+func (m *MockManager) TenantServiceCanaryDaoTransactions(db *gorm.DB) dao.TenantServiceCanaryDao {
+	ret := m.ctrl.Call(m, "TenantServiceCanaryDaoTransactions", db)
+	ret0, _ := ret[0].(dao.TenantServiceCanaryDao)
+	return ret0
+}
+
+// TenantServiceCanaryDaoTransactions indicates an expected call of TenantServiceCanaryDaoTransactions
+func (mr *MockManagerMockRecorder) TenantServiceCanaryDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceCanaryDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantServiceCanaryDaoTransactions), db)
+}
+
+// TenantServiceWebhookDao mocks base method
+func (m *MockManager) TenantServiceWebhookDao() dao.TenantServiceWebhookDao {
+	ret := m.ctrl.Call(m, "TenantServiceWebhookDao")
+	ret0, _ := ret[0].(dao.TenantServiceWebhookDao)
+	return ret0
+}
+
+// TenantServiceWebhookDao indicates an expected call of TenantServiceWebhookDao
+func (mr *MockManagerMockRecorder) TenantServiceWebhookDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceWebhookDao", reflect.TypeOf((*MockManager)(nil).TenantServiceWebhookDao))
+}
+
+// TenantServiceWebhookDaoTransactions mocks base method
+func (m *MockManager) TenantServiceWebhookDaoTransactions(db *gorm.DB) dao.TenantServiceWebhookDao {
+	ret := m.ctrl.Call(m, "TenantServiceWebhookDaoTransactions", db)
+	ret0, _ := ret[0].(dao.TenantServiceWebhookDao)
+	return ret0
+}
+
+// TenantServiceWebhookDaoTransactions indicates an expected call of TenantServiceWebhookDaoTransactions
+func (mr *MockManagerMockRecorder) TenantServiceWebhookDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantServiceWebhookDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantServiceWebhookDaoTransactions), db)
+}
+
+// TenantImagePolicyDao mocks base method
+func (m *MockManager) TenantImagePolicyDao() dao.TenantImagePolicyDao {
+	ret := m.ctrl.Call(m, "TenantImagePolicyDao")
+	ret0, _ := ret[0].(dao.TenantImagePolicyDao)
+	return ret0
+}
+
+// TenantImagePolicyDao indicates an expected call of TenantImagePolicyDao
+func (mr *MockManagerMockRecorder) TenantImagePolicyDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantImagePolicyDao", reflect.TypeOf((*MockManager)(nil).TenantImagePolicyDao))
+}
+
+// TenantImagePolicyDaoTransactions mocks base method
+func (m *MockManager) TenantImagePolicyDaoTransactions(db *gorm.DB) dao.TenantImagePolicyDao {
+	ret := m.ctrl.Call(m, "TenantImagePolicyDaoTransactions", db)
+	ret0, _ := ret[0].(dao.TenantImagePolicyDao)
+	return ret0
+}
+
+// TenantImagePolicyDaoTransactions indicates an expected call of TenantImagePolicyDaoTransactions
+func (mr *MockManagerMockRecorder) TenantImagePolicyDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantImagePolicyDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantImagePolicyDaoTransactions), db)
+}
+
+// TenantMeshTLSPolicyDao mocks base method
+func (m *MockManager) TenantMeshTLSPolicyDao() dao.TenantMeshTLSPolicyDao {
+	ret := m.ctrl.Call(m, "TenantMeshTLSPolicyDao")
+	ret0, _ := ret[0].(dao.TenantMeshTLSPolicyDao)
+	return ret0
+}
+
+// TenantMeshTLSPolicyDao indicates an expected call of TenantMeshTLSPolicyDao
+func (mr *MockManagerMockRecorder) TenantMeshTLSPolicyDao() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantMeshTLSPolicyDao", reflect.TypeOf((*MockManager)(nil).TenantMeshTLSPolicyDao))
+}
+
+// TenantMeshTLSPolicyDaoTransactions mocks base method
+func (m *MockManager) TenantMeshTLSPolicyDaoTransactions(db *gorm.DB) dao.TenantMeshTLSPolicyDao {
+	ret := m.ctrl.Call(m, "TenantMeshTLSPolicyDaoTransactions", db)
+	ret0, _ := ret[0].(dao.TenantMeshTLSPolicyDao)
+	return ret0
+}
+
+// TenantMeshTLSPolicyDaoTransactions indicates an expected call of TenantMeshTLSPolicyDaoTransactions
+func (mr *MockManagerMockRecorder) TenantMeshTLSPolicyDaoTransactions(db interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantMeshTLSPolicyDaoTransactions", reflect.TypeOf((*MockManager)(nil).TenantMeshTLSPolicyDaoTransactions), db)
+}
+
 // TenantServiceMonitorDao mocks base method
 func (m *MockManager) TenantServiceMonitorDao() dao.TenantServiceMonitorDao {
 	ret := m.ctrl.Call(m, "TenantServiceMonitorDao")
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // AppStatus
 type AppStatus struct {
 	EventID     string `gorm:"column:event_id;size:32;primary_key" json:"event_id"`
@@ -30,9 +32,88 @@ type AppBackup struct {
 	BackupMode string `gorm:"column:backup_mode;size:32" json:"backup_mode"`
 	BuckupSize int64  `gorm:"column:backup_size;type:bigint" json:"backup_size"`
 	Deleted    bool   `gorm:"column:deleted" json:"deleted"`
+	// sha256 of the backup archive, verified before restoring
+	Checksum string `gorm:"column:checksum;size:64" json:"checksum"`
+	// whether the backup archive is encrypted
+	Encrypted bool `gorm:"column:encrypted" json:"encrypted"`
+	// the schedule creating the backup, empty if the backup is created on demand
+	ScheduleID string `gorm:"column:schedule_id;size:32" json:"schedule_id"`
+	// whether the volume data is backed up incrementally as the volume snapshots
+	Incremental bool `gorm:"column:incremental" json:"incremental"`
+}
+
+//AppBackupVolumeSnapshot the incremental backup of a volume, it is a backup point of the volume
+type AppBackupVolumeSnapshot struct {
+	Model
+	BackupID   string `gorm:"column:backup_id;size:32;index" json:"backup_id"`
+	GroupID    string `gorm:"column:group_id;size:32;index" json:"group_id"`
+	ServiceID  string `gorm:"column:service_id;size:32" json:"service_id"`
+	VolumeName string `gorm:"column:volume_name;size:100" json:"volume_name"`
+	// the backup whose unchanged files are reused
+	ParentID  string `gorm:"column:parent_id;size:32" json:"parent_id"`
+	FileCount int    `gorm:"column:file_count" json:"file_count"`
+	// the total size of the files
+	Size int64 `gorm:"column:size;type:bigint" json:"size"`
+	// the size of the data which is not backed up by the previous backup points
+	AddedSize int64 `gorm:"column:added_size;type:bigint" json:"added_size"`
+}
+
+//TableName
+func (t *AppBackupVolumeSnapshot) TableName() string {
+	return "region_app_backup_volume_snapshot"
 }
 
 //TableName
 func (t *AppBackup) TableName() string {
 	return "region_app_backup"
 }
+
+//AppBackupSchedule creates the backups of the group app periodically,
+//and prunes the backups out of the retention policy.
+type AppBackupSchedule struct {
+	Model
+	ScheduleID string `gorm:"column:schedule_id;size:32;unique_index" json:"schedule_id"`
+	TenantID   string `gorm:"column:tenant_id;size:32" json:"tenant_id"`
+	GroupID    string `gorm:"column:group_id;size:32" json:"group_id"`
+	// five-field cron expression, such as '0 2 * * *'
+	Cron string `gorm:"column:cron;size:64" json:"cron"`
+	// full-online or full-offline
+	Mode string `gorm:"column:mode;size:32" json:"mode"`
+	// comma separated ids of the components to backup
+	ServiceIDs string `gorm:"column:service_ids;type:text" json:"service_ids"`
+	// the console level metadata written into the backups
+	Metadata string `gorm:"column:metadata;type:longtext" json:"-"`
+	Force    bool   `gorm:"column:force" json:"force"`
+	Enable   bool   `gorm:"column:enable" json:"enable"`
+	// whether the volume data is backed up incrementally
+	Incremental bool `gorm:"column:incremental" json:"incremental"`
+
+	// the storage of full-online backups
+	Provider   string `gorm:"column:provider;size:32" json:"provider"`
+	Endpoint   string `gorm:"column:endpoint;size:255" json:"endpoint"`
+	AccessKey  string `gorm:"column:access_key;size:255" json:"access_key"`
+	SecretKey  string `gorm:"column:secret_key;size:255" json:"-"`
+	BucketName string `gorm:"column:bucket_name;size:255" json:"bucket_name"`
+	UseSSL     bool   `gorm:"column:use_ssl" json:"use_ssl"`
+	// the secret holding the key encrypting the backup archives
+	EncryptionKeySecret string `gorm:"column:encryption_key_secret;size:255" json:"encryption_key_secret"`
+
+	// retention policy, the backups are never pruned if all of them are 0
+	KeepLast    int `gorm:"column:keep_last" json:"keep_last"`
+	KeepDaily   int `gorm:"column:keep_daily" json:"keep_daily"`
+	KeepWeekly  int `gorm:"column:keep_weekly" json:"keep_weekly"`
+	KeepMonthly int `gorm:"column:keep_monthly" json:"keep_monthly"`
+
+	NextTime     time.Time  `gorm:"column:next_time" json:"next_time"`
+	LastTime     *time.Time `gorm:"column:last_time" json:"last_time"`
+	LastBackupID string     `gorm:"column:last_backup_id;size:32" json:"last_backup_id"`
+	// starting, success or failed
+	LastStatus string `gorm:"column:last_status;size:32" json:"last_status"`
+	// the number of consecutive failures
+	FailureCount int `gorm:"column:failure_count" json:"failure_count"`
+}
+
+//TableName
+func (t *AppBackupSchedule) TableName() string {
+	return "region_app_backup_schedule"
+}
@@ -82,6 +82,19 @@ type HTTPRule struct {
 	Weight        int    `gorm:"column:weight"`
 	IP            string `gorm:"column:ip"`
 	CertificateID string `gorm:"column:certificate_id"`
+	// RateLimitRPS is the requests per second allowed for a client, 0 means unlimited
+	RateLimitRPS   int `gorm:"column:rate_limit_rps"`
+	RateLimitBurst int `gorm:"column:rate_limit_burst"`
+	// RateLimitKey identifies a client, ip or header:<name>
+	RateLimitKey string `gorm:"column:rate_limit_key"`
+	// ConnectionLimit is the concurrent connections allowed for a client, 0 means unlimited
+	ConnectionLimit int `gorm:"column:connection_limit"`
+	// IPAllowList and IPDenyList are comma separated ips or CIDRs
+	IPAllowList string `gorm:"column:ip_allow_list;size:2047"`
+	IPDenyList  string `gorm:"column:ip_deny_list;size:2047"`
+	// BasicAuthSecret is the name of the secret which contains the htpasswd users in the key auth
+	BasicAuthSecret string `gorm:"column:basic_auth_secret"`
+	ForwardAuthURL  string `gorm:"column:forward_auth_url;size:1023"`
 }
 
 // TableName returns table name of TCPRule
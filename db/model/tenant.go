@@ -584,8 +584,36 @@ type TenantServiceAutoscalerRuleMetrics struct {
 	MetricsName       string `gorm:"column:metric_name;not null"`
 	MetricTargetType  string `gorm:"column:metric_target_type;not null"`
 	MetricTargetValue int    `gorm:"column:metric_target_value;not null"`
+	// MetricSelector is a label selector(e.g. 'queue=orders,env in (prod)') used to narrow down
+	// the series of pods, object and external metrics.
+	MetricSelector string `gorm:"column:metric_selector;size:1024"`
+	// DescribedKind, DescribedName and DescribedAPIVersion identify the kubernetes object
+	// described by an object metric.
+	DescribedKind       string `gorm:"column:described_kind;size:64"`
+	DescribedName       string `gorm:"column:described_name;size:255"`
+	DescribedAPIVersion string `gorm:"column:described_api_version;size:64"`
 }
 
+const (
+	// ResourceMetricsType cpu or memory of the pods
+	ResourceMetricsType = "resource_metrics"
+	// PodsMetricsType custom metrics describing each pod, averaged across the pods
+	PodsMetricsType = "pods_metrics"
+	// ObjectMetricsType custom metrics describing a single kubernetes object
+	ObjectMetricsType = "object_metrics"
+	// ExternalMetricsType metrics not associated with any kubernetes object
+	ExternalMetricsType = "external_metrics"
+)
+
+const (
+	// UtilizationMetricTargetType the target is a percentage of the requested resources
+	UtilizationMetricTargetType = "utilization"
+	// AverageValueMetricTargetType the target is the average value across all the pods
+	AverageValueMetricTargetType = "average_value"
+	// ValueMetricTargetType the target is the raw value of the metric
+	ValueMetricTargetType = "value"
+)
+
 // TableName -
 func (t *TenantServiceAutoscalerRuleMetrics) TableName() string {
 	return "tenant_services_autoscaler_rule_metrics"
@@ -610,6 +638,124 @@ func (t *TenantServiceScalingRecords) TableName() string {
 	return "tenant_services_scaling_records"
 }
 
+// TenantServiceScheduledScalingPolicy keeps the replicas of the component between MinReplicas
+// and MaxReplicas from the activation time of StartCron to the activation time of EndCron.
+type TenantServiceScheduledScalingPolicy struct {
+	Model
+	PolicyID    string `gorm:"column:policy_id;unique;size:32" json:"policy_id"`
+	ServiceID   string `gorm:"column:service_id;size:32" json:"service_id"`
+	Enable      bool   `gorm:"column:enable" json:"enable"`
+	StartCron   string `gorm:"column:start_cron;size:64" json:"start_cron"`
+	EndCron     string `gorm:"column:end_cron;size:64" json:"end_cron"`
+	Timezone    string `gorm:"column:timezone;size:64" json:"timezone"`
+	MinReplicas int    `gorm:"column:min_replicas" json:"min_replicas"`
+	MaxReplicas int    `gorm:"column:max_replicas" json:"max_replicas"`
+}
+
+// TableName -
+func (t *TenantServiceScheduledScalingPolicy) TableName() string {
+	return "tenant_services_scheduled_scaling_policies"
+}
+
+// ScheduledScalingRecordType is the record type of the scaling records created by scheduled scaling policies.
+const ScheduledScalingRecordType = "scheduled"
+
+// TenantServiceCanary is the canary release of a component. The canary version runs side by side
+// with the stable version and receives the traffic selected by Weight, Header or Cookie.
+type TenantServiceCanary struct {
+	Model
+	ServiceID     string `gorm:"column:service_id;unique;size:32" json:"service_id"`
+	StableVersion string `gorm:"column:stable_version;size:32" json:"stable_version"`
+	CanaryVersion string `gorm:"column:canary_version;size:32" json:"canary_version"`
+	// Weight is the percentage of the requests routed to the canary version, 0 to 100
+	Weight int `gorm:"column:weight" json:"weight"`
+	// Header and Cookie route the matched requests to the canary version, such as 'X-Canary=true'
+	Header   string `gorm:"column:header;size:255" json:"header"`
+	Cookie   string `gorm:"column:cookie;size:255" json:"cookie"`
+	Replicas int    `gorm:"column:replicas" json:"replicas"`
+}
+
+// TableName -
+func (t *TenantServiceCanary) TableName() string {
+	return "tenant_services_canary"
+}
+
+// TenantServiceWebhook is the git push webhook of a component built from source code.
+// A push event that passes the signature verification and the filters builds the component.
+type TenantServiceWebhook struct {
+	Model
+	ServiceID string `gorm:"column:service_id;unique;size:32" json:"service_id"`
+	Secret    string `gorm:"column:secret;size:255" json:"secret"`
+	// Branches and Paths are comma separated glob patterns, such as 'master,release-*' and 'src/**,go.mod'.
+	// Empty Branches matches the branch of the webhook only, empty Paths matches all the files.
+	Branches   string `gorm:"column:branches;size:255" json:"branches"`
+	Paths      string `gorm:"column:paths;size:1024" json:"paths"`
+	RepoURL    string `gorm:"column:repo_url;size:2047" json:"repo_url"`
+	Branch     string `gorm:"column:branch;size:255" json:"branch"`
+	ServerType string `gorm:"column:server_type;size:32" json:"server_type"`
+	Lang       string `gorm:"column:lang;size:32" json:"lang"`
+	Runtime    string `gorm:"column:runtime;size:32" json:"runtime"`
+	User       string `gorm:"column:user;size:255" json:"user"`
+	Password   string `gorm:"column:password;size:255" json:"-"`
+	// BuildEnvs is the json encoded build environment variables
+	BuildEnvs string `gorm:"column:build_envs;type:text" json:"build_envs"`
+	Action    string `gorm:"column:action;size:32" json:"action"`
+	Enabled   bool   `gorm:"column:enabled" json:"enabled"`
+	// LastCommit is the commit that triggered the last build, which is used to drop the duplicate deliveries
+	LastCommit string `gorm:"column:last_commit;size:255" json:"last_commit"`
+}
+
+// TableName -
+func (t *TenantServiceWebhook) TableName() string {
+	return "tenant_services_webhook"
+}
+
+// the modes of the image policy
+const (
+	// ImagePolicyModeAudit writes the violations to the event log only
+	ImagePolicyModeAudit = "audit"
+	// ImagePolicyModeEnforce refuses the images violating the policy
+	ImagePolicyModeEnforce = "enforce"
+)
+
+// TenantImagePolicy is the policy of the images built and deployed in the tenant.
+type TenantImagePolicy struct {
+	Model
+	TenantID string `gorm:"column:tenant_id;unique;size:32" json:"tenant_id"`
+	Mode     string `gorm:"column:mode;size:16" json:"mode"`
+	// RequireSignature refuses the images which are not signed by the platform or the public keys
+	RequireSignature bool `gorm:"column:require_signature" json:"require_signature"`
+	// TrustedRegistries is comma separated patterns of the image repositories, such as 'docker.io/library/*,goodrain.me'.
+	// Empty means all the registries are trusted.
+	TrustedRegistries string `gorm:"column:trusted_registries;size:2047" json:"trusted_registries"`
+	// PublicKeys are the PEM encoded cosign public keys which verify the images from the other registries
+	PublicKeys string `gorm:"column:public_keys;type:text" json:"public_keys"`
+}
+
+// TableName -
+func (t *TenantImagePolicy) TableName() string {
+	return "tenant_image_policy"
+}
+
+// TenantMeshTLSPolicy is the mutual tls policy between the mesh connected components of the tenant,
+// or of an application if AppID is not empty.
+type TenantMeshTLSPolicy struct {
+	Model
+	TenantID string `gorm:"column:tenant_id;size:32;unique_index:tenant_app" json:"tenant_id"`
+	// AppID is empty for the policy of the tenant
+	AppID string `gorm:"column:app_id;size:32;unique_index:tenant_app" json:"app_id"`
+	// Mode is disabled, permissive or strict
+	Mode string `gorm:"column:mode;size:16" json:"mode"`
+	// AllowedSources is comma separated identity patterns 'tenant/app/component' allowed to call the components.
+	// Empty means all the identities of the mesh are allowed.
+	AllowedSources string `gorm:"column:allowed_sources;size:2047" json:"allowed_sources"`
+}
+
+// TableName -
+func (t *TenantMeshTLSPolicy) TableName() string {
+	return "tenant_mesh_tls_policy"
+}
+
 // ServiceID -
 type ServiceID struct {
 	ServiceID string `gorm:"column:service_id" json:"-"`
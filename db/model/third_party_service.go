@@ -40,6 +40,12 @@ type DiscorveryType string
 // DiscorveryTypeEtcd etcd
 var DiscorveryTypeEtcd DiscorveryType = "etcd"
 
+// DiscorveryTypeConsul consul
+var DiscorveryTypeConsul DiscorveryType = "consul"
+
+// DiscorveryTypeDNS dns, supports SRV records and A/AAAA records
+var DiscorveryTypeDNS DiscorveryType = "dns"
+
 func (d DiscorveryType) String() string {
 	return string(d)
 }
@@ -51,7 +51,14 @@ type VersionInfo struct {
 	// lost: there is nothing delivered
 	FinalStatus string    `gorm:"column:final_status;size:40" json:"final_status"`
 	FinishTime  time.Time `gorm:"column:finish_time;" json:"finish_time"`
-	PlanVersion string  `gorm:"column:plan_version;size:250" json:"plan_version"`
+	PlanVersion string    `gorm:"column:plan_version;size:250" json:"plan_version"`
+	// ImageDigest is the digest of the image pushed to the registry
+	ImageDigest string `gorm:"column:image_digest;size:100" json:"image_digest"`
+	// Signed means the image is signed by the platform
+	Signed bool `gorm:"column:signed" json:"signed"`
+	// SignerKey is the signer verifying the source image when built from an image, the fingerprint of
+	// a key of the image policy of the tenant or 'platform', it is checked against the policy when deploying
+	SignerKey string `gorm:"column:signer_key;size:100" json:"signer_key"`
 }
 
 //TableName
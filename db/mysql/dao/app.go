@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gridworkz/kato/db/model"
 	"github.com/jinzhu/gorm"
@@ -145,3 +146,169 @@ func (a *AppBackupDaoImpl) GetDeleteAppBackups() ([]*model.AppBackup, error) {
 	}
 	return apps, nil
 }
+
+//ListByScheduleID lists the backups created by the schedule
+func (a *AppBackupDaoImpl) ListByScheduleID(scheduleID string) ([]*model.AppBackup, error) {
+	var apps []*model.AppBackup
+	if err := a.DB.Where("schedule_id = ? and deleted=?", scheduleID, false).Order("create_time desc").Find(&apps).Error; err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+//AppBackupScheduleDaoImpl group app backup schedule store mysql impl
+type AppBackupScheduleDaoImpl struct {
+	DB *gorm.DB
+}
+
+//AddModel
+func (a *AppBackupScheduleDaoImpl) AddModel(mo model.Interface) error {
+	schedule, ok := mo.(*model.AppBackupSchedule)
+	if !ok {
+		return errors.New("Failed to convert interface to AppBackupSchedule")
+	}
+	var old model.AppBackupSchedule
+	if ok := a.DB.Where("schedule_id = ?", schedule.ScheduleID).Find(&old).RecordNotFound(); ok {
+		return a.DB.Create(schedule).Error
+	}
+	return fmt.Errorf("backup schedule exist with id %s", schedule.ScheduleID)
+}
+
+//UpdateModel
+func (a *AppBackupScheduleDaoImpl) UpdateModel(mo model.Interface) error {
+	schedule, ok := mo.(*model.AppBackupSchedule)
+	if !ok {
+		return errors.New("Failed to convert interface to AppBackupSchedule")
+	}
+	return a.DB.Save(schedule).Error
+}
+
+//GetByScheduleID
+func (a *AppBackupScheduleDaoImpl) GetByScheduleID(scheduleID string) (*model.AppBackupSchedule, error) {
+	var schedule model.AppBackupSchedule
+	if err := a.DB.Where("schedule_id = ?", scheduleID).Find(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+//ListByGroupID
+func (a *AppBackupScheduleDaoImpl) ListByGroupID(groupID string) ([]*model.AppBackupSchedule, error) {
+	var schedules []*model.AppBackupSchedule
+	if err := a.DB.Where("group_id = ?", groupID).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+//ListEnableOnes
+func (a *AppBackupScheduleDaoImpl) ListEnableOnes() ([]*model.AppBackupSchedule, error) {
+	var schedules []*model.AppBackupSchedule
+	if err := a.DB.Where("enable = ?", true).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+//DeleteByScheduleID
+func (a *AppBackupScheduleDaoImpl) DeleteByScheduleID(scheduleID string) error {
+	return a.DB.Where("schedule_id = ?", scheduleID).Delete(&model.AppBackupSchedule{}).Error
+}
+
+//UpdateNextTime moves the next time of the schedule from nextTime to newNextTime, it returns false
+//if the next time has been moved, so that a run of the schedule is executed by only one api instance.
+func (a *AppBackupScheduleDaoImpl) UpdateNextTime(scheduleID string, nextTime, newNextTime time.Time) (bool, error) {
+	res := a.DB.Model(&model.AppBackupSchedule{}).
+		Where("schedule_id = ? and next_time = ?", scheduleID, nextTime).
+		Updates(map[string]interface{}{"next_time": newNextTime, "last_time": time.Now()})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+//UpdateLastBackup records the backup created by the last run of the schedule
+func (a *AppBackupScheduleDaoImpl) UpdateLastBackup(scheduleID, backupID, status string) error {
+	fields := lastStatusFields(status)
+	fields["last_backup_id"] = backupID
+	return a.DB.Model(&model.AppBackupSchedule{}).Where("schedule_id = ?", scheduleID).Updates(fields).Error
+}
+
+//UpdateLastStatus updates the status of the last backup, it returns false
+//if the status has been updated, so that a failure is notified only once.
+func (a *AppBackupScheduleDaoImpl) UpdateLastStatus(scheduleID, backupID, status string) (bool, error) {
+	res := a.DB.Model(&model.AppBackupSchedule{}).
+		Where("schedule_id = ? and last_backup_id = ? and last_status <> ?", scheduleID, backupID, status).
+		Updates(lastStatusFields(status))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// lastStatusFields counts the consecutive failures along with the last status.
+func lastStatusFields(status string) map[string]interface{} {
+	fields := map[string]interface{}{"last_status": status}
+	switch status {
+	case "failed":
+		fields["failure_count"] = gorm.Expr("failure_count + 1")
+	case "success":
+		fields["failure_count"] = 0
+	}
+	return fields
+}
+
+//AppBackupVolumeSnapshotDaoImpl the incremental backups of the volumes store mysql impl
+type AppBackupVolumeSnapshotDaoImpl struct {
+	DB *gorm.DB
+}
+
+//AddModel
+func (a *AppBackupVolumeSnapshotDaoImpl) AddModel(mo model.Interface) error {
+	snapshot, ok := mo.(*model.AppBackupVolumeSnapshot)
+	if !ok {
+		return errors.New("Failed to convert interface to AppBackupVolumeSnapshot")
+	}
+	return a.DB.Create(snapshot).Error
+}
+
+//UpdateModel
+func (a *AppBackupVolumeSnapshotDaoImpl) UpdateModel(mo model.Interface) error {
+	snapshot, ok := mo.(*model.AppBackupVolumeSnapshot)
+	if !ok {
+		return errors.New("Failed to convert interface to AppBackupVolumeSnapshot")
+	}
+	return a.DB.Save(snapshot).Error
+}
+
+//ListByGroupID
+func (a *AppBackupVolumeSnapshotDaoImpl) ListByGroupID(groupID string) ([]*model.AppBackupVolumeSnapshot, error) {
+	var snapshots []*model.AppBackupVolumeSnapshot
+	if err := a.DB.Where("group_id = ?", groupID).Order("create_time desc").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+//ListByBackupID
+func (a *AppBackupVolumeSnapshotDaoImpl) ListByBackupID(backupID string) ([]*model.AppBackupVolumeSnapshot, error) {
+	var snapshots []*model.AppBackupVolumeSnapshot
+	if err := a.DB.Where("backup_id = ?", backupID).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+//GetLatest returns the latest backup point of the volume
+func (a *AppBackupVolumeSnapshotDaoImpl) GetLatest(serviceID, volumeName string) (*model.AppBackupVolumeSnapshot, error) {
+	var snapshot model.AppBackupVolumeSnapshot
+	if err := a.DB.Where("service_id = ? and volume_name = ?", serviceID, volumeName).Order("create_time desc").First(&snapshot).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+//DeleteByBackupID
+func (a *AppBackupVolumeSnapshotDaoImpl) DeleteByBackupID(backupID string) error {
+	return a.DB.Where("backup_id = ?", backupID).Delete(&model.AppBackupVolumeSnapshot{}).Error
+}
@@ -1824,3 +1824,223 @@ func (t *TenantServiceScalingRecordsDaoImpl) CountByServiceID(serviceID string)
 
 	return count, nil
 }
+
+// TenantServiceScheduledScalingPolicyDaoImpl -
+type TenantServiceScheduledScalingPolicyDaoImpl struct {
+	DB *gorm.DB
+}
+
+// AddModel -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) AddModel(mo model.Interface) error {
+	policy := mo.(*model.TenantServiceScheduledScalingPolicy)
+	var old model.TenantServiceScheduledScalingPolicy
+	if ok := t.DB.Where("policy_id = ?", policy.PolicyID).Find(&old).RecordNotFound(); ok {
+		return t.DB.Create(policy).Error
+	}
+	return errors.ErrRecordAlreadyExist
+}
+
+// UpdateModel -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) UpdateModel(mo model.Interface) error {
+	policy := mo.(*model.TenantServiceScheduledScalingPolicy)
+	return t.DB.Save(policy).Error
+}
+
+// GetByPolicyID -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) GetByPolicyID(policyID string) (*model.TenantServiceScheduledScalingPolicy, error) {
+	var policy model.TenantServiceScheduledScalingPolicy
+	if err := t.DB.Where("policy_id=?", policyID).Find(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ListByServiceID -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) ListByServiceID(serviceID string) ([]*model.TenantServiceScheduledScalingPolicy, error) {
+	var policies []*model.TenantServiceScheduledScalingPolicy
+	if err := t.DB.Where("service_id=?", serviceID).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// ListEnableOnes -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) ListEnableOnes() ([]*model.TenantServiceScheduledScalingPolicy, error) {
+	var policies []*model.TenantServiceScheduledScalingPolicy
+	if err := t.DB.Where("enable=?", true).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// DeleteByPolicyID -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) DeleteByPolicyID(policyID string) error {
+	return t.DB.Where("policy_id=?", policyID).Delete(&model.TenantServiceScheduledScalingPolicy{}).Error
+}
+
+// DeleteByServiceID -
+func (t *TenantServiceScheduledScalingPolicyDaoImpl) DeleteByServiceID(serviceID string) error {
+	return t.DB.Where("service_id=?", serviceID).Delete(&model.TenantServiceScheduledScalingPolicy{}).Error
+}
+
+// TenantServiceCanaryDaoImpl -
+type TenantServiceCanaryDaoImpl struct {
+	DB *gorm.DB
+}
+
+// AddModel -
+func (t *TenantServiceCanaryDaoImpl) AddModel(mo model.Interface) error {
+	canary := mo.(*model.TenantServiceCanary)
+	var old model.TenantServiceCanary
+	if ok := t.DB.Where("service_id = ?", canary.ServiceID).Find(&old).RecordNotFound(); ok {
+		return t.DB.Create(canary).Error
+	}
+	return errors.ErrRecordAlreadyExist
+}
+
+// UpdateModel -
+func (t *TenantServiceCanaryDaoImpl) UpdateModel(mo model.Interface) error {
+	canary := mo.(*model.TenantServiceCanary)
+	return t.DB.Save(canary).Error
+}
+
+// GetByServiceID -
+func (t *TenantServiceCanaryDaoImpl) GetByServiceID(serviceID string) (*model.TenantServiceCanary, error) {
+	var canary model.TenantServiceCanary
+	if err := t.DB.Where("service_id=?", serviceID).Find(&canary).Error; err != nil {
+		return nil, err
+	}
+	return &canary, nil
+}
+
+// DeleteByServiceID -
+func (t *TenantServiceCanaryDaoImpl) DeleteByServiceID(serviceID string) error {
+	return t.DB.Where("service_id=?", serviceID).Delete(&model.TenantServiceCanary{}).Error
+}
+
+// TenantServiceWebhookDaoImpl -
+type TenantServiceWebhookDaoImpl struct {
+	DB *gorm.DB
+}
+
+// AddModel -
+func (t *TenantServiceWebhookDaoImpl) AddModel(mo model.Interface) error {
+	webhook := mo.(*model.TenantServiceWebhook)
+	var old model.TenantServiceWebhook
+	if ok := t.DB.Where("service_id = ?", webhook.ServiceID).Find(&old).RecordNotFound(); ok {
+		return t.DB.Create(webhook).Error
+	}
+	return errors.ErrRecordAlreadyExist
+}
+
+// UpdateModel -
+func (t *TenantServiceWebhookDaoImpl) UpdateModel(mo model.Interface) error {
+	webhook := mo.(*model.TenantServiceWebhook)
+	return t.DB.Save(webhook).Error
+}
+
+// GetByServiceID -
+func (t *TenantServiceWebhookDaoImpl) GetByServiceID(serviceID string) (*model.TenantServiceWebhook, error) {
+	var webhook model.TenantServiceWebhook
+	if err := t.DB.Where("service_id=?", serviceID).Find(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteByServiceID -
+func (t *TenantServiceWebhookDaoImpl) DeleteByServiceID(serviceID string) error {
+	return t.DB.Where("service_id=?", serviceID).Delete(&model.TenantServiceWebhook{}).Error
+}
+
+// UpdateLastCommit records the commit that triggers a build, it returns false
+// if the commit is the same as the last one, so that a delivery is handled only once.
+func (t *TenantServiceWebhookDaoImpl) UpdateLastCommit(serviceID, commit string) (bool, error) {
+	res := t.DB.Model(&model.TenantServiceWebhook{}).
+		Where("service_id=? and last_commit<>?", serviceID, commit).
+		Update("last_commit", commit)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// TenantImagePolicyDaoImpl -
+type TenantImagePolicyDaoImpl struct {
+	DB *gorm.DB
+}
+
+// AddModel -
+func (t *TenantImagePolicyDaoImpl) AddModel(mo model.Interface) error {
+	policy := mo.(*model.TenantImagePolicy)
+	var old model.TenantImagePolicy
+	if ok := t.DB.Where("tenant_id = ?", policy.TenantID).Find(&old).RecordNotFound(); ok {
+		return t.DB.Create(policy).Error
+	}
+	return errors.ErrRecordAlreadyExist
+}
+
+// UpdateModel -
+func (t *TenantImagePolicyDaoImpl) UpdateModel(mo model.Interface) error {
+	policy := mo.(*model.TenantImagePolicy)
+	return t.DB.Save(policy).Error
+}
+
+// GetByTenantID -
+func (t *TenantImagePolicyDaoImpl) GetByTenantID(tenantID string) (*model.TenantImagePolicy, error) {
+	var policy model.TenantImagePolicy
+	if err := t.DB.Where("tenant_id=?", tenantID).Find(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeleteByTenantID -
+func (t *TenantImagePolicyDaoImpl) DeleteByTenantID(tenantID string) error {
+	return t.DB.Where("tenant_id=?", tenantID).Delete(&model.TenantImagePolicy{}).Error
+}
+
+// TenantMeshTLSPolicyDaoImpl -
+type TenantMeshTLSPolicyDaoImpl struct {
+	DB *gorm.DB
+}
+
+// AddModel -
+func (t *TenantMeshTLSPolicyDaoImpl) AddModel(mo model.Interface) error {
+	policy := mo.(*model.TenantMeshTLSPolicy)
+	var old model.TenantMeshTLSPolicy
+	if ok := t.DB.Where("tenant_id = ? and app_id = ?", policy.TenantID, policy.AppID).Find(&old).RecordNotFound(); ok {
+		return t.DB.Create(policy).Error
+	}
+	return errors.ErrRecordAlreadyExist
+}
+
+// UpdateModel -
+func (t *TenantMeshTLSPolicyDaoImpl) UpdateModel(mo model.Interface) error {
+	policy := mo.(*model.TenantMeshTLSPolicy)
+	return t.DB.Save(policy).Error
+}
+
+// GetByTenantAndApp returns the policy of the application, or the policy of the tenant if appID is empty
+func (t *TenantMeshTLSPolicyDaoImpl) GetByTenantAndApp(tenantID, appID string) (*model.TenantMeshTLSPolicy, error) {
+	var policy model.TenantMeshTLSPolicy
+	if err := t.DB.Where("tenant_id=? and app_id=?", tenantID, appID).Find(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// DeleteByTenantAndApp -
+func (t *TenantMeshTLSPolicyDaoImpl) DeleteByTenantAndApp(tenantID, appID string) error {
+	return t.DB.Where("tenant_id=? and app_id=?", tenantID, appID).Delete(&model.TenantMeshTLSPolicy{}).Error
+}
+
+// DeleteByTenantID deletes the policies of the tenant and of all its applications
+func (t *TenantMeshTLSPolicyDaoImpl) DeleteByTenantID(tenantID string) error {
+	return t.DB.Where("tenant_id=?", tenantID).Delete(&model.TenantMeshTLSPolicy{}).Error
+}
+
+// DeleteByAppID -
+func (t *TenantMeshTLSPolicyDaoImpl) DeleteByAppID(appID string) error {
+	return t.DB.Where("app_id=?", appID).Delete(&model.TenantMeshTLSPolicy{}).Error
+}
@@ -480,6 +480,34 @@ func (m *Manager) AppBackupDaoTransactions(db *gorm.DB) dao.AppBackupDao {
 	}
 }
 
+//AppBackupScheduleDao group app backup schedule
+func (m *Manager) AppBackupScheduleDao() dao.AppBackupScheduleDao {
+	return &mysqldao.AppBackupScheduleDaoImpl{
+		DB: m.db,
+	}
+}
+
+// AppBackupScheduleDaoTransactions
+func (m *Manager) AppBackupScheduleDaoTransactions(db *gorm.DB) dao.AppBackupScheduleDao {
+	return &mysqldao.AppBackupScheduleDaoImpl{
+		DB: db,
+	}
+}
+
+//AppBackupVolumeSnapshotDao the incremental backups of the volumes
+func (m *Manager) AppBackupVolumeSnapshotDao() dao.AppBackupVolumeSnapshotDao {
+	return &mysqldao.AppBackupVolumeSnapshotDaoImpl{
+		DB: m.db,
+	}
+}
+
+// AppBackupVolumeSnapshotDaoTransactions
+func (m *Manager) AppBackupVolumeSnapshotDaoTransactions(db *gorm.DB) dao.AppBackupVolumeSnapshotDao {
+	return &mysqldao.AppBackupVolumeSnapshotDaoImpl{
+		DB: db,
+	}
+}
+
 //ServiceSourceDao
 func (m *Manager) ServiceSourceDao() dao.ServiceSourceDao {
 	return &mysqldao.ServiceSourceImpl{
@@ -627,6 +655,76 @@ func (m *Manager) TenantServiceScalingRecordsDaoTransactions(db *gorm.DB) dao.Te
 	}
 }
 
+// TenantServiceScheduledScalingPolicyDao
+func (m *Manager) TenantServiceScheduledScalingPolicyDao() dao.TenantServiceScheduledScalingPolicyDao {
+	return &mysqldao.TenantServiceScheduledScalingPolicyDaoImpl{
+		DB: m.db,
+	}
+}
+
+// TenantServiceScheduledScalingPolicyDaoTransactions
+func (m *Manager) TenantServiceScheduledScalingPolicyDaoTransactions(db *gorm.DB) dao.TenantServiceScheduledScalingPolicyDao {
+	return &mysqldao.TenantServiceScheduledScalingPolicyDaoImpl{
+		DB: db,
+	}
+}
+
+// TenantServiceCanaryDao
+func (m *Manager) TenantServiceCanaryDao() dao.TenantServiceCanaryDao {
+	return &mysqldao.TenantServiceCanaryDaoImpl{
+		DB: m.db,
+	}
+}
+
+// TenantServiceCanaryDaoTransactions
+func (m *Manager) TenantServiceCanaryDaoTransactions(db *gorm.DB) dao.TenantServiceCanaryDao {
+	return &mysqldao.TenantServiceCanaryDaoImpl{
+		DB: db,
+	}
+}
+
+// TenantServiceWebhookDao
+func (m *Manager) TenantServiceWebhookDao() dao.TenantServiceWebhookDao {
+	return &mysqldao.TenantServiceWebhookDaoImpl{
+		DB: m.db,
+	}
+}
+
+// TenantServiceWebhookDaoTransactions
+func (m *Manager) TenantServiceWebhookDaoTransactions(db *gorm.DB) dao.TenantServiceWebhookDao {
+	return &mysqldao.TenantServiceWebhookDaoImpl{
+		DB: db,
+	}
+}
+
+// TenantImagePolicyDao
+func (m *Manager) TenantImagePolicyDao() dao.TenantImagePolicyDao {
+	return &mysqldao.TenantImagePolicyDaoImpl{
+		DB: m.db,
+	}
+}
+
+// TenantImagePolicyDaoTransactions
+func (m *Manager) TenantImagePolicyDaoTransactions(db *gorm.DB) dao.TenantImagePolicyDao {
+	return &mysqldao.TenantImagePolicyDaoImpl{
+		DB: db,
+	}
+}
+
+// TenantMeshTLSPolicyDao
+func (m *Manager) TenantMeshTLSPolicyDao() dao.TenantMeshTLSPolicyDao {
+	return &mysqldao.TenantMeshTLSPolicyDaoImpl{
+		DB: m.db,
+	}
+}
+
+// TenantMeshTLSPolicyDaoTransactions
+func (m *Manager) TenantMeshTLSPolicyDaoTransactions(db *gorm.DB) dao.TenantMeshTLSPolicyDao {
+	return &mysqldao.TenantMeshTLSPolicyDaoImpl{
+		DB: db,
+	}
+}
+
 //TenantServiceMonitorDao
 func (m *Manager) TenantServiceMonitorDao() dao.TenantServiceMonitorDao {
 	return &mysqldao.TenantServiceMonitorDaoImpl{
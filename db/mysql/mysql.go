@@ -132,6 +132,8 @@ func (m *Manager) RegisterTableModel() {
 	m.models = append(m.models, &model.NotificationEvent{})
 	m.models = append(m.models, &model.AppStatus{})
 	m.models = append(m.models, &model.AppBackup{})
+	m.models = append(m.models, &model.AppBackupSchedule{})
+	m.models = append(m.models, &model.AppBackupVolumeSnapshot{})
 	m.models = append(m.models, &model.ServiceSourceConfig{})
 	m.models = append(m.models, &model.Application{})
 	m.models = append(m.models, &model.ApplicationConfigGroup{})
@@ -153,6 +155,11 @@ func (m *Manager) RegisterTableModel() {
 	m.models = append(m.models, &model.TenantServiceAutoscalerRules{})
 	m.models = append(m.models, &model.TenantServiceAutoscalerRuleMetrics{})
 	m.models = append(m.models, &model.TenantServiceScalingRecords{})
+	m.models = append(m.models, &model.TenantServiceScheduledScalingPolicy{})
+	m.models = append(m.models, &model.TenantServiceCanary{})
+	m.models = append(m.models, &model.TenantServiceWebhook{})
+	m.models = append(m.models, &model.TenantImagePolicy{})
+	m.models = append(m.models, &model.TenantMeshTLSPolicy{})
 	m.models = append(m.models, &model.TenantServiceMonitor{})
 }
 
@@ -19,12 +19,16 @@
 package annotations
 
 import (
+	"github.com/gridworkz/kato/gateway/annotations/auth"
+	"github.com/gridworkz/kato/gateway/annotations/authreq"
 	"github.com/gridworkz/kato/gateway/annotations/cookie"
 	"github.com/gridworkz/kato/gateway/annotations/header"
+	"github.com/gridworkz/kato/gateway/annotations/ipaccess"
 	"github.com/gridworkz/kato/gateway/annotations/l4"
 	"github.com/gridworkz/kato/gateway/annotations/lbtype"
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/gateway/annotations/proxy"
+	"github.com/gridworkz/kato/gateway/annotations/ratelimit"
 	"github.com/gridworkz/kato/gateway/annotations/resolver"
 	"github.com/gridworkz/kato/gateway/annotations/rewrite"
 	"github.com/gridworkz/kato/gateway/annotations/upstreamhashby"
@@ -32,7 +36,7 @@ import (
 	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
 	"github.com/imdario/mergo"
 	"github.com/sirupsen/logrus"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -50,6 +54,12 @@ type Ingress struct {
 	UpstreamHashBy    string
 	LoadBalancingType string
 	Proxy             proxy.Config
+	RateLimit         ratelimit.Config
+	IPAccess          ipaccess.Config
+	BasicAuth         auth.Config
+	ForwardAuth       authreq.Config
+	// Denied is the reason why the location should be denied, e.g. an invalid auth
+	Denied error
 }
 
 // Extractor defines the annotation parsers to be used in the extraction of annotations
@@ -69,12 +79,16 @@ func NewAnnotationExtractor(cfg resolver.Resolver) Extractor {
 			"UpstreamHashBy":    upstreamhashby.NewParser(cfg),
 			"LoadBalancingType": lbtype.NewParser(cfg),
 			"Proxy":             proxy.NewParser(cfg),
+			"RateLimit":         ratelimit.NewParser(cfg),
+			"IPAccess":          ipaccess.NewParser(cfg),
+			"BasicAuth":         auth.NewParser(cfg),
+			"ForwardAuth":       authreq.NewParser(cfg),
 		},
 	}
 }
 
 // Extract extracts the annotations from an Ingress
-func (e Extractor) Extract(ing *extensions.Ingress) *Ingress {
+func (e Extractor) Extract(ing *networkingv1.Ingress) *Ingress {
 	pia := &Ingress{
 		ObjectMeta: ing.ObjectMeta,
 	}
@@ -106,6 +120,11 @@ func (e Extractor) Extract(ing *extensions.Ingress) *Ingress {
 		}
 	}
 
+	if denied, ok := data[DeniedKeyName]; ok {
+		pia.Denied = denied.(error)
+		delete(data, DeniedKeyName)
+	}
+
 	err := mergo.MapWithOverwrite(pia, data)
 	if err != nil {
 		logrus.Errorf("unexpected error merging extracted annotations: %v", err)
@@ -0,0 +1,95 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/gateway/annotations/resolver"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	// TypeBasic is the only supported auth type
+	TypeBasic = "basic"
+	// DefaultRealm is the realm of the basic auth if not specified
+	DefaultRealm = "Authentication Required"
+	// SecretKey is the key of the htpasswd content in the secret
+	SecretKey = "auth"
+)
+
+// Config describes the basic auth of a location
+type Config struct {
+	Type  string `json:"type"`
+	Realm string `json:"realm"`
+	// Secret is the key(namespace/name) of the secret which contains the htpasswd users
+	Secret string `json:"secret"`
+	// File is the htpasswd file written from the secret
+	File string `json:"file"`
+}
+
+// Equal tests for equality between two Config types
+func (c *Config) Equal(c2 *Config) bool {
+	if c == c2 {
+		return true
+	}
+	if c == nil || c2 == nil {
+		return false
+	}
+	return c.Type == c2.Type && c.Realm == c2.Realm && c.Secret == c2.Secret && c.File == c2.File
+}
+
+type auth struct {
+	r resolver.Resolver
+}
+
+// NewParser creates a new basic auth annotation parser
+func NewParser(r resolver.Resolver) parser.IngressAnnotation {
+	return auth{r}
+}
+
+// Parse parses the annotations contained in the ingress to configure the basic auth.
+// The secret must be in the same namespace as the ingress.
+func (a auth) Parse(ing *networkingv1.Ingress) (interface{}, error) {
+	typ, err := parser.GetStringAnnotation("auth-type", ing)
+	if err != nil {
+		return nil, err
+	}
+	// deny the location rather than exposing it without auth
+	if typ != TypeBasic {
+		return nil, errors.NewLocationDenied(fmt.Sprintf("unsupported auth type %s", typ))
+	}
+	secret, err := parser.GetStringAnnotation("auth-secret", ing)
+	if err != nil || secret == "" {
+		return nil, errors.NewLocationDenied("auth secret is required")
+	}
+	realm, err := parser.GetStringAnnotation("auth-realm", ing)
+	// the realm is quoted in the nginx configuration
+	realm = strings.NewReplacer(`"`, "", `\`, "").Replace(realm)
+	if err != nil || realm == "" {
+		realm = DefaultRealm
+	}
+	return &Config{
+		Type:   typ,
+		Realm:  realm,
+		Secret: ing.Namespace + "/" + secret,
+	}, nil
+}
@@ -0,0 +1,76 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package auth
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	api "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildIngress(annotations map[string]string) *networkingv1.Ingress {
+	data := map[string]string{}
+	for k, v := range annotations {
+		data[parser.GetAnnotationWithPrefix(k)] = v
+	}
+	return &networkingv1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   api.NamespaceDefault,
+			Annotations: data,
+		},
+	}
+}
+
+func TestAuth_Parse(t *testing.T) {
+	ing := buildIngress(map[string]string{
+		"auth-type":   "basic",
+		"auth-secret": "admin-users",
+		"auth-realm":  `Admin "UI"`,
+	})
+	i, err := NewParser(auth{}).Parse(ing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Config{Type: TypeBasic, Realm: "Admin UI", Secret: "default/admin-users"}
+	if cfg := i.(*Config); !cfg.Equal(want) {
+		t.Errorf("expected %+v but got %+v", want, cfg)
+	}
+
+	ing = buildIngress(map[string]string{"auth-type": "basic", "auth-secret": "admin-users"})
+	i, _ = NewParser(auth{}).Parse(ing)
+	if realm := i.(*Config).Realm; realm != DefaultRealm {
+		t.Errorf("expected realm %s but got %s", DefaultRealm, realm)
+	}
+}
+
+func TestAuth_ParseDenied(t *testing.T) {
+	for _, annotations := range []map[string]string{
+		{"auth-type": "digest", "auth-secret": "admin-users"},
+		{"auth-type": "basic"},
+	} {
+		_, err := NewParser(auth{}).Parse(buildIngress(annotations))
+		if !errors.IsLocationDenied(err) {
+			t.Errorf("expected location denied for %v but got %v", annotations, err)
+		}
+	}
+}
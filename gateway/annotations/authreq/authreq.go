@@ -0,0 +1,86 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package authreq
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/gateway/annotations/resolver"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Config describes the external service which authorizes the requests of a location
+type Config struct {
+	URL string `json:"url"`
+}
+
+// Equal tests for equality between two Config types
+func (c *Config) Equal(c2 *Config) bool {
+	if c == c2 {
+		return true
+	}
+	if c == nil || c2 == nil {
+		return false
+	}
+	return c.URL == c2.URL
+}
+
+// ValidateURL checks if the forward auth url is an absolute http(s) url
+func ValidateURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("forward auth url %s should start with http:// or https://", u)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("forward auth url %s has no host", u)
+	}
+	// the url is rendered into the nginx configuration
+	if strings.ContainsAny(u, " \t\r\n;\"'{}") {
+		return fmt.Errorf("forward auth url %s contains invalid characters", u)
+	}
+	return nil
+}
+
+type authreq struct {
+	r resolver.Resolver
+}
+
+// NewParser creates a new forward auth annotation parser
+func NewParser(r resolver.Resolver) parser.IngressAnnotation {
+	return authreq{r}
+}
+
+// Parse parses the annotations contained in the ingress to configure the forward auth
+func (a authreq) Parse(ing *networkingv1.Ingress) (interface{}, error) {
+	u, err := parser.GetStringAnnotation("auth-url", ing)
+	if err != nil {
+		return nil, err
+	}
+	// deny the location rather than exposing it without auth
+	if err := ValidateURL(u); err != nil {
+		return nil, errors.NewLocationDenied(err.Error())
+	}
+	return &Config{URL: u}, nil
+}
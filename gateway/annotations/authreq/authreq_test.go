@@ -0,0 +1,57 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package authreq
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	api "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildIngress(url string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: api.NamespaceDefault,
+			Annotations: map[string]string{
+				parser.GetAnnotationWithPrefix("auth-url"): url,
+			},
+		},
+	}
+}
+
+func TestAuthReq_Parse(t *testing.T) {
+	i, err := NewParser(authreq{}).Parse(buildIngress("http://auth.default.svc.cluster.local/verify"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u := i.(*Config).URL; u != "http://auth.default.svc.cluster.local/verify" {
+		t.Errorf("unexpected url %s", u)
+	}
+
+	for _, u := range []string{"auth/verify", "ftp://auth/verify", "http://auth/verify;return 200"} {
+		_, err := NewParser(authreq{}).Parse(buildIngress(u))
+		if !errors.IsLocationDenied(err) {
+			t.Errorf("expected location denied for %s but got %v", u, err)
+		}
+	}
+}
@@ -21,7 +21,7 @@ package cookie
 import (
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/gateway/annotations/resolver"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"strings"
 )
 
@@ -37,7 +37,7 @@ func NewParser(r resolver.Resolver) parser.IngressAnnotation {
 	return cookie{r}
 }
 
-func (c cookie) Parse(ing *extensions.Ingress) (interface{}, error) {
+func (c cookie) Parse(ing *networkingv1.Ingress) (interface{}, error) {
 	co, err := parser.GetStringAnnotation("cookie", ing)
 	if err != nil {
 		return nil, err
@@ -21,7 +21,7 @@ package header
 import (
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/gateway/annotations/resolver"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"strings"
 )
 
@@ -37,7 +37,7 @@ func NewParser(r resolver.Resolver) parser.IngressAnnotation {
 	return header{r}
 }
 
-func (h header) Parse(ing *extensions.Ingress) (interface{}, error) {
+func (h header) Parse(ing *networkingv1.Ingress) (interface{}, error) {
 	hr, err := parser.GetStringAnnotation("header", ing)
 	if err != nil {
 		return nil, err
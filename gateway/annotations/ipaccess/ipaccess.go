@@ -0,0 +1,110 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package ipaccess
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/gateway/annotations/resolver"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// Config contains the source ranges allowed or denied to access a location
+type Config struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// Equal tests for equality between two Config types
+func (c *Config) Equal(c2 *Config) bool {
+	if c == c2 {
+		return true
+	}
+	if c == nil || c2 == nil {
+		return false
+	}
+	return equalStrings(c.Allow, c2.Allow) && equalStrings(c.Deny, c2.Deny)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSourceRange parses comma separated ips or CIDRs
+func ParseSourceRange(val string) (valid []string, invalid []string) {
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(item); err == nil || net.ParseIP(item) != nil {
+			valid = append(valid, item)
+			continue
+		}
+		invalid = append(invalid, item)
+	}
+	return
+}
+
+type ipaccess struct {
+	r resolver.Resolver
+}
+
+// NewParser creates a new ip access annotation parser
+func NewParser(r resolver.Resolver) parser.IngressAnnotation {
+	return ipaccess{r}
+}
+
+// Parse parses the annotations contained in the ingress to restrict the access to the location by source ip.
+// An invalid allow list denies all clients rather than exposing the location.
+func (a ipaccess) Parse(ing *networkingv1.Ingress) (interface{}, error) {
+	config := &Config{}
+	if val, err := parser.GetStringAnnotation("denylist-source-range", ing); err == nil {
+		var invalid []string
+		config.Deny, invalid = ParseSourceRange(val)
+		if len(invalid) > 0 {
+			logrus.Warnf("ingress %s/%s: ignore invalid deny source ranges %v", ing.Namespace, ing.Name, invalid)
+		}
+	}
+	if val, err := parser.GetStringAnnotation("whitelist-source-range", ing); err == nil && strings.TrimSpace(val) != "" {
+		var invalid []string
+		config.Allow, invalid = ParseSourceRange(val)
+		if len(invalid) > 0 {
+			logrus.Warnf("ingress %s/%s: ignore invalid allow source ranges %v", ing.Namespace, ing.Name, invalid)
+		}
+		if len(config.Allow) == 0 {
+			config.Deny = append(config.Deny, "all")
+		}
+	}
+	if len(config.Allow) == 0 && len(config.Deny) == 0 {
+		return nil, errors.ErrMissingAnnotations
+	}
+	return config, nil
+}
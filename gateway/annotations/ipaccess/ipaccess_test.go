@@ -0,0 +1,91 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package ipaccess
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	api "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildIngress(annotations map[string]string) *networkingv1.Ingress {
+	data := map[string]string{}
+	for k, v := range annotations {
+		data[parser.GetAnnotationWithPrefix(k)] = v
+	}
+	return &networkingv1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   api.NamespaceDefault,
+			Annotations: data,
+		},
+	}
+}
+
+func TestIPAccess_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *Config
+	}{
+		{
+			name: "allow and deny",
+			annotations: map[string]string{
+				"whitelist-source-range": "10.0.0.0/8, 192.168.1.1",
+				"denylist-source-range":  "10.1.0.0/16",
+			},
+			want: &Config{Allow: []string{"10.0.0.0/8", "192.168.1.1"}, Deny: []string{"10.1.0.0/16"}},
+		},
+		{
+			name: "ignore invalid entries",
+			annotations: map[string]string{
+				"whitelist-source-range": "10.0.0.0/8,foo,2001:db8::/32",
+			},
+			want: &Config{Allow: []string{"10.0.0.0/8", "2001:db8::/32"}},
+		},
+		{
+			name: "invalid allow list denies all",
+			annotations: map[string]string{
+				"whitelist-source-range": "foo",
+			},
+			want: &Config{Deny: []string{"all"}},
+		},
+	}
+	for _, tc := range tests {
+		i, err := NewParser(ipaccess{}).Parse(buildIngress(tc.annotations))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		cfg, ok := i.(*Config)
+		if !ok {
+			t.Errorf("%s: expected a Config type", tc.name)
+			continue
+		}
+		if !cfg.Equal(tc.want) {
+			t.Errorf("%s: expected %+v but got %+v", tc.name, tc.want, cfg)
+		}
+	}
+
+	if _, err := NewParser(ipaccess{}).Parse(buildIngress(nil)); err == nil {
+		t.Errorf("expected an error for the ingress without annotations")
+	}
+}
@@ -22,7 +22,7 @@ import (
 	"fmt"
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/gateway/annotations/resolver"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 type Config struct {
@@ -39,7 +39,7 @@ func NewParser(r resolver.Resolver) parser.IngressAnnotation {
 	return l4{r}
 }
 
-func (l l4) Parse(ing *extensions.Ingress) (interface{}, error) {
+func (l l4) Parse(ing *networkingv1.Ingress) (interface{}, error) {
 	l4Enable, _ := parser.GetBoolAnnotation("l4-enable", ing)
 	l4Host, _ := parser.GetStringAnnotation("l4-host", ing)
 	if l4Host == "" {
@@ -21,7 +21,7 @@ package lbtype
 import (
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/gateway/annotations/resolver"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 type lbtype struct {
@@ -36,6 +36,6 @@ func NewParser(r resolver.Resolver) parser.IngressAnnotation {
 // Parse parses the annotations contained in the ingress rule
 // used to indicate if the location/s contains a fragment of
 // configuration to be included inside the paths of the rules
-func (a lbtype) Parse(ing *extensions.Ingress) (interface{}, error) {
+func (a lbtype) Parse(ing *networkingv1.Ingress) (interface{}, error) {
 	return parser.GetStringAnnotation("lb-type", ing)
 }
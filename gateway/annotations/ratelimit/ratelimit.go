@@ -0,0 +1,128 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/gateway/annotations/resolver"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpguts"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	// KeyIP limits clients by their remote address
+	KeyIP = "ip"
+	// KeyHeaderPrefix limits clients by the value of a request header, e.g. header:X-Api-Key
+	KeyHeaderPrefix = "header:"
+)
+
+// Config describes the rate and connection limits of a location
+type Config struct {
+	// RPS is the number of requests per second allowed for a client, 0 means unlimited
+	RPS int `json:"rps"`
+	// Burst is the number of requests exceeding RPS that are delayed rather than rejected
+	Burst int `json:"burst"`
+	// Key identifies a client, KeyIP or KeyHeaderPrefix followed by a header name
+	Key string `json:"key"`
+	// Connections is the number of concurrent connections allowed for a client, 0 means unlimited
+	Connections int `json:"connections"`
+}
+
+// Enabled returns whether any limit is configured
+func (c *Config) Enabled() bool {
+	return c.RPS > 0 || c.Connections > 0
+}
+
+// Header returns the header name used as the limit key,
+// or an empty string if clients are identified by ip
+func (c *Config) Header() string {
+	if strings.HasPrefix(c.Key, KeyHeaderPrefix) {
+		return strings.TrimPrefix(c.Key, KeyHeaderPrefix)
+	}
+	return ""
+}
+
+// Equal tests for equality between two Config types
+func (c *Config) Equal(c2 *Config) bool {
+	if c == c2 {
+		return true
+	}
+	if c == nil || c2 == nil {
+		return false
+	}
+	return c.RPS == c2.RPS && c.Burst == c2.Burst && c.Key == c2.Key && c.Connections == c2.Connections
+}
+
+// ValidateKey checks if the key is KeyIP or KeyHeaderPrefix followed by a valid header name
+func ValidateKey(key string) error {
+	if key == "" || key == KeyIP {
+		return nil
+	}
+	if strings.HasPrefix(key, KeyHeaderPrefix) && httpguts.ValidHeaderFieldName(strings.TrimPrefix(key, KeyHeaderPrefix)) {
+		return nil
+	}
+	return fmt.Errorf("limit key %s is invalid, should be %s or %s<header name>", key, KeyIP, KeyHeaderPrefix)
+}
+
+type ratelimit struct {
+	r resolver.Resolver
+}
+
+// NewParser creates a new rate limit annotation parser
+func NewParser(r resolver.Resolver) parser.IngressAnnotation {
+	return ratelimit{r}
+}
+
+// Parse parses the annotations contained in the ingress to configure the rate and connection limits
+func (r ratelimit) Parse(ing *networkingv1.Ingress) (interface{}, error) {
+	config := &Config{Key: KeyIP}
+	config.RPS = nonNegativeInt("limit-rps", ing)
+	config.Burst = nonNegativeInt("limit-burst", ing)
+	config.Connections = nonNegativeInt("limit-connections", ing)
+	if !config.Enabled() {
+		return nil, errors.ErrMissingAnnotations
+	}
+	if key, err := parser.GetStringAnnotation("limit-key", ing); err == nil && key != "" {
+		if err := ValidateKey(key); err != nil {
+			logrus.Warnf("ingress %s/%s: %v, fall back to %s", ing.Namespace, ing.Name, err, KeyIP)
+		} else {
+			config.Key = key
+		}
+	}
+	return config, nil
+}
+
+func nonNegativeInt(name string, ing *networkingv1.Ingress) int {
+	val, err := parser.GetIntAnnotation(name, ing)
+	if err != nil {
+		if !errors.IsMissingAnnotations(err) {
+			logrus.Warnf("ingress %s/%s: invalid annotation %s: %v", ing.Namespace, ing.Name, name, err)
+		}
+		return 0
+	}
+	if val < 0 {
+		logrus.Warnf("ingress %s/%s: annotation %s can not be negative: %d", ing.Namespace, ing.Name, name, val)
+		return 0
+	}
+	return val
+}
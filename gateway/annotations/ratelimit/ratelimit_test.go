@@ -0,0 +1,112 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/gridworkz/kato/util/ingress-nginx/ingress/errors"
+	api "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildIngress() *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: api.NamespaceDefault,
+		},
+	}
+}
+
+func TestRateLimit_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *Config
+	}{
+		{
+			name: "rps and burst",
+			annotations: map[string]string{
+				"limit-rps":   "10",
+				"limit-burst": "5",
+			},
+			want: &Config{RPS: 10, Burst: 5, Key: KeyIP},
+		},
+		{
+			name: "connections by header",
+			annotations: map[string]string{
+				"limit-connections": "3",
+				"limit-key":         "header:X-Api-Key",
+			},
+			want: &Config{Connections: 3, Key: "header:X-Api-Key"},
+		},
+		{
+			name: "invalid key and negative burst",
+			annotations: map[string]string{
+				"limit-rps":   "1",
+				"limit-burst": "-1",
+				"limit-key":   "header:X Api",
+			},
+			want: &Config{RPS: 1, Key: KeyIP},
+		},
+	}
+	for _, tc := range tests {
+		ing := buildIngress()
+		data := map[string]string{}
+		for k, v := range tc.annotations {
+			data[parser.GetAnnotationWithPrefix(k)] = v
+		}
+		ing.SetAnnotations(data)
+		i, err := NewParser(ratelimit{}).Parse(ing)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		cfg, ok := i.(*Config)
+		if !ok {
+			t.Errorf("%s: expected a Config type", tc.name)
+			continue
+		}
+		if !cfg.Equal(tc.want) {
+			t.Errorf("%s: expected %+v but got %+v", tc.name, tc.want, cfg)
+		}
+	}
+}
+
+func TestRateLimit_ParseWithoutLimits(t *testing.T) {
+	ing := buildIngress()
+	ing.SetAnnotations(map[string]string{
+		parser.GetAnnotationWithPrefix("limit-key"): "header:X-Api-Key",
+	})
+	_, err := NewParser(ratelimit{}).Parse(ing)
+	if !errors.IsMissingAnnotations(err) {
+		t.Errorf("expected missing annotations error but got %v", err)
+	}
+}
+
+func TestConfig_Header(t *testing.T) {
+	if h := (&Config{Key: "header:X-Api-Key"}).Header(); h != "X-Api-Key" {
+		t.Errorf("expected X-Api-Key but got %s", h)
+	}
+	if h := (&Config{Key: KeyIP}).Header(); h != "" {
+		t.Errorf("expected empty header but got %s", h)
+	}
+}
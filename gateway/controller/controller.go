@@ -36,6 +36,7 @@ import (
 	"github.com/gridworkz/kato/util/ingress-nginx/task"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/flowcontrol"
 )
@@ -178,7 +179,7 @@ func (gwc *GWController) syncGateway(key interface{}) error {
 }
 
 //NewGWController new Gateway controller
-func NewGWController(ctx context.Context, clientset kubernetes.Interface, cfg *option.Config, mc metric.Collector, node *cluster.NodeManager) (*GWController, error) {
+func NewGWController(ctx context.Context, clientset kubernetes.Interface, dynamicClient dynamic.Interface, cfg *option.Config, mc metric.Collector, node *cluster.NodeManager) (*GWController, error) {
 	gwc := &GWController{
 		updateCh:        channels.NewRingChannel(1024),
 		syncRateLimiter: flowcontrol.NewTokenBucketRateLimiter(cfg.SyncRateLimit, 1),
@@ -193,6 +194,7 @@ func NewGWController(ctx context.Context, clientset kubernetes.Interface, cfg *o
 
 	gwc.store = store.New(
 		clientset,
+		dynamicClient,
 		gwc.updateCh,
 		cfg, node)
 	gwc.syncQueue = task.NewTaskQueue(gwc.syncGateway)
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gridworkz/kato/gateway/annotations/auth"
+	"github.com/gridworkz/kato/gateway/annotations/authreq"
+	"github.com/gridworkz/kato/gateway/annotations/ipaccess"
 	"github.com/gridworkz/kato/gateway/annotations/proxy"
+	"github.com/gridworkz/kato/gateway/annotations/ratelimit"
 	"github.com/gridworkz/kato/gateway/annotations/rewrite"
 	v1 "github.com/gridworkz/kato/gateway/v1"
 )
@@ -122,6 +126,19 @@ type Location struct {
 	// to be used in connections against endpoints
 	// +optional
 	Proxy proxy.Config `json:"proxy,omitempty"`
+
+	// RateLimit limits the request rate and concurrent connections of clients
+	RateLimit ratelimit.Config `json:"rateLimit,omitempty"`
+	// LimitZone prefixes the limit keys of this location in the shared dicts
+	LimitZone string
+
+	IPAccess    ipaccess.Config
+	BasicAuth   auth.Config
+	ForwardAuth authreq.Config
+	// AuthLocation is the internal location which proxies the subrequests to ForwardAuth.URL
+	AuthLocation string
+	// Resolver is used to resolve the host of ForwardAuth.URL
+	Resolver string
 }
 
 //Validation validation nginx parameters
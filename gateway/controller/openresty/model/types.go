@@ -77,6 +77,8 @@ type Endpoint struct {
 	Port string `json:"port"`
 	// Weight weight of the endpoint
 	Weight int `json:"weight"`
+	// Version the deploy version of the service which the endpoint belongs to
+	Version string `json:"version,omitempty"`
 	// Target returns a reference to the object providing the endpoint
 	Target *apiv1.ObjectReference `json:"target,omitempty"`
 }
@@ -105,6 +107,7 @@ func CreateBackendByPool(pool *v1.Pool) *Backend {
 			Address: node.Host,
 			Port:    strconv.Itoa(int(node.Port)),
 			Weight:  node.Weight,
+			Version: node.Version,
 		})
 	}
 	backend.Endpoints = endpoints
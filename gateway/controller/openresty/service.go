@@ -22,6 +22,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
@@ -223,17 +225,30 @@ func (o *OrService) getNgxServer(conf *v1.Config) (l7srv []*model.Server, l4srv
 
 		}
 		for _, loc := range vs.Locations {
+			id := locationID(server.Listen, server.ServerName, loc.Path)
 			location := &model.Location{
 				DisableAccessLog: o.ocfg.AccessLogPath == "",
 				// TODO: Distinguish between server output logs
-				AccessLogPath:                  o.ocfg.AccessLogPath,
-				EnableMetrics:                  true,
-				Path:                           loc.Path,
-				NameCondition:                  loc.NameCondition,
-				Proxy:                          loc.Proxy,
-				Rewrite:                        loc.Rewrite,
-				PathRewrite:                    false,
-				DisableProxyPass:               loc.DisableProxyPass,
+				AccessLogPath:    o.ocfg.AccessLogPath,
+				EnableMetrics:    true,
+				Path:             loc.Path,
+				NameCondition:    loc.NameCondition,
+				Proxy:            loc.Proxy,
+				Rewrite:          loc.Rewrite,
+				PathRewrite:      false,
+				DisableProxyPass: loc.DisableProxyPass,
+				RateLimit:        loc.RateLimit,
+				LimitZone:        id,
+				IPAccess:         loc.IPAccess,
+				BasicAuth:        loc.BasicAuth,
+				ForwardAuth:      loc.ForwardAuth,
+			}
+			if loc.ForwardAuth.URL != "" {
+				location.AuthLocation = "/.kato-auth-" + id
+				location.Resolver = systemResolver()
+			}
+			if loc.Denied {
+				location.Return = model.Return{Code: 503}
 			}
 			server.Locations = append(server.Locations, location)
 		}
@@ -259,6 +274,41 @@ func (o *OrService) getNgxServer(conf *v1.Config) (l7srv []*model.Server, l4srv
 	return l7srv, l4srv
 }
 
+// locationID returns an identifier of the location which is unique across servers,
+// e.g. clients are limited separately in each location.
+func locationID(listen, serverName, path string) string {
+	h := fnv.New32a()
+	h.Write([]byte(listen + " " + serverName + " " + path))
+	return fmt.Sprintf("%x", h.Sum32())
+}
+
+// systemResolver returns the name servers in /etc/resolv.conf in the format of nginx resolver.
+// Note that nginx does not use the search domains, the host name should be fully qualified.
+func systemResolver() string {
+	content, err := ioutil.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		logrus.Warningf("read /etc/resolv.conf: %v", err)
+		return ""
+	}
+	var nameservers []string
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		ip := net.ParseIP(fields[1])
+		if ip == nil {
+			continue
+		}
+		if ip.To4() == nil {
+			nameservers = append(nameservers, "["+ip.String()+"]")
+			continue
+		}
+		nameservers = append(nameservers, ip.String())
+	}
+	return strings.Join(nameservers, " ")
+}
+
 // UpdatePools updates http upstreams dynamically.
 func (o *OrService) UpdatePools(hpools []*v1.Pool, tpools []*v1.Pool) error {
 	var lock sync.Mutex
@@ -21,6 +21,7 @@ package template
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	text_template "text/template"
 
@@ -58,63 +59,35 @@ func buildLuaHeaderRouter(input interface{}) string {
 	}
 	_ = loc
 	out := []string{"access_by_lua_block {"}
+	if loc.RateLimit.Enabled() {
+		out = append(out, buildLuaRateLimit(loc))
+	}
+	if loc.AuthLocation != "" {
+		out = append(out, fmt.Sprintf("\t\t\tauth.forward(%q)", loc.AuthLocation))
+	}
 
+	// the conditions are evaluated in order, so header conditions take precedence over
+	// cookie conditions, which take precedence over the default backend. A request that
+	// does not match any condition is routed to the default backend if there is one.
 	priority := make([]string, 3)
+	hasDefault := false
 	for name, c := range loc.NameCondition {
 		switch c.Type {
 		case v1.HeaderType:
-			snippet := []string{}
-			cond1 := []string{}
-			cond2 := []string{}
+			var condition []string
 			for key, val := range c.Value {
-				snippet = append(snippet, fmt.Sprintf("\t\t\tlocal %s = ngx.var.http_%s", key, key))
-				cond1 = append(cond1, key)
-				cond2 = append(cond2, fmt.Sprintf("%s == \"%s\"", key, val))
+				condition = append(condition, fmt.Sprintf("ngx.var[%q] == %q", headerVariable(key), val))
 			}
-			snippet = append(snippet, fmt.Sprintf("\t\t\tif %s then", strings.Join(cond1, " and ")))
-			snippet = append(snippet, fmt.Sprintf("\t\t\t\tif %s then", strings.Join(cond2, " and ")))
-			snippet = append(snippet, fmt.Sprintf("\t\t\t\t\tngx.var.target = \"%s\"", name))
-			snippet = append(snippet, "\t\t\t\t\telse")
-			snippet = append(snippet, "\t\t\t\t\t\t\tngx.exit(404)")
-			snippet = append(snippet, "\t\t\t\tend")
-			snippet = append(snippet, "\t\t\telseif ngx.var.target == 'default' then")
-			snippet = append(snippet, "\t\t\t\tngx.exit(404)")
-			snippet = append(snippet, "\t\t\tend")
-			priority[2] = strings.Join(snippet, "\n\r")
+			priority[2] = luaTarget(name, condition)
 		case v1.CookieType:
-			var snippet []string
-			snippet = append(snippet, `
-			string.split = function(s, p)
-                local rt= {}
-				string.gsub(s, '[^'..p..']+', function(w) table.insert(rt, w) end )
-                return rt
-            end
-			local cookie = ngx.var.http_Cookie
-			if cookie then
-				local tbl = string.split(cookie, ";")
-				local map = {}
-				for _, v in pairs(tbl) do
-					local list = string.split(v, "=")
-					map[list[1]] = list[2]
-				end
-			`)
 			var condition []string
 			for key, val := range c.Value {
-				condition = append(condition, fmt.Sprintf("map[\"%s\"] == \"%s\"", key, val))
+				condition = append(condition, fmt.Sprintf("ngx.var[%q] == %q", "cookie_"+key, val))
 			}
-			snippet = append(snippet, fmt.Sprintf("\t\t\t\tif %s then", strings.Join(condition, " and ")))
-			snippet = append(snippet, fmt.Sprintf("\t\t\t\t\tngx.var.target = \"%s\"", name))
-			snippet = append(snippet, "\t\t\t\telse")
-			snippet = append(snippet, "\t\t\t\t\tngx.exit(404)")
-			snippet = append(snippet, "\t\t\t\tend")
-			snippet = append(snippet, "\t\t\t\telseif ngx.var.target == 'default' then")
-			snippet = append(snippet, "\t\t\t\t\tngx.exit(404)")
-			snippet = append(snippet, "\t\t\tend")
-
-			priority[1] = strings.Join(snippet, "\n\r")
+			priority[1] = luaTarget(name, condition)
 		default:
-			snippet := fmt.Sprintf("\t\t\tngx.var.target = \"%s\"", name)
-			priority[0] = snippet
+			hasDefault = true
+			priority[0] = fmt.Sprintf("\t\t\tngx.var.target = \"%s\"", name)
 		}
 	}
 
@@ -123,12 +96,49 @@ func buildLuaHeaderRouter(input interface{}) string {
 			out = append(out, priority[i])
 		}
 	}
+	if !hasDefault && len(loc.NameCondition) > 0 {
+		out = append(out, "\t\t\tif ngx.var.target == 'default' then")
+		out = append(out, "\t\t\t\tngx.exit(404)")
+		out = append(out, "\t\t\tend")
+	}
 
 	out = append(out, "\t\t}")
 
 	return strings.Join(out, "\n\r")
 }
 
+// luaTarget routes the request to the backend if all the conditions are met
+func luaTarget(name string, condition []string) string {
+	sort.Strings(condition)
+	snippet := []string{
+		fmt.Sprintf("\t\t\tif %s then", strings.Join(condition, " and ")),
+		fmt.Sprintf("\t\t\t\tngx.var.target = \"%s\"", name),
+		"\t\t\tend",
+	}
+	return strings.Join(snippet, "\n\r")
+}
+
+// headerVariable returns the nginx variable of the request header, such as http_x_canary for X-Canary
+func headerVariable(header string) string {
+	return "http_" + strings.ToLower(strings.Replace(header, "-", "_", -1))
+}
+
+// buildLuaRateLimit limits clients before routing the request, rejected requests
+// never reach the header and cookie conditions.
+func buildLuaRateLimit(loc *model.Location) string {
+	limit := loc.RateLimit
+	args := []string{
+		fmt.Sprintf("zone = %q", loc.LimitZone),
+		fmt.Sprintf("rps = %d", limit.RPS),
+		fmt.Sprintf("burst = %d", limit.Burst),
+		fmt.Sprintf("conn = %d", limit.Connections),
+	}
+	if header := limit.Header(); header != "" {
+		args = append(args, fmt.Sprintf("header = %q", header))
+	}
+	return fmt.Sprintf("\t\t\tratelimit.access({ %s })", strings.Join(args, ", "))
+}
+
 // refer to http://nginx.org/en/docs/syntax.html
 // Nginx differentiates between size and offset
 // offset directives support gigabytes in addition
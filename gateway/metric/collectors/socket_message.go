@@ -51,6 +51,10 @@ type socketData struct {
 	Namespace      string  `json:"namespace"`
 	ServiceID      string  `json:"service_id"`
 	Path           string  `json:"path"`
+	// Rejected is the reason why the request was rejected by the rate limit, rate or connection
+	Rejected string `json:"rejected"`
+	// Version is the deploy version of the endpoint which served the request
+	Version string `json:"version"`
 }
 
 // SocketCollector stores prometheus metrics and ingress meta-data
@@ -63,6 +67,7 @@ type SocketCollector struct {
 	upstreamLatency *prometheus.SummaryVec
 	bytesSent       *prometheus.HistogramVec
 	requests        *prometheus.CounterVec
+	rejected        *prometheus.CounterVec
 	listener        net.Listener
 	metricMapping   map[string]interface{}
 	hosts           sets.String
@@ -77,6 +82,7 @@ var (
 		"namespace",
 		"service",
 		"service_id",
+		"version",
 	}
 )
 
@@ -152,7 +158,17 @@ func NewSocketCollector(gatewayHost string, metricsPerHost bool) (*SocketCollect
 				Namespace:   PrometheusNamespace,
 				ConstLabels: constLabels,
 			},
-			[]string{"host", "namespace", "service", "status", "service_id"},
+			[]string{"host", "namespace", "service", "status", "service_id", "version"},
+		),
+
+		rejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rejected_requests",
+				Help:        "The total number of client requests rejected by rate or connection limits.",
+				Namespace:   PrometheusNamespace,
+				ConstLabels: constLabels,
+			},
+			[]string{"host", "namespace", "service_id", "reason"},
 		),
 
 		bytesSent: prometheus.NewHistogramVec(
@@ -173,7 +189,7 @@ func NewSocketCollector(gatewayHost string, metricsPerHost bool) (*SocketCollect
 				Namespace:   PrometheusNamespace,
 				ConstLabels: constLabels,
 			},
-			[]string{"namespace", "service", "service_id"},
+			[]string{"namespace", "service", "service_id", "version"},
 		),
 	}
 
@@ -219,6 +235,7 @@ func (sc *SocketCollector) handleMessage(msg []byte) {
 			"namespace":  stats.Namespace,
 			"service":    stats.ServiceID,
 			"service_id": stats.ServiceID,
+			"version":    stats.Version,
 		}
 		if sc.metricsPerHost {
 			requestLabels["host"] = stats.Host
@@ -229,11 +246,13 @@ func (sc *SocketCollector) handleMessage(msg []byte) {
 			"service_id": stats.ServiceID,
 			"status":     stats.Status,
 			"host":       stats.Host,
+			"version":    stats.Version,
 		}
 		latencyLabels := prometheus.Labels{
 			"namespace":  stats.Namespace,
 			"service":    stats.ServiceID,
 			"service_id": stats.ServiceID,
+			"version":    stats.Version,
 		}
 		requestsMetric, err := sc.requests.GetMetricWith(collectorLabels)
 		if err != nil {
@@ -241,6 +260,19 @@ func (sc *SocketCollector) handleMessage(msg []byte) {
 		} else {
 			requestsMetric.Inc()
 		}
+		if stats.Rejected != "" {
+			rejectedMetric, err := sc.rejected.GetMetricWith(prometheus.Labels{
+				"host":       stats.Host,
+				"namespace":  stats.Namespace,
+				"service_id": stats.ServiceID,
+				"reason":     stats.Rejected,
+			})
+			if err != nil {
+				logrus.Errorf("Error fetching rejected requests metric: %v", err)
+			} else {
+				rejectedMetric.Inc()
+			}
+		}
 		if stats.Latency != -1 {
 			latencyMetric, err := sc.upstreamLatency.GetMetricWith(latencyLabels)
 			if err != nil {
@@ -359,6 +391,7 @@ func (sc SocketCollector) Describe(ch chan<- *prometheus.Desc) {
 	sc.requestTime.Describe(ch)
 	sc.requestLength.Describe(ch)
 	sc.requests.Describe(ch)
+	sc.rejected.Describe(ch)
 	sc.upstreamLatency.Describe(ch)
 	sc.responseTime.Describe(ch)
 	sc.responseLength.Describe(ch)
@@ -370,6 +403,7 @@ func (sc SocketCollector) Collect(ch chan<- prometheus.Metric) {
 	sc.requestTime.Collect(ch)
 	sc.requestLength.Collect(ch)
 	sc.requests.Collect(ch)
+	sc.rejected.Collect(ch)
 	sc.upstreamLatency.Collect(ch)
 	sc.responseTime.Collect(ch)
 	sc.responseLength.Collect(ch)
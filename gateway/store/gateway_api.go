@@ -0,0 +1,480 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	"github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// The gateway api routes are converted into ingresses, so that they share the
+// annotations, pools and virtual services with the ingresses created by kato.
+// Only the fields used by the conversion are defined here.
+
+// IngressClassAnnotation is the annotation of the ingress class before spec.ingressClassName
+const IngressClassAnnotation = "kubernetes.io/ingress.class"
+
+// RouteAnnotation records the route that the ingress is converted from.
+const RouteAnnotation = "gateway.kato.io/route"
+
+// gatewayAPIGroup is the group of the gateway api
+const gatewayAPIGroup = "gateway.networking.k8s.io"
+
+var (
+	// HTTPRouteResource is the resource of the gateway api HTTPRoute
+	HTTPRouteResource = schema.GroupVersionResource{Group: gatewayAPIGroup, Version: "v1alpha2", Resource: "httproutes"}
+	// TCPRouteResource is the resource of the gateway api TCPRoute
+	TCPRouteResource = schema.GroupVersionResource{Group: gatewayAPIGroup, Version: "v1alpha2", Resource: "tcproutes"}
+)
+
+// ParentReference refers to the gateway that a route attaches to.
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// BackendRef refers to the service that the traffic is forwarded to.
+type BackendRef struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+	Weight    *int32  `json:"weight,omitempty"`
+}
+
+// HTTPRoute -
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec -
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+// HTTPRouteRule -
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch `json:"matches,omitempty"`
+	BackendRefs []BackendRef     `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch -
+type HTTPRouteMatch struct {
+	Path    *HTTPPathMatch    `json:"path,omitempty"`
+	Headers []HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// The types of HTTPPathMatch
+const (
+	PathMatchExact             = "Exact"
+	PathMatchPathPrefix        = "PathPrefix"
+	PathMatchRegularExpression = "RegularExpression"
+)
+
+// HTTPPathMatch -
+type HTTPPathMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch -
+type HTTPHeaderMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+// TCPRoute -
+type TCPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TCPRouteSpec `json:"spec"`
+}
+
+// TCPRouteSpec -
+type TCPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Rules      []TCPRouteRule    `json:"rules,omitempty"`
+}
+
+// TCPRouteRule -
+type TCPRouteRule struct {
+	BackendRefs []BackendRef `json:"backendRefs,omitempty"`
+}
+
+// routeStore keeps the ingresses converted from the gateway api routes.
+type routeStore struct {
+	lock sync.RWMutex
+	// the key of the route -> ingresses
+	ingresses map[string][]*networkingv1.Ingress
+}
+
+func newRouteStore() *routeStore {
+	return &routeStore{ingresses: make(map[string][]*networkingv1.Ingress)}
+}
+
+// set replaces the ingresses of the route, and returns the replaced ones.
+func (r *routeStore) set(key string, ings []*networkingv1.Ingress) []*networkingv1.Ingress {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	old := r.ingresses[key]
+	if len(ings) == 0 {
+		delete(r.ingresses, key)
+	} else {
+		r.ingresses[key] = ings
+	}
+	return old
+}
+
+func (r *routeStore) listIngresses() []*networkingv1.Ingress {
+	if r == nil {
+		return nil
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	keys := make([]string, 0, len(r.ingresses))
+	for key := range r.ingresses {
+		keys = append(keys, key)
+	}
+	// keep the order stable, the first ingress of a location takes effect.
+	sort.Strings(keys)
+	var ingresses []*networkingv1.Ingress
+	for _, key := range keys {
+		ingresses = append(ingresses, r.ingresses[key]...)
+	}
+	return ingresses
+}
+
+// routeEventHandler returns the event handler of the informer of the given route resource.
+func (s *k8sStore) routeEventHandler(resource schema.GroupVersionResource) cache.ResourceEventHandlerFuncs {
+	handle := func(obj interface{}, eventType EventType) {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+			if !ok {
+				logrus.Errorf("couldn't get object from tombstone %#v", obj)
+				return
+			}
+			u, ok = tombstone.Obj.(*unstructured.Unstructured)
+			if !ok {
+				logrus.Errorf("Tombstone contained object that is not a route: %#v", obj)
+				return
+			}
+		}
+		key := routeKey(resource, u.GetNamespace(), u.GetName())
+		var ings []*networkingv1.Ingress
+		if eventType != DeleteEvent {
+			var err error
+			ings, err = convertRoute(resource, u, s.conf.GatewayName)
+			if err != nil {
+				logrus.Warningf("convert %s: %v", key, err)
+			}
+		}
+		names := make(map[string]struct{}, len(ings))
+		for _, ing := range ings {
+			names[ing.Name] = struct{}{}
+		}
+		for _, ing := range s.routes.set(key, ings) {
+			if _, ok := names[ing.Name]; ok {
+				continue
+			}
+			if err := s.listers.IngressAnnotation.Delete(s.annotations.Extract(ing)); err != nil {
+				logrus.Error(err)
+			}
+		}
+		for _, ing := range ings {
+			s.extractAnnotations(ing)
+		}
+		s.updateCh.In() <- Event{
+			Type: eventType,
+			Obj:  obj,
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			handle(obj, CreateEvent)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			oldRoute, ok := old.(*unstructured.Unstructured)
+			curRoute, ok2 := cur.(*unstructured.Unstructured)
+			if ok && ok2 && oldRoute.GetResourceVersion() == curRoute.GetResourceVersion() {
+				return
+			}
+			handle(cur, UpdateEvent)
+		},
+		DeleteFunc: func(obj interface{}) {
+			handle(obj, DeleteEvent)
+		},
+	}
+}
+
+func routeKey(resource schema.GroupVersionResource, namespace, name string) string {
+	return resource.Resource + "/" + namespace + "/" + name
+}
+
+func convertRoute(resource schema.GroupVersionResource, u *unstructured.Unstructured, gatewayName string) ([]*networkingv1.Ingress, error) {
+	switch resource {
+	case HTTPRouteResource:
+		var route HTTPRoute
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &route); err != nil {
+			return nil, err
+		}
+		return httpRouteToIngresses(&route, gatewayName), nil
+	case TCPRouteResource:
+		var route TCPRoute
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &route); err != nil {
+			return nil, err
+		}
+		return tcpRouteToIngresses(&route, gatewayName), nil
+	}
+	return nil, fmt.Errorf("unsupported resource %s", resource.String())
+}
+
+// attachedParents returns the parent references which refer to the given gateway.
+// gatewayName is in the form of namespace/name or name.
+func attachedParents(namespace string, parentRefs []ParentReference, gatewayName string) []ParentReference {
+	gwNamespace, gwName := "", gatewayName
+	if i := strings.Index(gatewayName, "/"); i >= 0 {
+		gwNamespace, gwName = gatewayName[:i], gatewayName[i+1:]
+	}
+	var parents []ParentReference
+	for _, ref := range parentRefs {
+		if ref.Group != nil && *ref.Group != gatewayAPIGroup {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != "Gateway" {
+			continue
+		}
+		refNamespace := namespace
+		if ref.Namespace != nil && *ref.Namespace != "" {
+			refNamespace = *ref.Namespace
+		}
+		if ref.Name != gwName || (gwNamespace != "" && refNamespace != gwNamespace) {
+			continue
+		}
+		parents = append(parents, ref)
+	}
+	return parents
+}
+
+// backendRefIsValid checks if the backend refers to a service in the namespace of the route.
+// The references to the other namespaces are not supported.
+func backendRefIsValid(namespace string, ref BackendRef) error {
+	if ref.Group != nil && *ref.Group != "" {
+		return fmt.Errorf("backend %s: group %s is not supported", ref.Name, *ref.Group)
+	}
+	if ref.Kind != nil && *ref.Kind != "Service" {
+		return fmt.Errorf("backend %s: kind %s is not supported", ref.Name, *ref.Kind)
+	}
+	if ref.Namespace != nil && *ref.Namespace != "" && *ref.Namespace != namespace {
+		return fmt.Errorf("backend %s: cross namespace reference is not supported", ref.Name)
+	}
+	if ref.Port == nil {
+		return fmt.Errorf("backend %s: port is required", ref.Name)
+	}
+	return nil
+}
+
+func backendWeight(ref BackendRef) int32 {
+	if ref.Weight == nil {
+		return 1
+	}
+	return *ref.Weight
+}
+
+func routeIngress(resource schema.GroupVersionResource, meta metav1.ObjectMeta, name string, annotations map[string]string) *networkingv1.Ingress {
+	annotations[RouteAnnotation] = routeKey(resource, meta.Namespace, meta.Name)
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   meta.Namespace,
+			Labels:      meta.Labels,
+			Annotations: annotations,
+		},
+	}
+}
+
+// httpRouteToIngresses converts the http route into ingresses, one for each match and backend.
+// The backends of a match share the same location, and the traffic is split by their weights.
+func httpRouteToIngresses(route *HTTPRoute, gatewayName string) []*networkingv1.Ingress {
+	if len(attachedParents(route.Namespace, route.Spec.ParentRefs, gatewayName)) == 0 {
+		return nil
+	}
+	hosts := route.Spec.Hostnames
+	if len(hosts) == 0 {
+		hosts = []string{""}
+	}
+	var ingresses []*networkingv1.Ingress
+	for i, rule := range route.Spec.Rules {
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []HTTPRouteMatch{{}}
+		}
+		for j, match := range matches {
+			path, err := httpIngressPath(match.Path)
+			if err != nil {
+				logrus.Warningf("httproute %s/%s: %v", route.Namespace, route.Name, err)
+				continue
+			}
+			header, err := headerAnnotation(match.Headers)
+			if err != nil {
+				logrus.Warningf("httproute %s/%s: %v", route.Namespace, route.Name, err)
+				continue
+			}
+			for k, ref := range rule.BackendRefs {
+				if err := backendRefIsValid(route.Namespace, ref); err != nil {
+					logrus.Warningf("httproute %s/%s: %v", route.Namespace, route.Name, err)
+					continue
+				}
+				weight := backendWeight(ref)
+				if weight <= 0 {
+					continue
+				}
+				anns := map[string]string{
+					parser.GetAnnotationWithPrefix("weight"): strconv.Itoa(int(weight)),
+				}
+				if header != "" {
+					anns[parser.GetAnnotationWithPrefix("header")] = header
+				}
+				ing := routeIngress(HTTPRouteResource, route.ObjectMeta, fmt.Sprintf("%s.httproute.%d.%d.%d", route.Name, i, j, k), anns)
+				ingPath := path
+				ingPath.Backend = networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: ref.Name,
+						Port: networkingv1.ServiceBackendPort{Number: *ref.Port},
+					},
+				}
+				for _, host := range hosts {
+					ing.Spec.Rules = append(ing.Spec.Rules, networkingv1.IngressRule{
+						Host: host,
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{ingPath},
+							},
+						},
+					})
+				}
+				ingresses = append(ingresses, ing)
+			}
+		}
+	}
+	return ingresses
+}
+
+func httpIngressPath(match *HTTPPathMatch) (networkingv1.HTTPIngressPath, error) {
+	pathType := networkingv1.PathTypePrefix
+	path := networkingv1.HTTPIngressPath{Path: "/", PathType: &pathType}
+	if match == nil {
+		return path, nil
+	}
+	if match.Value != nil && *match.Value != "" {
+		path.Path = *match.Value
+	}
+	matchType := PathMatchPathPrefix
+	if match.Type != nil {
+		matchType = *match.Type
+	}
+	switch matchType {
+	case PathMatchPathPrefix:
+	case PathMatchExact:
+		pathType = networkingv1.PathTypeExact
+	case PathMatchRegularExpression:
+		// nginx location with the regular expression
+		pathType = networkingv1.PathTypeImplementationSpecific
+		path.Path = "~ " + path.Path
+	default:
+		return path, fmt.Errorf("path match type %s is not supported", matchType)
+	}
+	return path, nil
+}
+
+// headerAnnotation converts the header matches into the value of the header annotation.
+func headerAnnotation(headers []HTTPHeaderMatch) (string, error) {
+	var items []string
+	for _, header := range headers {
+		if header.Type != nil && *header.Type != "Exact" {
+			return "", fmt.Errorf("header match type %s is not supported", *header.Type)
+		}
+		if strings.ContainsAny(header.Value, "=; ") {
+			return "", fmt.Errorf("header %s: value %q is not supported", header.Name, header.Value)
+		}
+		items = append(items, header.Name+"="+header.Value)
+	}
+	return strings.Join(items, ";"), nil
+}
+
+// tcpRouteToIngresses converts the tcp route into l4 ingresses. The listening port
+// comes from the port of the parent reference, since the gateway listens on the ports directly.
+func tcpRouteToIngresses(route *TCPRoute, gatewayName string) []*networkingv1.Ingress {
+	var ingresses []*networkingv1.Ingress
+	for i, parent := range attachedParents(route.Namespace, route.Spec.ParentRefs, gatewayName) {
+		if parent.Port == nil {
+			logrus.Warningf("tcproute %s/%s: the port of parent %s is required", route.Namespace, route.Name, parent.Name)
+			continue
+		}
+		for j, rule := range route.Spec.Rules {
+			for k, ref := range rule.BackendRefs {
+				if err := backendRefIsValid(route.Namespace, ref); err != nil {
+					logrus.Warningf("tcproute %s/%s: %v", route.Namespace, route.Name, err)
+					continue
+				}
+				weight := backendWeight(ref)
+				if weight <= 0 {
+					continue
+				}
+				anns := map[string]string{
+					parser.GetAnnotationWithPrefix("l4-enable"): "true",
+					parser.GetAnnotationWithPrefix("l4-host"):   "0.0.0.0",
+					parser.GetAnnotationWithPrefix("l4-port"):   strconv.Itoa(int(*parent.Port)),
+					parser.GetAnnotationWithPrefix("weight"):    strconv.Itoa(int(weight)),
+				}
+				ing := routeIngress(TCPRouteResource, route.ObjectMeta, fmt.Sprintf("%s.tcproute.%d.%d.%d", route.Name, i, j, k), anns)
+				ing.Spec.DefaultBackend = &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: ref.Name,
+						Port: networkingv1.ServiceBackendPort{Number: *ref.Port},
+					},
+				}
+				ingresses = append(ingresses, ing)
+			}
+		}
+	}
+	return ingresses
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/cmd/gateway/option"
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConvertHTTPRoute(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1alpha2",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "kato", "namespace": "kato-system"},
+			},
+			"hostnames": []interface{}{"foo.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{
+							"path":    map[string]interface{}{"type": "Exact", "value": "/api"},
+							"headers": []interface{}{map[string]interface{}{"name": "version", "value": "v2"}},
+						},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "v1", "port": int64(80), "weight": int64(90)},
+						map[string]interface{}{"name": "v2", "port": int64(8080), "weight": int64(10)},
+						map[string]interface{}{"name": "v3", "port": int64(80), "weight": int64(0)},
+						map[string]interface{}{"name": "other", "namespace": "other", "port": int64(80)},
+					},
+				},
+			},
+		},
+	}}
+	ings, err := convertRoute(HTTPRouteResource, u, "kato-system/kato")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ings) != 2 {
+		t.Fatalf("expected 2 ingresses, but got %d", len(ings))
+	}
+	for i, want := range []struct {
+		service string
+		port    int32
+		weight  string
+	}{{"v1", 80, "90"}, {"v2", 8080, "10"}} {
+		ing := ings[i]
+		if ing.Namespace != "default" {
+			t.Errorf("expected namespace default, but got %s", ing.Namespace)
+		}
+		if got := ing.Annotations[parser.GetAnnotationWithPrefix("weight")]; got != want.weight {
+			t.Errorf("expected weight %s, but got %s", want.weight, got)
+		}
+		if got := ing.Annotations[parser.GetAnnotationWithPrefix("header")]; got != "version=v2" {
+			t.Errorf("expected header version=v2, but got %s", got)
+		}
+		if got := ing.Annotations[RouteAnnotation]; got != "httproutes/default/foo" {
+			t.Errorf("expected route httproutes/default/foo, but got %s", got)
+		}
+		if len(ing.Spec.Rules) != 1 || ing.Spec.Rules[0].Host != "foo.example.com" {
+			t.Fatalf("unexpected rules %+v", ing.Spec.Rules)
+		}
+		path := ing.Spec.Rules[0].HTTP.Paths[0]
+		if locationPath(path) != "= /api" {
+			t.Errorf("expected location path = /api, but got %s", locationPath(path))
+		}
+		if path.Backend.Service.Name != want.service || path.Backend.Service.Port.Number != want.port {
+			t.Errorf("expected backend %s:%d, but got %+v", want.service, want.port, path.Backend.Service)
+		}
+	}
+	if ings[0].Name == ings[1].Name {
+		t.Errorf("expected different names, but both are %s", ings[0].Name)
+	}
+
+	// the route is not attached to the gateway
+	ings, err = convertRoute(HTTPRouteResource, u, "default/kato")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ings) != 0 {
+		t.Errorf("expected no ingress, but got %d", len(ings))
+	}
+}
+
+func TestConvertTCPRoute(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "db",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "kato", "port": int64(5432)},
+				map[string]interface{}{"name": "kato"},
+			},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "postgres", "port": int64(5432)},
+					},
+				},
+			},
+		},
+	}}
+	ings, err := convertRoute(TCPRouteResource, u, "kato")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ings) != 1 {
+		t.Fatalf("expected 1 ingress, but got %d", len(ings))
+	}
+	ing := ings[0]
+	if got := ing.Annotations[parser.GetAnnotationWithPrefix("l4-port")]; got != "5432" {
+		t.Errorf("expected l4 port 5432, but got %s", got)
+	}
+	if got := ing.Annotations[parser.GetAnnotationWithPrefix("weight")]; got != "1" {
+		t.Errorf("expected weight 1, but got %s", got)
+	}
+	if ing.Spec.DefaultBackend == nil || ing.Spec.DefaultBackend.Service.Name != "postgres" {
+		t.Errorf("unexpected backend %+v", ing.Spec.DefaultBackend)
+	}
+}
+
+func TestHTTPIngressPath(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		match   *HTTPPathMatch
+		want    string
+		wantErr bool
+	}{
+		{match: nil, want: "/"},
+		{match: &HTTPPathMatch{Value: str("/foo")}, want: "/foo"},
+		{match: &HTTPPathMatch{Type: str(PathMatchExact), Value: str("/foo")}, want: "= /foo"},
+		{match: &HTTPPathMatch{Type: str(PathMatchRegularExpression), Value: str("^/foo/[0-9]+")}, want: "~ ^/foo/[0-9]+"},
+		{match: &HTTPPathMatch{Type: str("Unknown"), Value: str("/foo")}, wantErr: true},
+	}
+	for _, tc := range tests {
+		path, err := httpIngressPath(tc.match)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("expected error %v, but got %v", tc.wantErr, err)
+			continue
+		}
+		if !tc.wantErr && locationPath(path) != tc.want {
+			t.Errorf("expected location path %s, but got %s", tc.want, locationPath(path))
+		}
+	}
+}
+
+func TestIngressClassMatched(t *testing.T) {
+	class := "kato"
+	other := "nginx"
+	s := k8sStore{conf: &option.Config{IngressClass: "kato"}}
+	tests := []struct {
+		ing  *networkingv1.Ingress
+		want bool
+	}{
+		{ing: &networkingv1.Ingress{}, want: true},
+		{ing: &networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: &class}}, want: true},
+		{ing: &networkingv1.Ingress{Spec: networkingv1.IngressSpec{IngressClassName: &other}}, want: false},
+	}
+	for _, tc := range tests {
+		if got := s.ingressClassMatched(tc.ing); got != tc.want {
+			t.Errorf("expected %v, but got %v", tc.want, got)
+		}
+	}
+}
@@ -32,6 +32,9 @@ type Informer struct {
 	Service  cache.SharedIndexInformer
 	Endpoint cache.SharedIndexInformer
 	Secret   cache.SharedIndexInformer
+	// HTTPRoute and TCPRoute are nil if the gateway api is not enabled
+	HTTPRoute cache.SharedIndexInformer
+	TCPRoute  cache.SharedIndexInformer
 }
 
 // Run initiates the synchronization of the informers against the API server.
@@ -63,4 +66,12 @@ func (i *Informer) Run(stopCh chan struct{}) {
 	) {
 		runtime.HandleError(fmt.Errorf("Timed out waiting for caches to sync"))
 	}
+
+	// do not wait for the routes, the gateway api CRDs may not be installed.
+	if i.HTTPRoute != nil {
+		go i.HTTPRoute.Run(stopCh)
+	}
+	if i.TCPRoute != nil {
+		go i.TCPRoute.Run(stopCh)
+	}
 }
@@ -20,23 +20,59 @@ package store
 
 import (
 	"fmt"
+	"sync"
 
+	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/util/ingress-nginx/k8s"
-	"k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
+// secretIngressMap is the mapping between ingresses and the secrets they reference
 type secretIngressMap struct {
-	v map[string][]string
+	lock sync.RWMutex
+	v    map[string][]string
 }
 
-func (m *secretIngressMap) update(ing *v1beta1.Ingress) {
+func (m *secretIngressMap) update(ing *networkingv1.Ingress) {
 	ingKey := k8s.MetaNamespaceKey(ing)
+	var secretKeys []string
 	for _, tls := range ing.Spec.TLS {
-		secretKey := fmt.Sprintf("%s/%s", ing.Namespace, tls.SecretName)
-		m.v[ingKey] = append(m.v[ingKey], secretKey)
+		secretKeys = append(secretKeys, fmt.Sprintf("%s/%s", ing.Namespace, tls.SecretName))
 	}
+	// the secret of basic auth
+	if name, err := parser.GetStringAnnotation("auth-secret", ing); err == nil && name != "" {
+		secretKeys = append(secretKeys, fmt.Sprintf("%s/%s", ing.Namespace, name))
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.v[ingKey] = secretKeys
+}
+
+func (m *secretIngressMap) delete(ingKey string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.v, ingKey)
 }
 
+// getSecretKeys returns the secrets referenced by the ingress
 func (m *secretIngressMap) getSecretKeys(ingKey string) []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
 	return m.v[ingKey]
 }
+
+// getIngressKeys returns the ingresses which reference the secret
+func (m *secretIngressMap) getIngressKeys(secretKey string) []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var ingKeys []string
+	for ingKey, secretKeys := range m.v {
+		for _, key := range secretKeys {
+			if key == secretKey {
+				ingKeys = append(ingKeys, ingKey)
+				break
+			}
+		}
+	}
+	return ingKeys
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/eapache/channels"
 	"github.com/gridworkz/kato/cmd/gateway/option"
 	"github.com/gridworkz/kato/gateway/annotations"
+	"github.com/gridworkz/kato/gateway/annotations/auth"
 	"github.com/gridworkz/kato/gateway/annotations/l4"
 	"github.com/gridworkz/kato/gateway/annotations/rewrite"
 	"github.com/gridworkz/kato/gateway/controller/config"
@@ -47,9 +48,10 @@ import (
 	ik8s "github.com/gridworkz/kato/util/ingress-nginx/k8s"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -67,6 +69,8 @@ const (
 	DeleteEvent EventType = "DELETE"
 	// CertificatePath is the default path of certificate file
 	CertificatePath = "/run/nginx/conf/certificate"
+	// AuthPath is the default path of htpasswd file of basic auth
+	AuthPath = "/run/nginx/conf/auth"
 	// DefVirSrvName is the default virtual service name
 	DefVirSrvName = "_"
 )
@@ -89,7 +93,7 @@ type Storer interface {
 	// list virtual service
 	ListVirtualService() ([]*v1.VirtualService, []*v1.VirtualService)
 
-	ListIngresses() []*extensions.Ingress
+	ListIngresses() []*networkingv1.Ingress
 
 	GetIngressAnnotations(key string) (*annotations.Ingress, error)
 
@@ -147,10 +151,14 @@ type k8sStore struct {
 	// Node controller to get the available IP address of the current node
 	node     *cluster.NodeManager
 	updateCh *channels.RingChannel
+	// routes contains the ingresses converted from the gateway api routes
+	routes *routeStore
 }
 
-// New creates a new Storer
+// New creates a new Storer. The gateway api routes are watched by dynamicClient
+// if conf.GatewayName is not empty.
 func New(client kubernetes.Interface,
+	dynamicClient dynamic.Interface,
 	updateCh *channels.RingChannel,
 	conf *option.Config, node *cluster.NodeManager) Storer {
 	store := &k8sStore{
@@ -158,7 +166,7 @@ func New(client kubernetes.Interface,
 		informers: &Informer{},
 		listers:   &Lister{},
 		secretIngressMap: &secretIngressMap{
-			make(map[string][]string),
+			v: make(map[string][]string),
 		},
 		sslStore:        NewSSLCertTracker(),
 		conf:            conf,
@@ -166,6 +174,7 @@ func New(client kubernetes.Interface,
 		backendConfig:   config.NewDefault(),
 		node:            node,
 		updateCh:        updateCh,
+		routes:          newRouteStore(),
 	}
 
 	store.annotations = annotations.NewAnnotationExtractor(store)
@@ -177,7 +186,7 @@ func New(client kubernetes.Interface,
 			options.LabelSelector = "creator=Kato"
 		})
 
-	store.informers.Ingress = store.sharedInformer.Extensions().V1beta1().Ingresses().Informer()
+	store.informers.Ingress = store.sharedInformer.Networking().V1().Ingresses().Informer()
 	store.listers.Ingress.Store = store.informers.Ingress.GetStore()
 
 	store.informers.Service = store.sharedInformer.Core().V1().Services().Informer()
@@ -191,7 +200,7 @@ func New(client kubernetes.Interface,
 
 	ingEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			ing := obj.(*extensions.Ingress)
+			ing := obj.(*networkingv1.Ingress)
 
 			// updating annotations information for ingress
 			store.extractAnnotations(ing)
@@ -206,14 +215,17 @@ func New(client kubernetes.Interface,
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
+			if ing, ok := obj.(*networkingv1.Ingress); ok {
+				store.secretIngressMap.delete(ik8s.MetaNamespaceKey(ing))
+			}
 			updateCh.In() <- Event{
 				Type: DeleteEvent,
 				Obj:  obj,
 			}
 		},
 		UpdateFunc: func(old, cur interface{}) {
-			oldIng := old.(*extensions.Ingress)
-			curIng := cur.(*extensions.Ingress)
+			oldIng := old.(*networkingv1.Ingress)
+			curIng := cur.(*networkingv1.Ingress)
 			// ignore the same secret as the old one
 			if oldIng.ResourceVersion == curIng.ResourceVersion || reflect.DeepEqual(oldIng, curIng) {
 				return
@@ -234,7 +246,7 @@ func New(client kubernetes.Interface,
 			key := ik8s.MetaNamespaceKey(sec)
 
 			// find references in ingresses and update local ssl certs
-			if ings := store.secretIngressMap.getSecretKeys(key); len(ings) > 0 {
+			if ings := store.secretIngressMap.getIngressKeys(key); len(ings) > 0 {
 				logrus.Infof("secret %v was added and it is used in ingress annotations. Parsing...", key)
 				for _, ingKey := range ings {
 					ing, err := store.GetIngress(ingKey)
@@ -261,7 +273,7 @@ func New(client kubernetes.Interface,
 				key := ik8s.MetaNamespaceKey(curSec)
 
 				// find references in ingresses and update local ssl certs
-				if ings := store.secretIngressMap.getSecretKeys(key); len(ings) > 0 {
+				if ings := store.secretIngressMap.getIngressKeys(key); len(ings) > 0 {
 					logrus.Infof("secret %v was updated and it is used in ingress annotations. Parsing...", key)
 					for _, ingKey := range ings {
 						ing, err := store.GetIngress(ingKey)
@@ -295,11 +307,13 @@ func New(client kubernetes.Interface,
 			}
 
 			store.sslStore.Delete(ik8s.MetaNamespaceKey(sec))
+			// requests of the locations using the deleted basic auth are rejected without the htpasswd file
+			os.Remove(authFile(ik8s.MetaNamespaceKey(sec)))
 
 			key := ik8s.MetaNamespaceKey(sec)
 
 			// find references in ingresses
-			if ings := store.secretIngressMap.getSecretKeys(key); len(ings) > 0 {
+			if ings := store.secretIngressMap.getIngressKeys(key); len(ings) > 0 {
 				logrus.Infof("secret %v was deleted and it is used in ingress annotations. Parsing...", key)
 				updateCh.In() <- Event{
 					Type: DeleteEvent,
@@ -340,11 +354,20 @@ func New(client kubernetes.Interface,
 	store.informers.Endpoint.AddEventHandler(epEventHandler)
 	store.informers.Service.AddEventHandler(cache.ResourceEventHandlerFuncs{})
 
+	if conf.GatewayName != "" && dynamicClient != nil {
+		// the routes are created by other tools, so they are not filtered by label.
+		routeInformer := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, conf.ResyncPeriod)
+		store.informers.HTTPRoute = routeInformer.ForResource(HTTPRouteResource).Informer()
+		store.informers.HTTPRoute.AddEventHandler(store.routeEventHandler(HTTPRouteResource))
+		store.informers.TCPRoute = routeInformer.ForResource(TCPRouteResource).Informer()
+		store.informers.TCPRoute.AddEventHandler(store.routeEventHandler(TCPRouteResource))
+	}
+
 	return store
 }
 
 // checkIngress checks whether the given ing is valid.
-func (s *k8sStore) checkIngress(ing *extensions.Ingress) bool {
+func (s *k8sStore) checkIngress(ing *networkingv1.Ingress) bool {
 	i, err := l4.NewParser(s).Parse(ing)
 	if err != nil {
 		logrus.Warningf("Uxpected error with ingress: %v", err)
@@ -367,7 +390,7 @@ func (s *k8sStore) checkIngress(ing *extensions.Ingress) bool {
 
 // extractAnnotations parses ingress annotations converting the value of the
 // annotation to a go struct and also information about the referenced secrets
-func (s *k8sStore) extractAnnotations(ing *extensions.Ingress) {
+func (s *k8sStore) extractAnnotations(ing *networkingv1.Ingress) {
 	key := ik8s.MetaNamespaceKey(ing)
 	logrus.Debugf("updating annotations information for ingress %v", key)
 
@@ -385,6 +408,8 @@ func (s *k8sStore) ListPool() ([]*v1.Pool, []*v1.Pool) {
 	var tcpPools []*v1.Pool
 	l7Pools := make(map[string]*v1.Pool)
 	l4Pools := make(map[string]*v1.Pool)
+	// pool name -> the nodes of each endpoints
+	l7Sources := make(map[string][]*poolSource)
 	for _, item := range s.listers.Endpoint.List() {
 		ep := item.(*corev1.Endpoints)
 		if ep.Subsets != nil || len(ep.Subsets) != 0 {
@@ -404,19 +429,22 @@ func (s *k8sStore) ListPool() ([]*v1.Pool, []*v1.Pool) {
 					pool.LoadBalancingType = v1.GetLoadBalancingType(backend.loadBalancingType)
 					l7Pools[backend.name] = pool
 				}
+				source := &poolSource{weight: backend.weight}
 				for _, ss := range ep.Subsets {
 					for _, port := range ss.Ports {
 						for _, address := range ss.Addresses {
 							if _, ok := l7PoolMap[epn]; ok { // l7
-								pool.Nodes = append(pool.Nodes, &v1.Node{
-									Host:   address.IP,
-									Port:   port.Port,
-									Weight: backend.weight,
+								source.nodes = append(source.nodes, &v1.Node{
+									Host:    address.IP,
+									Port:    port.Port,
+									Weight:  backend.weight,
+									Version: ep.Labels["version"],
 								})
 							}
 						}
 					}
 				}
+				l7Sources[backend.name] = append(l7Sources[backend.name], source)
 			}
 			// l4
 			backends = l4PoolBackendMap[ep.ObjectMeta.Name]
@@ -449,6 +477,7 @@ func (s *k8sStore) ListPool() ([]*v1.Pool, []*v1.Pool) {
 	}
 	// change map to slice TODO: use map directly
 	for _, pool := range l7Pools {
+		pool.Nodes = weightNodes(l7Sources[pool.Name])
 		httpPools = append(httpPools, pool)
 	}
 	for _, pool := range l4Pools {
@@ -457,16 +486,54 @@ func (s *k8sStore) ListPool() ([]*v1.Pool, []*v1.Pool) {
 	return httpPools, tcpPools
 }
 
+// poolSource is the nodes of a pool that come from the same endpoints
+type poolSource struct {
+	weight int
+	nodes  []*v1.Node
+}
+
+// weightNodes merges the nodes of a pool. If the pool is made up of more than one endpoints,
+// such as the stable and canary version of a service, the weight of a endpoints is shared by its nodes,
+// so that the traffic is split by the weight of the endpoints rather than the number of nodes.
+func weightNodes(sources []*poolSource) []*v1.Node {
+	nodes := []*v1.Node{}
+	if len(sources) == 1 {
+		return append(nodes, sources[0].nodes...)
+	}
+	positive := false
+	for _, source := range sources {
+		if source.weight > 0 && len(source.nodes) > 0 {
+			positive = true
+			break
+		}
+	}
+	for _, source := range sources {
+		if len(source.nodes) == 0 || (positive && source.weight <= 0) {
+			continue
+		}
+		weight := source.weight * 100 / len(source.nodes)
+		if weight < 1 {
+			weight = 1
+		}
+		for _, node := range source.nodes {
+			node.Weight = weight
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 // ListVirtualService list l7 virtual service and l4 virtual service
 func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.VirtualService) {
 	l7PoolBackendMap = make(map[string][]backend)
 	l4PoolBackendMap = make(map[string][]backend)
 	l7vsMap := make(map[string]*v1.VirtualService)
 	l4vsMap := make(map[string]*v1.VirtualService)
+	// listening -> the route which the l4 virtual service comes from
+	l4RouteMap := make(map[string]string)
 	// ServerName-LocationPath -> location
 	srvLocMap := make(map[string]*v1.Location)
-	for _, item := range s.listers.Ingress.List() {
-		ing := item.(*extensions.Ingress)
+	for _, ing := range s.ListIngresses() {
 		if !s.ingressIsValid(ing) {
 			continue
 		}
@@ -493,8 +560,8 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 					continue
 				}
 			}
-			svcKey := fmt.Sprintf("%v/%v", ing.Namespace, ing.Spec.Backend.ServiceName)
-			protocol := s.GetServiceProtocol(svcKey, ing.Spec.Backend.ServicePort.IntVal)
+			svcKey := fmt.Sprintf("%v/%v", ing.Namespace, ing.Spec.DefaultBackend.Service.Name)
+			protocol := s.GetServiceProtocol(svcKey, ing.Spec.DefaultBackend.Service.Port.Number)
 			listening := fmt.Sprintf("%s:%v", host, anns.L4.L4Port)
 			if string(protocol) == string(v1.ProtocolUDP) {
 				listening = fmt.Sprintf("%s %s", listening, "udp")
@@ -506,17 +573,28 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 				logrus.Warningf("ingress %s (Namespace:%s) l4 host repeat listening will be ignored", ing.Name, ing.Namespace)
 				continue
 			}
+			backendName := util.BackendName(listening, ing.Namespace)
+			if route := ing.Annotations[RouteAnnotation]; route != "" && l4RouteMap[listening] == route {
+				// the weighted backends of a route share the listening and the pool
+				l4PoolMap[ing.Spec.DefaultBackend.Service.Name] = struct{}{}
+				backend := backend{name: backendName, weight: anns.Weight.Weight}
+				l4PoolBackendMap[ing.Spec.DefaultBackend.Service.Name] = append(l4PoolBackendMap[ing.Spec.DefaultBackend.Service.Name], backend)
+				continue
+			}
 			conflictkey := []string{
 				listening, strings.Replace(listening, host, "0.0.0.0", 1),
 			}
+			conflict := false
 			for _, key := range conflictkey {
-				vs := l4vsMap[key]
-				if vs != nil {
-					logrus.Warningf("ingress %s (Namespace:%s) l4 host repeat listening will be ignored", ing.Name, ing.Namespace)
-					continue
+				if l4vsMap[key] != nil {
+					conflict = true
+					break
 				}
 			}
-			backendName := util.BackendName(listening, ing.Namespace)
+			if conflict {
+				logrus.Warningf("ingress %s (Namespace:%s) l4 host repeat listening will be ignored", ing.Name, ing.Namespace)
+				continue
+			}
 			vs := &v1.VirtualService{
 				Listening: []string{listening},
 				PoolName:  backendName,
@@ -524,11 +602,12 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 			}
 			vs.Namespace = anns.Namespace
 			vs.ServiceID = anns.Labels["service_id"]
-			l4PoolMap[ing.Spec.Backend.ServiceName] = struct{}{}
+			l4PoolMap[ing.Spec.DefaultBackend.Service.Name] = struct{}{}
 			l4vsMap[listening] = vs
+			l4RouteMap[listening] = ing.Annotations[RouteAnnotation]
 			l4vs = append(l4vs, vs)
 			backend := backend{name: backendName, weight: anns.Weight.Weight}
-			l4PoolBackendMap[ing.Spec.Backend.ServiceName] = append(l4PoolBackendMap[ing.Spec.Backend.ServiceName], backend)
+			l4PoolBackendMap[ing.Spec.DefaultBackend.Service.Name] = append(l4PoolBackendMap[ing.Spec.DefaultBackend.Service.Name], backend)
 			// endregion
 		} else {
 			// region l7
@@ -591,19 +670,28 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 				}
 
 				for _, path := range rule.IngressRuleValue.HTTP.Paths {
-					locKey := fmt.Sprintf("%s_%s", virSrvName, path.Path)
+					locPath := locationPath(path)
+					locKey := fmt.Sprintf("%s_%s", virSrvName, locPath)
 					location := srvLocMap[locKey]
-					l7PoolMap[path.Backend.ServiceName] = struct{}{}
+					l7PoolMap[path.Backend.Service.Name] = struct{}{}
 					// if location do not exists, then creates a new one
 					if location == nil {
 						location = &v1.Location{
-							Path:          path.Path,
+							Path:          locPath,
 							NameCondition: map[string]*v1.Condition{},
 						}
 						srvLocMap[locKey] = location
 						vs.Locations = append(vs.Locations, location)
-						// the first ingress proxy takes effect
+						// the first ingress proxy, rate limit and access policies take effect
 						location.Proxy = anns.Proxy
+						location.RateLimit = anns.RateLimit
+						location.IPAccess = anns.IPAccess
+						location.ForwardAuth = anns.ForwardAuth
+						location.BasicAuth = anns.BasicAuth
+						if location.BasicAuth.Secret != "" {
+							location.BasicAuth.File = authFile(location.BasicAuth.Secret)
+						}
+						location.Denied = anns.Denied != nil
 					}
 					// If their ServiceName is the same, then the new one will overwrite the old one.
 					nameCondition := &v1.Condition{}
@@ -631,15 +719,14 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 					if anns.UpstreamHashBy != "" {
 						backend.hashBy = anns.UpstreamHashBy
 					}
-					l7PoolBackendMap[path.Backend.ServiceName] = append(l7PoolBackendMap[path.Backend.ServiceName], backend)
+					l7PoolBackendMap[path.Backend.Service.Name] = append(l7PoolBackendMap[path.Backend.Service.Name], backend)
 				}
 			}
 			// endregion
 		}
 	}
 
-	for _, item := range s.listers.Ingress.List() {
-		ing := item.(*extensions.Ingress)
+	for _, ing := range s.ListIngresses() {
 		if !s.ingressIsValid(ing) {
 			continue
 		}
@@ -670,7 +757,8 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 				}
 
 				for _, path := range rule.IngressRuleValue.HTTP.Paths {
-					locKey := fmt.Sprintf("%s_%s", virSrvName, path.Path)
+					locPath := locationPath(path)
+					locKey := fmt.Sprintf("%s_%s", virSrvName, locPath)
 					location := srvLocMap[locKey]
 					if location != nil {
 						// If location != nil, the http policy for path is already set.
@@ -678,7 +766,7 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 						continue
 					}
 					location = &v1.Location{
-						Path:             path.Path,
+						Path:             locPath,
 						DisableProxyPass: true,
 						Rewrite: rewrite.Config{
 							Rewrites: []*rewrite.Rewrite{
@@ -701,17 +789,31 @@ func (s *k8sStore) ListVirtualService() (l7vs []*v1.VirtualService, l4vs []*v1.V
 }
 
 // ingressIsValid checks if the specified ingress is valid
-func (s *k8sStore) ingressIsValid(ing *extensions.Ingress) bool {
+func (s *k8sStore) ingressIsValid(ing *networkingv1.Ingress) bool {
+	if !s.ingressClassMatched(ing) {
+		logrus.Debugf("ingress %s/%s does not belong to ingress class %s, ignore it", ing.Namespace, ing.Name, s.conf.IngressClass)
+		return false
+	}
 	var endpointKey string
-	if ing.Spec.Backend != nil { // stream
-		endpointKey = fmt.Sprintf("%s/%s", ing.Namespace, ing.Spec.Backend.ServiceName)
+	if ing.Spec.DefaultBackend != nil { // stream
+		if ing.Spec.DefaultBackend.Service == nil {
+			logrus.Debugf("ingress %s/%s has no service backend, ignore it", ing.Namespace, ing.Name)
+			return false
+		}
+		endpointKey = fmt.Sprintf("%s/%s", ing.Namespace, ing.Spec.DefaultBackend.Service.Name)
 	} else { // http
-	Loop:
 		for _, rule := range ing.Spec.Rules {
+			if rule.IngressRuleValue.HTTP == nil {
+				logrus.Debugf("ingress %s/%s has a rule without http paths, ignore it", ing.Namespace, ing.Name)
+				return false
+			}
 			for _, path := range rule.IngressRuleValue.HTTP.Paths {
-				endpointKey = fmt.Sprintf("%s/%s", ing.Namespace, path.Backend.ServiceName)
-				if endpointKey != "" {
-					break Loop
+				if path.Backend.Service == nil {
+					logrus.Debugf("ingress %s/%s has no service backend, ignore it", ing.Namespace, ing.Name)
+					return false
+				}
+				if endpointKey == "" {
+					endpointKey = fmt.Sprintf("%s/%s", ing.Namespace, path.Backend.Service.Name)
 				}
 			}
 		}
@@ -742,6 +844,32 @@ func (s *k8sStore) ingressIsValid(ing *extensions.Ingress) bool {
 	return true
 }
 
+// ingressClassMatched checks if the ingress belongs to the ingress class of the gateway.
+// Ingresses without ingress class are always accepted, which keeps compatible with the
+// ingresses created by the old version of kato.
+func (s *k8sStore) ingressClassMatched(ing *networkingv1.Ingress) bool {
+	if s.conf == nil || s.conf.IngressClass == "" {
+		return true
+	}
+	class := ing.GetAnnotations()[IngressClassAnnotation]
+	if ing.Spec.IngressClassName != nil {
+		class = *ing.Spec.IngressClassName
+	}
+	return class == "" || class == s.conf.IngressClass
+}
+
+// locationPath returns the path of the nginx location for the given ingress path.
+func locationPath(path networkingv1.HTTPIngressPath) string {
+	p := path.Path
+	if p == "" {
+		p = "/"
+	}
+	if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+		return "= " + p
+	}
+	return p
+}
+
 func hasReadyAddresses(endpoints *corev1.Endpoints) bool {
 	for _, ep := range endpoints.Subsets {
 		if len(ep.Addresses) > 0 {
@@ -752,19 +880,21 @@ func hasReadyAddresses(endpoints *corev1.Endpoints) bool {
 }
 
 // GetIngress returns the Ingress matching key.
-func (s *k8sStore) GetIngress(key string) (*extensions.Ingress, error) {
+func (s *k8sStore) GetIngress(key string) (*networkingv1.Ingress, error) {
 	return s.listers.Ingress.ByKey(key)
 }
 
 // ListIngresses returns the list of Ingresses
-func (s *k8sStore) ListIngresses() []*extensions.Ingress {
+func (s *k8sStore) ListIngresses() []*networkingv1.Ingress {
 	// filter ingress rules
-	var ingresses []*extensions.Ingress
+	var ingresses []*networkingv1.Ingress
 	for _, item := range s.listers.Ingress.List() {
-		ing := item.(*extensions.Ingress)
+		ing := item.(*networkingv1.Ingress)
 
 		ingresses = append(ingresses, ing)
 	}
+	// the ingresses converted from the gateway api routes
+	ingresses = append(ingresses, s.routes.listIngresses()...)
 
 	return ingresses
 }
@@ -803,7 +933,7 @@ func (s *k8sStore) Run(stopCh chan struct{}) {
 
 // syncSecrets synchronizes data from all Secrets referenced by the given
 // Ingress with the local store and file system.
-func (s *k8sStore) syncSecrets(ing *extensions.Ingress) {
+func (s *k8sStore) syncSecrets(ing *networkingv1.Ingress) {
 	key := ik8s.MetaNamespaceKey(ing)
 	for _, secrKey := range s.secretIngressMap.getSecretKeys(key) {
 		s.syncSecret(secrKey)
@@ -811,6 +941,15 @@ func (s *k8sStore) syncSecrets(ing *extensions.Ingress) {
 }
 
 func (s *k8sStore) syncSecret(secrKey string) {
+	item, exists, err := s.listers.Secret.GetByKey(secrKey)
+	if err == nil && exists {
+		if secret := item.(*corev1.Secret); secret.Data[auth.SecretKey] != nil {
+			if err := writeAuthFile(secrKey, secret.Data[auth.SecretKey]); err != nil {
+				logrus.Errorf("fail to write htpasswd file: %v", err)
+			}
+			return
+		}
+	}
 	sslCert, err := s.getCertificatePem(secrKey)
 	if err != nil {
 		logrus.Errorf("fail to get certificate pem: %v", err)
@@ -879,6 +1018,22 @@ func (s *k8sStore) getCertificatePem(secrKey string) (*v1.SSLCert, error) {
 	}, nil
 }
 
+// authFile returns the htpasswd file of the basic auth secret
+func authFile(secrKey string) string {
+	return fmt.Sprintf("%s/%s.htpasswd", AuthPath, strings.Replace(secrKey, "/", "-", 1))
+}
+
+func writeAuthFile(secrKey string, content []byte) error {
+	if err := os.MkdirAll(AuthPath, 0777); err != nil {
+		return fmt.Errorf("cant not create directory %s: %v", AuthPath, err)
+	}
+	filename := authFile(secrKey)
+	if err := ioutil.WriteFile(filename, content, 0644); err != nil {
+		return fmt.Errorf("cant not write data to %s: %v", filename, err)
+	}
+	return nil
+}
+
 // GetDefaultBackend returns the default backend
 func (s *k8sStore) GetDefaultBackend() defaults.Backend {
 	return s.GetBackendConfiguration().Backend
@@ -893,10 +1048,10 @@ func (s *k8sStore) GetBackendConfiguration() config.Configuration {
 
 func (s *k8sStore) loopUpdateIngress() {
 	for ipevent := range s.node.IPManager().NeedUpdateGatewayPolicy() {
-		ingress := s.listers.Ingress.List()
+		ingress := s.ListIngresses()
 		for i := range ingress {
-			curIng, ok := ingress[i].(*v1beta1.Ingress)
-			if ok && curIng != nil && s.annotations.Extract(curIng).L4.L4Host == ipevent.IP.String() {
+			curIng := ingress[i]
+			if curIng != nil && s.annotations.Extract(curIng).L4.L4Host == ipevent.IP.String() {
 				s.extractAnnotations(curIng)
 				s.secretIngressMap.update(curIng)
 				s.syncSecrets(curIng)
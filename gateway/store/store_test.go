@@ -6,8 +6,9 @@ import (
 	"testing"
 
 	"github.com/gridworkz/kato/gateway/annotations/parser"
+	v1 "github.com/gridworkz/kato/gateway/v1"
 	api "k8s.io/api/core/v1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,11 +61,61 @@ func TestRbdStore_checkIngress(t *testing.T) {
 	}
 }
 
-func buildIngress() *extensions.Ingress {
-	return &extensions.Ingress{
+func buildIngress() *networkingv1.Ingress {
+	return &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "foobar",
 			Namespace: api.NamespaceDefault,
 		},
 	}
 }
+
+func TestWeightNodes(t *testing.T) {
+	newSource := func(weight int, version string, count int) *poolSource {
+		source := &poolSource{weight: weight}
+		for i := 0; i < count; i++ {
+			source.nodes = append(source.nodes, &v1.Node{Host: fmt.Sprintf("10.0.0.%d", i), Port: 5000, Weight: weight, Version: version})
+		}
+		return source
+	}
+	tests := []struct {
+		name    string
+		sources []*poolSource
+		weights map[string]int
+	}{
+		{
+			name:    "single endpoints",
+			sources: []*poolSource{newSource(1, "stable", 3)},
+			weights: map[string]int{"stable": 1},
+		},
+		{
+			name:    "split by weight of endpoints",
+			sources: []*poolSource{newSource(90, "stable", 3), newSource(10, "canary", 1)},
+			weights: map[string]int{"stable": 3000, "canary": 1000},
+		},
+		{
+			name:    "all the traffic to canary",
+			sources: []*poolSource{newSource(0, "stable", 2), newSource(100, "canary", 1)},
+			weights: map[string]int{"canary": 10000},
+		},
+		{
+			name:    "canary without endpoints",
+			sources: []*poolSource{newSource(80, "stable", 2), newSource(20, "canary", 0)},
+			weights: map[string]int{"stable": 4000},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := weightNodes(tc.sources)
+			for _, node := range nodes {
+				weight, ok := tc.weights[node.Version]
+				if !ok {
+					t.Fatalf("unexpected node of version %s", node.Version)
+				}
+				if node.Weight != weight {
+					t.Errorf("expected weight %d for version %s, but returned %d", weight, node.Version, node.Weight)
+				}
+			}
+		})
+	}
+}
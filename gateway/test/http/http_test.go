@@ -23,7 +23,7 @@ import (
 	"github.com/gridworkz/kato/gateway/controller"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	api_meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -123,23 +123,25 @@ func TestHttpDefault(t *testing.T) {
 	_ = ensureService(service, clientSet, t)
 	time.Sleep(3 * time.Second)
 
-	ingress := &extensions.Ingress{
+	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "default-ing",
 			Namespace: ns.Name,
 		},
-		Spec: extensions.IngressSpec{
-			Rules: []extensions.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
 				{
 					Host: "www.http-router.com",
-					IngressRuleValue: extensions.IngressRuleValue{
-						HTTP: &extensions.HTTPIngressRuleValue{
-							Paths: []extensions.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
 									Path: "/http-router",
-									Backend: extensions.IngressBackend{
-										ServiceName: "default-svc",
-										ServicePort: intstr.FromInt(80),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "default-svc",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
 									},
 								},
 							},
@@ -226,7 +228,7 @@ func TestHttpCookie(t *testing.T) {
 
 	time.Sleep(3 * time.Second)
 
-	ingress := &extensions.Ingress{
+	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "router-cookie-ing",
 			Namespace: ns.Name,
@@ -234,18 +236,20 @@ func TestHttpCookie(t *testing.T) {
 				parser.GetAnnotationWithPrefix("cookie"): "ck1:cv1;ck2:cv2;",
 			},
 		},
-		Spec: extensions.IngressSpec{
-			Rules: []extensions.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
 				{
 					Host: "www.http-router.com",
-					IngressRuleValue: extensions.IngressRuleValue{
-						HTTP: &extensions.HTTPIngressRuleValue{
-							Paths: []extensions.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
 									Path: "/http-router",
-									Backend: extensions.IngressBackend{
-										ServiceName: "router-cookie-svc",
-										ServicePort: intstr.FromInt(80),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "router-cookie-svc",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
 									},
 								},
 							},
@@ -335,7 +339,7 @@ func TestHttpHeader(t *testing.T) {
 
 	time.Sleep(3 * time.Second)
 
-	ingress := &extensions.Ingress{
+	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "router-header-ing",
 			Namespace: ns.Name,
@@ -343,18 +347,20 @@ func TestHttpHeader(t *testing.T) {
 				parser.GetAnnotationWithPrefix("header"): "hk1:hv1;hk2:hv2;",
 			},
 		},
-		Spec: extensions.IngressSpec{
-			Rules: []extensions.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
 				{
 					Host: "www.http-router.com",
-					IngressRuleValue: extensions.IngressRuleValue{
-						HTTP: &extensions.HTTPIngressRuleValue{
-							Paths: []extensions.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
 									Path: "/http-router",
-									Backend: extensions.IngressBackend{
-										ServiceName: "router-header-svc",
-										ServicePort: intstr.FromInt(80),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "router-header-svc",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
 									},
 								},
 							},
@@ -373,7 +379,7 @@ func Test_ListIngress(t *testing.T) {
 		t.Errorf("can't create Kubernetes's client: %v", err)
 	}
 
-	ings, err := clientSet.ExtensionsV1beta1().Ingresses("gateway").List(api_meta_v1.ListOptions{})
+	ings, err := clientSet.NetworkingV1().Ingresses("gateway").List(api_meta_v1.ListOptions{})
 	if err != nil {
 		t.Fatalf("error listing ingresses: %v", err)
 	}
@@ -456,7 +462,7 @@ func TestHttpUpstreamHashBy(t *testing.T) {
 
 	time.Sleep(3 * time.Second)
 
-	ingress := &extensions.Ingress{
+	ingress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "upstreamhashby-ing",
 			Namespace: ns.Name,
@@ -464,18 +470,20 @@ func TestHttpUpstreamHashBy(t *testing.T) {
 				parser.GetAnnotationWithPrefix("upstream-hash-by"): "$request_uri",
 			},
 		},
-		Spec: extensions.IngressSpec{
-			Rules: []extensions.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
 				{
 					Host: "www.http-upstreamhashby.com",
-					IngressRuleValue: extensions.IngressRuleValue{
-						HTTP: &extensions.HTTPIngressRuleValue{
-							Paths: []extensions.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
 									Path: "/",
-									Backend: extensions.IngressBackend{
-										ServiceName: "upstreamhashby-svc",
-										ServicePort: intstr.FromInt(80),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: "upstreamhashby-svc",
+											Port: networkingv1.ServiceBackendPort{Number: 80},
+										},
 									},
 								},
 							},
@@ -552,15 +560,15 @@ func ensureService(service *corev1.Service, clientSet kubernetes.Interface, t *t
 
 }
 
-func ensureIngress(ingress *extensions.Ingress, clientSet kubernetes.Interface, t *testing.T) *extensions.Ingress {
+func ensureIngress(ingress *networkingv1.Ingress, clientSet kubernetes.Interface, t *testing.T) *networkingv1.Ingress {
 	t.Helper()
-	ing, err := clientSet.ExtensionsV1beta1().Ingresses(ingress.Namespace).Update(ingress)
+	ing, err := clientSet.NetworkingV1().Ingresses(ingress.Namespace).Update(ingress)
 
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
 			t.Logf("Ingress %v not found, creating", ingress)
 
-			ing, err = clientSet.ExtensionsV1beta1().Ingresses(ingress.Namespace).Create(ingress)
+			ing, err = clientSet.NetworkingV1().Ingresses(ingress.Namespace).Create(ingress)
 			if err != nil {
 				t.Fatalf("error creating ingress %+v: %v", ingress, err)
 			}
@@ -19,7 +19,11 @@
 package v1
 
 import (
+	"github.com/gridworkz/kato/gateway/annotations/auth"
+	"github.com/gridworkz/kato/gateway/annotations/authreq"
+	"github.com/gridworkz/kato/gateway/annotations/ipaccess"
 	"github.com/gridworkz/kato/gateway/annotations/proxy"
+	"github.com/gridworkz/kato/gateway/annotations/ratelimit"
 	"github.com/gridworkz/kato/gateway/annotations/rewrite"
 )
 
@@ -45,7 +49,18 @@ type Location struct {
 	// Proxy contains information about timeouts and buffer sizes
 	// to be used in connections against endpoints
 	// +optional
-	Proxy            proxy.Config `json:"proxy,omitempty"`
+	Proxy proxy.Config `json:"proxy,omitempty"`
+	// RateLimit limits the request rate and concurrent connections of clients
+	// +optional
+	RateLimit ratelimit.Config `json:"rateLimit,omitempty"`
+	// IPAccess, BasicAuth and ForwardAuth restrict the clients accessing this location
+	// +optional
+	IPAccess    ipaccess.Config `json:"ipAccess,omitempty"`
+	BasicAuth   auth.Config     `json:"basicAuth,omitempty"`
+	ForwardAuth authreq.Config  `json:"forwardAuth,omitempty"`
+	// Denied is true if the access policies of this location are invalid,
+	// all requests are rejected in this case.
+	Denied           bool
 	DisableProxyPass bool
 }
 
@@ -80,6 +95,18 @@ func (l *Location) Equals(c *Location) bool {
 		return false
 	}
 
+	if !l.RateLimit.Equal(&c.RateLimit) {
+		return false
+	}
+
+	if !l.IPAccess.Equal(&c.IPAccess) || !l.BasicAuth.Equal(&c.BasicAuth) || !l.ForwardAuth.Equal(&c.ForwardAuth) {
+		return false
+	}
+
+	if l.Denied != c.Denied {
+		return false
+	}
+
 	return true
 }
 
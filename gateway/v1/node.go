@@ -30,6 +30,7 @@ type Node struct {
 	Weight      int    `json:"weight"`
 	MaxFails    int    `json:"max_fails"`
 	FailTimeout string `json:"fail_timeout"`
+	Version     string `json:"version"` //The deploy version of the service
 }
 
 //Equals -
@@ -70,5 +71,8 @@ func (n *Node) Equals(c *Node) bool { //
 	if n.FailTimeout != c.FailTimeout {
 		return false
 	}
+	if n.Version != c.Version {
+		return false
+	}
 	return true
 }
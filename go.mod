@@ -29,7 +29,7 @@ require (
 	github.com/eapache/channels v1.1.0
 	github.com/emicklei/go-restful v2.14.2+incompatible
 	github.com/emicklei/go-restful-swagger12 v0.0.0-20170926063155-7524189396c6
-	github.com/envoyproxy/go-control-plane v0.9.5
+	github.com/envoyproxy/go-control-plane v0.9.7
 	github.com/fatih/color v1.9.0
 	github.com/fatih/structs v1.1.0
 	github.com/fsnotify/fsnotify v1.4.9
@@ -122,6 +122,7 @@ require (
 	k8s.io/apiserver v0.20.0
 	k8s.io/client-go v0.20.1
 	k8s.io/component-base v0.20.1 // indirect
+	k8s.io/cri-api v0.20.0
 	k8s.io/klog/v2 v2.5.0 // indirect
 	sigs.k8s.io/controller-runtime v0.7.0
 )
@@ -481,9 +481,30 @@ spec:
             probe:
               description: health check probe
               properties:
+                failureThreshold:
+                  description: Minimum consecutive failures for the probe to be considered
+                    failed after having succeeded. Defaults to 3. Minimum value is 1.
+                  format: int32
+                  type: integer
                 httpGet:
                   description: HTTPGet specifies the http request to perform.
                   properties:
+                    expectedStatuses:
+                      description: The ranges of the status codes which are considered
+                        successful. Defaults to 200-399.
+                      items:
+                        description: HTTPStatusRange is a range of HTTP status codes, both
+                          ends are inclusive.
+                        properties:
+                          end:
+                            type: integer
+                          start:
+                            type: integer
+                        required:
+                        - end
+                        - start
+                        type: object
+                      type: array
                     httpHeaders:
                       description: Custom headers to set in the request. HTTP allows
                         repeated headers.
@@ -502,15 +523,44 @@ spec:
                         - value
                         type: object
                       type: array
+                    insecureSkipVerify:
+                      description: InsecureSkipVerify skips the verification of the server
+                        certificate if the scheme is HTTPS.
+                      type: boolean
                     path:
                       description: Path to access on the HTTP server.
                       type: string
+                    port:
+                      description: Port to access on the HTTP server, defaults to the port
+                        of the endpoint.
+                      type: integer
+                    scheme:
+                      description: Scheme to use for connecting to the host, HTTP or HTTPS.
+                        Defaults to HTTP.
+                      type: string
                   type: object
+                periodSeconds:
+                  description: How often (in seconds) to perform the probe. Default to
+                    10 seconds. Minimum value is 1.
+                  format: int32
+                  type: integer
+                successThreshold:
+                  description: Minimum consecutive successes for the probe to be considered
+                    successful after having failed. Defaults to 1. Minimum value is 1.
+                  format: int32
+                  type: integer
                 tcpSocket:
-                  description: 'TCPSocket specifies an action involving a TCP port.
-                    TCP hooks not yet supported TODO: implement a realistic TCP lifecycle
-                    hook'
+                  description: TCPSocket specifies an action involving a TCP port.
+                  properties:
+                    port:
+                      description: Port to connect to, defaults to the port of the endpoint.
+                      type: integer
                   type: object
+                timeoutSeconds:
+                  description: Number of seconds after which the probe times out. Defaults
+                    to 1 second. Minimum value is 1.
+                  format: int32
+                  type: integer
               type: object
           required:
           - endpointSource
@@ -525,6 +575,10 @@ spec:
                   address:
                     description: The address including the port number.
                     type: string
+                  hostname:
+                    description: Hostname the domain name which the address is
+                      resolved from.
+                    type: string
                   reason:
                     description: Reason probe not passed reason
                     type: string
@@ -29,7 +29,9 @@ import (
 	"time"
 
 	"github.com/docker/docker/client"
+	units "github.com/docker/go-units"
 	"github.com/gridworkz/kato/builder"
+	"github.com/gridworkz/kato/builder/cache"
 	"github.com/gridworkz/kato/builder/parser/code"
 	"github.com/gridworkz/kato/builder/sources"
 	"github.com/gridworkz/kato/grctl/clients"
@@ -300,6 +302,27 @@ func NewSourceBuildCmd() cli.Command {
 					},
 				},
 			},
+			cli.Command{
+				Name:  "cache",
+				Usage: "build cache manage",
+				Subcommands: []cli.Command{
+					cli.Command{
+						Name:   "list",
+						Usage:  "list the build caches, the least recently used first",
+						Flags:  buildCacheFlags,
+						Action: listBuildCaches,
+					},
+					cli.Command{
+						Name:  "purge",
+						Usage: "purge the build caches, the caches in use are skipped",
+						Flags: append(buildCacheFlags, cli.BoolFlag{
+							Name:  "all",
+							Usage: "purge the build caches of all tenants",
+						}),
+						Action: purgeBuildCaches,
+					},
+				},
+			},
 		},
 		Name:  "build",
 		Usage: "Commands related to building source code",
@@ -307,6 +330,54 @@ func NewSourceBuildCmd() cli.Command {
 	return c
 }
 
+var buildCacheFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "tenant-id,t",
+		Usage: "the tenant id, all tenants if empty",
+	},
+	cli.StringFlag{
+		Name:  "service-id,s",
+		Usage: "the service id, all services if empty",
+	},
+	cli.StringFlag{
+		Name:  "lang,l",
+		Usage: "the source code lang type, such as Java-maven, Node.js, Python and Go, all lang types if empty",
+	},
+}
+
+func listBuildCaches(c *cli.Context) error {
+	Common(c)
+	entries, err := clients.RegionClient.Builder().ListBuildCaches(c.String("tenant-id"), c.String("service-id"), c.String("lang"))
+	handleErr(err)
+	printBuildCaches(entries)
+	return nil
+}
+
+func purgeBuildCaches(c *cli.Context) error {
+	if c.String("tenant-id") == "" && c.String("service-id") == "" && c.String("lang") == "" && !c.Bool("all") {
+		showError("Please specify the tenant id, service id or lang, or --all to purge all the build caches")
+	}
+	Common(c)
+	entries, err := clients.RegionClient.Builder().PurgeBuildCaches(c.String("tenant-id"), c.String("service-id"), c.String("lang"))
+	handleErr(err)
+	printBuildCaches(entries)
+	fmt.Printf("%d build caches are purged\n", len(entries))
+	return nil
+}
+
+func printBuildCaches(entries []*cache.Entry) {
+	table := termtables.CreateTable()
+	table.AddHeaders("TenantID", "ServiceID", "Lang", "Branches", "Size", "Hits", "Misses", "LastUsed", "InUse")
+	var total int64
+	for _, e := range entries {
+		table.AddRow(e.TenantID, e.ServiceID, e.Lang, strings.Join(e.Branches, ","), units.HumanSize(float64(e.Size)),
+			e.Hits, e.Misses, e.LastUsed.Format(time.RFC3339), e.InUse)
+		total += e.Size
+	}
+	fmt.Print(table.Render())
+	fmt.Printf("Total size: %s\n", units.HumanSize(float64(total)))
+}
+
 func build(c *cli.Context) error {
 	dir := c.String("dir")
 	if dir == "" {
@@ -26,17 +26,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/gosuri/uitable"
+	"github.com/gridworkz/kato/api/region"
 	eventdb "github.com/gridworkz/kato/eventlog/db"
+	"github.com/gridworkz/kato/gateway/annotations/parser"
 	"github.com/gridworkz/kato/grctl/clients"
 	coreutil "github.com/gridworkz/kato/util"
 	"github.com/gridworkz/kato/util/constants"
 	"github.com/gridworkz/kato/util/termtables"
-	"github.com/gorilla/websocket"
-	"github.com/gosuri/uitable"
 	"github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 	"github.com/urfave/cli"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -79,6 +82,26 @@ func NewCmdService() cli.Command {
 					return showServiceDeployInfo(c)
 				},
 			},
+			cli.Command{
+				Name: "watch",
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:     "tenantAlias,t",
+						Value:    "",
+						Usage:    "Specify the tenant alias",
+						FilePath: GetTenantNamePath(),
+					},
+					cli.BoolFlag{
+						Name:  "pods",
+						Usage: "Follow the pod events instead of the status",
+					},
+				},
+				Usage: "Follow application service status or pod events。For example <grctl service watch <service_alias> -t gridworkz --pods>",
+				Action: func(c *cli.Context) error {
+					Common(c)
+					return watchService(c)
+				},
+			},
 			cli.Command{
 				Name:  "start",
 				Usage: "Start an application service, For example <grctl service start gridworkz/gra564a1>",
@@ -334,6 +357,37 @@ func stopService(c *cli.Context) error {
 	fmt.Println("EventID:", eventID)
 	return nil
 }
+func watchService(c *cli.Context) error {
+	serviceAlias := c.Args().First()
+	tenantName := c.String("tenantAlias")
+	info := strings.Split(serviceAlias, "/")
+	if len(info) >= 2 {
+		tenantName = info[0]
+		serviceAlias = info[1]
+	}
+	if tenantName == "" {
+		showError("tenant alias can not be empty")
+	}
+	if serviceAlias == "" {
+		showError("service alias can not be empty")
+	}
+	service := clients.RegionClient.Tenants(tenantName).Services(serviceAlias)
+	if c.Bool("pods") {
+		return service.WatchPods(func(evt *region.WatchEvent) error {
+			fmt.Printf("%s %-8s %s %s %s\n", time.Now().Format(time.RFC3339), strings.ToUpper(evt.Event),
+				gjson.GetBytes(evt.Data, "pod.pod_name").String(),
+				gjson.GetBytes(evt.Data, "pod.pod_status").String(),
+				gjson.GetBytes(evt.Data, "pod.pod_ip").String())
+			return nil
+		})
+	}
+	return service.WatchStatus(func(evt *region.WatchEvent) error {
+		fmt.Printf("%s %-8s %s\n", time.Now().Format(time.RFC3339), strings.ToUpper(evt.Event),
+			gjson.GetBytes(evt.Data, "status").String())
+		return nil
+	})
+}
+
 func showServiceDeployInfo(c *cli.Context) error {
 	serviceAlias := c.Args().First()
 	tenantName := c.String("tenantAlias")
@@ -424,17 +478,18 @@ func showServiceDeployInfo(c *cli.Context) error {
 	}
 	//show ingress
 	ingressTable := termtables.CreateTable()
-	ingressTable.AddHeaders("Name", "Host")
+	ingressTable.AddHeaders("Name", "Host", "RateLimit", "ConnLimit")
 	for ingressID := range deployInfo.Ingresses {
 		if clients.K8SClient != nil {
-			ingress, _ := clients.K8SClient.ExtensionsV1beta1().Ingresses(tenantID).Get(ingressID, metav1.GetOptions{})
+			ingress, _ := clients.K8SClient.NetworkingV1().Ingresses(tenantID).Get(ingressID, metav1.GetOptions{})
 			if ingress != nil {
+				rateLimit, connLimit := ingressLimits(ingress)
 				for _, rule := range ingress.Spec.Rules {
-					ingressTable.AddRow(ingress.Name, rule.Host)
+					ingressTable.AddRow(ingress.Name, rule.Host, rateLimit, connLimit)
 				}
 			}
 		} else {
-			ingressTable.AddRow(ingressID, "-")
+			ingressTable.AddRow(ingressID, "-", "-", "-")
 		}
 	}
 	fmt.Println("------------Ingress------------")
@@ -581,3 +636,20 @@ func showTenantServices(ctx *cli.Context) error {
 	}
 	return nil
 }
+
+// ingressLimits returns the rate limit and connection limit of the ingress, such as 10r/s burst=5 by ip
+func ingressLimits(ing *networkingv1.Ingress) (string, string) {
+	anns := ing.GetAnnotations()
+	key := anns[parser.GetAnnotationWithPrefix("limit-key")]
+	if key == "" {
+		key = "ip"
+	}
+	rateLimit, connLimit := "-", "-"
+	if rps := anns[parser.GetAnnotationWithPrefix("limit-rps")]; rps != "" {
+		rateLimit = fmt.Sprintf("%sr/s burst=%s by %s", rps, anns[parser.GetAnnotationWithPrefix("limit-burst")], key)
+	}
+	if conn := anns[parser.GetAnnotationWithPrefix("limit-connections")]; conn != "" {
+		connLimit = fmt.Sprintf("%s by %s", conn, key)
+	}
+	return rateLimit, connLimit
+}
@@ -0,0 +1,27 @@
+local _M = {}
+
+local REDIRECT_STATUS = { [301] = true, [302] = true, [303] = true, [307] = true, [308] = true }
+
+-- forward sends a subrequest to the internal location which proxies to the external auth service.
+-- The request is allowed if the auth service returns 2xx, the status and the auth headers of
+-- 401, 403 and 3xx responses are returned to the client, and other responses are treated as errors.
+function _M.forward(location)
+  local res = ngx.location.capture(location, { method = ngx.HTTP_GET })
+  if res.status >= ngx.HTTP_OK and res.status < ngx.HTTP_SPECIAL_RESPONSE then
+    return
+  end
+
+  if res.status == ngx.HTTP_UNAUTHORIZED or res.status == ngx.HTTP_FORBIDDEN then
+    ngx.header["WWW-Authenticate"] = res.header["WWW-Authenticate"]
+    return ngx.exit(res.status)
+  end
+
+  if REDIRECT_STATUS[res.status] and res.header["Location"] then
+    return ngx.redirect(res.header["Location"], res.status)
+  end
+
+  ngx.log(ngx.ERR, "unexpected status ", res.status, " from the forward auth ", location)
+  return ngx.exit(ngx.HTTP_INTERNAL_SERVER_ERROR)
+end
+
+return _M
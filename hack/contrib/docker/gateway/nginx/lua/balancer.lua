@@ -6,6 +6,7 @@ local sticky = require("balancer.sticky")
 local ewma = require("balancer.ewma")
 local json = require("cjson")
 local config = require("config")
+local util = require("util")
 
 local DEFAULT_LB_ALG = "round_robin"
 local IMPLEMENTATIONS = {
@@ -18,6 +19,8 @@ local IMPLEMENTATIONS = {
 local _M = {}
 -- save all backend balancer data
 local balancers = {}
+-- save the deploy version of every backend endpoint, used by the metrics of canary release
+local versions = {}
 
 -- measured in seconds
 -- for an Nginx worker to pick up the new list of upstream peers
@@ -50,8 +53,20 @@ local function get_balancer()
   return balancers[backend_name]
 end
 
+-- sync_versions maps the endpoints of backend to their deploy version
+local function sync_versions(backend)
+  local endpoint_versions = {}
+  for _, endpoint in ipairs(backend.endpoints or {}) do
+    if endpoint.version and endpoint.version ~= "" then
+      endpoint_versions[util.format_endpoint(endpoint.address, endpoint.port)] = endpoint.version
+    end
+  end
+  versions[backend.name] = endpoint_versions
+end
+
 --  sync_backend sync define backend data 
 local function sync_backend(backend)
+  sync_versions(backend)
   local implementation = get_implementation(backend)
   local balancer = balancers[backend.name]
 
@@ -77,6 +92,7 @@ local function sync_backends()
   local backends_data = config.get_backends_data()
   if not backends_data then
     balancers = {}
+    versions = {}
     return
   end
 
@@ -95,6 +111,7 @@ local function sync_backends()
   for backend_name, _ in pairs(balancers) do
     if not balancers_to_keep[backend_name] then
       balancers[backend_name] = nil
+      versions[backend_name] = nil
     end
   end
 end
@@ -127,6 +144,11 @@ function _M.balance()
     return
   end
 
+  local endpoint_versions = versions[ngx.var.target]
+  if endpoint_versions then
+    ngx.ctx.backend_version = endpoint_versions[peer]
+  end
+
   ngx_balancer.set_more_tries(1)
 
   local ok, err = ngx_balancer.set_current_peer(peer)
@@ -88,7 +88,7 @@ end
 local function score(upstream)
   -- Original implementation used names
   -- Endpoints don't have names, so passing in IP:Port as key instead
-  local upstream_name = upstream.address .. ":" .. upstream.port
+  local upstream_name = util.format_endpoint(upstream.address, upstream.port)
   return get_or_update_ewma(upstream_name, 0, false)
 end
 
@@ -129,7 +129,7 @@ function _M.balance(self)
   end
 
   -- TODO(elvinefendi) move this processing to _M.sync
-  return endpoint.address .. ":" .. endpoint.port
+  return util.format_endpoint(endpoint.address, endpoint.port)
 end
 
 function _M.after_balance(_)
@@ -34,6 +34,8 @@ local function metrics()
     upstreamLatency = tonumber(ngx.var.upstream_connect_time) or -1,
     upstreamResponseTime = tonumber(ngx.var.upstream_response_time) or -1,
     upstreamResponseLength = tonumber(ngx.var.upstream_response_length) or -1,
+    rejected = ngx.ctx.limit_rejected or "",
+    version = ngx.ctx.backend_version or "-",
     --upstreamStatus = ngx.var.upstream_status or "-",
   }
 end
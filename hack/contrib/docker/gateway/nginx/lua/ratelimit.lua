@@ -0,0 +1,87 @@
+local limit_req = require("resty.limit.req")
+local limit_conn = require("resty.limit.conn")
+
+local REQ_DICT = "kato_limit_req"
+local CONN_DICT = "kato_limit_conn"
+-- the initial guess of the request duration used by limit_conn, it is adjusted in the log phase
+local CONN_DEFAULT_DELAY = 0.5
+
+local _M = {}
+
+local function client_key(conf)
+  local key = ngx.var.remote_addr
+  if conf.header then
+    local val = ngx.req.get_headers()[conf.header]
+    if type(val) == "table" then
+      val = val[1]
+    end
+    -- requests without the header are limited by the client ip
+    if val and val ~= "" then
+      key = val
+    end
+  end
+  return conf.zone .. ":" .. key
+end
+
+local function reject(reason)
+  ngx.ctx.limit_rejected = reason
+  return ngx.exit(ngx.HTTP_TOO_MANY_REQUESTS)
+end
+
+-- access limits the request rate and the concurrent connections of the client,
+-- the requests exceeding the limits are rejected with 429.
+function _M.access(conf)
+  local key = client_key(conf)
+
+  if conf.rps > 0 then
+    local lim, err = limit_req.new(REQ_DICT, conf.rps, conf.burst)
+    if not lim then
+      ngx.log(ngx.ERR, "failed to instantiate a resty.limit.req object: ", err)
+    else
+      local delay, err = lim:incoming(key, true)
+      if not delay then
+        if err == "rejected" then
+          return reject("rate")
+        end
+        ngx.log(ngx.ERR, "failed to limit req: ", err)
+      elseif delay >= 0.001 then
+        ngx.sleep(delay)
+      end
+    end
+  end
+
+  if conf.conn > 0 then
+    local lim, err = limit_conn.new(CONN_DICT, conf.conn, 0, CONN_DEFAULT_DELAY)
+    if not lim then
+      ngx.log(ngx.ERR, "failed to instantiate a resty.limit.conn object: ", err)
+      return
+    end
+    local delay, err = lim:incoming(key, true)
+    if not delay then
+      if err == "rejected" then
+        return reject("connection")
+      end
+      ngx.log(ngx.ERR, "failed to limit conn: ", err)
+      return
+    end
+    if lim:is_committed() then
+      ngx.ctx.limit_conn = lim
+      ngx.ctx.limit_conn_key = key
+    end
+  end
+end
+
+-- log releases the connection counted in the access phase.
+function _M.log()
+  local lim = ngx.ctx.limit_conn
+  if not lim then
+    return
+  end
+  local latency = tonumber(ngx.var.request_time) or 0
+  local _, err = lim:leaving(ngx.ctx.limit_conn_key, latency)
+  if err then
+    ngx.log(ngx.ERR, "failed to record the connection leaving request: ", err)
+  end
+end
+
+return _M
@@ -47,7 +47,8 @@ local function format_ipv6_endpoints(endpoints)
   local formatted_endpoints = {}
   for _, endpoint in ipairs(endpoints) do
     local formatted_endpoint = endpoint
-    if not endpoint.address:match("^%d+.%d+.%d+.%d+$") then
+    -- only IPv6 addresses contain colons, the host names are kept as they are
+    if endpoint.address:find(":", 1, true) and endpoint.address:sub(1, 1) ~= "[" then
       formatted_endpoint.address = string.format("[%s]", endpoint.address)
     end
     table.insert(formatted_endpoints, formatted_endpoint)
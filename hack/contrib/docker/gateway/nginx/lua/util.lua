@@ -6,11 +6,19 @@ local resty_md5 = require("resty.md5")
 
 local _M = {}
 
+-- format_endpoint returns address:port, the IPv6 address is enclosed in brackets
+function _M.format_endpoint(address, port)
+  if string.find(address, ":", 1, true) and string_sub(address, 1, 1) ~= "[" then
+    return "[" .. address .. "]:" .. port
+  end
+  return address .. ":" .. port
+end
+
 function _M.get_nodes(endpoints)
   local nodes = {}
 
   for _, endpoint in pairs(endpoints) do
-    local endpoint_string = endpoint.address .. ":" .. endpoint.port
+    local endpoint_string = _M.format_endpoint(endpoint.address, endpoint.port)
     nodes[endpoint_string] = endpoint.weight
   end
 
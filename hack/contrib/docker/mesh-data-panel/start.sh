@@ -6,10 +6,16 @@ if [ "$1" = "bash" ];then
 elif [ "$1" = "version" ];then
     echo /root/kato-mesh-data-panel version
 else
-    env2file conversion -f /root/envoy_config.yaml
+    # XDS_API_VERSION is set by the worker to the xds api version the node serves this sidecar on,
+    # v2 only if the plugin config pins it and mutual tls is disabled
+    envoy_config=/root/envoy_config_v3.yaml
+    if [ "${XDS_API_VERSION}" = "v2" ];then
+        envoy_config=/root/envoy_config.yaml
+    fi
+    env2file conversion -f ${envoy_config}
     cluster_name=${TENANT_ID}_${PLUGIN_ID}_${SERVICE_NAME}
     # start sidecar process
     /root/kato-mesh-data-panel&
     # start envoy process
-    exec envoy -c /root/envoy_config.yaml --service-cluster ${cluster_name} --service-node ${cluster_name}     
+    exec envoy -c ${envoy_config} --service-cluster ${cluster_name} --service-node ${cluster_name}
 fi
@@ -172,11 +172,16 @@ func (u *MQSource) dequeue(request *restful.Request, response *restful.Response)
 	}
 	ctx, cancel := context.WithCancel(request.Request.Context())
 	defer cancel()
-	value, err := u.mq.Dequeue(ctx, topic)
+	message, err := u.mq.Dequeue(ctx, topic)
 	if err != nil {
 		NewFaliResponse(500, "dequeue error."+err.Error(), "Message out of queue error", response)
 		return
 	}
+	// the http api can not acknowledge the message, so it is acknowledged once it is dequeued
+	if err := u.mq.Ack(topic, message.Receipt); err != nil {
+		logrus.Warningf("ack message %s: %v", message.Receipt, err)
+	}
+	value := message.Body
 	task, err := discovermodel.NewTask([]byte(value))
 	if err != nil {
 		NewFaliResponse(500, "dequeue error."+err.Error(), "The queue read message format is illegal", response)
@@ -23,11 +23,14 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type TaskMessage struct {
-	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	TaskType             string   `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
-	TaskBody             []byte   `protobuf:"bytes,3,opt,name=task_body,json=taskBody,proto3" json:"task_body,omitempty"`
-	CreateTime           string   `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
-	User                 string   `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	TaskId     string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	TaskType   string `protobuf:"bytes,2,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	TaskBody   []byte `protobuf:"bytes,3,opt,name=task_body,json=taskBody,proto3" json:"task_body,omitempty"`
+	CreateTime string `protobuf:"bytes,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	User       string `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	// receipt is used to acknowledge the message, it is empty if the backend does not need acknowledgements
+	Receipt              string   `protobuf:"bytes,6,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	RedeliveryCount      int32    `protobuf:"varint,7,opt,name=redelivery_count,json=redeliveryCount,proto3" json:"redelivery_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -93,6 +96,20 @@ func (m *TaskMessage) GetUser() string {
 	return ""
 }
 
+func (m *TaskMessage) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+func (m *TaskMessage) GetRedeliveryCount() int32 {
+	if m != nil {
+		return m.RedeliveryCount
+	}
+	return 0
+}
+
 type EnqueueRequest struct {
 	Topic                string       `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
 	Message              *TaskMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
@@ -273,39 +290,225 @@ func (m *TopicRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_TopicRequest proto.InternalMessageInfo
 
+type AckRequest struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Receipt              string   `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AckRequest) Reset()         { *m = AckRequest{} }
+func (m *AckRequest) String() string { return proto.CompactTextString(m) }
+func (*AckRequest) ProtoMessage()    {}
+func (*AckRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{5}
+}
+
+func (m *AckRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AckRequest.Unmarshal(m, b)
+}
+func (m *AckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AckRequest.Marshal(b, m, deterministic)
+}
+func (m *AckRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AckRequest.Merge(m, src)
+}
+func (m *AckRequest) XXX_Size() int {
+	return xxx_messageInfo_AckRequest.Size(m)
+}
+func (m *AckRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AckRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AckRequest proto.InternalMessageInfo
+
+func (m *AckRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *AckRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
+type DeadLetterRequest struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeadLetterRequest) Reset()         { *m = DeadLetterRequest{} }
+func (m *DeadLetterRequest) String() string { return proto.CompactTextString(m) }
+func (*DeadLetterRequest) ProtoMessage()    {}
+func (*DeadLetterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{6}
+}
+
+func (m *DeadLetterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeadLetterRequest.Unmarshal(m, b)
+}
+func (m *DeadLetterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeadLetterRequest.Marshal(b, m, deterministic)
+}
+func (m *DeadLetterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeadLetterRequest.Merge(m, src)
+}
+func (m *DeadLetterRequest) XXX_Size() int {
+	return xxx_messageInfo_DeadLetterRequest.Size(m)
+}
+func (m *DeadLetterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeadLetterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeadLetterRequest proto.InternalMessageInfo
+
+func (m *DeadLetterRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+type DeadLetterReply struct {
+	Messages             []*TaskMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *DeadLetterReply) Reset()         { *m = DeadLetterReply{} }
+func (m *DeadLetterReply) String() string { return proto.CompactTextString(m) }
+func (*DeadLetterReply) ProtoMessage()    {}
+func (*DeadLetterReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{7}
+}
+
+func (m *DeadLetterReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeadLetterReply.Unmarshal(m, b)
+}
+func (m *DeadLetterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeadLetterReply.Marshal(b, m, deterministic)
+}
+func (m *DeadLetterReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeadLetterReply.Merge(m, src)
+}
+func (m *DeadLetterReply) XXX_Size() int {
+	return xxx_messageInfo_DeadLetterReply.Size(m)
+}
+func (m *DeadLetterReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeadLetterReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeadLetterReply proto.InternalMessageInfo
+
+func (m *DeadLetterReply) GetMessages() []*TaskMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+type RequeueRequest struct {
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// requeue all the dead letters of the topic if receipt is empty
+	Receipt              string   `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RequeueRequest) Reset()         { *m = RequeueRequest{} }
+func (m *RequeueRequest) String() string { return proto.CompactTextString(m) }
+func (*RequeueRequest) ProtoMessage()    {}
+func (*RequeueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{8}
+}
+
+func (m *RequeueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RequeueRequest.Unmarshal(m, b)
+}
+func (m *RequeueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RequeueRequest.Marshal(b, m, deterministic)
+}
+func (m *RequeueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RequeueRequest.Merge(m, src)
+}
+func (m *RequeueRequest) XXX_Size() int {
+	return xxx_messageInfo_RequeueRequest.Size(m)
+}
+func (m *RequeueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RequeueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RequeueRequest proto.InternalMessageInfo
+
+func (m *RequeueRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *RequeueRequest) GetReceipt() string {
+	if m != nil {
+		return m.Receipt
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*TaskMessage)(nil), "pb.TaskMessage")
 	proto.RegisterType((*EnqueueRequest)(nil), "pb.EnqueueRequest")
 	proto.RegisterType((*DequeueRequest)(nil), "pb.DequeueRequest")
 	proto.RegisterType((*TaskReply)(nil), "pb.TaskReply")
 	proto.RegisterType((*TopicRequest)(nil), "pb.TopicRequest")
+	proto.RegisterType((*AckRequest)(nil), "pb.AckRequest")
+	proto.RegisterType((*DeadLetterRequest)(nil), "pb.DeadLetterRequest")
+	proto.RegisterType((*DeadLetterReply)(nil), "pb.DeadLetterReply")
+	proto.RegisterType((*RequeueRequest)(nil), "pb.RequeueRequest")
 }
 
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 336 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xcb, 0x4e, 0xc3, 0x30,
-	0x10, 0x45, 0x9b, 0x3e, 0x12, 0x32, 0x7d, 0x80, 0x46, 0x08, 0xac, 0xb2, 0xa0, 0xf2, 0xaa, 0x08,
-	0x29, 0x42, 0xe5, 0x0f, 0x10, 0x08, 0x58, 0xb0, 0x68, 0x14, 0xd6, 0x55, 0xd2, 0x8c, 0x20, 0xea,
-	0xc3, 0x6e, 0xed, 0x2c, 0xf2, 0x21, 0x6c, 0xf8, 0x5a, 0x64, 0x3b, 0xad, 0x5a, 0xd8, 0xb0, 0xf3,
-	0xcc, 0xcd, 0xcc, 0x9c, 0x7b, 0x15, 0xe8, 0xaf, 0x48, 0xa9, 0xf4, 0x83, 0x22, 0xb9, 0x15, 0x5a,
-	0x60, 0x53, 0x66, 0xfc, 0xcb, 0x83, 0x6e, 0x92, 0xaa, 0xc5, 0x9b, 0x53, 0xf0, 0x12, 0x02, 0x9d,
-	0xaa, 0xc5, 0xac, 0xc8, 0x99, 0x37, 0xf2, 0xc6, 0x61, 0xec, 0x9b, 0xf2, 0x35, 0xc7, 0x2b, 0x08,
-	0xad, 0xa0, 0x2b, 0x49, 0xac, 0x69, 0xa5, 0x13, 0xd3, 0x48, 0x2a, 0x49, 0x7b, 0x31, 0x13, 0x79,
-	0xc5, 0x5a, 0x23, 0x6f, 0xdc, 0x73, 0xe2, 0x83, 0xc8, 0x2b, 0xbc, 0x86, 0xee, 0x7c, 0x4b, 0xa9,
-	0xa6, 0x99, 0x2e, 0x56, 0xc4, 0xda, 0x76, 0x16, 0x5c, 0x2b, 0x29, 0x56, 0x84, 0x08, 0xed, 0x52,
-	0xd1, 0x96, 0x75, 0xac, 0x62, 0xdf, 0x7c, 0x0a, 0x83, 0xa7, 0xf5, 0xa6, 0xa4, 0x92, 0x62, 0xda,
-	0x94, 0xa4, 0x34, 0x9e, 0x43, 0x47, 0x0b, 0x59, 0xcc, 0x6b, 0x2e, 0x57, 0xe0, 0x0d, 0x04, 0xb5,
-	0x29, 0x0b, 0xd5, 0x9d, 0x9c, 0x46, 0x32, 0x8b, 0x0e, 0x1c, 0xc5, 0x3b, 0x9d, 0x3f, 0xc3, 0xe0,
-	0x91, 0xfe, 0xb1, 0xd2, 0xf0, 0x2e, 0x0b, 0x5a, 0xeb, 0xd9, 0xa7, 0x50, 0xba, 0xf6, 0x0a, 0xae,
-	0xf5, 0x22, 0x94, 0xe6, 0xef, 0x10, 0x9a, 0x03, 0x31, 0xc9, 0x65, 0x85, 0x17, 0xe0, 0x2b, 0x9d,
-	0xea, 0x52, 0xed, 0xf2, 0x72, 0x15, 0xb2, 0x63, 0xb0, 0x70, 0xcf, 0x61, 0x26, 0xec, 0x21, 0xc5,
-	0x5a, 0xa3, 0x96, 0x4d, 0xd8, 0x56, 0x7c, 0x00, 0xbd, 0xc4, 0xbc, 0x6a, 0xba, 0xc9, 0xb7, 0xe7,
-	0xee, 0x4c, 0x0d, 0x32, 0x46, 0x10, 0xd4, 0x81, 0x20, 0x1a, 0x8b, 0xc7, 0xe9, 0x0c, 0xfb, 0x3b,
-	0xdb, 0x96, 0x8a, 0x37, 0xf0, 0x16, 0x7c, 0xbb, 0x4d, 0xe1, 0x99, 0x95, 0x0e, 0x36, 0xff, 0xfd,
-	0xf8, 0x0e, 0x82, 0x3a, 0x1a, 0xb7, 0xfc, 0x38, 0xa7, 0xe1, 0xef, 0x4c, 0x79, 0x23, 0xf3, 0xed,
-	0x2f, 0x74, 0xff, 0x13, 0x00, 0x00, 0xff, 0xff, 0xa0, 0x5f, 0x1b, 0x28, 0x53, 0x02, 0x00, 0x00,
+	// 475 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc7, 0xeb, 0xb8, 0xb1, 0x9b, 0x49, 0xeb, 0x94, 0xe1, 0x6b, 0x15, 0x0e, 0x44, 0x7b, 0x40,
+	0x89, 0x2a, 0x59, 0xa8, 0x1c, 0x41, 0x88, 0x42, 0x10, 0x20, 0xc1, 0xa1, 0x56, 0x38, 0x47, 0x8e,
+	0x3d, 0x02, 0x2b, 0x1f, 0xde, 0x7a, 0xd7, 0x48, 0x7e, 0x2a, 0xde, 0x87, 0xa7, 0x41, 0xbb, 0xeb,
+	0x24, 0x4e, 0x02, 0x55, 0x6e, 0x9e, 0xf9, 0xef, 0xce, 0xfc, 0xe7, 0x37, 0x2b, 0xc3, 0xc5, 0x92,
+	0xa4, 0x8c, 0x7f, 0x50, 0x28, 0x8a, 0x5c, 0xe5, 0xd8, 0x12, 0x33, 0xfe, 0xc7, 0x81, 0xee, 0x24,
+	0x96, 0xf3, 0x6f, 0x56, 0xc1, 0xa7, 0xe0, 0xab, 0x58, 0xce, 0xa7, 0x59, 0xca, 0x9c, 0x81, 0x33,
+	0xec, 0x44, 0x9e, 0x0e, 0xbf, 0xa4, 0xf8, 0x0c, 0x3a, 0x46, 0x50, 0x95, 0x20, 0xd6, 0x32, 0xd2,
+	0x99, 0x4e, 0x4c, 0x2a, 0x41, 0x1b, 0x71, 0x96, 0xa7, 0x15, 0x73, 0x07, 0xce, 0xf0, 0xdc, 0x8a,
+	0xef, 0xf3, 0xb4, 0xc2, 0xe7, 0xd0, 0x4d, 0x0a, 0x8a, 0x15, 0x4d, 0x55, 0xb6, 0x24, 0x76, 0x6a,
+	0xee, 0x82, 0x4d, 0x4d, 0xb2, 0x25, 0x21, 0xc2, 0x69, 0x29, 0xa9, 0x60, 0x6d, 0xa3, 0x98, 0x6f,
+	0x64, 0xe0, 0x17, 0x94, 0x50, 0x26, 0x14, 0xf3, 0x4c, 0x7a, 0x1d, 0xe2, 0x08, 0x2e, 0x0b, 0x4a,
+	0x69, 0x91, 0xfd, 0xa2, 0xa2, 0x9a, 0x26, 0x79, 0xb9, 0x52, 0xcc, 0x1f, 0x38, 0xc3, 0x76, 0xd4,
+	0xdb, 0xe6, 0x3f, 0xe8, 0x34, 0xbf, 0x85, 0xe0, 0xe3, 0xea, 0xae, 0xa4, 0x92, 0x22, 0xba, 0x2b,
+	0x49, 0x2a, 0x7c, 0x04, 0x6d, 0x95, 0x8b, 0x2c, 0xa9, 0x87, 0xb3, 0x01, 0x8e, 0xc0, 0xaf, 0xc9,
+	0x98, 0xc9, 0xba, 0xd7, 0xbd, 0x50, 0xcc, 0xc2, 0x06, 0x96, 0x68, 0xad, 0xf3, 0x4f, 0x10, 0x8c,
+	0xe9, 0x88, 0x92, 0x7a, 0xe8, 0x45, 0x46, 0x2b, 0x35, 0xfd, 0x99, 0x4b, 0x55, 0x03, 0x03, 0x9b,
+	0xfa, 0x9c, 0x4b, 0xc5, 0xbf, 0x43, 0x47, 0x37, 0x88, 0x48, 0x2c, 0x2a, 0x7c, 0x02, 0x9e, 0x54,
+	0xb1, 0x2a, 0xe5, 0x1a, 0xba, 0x8d, 0x34, 0x85, 0xa6, 0xb1, 0xce, 0xc6, 0x87, 0xbe, 0x61, 0x1a,
+	0x49, 0xe6, 0x0e, 0x5c, 0xb3, 0x26, 0x13, 0xf1, 0x00, 0xce, 0x27, 0xfa, 0xab, 0x76, 0xc7, 0xdf,
+	0x00, 0xdc, 0x24, 0xf3, 0xfb, 0xbd, 0x36, 0x58, 0xb7, 0x76, 0x58, 0xf3, 0x11, 0x3c, 0x18, 0x53,
+	0x9c, 0x7e, 0x25, 0xa5, 0xa8, 0xb8, 0xb7, 0x08, 0x7f, 0x0b, 0xbd, 0xe6, 0x51, 0x3d, 0xd5, 0x15,
+	0x9c, 0xd5, 0x76, 0xf5, 0x5c, 0xee, 0xbf, 0xb8, 0x6e, 0x0e, 0xf0, 0x77, 0x10, 0x44, 0xc7, 0x80,
+	0xfd, 0xaf, 0xd9, 0xeb, 0xdf, 0x2d, 0x8b, 0xf4, 0x56, 0x17, 0xc1, 0x10, 0xfc, 0x7a, 0xf7, 0x88,
+	0xba, 0xeb, 0xee, 0x43, 0xe8, 0x5f, 0xac, 0x9d, 0x18, 0xab, 0xfc, 0x04, 0xaf, 0xc0, 0x33, 0xe0,
+	0x24, 0x5e, 0x1a, 0xa9, 0x01, 0xf1, 0xf0, 0xf0, 0x4b, 0xf0, 0xc7, 0xd4, 0x28, 0xbe, 0xfb, 0x24,
+	0xfa, 0xfb, 0x63, 0xf2, 0x13, 0x7c, 0x01, 0xee, 0x4d, 0x32, 0xc7, 0x40, 0x2b, 0xdb, 0x85, 0x1c,
+	0x56, 0x7e, 0x0d, 0xdd, 0x2d, 0x46, 0x89, 0x8f, 0x6d, 0xf5, 0xbd, 0x15, 0xf4, 0x1f, 0xee, 0xa7,
+	0xed, 0xe5, 0x10, 0xfc, 0xa8, 0x69, 0x6b, 0x17, 0xe8, 0x41, 0xb3, 0x99, 0x67, 0xfe, 0x03, 0xaf,
+	0xfe, 0x0e, 0x00, 0x27, 0x93, 0x45, 0xb8, 0x18, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -323,6 +526,9 @@ type TaskQueueClient interface {
 	Enqueue(ctx context.Context, in *EnqueueRequest, opts ...grpc.CallOption) (*TaskReply, error)
 	Topics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TaskReply, error)
 	Dequeue(ctx context.Context, in *DequeueRequest, opts ...grpc.CallOption) (*TaskMessage, error)
+	Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*TaskReply, error)
+	DeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error)
+	Requeue(ctx context.Context, in *RequeueRequest, opts ...grpc.CallOption) (*TaskReply, error)
 }
 
 type taskQueueClient struct {
@@ -360,11 +566,41 @@ func (c *taskQueueClient) Dequeue(ctx context.Context, in *DequeueRequest, opts
 	return out, nil
 }
 
+func (c *taskQueueClient) Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*TaskReply, error) {
+	out := new(TaskReply)
+	err := c.cc.Invoke(ctx, "/pb.TaskQueue/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskQueueClient) DeadLetters(ctx context.Context, in *DeadLetterRequest, opts ...grpc.CallOption) (*DeadLetterReply, error) {
+	out := new(DeadLetterReply)
+	err := c.cc.Invoke(ctx, "/pb.TaskQueue/DeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskQueueClient) Requeue(ctx context.Context, in *RequeueRequest, opts ...grpc.CallOption) (*TaskReply, error) {
+	out := new(TaskReply)
+	err := c.cc.Invoke(ctx, "/pb.TaskQueue/Requeue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaskQueueServer is the server API for TaskQueue service.
 type TaskQueueServer interface {
 	Enqueue(context.Context, *EnqueueRequest) (*TaskReply, error)
 	Topics(context.Context, *TopicRequest) (*TaskReply, error)
 	Dequeue(context.Context, *DequeueRequest) (*TaskMessage, error)
+	Ack(context.Context, *AckRequest) (*TaskReply, error)
+	DeadLetters(context.Context, *DeadLetterRequest) (*DeadLetterReply, error)
+	Requeue(context.Context, *RequeueRequest) (*TaskReply, error)
 }
 
 func RegisterTaskQueueServer(s *grpc.Server, srv TaskQueueServer) {
@@ -425,6 +661,60 @@ func _TaskQueue_Dequeue_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaskQueue_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskQueueServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TaskQueue/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskQueueServer).Ack(ctx, req.(*AckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskQueue_DeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskQueueServer).DeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TaskQueue/DeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskQueueServer).DeadLetters(ctx, req.(*DeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskQueue_Requeue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskQueueServer).Requeue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TaskQueue/Requeue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskQueueServer).Requeue(ctx, req.(*RequeueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TaskQueue_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.TaskQueue",
 	HandlerType: (*TaskQueueServer)(nil),
@@ -441,6 +731,18 @@ var _TaskQueue_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Dequeue",
 			Handler:    _TaskQueue_Dequeue_Handler,
 		},
+		{
+			MethodName: "Ack",
+			Handler:    _TaskQueue_Ack_Handler,
+		},
+		{
+			MethodName: "DeadLetters",
+			Handler:    _TaskQueue_DeadLetters_Handler,
+		},
+		{
+			MethodName: "Requeue",
+			Handler:    _TaskQueue_Requeue_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "message.proto",
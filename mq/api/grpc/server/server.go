@@ -45,6 +45,9 @@ func (s *mqServer) Enqueue(ctx context.Context, in *pb.EnqueueRequest) (*pb.Task
 	if in.Message.TaskId == "" {
 		in.Message.TaskId = util.NewUUID()
 	}
+	// the message may be sent back by the consumer, the receipt of the last delivery is out of date
+	in.Message.Receipt = ""
+	in.Message.RedeliveryCount = 0
 	message, err := proto.Marshal(in.Message)
 	if err != nil {
 		return nil, err
@@ -78,14 +81,61 @@ func (s *mqServer) Dequeue(ctx context.Context, in *pb.DequeueRequest) (*pb.Task
 		return nil, err
 	}
 	var task pb.TaskMessage
-	err = proto.Unmarshal([]byte(message), &task)
+	err = proto.Unmarshal([]byte(message.Body), &task)
 	if err != nil {
 		return nil, err
 	}
+	task.Receipt = message.Receipt
+	task.RedeliveryCount = int32(message.Redelivered)
 	logrus.Debugf("task (%s) dnqueue by (%s).", task.GetTaskType(), in.ClientHost)
 	return &task, nil
 }
 
+func (s *mqServer) Ack(ctx context.Context, in *pb.AckRequest) (*pb.TaskReply, error) {
+	if err := s.actionMQ.Ack(in.Topic, in.Receipt); err != nil {
+		return nil, err
+	}
+	return &pb.TaskReply{
+		Status: "success",
+	}, nil
+}
+
+func (s *mqServer) DeadLetters(ctx context.Context, in *pb.DeadLetterRequest) (*pb.DeadLetterReply, error) {
+	if in.Topic == "" || !s.actionMQ.TopicIsExist(in.Topic) {
+		return nil, fmt.Errorf("topic %s is not support", in.Topic)
+	}
+	messages, err := s.actionMQ.DeadLetters(in.Topic)
+	if err != nil {
+		return nil, err
+	}
+	var reply pb.DeadLetterReply
+	for _, message := range messages {
+		var task pb.TaskMessage
+		if err := proto.Unmarshal([]byte(message.Body), &task); err != nil {
+			logrus.Warningf("dead letter %s is not a task message: %v", message.Receipt, err)
+		}
+		task.Receipt = message.Receipt
+		task.RedeliveryCount = int32(message.Redelivered)
+		reply.Messages = append(reply.Messages, &task)
+	}
+	return &reply, nil
+}
+
+func (s *mqServer) Requeue(ctx context.Context, in *pb.RequeueRequest) (*pb.TaskReply, error) {
+	if in.Topic == "" || !s.actionMQ.TopicIsExist(in.Topic) {
+		return nil, fmt.Errorf("topic %s is not support", in.Topic)
+	}
+	count, err := s.actionMQ.Requeue(in.Topic, in.Receipt)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("%d dead letters of topic %s are requeued", count, in.Topic)
+	return &pb.TaskReply{
+		Status:  "success",
+		Message: fmt.Sprintf("%d messages requeued", count),
+	}, nil
+}
+
 //RegisterServer
 func RegisterServer(server *grpc1.Server, actionMQ mq.ActionMQ) {
 	pb.RegisterTaskQueueServer(server, &mqServer{actionMQ})
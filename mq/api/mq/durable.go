@@ -0,0 +1,417 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mq
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gridworkz/kato/cmd/mq/option"
+	"github.com/gridworkz/kato/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// RedeliveryNumber the number of the messages redelivered after the visibility timeout
+var RedeliveryNumber float64 = 0
+
+// DeadLetterNumber the number of the messages moved to the dead letter topics
+var DeadLetterNumber float64 = 0
+
+//compactThreshold the message log is compacted if the records are more than the threshold
+//and twice the live messages
+const compactThreshold = 1000
+
+type durableMessage struct {
+	id          string
+	topic       string
+	origin      string
+	body        []byte
+	seq         uint64
+	enqueueTime time.Time
+	deliveries  int
+	deadline    time.Time
+}
+
+func (m *durableMessage) message() *Message {
+	redelivered := m.deliveries - 1
+	if redelivered < 0 {
+		redelivered = 0
+	}
+	return &Message{
+		Receipt:     m.id,
+		Topic:       m.topic,
+		Body:        string(m.body),
+		Redelivered: redelivered,
+		EnqueueTime: m.enqueueTime,
+	}
+}
+
+type durableTopic struct {
+	// ready the messages waiting to be delivered, ordered by the sequence
+	ready    []*durableMessage
+	inflight map[string]*durableMessage
+	// wait is closed when there are new ready messages
+	wait chan struct{}
+}
+
+func (t *durableTopic) push(m *durableMessage) {
+	i := sort.Search(len(t.ready), func(i int) bool { return t.ready[i].seq > m.seq })
+	t.ready = append(t.ready, nil)
+	copy(t.ready[i+1:], t.ready[i:])
+	t.ready[i] = m
+	if t.wait != nil {
+		close(t.wait)
+		t.wait = nil
+	}
+}
+
+func (t *durableTopic) remove(id string) {
+	delete(t.inflight, id)
+	for i, m := range t.ready {
+		if m.id == id {
+			t.ready = append(t.ready[:i], t.ready[i+1:]...)
+			return
+		}
+	}
+}
+
+//durableQueue is a message queue persisted by an embedded message log. A dequeued message
+//must be acknowledged before the visibility timeout, otherwise it is redelivered, and it
+//is moved to the dead letter topic after too many redeliveries.
+type durableQueue struct {
+	config     option.Config
+	ctx        context.Context
+	cancel     context.CancelFunc
+	queues     map[string]string
+	queuesLock sync.Mutex
+	lock       sync.Mutex
+	topics     map[string]*durableTopic
+	messages   map[string]*durableMessage
+	seq        uint64
+	log        *messageLog
+}
+
+func newDurableQueue(ctx context.Context, c option.Config) *durableQueue {
+	ctx, cancel := context.WithCancel(ctx)
+	return &durableQueue{
+		config:   c,
+		ctx:      ctx,
+		cancel:   cancel,
+		queues:   make(map[string]string),
+		topics:   make(map[string]*durableTopic),
+		messages: make(map[string]*durableMessage),
+	}
+}
+
+func (d *durableQueue) Start() error {
+	logrus.Debug("durable message queue starting")
+	log, records, err := openMessageLog(d.config.DataDir)
+	if err != nil {
+		return err
+	}
+	d.log = log
+	d.replay(records)
+	for _, t := range defaultTopics() {
+		d.registerTopic(t)
+	}
+	go d.redeliver()
+	logrus.Infof("durable message queue started success, %d messages are recovered", len(d.messages))
+	return nil
+}
+
+//replay rebuilds the messages from the log, the messages delivered but not acknowledged are ready again.
+func (d *durableQueue) replay(records []record) {
+	for _, rec := range records {
+		switch rec.Op {
+		case opEnqueue:
+			d.seq++
+			m := &durableMessage{
+				id:          rec.ID,
+				topic:       rec.Topic,
+				origin:      rec.Origin,
+				body:        rec.Body,
+				seq:         d.seq,
+				enqueueTime: rec.Time,
+				deliveries:  rec.Deliveries,
+			}
+			if m.origin == "" {
+				m.origin = m.topic
+			}
+			d.messages[m.id] = m
+			d.getTopic(m.topic).push(m)
+		case opDeliver:
+			if m := d.messages[rec.ID]; m != nil {
+				m.deliveries++
+			}
+		case opAck:
+			if m := d.messages[rec.ID]; m != nil {
+				d.getTopic(m.topic).remove(m.id)
+				delete(d.messages, m.id)
+			}
+		case opDead:
+			if m := d.messages[rec.ID]; m != nil {
+				d.move(m, DeadLetterTopic(m.origin))
+			}
+		case opRequeue:
+			if m := d.messages[rec.ID]; m != nil {
+				d.move(m, m.origin)
+				m.deliveries = 0
+			}
+		}
+	}
+}
+
+func (d *durableQueue) getTopic(topic string) *durableTopic {
+	t, ok := d.topics[topic]
+	if !ok {
+		t = &durableTopic{inflight: make(map[string]*durableMessage)}
+		d.topics[topic] = t
+	}
+	return t
+}
+
+//move moves the message to another topic
+func (d *durableQueue) move(m *durableMessage, topic string) {
+	d.getTopic(m.topic).remove(m.id)
+	m.topic = topic
+	m.deadline = time.Time{}
+	d.getTopic(topic).push(m)
+}
+
+func (d *durableQueue) registerTopic(topic string) {
+	d.queuesLock.Lock()
+	defer d.queuesLock.Unlock()
+	d.queues[topic] = topic
+}
+
+func (d *durableQueue) TopicIsExist(topic string) bool {
+	d.queuesLock.Lock()
+	defer d.queuesLock.Unlock()
+	_, ok := d.queues[topic]
+	return ok
+}
+
+func (d *durableQueue) GetAllTopics() []string {
+	d.queuesLock.Lock()
+	defer d.queuesLock.Unlock()
+	var topics []string
+	for k := range d.queues {
+		topics = append(topics, k)
+	}
+	return topics
+}
+
+func (d *durableQueue) Stop() error {
+	d.cancel()
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.log != nil {
+		return d.log.close()
+	}
+	return nil
+}
+
+func (d *durableQueue) Enqueue(ctx context.Context, topic, value string) error {
+	EnqueueNumber++
+	if len(value) > maxMessageSize {
+		return fmt.Errorf("message size %d exceeds the max size %d", len(value), maxMessageSize)
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	m := &durableMessage{
+		id:          util.NewUUID(),
+		topic:       topic,
+		origin:      topic,
+		body:        []byte(value),
+		seq:         d.seq + 1,
+		enqueueTime: time.Now(),
+	}
+	err := d.log.append(record{Op: opEnqueue, ID: m.id, Topic: m.topic, Body: m.body, Time: m.enqueueTime})
+	if err != nil {
+		return err
+	}
+	d.seq++
+	d.messages[m.id] = m
+	d.getTopic(topic).push(m)
+	return nil
+}
+
+//Dequeue blocks until there is a message in the topic or the context is done
+func (d *durableQueue) Dequeue(ctx context.Context, topic string) (*Message, error) {
+	DequeueNumber++
+	for {
+		d.lock.Lock()
+		t := d.getTopic(topic)
+		if len(t.ready) > 0 {
+			m := t.ready[0]
+			if err := d.log.append(record{Op: opDeliver, ID: m.id}); err != nil {
+				d.lock.Unlock()
+				return nil, err
+			}
+			t.ready = t.ready[1:]
+			m.deliveries++
+			m.deadline = time.Now().Add(d.visibilityTimeout())
+			t.inflight[m.id] = m
+			msg := m.message()
+			d.lock.Unlock()
+			return msg, nil
+		}
+		if t.wait == nil {
+			t.wait = make(chan struct{})
+		}
+		wait := t.wait
+		d.lock.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-d.ctx.Done():
+			return nil, d.ctx.Err()
+		}
+	}
+}
+
+func (d *durableQueue) Ack(topic, receipt string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	m := d.messages[receipt]
+	if m == nil || m.topic != topic {
+		return nil
+	}
+	if err := d.log.append(record{Op: opAck, ID: m.id}); err != nil {
+		return err
+	}
+	d.getTopic(topic).remove(m.id)
+	delete(d.messages, m.id)
+	d.compact()
+	return nil
+}
+
+func (d *durableQueue) DeadLetters(topic string) ([]*Message, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var messages []*Message
+	for _, m := range d.getTopic(DeadLetterTopic(topic)).ready {
+		messages = append(messages, m.message())
+	}
+	return messages, nil
+}
+
+func (d *durableQueue) Requeue(topic, receipt string) (int, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	dead := d.getTopic(DeadLetterTopic(topic))
+	var requeue []*durableMessage
+	for _, m := range dead.ready {
+		if receipt == "" || m.id == receipt {
+			requeue = append(requeue, m)
+		}
+	}
+	for i, m := range requeue {
+		if err := d.log.append(record{Op: opRequeue, ID: m.id}); err != nil {
+			return i, err
+		}
+		d.move(m, topic)
+		m.deliveries = 0
+	}
+	return len(requeue), nil
+}
+
+func (d *durableQueue) MessageQueueSize(topic string) int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	t := d.getTopic(topic)
+	return int64(len(t.ready) + len(t.inflight))
+}
+
+func (d *durableQueue) visibilityTimeout() time.Duration {
+	if d.config.VisibilityTimeout <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(d.config.VisibilityTimeout) * time.Second
+}
+
+//redeliver makes the messages that are not acknowledged before the visibility timeout ready again,
+//or moves them to the dead letter topic if they have been redelivered too many times.
+func (d *durableQueue) redeliver() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.lock.Lock()
+			d.expire(time.Now())
+			d.lock.Unlock()
+		}
+	}
+}
+
+func (d *durableQueue) expire(now time.Time) {
+	for _, t := range d.topics {
+		for _, m := range t.inflight {
+			if now.Before(m.deadline) {
+				continue
+			}
+			if m.deliveries > d.config.MaxRedelivery {
+				if err := d.log.append(record{Op: opDead, ID: m.id}); err != nil {
+					logrus.Errorf("move message %s to the dead letter topic: %v", m.id, err)
+					continue
+				}
+				logrus.Warningf("message %s of topic %s has been delivered %d times, move it to the dead letter topic",
+					m.id, m.topic, m.deliveries)
+				d.move(m, DeadLetterTopic(m.origin))
+				DeadLetterNumber++
+				continue
+			}
+			delete(t.inflight, m.id)
+			m.deadline = time.Time{}
+			t.push(m)
+			RedeliveryNumber++
+		}
+	}
+	d.compact()
+}
+
+//compact rewrites the message log if most of its records are out of date
+func (d *durableQueue) compact() {
+	if d.log.records < compactThreshold || d.log.records < 2*len(d.messages) {
+		return
+	}
+	messages := make([]*durableMessage, 0, len(d.messages))
+	for _, m := range d.messages {
+		messages = append(messages, m)
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].seq < messages[j].seq })
+	records := make([]record, 0, len(messages))
+	for _, m := range messages {
+		rec := record{Op: opEnqueue, ID: m.id, Topic: m.topic, Body: m.body, Time: m.enqueueTime, Deliveries: m.deliveries}
+		if m.origin != m.topic {
+			rec.Origin = m.origin
+		}
+		records = append(records, rec)
+	}
+	if err := d.log.compact(records); err != nil {
+		logrus.Errorf("compact message log: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package mq
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gridworkz/kato/cmd/mq/option"
+)
+
+func newTestDurableQueue(t *testing.T, dir string) *durableQueue {
+	d := newDurableQueue(context.TODO(), option.Config{
+		DataDir:           dir,
+		VisibilityTimeout: 10,
+		MaxRedelivery:     1,
+	})
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDurableQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := newTestDurableQueue(t, dir)
+	for _, value := range []string{"a", "b"} {
+		if err := d.Enqueue(context.TODO(), "builder", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	msg, err := d.Dequeue(context.TODO(), "builder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Body != "a" || msg.Redelivered != 0 {
+		t.Fatalf("expected the first message a, but got %s(%d)", msg.Body, msg.Redelivered)
+	}
+	if err := d.Ack("builder", msg.Receipt); err != nil {
+		t.Fatal(err)
+	}
+
+	// the message is redelivered after the visibility timeout
+	msg, _ = d.Dequeue(context.TODO(), "builder")
+	d.expire(time.Now().Add(time.Minute))
+	msg, _ = d.Dequeue(context.TODO(), "builder")
+	if msg.Body != "b" || msg.Redelivered != 1 {
+		t.Fatalf("expected message b redelivered once, but got %s(%d)", msg.Body, msg.Redelivered)
+	}
+	// and it is moved to the dead letter topic after too many redeliveries
+	d.expire(time.Now().Add(time.Minute))
+	if size := d.MessageQueueSize("builder"); size != 0 {
+		t.Fatalf("expected no message in the topic, but got %d", size)
+	}
+	d.Stop()
+
+	// the dead letters survive the restart
+	d = newTestDurableQueue(t, dir)
+	defer d.Stop()
+	dead, _ := d.DeadLetters("builder")
+	if len(dead) != 1 || dead[0].Body != "b" {
+		t.Fatalf("expected the dead letter b, but got %v", dead)
+	}
+	if n, err := d.Requeue("builder", ""); err != nil || n != 1 {
+		t.Fatalf("expected 1 message requeued, but got %d: %v", n, err)
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	msg, err = d.Dequeue(ctx, "builder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Body != "b" || msg.Redelivered != 0 {
+		t.Fatalf("expected the requeued message b, but got %s(%d)", msg.Body, msg.Redelivered)
+	}
+}
+
+func TestDurableQueueCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := newTestDurableQueue(t, dir)
+	if err := d.Enqueue(context.TODO(), "worker", "live"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < compactThreshold/2; i++ {
+		d.Enqueue(context.TODO(), "builder", "done")
+		msg, _ := d.Dequeue(context.TODO(), "builder")
+		d.Ack("builder", msg.Receipt)
+	}
+	if d.log.records >= compactThreshold {
+		t.Fatalf("expected the message log is compacted, but got %d records", d.log.records)
+	}
+	d.Stop()
+
+	d = newTestDurableQueue(t, dir)
+	defer d.Stop()
+	if size := d.MessageQueueSize("worker"); size != 1 {
+		t.Fatalf("expected 1 message after compaction, but got %d", size)
+	}
+}
+
+func TestMessageLogTornWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, _, err := openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.append(record{Op: opEnqueue, ID: "1", Topic: "worker", Body: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+	// a record partially written before crash
+	l.file.Write([]byte{0, 0, 1})
+	l.close()
+
+	l, records, err := openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ID != "1" {
+		t.Fatalf("expected 1 record, but got %v", records)
+	}
+	// the torn write is truncated, so the new record can be read
+	if err := l.append(record{Op: opAck, ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	l.close()
+	l, records, err = openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.close()
+	if len(records) != 2 || records[1].Op != opAck {
+		t.Fatalf("expected 2 records, but got %v", records)
+	}
+}
+
+func TestMessageLogBrokenSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, _, err := openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.append(record{Op: opEnqueue, ID: "1", Topic: "worker", Body: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+	// a broken header claiming a record of 4GB
+	l.file.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, '{'})
+	l.close()
+
+	l, records, err := openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.close()
+	if len(records) != 1 || records[0].ID != "1" {
+		t.Fatalf("expected 1 record, but got %v", records)
+	}
+
+	d := newDurableQueue(context.TODO(), option.Config{DataDir: dir})
+	d.log = l
+	if err := d.Enqueue(context.TODO(), "worker", string(make([]byte, maxMessageSize+1))); err == nil {
+		t.Fatal("expected the message larger than the max size is rejected")
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mq
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	opEnqueue = "enqueue"
+	opDeliver = "deliver"
+	opAck     = "ack"
+	opDead    = "dead"
+	opRequeue = "requeue"
+)
+
+//maxMessageSize the max size of the message body, it is the default max size of the grpc messages received by the server
+const maxMessageSize = 4 * 1024 * 1024
+
+//maxRecordSize the body is base64 encoded in the record, a record larger than it is corrupted
+const maxRecordSize = maxMessageSize/3*4 + 64*1024
+
+//record an operation of the message log
+type record struct {
+	Op    string    `json:"op"`
+	ID    string    `json:"id"`
+	Topic string    `json:"topic,omitempty"`
+	Body  []byte    `json:"body,omitempty"`
+	Time  time.Time `json:"time,omitempty"`
+	// Origin the topic of the dead letter before it is moved to the dead letter topic
+	Origin string `json:"origin,omitempty"`
+	// Deliveries is only set by the compaction, the message is not delivered on replay
+	Deliveries int `json:"deliveries,omitempty"`
+}
+
+//messageLog is an append-only log of the message operations. Every record is framed by its
+//length and crc32 checksum, so that a torn write at the tail of the log is detected and
+//truncated on replay.
+type messageLog struct {
+	path    string
+	file    *os.File
+	records int
+}
+
+//openMessageLog opens the message log in the dir and replays its records
+func openMessageLog(dir string) (*messageLog, []record, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("create message log dir %s: %v", dir, err)
+	}
+	l := &messageLog{path: path.Join(dir, "messages.log")}
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open message log %s: %v", l.path, err)
+	}
+	records, offset, err := readRecords(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	// drop the torn write at the tail of the log
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("truncate message log %s: %v", l.path, err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	l.file = file
+	l.records = len(records)
+	return l, records, nil
+}
+
+//readRecords reads the records until the end of the file or the first broken record,
+//returns the offset of the end of the last valid record.
+func readRecords(r io.Reader) ([]record, int64, error) {
+	var records []record
+	var offset int64
+	reader := bufio.NewReader(r)
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err != io.EOF {
+				logrus.Warningf("message log is truncated at %d: %v", offset, err)
+			}
+			return records, offset, nil
+		}
+		size := binary.BigEndian.Uint32(header[:4])
+		if size > maxRecordSize {
+			// do not allocate the broken size, which could be up to 4GB
+			logrus.Warningf("message log is broken at %d: record size %d exceeds %d", offset, size, maxRecordSize)
+			return records, offset, nil
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			logrus.Warningf("message log is truncated at %d: %v", offset, err)
+			return records, offset, nil
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			logrus.Warningf("message log is broken at %d: checksum mismatch", offset)
+			return records, offset, nil
+		}
+		var rec record
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return nil, 0, fmt.Errorf("decode message log record at %d: %v", offset, err)
+		}
+		records = append(records, rec)
+		offset += int64(len(header)) + int64(size)
+	}
+}
+
+func encodeRecord(rec record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[8:], payload)
+	return buf, nil
+}
+
+//append writes the record to the log and flushes it to the disk
+func (l *messageLog) append(rec record) error {
+	buf, err := encodeRecord(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(buf); err != nil {
+		return fmt.Errorf("write message log: %v", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("sync message log: %v", err)
+	}
+	l.records++
+	return nil
+}
+
+//compact rewrites the log with the records of the live messages
+func (l *messageLog) compact(records []record) error {
+	tmp := l.path + ".compact"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("create compacted message log: %v", err)
+	}
+	writer := bufio.NewWriter(file)
+	for _, rec := range records {
+		buf, err := encodeRecord(rec)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if _, err := writer.Write(buf); err != nil {
+			file.Close()
+			return fmt.Errorf("write compacted message log: %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("write compacted message log: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("sync compacted message log: %v", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		file.Close()
+		return fmt.Errorf("replace message log: %v", err)
+	}
+	l.file.Close()
+	l.file = file
+	l.records = len(records)
+	return nil
+}
+
+func (l *messageLog) close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
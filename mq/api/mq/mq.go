@@ -19,6 +19,7 @@
 package mq
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"sync"
@@ -38,7 +39,14 @@ import (
 //ActionMQ
 type ActionMQ interface {
 	Enqueue(context.Context, string, string) error
-	Dequeue(context.Context, string) (string, error)
+	Dequeue(context.Context, string) (*Message, error)
+	// Ack acknowledges the message by its receipt, so that it will not be redelivered.
+	// Acknowledging an unknown message is not an error.
+	Ack(topic, receipt string) error
+	// DeadLetters returns the messages of the dead letter topic of the topic
+	DeadLetters(topic string) ([]*Message, error)
+	// Requeue moves the dead letter back to the topic, all the dead letters are moved if receipt is empty
+	Requeue(topic, receipt string) (int, error)
 	TopicIsExist(string) bool
 	GetAllTopics() []string
 	Start() error
@@ -46,6 +54,25 @@ type ActionMQ interface {
 	MessageQueueSize(topic string) int64
 }
 
+//Message a message dequeued from the topic
+type Message struct {
+	// Receipt is used to acknowledge the message, it is empty if the message does not need to be acknowledged
+	Receipt string
+	Topic   string
+	Body    string
+	// Redelivered the number of times the message has been redelivered
+	Redelivered int
+	EnqueueTime time.Time
+}
+
+//ErrNotSupported the operation is not supported by the backend
+var ErrNotSupported = errors.New("not supported by the message queue backend")
+
+//DeadLetterTopic returns the dead letter topic of the topic
+func DeadLetterTopic(topic string) string {
+	return topic + "_dead_letter"
+}
+
 // EnqueueNumber
 var EnqueueNumber float64 = 0
 
@@ -54,6 +81,13 @@ var DequeueNumber float64 = 0
 
 // NewActionMQ
 func NewActionMQ(ctx context.Context, c option.Config) ActionMQ {
+	switch c.Backend {
+	case "log":
+		return newDurableQueue(ctx, c)
+	case "", "etcd":
+	default:
+		logrus.Warningf("unknown message queue backend %s, use etcd instead", c.Backend)
+	}
 	etcdQueue := etcdQueue{
 		config: c,
 		ctx:    ctx,
@@ -85,20 +119,22 @@ func (e *etcdQueue) Start() error {
 		return err
 	}
 	e.client = cli
-	topics := os.Getenv("topics")
-	if topics != "" {
-		ts := strings.Split(topics, ",")
-		for _, t := range ts {
-			e.registerTopic(t)
-		}
+	for _, t := range defaultTopics() {
+		e.registerTopic(t)
 	}
-	e.registerTopic(client.BuilderTopic)
-	e.registerTopic(client.WindowsBuilderTopic)
-	e.registerTopic(client.WorkerTopic)
 	logrus.Info("etcd message queue client started success")
 	return nil
 }
 
+//defaultTopics returns the topics from the env 'topics' and the topics of kato components
+func defaultTopics() []string {
+	var topics []string
+	if ts := os.Getenv("topics"); ts != "" {
+		topics = append(topics, strings.Split(ts, ",")...)
+	}
+	return append(topics, client.BuilderTopic, client.WindowsBuilderTopic, client.WorkerTopic)
+}
+
 //registerTopic
 func (e *etcdQueue) registerTopic(topic string) {
 	e.queuesLock.Lock()
@@ -135,10 +171,27 @@ func (e *etcdQueue) Enqueue(ctx context.Context, topic, value string) error {
 	return queue.Enqueue(value)
 }
 
-func (e *etcdQueue) Dequeue(ctx context.Context, topic string) (string, error) {
+func (e *etcdQueue) Dequeue(ctx context.Context, topic string) (*Message, error) {
 	DequeueNumber++
 	queue := etcdutil.NewQueue(ctx, e.client, e.queueKey(topic))
-	return queue.Dequeue()
+	value, err := queue.Dequeue()
+	if err != nil {
+		return nil, err
+	}
+	// the message is deleted from etcd once it is dequeued, so it does not need to be acknowledged
+	return &Message{Topic: topic, Body: value}, nil
+}
+
+func (e *etcdQueue) Ack(topic, receipt string) error {
+	return nil
+}
+
+func (e *etcdQueue) DeadLetters(topic string) ([]*Message, error) {
+	return nil, ErrNotSupported
+}
+
+func (e *etcdQueue) Requeue(topic, receipt string) (int, error) {
+	return 0, ErrNotSupported
 }
 
 func (e *etcdQueue) MessageQueueSize(topic string) int64 {
@@ -45,6 +45,7 @@ type MQClient interface {
 	pb.TaskQueueClient
 	Close()
 	SendBuilderTopic(t TaskStruct) error
+	AckTask(topic string, task *pb.TaskMessage) error
 }
 
 type mqClient struct {
@@ -127,3 +128,18 @@ func (m *mqClient) SendBuilderTopic(t TaskStruct) error {
 	}
 	return nil
 }
+
+//AckTask acknowledges the task dequeued from the topic, so that it will not be redelivered
+func (m *mqClient) AckTask(topic string, task *pb.TaskMessage) error {
+	// the backend does not need acknowledgements
+	if task.Receipt == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(m.ctx, time.Second*5)
+	defer cancel()
+	_, err := m.TaskQueueClient.Ack(ctx, &pb.AckRequest{Topic: topic, Receipt: task.Receipt})
+	if err != nil {
+		return fmt.Errorf("send ack request error %s", err.Error())
+	}
+	return nil
+}
@@ -47,9 +47,16 @@ type Exporter struct {
 	scrapeErrors       *prometheus.CounterVec
 	lbPluginUp         prometheus.Gauge
 	queueMessageNumber *prometheus.GaugeVec
+	deadLetterNumber   *prometheus.GaugeVec
 	mqm                mq.ActionMQ
 }
 
+var redeliveryDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "redelivery_total"),
+	"The number of the messages redelivered after the visibility timeout.",
+	nil, nil,
+)
+
 var healthDesc = prometheus.NewDesc(
 	prometheus.BuildFQName(namespace, exporter, "health_status"),
 	"health status.",
@@ -88,6 +95,11 @@ func NewExporter(mqm mq.ActionMQ) *Exporter {
 			Name:      "queue_message_number",
 			Help:      "Message queue enqueue total.",
 		}, []string{"topic"}),
+		deadLetterNumber: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dead_letter_number",
+			Help:      "The number of the messages in the dead letter topic.",
+		}, []string{"topic"}),
 	}
 }
 
@@ -116,11 +128,14 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.scrapeErrors.Collect(ch)
 	for _, topic := range e.mqm.GetAllTopics() {
 		e.queueMessageNumber.WithLabelValues(topic).Set(float64(e.mqm.MessageQueueSize(topic)))
+		e.deadLetterNumber.WithLabelValues(topic).Set(float64(e.mqm.MessageQueueSize(mq.DeadLetterTopic(topic))))
 	}
 	e.queueMessageNumber.Collect(ch)
+	e.deadLetterNumber.Collect(ch)
 }
 
 func (e *Exporter) scrape(ch chan<- prometheus.Metric) {
 	e.totalScrapes.Inc()
 	ch <- prometheus.MustNewConstMetric(healthDesc, prometheus.GaugeValue, 1, "mq")
+	ch <- prometheus.MustNewConstMetric(redeliveryDesc, prometheus.CounterValue, mq.RedeliveryNumber)
 }
@@ -24,6 +24,7 @@ import (
 
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/sirupsen/logrus"
 
@@ -33,6 +34,8 @@ import (
 	endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	envoy_api_v2_listener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	fault_common "github.com/envoyproxy/go-control-plane/envoy/config/filter/fault/v2"
+	http_fault "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/fault/v2"
 	http_rate_limit "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/rate_limit/v2"
 	http_connection_manager "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 	tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
@@ -175,6 +178,15 @@ func CreateHTTPConnectionManager(name, statPrefix string, rateOpt *RateLimitOpti
 			},
 		})
 	}
+	if hasFault(routes) {
+		//the routes carry their own faults, the filter injects nothing by default
+		httpFilters = append(httpFilters, &http_connection_manager.HttpFilter{
+			Name: wellknown.Fault,
+			ConfigType: &http_connection_manager.HttpFilter_Config{
+				Config: MessageToStruct(&http_fault.HTTPFault{}),
+			},
+		})
+	}
 	httpFilters = append(httpFilters, &http_connection_manager.HttpFilter{
 		Name: wellknown.Router,
 	})
@@ -242,6 +254,8 @@ func CreateSocketAddress(protocol, address string, port uint32) *core.Address {
 	if strings.HasPrefix(address, "http://") {
 		address = strings.Split(address, "http://")[1]
 	}
+	// envoy requires the IPv6 address without brackets
+	address = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
 	return &core.Address{
 		Address: &core.Address_SocketAddress{
 			SocketAddress: &core.SocketAddress{
@@ -385,6 +399,93 @@ func CreateRoute(clusterName, prefix string, headers []*route.HeaderMatcher, wei
 	return rout
 }
 
+//CreateRetryPolicy create the retry policy of a route, nil if the retry is disabled
+func CreateRetryPolicy(options KatoPluginOptions) *route.RetryPolicy {
+	if options.RetryAttempts == 0 {
+		return nil
+	}
+	retryPolicy := &route.RetryPolicy{
+		RetryOn:    options.RetryOn,
+		NumRetries: ConversionUInt32(options.RetryAttempts),
+	}
+	if options.PerTryTimeoutMS > 0 {
+		retryPolicy.PerTryTimeout = ConverTimeDurationMS(options.PerTryTimeoutMS)
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		logrus.Errorf("validate envoy config retry policy failure %s", err.Error())
+		return nil
+	}
+	return retryPolicy
+}
+
+//CreateFractionalPercent create fractional percent with the precision of 0.0001%
+func CreateFractionalPercent(percent float64) *_type.FractionalPercent {
+	return &_type.FractionalPercent{
+		Numerator:   uint32(percent*10000 + 0.5),
+		Denominator: _type.FractionalPercent_MILLION,
+	}
+}
+
+//CreateHTTPFault create the fault injection of a route, nil if no fault is injected
+func CreateHTTPFault(options KatoPluginOptions) *http_fault.HTTPFault {
+	if !options.HasFault() {
+		return nil
+	}
+	httpFault := &http_fault.HTTPFault{}
+	if options.FaultDelayMS > 0 && options.FaultDelayPercent > 0 {
+		httpFault.Delay = &fault_common.FaultDelay{
+			FaultDelaySecifier: &fault_common.FaultDelay_FixedDelay{
+				FixedDelay: ConverTimeDurationMS(options.FaultDelayMS),
+			},
+			Percentage: CreateFractionalPercent(options.FaultDelayPercent),
+		}
+	}
+	if options.FaultAbortStatus != 0 && options.FaultAbortPercent > 0 {
+		httpFault.Abort = &http_fault.FaultAbort{
+			ErrorType: &http_fault.FaultAbort_HttpStatus{
+				HttpStatus: options.FaultAbortStatus,
+			},
+			Percentage: CreateFractionalPercent(options.FaultAbortPercent),
+		}
+	}
+	if err := httpFault.Validate(); err != nil {
+		logrus.Errorf("validate envoy config http fault failure %s", err.Error())
+		return nil
+	}
+	return httpFault
+}
+
+//SetRoutePolicy set the retry policy, the timeout and the fault injection of the options on the route.
+//The fault injection takes effect only if the http connection manager has the fault filter,
+//CreateHTTPConnectionManager adds it if any route injects faults.
+func SetRoutePolicy(rout *route.Route, options KatoPluginOptions) {
+	action, ok := rout.Action.(*route.Route_Route)
+	if !ok {
+		return
+	}
+	action.Route.RetryPolicy = CreateRetryPolicy(options)
+	if options.RequestTimeoutMS != nil {
+		action.Route.Timeout = ConverTimeDurationMS(*options.RequestTimeoutMS)
+	}
+	if httpFault := CreateHTTPFault(options); httpFault != nil {
+		rout.TypedPerFilterConfig = map[string]*any.Any{
+			wellknown.Fault: Message2Any(httpFault),
+		}
+	}
+}
+
+//hasFault whether any route of the virtual hosts injects faults
+func hasFault(virtualHosts []*route.VirtualHost) bool {
+	for _, virtualHost := range virtualHosts {
+		for _, rout := range virtualHost.Routes {
+			if _, ok := rout.TypedPerFilterConfig[wellknown.Fault]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 //CreateHeaderMatcher create http route config header matcher
 func CreateHeaderMatcher(header v1.Header) *route.HeaderMatcher {
 	if header.Name == "" {
@@ -20,6 +20,7 @@ package v2
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
@@ -82,6 +83,14 @@ func ConverTimeDuration(second int64) *duration.Duration {
 	}
 }
 
+//ConverTimeDurationMS millisecond
+func ConverTimeDurationMS(ms int64) *duration.Duration {
+	return &duration.Duration{
+		Seconds: ms / 1000,
+		Nanos:   int32(ms%1000) * 1000000,
+	}
+}
+
 const (
 	//KeyPrefix request path prefix
 	KeyPrefix string = "Prefix"
@@ -127,8 +136,51 @@ const (
 	KeyConnectionTimeout string = "ConnectionTimeout"
 	//KeyTCPIdleTimeout tcp idle timeout
 	KeyTCPIdleTimeout string = "TCPIdleTimeout"
+	//KeyGrpcHealthServiceName the grpc service name of the cluster health check
+	KeyGrpcHealthServiceName string = "GrpcHealthServiceName"
+	//KeyHealthCheckTimeout cluster health check timeout
+	KeyHealthCheckTimeout string = "HealthCheckTimeout"
+	//KeyHealthCheckInterval cluster health check interval
+	KeyHealthCheckInterval string = "HealthCheckInterval"
+	//KeyRetryAttempts the number of retries of a failed http request, 0 means no retry
+	KeyRetryAttempts string = "RetryAttempts"
+	//KeyRetryOn the conditions to retry on separated by commas, such as 5xx,reset,unavailable.
+	//If not specified, the default is connect-failure,refused-stream,gateway-error
+	KeyRetryOn string = "RetryOn"
+	//KeyPerTryTimeoutMS the timeout of each try of a http request in milliseconds
+	KeyPerTryTimeoutMS string = "PerTryTimeoutMS"
+	//KeyRequestTimeoutMS the timeout of a http request including all the retries in milliseconds,
+	//0 disables the timeout. If not specified, the default of envoy is 15s
+	KeyRequestTimeoutMS string = "RequestTimeoutMS"
+	//KeyFaultDelayMS the fixed delay in milliseconds injected before the http requests are forwarded
+	KeyFaultDelayMS string = "FaultDelayMS"
+	//KeyFaultDelayPercent the percentage of the http requests to delay, default 100 if FaultDelayMS is set
+	KeyFaultDelayPercent string = "FaultDelayPercent"
+	//KeyFaultAbortStatus the http status the aborted requests are answered with
+	KeyFaultAbortStatus string = "FaultAbortStatus"
+	//KeyFaultAbortPercent the percentage of the http requests to abort, default 100 if FaultAbortStatus is set
+	KeyFaultAbortPercent string = "FaultAbortPercent"
 )
 
+//DefaultRetryOn the retry conditions used if RetryAttempts is set without RetryOn,
+//they are safe for the requests which are not idempotent
+var DefaultRetryOn = "connect-failure,refused-stream,gateway-error"
+
+//retryOnConditions the http and grpc retry conditions supported by envoy
+var retryOnConditions = map[string]bool{
+	"5xx":                true,
+	"gateway-error":      true,
+	"reset":              true,
+	"connect-failure":    true,
+	"retriable-4xx":      true,
+	"refused-stream":     true,
+	"cancelled":          true,
+	"deadline-exceeded":  true,
+	"internal":           true,
+	"resource-exhausted": true,
+	"unavailable":        true,
+}
+
 //KatoPluginOptions kato plugin config struct
 type KatoPluginOptions struct {
 	Prefix                   string
@@ -147,6 +199,22 @@ type KatoPluginOptions struct {
 	HealthyPanicThreshold    int64
 	ConnectionTimeout        int64
 	TCPIdleTimeout           int64
+	GrpcHealthServiceName    string
+	HealthCheckTimeout       int64
+	HealthCheckInterval      int64
+	RetryAttempts            uint32
+	RetryOn                  string
+	PerTryTimeoutMS          int64
+	RequestTimeoutMS         *int64
+	FaultDelayMS             int64
+	FaultDelayPercent        float64
+	FaultAbortStatus         uint32
+	FaultAbortPercent        float64
+}
+
+//HasFault whether the options inject delays or aborts into the http requests
+func (r KatoPluginOptions) HasFault() bool {
+	return (r.FaultDelayMS > 0 && r.FaultDelayPercent > 0) || (r.FaultAbortStatus != 0 && r.FaultAbortPercent > 0)
 }
 
 //KatoInboundPluginOptions kato inbound plugin options
@@ -185,6 +253,11 @@ func GetOptionValues(sr map[string]interface{}) KatoPluginOptions {
 		HealthyPanicThreshold: 50,
 		ConnectionTimeout:     250,
 		TCPIdleTimeout:        60 * 60 * 2,
+		HealthCheckTimeout:    5,
+		HealthCheckInterval:   4,
+		RetryOn:               DefaultRetryOn,
+		FaultDelayPercent:     100,
+		FaultAbortPercent:     100,
 	}
 	if sr == nil {
 		return rpo
@@ -275,11 +348,114 @@ func GetOptionValues(sr map[string]interface{}) KatoPluginOptions {
 			if i, err := strconv.Atoi(v.(string)); err == nil {
 				rpo.TCPIdleTimeout = int64(i)
 			}
+		case KeyGrpcHealthServiceName:
+			rpo.GrpcHealthServiceName = v.(string)
+		case KeyHealthCheckTimeout:
+			if i, err := strconv.Atoi(v.(string)); err == nil && i != 0 {
+				rpo.HealthCheckTimeout = int64(i)
+			}
+		case KeyHealthCheckInterval:
+			if i, err := strconv.Atoi(v.(string)); err == nil && i != 0 {
+				rpo.HealthCheckInterval = int64(i)
+			}
+		case KeyRetryAttempts:
+			value, _ := v.(string)
+			if i, err := strconv.Atoi(value); err == nil && i > 0 {
+				rpo.RetryAttempts = uint32(i)
+			}
+		case KeyRetryOn:
+			value, _ := v.(string)
+			if value != "" {
+				rpo.RetryOn = strings.Replace(value, " ", "", -1)
+			}
+		case KeyPerTryTimeoutMS:
+			value, _ := v.(string)
+			if i, err := strconv.Atoi(value); err == nil && i > 0 {
+				rpo.PerTryTimeoutMS = int64(i)
+			}
+		case KeyRequestTimeoutMS:
+			value, _ := v.(string)
+			if i, err := strconv.Atoi(value); err == nil && i >= 0 {
+				timeout := int64(i)
+				rpo.RequestTimeoutMS = &timeout
+			}
+		case KeyFaultDelayMS:
+			value, _ := v.(string)
+			if i, err := strconv.Atoi(value); err == nil && i > 0 {
+				rpo.FaultDelayMS = int64(i)
+			}
+		case KeyFaultDelayPercent:
+			value, _ := v.(string)
+			if f, err := strconv.ParseFloat(value, 64); err == nil && f >= 0 && f <= 100 {
+				rpo.FaultDelayPercent = f
+			}
+		case KeyFaultAbortStatus:
+			value, _ := v.(string)
+			if i, err := strconv.Atoi(value); err == nil && i >= 200 && i < 600 {
+				rpo.FaultAbortStatus = uint32(i)
+			}
+		case KeyFaultAbortPercent:
+			value, _ := v.(string)
+			if f, err := strconv.ParseFloat(value, 64); err == nil && f >= 0 && f <= 100 {
+				rpo.FaultAbortPercent = f
+			}
 		}
 	}
 	return rpo
 }
 
+//ValidateOptions checks the retry, timeout and fault injection options of a dependency,
+//the other options fall back to their defaults if invalid
+func ValidateOptions(sr map[string]interface{}) error {
+	values := make(map[string]string)
+	for kind, v := range sr {
+		switch kind {
+		case KeyRetryAttempts, KeyRetryOn, KeyPerTryTimeoutMS, KeyRequestTimeoutMS,
+			KeyFaultDelayMS, KeyFaultDelayPercent, KeyFaultAbortStatus, KeyFaultAbortPercent:
+		default:
+			continue
+		}
+		value, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", kind)
+		}
+		if value == "" {
+			continue
+		}
+		switch kind {
+		case KeyRetryAttempts, KeyPerTryTimeoutMS, KeyRequestTimeoutMS, KeyFaultDelayMS:
+			if i, err := strconv.Atoi(value); err != nil || i < 0 {
+				return fmt.Errorf("%s must be a non-negative integer", kind)
+			}
+		case KeyFaultAbortStatus:
+			if i, err := strconv.Atoi(value); err != nil || i < 200 || i >= 600 {
+				return fmt.Errorf("%s must be a http status between 200 and 599", kind)
+			}
+		case KeyFaultDelayPercent, KeyFaultAbortPercent:
+			if f, err := strconv.ParseFloat(value, 64); err != nil || f < 0 || f > 100 {
+				return fmt.Errorf("%s must be a percentage between 0 and 100", kind)
+			}
+		case KeyRetryOn:
+			for _, condition := range strings.Split(value, ",") {
+				if !retryOnConditions[strings.TrimSpace(condition)] {
+					return fmt.Errorf("%s: unsupported retry condition '%s'", kind, condition)
+				}
+			}
+		}
+		values[kind] = value
+	}
+	attempts, _ := strconv.Atoi(values[KeyRetryAttempts])
+	perTryTimeout, _ := strconv.Atoi(values[KeyPerTryTimeoutMS])
+	requestTimeout, _ := strconv.Atoi(values[KeyRequestTimeoutMS])
+	if perTryTimeout > 0 && attempts == 0 {
+		return fmt.Errorf("%s requires %s", KeyPerTryTimeoutMS, KeyRetryAttempts)
+	}
+	if requestTimeout > 0 && perTryTimeout > requestTimeout {
+		return fmt.Errorf("%s must not be greater than %s", KeyPerTryTimeoutMS, KeyRequestTimeoutMS)
+	}
+	return nil
+}
+
 //GetKatoInboundPluginOptions get kato inbound plugin options
 func GetKatoInboundPluginOptions(sr map[string]interface{}) (r KatoInboundPluginOptions) {
 	for k, v := range sr {
@@ -0,0 +1,67 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package v2
+
+import "testing"
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "other options are not checked", options: map[string]interface{}{KeyWeight: 80}},
+		{name: "retry", options: map[string]interface{}{KeyRetryAttempts: "3", KeyRetryOn: "5xx, reset", KeyPerTryTimeoutMS: "500", KeyRequestTimeoutMS: "2000"}},
+		{name: "fault", options: map[string]interface{}{KeyFaultDelayMS: "100", KeyFaultDelayPercent: "0.5", KeyFaultAbortStatus: "503", KeyFaultAbortPercent: "100"}},
+		{name: "not a string", options: map[string]interface{}{KeyRetryAttempts: 3}, wantErr: true},
+		{name: "negative attempts", options: map[string]interface{}{KeyRetryAttempts: "-1"}, wantErr: true},
+		{name: "unknown retry condition", options: map[string]interface{}{KeyRetryOn: "5xx,always"}, wantErr: true},
+		{name: "per try timeout without retry", options: map[string]interface{}{KeyPerTryTimeoutMS: "500"}, wantErr: true},
+		{name: "per try timeout over request timeout", options: map[string]interface{}{KeyRetryAttempts: "2", KeyPerTryTimeoutMS: "500", KeyRequestTimeoutMS: "100"}, wantErr: true},
+		{name: "abort status", options: map[string]interface{}{KeyFaultAbortStatus: "600"}, wantErr: true},
+		{name: "percent", options: map[string]interface{}{KeyFaultDelayPercent: "100.1"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := ValidateOptions(tc.options); (err != nil) != tc.wantErr {
+			t.Errorf("%s: want error %v, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestGetOptionValuesRouteResilience(t *testing.T) {
+	options := GetOptionValues(map[string]interface{}{KeyRetryAttempts: "2", KeyRequestTimeoutMS: "0", KeyFaultAbortStatus: "503"})
+	if options.RetryAttempts != 2 || options.RetryOn != DefaultRetryOn {
+		t.Errorf("unexpected retry options %d %s", options.RetryAttempts, options.RetryOn)
+	}
+	if options.RequestTimeoutMS == nil || *options.RequestTimeoutMS != 0 {
+		t.Errorf("the request timeout 0 should disable the timeout")
+	}
+	if !options.HasFault() || options.FaultAbortPercent != 100 {
+		t.Errorf("the abort should apply to all the requests by default")
+	}
+	if GetOptionValues(nil).HasFault() {
+		t.Errorf("no fault should be injected by default")
+	}
+	// the options saved before they are validated may not be strings
+	options = GetOptionValues(map[string]interface{}{KeyRetryAttempts: 3, KeyFaultAbortStatus: 503.0})
+	if options.RetryAttempts != 0 || options.HasFault() {
+		t.Errorf("the options which are not strings should be ignored")
+	}
+}
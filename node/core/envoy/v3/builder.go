@@ -0,0 +1,651 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package v3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/sirupsen/logrus"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	configratelimit "github.com/envoyproxy/go-control-plane/envoy/config/ratelimit/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	fault_common "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	http_fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	http_rate_limit "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ratelimit/v3"
+	router "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	http_connection_manager "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tcp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
+	udp_proxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/udp/udp_proxy/v3"
+	_type "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "github.com/gridworkz/kato/node/core/envoy/v1"
+)
+
+//DefaultLocalhostListenerAddress -
+var DefaultLocalhostListenerAddress = "127.0.0.1"
+
+// DefaultLocalhostListenerPort -
+var DefaultLocalhostListenerPort uint32 = 80
+
+//CreateTCPListener listener builder
+func CreateTCPListener(name, clusterName, address, statPrefix string, port uint32, idleTimeout int64) *listener.Listener {
+	if address == "" {
+		address = DefaultLocalhostListenerAddress
+	}
+	tcpProxy := &tcp_proxy.TcpProxy{
+		StatPrefix: statPrefix,
+		//todo:TcpProxy_WeightedClusters
+		ClusterSpecifier: &tcp_proxy.TcpProxy_Cluster{
+			Cluster: clusterName,
+		},
+		IdleTimeout: ConverTimeDuration(idleTimeout),
+	}
+	if err := tcpProxy.Validate(); err != nil {
+		logrus.Errorf("validate listener tcp proxy config failure %s", err.Error())
+		return nil
+	}
+	l := &listener.Listener{
+		Name:    name,
+		Address: CreateSocketAddress("tcp", address, port),
+		FilterChains: []*listener.FilterChain{
+			{
+				Filters: []*listener.Filter{
+					{
+						Name:       wellknown.TCPProxy,
+						ConfigType: &listener.Filter_TypedConfig{TypedConfig: Message2Any(tcpProxy)},
+					},
+				},
+			},
+		},
+	}
+	if err := l.Validate(); err != nil {
+		logrus.Errorf("validate listener config failure %s", err.Error())
+		return nil
+	}
+	return l
+}
+
+//CreateUDPListener create udp listenner
+func CreateUDPListener(name, clusterName, address, statPrefix string, port uint32) *listener.Listener {
+	if address == "" {
+		address = DefaultLocalhostListenerAddress
+	}
+	config := &udp_proxy.UdpProxyConfig{
+		StatPrefix: statPrefix,
+		RouteSpecifier: &udp_proxy.UdpProxyConfig_Cluster{
+			Cluster: clusterName,
+		},
+	}
+	if err := config.Validate(); err != nil {
+		logrus.Errorf("validate listener udp config failure %s", err.Error())
+		return nil
+	}
+	l := &listener.Listener{
+		Name:    name,
+		Address: CreateSocketAddress("udp", address, port),
+		ListenerFilters: []*listener.ListenerFilter{
+			{
+				Name: "envoy.filters.udp_listener.udp_proxy",
+				ConfigType: &listener.ListenerFilter_TypedConfig{
+					TypedConfig: Message2Any(config),
+				},
+			},
+		},
+		// Listening on UDP without SO_REUSEPORT socket option may result to unstable packet proxying. Consider configuring the reuse_port listener option.
+		ReusePort: true,
+	}
+	if err := l.Validate(); err != nil {
+		logrus.Errorf("validate listener config failure %s", err.Error())
+		return nil
+	}
+	return l
+}
+
+//RateLimitOptions rate limit options
+type RateLimitOptions struct {
+	Enable                bool
+	Domain                string
+	RateServerClusterName string
+	Stage                 uint32
+}
+
+//DefaultRateLimitServerClusterName default rate limit server cluster name
+var DefaultRateLimitServerClusterName = "rate_limit_service_cluster"
+
+//CreateHTTPRateLimit create http rate limit
+func CreateHTTPRateLimit(option RateLimitOptions) *http_rate_limit.RateLimit {
+	httpRateLimit := &http_rate_limit.RateLimit{
+		Domain: option.Domain,
+		Stage:  option.Stage,
+		RateLimitService: &configratelimit.RateLimitServiceConfig{
+			GrpcService: &core.GrpcService{
+				TargetSpecifier: &core.GrpcService_EnvoyGrpc_{
+					EnvoyGrpc: &core.GrpcService_EnvoyGrpc{
+						ClusterName: option.RateServerClusterName,
+					},
+				},
+			},
+			TransportApiVersion: core.ApiVersion_V3,
+		},
+	}
+	if err := httpRateLimit.Validate(); err != nil {
+		logrus.Errorf("create http rate limit failure %s", err.Error())
+		return nil
+	}
+	logrus.Debugf("service http rate limit for domain %s", httpRateLimit.Domain)
+	return httpRateLimit
+}
+
+//CreateHTTPConnectionManager create http connection manager
+func CreateHTTPConnectionManager(name, statPrefix string, rateOpt *RateLimitOptions, routes ...*route.VirtualHost) *http_connection_manager.HttpConnectionManager {
+	var httpFilters []*http_connection_manager.HttpFilter
+	if rateOpt != nil && rateOpt.Enable {
+		if rateLimit := CreateHTTPRateLimit(*rateOpt); rateLimit != nil {
+			httpFilters = append(httpFilters, &http_connection_manager.HttpFilter{
+				Name: wellknown.HTTPRateLimit,
+				ConfigType: &http_connection_manager.HttpFilter_TypedConfig{
+					TypedConfig: Message2Any(rateLimit),
+				},
+			})
+		}
+	}
+	if hasFault(routes) {
+		//the routes carry their own faults, the filter injects nothing by default
+		httpFilters = append(httpFilters, &http_connection_manager.HttpFilter{
+			Name: wellknown.Fault,
+			ConfigType: &http_connection_manager.HttpFilter_TypedConfig{
+				TypedConfig: Message2Any(&http_fault.HTTPFault{}),
+			},
+		})
+	}
+	// v3 only accepts typed filter configs, the router has to carry an empty one
+	httpFilters = append(httpFilters, &http_connection_manager.HttpFilter{
+		Name: wellknown.Router,
+		ConfigType: &http_connection_manager.HttpFilter_TypedConfig{
+			TypedConfig: Message2Any(&router.Router{}),
+		},
+	})
+	hcm := &http_connection_manager.HttpConnectionManager{
+		StatPrefix: statPrefix,
+		RouteSpecifier: &http_connection_manager.HttpConnectionManager_RouteConfig{
+			RouteConfig: &route.RouteConfiguration{
+				Name:         name,
+				VirtualHosts: routes,
+			},
+		},
+		HttpFilters: httpFilters,
+	}
+	if err := hcm.Validate(); err != nil {
+		logrus.Errorf("validate http connertion manager config failure %s", err.Error())
+		return nil
+	}
+	return hcm
+}
+
+//CreateHTTPListener create http manager listener
+func CreateHTTPListener(name, address, statPrefix string, port uint32, rateOpt *RateLimitOptions, routes ...*route.VirtualHost) *listener.Listener {
+	hcm := CreateHTTPConnectionManager(name, statPrefix, rateOpt, routes...)
+	if hcm == nil {
+		logrus.Warningf("create http connection manager failure %s", name)
+		return nil
+	}
+	l := &listener.Listener{
+		Name:    name,
+		Address: CreateSocketAddress("tcp", address, port),
+		FilterChains: []*listener.FilterChain{
+			{
+				Filters: []*listener.Filter{
+					{
+						Name:       wellknown.HTTPConnectionManager,
+						ConfigType: &listener.Filter_TypedConfig{TypedConfig: Message2Any(hcm)},
+					},
+				},
+			},
+		},
+	}
+	if err := l.Validate(); err != nil {
+		logrus.Errorf("validate listener config failure %s", err.Error())
+		return nil
+	}
+	return l
+}
+
+//CreateSocketAddress create socket address
+func CreateSocketAddress(protocol, address string, port uint32) *core.Address {
+	if strings.HasPrefix(address, "https://") {
+		address = strings.Split(address, "https://")[1]
+	}
+	if strings.HasPrefix(address, "http://") {
+		address = strings.Split(address, "http://")[1]
+	}
+	// envoy requires the IPv6 address without brackets
+	address = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+	return &core.Address{
+		Address: &core.Address_SocketAddress{
+			SocketAddress: &core.SocketAddress{
+				Protocol: func(protocol string) core.SocketAddress_Protocol {
+					if protocol == "udp" {
+						return core.SocketAddress_UDP
+					}
+					return core.SocketAddress_TCP
+				}(protocol),
+				Address: address,
+				PortSpecifier: &core.SocketAddress_PortValue{
+					PortValue: port,
+				},
+			},
+		},
+	}
+}
+
+//CreateCircuitBreaker create down cluster circuitbreaker
+func CreateCircuitBreaker(options KatoPluginOptions) *cluster.CircuitBreakers {
+	circuitBreakers := &cluster.CircuitBreakers{
+		Thresholds: []*cluster.CircuitBreakers_Thresholds{
+			{
+				Priority:           core.RoutingPriority_DEFAULT,
+				MaxConnections:     ConversionUInt32(uint32(options.MaxConnections)),
+				MaxRequests:        ConversionUInt32(uint32(options.MaxRequests)),
+				MaxRetries:         ConversionUInt32(uint32(options.MaxActiveRetries)),
+				MaxPendingRequests: ConversionUInt32(uint32(options.MaxPendingRequests)),
+			},
+		},
+	}
+	if err := circuitBreakers.Validate(); err != nil {
+		logrus.Errorf("validate envoy config circuitBreakers failure %s", err.Error())
+		return nil
+	}
+	return circuitBreakers
+}
+
+//CreatOutlierDetection create up cluster OutlierDetection
+func CreatOutlierDetection(options KatoPluginOptions) *cluster.OutlierDetection {
+	outlierDetection := &cluster.OutlierDetection{
+		Interval:           ConverTimeDuration(options.Interval),
+		BaseEjectionTime:   ConverTimeDuration(options.BaseEjectionTimeMS / 1000),
+		MaxEjectionPercent: ConversionUInt32(uint32(options.MaxEjectionPercent)),
+		Consecutive_5Xx:    ConversionUInt32(uint32(options.ConsecutiveErrors)),
+	}
+	if err := outlierDetection.Validate(); err != nil {
+		logrus.Errorf("validate envoy config outlierDetection failure %s", err.Error())
+		return nil
+	}
+	return outlierDetection
+}
+
+//CreateRouteVirtualHost create route virtual host
+func CreateRouteVirtualHost(name string, domains []string, rateLimits []*route.RateLimit, routes ...*route.Route) *route.VirtualHost {
+	pvh := &route.VirtualHost{
+		Name:       name,
+		Domains:    domains,
+		Routes:     routes,
+		RateLimits: rateLimits,
+	}
+	if err := pvh.Validate(); err != nil {
+		logrus.Errorf("route virtualhost config validate failure %s domains %s", err.Error(), domains)
+		return nil
+	}
+	return pvh
+}
+
+//CreateRouteWithHostRewrite create route with hostRewrite
+func CreateRouteWithHostRewrite(host, clusterName, prefix string, headers []*route.HeaderMatcher, weight uint32) *route.Route {
+	if host == "" {
+		return nil
+	}
+	if strings.HasPrefix(host, "https://") {
+		host = strings.Split(host, "https://")[1]
+	}
+	if strings.HasPrefix(host, "http://") {
+		host = strings.Split(host, "http://")[1]
+	}
+	rout := &route.Route{
+		Match: &route.RouteMatch{
+			PathSpecifier: &route.RouteMatch_Prefix{
+				Prefix: prefix,
+			},
+			Headers: headers,
+		},
+		Action: &route.Route_Route{
+			Route: &route.RouteAction{
+				ClusterSpecifier: &route.RouteAction_Cluster{
+					Cluster: clusterName,
+				},
+				Priority: core.RoutingPriority_DEFAULT,
+				HostRewriteSpecifier: &route.RouteAction_HostRewriteLiteral{
+					HostRewriteLiteral: host,
+				},
+			},
+		},
+	}
+	if err := rout.Validate(); err != nil {
+		logrus.Errorf("route http route config validate failure %s", err.Error())
+		return nil
+	}
+	return rout
+}
+
+//CreateRoute create http route
+func CreateRoute(clusterName, prefix string, headers []*route.HeaderMatcher, weight uint32) *route.Route {
+	rout := &route.Route{
+		Match: &route.RouteMatch{
+			PathSpecifier: &route.RouteMatch_Prefix{
+				Prefix: prefix,
+			},
+			Headers: headers,
+		},
+		Action: &route.Route_Route{
+			Route: &route.RouteAction{
+				ClusterSpecifier: &route.RouteAction_WeightedClusters{
+					WeightedClusters: &route.WeightedCluster{
+						Clusters: []*route.WeightedCluster_ClusterWeight{
+							{
+								Name:   clusterName,
+								Weight: ConversionUInt32(weight),
+							},
+						},
+					},
+				},
+				Priority: core.RoutingPriority_DEFAULT,
+			},
+		},
+	}
+
+	if err := rout.Validate(); err != nil {
+		logrus.Errorf("route http route config validate failure %s", err.Error())
+		return nil
+	}
+	return rout
+}
+
+//CreateRetryPolicy create the retry policy of a route, nil if the retry is disabled
+func CreateRetryPolicy(options KatoPluginOptions) *route.RetryPolicy {
+	if options.RetryAttempts == 0 {
+		return nil
+	}
+	retryPolicy := &route.RetryPolicy{
+		RetryOn:    options.RetryOn,
+		NumRetries: ConversionUInt32(options.RetryAttempts),
+	}
+	if options.PerTryTimeoutMS > 0 {
+		retryPolicy.PerTryTimeout = ConverTimeDurationMS(options.PerTryTimeoutMS)
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		logrus.Errorf("validate envoy config retry policy failure %s", err.Error())
+		return nil
+	}
+	return retryPolicy
+}
+
+//CreateFractionalPercent create fractional percent with the precision of 0.0001%
+func CreateFractionalPercent(percent float64) *_type.FractionalPercent {
+	return &_type.FractionalPercent{
+		Numerator:   uint32(percent*10000 + 0.5),
+		Denominator: _type.FractionalPercent_MILLION,
+	}
+}
+
+//CreateHTTPFault create the fault injection of a route, nil if no fault is injected
+func CreateHTTPFault(options KatoPluginOptions) *http_fault.HTTPFault {
+	if !options.HasFault() {
+		return nil
+	}
+	httpFault := &http_fault.HTTPFault{}
+	if options.FaultDelayMS > 0 && options.FaultDelayPercent > 0 {
+		httpFault.Delay = &fault_common.FaultDelay{
+			FaultDelaySecifier: &fault_common.FaultDelay_FixedDelay{
+				FixedDelay: ConverTimeDurationMS(options.FaultDelayMS),
+			},
+			Percentage: CreateFractionalPercent(options.FaultDelayPercent),
+		}
+	}
+	if options.FaultAbortStatus != 0 && options.FaultAbortPercent > 0 {
+		httpFault.Abort = &http_fault.FaultAbort{
+			ErrorType: &http_fault.FaultAbort_HttpStatus{
+				HttpStatus: options.FaultAbortStatus,
+			},
+			Percentage: CreateFractionalPercent(options.FaultAbortPercent),
+		}
+	}
+	if err := httpFault.Validate(); err != nil {
+		logrus.Errorf("validate envoy config http fault failure %s", err.Error())
+		return nil
+	}
+	return httpFault
+}
+
+//SetRoutePolicy set the retry policy, the timeout and the fault injection of the options on the route.
+//The fault injection takes effect only if the http connection manager has the fault filter,
+//CreateHTTPConnectionManager adds it if any route injects faults.
+func SetRoutePolicy(rout *route.Route, options KatoPluginOptions) {
+	action, ok := rout.Action.(*route.Route_Route)
+	if !ok {
+		return
+	}
+	action.Route.RetryPolicy = CreateRetryPolicy(options)
+	if options.RequestTimeoutMS != nil {
+		action.Route.Timeout = ConverTimeDurationMS(*options.RequestTimeoutMS)
+	}
+	if httpFault := CreateHTTPFault(options); httpFault != nil {
+		rout.TypedPerFilterConfig = map[string]*any.Any{
+			wellknown.Fault: Message2Any(httpFault),
+		}
+	}
+}
+
+//hasFault whether any route of the virtual hosts injects faults
+func hasFault(virtualHosts []*route.VirtualHost) bool {
+	for _, virtualHost := range virtualHosts {
+		for _, rout := range virtualHost.Routes {
+			if _, ok := rout.TypedPerFilterConfig[wellknown.Fault]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//CreateHeaderMatcher create http route config header matcher
+func CreateHeaderMatcher(header v1.Header) *route.HeaderMatcher {
+	if header.Name == "" {
+		return nil
+	}
+	headerMatcher := &route.HeaderMatcher{
+		Name: header.Name,
+		HeaderMatchSpecifier: &route.HeaderMatcher_PrefixMatch{
+			PrefixMatch: header.Value,
+		},
+	}
+	if err := headerMatcher.Validate(); err != nil {
+		logrus.Errorf("route http header(%s) matcher config validate failure %s", header.Name, err.Error())
+		return nil
+	}
+	return headerMatcher
+}
+
+//CreateEDSClusterConfig create eds cluster config, endpoints are delivered over the aggregated discovery stream
+func CreateEDSClusterConfig(serviceName string) *cluster.Cluster_EdsClusterConfig {
+	edsClusterConfig := &cluster.Cluster_EdsClusterConfig{
+		EdsConfig: &core.ConfigSource{
+			ConfigSourceSpecifier: &core.ConfigSource_Ads{
+				Ads: &core.AggregatedConfigSource{},
+			},
+			ResourceApiVersion: core.ApiVersion_V3,
+		},
+		ServiceName: serviceName,
+	}
+	if err := edsClusterConfig.Validate(); err != nil {
+		logrus.Errorf("validate eds cluster config failure %s", err.Error())
+		return nil
+	}
+	return edsClusterConfig
+}
+
+//ClusterOptions cluster options
+type ClusterOptions struct {
+	Name                     string
+	ServiceName              string
+	ConnectionTimeout        *duration.Duration
+	ClusterType              cluster.Cluster_DiscoveryType
+	MaxRequestsPerConnection *uint32
+	OutlierDetection         *cluster.OutlierDetection
+	CircuitBreakers          *cluster.CircuitBreakers
+	// Hosts of a static cluster, v3 has no hosts field so they become the load assignment
+	Hosts                 []*core.Address
+	HealthyPanicThreshold int64
+	TransportSocket       *core.TransportSocket
+	LoadAssignment        *endpoint.ClusterLoadAssignment
+	Protocol              string
+	// grpc service name of health check
+	GrpcHealthServiceName string
+	//health check
+	HealthTimeout  int64
+	HealthInterval int64
+}
+
+//CreateCluster create cluster config
+func CreateCluster(options ClusterOptions) *cluster.Cluster {
+	var edsClusterConfig *cluster.Cluster_EdsClusterConfig
+	if options.ClusterType == cluster.Cluster_EDS {
+		edsClusterConfig = CreateEDSClusterConfig(options.ServiceName)
+		if edsClusterConfig == nil {
+			logrus.Errorf("create eds cluster config failure")
+			return nil
+		}
+	}
+	c := &cluster.Cluster{
+		Name:                 options.Name,
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: options.ClusterType},
+		ConnectTimeout:       options.ConnectionTimeout,
+		LbPolicy:             cluster.Cluster_ROUND_ROBIN,
+		EdsClusterConfig:     edsClusterConfig,
+		OutlierDetection:     options.OutlierDetection,
+		CircuitBreakers:      options.CircuitBreakers,
+		CommonLbConfig: &cluster.Cluster_CommonLbConfig{
+			HealthyPanicThreshold: &_type.Percent{Value: float64(options.HealthyPanicThreshold) / 100},
+		},
+	}
+	if options.Protocol == "http2" || options.Protocol == "grpc" {
+		c.Http2ProtocolOptions = &core.Http2ProtocolOptions{}
+		// set grpc health check
+		if options.Protocol == "grpc" && options.GrpcHealthServiceName != "" {
+			c.HealthChecks = append(c.HealthChecks, &core.HealthCheck{
+				Timeout:  ConverTimeDuration(options.HealthTimeout),
+				Interval: ConverTimeDuration(options.HealthInterval),
+				//The number of unhealthy health checks required before a host is marked unhealthy.
+				//Note that for http health checking if a host responds with 503 this threshold is ignored and the host is considered unhealthy immediately.
+				UnhealthyThreshold: ConversionUInt32(2),
+				//The number of healthy health checks required before a host is marked healthy.
+				//Note that during startup, only a single successful health check is required to mark a host healthy.
+				HealthyThreshold: ConversionUInt32(1),
+				HealthChecker: &core.HealthCheck_GrpcHealthCheck_{
+					GrpcHealthCheck: &core.HealthCheck_GrpcHealthCheck{
+						ServiceName: options.GrpcHealthServiceName,
+					},
+				}})
+		}
+	}
+	if options.TransportSocket != nil {
+		c.TransportSocket = options.TransportSocket
+	}
+	if options.LoadAssignment != nil {
+		c.LoadAssignment = options.LoadAssignment
+	} else if len(options.Hosts) > 0 {
+		c.LoadAssignment = CreateStaticLoadAssignment(options.Name, options.Hosts...)
+	}
+	if options.MaxRequestsPerConnection != nil {
+		c.MaxRequestsPerConnection = ConversionUInt32(*options.MaxRequestsPerConnection)
+	}
+	if err := c.Validate(); err != nil {
+		logrus.Errorf("validate cluster config failure %s", err.Error())
+		return nil
+	}
+	return c
+}
+
+//CreateStaticLoadAssignment create the load assignment of a static cluster
+func CreateStaticLoadAssignment(clusterName string, hosts ...*core.Address) *endpoint.ClusterLoadAssignment {
+	var lbe []*endpoint.LbEndpoint
+	for _, host := range hosts {
+		lbe = append(lbe, &endpoint.LbEndpoint{
+			HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+				Endpoint: &endpoint.Endpoint{
+					Address: host,
+				},
+			},
+		})
+	}
+	return &endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints:   []*endpoint.LocalityLbEndpoints{{LbEndpoints: lbe}},
+	}
+}
+
+//GetServiceAliasByService get service alias from k8s service
+func GetServiceAliasByService(service *corev1.Service) string {
+	//v5.1 and later
+	if serviceAlias, ok := service.Labels["service_alias"]; ok {
+		return serviceAlias
+	}
+	//version before v5.1
+	if serviceAlias, ok := service.Spec.Selector["name"]; ok {
+		return serviceAlias
+	}
+	return ""
+}
+
+//CreateDNSLoadAssignment create dns loadAssignment
+func CreateDNSLoadAssignment(serviceAlias, namespace, domain string, service *corev1.Service) *endpoint.ClusterLoadAssignment {
+	destServiceAlias := GetServiceAliasByService(service)
+	if destServiceAlias == "" {
+		logrus.Errorf("service alias is empty in k8s service %s", service.Name)
+		return nil
+	}
+
+	clusterName := fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, destServiceAlias, service.Spec.Ports[0].Port)
+	protocol := service.Labels["port_protocol"]
+	port := service.Spec.Ports[0].Port
+	lbe := []*endpoint.LbEndpoint{
+		{
+			HostIdentifier: &endpoint.LbEndpoint_Endpoint{
+				Endpoint: &endpoint.Endpoint{
+					Address:           CreateSocketAddress(protocol, domain, uint32(port)),
+					HealthCheckConfig: &endpoint.Endpoint_HealthCheckConfig{PortValue: uint32(port)},
+				},
+			},
+		},
+	}
+	cla := &endpoint.ClusterLoadAssignment{
+		ClusterName: clusterName,
+		Endpoints:   []*endpoint.LocalityLbEndpoints{{LbEndpoints: lbe}},
+	}
+	if err := cla.Validate(); err != nil {
+		logrus.Errorf("endpoints discover validate failure %s", err.Error())
+	}
+
+	return cla
+}
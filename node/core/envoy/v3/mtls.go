@@ -0,0 +1,223 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package v3
+
+import (
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	rbacconfig "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	tls_inspector "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/listener/tls_inspector/v3"
+	rbac "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/rbac/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/sirupsen/logrus"
+)
+
+// the names of the sds secrets of the mesh mutual tls
+const (
+	// IdentitySecretName is the certificate and key of the component identity
+	IdentitySecretName = "kato-mesh-identity"
+	// RootCASecretName is the ca certificate validating the peers
+	RootCASecretName = "kato-mesh-root-ca"
+)
+
+//CreateTLSCertificateSecret create the sds secret of a certificate chain and its private key
+func CreateTLSCertificateSecret(name string, certPEM, keyPEM []byte) *tls.Secret {
+	return &tls.Secret{
+		Name: name,
+		Type: &tls.Secret_TlsCertificate{
+			TlsCertificate: &tls.TlsCertificate{
+				CertificateChain: &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: certPEM}},
+				PrivateKey:       &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: keyPEM}},
+			},
+		},
+	}
+}
+
+//CreateValidationContextSecret create the sds secret of the trusted ca certificates
+func CreateValidationContextSecret(name string, caPEM []byte) *tls.Secret {
+	return &tls.Secret{
+		Name: name,
+		Type: &tls.Secret_ValidationContext{
+			ValidationContext: &tls.CertificateValidationContext{
+				TrustedCa: &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: caPEM}},
+			},
+		},
+	}
+}
+
+//CreateSDSSecretConfig create the config of a secret delivered over ads
+func CreateSDSSecretConfig(name string) *tls.SdsSecretConfig {
+	return &tls.SdsSecretConfig{
+		Name: name,
+		SdsConfig: &core.ConfigSource{
+			ConfigSourceSpecifier: &core.ConfigSource_Ads{
+				Ads: &core.AggregatedConfigSource{},
+			},
+			ResourceApiVersion: core.ApiVersion_V3,
+		},
+	}
+}
+
+//CreateMeshCommonTLSContext create the tls context presenting the identity certificate and validating the peer
+//with the root ca. If peerSANs is not empty, the peer certificate must have one of them.
+func CreateMeshCommonTLSContext(peerSANs ...string) *tls.CommonTlsContext {
+	var sanMatchers []*matcher.StringMatcher
+	for _, san := range peerSANs {
+		sanMatchers = append(sanMatchers, &matcher.StringMatcher{
+			MatchPattern: &matcher.StringMatcher_Exact{Exact: san},
+		})
+	}
+	return &tls.CommonTlsContext{
+		TlsCertificateSdsSecretConfigs: []*tls.SdsSecretConfig{CreateSDSSecretConfig(IdentitySecretName)},
+		ValidationContextType: &tls.CommonTlsContext_CombinedValidationContext{
+			CombinedValidationContext: &tls.CommonTlsContext_CombinedCertificateValidationContext{
+				DefaultValidationContext:         &tls.CertificateValidationContext{MatchSubjectAltNames: sanMatchers},
+				ValidationContextSdsSecretConfig: CreateSDSSecretConfig(RootCASecretName),
+			},
+		},
+	}
+}
+
+//CreateUpstreamMTLSTransportSocket create the transport socket of a cluster calling a component over mutual tls,
+//peerSAN is the identity uri of the called component
+func CreateUpstreamMTLSTransportSocket(peerSAN string) *core.TransportSocket {
+	tlsContext := &tls.UpstreamTlsContext{
+		CommonTlsContext: CreateMeshCommonTLSContext(peerSAN),
+	}
+	if err := tlsContext.Validate(); err != nil {
+		logrus.Errorf("validate upstream mtls context failure %s", err.Error())
+		return nil
+	}
+	return &core.TransportSocket{
+		Name:       wellknown.TransportSocketTls,
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: Message2Any(tlsContext)},
+	}
+}
+
+//CreateDownstreamMTLSTransportSocket create the transport socket of a listener accepting mutual tls only
+func CreateDownstreamMTLSTransportSocket() *core.TransportSocket {
+	tlsContext := &tls.DownstreamTlsContext{
+		CommonTlsContext:         CreateMeshCommonTLSContext(),
+		RequireClientCertificate: &wrappers.BoolValue{Value: true},
+	}
+	if err := tlsContext.Validate(); err != nil {
+		logrus.Errorf("validate downstream mtls context failure %s", err.Error())
+		return nil
+	}
+	return &core.TransportSocket{
+		Name:       wellknown.TransportSocketTls,
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: Message2Any(tlsContext)},
+	}
+}
+
+//CreateRBACFilter create the network filter allowing the peers whose identity uri matches one of the regular expressions
+func CreateRBACFilter(statPrefix string, principalRegexes []string) *listener.Filter {
+	var principals []*rbacconfig.Principal
+	for _, expr := range principalRegexes {
+		principals = append(principals, &rbacconfig.Principal{
+			Identifier: &rbacconfig.Principal_Authenticated_{
+				Authenticated: &rbacconfig.Principal_Authenticated{
+					PrincipalName: &matcher.StringMatcher{
+						MatchPattern: &matcher.StringMatcher_SafeRegex{
+							SafeRegex: &matcher.RegexMatcher{
+								EngineType: &matcher.RegexMatcher_GoogleRe2{GoogleRe2: &matcher.RegexMatcher_GoogleRE2{}},
+								Regex:      expr,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	config := &rbac.RBAC{
+		StatPrefix: statPrefix,
+		Rules: &rbacconfig.RBAC{
+			Action: rbacconfig.RBAC_ALLOW,
+			Policies: map[string]*rbacconfig.Policy{
+				"allowed-sources": {
+					Permissions: []*rbacconfig.Permission{{Rule: &rbacconfig.Permission_Any{Any: true}}},
+					Principals:  principals,
+				},
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		logrus.Errorf("validate rbac filter config failure %s", err.Error())
+		return nil
+	}
+	return &listener.Filter{
+		Name:       wellknown.RoleBasedAccessControl,
+		ConfigType: &listener.Filter_TypedConfig{TypedConfig: Message2Any(config)},
+	}
+}
+
+//MeshTLSOptions the mutual tls options of an inbound listener
+type MeshTLSOptions struct {
+	// Strict refuses the plaintext connections, otherwise both mutual tls and plaintext are accepted
+	Strict bool
+	// AllowedPrincipals are the regular expressions of the identity uris allowed to connect over mutual tls,
+	// all the identities issued by the mesh ca are allowed if empty
+	AllowedPrincipals []string
+	StatPrefix        string
+}
+
+//SecureListener terminates mutual tls on a tcp based listener, the mutual tls filter chain is a copy of the
+//plaintext one with the rbac filter in front of it. The plaintext filter chain is kept in permissive mode,
+//the tls inspector tells the two apart.
+func SecureListener(l *listener.Listener, options MeshTLSOptions) *listener.Listener {
+	if l == nil || len(l.FilterChains) != 1 {
+		return l
+	}
+	transportSocket := CreateDownstreamMTLSTransportSocket()
+	if transportSocket == nil {
+		return nil
+	}
+	plaintext := l.FilterChains[0]
+	var filters []*listener.Filter
+	if len(options.AllowedPrincipals) > 0 {
+		rbacFilter := CreateRBACFilter(options.StatPrefix, options.AllowedPrincipals)
+		if rbacFilter == nil {
+			return nil
+		}
+		filters = append(filters, rbacFilter)
+	}
+	filters = append(filters, plaintext.Filters...)
+	mtls := &listener.FilterChain{
+		Filters:         filters,
+		TransportSocket: transportSocket,
+	}
+	if options.Strict {
+		l.FilterChains = []*listener.FilterChain{mtls}
+	} else {
+		mtls.FilterChainMatch = &listener.FilterChainMatch{TransportProtocol: "tls"}
+		l.FilterChains = []*listener.FilterChain{mtls, plaintext}
+		l.ListenerFilters = append(l.ListenerFilters, &listener.ListenerFilter{
+			Name:       wellknown.TlsInspector,
+			ConfigType: &listener.ListenerFilter_TypedConfig{TypedConfig: Message2Any(&tls_inspector.TlsInspector{})},
+		})
+	}
+	if err := l.Validate(); err != nil {
+		logrus.Errorf("validate mtls listener config failure %s", err.Error())
+		return nil
+	}
+	return l
+}
@@ -0,0 +1,95 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package v3
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/sirupsen/logrus"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	envoyv2 "github.com/gridworkz/kato/node/core/envoy/v2"
+)
+
+// Message2Any converts from proto message to proto any
+func Message2Any(msg proto.Message) *any.Any {
+	a, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		logrus.Error(err.Error())
+		return &any.Any{}
+	}
+	return a
+}
+
+//ConversionUInt32 conversion uint32 to wrappers uint32
+func ConversionUInt32(value uint32) *wrappers.UInt32Value {
+	return &wrappers.UInt32Value{
+		Value: value,
+	}
+}
+
+//ConverTimeDuration second
+func ConverTimeDuration(second int64) *duration.Duration {
+	return &duration.Duration{
+		Seconds: second,
+	}
+}
+
+//ConverTimeDurationMS millisecond
+func ConverTimeDurationMS(ms int64) *duration.Duration {
+	return &duration.Duration{
+		Seconds: ms / 1000,
+		Nanos:   int32(ms%1000) * 1000000,
+	}
+}
+
+//KatoPluginOptions kato plugin config struct
+//the plugin options do not depend on the xds api version, they are shared with the v2 builder
+type KatoPluginOptions = envoyv2.KatoPluginOptions
+
+//KatoInboundPluginOptions kato inbound plugin options
+type KatoInboundPluginOptions = envoyv2.KatoInboundPluginOptions
+
+//GetOptionValues get value from options
+//if not exist,return default value
+func GetOptionValues(sr map[string]interface{}) KatoPluginOptions {
+	return envoyv2.GetOptionValues(sr)
+}
+
+//GetKatoInboundPluginOptions get kato inbound plugin options
+func GetKatoInboundPluginOptions(sr map[string]interface{}) KatoInboundPluginOptions {
+	return envoyv2.GetKatoInboundPluginOptions(sr)
+}
+
+//CheckWeightSum check all cluster weight sum
+func CheckWeightSum(clusters []*route.WeightedCluster_ClusterWeight, weight uint32) uint32 {
+	var sum uint32
+	for _, cluster := range clusters {
+		sum += cluster.Weight.GetValue()
+	}
+	if sum >= 100 {
+		return 0
+	}
+	if (sum + weight) > 100 {
+		return 100 - sum
+	}
+	return weight
+}
@@ -0,0 +1,312 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package containerruntime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+const (
+	defaultConnectionTimeout = 10 * time.Second
+	// the cri v1alpha2 has no event api, the containers are listed periodically instead
+	defaultWatchPeriod = 2 * time.Second
+	maxMsgSize         = 1024 * 1024 * 16
+)
+
+// CRIRuntime is the container runtime serving the cri, such as containerd
+type CRIRuntime struct {
+	name          string
+	runtimeClient runtimeapi.RuntimeServiceClient
+	imageClient   runtimeapi.ImageServiceClient
+	watchPeriod   time.Duration
+}
+
+// NewCRIRuntime connects to the cri runtime listening on the unix socket endpoint
+func NewCRIRuntime(endpoint string, timeout time.Duration) (*CRIRuntime, error) {
+	addr, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("connect cri endpoint %s failure %s", endpoint, err.Error())
+	}
+	r := &CRIRuntime{
+		runtimeClient: runtimeapi.NewRuntimeServiceClient(conn),
+		imageClient:   runtimeapi.NewImageServiceClient(conn),
+		watchPeriod:   defaultWatchPeriod,
+	}
+	vctx, vcancel := context.WithTimeout(context.Background(), timeout)
+	defer vcancel()
+	version, err := r.runtimeClient.Version(vctx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("get cri runtime version failure %s", err.Error())
+	}
+	r.name = version.RuntimeName
+	logrus.Infof("connected to container runtime %s %s, cri %s", version.RuntimeName, version.RuntimeVersion, version.RuntimeApiVersion)
+	return r, nil
+}
+
+// parseEndpoint returns the socket path of unix:///path or /path
+func parseEndpoint(endpoint string) (string, error) {
+	if strings.HasPrefix(endpoint, "/") {
+		return endpoint, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid cri endpoint %s: %v", endpoint, err)
+	}
+	if u.Scheme != "unix" || u.Path == "" {
+		return "", fmt.Errorf("invalid cri endpoint %s, only unix socket is supported", endpoint)
+	}
+	return u.Path, nil
+}
+
+// Name returns the name reported by the runtime, such as containerd
+func (r *CRIRuntime) Name() string {
+	return r.name
+}
+
+// ListContainers lists the running containers
+func (r *CRIRuntime) ListContainers(ctx context.Context) ([]*Container, error) {
+	res, err := r.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{
+		Filter: &runtimeapi.ContainerFilter{
+			State: &runtimeapi.ContainerStateValue{State: runtimeapi.ContainerState_CONTAINER_RUNNING},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var re []*Container
+	for _, c := range res.Containers {
+		container := &Container{
+			ID:      c.Id,
+			Created: time.Unix(0, c.CreatedAt),
+			Labels:  c.Labels,
+			Running: c.State == runtimeapi.ContainerState_CONTAINER_RUNNING,
+		}
+		if c.Metadata != nil {
+			container.Name = c.Metadata.Name
+		}
+		if c.Image != nil {
+			container.Image = c.Image.Image
+		}
+		re = append(re, container)
+	}
+	return re, nil
+}
+
+// criContainerInfo is the verbose info of the container status reported by containerd
+type criContainerInfo struct {
+	Config *struct {
+		Envs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"envs"`
+	} `json:"config"`
+	RuntimeSpec *struct {
+		Process *struct {
+			Args []string `json:"args"`
+			Env  []string `json:"env"`
+		} `json:"process"`
+	} `json:"runtimeSpec"`
+}
+
+// InspectContainer returns the container, the env and the args are read from the verbose info
+func (r *CRIRuntime) InspectContainer(ctx context.Context, containerID string) (*Container, error) {
+	res, err := r.runtimeClient.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
+		ContainerId: containerID,
+		Verbose:     true,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrContainerNotFound
+		}
+		return nil, err
+	}
+	s := res.Status
+	if s == nil {
+		return nil, fmt.Errorf("container %s has no status", containerID)
+	}
+	container := &Container{
+		ID:        s.Id,
+		Created:   time.Unix(0, s.CreatedAt),
+		Labels:    s.Labels,
+		LogPath:   s.LogPath,
+		LogFormat: LogFormatCRI,
+		Running:   s.State == runtimeapi.ContainerState_CONTAINER_RUNNING,
+	}
+	if s.Metadata != nil {
+		container.Name = s.Metadata.Name
+	}
+	if s.Image != nil {
+		container.Image = s.Image.Image
+	}
+	if raw, ok := res.Info["info"]; ok {
+		var info criContainerInfo
+		if err := json.Unmarshal([]byte(raw), &info); err != nil {
+			logrus.Warningf("parse the info of container %s failure %s", containerID, err.Error())
+		}
+		if info.RuntimeSpec != nil && info.RuntimeSpec.Process != nil {
+			if args := info.RuntimeSpec.Process.Args; len(args) > 0 {
+				container.Path = args[0]
+				container.Args = args[1:]
+			}
+			container.Env = info.RuntimeSpec.Process.Env
+		}
+		// the runtime spec also contains the envs of the image, the config only those of the pod
+		if len(container.Env) == 0 && info.Config != nil {
+			for _, env := range info.Config.Envs {
+				container.Env = append(container.Env, env.Key+"="+env.Value)
+			}
+		}
+	}
+	return container, nil
+}
+
+// WatchContainers lists the containers in all states every watch period, sends start for the new ones,
+// die for those no longer running and destroy for the removed ones. The containers created and exited
+// between two listings are still sent as started, their log files are kept until they are removed.
+func (r *CRIRuntime) WatchContainers(ctx context.Context, events chan<- ContainerEvent) error {
+	states, err := r.containerStates(ctx)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(r.watchPeriod)
+	defer ticker.Stop()
+	send := func(action, containerID string) bool {
+		select {
+		case events <- ContainerEvent{Action: action, ContainerID: containerID}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := r.containerStates(ctx)
+		if err != nil {
+			return err
+		}
+		for id, running := range current {
+			wasRunning, exist := states[id]
+			if !exist && !send(ContainerActionStart, id) {
+				return nil
+			}
+			if exist && wasRunning && !running && !send(ContainerActionDie, id) {
+				return nil
+			}
+		}
+		for id := range states {
+			if _, exist := current[id]; !exist && !send(ContainerActionDestroy, id) {
+				return nil
+			}
+		}
+		states = current
+	}
+}
+
+// containerStates returns whether the containers in all states are running
+func (r *CRIRuntime) containerStates(ctx context.Context) (map[string]bool, error) {
+	res, err := r.runtimeClient.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]bool, len(res.Containers))
+	for _, c := range res.Containers {
+		states[c.Id] = c.State == runtimeapi.ContainerState_CONTAINER_RUNNING
+	}
+	return states, nil
+}
+
+// ListImages lists all the images
+func (r *CRIRuntime) ListImages(ctx context.Context) ([]*Image, error) {
+	res, err := r.imageClient.ListImages(ctx, &runtimeapi.ListImagesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var re []*Image
+	for _, image := range res.Images {
+		re = append(re, &Image{
+			ID:       image.Id,
+			RepoTags: image.RepoTags,
+			Size:     int64(image.Size_),
+		})
+	}
+	return re, nil
+}
+
+// GetImageRef returns the id of the image
+func (r *CRIRuntime) GetImageRef(ctx context.Context, image string) (string, error) {
+	res, err := r.imageClient.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{
+		Image: &runtimeapi.ImageSpec{Image: image},
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.Image == nil {
+		return "", ErrImageNotFound
+	}
+	return res.Image.Id, nil
+}
+
+// RemoveImage removes the image by id
+func (r *CRIRuntime) RemoveImage(ctx context.Context, imageID string) error {
+	_, err := r.imageClient.RemoveImage(ctx, &runtimeapi.RemoveImageRequest{
+		Image: &runtimeapi.ImageSpec{Image: imageID},
+	})
+	return err
+}
+
+// ImageFsPath returns the mountpoint of the image filesystem
+func (r *CRIRuntime) ImageFsPath(ctx context.Context) (string, error) {
+	res, err := r.imageClient.ImageFsInfo(ctx, &runtimeapi.ImageFsInfoRequest{})
+	if err != nil {
+		return "", err
+	}
+	for _, fs := range res.ImageFilesystems {
+		if fs.FsId != nil && fs.FsId.Mountpoint != "" {
+			return fs.FsId.Mountpoint, nil
+		}
+	}
+	return "", fmt.Errorf("the runtime %s reports no image filesystem", r.name)
+}
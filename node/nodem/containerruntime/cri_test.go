@@ -0,0 +1,322 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package containerruntime
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1alpha2"
+)
+
+// fakeCRIServer serves the part of the cri used by the node
+type fakeCRIServer struct {
+	runtimeapi.UnimplementedRuntimeServiceServer
+	runtimeapi.UnimplementedImageServiceServer
+	lock       sync.Mutex
+	containers map[string]*runtimeapi.ContainerStatus
+	infos      map[string]string
+	images     map[string]*runtimeapi.Image
+}
+
+func newFakeCRIServer() *fakeCRIServer {
+	return &fakeCRIServer{
+		containers: make(map[string]*runtimeapi.ContainerStatus),
+		infos:      make(map[string]string),
+		images:     make(map[string]*runtimeapi.Image),
+	}
+}
+
+func (f *fakeCRIServer) setContainer(id string, state runtimeapi.ContainerState, info string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.containers[id] = &runtimeapi.ContainerStatus{
+		Id:        id,
+		Metadata:  &runtimeapi.ContainerMetadata{Name: "web"},
+		State:     state,
+		CreatedAt: time.Now().UnixNano(),
+		Image:     &runtimeapi.ImageSpec{Image: "nginx:latest"},
+		Labels:    map[string]string{"io.kubernetes.pod.name": "web-0"},
+		LogPath:   "/var/log/pods/web-0/web/0.log",
+	}
+	f.infos[id] = info
+}
+
+func (f *fakeCRIServer) removeContainer(id string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.containers, id)
+	delete(f.infos, id)
+}
+
+func (f *fakeCRIServer) Version(ctx context.Context, req *runtimeapi.VersionRequest) (*runtimeapi.VersionResponse, error) {
+	return &runtimeapi.VersionResponse{RuntimeName: "containerd", RuntimeVersion: "v1.4.3", RuntimeApiVersion: "v1alpha2"}, nil
+}
+
+func (f *fakeCRIServer) ListContainers(ctx context.Context, req *runtimeapi.ListContainersRequest) (*runtimeapi.ListContainersResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var containers []*runtimeapi.Container
+	for _, s := range f.containers {
+		if req.Filter != nil && req.Filter.State != nil && req.Filter.State.State != s.State {
+			continue
+		}
+		containers = append(containers, &runtimeapi.Container{
+			Id:        s.Id,
+			Metadata:  s.Metadata,
+			Image:     s.Image,
+			State:     s.State,
+			CreatedAt: s.CreatedAt,
+			Labels:    s.Labels,
+		})
+	}
+	return &runtimeapi.ListContainersResponse{Containers: containers}, nil
+}
+
+func (f *fakeCRIServer) ContainerStatus(ctx context.Context, req *runtimeapi.ContainerStatusRequest) (*runtimeapi.ContainerStatusResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	s, ok := f.containers[req.ContainerId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "container %s not found", req.ContainerId)
+	}
+	res := &runtimeapi.ContainerStatusResponse{Status: s}
+	if req.Verbose {
+		res.Info = map[string]string{"info": f.infos[req.ContainerId]}
+	}
+	return res, nil
+}
+
+func (f *fakeCRIServer) ListImages(ctx context.Context, req *runtimeapi.ListImagesRequest) (*runtimeapi.ListImagesResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var images []*runtimeapi.Image
+	for _, image := range f.images {
+		images = append(images, image)
+	}
+	return &runtimeapi.ListImagesResponse{Images: images}, nil
+}
+
+func (f *fakeCRIServer) ImageStatus(ctx context.Context, req *runtimeapi.ImageStatusRequest) (*runtimeapi.ImageStatusResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, image := range f.images {
+		if image.Id == req.Image.Image {
+			return &runtimeapi.ImageStatusResponse{Image: image}, nil
+		}
+		for _, tag := range image.RepoTags {
+			if tag == req.Image.Image {
+				return &runtimeapi.ImageStatusResponse{Image: image}, nil
+			}
+		}
+	}
+	// the cri answers a missing image with an empty response
+	return &runtimeapi.ImageStatusResponse{}, nil
+}
+
+func (f *fakeCRIServer) RemoveImage(ctx context.Context, req *runtimeapi.RemoveImageRequest) (*runtimeapi.RemoveImageResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.images, req.Image.Image)
+	return &runtimeapi.RemoveImageResponse{}, nil
+}
+
+func (f *fakeCRIServer) ImageFsInfo(ctx context.Context, req *runtimeapi.ImageFsInfoRequest) (*runtimeapi.ImageFsInfoResponse, error) {
+	return &runtimeapi.ImageFsInfoResponse{ImageFilesystems: []*runtimeapi.FilesystemUsage{
+		{FsId: &runtimeapi.FilesystemIdentifier{Mountpoint: "/var/lib/containerd/io.containerd.snapshotter.v1.overlayfs"}},
+	}}, nil
+}
+
+func startFakeCRIServer(t *testing.T) (*fakeCRIServer, string, func()) {
+	dir, err := ioutil.TempDir("", "cri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sock := path.Join(dir, "containerd.sock")
+	lis, err := net.Listen("unix", sock)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	fake := newFakeCRIServer()
+	server := grpc.NewServer()
+	runtimeapi.RegisterRuntimeServiceServer(server, fake)
+	runtimeapi.RegisterImageServiceServer(server, fake)
+	go server.Serve(lis)
+	return fake, "unix://" + sock, func() {
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCRIRuntimeContainers(t *testing.T) {
+	fake, endpoint, stop := startFakeCRIServer(t)
+	defer stop()
+	fake.setContainer("c1", runtimeapi.ContainerState_CONTAINER_RUNNING,
+		`{"config":{"envs":[{"key":"SERVICE_ID","value":"s1"}]},"runtimeSpec":{"process":{"args":["nginx","-g","daemon off;"],"env":["PATH=/bin","SERVICE_ID=s1"]}}}`)
+	fake.setContainer("c2", runtimeapi.ContainerState_CONTAINER_EXITED, `{"config":{"envs":[{"key":"SERVICE_ID","value":"s2"}]}}`)
+
+	r, err := New(RuntimeContainerd, endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name() != "containerd" {
+		t.Errorf("want runtime containerd, got %s", r.Name())
+	}
+	ctx := context.Background()
+	containers, err := r.ListContainers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 1 || containers[0].ID != "c1" || containers[0].Name != "web" {
+		t.Fatalf("want the running container c1, got %+v", containers)
+	}
+
+	c1, err := r.InspectContainer(ctx, "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1.LogFormat != LogFormatCRI || c1.LogPath != "/var/log/pods/web-0/web/0.log" || !c1.Running {
+		t.Errorf("unexpected container %+v", c1)
+	}
+	if c1.Path != "nginx" || len(c1.Args) != 2 || len(c1.Env) != 2 || c1.Env[1] != "SERVICE_ID=s1" {
+		t.Errorf("the process should be read from the runtime spec, got %s %v %v", c1.Path, c1.Args, c1.Env)
+	}
+	c2, err := r.InspectContainer(ctx, "c2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c2.Env) != 1 || c2.Env[0] != "SERVICE_ID=s2" || c2.Running {
+		t.Errorf("the env should be read from the config without runtime spec, got %+v", c2)
+	}
+	if _, err := r.InspectContainer(ctx, "c3"); err != ErrContainerNotFound {
+		t.Errorf("want ErrContainerNotFound, got %v", err)
+	}
+}
+
+func TestCRIRuntimeImages(t *testing.T) {
+	fake, endpoint, stop := startFakeCRIServer(t)
+	defer stop()
+	fake.images["sha256:a"] = &runtimeapi.Image{Id: "sha256:a", RepoTags: []string{"k8s.gcr.io/pause:3.2"}, Size_: 300}
+	fake.images["sha256:b"] = &runtimeapi.Image{Id: "sha256:b", RepoTags: []string{"nginx:latest"}, Size_: 5000}
+
+	r, err := NewCRIRuntime(endpoint, time.Second*5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	images, err := r.ListImages(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("want 2 images, got %d", len(images))
+	}
+	if ref, err := r.GetImageRef(ctx, "nginx:latest"); err != nil || ref != "sha256:b" {
+		t.Errorf("want image sha256:b, got %s %v", ref, err)
+	}
+	if _, err := r.GetImageRef(ctx, "redis:latest"); err != ErrImageNotFound {
+		t.Errorf("want ErrImageNotFound, got %v", err)
+	}
+	if err := r.RemoveImage(ctx, "sha256:b"); err != nil {
+		t.Fatal(err)
+	}
+	if images, _ := r.ListImages(ctx); len(images) != 1 || images[0].ID != "sha256:a" {
+		t.Errorf("the image sha256:b should be removed, got %+v", images)
+	}
+	if p, err := r.ImageFsPath(ctx); err != nil || p != "/var/lib/containerd/io.containerd.snapshotter.v1.overlayfs" {
+		t.Errorf("unexpected image fs path %s %v", p, err)
+	}
+}
+
+func TestCRIRuntimeWatchContainers(t *testing.T) {
+	fake, endpoint, stop := startFakeCRIServer(t)
+	defer stop()
+	fake.setContainer("c1", runtimeapi.ContainerState_CONTAINER_RUNNING, "")
+
+	r, err := NewCRIRuntime(endpoint, time.Second*5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.watchPeriod = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan ContainerEvent)
+	errch := make(chan error, 1)
+	go func() {
+		errch <- r.WatchContainers(ctx, events)
+	}()
+	// wait for the first listing before changing the containers
+	time.Sleep(50 * time.Millisecond)
+
+	expect := func(action, id string) {
+		select {
+		case event := <-events:
+			if event.Action != action || event.ContainerID != id {
+				t.Fatalf("want %s %s, got %s %s", action, id, event.Action, event.ContainerID)
+			}
+		case err := <-errch:
+			t.Fatalf("watch containers failure %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timeout waiting for %s %s", action, id)
+		}
+	}
+	fake.setContainer("c2", runtimeapi.ContainerState_CONTAINER_RUNNING, "")
+	expect(ContainerActionStart, "c2")
+	fake.setContainer("c1", runtimeapi.ContainerState_CONTAINER_EXITED, "")
+	expect(ContainerActionDie, "c1")
+	fake.removeContainer("c1")
+	expect(ContainerActionDestroy, "c1")
+	// a container exited before the next listing is still started
+	fake.setContainer("c3", runtimeapi.ContainerState_CONTAINER_EXITED, "")
+	expect(ContainerActionStart, "c3")
+
+	cancel()
+	if err := <-errch; err != nil {
+		t.Errorf("the watch should stop without error, got %v", err)
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{endpoint: "unix:///run/containerd/containerd.sock", want: "/run/containerd/containerd.sock"},
+		{endpoint: "/var/run/crio/crio.sock", want: "/var/run/crio/crio.sock"},
+		{endpoint: "tcp://127.0.0.1:2375", wantErr: true},
+		{endpoint: "unix://", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseEndpoint(tc.endpoint)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("%s: want %s %v, got %s %v", tc.endpoint, tc.want, tc.wantErr, got, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package containerruntime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+)
+
+// DockerRuntime is the container runtime of the docker daemon
+type DockerRuntime struct {
+	client *client.Client
+}
+
+// NewDockerRuntime creates the docker runtime with the client configured by the environment
+func NewDockerRuntime() (*DockerRuntime, error) {
+	cli, err := client.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewDockerRuntimeWithClient(cli), nil
+}
+
+// NewDockerRuntimeWithClient creates the docker runtime with the client
+func NewDockerRuntimeWithClient(cli *client.Client) *DockerRuntime {
+	return &DockerRuntime{client: cli}
+}
+
+// Client returns the docker client
+func (d *DockerRuntime) Client() *client.Client {
+	return d.client
+}
+
+// Name returns docker
+func (d *DockerRuntime) Name() string {
+	return RuntimeDocker
+}
+
+// ListContainers lists the running containers
+func (d *DockerRuntime) ListContainers(ctx context.Context) ([]*Container, error) {
+	containers, err := d.client.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var re []*Container
+	for _, c := range containers {
+		container := &Container{
+			ID:      c.ID,
+			Image:   c.Image,
+			Created: time.Unix(c.Created, 0),
+			Labels:  c.Labels,
+			Running: c.State == "running",
+		}
+		if len(c.Names) > 0 {
+			container.Name = c.Names[0]
+		}
+		re = append(re, container)
+	}
+	return re, nil
+}
+
+// InspectContainer returns the container
+func (d *DockerRuntime) InspectContainer(ctx context.Context, containerID string) (*Container, error) {
+	info, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, ErrContainerNotFound
+		}
+		return nil, err
+	}
+	if info.ContainerJSONBase == nil {
+		return nil, fmt.Errorf("container %s has no detail info", containerID)
+	}
+	created, _ := time.Parse(time.RFC3339Nano, info.Created)
+	container := &Container{
+		ID:      info.ID,
+		Name:    info.Name,
+		Created: created,
+		Path:    info.Path,
+		Args:    info.Args,
+		LogPath: info.LogPath,
+	}
+	if info.State != nil {
+		container.Running = info.State.Running
+	}
+	if info.Config != nil {
+		container.Image = info.Config.Image
+		container.Env = info.Config.Env
+		container.Labels = info.Config.Labels
+	}
+	if info.HostConfig != nil {
+		container.LogFormat = info.HostConfig.LogConfig.Type
+	}
+	return container, nil
+}
+
+// WatchContainers sends the events of the containers from the docker daemon
+func (d *DockerRuntime) WatchContainers(ctx context.Context, eventsCh chan<- ContainerEvent) error {
+	containerFilter := filters.NewArgs()
+	containerFilter.Add("type", "container")
+	eventchan, eventerrchan := d.client.Events(ctx, types.EventsOptions{
+		Filters: containerFilter,
+	})
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-eventerrchan:
+			return err
+		case event, ok := <-eventchan:
+			if !ok {
+				return fmt.Errorf("event chan is closed")
+			}
+			if event.Type != events.ContainerEventType {
+				continue
+			}
+			select {
+			case eventsCh <- ContainerEvent{Action: event.Action, ContainerID: event.ID}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// ListImages lists all the images
+func (d *DockerRuntime) ListImages(ctx context.Context) ([]*Image, error) {
+	images, err := d.client.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var re []*Image
+	for _, image := range images {
+		re = append(re, &Image{
+			ID:       image.ID,
+			RepoTags: image.RepoTags,
+			Size:     image.Size,
+		})
+	}
+	return re, nil
+}
+
+// GetImageRef returns the id of the image
+func (d *DockerRuntime) GetImageRef(ctx context.Context, image string) (string, error) {
+	inspect, _, err := d.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return "", ErrImageNotFound
+		}
+		return "", err
+	}
+	return inspect.ID, nil
+}
+
+// RemoveImage removes the image by force
+func (d *DockerRuntime) RemoveImage(ctx context.Context, imageID string) error {
+	items, err := d.client.ImageRemove(ctx, imageID, types.ImageRemoveOptions{
+		Force: true,
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Deleted != "" {
+			logrus.Debugf("image deleted: %s", item.Deleted)
+		}
+		if item.Untagged != "" {
+			logrus.Debugf("image untagged: %s", item.Untagged)
+		}
+	}
+	return nil
+}
+
+// ImageFsPath returns the root dir of docker
+func (d *DockerRuntime) ImageFsPath(ctx context.Context) (string, error) {
+	info, err := d.client.Info(ctx)
+	if err != nil {
+		return "", fmt.Errorf("docker info: %v", err)
+	}
+	return info.DockerRootDir, nil
+}
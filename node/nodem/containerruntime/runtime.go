@@ -0,0 +1,126 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package containerruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// RuntimeDocker is the docker daemon
+	RuntimeDocker = "docker"
+	// RuntimeContainerd is containerd, or any other runtime serving the cri through RuntimeEndpoint
+	RuntimeContainerd = "containerd"
+	// DefaultRuntimeEndpoint is the cri endpoint of containerd
+	DefaultRuntimeEndpoint = "unix:///run/containerd/containerd.sock"
+)
+
+// the formats of the container log files
+const (
+	// LogFormatJSONFile is the json-file log driver of docker
+	LogFormatJSONFile = "json-file"
+	// LogFormatSyslog is the syslog log driver of docker
+	LogFormatSyslog = "syslog"
+	// LogFormatCRI is the log format of the cri runtimes, the lines are '<time> <stream> <tag> <log>'
+	LogFormatCRI = "cri"
+)
+
+// the actions of ContainerEvent
+const (
+	ContainerActionCreate  = "create"
+	ContainerActionStart   = "start"
+	ContainerActionStop    = "stop"
+	ContainerActionDie     = "die"
+	ContainerActionDestroy = "destroy"
+)
+
+// ErrImageNotFound the image does not exist in the runtime
+var ErrImageNotFound = errors.New("image not found")
+
+// ErrContainerNotFound the container does not exist in the runtime
+var ErrContainerNotFound = errors.New("container not found")
+
+// Container is a container of the runtime
+type Container struct {
+	ID      string
+	Name    string
+	Image   string
+	Created time.Time
+	// Path is the entrypoint and Args its arguments
+	Path   string
+	Args   []string
+	Env    []string
+	Labels map[string]string
+	// LogPath is the log file of the container, empty if the runtime does not write one
+	LogPath string
+	// LogFormat is one of the LogFormat constants, or the name of a docker log driver without log file
+	LogFormat string
+	Running   bool
+}
+
+// ContainerEvent is a change of the state of a container
+type ContainerEvent struct {
+	Action      string
+	ContainerID string
+}
+
+// Image is an image of the runtime
+type Image struct {
+	ID       string
+	RepoTags []string
+	Size     int64
+}
+
+// ContainerRuntime is the container runtime of the node
+type ContainerRuntime interface {
+	// Name returns the name of the runtime, such as docker or containerd
+	Name() string
+	// ListContainers lists the running containers
+	ListContainers(ctx context.Context) ([]*Container, error)
+	// InspectContainer returns the container, ErrContainerNotFound if it does not exist
+	InspectContainer(ctx context.Context, containerID string) (*Container, error)
+	// WatchContainers sends the events of the containers until the context is done or an error occurs
+	WatchContainers(ctx context.Context, events chan<- ContainerEvent) error
+	// ListImages lists all the images
+	ListImages(ctx context.Context) ([]*Image, error)
+	// GetImageRef returns the id of the image, ErrImageNotFound if it does not exist
+	GetImageRef(ctx context.Context, image string) (string, error)
+	// RemoveImage removes the image by id
+	RemoveImage(ctx context.Context, imageID string) error
+	// ImageFsPath returns the path of the filesystem storing the images
+	ImageFsPath(ctx context.Context) (string, error)
+}
+
+// New creates the container runtime of the type, the endpoint is only used by the cri runtimes
+func New(runtimeType, endpoint string) (ContainerRuntime, error) {
+	switch runtimeType {
+	case "", RuntimeDocker:
+		return NewDockerRuntime()
+	case RuntimeContainerd:
+		if endpoint == "" {
+			endpoint = DefaultRuntimeEndpoint
+		}
+		return NewCRIRuntime(endpoint, defaultConnectionTimeout)
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %s", runtimeType)
+	}
+}
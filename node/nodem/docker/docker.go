@@ -15,8 +15,22 @@ import (
 var defaultFileName = "server.crt"
 var defaultFilePath = "/etc/docker/certs.d/gridworkz"
 
+// the ca of the registry for containerd, which loads it if the registry config path of the cri plugin
+// is /etc/containerd/certs.d, such as config_path = "/etc/containerd/certs.d" in /etc/containerd/config.toml
+var containerdFileName = "ca.crt"
+var containerdFilePath = "/etc/containerd/certs.d/gridworkz"
+
 // SyncDockerCertFromSecret sync docker cert from secret
 func SyncDockerCertFromSecret(clientset kubernetes.Interface, namespace, secretName string) error {
+	return syncCertFromSecret(clientset, namespace, secretName, defaultFilePath, defaultFileName)
+}
+
+// SyncContainerdCertFromSecret sync the registry cert from secret into the certs dir of containerd
+func SyncContainerdCertFromSecret(clientset kubernetes.Interface, namespace, secretName string) error {
+	return syncCertFromSecret(clientset, namespace, secretName, containerdFilePath, containerdFileName)
+}
+
+func syncCertFromSecret(clientset kubernetes.Interface, namespace, secretName, filePath, fileName string) error {
 	namespace = strings.TrimSpace(namespace)
 	secretName = strings.TrimSpace(secretName)
 	if namespace == "" || secretName == "" {
@@ -27,7 +41,7 @@ func SyncDockerCertFromSecret(clientset kubernetes.Interface, namespace, secretN
 		return err
 	}
 	if certInfo, ok := secretInfo.Data["cert"]; ok { // TODO gridworkz key name
-		if err := saveORUpdateFile(certInfo); err != nil {
+		if err := saveORUpdateFile(filePath, fileName, certInfo); err != nil {
 			return err
 		}
 
@@ -37,20 +51,21 @@ func SyncDockerCertFromSecret(clientset kubernetes.Interface, namespace, secretN
 	return nil
 }
 
-// sync as file saved int /etc/docker/gridworkz/server.crt
-func saveORUpdateFile(content []byte) error {
+// sync as file saved int /etc/docker/certs.d/gridworkz/server.crt or /etc/containerd/certs.d/gridworkz/ca.crt
+func saveORUpdateFile(filePath, fileName string, content []byte) error {
 	// If path is already a directory, MkdirAll does nothing and returns nil
-	if err := os.MkdirAll(defaultFilePath, 0666); err != nil {
-		logrus.Errorf("mkdir path(%s) error: %s", defaultFilePath, err.Error())
+	if err := os.MkdirAll(filePath, 0666); err != nil {
+		logrus.Errorf("mkdir path(%s) error: %s", filePath, err.Error())
 		return err
 	}
-	logrus.Debugf("mkdir path(%s) successfully", defaultFilePath)
-	dest := path.Join(defaultFilePath, defaultFileName)
+	logrus.Debugf("mkdir path(%s) successfully", filePath)
+	dest := path.Join(filePath, fileName)
 	// Create creates the named file with mode 0666 (before umask), truncating it if it already exists
 	file, err := os.Create(dest)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	_, err = io.Copy(file, strings.NewReader(string(content)))
 	return err
@@ -0,0 +1,174 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package conver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/ptypes"
+	api_model "github.com/gridworkz/kato/api/model"
+	envoyv3 "github.com/gridworkz/kato/node/core/envoy/v3"
+	"github.com/gridworkz/kato/node/utils"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+//OneNodeClusterV3 conver xds v3 cluster of on envoy node
+//the upstream clusters originate mutual tls to the depended components enabling it in mtls
+func OneNodeClusterV3(serviceAlias, namespace string, configs *corev1.ConfigMap, services []*corev1.Service, mtls *MeshTLS) ([]types.Resource, error) {
+	resources, _, err := GetPluginConfigs(configs)
+	if err != nil {
+		return nil, err
+	}
+	var clusters []types.Resource
+	if resources.BaseServices != nil && len(resources.BaseServices) > 0 {
+		for _, cl := range upstreamClustersV3(serviceAlias, namespace, resources.BaseServices, services, mtls) {
+			if err := cl.Validate(); err != nil {
+				logrus.Errorf("cluster validate failure %s", err.Error())
+			} else {
+				clusters = append(clusters, cl)
+			}
+		}
+	}
+	if resources.BasePorts != nil && len(resources.BasePorts) > 0 {
+		for _, cl := range downstreamClustersV3(serviceAlias, namespace, resources.BasePorts) {
+			if err := cl.Validate(); err != nil {
+				logrus.Errorf("cluster validate failure %s", err.Error())
+			} else {
+				clusters = append(clusters, cl)
+			}
+		}
+	}
+	if len(clusters) == 0 {
+		logrus.Warningf("configmap name: %s; plugin-config: %s; create clusters zero length", configs.Name, configs.Data["plugin-config"])
+	}
+	return clusters, nil
+}
+
+// upstreamClustersV3 handle upstream app cluster with xds v3 api
+// handle kubernetes inner service
+func upstreamClustersV3(serviceAlias, namespace string, dependsServices []*api_model.BaseService, services []*corev1.Service, mtls *MeshTLS) (cdsClusters []*cluster.Cluster) {
+	var clusterConfig = make(map[string]*api_model.BaseService, len(dependsServices))
+	for i, dService := range dependsServices {
+		depServiceIndex := fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, dService.DependServiceAlias, dService.Port)
+		clusterConfig[depServiceIndex] = dependsServices[i]
+	}
+	for _, service := range services {
+		inner, ok := service.Labels["service_type"]
+		destServiceAlias := GetServiceAliasByService(service)
+		port := service.Spec.Ports[0]
+		if !ok || inner != "inner" {
+			continue
+		}
+		getOptions := func() (d envoyv3.KatoPluginOptions) {
+			relPort, _ := strconv.Atoi(service.Labels["origin_port"])
+			if relPort == 0 {
+				relPort = int(port.TargetPort.IntVal)
+			}
+			depServiceIndex := fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, GetServiceAliasByService(service), relPort)
+			if _, ok := clusterConfig[depServiceIndex]; ok {
+				return envoyv3.GetOptionValues(clusterConfig[depServiceIndex].Options)
+			}
+			return envoyv3.GetOptionValues(nil)
+		}
+		var clusterOption envoyv3.ClusterOptions
+		clusterOption.Name = fmt.Sprintf("%s_%s_%s_%v", namespace, serviceAlias, GetServiceAliasByService(service), port.Port)
+		options := getOptions()
+		clusterOption.OutlierDetection = envoyv3.CreatOutlierDetection(options)
+		clusterOption.CircuitBreakers = envoyv3.CreateCircuitBreaker(options)
+		clusterOption.ServiceName = fmt.Sprintf("%s_%s_%s_%v", namespace, serviceAlias, destServiceAlias, port.Port)
+		if domain, ok := service.Annotations["domain"]; ok && domain != "" {
+			logrus.Debugf("domain endpoint[%s], create logical_dns cluster: ", domain)
+			clusterOption.ClusterType = cluster.Cluster_LOGICAL_DNS
+			clusterOption.LoadAssignment = envoyv3.CreateDNSLoadAssignment(serviceAlias, namespace, domain, service)
+			if strings.HasPrefix(domain, "https://") {
+				splitDomain := strings.Split(domain, "https://")
+				if len(splitDomain) == 2 {
+					clusterOption.TransportSocket = transportSocketV3(clusterOption.Name, splitDomain[1])
+				}
+			}
+		} else {
+			clusterOption.ClusterType = cluster.Cluster_EDS
+			clusterOption.TransportSocket = mtls.upstreamTransportSocket(service)
+		}
+		clusterOption.HealthyPanicThreshold = options.HealthyPanicThreshold
+		clusterOption.ConnectionTimeout = envoyv3.ConverTimeDuration(options.ConnectionTimeout)
+		// set port realy protocol
+		portProtocol := service.Labels["port_protocol"]
+		clusterOption.Protocol = portProtocol
+		clusterOption.GrpcHealthServiceName = options.GrpcHealthServiceName
+		clusterOption.HealthTimeout = options.HealthCheckTimeout
+		clusterOption.HealthInterval = options.HealthCheckInterval
+		c := envoyv3.CreateCluster(clusterOption)
+		if c != nil {
+			logrus.Debugf("cluster is : %v", c)
+			cdsClusters = append(cdsClusters, c)
+		}
+	}
+	return
+}
+
+func transportSocketV3(name, domain string) *core.TransportSocket {
+	logrus.Debugf("https domain tlsContext: %s", domain)
+	// refer to: https://www.envoyproxy.io/docs/envoy/v1.17.2/api-v3/extensions/transport_sockets/tls/v3/tls.proto#extensions-transport-sockets-tls-v3-upstreamtlscontext
+	tlsContext, err := ptypes.MarshalAny(&tls.UpstreamTlsContext{Sni: domain})
+	if err != nil {
+		logrus.Errorf("error marshaling tls context to transport_socket config for cluster %s, err=%v",
+			name, err)
+		// no tls context for the cluster
+		return nil
+	}
+	return &core.TransportSocket{
+		Name: utils.EnvoyTLSSocketName,
+		ConfigType: &core.TransportSocket_TypedConfig{
+			TypedConfig: tlsContext,
+		},
+	}
+}
+
+//downstreamClustersV3 handle app self cluster with xds v3 api
+//only local port
+func downstreamClustersV3(serviceAlias, namespace string, ports []*api_model.BasePort) (cdsClusters []*cluster.Cluster) {
+	for i := range ports {
+		port := ports[i]
+		address := envoyv3.CreateSocketAddress(port.Protocol, "127.0.0.1", uint32(port.Port))
+		clusterName := fmt.Sprintf("%s_%s_%v", namespace, serviceAlias, port.Port)
+		option := envoyv3.GetOptionValues(port.Options)
+		c := envoyv3.CreateCluster(envoyv3.ClusterOptions{
+			Name:                     clusterName,
+			ConnectionTimeout:        envoyv3.ConverTimeDuration(option.ConnectionTimeout),
+			ServiceName:              "",
+			ClusterType:              cluster.Cluster_STATIC,
+			CircuitBreakers:          envoyv3.CreateCircuitBreaker(option),
+			OutlierDetection:         envoyv3.CreatOutlierDetection(option),
+			MaxRequestsPerConnection: option.MaxRequestsPerConnection,
+			Hosts:                    []*core.Address{address},
+			HealthyPanicThreshold:    option.HealthyPanicThreshold,
+		})
+		if c != nil {
+			cdsClusters = append(cdsClusters, c)
+		}
+	}
+	return
+}
@@ -0,0 +1,332 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package conver
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/golang/protobuf/jsonpb"
+	api_model "github.com/gridworkz/kato/api/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+type converCase struct {
+	name      string
+	config    *corev1.ConfigMap
+	services  []*corev1.Service
+	endpoints []*corev1.Endpoints
+	mtls      *MeshTLS
+}
+
+func newPluginConfig(t *testing.T, rs *api_model.ResourceSpec) *corev1.ConfigMap {
+	body, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-app-plugin",
+			Namespace: "tenant",
+			Labels:    map[string]string{"plugin_id": "plugin", "service_alias": "grapp"},
+		},
+		Data: map[string]string{
+			"plugin-config": string(body),
+			"plugin-model":  "net-plugin:in-and-out",
+		},
+	}
+}
+
+func newService(name, alias, serviceType, portProtocol string, port int32, annotations map[string]string) *corev1.Service {
+	protocol := corev1.ProtocolTCP
+	if portProtocol == "udp" {
+		protocol = corev1.ProtocolUDP
+	}
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "tenant",
+			Labels: map[string]string{
+				"service_alias": alias,
+				"service_type":  serviceType,
+				"port_protocol": portProtocol,
+			},
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: port, Protocol: protocol, TargetPort: intstr.FromInt(int(port))},
+			},
+		},
+	}
+}
+
+func withLabels(service *corev1.Service, labels map[string]string) *corev1.Service {
+	for k, v := range labels {
+		service.Labels[k] = v
+	}
+	return service
+}
+
+func newEndpoints(name string, port int32, ready []string, notReady []string) *corev1.Endpoints {
+	subset := corev1.EndpointSubset{Ports: []corev1.EndpointPort{{Port: port, Protocol: corev1.ProtocolTCP}}}
+	for _, ip := range ready {
+		subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+	}
+	for _, ip := range notReady {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: ip})
+	}
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant"},
+		Subsets:    []corev1.EndpointSubset{subset},
+	}
+}
+
+func converCases(t *testing.T) []converCase {
+	return []converCase{
+		{
+			name: "outbound",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BaseServices: []*api_model.BaseService{
+					{ServiceAlias: "grapp", DependServiceAlias: "grweb", DependServiceID: "web", Port: 5000, Protocol: "http",
+						Options: map[string]interface{}{"Domains": "web.example.com", "Prefix": "/api", "Weight": "80"}},
+					{ServiceAlias: "grapp", DependServiceAlias: "grmysql", DependServiceID: "mysql", Port: 3306, Protocol: "tcp",
+						Options: map[string]interface{}{"TCPIdleTimeout": "600", "MaxConnections": "100"}},
+					{ServiceAlias: "grapp", DependServiceAlias: "grdns", DependServiceID: "dns", Port: 53, Protocol: "udp"},
+					{ServiceAlias: "grapp", DependServiceAlias: "grrpc", DependServiceID: "rpc", Port: 9000, Protocol: "grpc",
+						Options: map[string]interface{}{"GrpcHealthServiceName": "rpc.Health"}},
+				},
+			}),
+			services: []*corev1.Service{
+				newService("service-web-5000", "grweb", "inner", "http", 5000, nil),
+				newService("service-mysql-3306", "grmysql", "inner", "tcp", 3306, nil),
+				newService("service-dns-53", "grdns", "inner", "udp", 53, nil),
+				newService("service-rpc-9000", "grrpc", "inner", "grpc", 9000, nil),
+			},
+			endpoints: []*corev1.Endpoints{
+				newEndpoints("service-web-5000", 5000, []string{"10.0.0.1", "10.0.0.2"}, nil),
+				newEndpoints("service-mysql-3306", 3306, nil, []string{"10.0.0.3"}),
+				newEndpoints("service-dns-53", 53, []string{"10.0.0.4"}, nil),
+				newEndpoints("service-rpc-9000", 9000, []string{"10.0.0.5"}, nil),
+			},
+		},
+		{
+			name: "inbound",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BasePorts: []*api_model.BasePort{
+					{ServiceAlias: "grapp", Port: 5000, ListenPort: 65301, Protocol: "http",
+						Options: map[string]interface{}{"OPEN_LIMIT": "yes", "LIMIT_DOMAIN": "limit.common"}},
+					{ServiceAlias: "grapp", Port: 3306, ListenPort: 65302, Protocol: "tcp"},
+				},
+			}),
+			services: []*corev1.Service{
+				newService("service-app-5000out", "grapp", "outer", "http", 5000, nil),
+			},
+			endpoints: []*corev1.Endpoints{
+				newEndpoints("service-app-5000out", 5000, []string{"10.0.0.6"}, nil),
+			},
+		},
+		{
+			name: "domain",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BaseServices: []*api_model.BaseService{
+					{ServiceAlias: "grapp", DependServiceAlias: "grthird", DependServiceID: "third", Port: 443, Protocol: "https"},
+				},
+			}),
+			services: []*corev1.Service{
+				newService("service-third-443", "grthird", "inner", "https", 443, map[string]string{"domain": "https://api.example.com"}),
+			},
+		},
+		{
+			name: "resilience",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BaseServices: []*api_model.BaseService{
+					{ServiceAlias: "grapp", DependServiceAlias: "grweb", DependServiceID: "web", Port: 5000, Protocol: "http",
+						Options: map[string]interface{}{"Domains": "web.example.com", "RetryAttempts": "3", "RetryOn": "5xx, reset",
+							"PerTryTimeoutMS": "500", "RequestTimeoutMS": "2000"}},
+					{ServiceAlias: "grapp", DependServiceAlias: "grapi", DependServiceID: "api", Port: 8080, Protocol: "http",
+						Options: map[string]interface{}{"Domains": "api.example.com", "RequestTimeoutMS": "0", "FaultDelayMS": "1500",
+							"FaultDelayPercent": "12.5", "FaultAbortStatus": "503", "FaultAbortPercent": "1"}},
+				},
+			}),
+			services: []*corev1.Service{
+				newService("service-web-5000", "grweb", "inner", "http", 5000, nil),
+				newService("service-api-8080", "grapi", "inner", "http", 8080, nil),
+			},
+		},
+		{
+			name: "mtls-permissive",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BaseServices: []*api_model.BaseService{
+					{ServiceAlias: "grapp", DependServiceAlias: "grweb", DependServiceID: "web", Port: 5000, Protocol: "http"},
+					{ServiceAlias: "grapp", DependServiceAlias: "grmysql", DependServiceID: "mysql", Port: 3306, Protocol: "tcp"},
+				},
+				BasePorts: []*api_model.BasePort{
+					{ServiceAlias: "grapp", Port: 8080, ListenPort: 65301, Protocol: "http"},
+					{ServiceAlias: "grapp", Port: 9000, ListenPort: 65302, Protocol: "tcp"},
+				},
+			}),
+			services: []*corev1.Service{
+				// the inbound plugin of grweb takes over its port
+				withLabels(newService("service-web-65301", "grweb", "inner", "http", 65301, nil),
+					map[string]string{"origin_port": "5000", "service_id": "web", "tenant_name": "demo", "app_id": "app1"}),
+				withLabels(newService("service-mysql-3306", "grmysql", "inner", "tcp", 3306, nil),
+					map[string]string{"service_id": "mysql", "tenant_name": "demo", "app_id": "app1"}),
+			},
+			endpoints: []*corev1.Endpoints{
+				newEndpoints("service-web-65301", 65301, []string{"10.0.0.1"}, nil),
+				newEndpoints("service-mysql-3306", 3306, []string{"10.0.0.3"}, nil),
+			},
+			mtls: &MeshTLS{
+				MeshTLSConfig: api_model.MeshTLSConfig{
+					Mode:           api_model.MeshTLSModePermissive,
+					AllowedSources: []string{"demo/app1", "*/*/grgateway"},
+				},
+				Upstreams: map[string]string{"web": api_model.MeshTLSModeStrict, "mysql": api_model.MeshTLSModeDisabled},
+			},
+		},
+		{
+			name: "mtls-strict",
+			config: newPluginConfig(t, &api_model.ResourceSpec{
+				BasePorts: []*api_model.BasePort{
+					{ServiceAlias: "grapp", Port: 9000, ListenPort: 65302, Protocol: "tcp"},
+					{ServiceAlias: "grapp", Port: 53, ListenPort: 65303, Protocol: "udp"},
+				},
+			}),
+			mtls: &MeshTLS{MeshTLSConfig: api_model.MeshTLSConfig{Mode: api_model.MeshTLSModeStrict}},
+		},
+	}
+}
+
+func converResources(version, serviceAlias, namespace string, c converCase) (listeners, clusters, endpoints []types.Resource, err error) {
+	switch version {
+	case XDSVersionV2:
+		if listeners, err = OneNodeListerner(serviceAlias, namespace, c.config, c.services); err != nil {
+			return
+		}
+		if clusters, err = OneNodeCluster(serviceAlias, namespace, c.config, c.services); err != nil {
+			return
+		}
+		endpoints = OneNodeClusterLoadAssignment(serviceAlias, namespace, c.endpoints, c.services)
+	case XDSVersionV3:
+		if listeners, err = OneNodeListernerV3(serviceAlias, namespace, c.config, c.services, c.mtls); err != nil {
+			return
+		}
+		if clusters, err = OneNodeClusterV3(serviceAlias, namespace, c.config, c.services, c.mtls); err != nil {
+			return
+		}
+		endpoints = OneNodeClusterLoadAssignmentV3(serviceAlias, namespace, c.endpoints, c.services)
+	default:
+		err = fmt.Errorf("unknown xds version %s", version)
+	}
+	return
+}
+
+func marshalResources(t *testing.T, resources []types.Resource) []json.RawMessage {
+	var marshaler jsonpb.Marshaler
+	out := []json.RawMessage{}
+	for _, resource := range resources {
+		body, err := marshaler.MarshalToString(resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, json.RawMessage(body))
+	}
+	return out
+}
+
+func TestConverGolden(t *testing.T) {
+	for _, c := range converCases(t) {
+		for _, version := range []string{XDSVersionV2, XDSVersionV3} {
+			listeners, clusters, endpoints, err := converResources(version, "grapp", "tenant", c)
+			if err != nil {
+				t.Fatalf("%s %s: %v", c.name, version, err)
+			}
+			got, err := json.MarshalIndent(map[string][]json.RawMessage{
+				"listeners": marshalResources(t, listeners),
+				"clusters":  marshalResources(t, clusters),
+				"endpoints": marshalResources(t, endpoints),
+			}, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			golden := filepath.Join("testdata", fmt.Sprintf("%s.%s.golden.json", c.name, version))
+			if *update {
+				if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s does not match the converted resources, run the test with -update if the change is intended\n%s", golden, got)
+			}
+		}
+	}
+}
+
+func TestServeXDSVersion(t *testing.T) {
+	tests := []struct {
+		pinned interface{}
+		v2, v3 bool
+	}{
+		{pinned: nil, v2: true, v3: true},
+		{pinned: "v2", v2: true, v3: false},
+		{pinned: " V3 ", v2: false, v3: true},
+		{pinned: "v4", v2: true, v3: true},
+	}
+	for _, tc := range tests {
+		rs := &api_model.ResourceSpec{BaseNormal: api_model.BaseEnv{Options: map[string]interface{}{}}}
+		if tc.pinned != nil {
+			rs.BaseNormal.Options[KeyXDSVersion] = tc.pinned
+		}
+		if got := ServeXDSVersion(rs, XDSVersionV2); got != tc.v2 {
+			t.Errorf("pinned %v: want v2 %v, got %v", tc.pinned, tc.v2, got)
+		}
+		if got := ServeXDSVersion(rs, XDSVersionV3); got != tc.v3 {
+			t.Errorf("pinned %v: want v3 %v, got %v", tc.pinned, tc.v3, got)
+		}
+	}
+}
+
+func TestServeNodeXDSVersion(t *testing.T) {
+	rs := &api_model.ResourceSpec{BaseNormal: api_model.BaseEnv{Options: map[string]interface{}{KeyXDSVersion: "v2"}}}
+	if !ServeNodeXDSVersion(rs, nil, XDSVersionV2) || ServeNodeXDSVersion(rs, nil, XDSVersionV3) {
+		t.Errorf("a node without mutual tls should follow the pinned version")
+	}
+	upstream := &MeshTLS{
+		MeshTLSConfig: api_model.MeshTLSConfig{Mode: api_model.MeshTLSModeDisabled},
+		Upstreams:     map[string]string{"dep": api_model.MeshTLSModeStrict},
+	}
+	if ServeNodeXDSVersion(rs, upstream, XDSVersionV2) || !ServeNodeXDSVersion(rs, upstream, XDSVersionV3) {
+		t.Errorf("a node with mutual tls should be served on v3 only")
+	}
+}
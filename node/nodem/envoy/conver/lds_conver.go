@@ -141,6 +141,7 @@ func upstreamListener(serviceAlias, namespace string, dependsServices []*api_mod
 			if domain, ok := service.Annotations["domain"]; ok && domain != "" && (protocol == "https" || protocol == "http") {
 				route := envoyv2.CreateRouteWithHostRewrite(domain, clusterName, "/", nil, 0)
 				if route != nil {
+					envoyv2.SetRoutePolicy(route, options)
 					pvh := envoyv2.CreateRouteVirtualHost(
 						fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, GetServiceAliasByService(service), port),
 						[]string{"*"},
@@ -178,6 +179,7 @@ func upstreamListener(serviceAlias, namespace string, dependsServices []*api_mod
 			case "http", "https":
 				hashKey := options.RouteBasicHash()
 				if oldroute, ok := uniqRoute[hashKey]; ok {
+					// the weighted route keeps the retry, timeout and fault policy of its first dependency
 					oldrr := oldroute.Action.(*route.Route_Route)
 					if oldrrwc, ok := oldrr.Route.ClusterSpecifier.(*route.RouteAction_WeightedClusters); ok {
 						weight := envoyv2.CheckWeightSum(oldrrwc.WeightedClusters.Clusters, options.Weight)
@@ -202,6 +204,7 @@ func upstreamListener(serviceAlias, namespace string, dependsServices []*api_mod
 					}
 
 					if route != nil {
+						envoyv2.SetRoutePolicy(route, options)
 						pvh := envoyv2.CreateRouteVirtualHost(fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias,
 							GetServiceAliasByService(service), port), options.Domains, nil, route)
 						if pvh != nil {
@@ -0,0 +1,298 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package conver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	api_model "github.com/gridworkz/kato/api/model"
+	envoyv3 "github.com/gridworkz/kato/node/core/envoy/v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+//OneNodeListernerV3 conver xds v3 listerner of on envoy node
+//the inbound listeners terminate mutual tls if it is enabled by mtls
+func OneNodeListernerV3(serviceAlias, namespace string, configs *corev1.ConfigMap, services []*corev1.Service, mtls *MeshTLS) ([]types.Resource, error) {
+	resources, _, err := GetPluginConfigs(configs)
+	if err != nil {
+		return nil, err
+	}
+	var listener []types.Resource
+	var notCreateCommonHTTPListener = func() bool {
+		if configs.Annotations["disable_create_http_common_listener"] == "true" {
+			return true
+		}
+		if strings.Contains(configs.Name, "def-mesh") {
+			return true
+		}
+		return false
+	}()
+	if resources.BaseServices != nil && len(resources.BaseServices) > 0 {
+		for _, l := range upstreamListenerV3(serviceAlias, namespace, resources.BaseServices, services, !notCreateCommonHTTPListener) {
+			if err := l.Validate(); err != nil {
+				logrus.Errorf("listener validate failure %s", err.Error())
+			} else {
+				logrus.Debugf("create listener %s for service %s", l.Name, serviceAlias)
+				listener = append(listener, l)
+			}
+		}
+	}
+	if resources.BasePorts != nil && len(resources.BasePorts) > 0 {
+		for _, l := range downstreamListenerV3(serviceAlias, namespace, resources.BasePorts, mtls) {
+			if err := l.Validate(); err != nil {
+				logrus.Errorf("listener validate failure %s", err.Error())
+			} else {
+				logrus.Debugf("create listener %s for service %s", l.Name, serviceAlias)
+				listener = append(listener, l)
+			}
+		}
+	}
+	if len(listener) == 0 {
+		logrus.Warningf("configmap name: %s; plugin-config: %s; create listener zero length", configs.Name, configs.Data["plugin-config"])
+	}
+	return listener, nil
+}
+
+//upstreamListenerV3 handle upstream app listener with xds v3 api
+// handle kubernetes inner service
+func upstreamListenerV3(serviceAlias, namespace string, dependsServices []*api_model.BaseService, services []*corev1.Service, createHTTPListen bool) (ldsL []*listenerv3.Listener) {
+	var ListennerConfig = make(map[string]*api_model.BaseService, len(dependsServices))
+	for i, dService := range dependsServices {
+		protoccol := "tcp"
+		if strings.ToLower(dService.Protocol) == "udp" {
+			protoccol = "udp"
+		}
+		if strings.ToLower(dService.Protocol) == "sctp" {
+			protoccol = "sctp"
+		}
+		listennerName := fmt.Sprintf("%s_%s_%s_%s_%d", namespace, serviceAlias, dService.DependServiceAlias, protoccol, dService.Port)
+		ListennerConfig[listennerName] = dependsServices[i]
+	}
+	var portMap = make(map[int32]int)
+	var uniqRoute = make(map[string]*route.Route, len(services))
+	var newVHL []*route.VirtualHost
+	for _, service := range services {
+		inner, ok := service.Labels["service_type"]
+		if !ok || inner != "inner" {
+			continue
+		}
+		port := service.Spec.Ports[0].Port
+		protocol := service.Spec.Ports[0].Protocol
+		var ListenPort = port
+		//listener real port
+		if value, ok := service.Labels["origin_port"]; ok {
+			origin, _ := strconv.Atoi(value)
+			if origin != 0 {
+				ListenPort = int32(origin)
+			}
+		}
+		clusterName := fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, GetServiceAliasByService(service), port)
+		listennerName := fmt.Sprintf("%s_%s_%s_%s_%d", namespace, serviceAlias, GetServiceAliasByService(service), strings.ToLower(string(protocol)), ListenPort)
+		destService := ListennerConfig[listennerName]
+		statPrefix := fmt.Sprintf("%s_%s", serviceAlias, GetServiceAliasByService(service))
+		var options envoyv3.KatoPluginOptions
+		if destService != nil {
+			options = envoyv3.GetOptionValues(destService.Options)
+		} else {
+			logrus.Warningf("destService is nil for service %s listenner name %s", serviceAlias, listennerName)
+		}
+		// Unique by listen port
+		if _, ok := portMap[ListenPort]; !ok {
+			//listener name depend listner port
+			listenerName := fmt.Sprintf("%s_%s_%d", namespace, serviceAlias, ListenPort)
+			var listener *listenerv3.Listener
+			protocol := service.Labels["port_protocol"]
+			if domain, ok := service.Annotations["domain"]; ok && domain != "" && (protocol == "https" || protocol == "http") {
+				route := envoyv3.CreateRouteWithHostRewrite(domain, clusterName, "/", nil, 0)
+				if route != nil {
+					envoyv3.SetRoutePolicy(route, options)
+					pvh := envoyv3.CreateRouteVirtualHost(
+						fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias, GetServiceAliasByService(service), port),
+						[]string{"*"},
+						nil,
+						route,
+					)
+					if pvh != nil {
+						listener = envoyv3.CreateHTTPListener(fmt.Sprintf("%s_%s_http_%d", namespace, serviceAlias, port), envoyv3.DefaultLocalhostListenerAddress, fmt.Sprintf("%s_%d", serviceAlias, port), uint32(port), nil, pvh)
+					} else {
+						logrus.Warnf("create route virtual host of domain listener %s failure", fmt.Sprintf("%s_%s_http_%d", namespace, serviceAlias, port))
+					}
+				}
+			} else if protocol == "udp" {
+				listener = envoyv3.CreateUDPListener(listenerName, clusterName, envoyv3.DefaultLocalhostListenerAddress, statPrefix, uint32(ListenPort))
+			} else {
+				listener = envoyv3.CreateTCPListener(listenerName, clusterName, envoyv3.DefaultLocalhostListenerAddress, statPrefix, uint32(ListenPort), options.TCPIdleTimeout)
+			}
+			if listener != nil {
+				ldsL = append(ldsL, listener)
+			} else {
+				logrus.Warningf("create tcp listenner %s failure", listenerName)
+				continue
+			}
+			portMap[ListenPort] = len(ldsL) - 1
+		}
+
+		portProtocol, _ := service.Labels["port_protocol"]
+		if destService != nil && destService.Protocol != "" {
+			portProtocol = destService.Protocol
+		}
+
+		if portProtocol != "" {
+			//TODO: support more protocol
+			switch portProtocol {
+			case "http", "https":
+				hashKey := options.RouteBasicHash()
+				if oldroute, ok := uniqRoute[hashKey]; ok {
+					// the weighted route keeps the retry, timeout and fault policy of its first dependency
+					oldrr := oldroute.Action.(*route.Route_Route)
+					if oldrrwc, ok := oldrr.Route.ClusterSpecifier.(*route.RouteAction_WeightedClusters); ok {
+						weight := envoyv3.CheckWeightSum(oldrrwc.WeightedClusters.Clusters, options.Weight)
+						oldrrwc.WeightedClusters.Clusters = append(oldrrwc.WeightedClusters.Clusters, &route.WeightedCluster_ClusterWeight{
+							Name:   clusterName,
+							Weight: envoyv3.ConversionUInt32(weight),
+						})
+					}
+				} else {
+					var headerMatchers []*route.HeaderMatcher
+					for _, header := range options.Headers {
+						headerMatcher := envoyv3.CreateHeaderMatcher(header)
+						if headerMatcher != nil {
+							headerMatchers = append(headerMatchers, headerMatcher)
+						}
+					}
+					var route *route.Route
+					if domain, ok := service.Annotations["domain"]; ok && domain != "" {
+						route = envoyv3.CreateRouteWithHostRewrite(domain, clusterName, options.Prefix, headerMatchers, options.Weight)
+					} else {
+						route = envoyv3.CreateRoute(clusterName, options.Prefix, headerMatchers, options.Weight)
+					}
+
+					if route != nil {
+						envoyv3.SetRoutePolicy(route, options)
+						pvh := envoyv3.CreateRouteVirtualHost(fmt.Sprintf("%s_%s_%s_%d", namespace, serviceAlias,
+							GetServiceAliasByService(service), port), options.Domains, nil, route)
+						if pvh != nil {
+							newVHL = append(newVHL, pvh)
+							uniqRoute[hashKey] = route
+						}
+					}
+				}
+			default:
+				continue
+			}
+		}
+	}
+	logrus.Debugf("virtual host is : %v", newVHL)
+	// create common http listener
+	if len(newVHL) > 0 && createHTTPListen {
+		//remove 80 tcp listener is exist
+		if i, ok := portMap[80]; ok {
+			ldsL = append(ldsL[:i], ldsL[i+1:]...)
+		}
+		statsPrefix := fmt.Sprintf("%s_80", serviceAlias)
+		plds := envoyv3.CreateHTTPListener(fmt.Sprintf("%s_%s_http_80", namespace, serviceAlias), envoyv3.DefaultLocalhostListenerAddress, statsPrefix, 80, nil, newVHL...)
+		if plds != nil {
+			ldsL = append(ldsL, plds)
+		} else {
+			logrus.Warnf("create listenner %s failure", fmt.Sprintf("%s_%s_http_80", namespace, serviceAlias))
+		}
+	}
+	return
+}
+
+//downstreamListenerV3 handle app self port listener with xds v3 api
+func downstreamListenerV3(serviceAlias, namespace string, ports []*api_model.BasePort, mtls *MeshTLS) (ls []*listenerv3.Listener) {
+	var portMap = make(map[int32]int, 0)
+	for i := range ports {
+		p := ports[i]
+		port := int32(p.Port)
+		clusterName := fmt.Sprintf("%s_%s_%d", namespace, serviceAlias, port)
+		listenerName := clusterName
+		statsPrefix := fmt.Sprintf("%s_%d", serviceAlias, port)
+		if _, ok := portMap[port]; !ok {
+			inboundConfig := envoyv3.GetKatoInboundPluginOptions(p.Options)
+			options := envoyv3.GetOptionValues(p.Options)
+			if p.Protocol == "http" || p.Protocol == "https" {
+				var limit []*route.RateLimit
+				if inboundConfig.OpenLimit {
+					limit = []*route.RateLimit{
+						&route.RateLimit{
+							Actions: []*route.RateLimit_Action{
+								&route.RateLimit_Action{
+									ActionSpecifier: &route.RateLimit_Action_RemoteAddress_{
+										RemoteAddress: &route.RateLimit_Action_RemoteAddress{},
+									},
+								},
+							},
+						},
+					}
+				}
+				route := envoyv3.CreateRoute(clusterName, "/", nil, 100)
+				if route == nil {
+					logrus.Warning("create route cirtual route failure")
+					continue
+				}
+				virtuals := envoyv3.CreateRouteVirtualHost(listenerName, []string{"*"}, limit, route)
+				if virtuals == nil {
+					logrus.Warning("create route cirtual failure")
+					continue
+				}
+				listener := envoyv3.CreateHTTPListener(listenerName, "0.0.0.0", statsPrefix, uint32(p.ListenPort), &envoyv3.RateLimitOptions{
+					Enable:                inboundConfig.OpenLimit,
+					Domain:                inboundConfig.LimitDomain,
+					RateServerClusterName: envoyv3.DefaultRateLimitServerClusterName,
+					Stage:                 0,
+				}, virtuals)
+				if tlsOptions := mtls.inboundOptions(statsPrefix); listener != nil && tlsOptions != nil {
+					listener = envoyv3.SecureListener(listener, *tlsOptions)
+				}
+				if listener != nil {
+					ls = append(ls, listener)
+				}
+			} else if p.Protocol == "udp" {
+				listener := envoyv3.CreateUDPListener(listenerName, clusterName, "0.0.0.0", statsPrefix, uint32(p.ListenPort))
+				if listener != nil {
+					ls = append(ls, listener)
+				} else {
+					logrus.Warningf("create udp listener %s failure", listenerName)
+					continue
+				}
+			} else {
+				listener := envoyv3.CreateTCPListener(listenerName, clusterName, "0.0.0.0", statsPrefix, uint32(p.ListenPort), options.TCPIdleTimeout)
+				if tlsOptions := mtls.inboundOptions(statsPrefix); listener != nil && tlsOptions != nil {
+					listener = envoyv3.SecureListener(listener, *tlsOptions)
+				}
+				if listener != nil {
+					ls = append(ls, listener)
+				} else {
+					logrus.Warningf("create tcp listener %s failure", listenerName)
+					continue
+				}
+			}
+			portMap[port] = 1
+		}
+	}
+	return
+}
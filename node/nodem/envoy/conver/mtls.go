@@ -0,0 +1,106 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conver
+
+import (
+	"encoding/json"
+	"strconv"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	api_model "github.com/gridworkz/kato/api/model"
+	envoyv3 "github.com/gridworkz/kato/node/core/envoy/v3"
+	"github.com/gridworkz/kato/node/nodem/envoy/meshtls"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+//MeshTLS is the mutual tls setting of an envoy node, only the xds v3 api supports it.
+//A nil MeshTLS keeps all the traffic of the node in plaintext.
+type MeshTLS struct {
+	// the config of the component of the node
+	api_model.MeshTLSConfig
+	// Upstreams are the mutual tls modes of the depended components, keyed by service id
+	Upstreams map[string]string
+}
+
+//GetMeshTLSConfig returns the mutual tls config written by the worker into the plugin configmap,
+//mutual tls is disabled if the configmap has no config
+func GetMeshTLSConfig(configs *corev1.ConfigMap) *api_model.MeshTLSConfig {
+	config := &api_model.MeshTLSConfig{Mode: api_model.MeshTLSModeDisabled}
+	if configs == nil || configs.Data[api_model.KeyMeshTLSConfig] == "" {
+		return config
+	}
+	if err := json.Unmarshal([]byte(configs.Data[api_model.KeyMeshTLSConfig]), config); err != nil {
+		logrus.Warningf("configmap name: %s; parse mesh tls config failure %s", configs.Name, err.Error())
+		return &api_model.MeshTLSConfig{Mode: api_model.MeshTLSModeDisabled}
+	}
+	return config
+}
+
+func meshTLSEnabled(mode string) bool {
+	return mode == api_model.MeshTLSModePermissive || mode == api_model.MeshTLSModeStrict
+}
+
+//Enabled returns whether the node needs the identity certificate
+func (m *MeshTLS) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	if meshTLSEnabled(m.Mode) {
+		return true
+	}
+	for _, mode := range m.Upstreams {
+		if meshTLSEnabled(mode) {
+			return true
+		}
+	}
+	return false
+}
+
+//inboundOptions returns the mutual tls options of the inbound listeners, nil means plaintext
+func (m *MeshTLS) inboundOptions(statPrefix string) *envoyv3.MeshTLSOptions {
+	if m == nil || !meshTLSEnabled(m.Mode) {
+		return nil
+	}
+	options := &envoyv3.MeshTLSOptions{
+		Strict:     m.Mode == api_model.MeshTLSModeStrict,
+		StatPrefix: statPrefix,
+	}
+	for _, source := range m.AllowedSources {
+		expr, err := meshtls.ParsePattern(source)
+		if err != nil {
+			logrus.Warningf("ignore the allowed source %s of mesh tls: %s", source, err.Error())
+			continue
+		}
+		options.AllowedPrincipals = append(options.AllowedPrincipals, expr)
+	}
+	return options
+}
+
+//upstreamTransportSocket returns the mutual tls transport socket of the cluster calling the service, nil means plaintext.
+//Mutual tls is originated only if the inbound plugin of the depended component takes over the port of the service.
+func (m *MeshTLS) upstreamTransportSocket(service *corev1.Service) *core.TransportSocket {
+	if m == nil || !meshTLSEnabled(m.Upstreams[service.Labels["service_id"]]) {
+		return nil
+	}
+	if origin, _ := strconv.Atoi(service.Labels["origin_port"]); origin == 0 || service.Labels["port_protocol"] == "udp" {
+		return nil
+	}
+	return envoyv3.CreateUpstreamMTLSTransportSocket(meshtls.IdentityFromLabels(service.Labels).URI())
+}
@@ -0,0 +1,66 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package conver
+
+import (
+	"strings"
+
+	api_model "github.com/gridworkz/kato/api/model"
+)
+
+const (
+	//XDSVersionV2 envoy xds v2 api, removed from current envoy releases
+	XDSVersionV2 = "v2"
+	//XDSVersionV3 envoy xds v3 api
+	XDSVersionV3 = "v3"
+	//KeyXDSVersion plugin config option that pins the xds api version of the envoy node
+	KeyXDSVersion = "XDS_API_VERSION"
+)
+
+//ServeXDSVersion returns whether the envoy node of the plugin config is served on the given xds api version
+//a plugin config that does not pin a version is served on both during the v2 to v3 transition window,
+//so that sidecars still requesting v2 keep working until they are restarted with a v3 bootstrap
+func ServeXDSVersion(rs *api_model.ResourceSpec, version string) bool {
+	if pinned := PinnedXDSVersion(rs); pinned != "" {
+		return pinned == version
+	}
+	return true
+}
+
+//ServeNodeXDSVersion is ServeXDSVersion taking the mutual tls setting of the node into account,
+//only the xds v3 api supports mutual tls, so a node with mutual tls enabled is served on v3 only,
+//even if the plugin config pins v2. Serving it plaintext on v2 would bypass the mutual tls policy.
+func ServeNodeXDSVersion(rs *api_model.ResourceSpec, meshTLS *MeshTLS, version string) bool {
+	if meshTLS.Enabled() {
+		return version == XDSVersionV3
+	}
+	return ServeXDSVersion(rs, version)
+}
+
+//PinnedXDSVersion returns the xds api version pinned by the plugin config, empty if it is not pinned
+func PinnedXDSVersion(rs *api_model.ResourceSpec) string {
+	if rs == nil {
+		return ""
+	}
+	pinned, _ := rs.BaseNormal.Options[KeyXDSVersion].(string)
+	switch pinned = strings.ToLower(strings.TrimSpace(pinned)); pinned {
+	case XDSVersionV2, XDSVersionV3:
+		return pinned
+	}
+	return ""
+}
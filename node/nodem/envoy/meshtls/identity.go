@@ -0,0 +1,110 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package meshtls
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+//TrustDomain is the spiffe trust domain of the identities issued by the mesh ca
+const TrustDomain = "kato"
+
+// noneSegment stands for the empty segments, such as the application of a component not in any application
+const noneSegment = "-"
+
+//Identity is the identity of a component in the mesh, it is encoded as a spiffe uri
+//spiffe://kato/tenant/<tenant name>/app/<app id>/component/<service alias>
+type Identity struct {
+	Tenant    string
+	App       string
+	Component string
+}
+
+//IdentityFromLabels returns the identity of the component from the common labels of its kubernetes resources
+func IdentityFromLabels(labels map[string]string) Identity {
+	return Identity{
+		Tenant:    labels["tenant_name"],
+		App:       labels["app_id"],
+		Component: labels["service_alias"],
+	}
+}
+
+func segment(s string) string {
+	if s == "" {
+		return noneSegment
+	}
+	return url.PathEscape(s)
+}
+
+//URI returns the spiffe uri of the identity
+func (i Identity) URI() string {
+	return fmt.Sprintf("spiffe://%s/tenant/%s/app/%s/component/%s", TrustDomain, segment(i.Tenant), segment(i.App), segment(i.Component))
+}
+
+//String returns the identity as tenant/app/component, the format of the authorization rules
+func (i Identity) String() string {
+	return fmt.Sprintf("%s/%s/%s", segment(i.Tenant), segment(i.App), segment(i.Component))
+}
+
+//ParsePattern parses the identity pattern 'tenant/app/component' of the authorization rules, and returns
+//the regular expression matching the spiffe uris of the identities. A segment may contain '*' which
+//matches any characters in the segment, the missing segments match any value.
+func ParsePattern(pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", fmt.Errorf("empty identity pattern")
+	}
+	segments := strings.Split(pattern, "/")
+	if len(segments) > 3 {
+		return "", fmt.Errorf("identity pattern %s has more than 3 segments, the format is tenant/app/component", pattern)
+	}
+	for len(segments) < 3 {
+		segments = append(segments, "*")
+	}
+	var exprs []string
+	for _, s := range segments {
+		if s == "" {
+			return "", fmt.Errorf("identity pattern %s has an empty segment", pattern)
+		}
+		parts := strings.Split(s, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(url.PathEscape(parts[i]))
+		}
+		exprs = append(exprs, strings.Join(parts, "[^/]*"))
+	}
+	return fmt.Sprintf("^spiffe://%s/tenant/%s/app/%s/component/%s$", regexp.QuoteMeta(TrustDomain), exprs[0], exprs[1], exprs[2]), nil
+}
+
+//ParsePatterns parses the identity patterns separated by commas
+func ParsePatterns(patterns string) ([]string, error) {
+	var re []string
+	for _, p := range strings.Split(patterns, ",") {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		if _, err := ParsePattern(p); err != nil {
+			return nil, err
+		}
+		re = append(re, strings.TrimSpace(p))
+	}
+	return re, nil
+}
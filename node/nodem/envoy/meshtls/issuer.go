@@ -0,0 +1,78 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package meshtls
+
+import (
+	"sync"
+	"time"
+)
+
+//DefaultCertTTL is the validity of the component certificates
+var DefaultCertTTL = 24 * time.Hour
+
+//Issuer issues and caches the certificates of the component identities,
+//the certificate is reissued when it needs rotation
+type Issuer struct {
+	ca    *CA
+	ttl   time.Duration
+	lock  sync.Mutex
+	certs map[string]*Certificate
+	now   func() time.Time
+}
+
+//NewIssuer creates an issuer of the ca, ttl is the validity of the issued certificates
+func NewIssuer(ca *CA, ttl time.Duration) *Issuer {
+	if ttl <= 0 {
+		ttl = DefaultCertTTL
+	}
+	return &Issuer{
+		ca:    ca,
+		ttl:   ttl,
+		certs: make(map[string]*Certificate),
+		now:   time.Now,
+	}
+}
+
+//CA returns the ca of the issuer
+func (i *Issuer) CA() *CA {
+	return i.ca
+}
+
+//Get returns the certificate of the identity, a new certificate is issued if there is none
+//or the cached one needs rotation
+func (i *Issuer) Get(id Identity) (*Certificate, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if cert, ok := i.certs[id.URI()]; ok && !cert.NeedRotate(i.now()) {
+		return cert, nil
+	}
+	cert, err := i.ca.issue(id, i.now(), i.ttl)
+	if err != nil {
+		return nil, err
+	}
+	i.certs[id.URI()] = cert
+	return cert, nil
+}
+
+//Forget removes the cached certificate of the identity
+func (i *Issuer) Forget(id Identity) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	delete(i.certs, id.URI())
+}
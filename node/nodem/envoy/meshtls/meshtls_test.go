@@ -0,0 +1,130 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package meshtls
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA(time.Hour * 24 * 365)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := Identity{Tenant: "demo", App: "app1", Component: "grweb"}
+	cert, err := ca.Issue(id, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(cert.CertPEM)
+	if block == nil {
+		t.Fatal("no certificate pem issued")
+	}
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca.CertPEM()) {
+		t.Fatal("can not load the ca pem")
+	}
+	if _, err := x509Cert.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Fatalf("verify the issued certificate: %v", err)
+	}
+	if len(x509Cert.URIs) != 1 || x509Cert.URIs[0].String() != "spiffe://kato/tenant/demo/app/app1/component/grweb" {
+		t.Errorf("unexpected uri sans %v", x509Cert.URIs)
+	}
+	reloaded, err := ParseCA(ca.certPEM, ca.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.Issue(id, time.Hour); err != nil {
+		t.Errorf("issue with the reloaded ca: %v", err)
+	}
+}
+
+func TestIssuerRotation(t *testing.T) {
+	ca, err := NewCA(time.Hour * 24 * 365)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := NewIssuer(ca, 3*time.Hour)
+	now := time.Now()
+	issuer.now = func() time.Time { return now }
+	id := Identity{Tenant: "demo", Component: "grweb"}
+	first, err := issuer.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := issuer.Get(id); second != first {
+		t.Error("the cached certificate should be returned before rotation")
+	}
+	if first.NeedRotate(now) {
+		t.Error("a new certificate should not need rotation")
+	}
+	// two thirds of the lifetime
+	now = first.NotBefore.Add(2*time.Hour + time.Minute)
+	if !first.NeedRotate(now) {
+		t.Fatal("the certificate should be rotated after two thirds of its lifetime")
+	}
+	rotated, err := issuer.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated == first {
+		t.Error("a new certificate should be issued for rotation")
+	}
+	if cached, _ := issuer.Get(id); cached != rotated {
+		t.Error("the rotated certificate should be cached")
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		id      Identity
+		match   bool
+	}{
+		{pattern: "demo/app1/grweb", id: Identity{Tenant: "demo", App: "app1", Component: "grweb"}, match: true},
+		{pattern: "demo/app1/grweb", id: Identity{Tenant: "demo", App: "app1", Component: "grapi"}, match: false},
+		{pattern: "demo", id: Identity{Tenant: "demo", App: "app2", Component: "grapi"}, match: true},
+		{pattern: "demo", id: Identity{Tenant: "demo2", App: "app2", Component: "grapi"}, match: false},
+		{pattern: "*/*/gr*", id: Identity{Tenant: "other", Component: "grapi"}, match: true},
+		{pattern: "demo/-/grapi", id: Identity{Tenant: "demo", Component: "grapi"}, match: true},
+		{pattern: "de.o", id: Identity{Tenant: "demo", Component: "grapi"}, match: false},
+	}
+	for _, tc := range tests {
+		expr, err := ParsePattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("pattern %s: %v", tc.pattern, err)
+		}
+		if got := regexp.MustCompile(expr).MatchString(tc.id.URI()); got != tc.match {
+			t.Errorf("pattern %s, identity %s: want match %v, got %v", tc.pattern, tc.id, tc.match, got)
+		}
+	}
+	for _, pattern := range []string{"", "a/b/c/d", "a//c"} {
+		if _, err := ParsePattern(pattern); err == nil {
+			t.Errorf("pattern %q should be invalid", pattern)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package meshtls
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// the keys of the ca in the kubernetes secret
+const (
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+)
+
+//LoadOrCreateCA loads the mesh ca from the kubernetes secret, the ca is created and saved
+//into the secret if it does not exist. All nodes share the same ca through the secret.
+func LoadOrCreateCA(kubecli kubernetes.Interface, namespace, name string) (*CA, error) {
+	secret, err := kubecli.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err == nil {
+		return ParseCA(secret.Data[caCertKey], secret.Data[caKeyKey])
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return nil, fmt.Errorf("get mesh ca secret %s/%s: %v", namespace, name, err)
+	}
+	ca, err := NewCA(DefaultCATTL)
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"creator": "Kato"},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			caCertKey: ca.certPEM,
+			caKeyKey:  ca.keyPEM,
+		},
+	}
+	if _, err := kubecli.CoreV1().Secrets(namespace).Create(secret); err != nil {
+		if k8sErrors.IsAlreadyExists(err) {
+			// another node created the ca at the same time
+			return LoadOrCreateCA(kubecli, namespace, name)
+		}
+		return nil, fmt.Errorf("create mesh ca secret %s/%s: %v", namespace, name, err)
+	}
+	logrus.Infof("created mesh ca in secret %s/%s", namespace, name)
+	return ca, nil
+}
@@ -34,15 +34,19 @@ import (
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v2"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/v2"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/v2"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
 	api_model "github.com/gridworkz/kato/api/model"
 	"github.com/gridworkz/kato/cmd/node/option"
 	"github.com/gridworkz/kato/node/nodem/envoy/conver"
+	"github.com/gridworkz/kato/node/nodem/envoy/meshtls"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	corev1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	kcache "k8s.io/client-go/tools/cache"
@@ -51,9 +55,11 @@ import (
 //DiscoverServerManager
 type DiscoverServerManager struct {
 	server          server.Server
+	serverV3        serverv3.Server
 	conf            option.Conf
 	grpcServer      *grpc.Server
 	cacheManager    cache.SnapshotCache
+	cacheManagerV3  cachev3.SnapshotCache
 	cacheNodeConfig []*NodeConfig
 	kubecli         kubernetes.Interface
 	eventChan       chan *Event
@@ -64,6 +70,8 @@ type DiscoverServerManager struct {
 	endpoints       cacheHandler
 	configmaps      cacheHandler
 	queue           Queue
+	// issuer of the mesh mutual tls certificates, mutual tls is disabled if it is nil
+	issuer *meshtls.Issuer
 }
 
 // Hasher returns node ID as an ID
@@ -88,6 +96,12 @@ type NodeConfig struct {
 	configModel                    *api_model.ResourceSpec
 	dependServices                 sync.Map
 	listeners, clusters, endpoints []types.Resource
+	// xds v3 resources, see conver.ServeXDSVersion
+	listenersV3, clustersV3, endpointsV3, secretsV3 []types.Resource
+	// identity and mutual tls setting of the component, only the xds v3 api supports mutual tls
+	identity    meshtls.Identity
+	meshTLS     *conver.MeshTLS
+	certificate *meshtls.Certificate
 }
 
 //GetID get envoy node config id
@@ -114,6 +128,12 @@ func (n *NodeConfig) TryUpdate(obj interface{}) (needUpdate bool) {
 			return true
 		}
 	}
+	// the mesh tls mode of the depended components is in their plugin configmaps
+	if configMap, ok := obj.(*corev1.ConfigMap); ok && checkIsHandleResource(configMap) {
+		if _, ok := n.dependServices.Load(configMap.Labels["service_id"]); ok {
+			return true
+		}
+	}
 	return false
 }
 
@@ -165,6 +185,7 @@ func (d *DiscoverServerManager) NewNodeConfig(config *corev1.ConfigMap) (*NodeCo
 		config:         config,
 		configModel:    configs,
 		dependServices: sync.Map{},
+		identity:       meshtls.IdentityFromLabels(config.Labels),
 	}
 	return nc, nil
 }
@@ -214,6 +235,25 @@ func (d *DiscoverServerManager) UpdateNodeConfig(nc *NodeConfig) error {
 			endpoint = append(endpoint, downEndpoint...)
 		}
 	}
+	// mutual tls decides the api versions of the node, see conver.ServeNodeXDSVersion
+	nc.meshTLS = d.getMeshTLS(nc)
+	serveV2 := conver.ServeNodeXDSVersion(nc.configModel, nc.meshTLS, conver.XDSVersionV2)
+	serveV3 := conver.ServeNodeXDSVersion(nc.configModel, nc.meshTLS, conver.XDSVersionV3)
+	if !serveV2 && conver.PinnedXDSVersion(nc.configModel) == conver.XDSVersionV2 {
+		logrus.Errorf("envoy node %s is pinned to xds v2 but mesh mutual tls is enabled, it is served on v3 only", nc.GetID())
+	}
+	if serveV2 {
+		d.converV2(nc, services, endpoint)
+	}
+	if serveV3 {
+		d.converV3(nc, services, endpoint)
+	}
+	//Fill the configuration information and inject envoy
+	nc.VersionUpdate()
+	return d.setSnapshot(nc, serveV2, serveV3)
+}
+
+func (d *DiscoverServerManager) converV2(nc *NodeConfig, services []*corev1.Service, endpoint []*corev1.Endpoints) {
 	listeners, err := conver.OneNodeListerner(nc.serviceAlias, nc.namespace, nc.config, services)
 	if err != nil {
 		logrus.Errorf("create envoy listeners failure %s", err.Error())
@@ -230,22 +270,32 @@ func (d *DiscoverServerManager) UpdateNodeConfig(nc *NodeConfig) error {
 	if len(clusterLoadAssignment) == 0 {
 		logrus.Warningf("configmap name: %s; plugin-config: %s; empty clusterLoadAssignment", nc.config.Name, nc.config.Data["plugin-config"])
 	}
-	if err != nil {
-		logrus.Errorf("create envoy endpoints failure %s", err.Error())
-	} else {
-		nc.endpoints = clusterLoadAssignment
+	nc.endpoints = clusterLoadAssignment
+}
+
+//setSnapshot sets the snapshots of both api versions, a failure of one version does not keep the other one stale
+func (d *DiscoverServerManager) setSnapshot(nc *NodeConfig, serveV2, serveV3 bool) error {
+	var errs []error
+	if !serveV2 {
+		// the node has been pinned to another api version
+		d.cacheManager.ClearSnapshot(nc.nodeID)
+	} else if err := d.setSnapshotV2(nc); err != nil {
+		errs = append(errs, fmt.Errorf("set v2 snapshot: %v", err))
 	}
-	//Fill the configuration information and inject envoy
-	nc.VersionUpdate()
-	return d.setSnapshot(nc)
+	if !serveV3 {
+		d.cacheManagerV3.ClearSnapshot(nc.nodeID)
+	} else if err := d.setSnapshotV3(nc); err != nil {
+		errs = append(errs, fmt.Errorf("set v3 snapshot: %v", err))
+	}
+	return utilerrors.NewAggregate(errs)
 }
 
-func (d *DiscoverServerManager) setSnapshot(nc *NodeConfig) error {
+func (d *DiscoverServerManager) setSnapshotV2(nc *NodeConfig) error {
 	if len(nc.clusters) < 1 || len(nc.listeners) < 1 {
 		logrus.Warningf("node id: %s; node config cluster length is zero or listener length is zero,not set snapshot", nc.GetID())
 		return nil
 	}
-	snapshot := cache.NewSnapshot(nc.GetVersion(), nc.endpoints, nc.clusters, nil, nc.listeners, nil)
+	snapshot := cache.NewSnapshot(nc.GetVersion(), nc.endpoints, nc.clusters, nil, nc.listeners, nil, nil)
 	err := d.cacheManager.SetSnapshot(nc.nodeID, snapshot)
 	if err != nil {
 		return err
@@ -257,13 +307,17 @@ func (d *DiscoverServerManager) setSnapshot(nc *NodeConfig) error {
 //CreateDiscoverServerManager
 func CreateDiscoverServerManager(clientset kubernetes.Interface, conf option.Conf) (*DiscoverServerManager, error) {
 	configcache := cache.NewSnapshotCache(false, Hasher{}, logrus.WithField("module", "config-cache"))
+	// v3 resources are requested over ADS, the cache holds a response until all requested resources are known
+	configcacheV3 := cachev3.NewSnapshotCache(true, HasherV3{}, logrus.WithField("module", "config-cache-v3"))
 	ctx, cancel := context.WithCancel(context.Background())
 	dsm := &DiscoverServerManager{
-		server:       server.NewServer(ctx, configcache, nil),
-		cacheManager: configcache,
-		kubecli:      clientset,
-		conf:         conf,
-		eventChan:    make(chan *Event, 100),
+		server:         server.NewServer(ctx, configcache, nil),
+		serverV3:       serverv3.NewServer(ctx, configcacheV3, newXDSAuthorizer(clientset).callbacks()),
+		cacheManager:   configcache,
+		cacheManagerV3: configcacheV3,
+		kubecli:        clientset,
+		conf:           conf,
+		eventChan:      make(chan *Event, 100),
 		pool: &sync.Pool{
 			New: func() interface{} {
 				return &Task{}
@@ -273,6 +327,13 @@ func CreateDiscoverServerManager(clientset kubernetes.Interface, conf option.Con
 		cancel: cancel,
 		queue:  NewQueue(1 * time.Second),
 	}
+	ca, err := meshtls.LoadOrCreateCA(clientset, conf.RbdNamespace, conf.MeshCASecret)
+	if err != nil {
+		// the components keep talking in plaintext
+		logrus.Errorf("load mesh ca failure, mesh mutual tls is disabled: %s", err.Error())
+	} else {
+		dsm.issuer = meshtls.NewIssuer(ca, conf.MeshCertTTL)
+	}
 	sharedInformers := informers.NewFilteredSharedInformerFactory(dsm.kubecli, time.Second*10, corev1.NamespaceAll, func(options *meta_v1.ListOptions) {
 		options.LabelSelector = "creator=Kato"
 	})
@@ -283,6 +344,7 @@ func CreateDiscoverServerManager(clientset kubernetes.Interface, conf option.Con
 	configsInformer := sharedInformers.Core().V1().ConfigMaps().Informer()
 	dsm.configmaps = dsm.createCacheHandler(configsInformer, "ConfigMaps")
 	dsm.configmaps.handler.Append(dsm.configHandle)
+	dsm.configmaps.handler.Append(dsm.resourceSimpleHandle)
 	dsm.endpoints.handler.Append(dsm.resourceSimpleHandle)
 	dsm.services.handler.Append(dsm.resourceSimpleHandle)
 	return dsm, nil
@@ -294,6 +356,7 @@ const grpcMaxConcurrentStreams = 1000000
 func (d *DiscoverServerManager) Start(errch chan error) error {
 	go func() {
 		go d.queue.Run(d.ctx.Done())
+		go d.rotateCertificates()
 		go d.services.informer.Run(d.ctx.Done())
 		go d.endpoints.informer.Run(d.ctx.Done())
 		//waiting service and endpoint resource loading is complete
@@ -316,6 +379,7 @@ func (d *DiscoverServerManager) Start(errch chan error) error {
 		v2.RegisterRouteDiscoveryServiceServer(d.grpcServer, d.server)
 		v2.RegisterListenerDiscoveryServiceServer(d.grpcServer, d.server)
 		discovery.RegisterSecretDiscoveryServiceServer(d.grpcServer, d.server)
+		d.registerV3(d.grpcServer)
 		logrus.Infof("envoy grpc management server listening %s", d.conf.GrpcAPIAddr)
 		lis, err := net.Listen("tcp", d.conf.GrpcAPIAddr)
 		if err != nil {
@@ -409,6 +473,10 @@ func (d *DiscoverServerManager) DeleteNodeConfig(nodeID string) {
 	for i, existNC := range d.cacheNodeConfig {
 		if existNC.nodeID == nodeID {
 			d.cacheManager.ClearSnapshot(existNC.nodeID)
+			d.cacheManagerV3.ClearSnapshot(existNC.nodeID)
+			if d.issuer != nil {
+				d.issuer.Forget(existNC.identity)
+			}
 			d.cacheNodeConfig = append(d.cacheNodeConfig[:i], d.cacheNodeConfig[i+1:]...)
 		}
 	}
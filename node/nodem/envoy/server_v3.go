@@ -0,0 +1,192 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package envoy
+
+import (
+	"fmt"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
+	secretservice "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	api_model "github.com/gridworkz/kato/api/model"
+	envoyv3 "github.com/gridworkz/kato/node/core/envoy/v3"
+	"github.com/gridworkz/kato/node/nodem/envoy/conver"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kcache "k8s.io/client-go/tools/cache"
+)
+
+// certRotationCheckInterval is the interval to check the certificates of the components for rotation
+var certRotationCheckInterval = time.Minute
+
+// HasherV3 returns node cluster as the ID of a xds v3 node
+type HasherV3 struct {
+}
+
+// ID function
+func (h HasherV3) ID(node *corev3.Node) string {
+	if node == nil {
+		return "unknown"
+	}
+	return node.Cluster
+}
+
+//registerV3 register the xds v3 services, envoy uses the aggregated discovery service,
+//the single resource services are kept for clients such as the init probe
+func (d *DiscoverServerManager) registerV3(grpcServer *grpc.Server) {
+	discoveryv3.RegisterAggregatedDiscoveryServiceServer(grpcServer, d.serverV3)
+	endpointservice.RegisterEndpointDiscoveryServiceServer(grpcServer, d.serverV3)
+	clusterservice.RegisterClusterDiscoveryServiceServer(grpcServer, d.serverV3)
+	routeservice.RegisterRouteDiscoveryServiceServer(grpcServer, d.serverV3)
+	listenerservice.RegisterListenerDiscoveryServiceServer(grpcServer, d.serverV3)
+	secretservice.RegisterSecretDiscoveryServiceServer(grpcServer, d.serverV3)
+}
+
+func (d *DiscoverServerManager) converV3(nc *NodeConfig, services []*corev1.Service, endpoint []*corev1.Endpoints) {
+	secrets, err := d.meshTLSSecrets(nc)
+	if err != nil {
+		logrus.Errorf("issue mesh tls certificate of %s failure %s, mutual tls is disabled", nc.identity, err.Error())
+		nc.meshTLS, secrets = nil, nil
+	}
+	if secrets == nil {
+		nc.certificate = nil
+	}
+	nc.secretsV3 = secrets
+	listeners, err := conver.OneNodeListernerV3(nc.serviceAlias, nc.namespace, nc.config, services, nc.meshTLS)
+	if err != nil {
+		logrus.Errorf("create envoy v3 listeners failure %s", err.Error())
+	} else {
+		nc.listenersV3 = listeners
+	}
+	clusters, err := conver.OneNodeClusterV3(nc.serviceAlias, nc.namespace, nc.config, services, nc.meshTLS)
+	if err != nil {
+		logrus.Errorf("create envoy v3 clusters failure %s", err.Error())
+	} else {
+		nc.clustersV3 = clusters
+	}
+	clusterLoadAssignment := conver.OneNodeClusterLoadAssignmentV3(nc.serviceAlias, nc.namespace, endpoint, services)
+	if len(clusterLoadAssignment) == 0 {
+		logrus.Warningf("configmap name: %s; plugin-config: %s; empty v3 clusterLoadAssignment", nc.config.Name, nc.config.Data["plugin-config"])
+	}
+	nc.endpointsV3 = clusterLoadAssignment
+}
+
+func (d *DiscoverServerManager) setSnapshotV3(nc *NodeConfig) error {
+	if len(nc.clustersV3) < 1 || len(nc.listenersV3) < 1 {
+		logrus.Warningf("node id: %s; node v3 config cluster length is zero or listener length is zero,not set snapshot", nc.GetID())
+		return nil
+	}
+	snapshot := cachev3.NewSnapshot(nc.GetVersion(), nc.endpointsV3, nc.clustersV3, nil, nc.listenersV3, nil, nc.secretsV3)
+	if err := d.cacheManagerV3.SetSnapshot(nc.nodeID, snapshot); err != nil {
+		return err
+	}
+	logrus.Infof("cache envoy node %s v3 config,version: %s", nc.GetID(), nc.GetVersion())
+	return nil
+}
+
+//getMeshTLS returns the mutual tls setting of the node, the modes of the depended components
+//are read from their plugin configmaps
+func (d *DiscoverServerManager) getMeshTLS(nc *NodeConfig) *conver.MeshTLS {
+	if d.issuer == nil {
+		return nil
+	}
+	mtls := &conver.MeshTLS{
+		MeshTLSConfig: *conver.GetMeshTLSConfig(nc.config),
+		Upstreams:     make(map[string]string),
+	}
+	for _, dep := range nc.configModel.BaseServices {
+		mtls.Upstreams[dep.DependServiceID] = d.getUpstreamMeshTLSMode(nc.namespace, dep.DependServiceID)
+	}
+	return mtls
+}
+
+func (d *DiscoverServerManager) getUpstreamMeshTLSMode(namespace, serviceID string) string {
+	mode := api_model.MeshTLSModeDisabled
+	selector, err := labels.Parse(fmt.Sprintf("service_id=%s", serviceID))
+	if err != nil {
+		logrus.Errorf("parse selector of service %s failure %s", serviceID, err.Error())
+		return mode
+	}
+	kcache.ListAllByNamespace(d.configmaps.informer.GetIndexer(), namespace, selector, func(obj interface{}) {
+		configMap := obj.(*corev1.ConfigMap)
+		if !checkIsHandleResource(configMap) || configMap.Data[api_model.KeyMeshTLSConfig] == "" {
+			return
+		}
+		mode = conver.GetMeshTLSConfig(configMap).Mode
+	})
+	return mode
+}
+
+//meshTLSSecrets returns the sds secrets of the node, the identity certificate is issued or rotated if needed
+func (d *DiscoverServerManager) meshTLSSecrets(nc *NodeConfig) ([]types.Resource, error) {
+	if !nc.meshTLS.Enabled() {
+		return nil, nil
+	}
+	cert, err := d.issuer.Get(nc.identity)
+	if err != nil {
+		return nil, err
+	}
+	nc.certificate = cert
+	return []types.Resource{
+		envoyv3.CreateTLSCertificateSecret(envoyv3.IdentitySecretName, cert.CertPEM, cert.KeyPEM),
+		envoyv3.CreateValidationContextSecret(envoyv3.RootCASecretName, d.issuer.CA().CertPEM()),
+	}, nil
+}
+
+//rotateCertificates checks the certificates periodically, the check is pushed into the queue
+//because the node configs are handled there only
+func (d *DiscoverServerManager) rotateCertificates() {
+	if d.issuer == nil {
+		return
+	}
+	ticker := time.NewTicker(certRotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			d.queue.Push(NewTask(d.rotateHandle, nil, EventUpdate))
+		}
+	}
+}
+
+//rotateHandle updates the node configs whose certificate needs rotation, the new certificate
+//is delivered with a new snapshot version before the old one expires
+func (d *DiscoverServerManager) rotateHandle(obj interface{}, event Event) error {
+	for i, nodeConfig := range d.cacheNodeConfig {
+		if nodeConfig.certificate == nil || !nodeConfig.certificate.NeedRotate(time.Now()) {
+			continue
+		}
+		logrus.Infof("rotate the mesh tls certificate of envoy node %s", nodeConfig.GetID())
+		if err := d.UpdateNodeConfig(d.cacheNodeConfig[i]); err != nil {
+			logrus.Errorf("update envoy node config failure %s", err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package envoy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+//xdsAuthorizer ties the delivery of the sds secrets to the caller. The secrets hold the private key
+//of the identity of a component, so they are delivered only to a peer whose address is the ip of a pod
+//of the component named by the node of the request, other xds resources are served to any caller.
+type xdsAuthorizer struct {
+	// podIPs returns the ips of the pods of the component
+	podIPs func(namespace, serviceAlias string) ([]string, error)
+	lock   sync.Mutex
+	// streams are the open streams keyed by stream id
+	streams map[int64]*xdsStream
+}
+
+type xdsStream struct {
+	peerIP string
+	// the node cluster the stream has been authorized for
+	authorized string
+}
+
+func newXDSAuthorizer(kubecli kubernetes.Interface) *xdsAuthorizer {
+	return &xdsAuthorizer{
+		podIPs: func(namespace, serviceAlias string) ([]string, error) {
+			pods, err := kubecli.CoreV1().Pods(namespace).List(meta_v1.ListOptions{
+				LabelSelector: fmt.Sprintf("creator=Kato,service_alias=%s", serviceAlias),
+			})
+			if err != nil {
+				return nil, err
+			}
+			var ips []string
+			for _, pod := range pods.Items {
+				if pod.Status.PodIP != "" {
+					ips = append(ips, pod.Status.PodIP)
+				}
+			}
+			return ips, nil
+		},
+		streams: make(map[int64]*xdsStream),
+	}
+}
+
+//callbacks returns the callbacks of the xds v3 server
+func (a *xdsAuthorizer) callbacks() serverv3.Callbacks {
+	return serverv3.CallbackFuncs{
+		StreamOpenFunc:    a.onStreamOpen,
+		StreamClosedFunc:  a.onStreamClosed,
+		StreamRequestFunc: a.onStreamRequest,
+		FetchRequestFunc:  a.onFetchRequest,
+	}
+}
+
+func (a *xdsAuthorizer) onStreamOpen(ctx context.Context, streamID int64, typeURL string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.streams[streamID] = &xdsStream{peerIP: peerIP(ctx)}
+	return nil
+}
+
+func (a *xdsAuthorizer) onStreamClosed(streamID int64) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.streams, streamID)
+}
+
+func (a *xdsAuthorizer) onStreamRequest(streamID int64, req *discovery.DiscoveryRequest) error {
+	if req.TypeUrl != resourcev3.SecretType {
+		return nil
+	}
+	cluster := req.GetNode().GetCluster()
+	a.lock.Lock()
+	stream, ok := a.streams[streamID]
+	a.lock.Unlock()
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "unknown xds stream %d", streamID)
+	}
+	// the node of a stream may change between the requests, so the authorized cluster is checked every time
+	if stream.authorized == cluster {
+		return nil
+	}
+	if err := a.authorize(stream.peerIP, cluster); err != nil {
+		return err
+	}
+	a.lock.Lock()
+	stream.authorized = cluster
+	a.lock.Unlock()
+	return nil
+}
+
+func (a *xdsAuthorizer) onFetchRequest(ctx context.Context, req *discovery.DiscoveryRequest) error {
+	if req.TypeUrl != resourcev3.SecretType {
+		return nil
+	}
+	return a.authorize(peerIP(ctx), req.GetNode().GetCluster())
+}
+
+//authorize checks the peer is a pod of the component of the node cluster, see createNodeID
+func (a *xdsAuthorizer) authorize(ip, cluster string) error {
+	info := strings.SplitN(cluster, "_", 3)
+	if ip == "" || len(info) != 3 || info[0] == "" || info[2] == "" {
+		logrus.Warningf("deny the sds request of node %s from %s", cluster, ip)
+		return status.Errorf(codes.PermissionDenied, "sds secrets of node %s are not available to %s", cluster, ip)
+	}
+	ips, err := a.podIPs(info[0], info[2])
+	if err != nil {
+		logrus.Errorf("list the pods of node %s failure %s", cluster, err.Error())
+		return status.Errorf(codes.Unavailable, "list the pods of node %s failure", cluster)
+	}
+	for _, podIP := range ips {
+		if podIP == ip {
+			return nil
+		}
+	}
+	logrus.Warningf("deny the sds request of node %s from %s, it is not a pod of the component", cluster, ip)
+	return status.Errorf(codes.PermissionDenied, "sds secrets of node %s are not available to %s", cluster, ip)
+}
+
+//peerIP returns the ip of the grpc peer of the context, empty if it is unknown
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
@@ -0,0 +1,80 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package envoy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/grpc/peer"
+)
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+}
+
+func secretRequest(cluster string) *discovery.DiscoveryRequest {
+	return &discovery.DiscoveryRequest{TypeUrl: resourcev3.SecretType, Node: &corev3.Node{Cluster: cluster}}
+}
+
+func TestXDSAuthorizer(t *testing.T) {
+	a := &xdsAuthorizer{
+		podIPs: func(namespace, serviceAlias string) ([]string, error) {
+			if namespace == "tenant" && serviceAlias == "gr123456" {
+				return []string{"10.0.0.1"}, nil
+			}
+			return nil, nil
+		},
+		streams: make(map[int64]*xdsStream),
+	}
+	node := createNodeID("tenant", "plugin", "gr123456")
+	if err := a.onStreamOpen(peerContext("10.0.0.1"), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.onStreamRequest(1, secretRequest(node)); err != nil {
+		t.Errorf("the pod of the component should get its secrets: %v", err)
+	}
+	// the node of the stream changes to another component
+	if err := a.onStreamRequest(1, secretRequest(createNodeID("tenant", "plugin", "gr654321"))); err == nil {
+		t.Errorf("the pod should not get the secrets of another component")
+	}
+	a.onStreamClosed(1)
+	if err := a.onStreamOpen(peerContext("10.0.0.2"), 2, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.onStreamRequest(2, secretRequest(node)); err == nil {
+		t.Errorf("a peer that is not a pod of the component should not get the secrets")
+	}
+	if err := a.onStreamRequest(2, &discovery.DiscoveryRequest{TypeUrl: resourcev3.ClusterType, Node: &corev3.Node{Cluster: node}}); err != nil {
+		t.Errorf("the other resources should be served to any peer: %v", err)
+	}
+	if err := a.onFetchRequest(peerContext("10.0.0.2"), secretRequest(node)); err == nil {
+		t.Errorf("a fetch from a peer that is not a pod of the component should be denied")
+	}
+	if err := a.onFetchRequest(peerContext("10.0.0.1"), secretRequest(node)); err != nil {
+		t.Errorf("a fetch from the pod of the component should be allowed: %v", err)
+	}
+	if err := a.onFetchRequest(peerContext("10.0.0.1"), secretRequest("invalid")); err == nil {
+		t.Errorf("an invalid node should be denied")
+	}
+}
@@ -25,8 +25,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/client"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 	"github.com/shirou/gopsutil/disk"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/errors"
@@ -36,7 +35,7 @@ import (
 
 var (
 	// ErrImageNotFound -
-	ErrImageNotFound = goerrors.New("image not found")
+	ErrImageNotFound = containerruntime.ErrImageNotFound
 )
 
 // FsStats -
@@ -78,7 +77,7 @@ type ImageGCPolicy struct {
 }
 
 type realImageGCManager struct {
-	dockerClient *client.Client
+	runtime containerruntime.ContainerRuntime
 
 	// Records of images and their use.
 	imageRecords     map[string]*imageRecord
@@ -108,7 +107,7 @@ type imageRecord struct {
 }
 
 // NewImageGCManager instantiates a new ImageGCManager object.
-func NewImageGCManager(dockerClient *client.Client, policy ImageGCPolicy, sandboxImage string) (ImageGCManager, error) {
+func NewImageGCManager(runtime containerruntime.ContainerRuntime, policy ImageGCPolicy, sandboxImage string) (ImageGCManager, error) {
 	// Validate policy.
 	if policy.HighThresholdPercent < 0 || policy.HighThresholdPercent > 100 {
 		return nil, fmt.Errorf("invalid HighThresholdPercent %d, must be in range [0-100]", policy.HighThresholdPercent)
@@ -120,7 +119,7 @@ func NewImageGCManager(dockerClient *client.Client, policy ImageGCPolicy, sandbo
 		return nil, fmt.Errorf("LowThresholdPercent %d can not be higher than HighThresholdPercent %d", policy.LowThresholdPercent, policy.HighThresholdPercent)
 	}
 	im := &realImageGCManager{
-		dockerClient: dockerClient,
+		runtime:      runtime,
 		policy:       policy,
 		imageRecords: make(map[string]*imageRecord),
 		initialized:  false,
@@ -229,58 +228,28 @@ func (im *realImageGCManager) getImageRef(imageID string) (string, error) {
 	ctx, cancel := getContextWithTimeout(3 * time.Second)
 	defer cancel()
 
-	inspect, _, err := im.dockerClient.ImageInspectWithRaw(ctx, imageID)
-	if err != nil {
-		if strings.Contains(err.Error(), "No such image") {
-			return "", ErrImageNotFound
-		}
-		return "", err
-	}
-
-	return inspect.ID, nil
+	return im.runtime.GetImageRef(ctx, imageID)
 }
 
-func (im *realImageGCManager) listImages() ([]types.ImageSummary, error) {
+func (im *realImageGCManager) listImages() ([]*containerruntime.Image, error) {
 	ctx, cancel := getContextWithTimeout(3 * time.Second)
 	defer cancel()
 
-	return im.dockerClient.ImageList(ctx, types.ImageListOptions{})
+	return im.runtime.ListImages(ctx)
 }
 
 func (im *realImageGCManager) removeImage(imageID string) error {
 	ctx, cancel := getContextWithTimeout(3 * time.Second)
 	defer cancel()
 
-	opts := types.ImageRemoveOptions{
-		Force: true,
-	}
-	items, err := im.dockerClient.ImageRemove(ctx, imageID, opts)
-	if err != nil {
-		return err
-	}
-
-	for _, item := range items {
-		if item.Deleted != "" {
-			logrus.Debugf("image deleted: %s", item.Deleted)
-		}
-		if item.Untagged != "" {
-			logrus.Debugf("image untagged: %s", item.Untagged)
-		}
-	}
-
-	return nil
+	return im.runtime.RemoveImage(ctx, imageID)
 }
 
-func (im *realImageGCManager) dockerRootDir() (string, error) {
+func (im *realImageGCManager) imageFsPath() (string, error) {
 	ctx, cancel := getContextWithTimeout(3 * time.Second)
 	defer cancel()
 
-	dockerInfo, err := im.dockerClient.Info(ctx)
-	if err != nil {
-		return "", fmt.Errorf("docker info: %v", err)
-	}
-
-	return dockerInfo.DockerRootDir, nil
+	return im.runtime.ImageFsPath(ctx)
 }
 
 // getContextWithTimeout returns a context with timeout.
@@ -289,14 +258,14 @@ func getContextWithTimeout(timeout time.Duration) (context.Context, context.Canc
 }
 
 func (im *realImageGCManager) GarbageCollect() error {
-	dockerRootDir, err := im.dockerRootDir()
+	imageFsPath, err := im.imageFsPath()
 	if err != nil {
-		logrus.Errorf("failed to get docker root dir: %v; use '/var/lib/docker'", err)
-		dockerRootDir = "/var/lib/docker"
+		imageFsPath = "/var/lib/" + im.runtime.Name()
+		logrus.Errorf("failed to get image filesystem path of %s: %v; use '%s'", im.runtime.Name(), err, imageFsPath)
 	}
 
-	logrus.Infof("docker root dir: %s", dockerRootDir)
-	fsStats, err := GetFsStats(dockerRootDir)
+	logrus.Infof("image filesystem path: %s", imageFsPath)
+	fsStats, err := GetFsStats(imageFsPath)
 	if err != nil {
 		return err
 	}
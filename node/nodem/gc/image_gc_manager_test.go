@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 )
 
 var dockerTimeout = 10 * time.Second
@@ -22,7 +23,7 @@ func TestGetImageRef(t *testing.T) {
 	}
 
 	im := realImageGCManager{
-		dockerClient: dockerCli,
+		runtime: containerruntime.NewDockerRuntimeWithClient(dockerCli),
 	}
 	if _, err := im.getImageRef("nginx"); err != nil {
 		t.Error(err)
@@ -36,7 +37,7 @@ func TestListImages(t *testing.T) {
 	}
 
 	im := realImageGCManager{
-		dockerClient: dockerCli,
+		runtime: containerruntime.NewDockerRuntimeWithClient(dockerCli),
 	}
 
 	images, err := im.listImages()
@@ -56,7 +57,7 @@ func TestRemoveImage(t *testing.T) {
 	}
 
 	im := realImageGCManager{
-		dockerClient: dockerCli,
+		runtime: containerruntime.NewDockerRuntimeWithClient(dockerCli),
 	}
 
 	if err := im.removeImage("sha256:568c4670fa800978e08e4a51132b995a54f8d5ae83ca133ef5546d092b864acf"); err != nil {
@@ -78,6 +79,66 @@ func TestDockerRootDir(t *testing.T) {
 	t.Logf("docker root dir: %s", dockerInfo.DockerRootDir)
 }
 
+type fakeRuntime struct {
+	containerruntime.ContainerRuntime
+	images []*containerruntime.Image
+	refs   map[string]string
+}
+
+func (f *fakeRuntime) ListImages(ctx context.Context) ([]*containerruntime.Image, error) {
+	return f.images, nil
+}
+
+func (f *fakeRuntime) GetImageRef(ctx context.Context, image string) (string, error) {
+	if ref, ok := f.refs[image]; ok {
+		return ref, nil
+	}
+	return "", containerruntime.ErrImageNotFound
+}
+
+func TestDetectImages(t *testing.T) {
+	runtime := &fakeRuntime{
+		images: []*containerruntime.Image{
+			{ID: "sha256:pause", Size: 100},
+			{ID: "sha256:nginx", Size: 200},
+			{ID: "sha256:redis", Size: 300},
+		},
+		refs: map[string]string{
+			"k8s.gcr.io/pause:3.1": "sha256:pause",
+			"nginx:latest":         "sha256:nginx",
+		},
+	}
+	im, err := NewImageGCManager(runtime, ImageGCPolicy{HighThresholdPercent: 90, LowThresholdPercent: 75}, "k8s.gcr.io/pause:3.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	im.SetServiceImages([]string{"nginx:latest", "mysql:5.7"})
+	rim := im.(*realImageGCManager)
+
+	detectTime := time.Now()
+	imagesInUse, err := rim.detectImages(detectTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imagesInUse.Len() != 2 || !imagesInUse.Has("sha256:pause") || !imagesInUse.Has("sha256:nginx") {
+		t.Errorf("unexpected images in use %v", imagesInUse.List())
+	}
+	if len(rim.imageRecords) != 3 {
+		t.Fatalf("want 3 image records, got %d", len(rim.imageRecords))
+	}
+	if record := rim.imageRecords["sha256:redis"]; record.size != 300 || !record.firstDetected.Equal(detectTime) || !record.lastUsed.IsZero() {
+		t.Errorf("unexpected record of unused image %+v", record)
+	}
+
+	runtime.images = runtime.images[:2]
+	if _, err := rim.detectImages(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rim.imageRecords["sha256:redis"]; ok {
+		t.Errorf("the record of the removed image should be deleted")
+	}
+}
+
 func TestFoobar(t *testing.T) {
 	f := 62.123
 	t.Errorf("%0.f%%", f)
@@ -0,0 +1,106 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// the tags of the cri log lines
+const (
+	criTagPartial = "P"
+	criTagFull    = "F"
+)
+
+// decodeCRIFunc is used to create a decoder for the log files of the cri runtimes,
+// each line is '<RFC3339Nano time> <stdout|stderr> <P|F> <log>'
+func decodeCRIFunc(rdr io.Reader) func() (*Message, error) {
+	br := bufio.NewReader(rdr)
+	return func() (*Message, error) {
+		for {
+			line, err := br.ReadBytes('\n')
+			if err != nil {
+				// the line is being written, rewind to read it completely next time
+				if err == io.EOF && len(line) > 0 {
+					if seeker, ok := rdr.(io.Seeker); ok {
+						if _, serr := seeker.Seek(-int64(len(line)), io.SeekCurrent); serr != nil {
+							logrus.Warnf("rewind the cri log file failure %s", serr.Error())
+						}
+					}
+				}
+				return nil, err
+			}
+			msg, err := parseCRILogLine(line)
+			if err != nil {
+				logrus.Warnf("skip the invalid cri log line: %s", err.Error())
+				continue
+			}
+			return msg, nil
+		}
+	}
+}
+
+// parseCRILogLine parses a line of the cri log file, the full lines keep their line break as
+// the lines of the json-file log driver, the partial lines have none
+func parseCRILogLine(line []byte) (*Message, error) {
+	idx := bytes.IndexByte(line, ' ')
+	if idx < 0 {
+		return nil, fmt.Errorf("timestamp is not found in %q", line)
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, string(line[:idx]))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected timestamp format %q: %v", line[:idx], err)
+	}
+	line = line[idx+1:]
+	idx = bytes.IndexByte(line, ' ')
+	if idx < 0 {
+		return nil, fmt.Errorf("stream type is not found in %q", line)
+	}
+	stream := string(line[:idx])
+	if stream != "stdout" && stream != "stderr" {
+		return nil, fmt.Errorf("unexpected stream type %q", stream)
+	}
+	line = line[idx+1:]
+	idx = bytes.IndexByte(line, ' ')
+	if idx < 0 {
+		return nil, fmt.Errorf("log tag is not found in %q", line)
+	}
+	// the tag may carry more fields separated by ':' in the future
+	tag := string(bytes.SplitN(line[:idx], []byte(":"), 2)[0])
+	if tag != criTagPartial && tag != criTagFull {
+		return nil, fmt.Errorf("unexpected log tag %q", tag)
+	}
+	content := line[idx+1:]
+	msg := &Message{
+		Source:    stream,
+		Timestamp: timestamp,
+		Partial:   tag == criTagPartial,
+	}
+	if msg.Partial {
+		content = bytes.TrimSuffix(content, []byte("\n"))
+	}
+	msg.Line = content
+	return msg, nil
+}
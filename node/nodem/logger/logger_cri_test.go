@@ -0,0 +1,101 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package logger
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestParseCRILogLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		source  string
+		content string
+		partial bool
+		wantErr bool
+	}{
+		{line: "2021-03-01T10:00:00.123456789Z stdout F hello world\n", source: "stdout", content: "hello world\n"},
+		{line: "2021-03-01T10:00:00.123456789+08:00 stderr P part of \n", source: "stderr", content: "part of ", partial: true},
+		{line: "2021-03-01T10:00:00Z stdout F \n", source: "stdout", content: "\n"},
+		{line: "2021-03-01T10:00:00Z stdout F:extra log\n", source: "stdout", content: "log\n"},
+		{line: "2021-03-01 stdout F log\n", wantErr: true},
+		{line: "2021-03-01T10:00:00Z stdin F log\n", wantErr: true},
+		{line: "2021-03-01T10:00:00Z stdout X log\n", wantErr: true},
+		{line: "2021-03-01T10:00:00Z stdout\n", wantErr: true},
+	}
+	for _, tc := range tests {
+		msg, err := parseCRILogLine([]byte(tc.line))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%q: want error %v, got %v", tc.line, tc.wantErr, err)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if msg.Source != tc.source || string(msg.Line) != tc.content || msg.Partial != tc.partial {
+			t.Errorf("%q: unexpected message source=%s line=%q partial=%v", tc.line, msg.Source, msg.Line, msg.Partial)
+		}
+		if msg.Timestamp.IsZero() {
+			t.Errorf("%q: timestamp is not parsed", tc.line)
+		}
+	}
+}
+
+func TestDecodeCRIFunc(t *testing.T) {
+	f, err := ioutil.TempFile("", "cri-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.WriteString("2021-03-01T10:00:00Z stdout F first\ninvalid line\n2021-03-01T10:00:01Z stderr F sec"); err != nil {
+		t.Fatal(err)
+	}
+	rdr, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+
+	decode := decodeCRIFunc(rdr)
+	msg, err := decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(msg.Line) != "first\n" {
+		t.Errorf("want first line, got %q", msg.Line)
+	}
+	// the invalid line is skipped and the incomplete line is not returned
+	if _, err := decode(); err != io.EOF {
+		t.Fatalf("want EOF, got %v", err)
+	}
+	if _, err := f.WriteString("ond\n"); err != nil {
+		t.Fatal(err)
+	}
+	msg, err = decodeCRIFunc(rdr)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Source != "stderr" || string(msg.Line) != "second\n" {
+		t.Errorf("want the rewound second line, got %s %q", msg.Source, msg.Line)
+	}
+}
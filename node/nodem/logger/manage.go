@@ -22,17 +22,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/events"
-	"github.com/docker/docker/api/types/filters"
 	"github.com/gridworkz/kato/cmd/node/option"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 )
 
 //RFC3339NanoFixed time format
@@ -43,18 +40,22 @@ type ContainerLogManage struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
 	conf          *option.Conf
+	runtime       containerruntime.ContainerRuntime
 	cchan         chan ContainerEvent
 	containerLogs sync.Map
+	// the containers whose loggers are being started
+	startings sync.Map
 }
 
 //CreatContainerLogManage 
 func CreatContainerLogManage(conf *option.Conf) *ContainerLogManage {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &ContainerLogManage{
-		ctx:    ctx,
-		cancel: cancel,
-		conf:   conf,
-		cchan:  make(chan ContainerEvent, 100),
+		ctx:     ctx,
+		cancel:  cancel,
+		conf:    conf,
+		runtime: conf.ContainerRuntime,
+		cchan:   make(chan ContainerEvent, 100),
 	}
 }
 
@@ -69,6 +70,9 @@ func (c *ContainerLogManage) Start() error {
 	go c.handleLogger()
 	go c.listAndWatchContainer(errchan)
 	go c.loollist()
+	if c.conf.ContainerRuntimeType == containerruntime.RuntimeContainerd {
+		go c.listAndWatchPodLogs()
+	}
 	logrus.Infof("start container log manage success")
 	return nil
 }
@@ -82,29 +86,17 @@ func (c *ContainerLogManage) Stop() {
 	})
 }
 
-func (c *ContainerLogManage) getContainerLogByFile(info types.ContainerJSON) (*LogFile, error) {
-	return nil, nil
-}
-
-func (c *ContainerLogManage) getContainerLogReader(ctx context.Context, containerID string) (io.ReadCloser, io.ReadCloser, error) {
-	stderr, err := c.conf.DockerCli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
-		ShowStderr: true,
-		Since:      time.Now().Format(RFC3339NanoFixed),
-		Follow:     true,
-	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("Open container stderr output failure,%s", err.Error())
+//getDecodeFunc returns the decoder of the log file format, nil if the format is not supported
+func getDecodeFunc(logFormat string) makeDecoderFunc {
+	switch logFormat {
+	case containerruntime.LogFormatJSONFile, containerruntime.LogFormatSyslog:
+		return decodeFunc
+	case containerruntime.LogFormatCRI:
+		return decodeCRIFunc
 	}
-	stdout, err := c.conf.DockerCli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
-		ShowStdout: true,
-		Since:      time.Now().Format(RFC3339NanoFixed),
-		Follow:     true,
-	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("Open container stdout output failure,%s", err.Error())
-	}
-	return stdout, stderr, nil
+	return nil
 }
+
 func (c *ContainerLogManage) handleLogger() {
 	for {
 		select {
@@ -112,9 +104,8 @@ func (c *ContainerLogManage) handleLogger() {
 			return
 		case cevent := <-c.cchan:
 			switch cevent.Action {
-			case "start":
-				loggerType := cevent.Container.HostConfig.LogConfig.Type
-				if loggerType != "json-file" && loggerType != "syslog" {
+			case containerruntime.ContainerActionStart:
+				if getDecodeFunc(cevent.Container.LogFormat) == nil {
 					continue
 				}
 				if logger, ok := c.containerLogs.Load(cevent.Container.ID); ok {
@@ -124,14 +115,19 @@ func (c *ContainerLogManage) handleLogger() {
 						logrus.Infof("restart copy container log for container %s", cevent.Container.Name)
 					}
 				} else {
+					// the start of a container may be found by both the runtime and its log file
+					if _, starting := c.startings.LoadOrStore(cevent.Container.ID, true); starting {
+						continue
+					}
 					go func() {
+						defer c.startings.Delete(cevent.Container.ID)
 						retry := 0
 						for retry < maxJSONDecodeRetry {
 							retry++
 							var reader *LogFile
 							if cevent.Container.LogPath != "" {
 								var err error
-								reader, err = NewLogFile(cevent.Container.LogPath, 2, false, decodeFunc, 0640, getTailReader)
+								reader, err = NewLogFile(cevent.Container.LogPath, 2, false, getDecodeFunc(cevent.Container.LogFormat), 0640, getTailReader)
 								if err != nil {
 									logrus.Errorf("create logger failure %s", err.Error())
 									time.Sleep(time.Second * 1)
@@ -140,10 +136,12 @@ func (c *ContainerLogManage) handleLogger() {
 							} else {
 								time.Sleep(time.Second * 1)
 								//retry get container inspect info
-								cevent.Container, _ = c.getContainer(cevent.Container.ID)
+								if container, err := c.getContainer(cevent.Container.ID); err == nil {
+									cevent.Container = container
+								}
 								continue
 							}
-							clog := createContainerLog(c.ctx, cevent.Container, reader)
+							clog := createContainerLog(c.ctx, cevent.Container, reader, c.runtime.Name())
 							if err := clog.StartLogging(); err != nil {
 								clog.Stop()
 								if err == ErrNeglectedContainer {
@@ -152,7 +150,9 @@ func (c *ContainerLogManage) handleLogger() {
 								logrus.Errorf("start copy docker log failure %s", err.Error())
 								time.Sleep(time.Second * 1)
 								//retry get container inspect info
-								cevent.Container, _ = c.getContainer(cevent.Container.ID)
+								if container, err := c.getContainer(cevent.Container.ID); err == nil {
+									cevent.Container = container
+								}
 								continue
 							}
 							c.containerLogs.Store(cevent.Container.ID, clog)
@@ -161,7 +161,7 @@ func (c *ContainerLogManage) handleLogger() {
 						}
 					}()
 				}
-			case "die", "destroy":
+			case containerruntime.ContainerActionDie, containerruntime.ContainerActionDestroy:
 				if logger, ok := c.containerLogs.Load(cevent.Container.ID); ok {
 					clog, okf := logger.(*ContainerLog)
 					if okf {
@@ -178,7 +178,7 @@ func (c *ContainerLogManage) handleLogger() {
 //ContainerEvent
 type ContainerEvent struct {
 	Action    string
-	Container types.ContainerJSON
+	Container *containerruntime.Container
 }
 
 func (c *ContainerLogManage) cacheContainer(cs ...ContainerEvent) {
@@ -187,13 +187,13 @@ func (c *ContainerLogManage) cacheContainer(cs ...ContainerEvent) {
 		c.cchan <- container
 	}
 }
-func (c *ContainerLogManage) listContainer() []types.Container {
+func (c *ContainerLogManage) listContainer() []*containerruntime.Container {
 	lictctx, cancel := context.WithTimeout(c.ctx, time.Second*60)
 	defer cancel()
-	containers, err := c.conf.DockerCli.ContainerList(lictctx, types.ContainerListOptions{})
+	containers, err := c.runtime.ListContainers(lictctx)
 	if err != nil {
 		logrus.Errorf("list containers failure.%s", err.Error())
-		containers, _ = c.conf.DockerCli.ContainerList(lictctx, types.ContainerListOptions{})
+		containers, _ = c.runtime.ListContainers(lictctx)
 	}
 	return containers
 }
@@ -207,16 +207,12 @@ func (c *ContainerLogManage) loollist() {
 			return
 		case <-ticker.C:
 			for _, container := range c.listContainer() {
-				cj, _ := c.getContainer(container.ID)
-				if cj.ContainerJSONBase == nil || cj.HostConfig == nil || cj.HostConfig.LogConfig.Type == "" {
-					continue
-				}
-				loggerType := cj.HostConfig.LogConfig.Type
-				if loggerType != "json-file" && loggerType != "syslog" {
+				cj, err := c.getContainer(container.ID)
+				if err != nil || getDecodeFunc(cj.LogFormat) == nil {
 					continue
 				}
 				if _, exist := c.containerLogs.Load(container.ID); !exist {
-					c.cacheContainer(ContainerEvent{Action: "start", Container: cj})
+					c.cacheContainer(ContainerEvent{Action: containerruntime.ContainerActionStart, Container: cj})
 				}
 			}
 		}
@@ -228,13 +224,13 @@ func (c *ContainerLogManage) listAndWatchContainer(errchan chan error) {
 	for _, con := range containers {
 		container, err := c.getContainer(con.ID)
 		if err != nil {
-			if !strings.Contains(err.Error(), "No such container") {
+			if err != containerruntime.ErrContainerNotFound {
 				logrus.Errorf("get container detail info failure %s", err.Error())
 			}
 			// The log path cannot be obtained if the container details cannot be obtained
 			continue
 		}
-		c.cacheContainer(ContainerEvent{Action: "start", Container: container})
+		c.cacheContainer(ContainerEvent{Action: containerruntime.ContainerActionStart, Container: container})
 	}
 	logrus.Info("list containers complete, start watch container")
 	for {
@@ -246,41 +242,51 @@ func (c *ContainerLogManage) listAndWatchContainer(errchan chan error) {
 }
 
 func (c *ContainerLogManage) watchContainer() error {
-	containerFileter := filters.NewArgs()
-	containerFileter.Add("type", "container")
-	eventchan, eventerrchan := c.conf.DockerCli.Events(c.ctx, types.EventsOptions{
-		Filters: containerFileter,
-	})
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	eventchan := make(chan containerruntime.ContainerEvent, 100)
+	eventerrchan := make(chan error, 1)
+	go func() {
+		eventerrchan <- c.runtime.WatchContainers(ctx, eventchan)
+	}()
 	for {
 		select {
 		case <-c.ctx.Done():
 			return nil
 		case err := <-eventerrchan:
 			return err
-		case event, ok := <-eventchan:
-			if !ok {
-				return fmt.Errorf("event chan is closed")
+		case event := <-eventchan:
+			if !checkEventAction(event.Action) {
+				break
 			}
-			if event.Type == events.ContainerEventType && checkEventAction(event.Action) {
-				container, err := c.getContainer(event.ID)
-				if err != nil {
-					if !strings.Contains(err.Error(), "No such container") {
-						logrus.Errorf("get container detail info failure %s", err.Error())
-					}
+			container, err := c.getContainer(event.ContainerID)
+			if err != nil {
+				if err != containerruntime.ErrContainerNotFound {
+					logrus.Errorf("get container detail info failure %s", err.Error())
+				}
+				// the logger of a removed container is stopped by its id
+				if event.Action != containerruntime.ContainerActionDie && event.Action != containerruntime.ContainerActionDestroy {
 					break
 				}
-				c.cacheContainer(ContainerEvent{Action: event.Action, Container: container})
+				container = &containerruntime.Container{ID: event.ContainerID}
 			}
+			c.cacheContainer(ContainerEvent{Action: event.Action, Container: container})
 		}
 	}
 }
-func (c *ContainerLogManage) getContainer(containerID string) (types.ContainerJSON, error) {
+func (c *ContainerLogManage) getContainer(containerID string) (*containerruntime.Container, error) {
 	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
 	defer cancel()
-	return c.conf.DockerCli.ContainerInspect(ctx, containerID)
+	return c.runtime.InspectContainer(ctx, containerID)
 }
 
-var handleAction = []string{"create", "start", "stop", "die", "destroy"}
+var handleAction = []string{
+	containerruntime.ContainerActionCreate,
+	containerruntime.ContainerActionStart,
+	containerruntime.ContainerActionStop,
+	containerruntime.ContainerActionDie,
+	containerruntime.ContainerActionDestroy,
+}
 
 func checkEventAction(action string) bool {
 	for _, enable := range handleAction {
@@ -291,13 +297,14 @@ func checkEventAction(action string) bool {
 	return false
 }
 
-func createContainerLog(ctx context.Context, container types.ContainerJSON, reader *LogFile) *ContainerLog {
+func createContainerLog(ctx context.Context, container *containerruntime.Container, reader *LogFile, daemonName string) *ContainerLog {
 	cctx, cancel := context.WithCancel(ctx)
 	return &ContainerLog{
-		ctx:           cctx,
-		cancel:        cancel,
-		ContainerJSON: container,
-		reader:        reader,
+		ctx:        cctx,
+		cancel:     cancel,
+		Container:  container,
+		reader:     reader,
+		daemonName: daemonName,
 	}
 }
 
@@ -305,12 +312,13 @@ func createContainerLog(ctx context.Context, container types.ContainerJSON, read
 type ContainerLog struct {
 	ctx    context.Context
 	cancel context.CancelFunc
-	types.ContainerJSON
-	LogCopier *Copier
-	LogDriver []Logger
-	reader    *LogFile
-	since     time.Time
-	stoped    *bool
+	*containerruntime.Container
+	LogCopier  *Copier
+	LogDriver  []Logger
+	reader     *LogFile
+	since      time.Time
+	stoped     *bool
+	daemonName string
 }
 
 //StartLogging start copy log
@@ -369,7 +377,7 @@ var ErrNeglectedContainer = fmt.Errorf("Neglected container")
 
 // startLogger starts a new logger driver for the container.
 func (container *ContainerLog) startLogger() ([]Logger, error) {
-	configs := getLoggerConfig(container.Config.Env)
+	configs := getLoggerConfig(container.Env)
 	var loggers []Logger
 	for _, config := range configs {
 		initDriver, err :=
@@ -378,18 +386,17 @@ func (container *ContainerLog) startLogger() ([]Logger, error) {
 			logrus.Warnf("get container log driver failure %s", err.Error())
 			continue
 		}
-		createTime, _ := time.Parse(RFC3339NanoFixed, container.Created)
 		info := Info{
 			Config:              config.Options,
 			ContainerID:         container.ID,
 			ContainerName:       container.Name,
 			ContainerEntrypoint: container.Path,
 			ContainerArgs:       container.Args,
-			ContainerImageName:  container.Config.Image,
-			ContainerCreated:    createTime,
-			ContainerEnv:        container.Config.Env,
-			ContainerLabels:     container.Config.Labels,
-			DaemonName:          "docker",
+			ContainerImageName:  container.Image,
+			ContainerCreated:    container.Created,
+			ContainerEnv:        container.Env,
+			ContainerLabels:     container.Labels,
+			DaemonName:          container.daemonName,
 		}
 		l, err := initDriver(info)
 		if err != nil {
@@ -406,7 +413,7 @@ func (container *ContainerLog) startLogger() ([]Logger, error) {
 
 //Restart
 func (container *ContainerLog) Restart() {
-	if *container.stoped {
+	if container.stoped != nil && *container.stoped {
 		copier := NewCopier(container.reader, container.LogDriver, container.since)
 		container.LogCopier = copier
 		copier.Run()
@@ -19,7 +19,6 @@
 package logger
 
 import (
-	"bufio"
 	"fmt"
 	"testing"
 
@@ -27,6 +26,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/gridworkz/kato/cmd/node/option"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
 )
 
 func TestWatchConatainer(t *testing.T) {
@@ -35,35 +35,12 @@ func TestWatchConatainer(t *testing.T) {
 		t.Fatal(err)
 	}
 	cm := CreatContainerLogManage(&option.Conf{
-		DockerCli: dc,
+		ContainerRuntime: containerruntime.NewDockerRuntimeWithClient(dc),
 	})
 	cm.Start()
 	select {}
 }
 
-func TestGetConatainerLogger(t *testing.T) {
-	dc, err := client.NewEnvClient()
-	if err != nil {
-		t.Fatal(err)
-	}
-	cm := CreatContainerLogManage(&option.Conf{
-		DockerCli: dc,
-	})
-
-	stdout, _, err := cm.getContainerLogReader(cm.ctx, "9874f23cbfc8201571bc654955aad94124f256ccb37e10f914f80865d734a4c5")
-	if err != nil {
-		t.Fatal(err)
-	}
-	buffer := bufio.NewReader(stdout)
-	for {
-		line, _, err := buffer.ReadLine()
-		if err != nil {
-			t.Fatal(err)
-		}
-		fmt.Println(string(line))
-	}
-}
-
 func TestHostConfig(t *testing.T) {
 	cj := new(types.ContainerJSON)
 	if cj.ContainerJSONBase == nil || cj.HostConfig == nil || cj.HostConfig.LogConfig.Type == "" {
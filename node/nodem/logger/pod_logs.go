@@ -0,0 +1,194 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
+)
+
+// the kubelet writes the logs of the containers run by the cri runtime into
+// /var/log/pods/<namespace>_<pod>_<uid>/<container>/<restart count>.log and links them as
+// /var/log/containers/<pod>_<namespace>_<container>-<container id>.log
+var podLogDir = "/var/log/pods"
+var containerLogDir = "/var/log/containers"
+
+// the depth of the log files under podLogDir
+const podLogFileDepth = 3
+
+// the times of looking up the link of a new log file
+const maxPodLogRetry = 10
+
+func (c *ContainerLogManage) listAndWatchPodLogs() {
+	for {
+		if err := c.watchPodLogs(); err != nil {
+			logrus.Errorf("watch pod log files error %s, will retry", err.Error())
+		}
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// watchPodLogs starts logging the container of every new log file under podLogDir,
+// the containers exited before the runtime is listed again are not missed.
+func (c *ContainerLogManage) watchPodLogs() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// the log files existing before are logged by listing the containers
+	if err := c.addPodLogDir(watcher, podLogDir, false); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			return err
+		case event := <-watcher.Events:
+			if event.Op&fsnotify.Create == 0 {
+				continue
+			}
+			info, err := os.Lstat(event.Name)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if err := c.addPodLogDir(watcher, event.Name, true); err != nil {
+					logrus.Warningf("watch pod log dir %s failure %s", event.Name, err.Error())
+				}
+				continue
+			}
+			c.handlePodLogFile(event.Name, info)
+		}
+	}
+}
+
+// addPodLogDir watches the dir and its sub dirs holding log files, the log files found are logged if scan
+func (c *ContainerLogManage) addPodLogDir(watcher *fsnotify.Watcher, dir string, scan bool) error {
+	depth := podLogDepth(dir)
+	if depth < 0 || depth >= podLogFileDepth {
+		return nil
+	}
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+	// the entries created before the dir is watched
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		if info.IsDir() {
+			if err := c.addPodLogDir(watcher, path, scan); err != nil {
+				return err
+			}
+			continue
+		}
+		if scan {
+			c.handlePodLogFile(path, info)
+		}
+	}
+	return nil
+}
+
+func (c *ContainerLogManage) handlePodLogFile(path string, info os.FileInfo) {
+	if !info.Mode().IsRegular() || podLogDepth(path) != podLogFileDepth || filepath.Ext(path) != ".log" {
+		return
+	}
+	go c.logPodLogFile(path)
+}
+
+// logPodLogFile starts logging the container of the log file,
+// the link of the log file is created by the kubelet after the file, so it is retried for a while
+func (c *ContainerLogManage) logPodLogFile(path string) {
+	for retry := 0; retry < maxPodLogRetry; retry++ {
+		containerID, err := containerIDOfLogFile(path)
+		if err != nil {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		if _, exist := c.containerLogs.Load(containerID); exist {
+			return
+		}
+		container, err := c.getContainer(containerID)
+		if err != nil {
+			if err != containerruntime.ErrContainerNotFound {
+				logrus.Errorf("get container detail info failure %s", err.Error())
+			}
+			return
+		}
+		c.cacheContainer(ContainerEvent{Action: containerruntime.ContainerActionStart, Container: container})
+		return
+	}
+	logrus.Warningf("the container of log file %s is not found", path)
+}
+
+// podLogDepth returns the depth of the path under podLogDir, -1 if the path is not under it
+func podLogDepth(path string) int {
+	rel, err := filepath.Rel(podLogDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return -1
+	}
+	if rel == "." {
+		return 0
+	}
+	return len(strings.Split(rel, string(filepath.Separator)))
+}
+
+// containerIDOfLogFile returns the container id from the name of the link in containerLogDir to the log file
+func containerIDOfLogFile(path string) (string, error) {
+	infos, err := ioutil.ReadDir(containerLogDir)
+	if err != nil {
+		return "", err
+	}
+	for _, info := range infos {
+		if info.Mode()&os.ModeSymlink == 0 || filepath.Ext(info.Name()) != ".log" {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(containerLogDir, info.Name()))
+		if err != nil || target != path {
+			continue
+		}
+		name := strings.TrimSuffix(info.Name(), ".log")
+		if i := strings.LastIndex(name, "-"); i >= 0 && i < len(name)-1 {
+			return name[i+1:], nil
+		}
+	}
+	return "", fmt.Errorf("no link to log file %s", path)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package logger
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gridworkz/kato/cmd/node/option"
+	"github.com/gridworkz/kato/node/nodem/containerruntime"
+)
+
+type fakeRuntime struct {
+	containerruntime.ContainerRuntime
+	containers map[string]*containerruntime.Container
+}
+
+func (f *fakeRuntime) InspectContainer(ctx context.Context, containerID string) (*containerruntime.Container, error) {
+	if c, ok := f.containers[containerID]; ok {
+		return c, nil
+	}
+	return nil, containerruntime.ErrContainerNotFound
+}
+
+func TestWatchPodLogs(t *testing.T) {
+	root, err := ioutil.TempDir("", "podlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	podLogDir, containerLogDir = filepath.Join(root, "pods"), filepath.Join(root, "containers")
+	defer func() {
+		podLogDir, containerLogDir = "/var/log/pods", "/var/log/containers"
+	}()
+	for _, dir := range []string{podLogDir, containerLogDir} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logPath := filepath.Join(podLogDir, "default_job-x_uid", "job", "0.log")
+	cm := CreatContainerLogManage(&option.Conf{
+		ContainerRuntime: &fakeRuntime{containers: map[string]*containerruntime.Container{
+			"c1": {ID: "c1", LogPath: logPath, LogFormat: containerruntime.LogFormatCRI},
+		}},
+	})
+	defer cm.cancel()
+	errch := make(chan error, 1)
+	go func() {
+		errch <- cm.watchPodLogs()
+	}()
+	// wait for the pod log dir being watched
+	time.Sleep(100 * time.Millisecond)
+
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(logPath, []byte("2021-03-01T10:00:00Z stdout F done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(logPath, filepath.Join(containerLogDir, "job-x_default_job-c1.log")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-cm.cchan:
+		if event.Action != containerruntime.ContainerActionStart || event.Container.ID != "c1" {
+			t.Fatalf("want start c1, got %s %s", event.Action, event.Container.ID)
+		}
+	case err := <-errch:
+		t.Fatalf("watch pod logs failure %v", err)
+	case <-time.After(time.Second * 10):
+		t.Fatal("timeout waiting for the container of the new log file")
+	}
+}
+
+func TestContainerIDOfLogFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "containerlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	containerLogDir = root
+	defer func() {
+		containerLogDir = "/var/log/containers"
+	}()
+	if err := os.Symlink("/var/log/pods/default_web-0_uid/web/1.log", filepath.Join(root, "web-0_default_web-abc123.log")); err != nil {
+		t.Fatal(err)
+	}
+	id, err := containerIDOfLogFile("/var/log/pods/default_web-0_uid/web/1.log")
+	if err != nil || id != "abc123" {
+		t.Errorf("want abc123, got %s %v", id, err)
+	}
+	if _, err := containerIDOfLogFile("/var/log/pods/default_web-0_uid/web/0.log"); err == nil {
+		t.Error("want error for the log file without link")
+	}
+}
@@ -78,7 +78,7 @@ func NewNodeManager(ctx context.Context, conf *option.Conf) (*NodeManager, error
 		HighThresholdPercent: int(conf.ImageGCHighThresholdPercent),
 		LowThresholdPercent:  int(conf.ImageGCLowThresholdPercent),
 	}
-	imageGCManager, err := gc.NewImageGCManager(conf.DockerCli, imageGCPolicy, sandboxImage)
+	imageGCManager, err := gc.NewImageGCManager(conf.ContainerRuntime, imageGCPolicy, sandboxImage)
 	if err != nil {
 		return nil, fmt.Errorf("create new imageGCManager: %v", err)
 	}
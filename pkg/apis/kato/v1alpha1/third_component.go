@@ -19,13 +19,14 @@
 package v1alpha1
 
 import (
-	"fmt"
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func init() {
@@ -98,10 +99,56 @@ type HealthProbe struct {
 	// +optional
 	HTTPGet *HTTPGetAction `json:"httpGet,omitempty"`
 	// TCPSocket specifies an action involving a TCP port.
-	// TCP hooks not yet supported
-	// TODO: implement a realistic TCP lifecycle hook
 	// +optional
 	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty"`
+	// Number of seconds after which the probe times out.
+	// Defaults to 1 second. Minimum value is 1.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// How often (in seconds) to perform the probe.
+	// Default to 10 seconds. Minimum value is 1.
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// Defaults to 1. Minimum value is 1.
+	// +optional
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// Defaults to 3. Minimum value is 1.
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// GetTimeout returns the timeout of the probe.
+func (h *HealthProbe) GetTimeout() time.Duration {
+	if h.TimeoutSeconds < 1 {
+		return time.Second
+	}
+	return time.Duration(h.TimeoutSeconds) * time.Second
+}
+
+// GetPeriod returns the period of the probe.
+func (h *HealthProbe) GetPeriod() time.Duration {
+	if h.PeriodSeconds < 1 {
+		return 10 * time.Second
+	}
+	return time.Duration(h.PeriodSeconds) * time.Second
+}
+
+// GetSuccessThreshold returns the success threshold of the probe.
+func (h *HealthProbe) GetSuccessThreshold() int32 {
+	if h.SuccessThreshold < 1 {
+		return 1
+	}
+	return h.SuccessThreshold
+}
+
+// GetFailureThreshold returns the failure threshold of the probe.
+func (h *HealthProbe) GetFailureThreshold() int32 {
+	if h.FailureThreshold < 1 {
+		return 3
+	}
+	return h.FailureThreshold
 }
 
 //ComponentPort component port define
@@ -114,16 +161,62 @@ type ComponentPort struct {
 
 //TCPSocketAction enable tcp check
 type TCPSocketAction struct {
+	// Port to connect to, defaults to the port of the endpoint.
+	// +optional
+	Port int `json:"port,omitempty"`
 }
 
+// URIScheme identifies the scheme used for connection to a host for HTTPGetAction
+type URIScheme string
+
+const (
+	// URISchemeHTTP means that the scheme used will be http://
+	URISchemeHTTP URIScheme = "HTTP"
+	// URISchemeHTTPS means that the scheme used will be https://
+	URISchemeHTTPS URIScheme = "HTTPS"
+)
+
 //HTTPGetAction enable http check
 type HTTPGetAction struct {
 	// Path to access on the HTTP server.
 	// +optional
 	Path string `json:"path,omitempty"`
+	// Port to access on the HTTP server, defaults to the port of the endpoint.
+	// +optional
+	Port int `json:"port,omitempty"`
+	// Scheme to use for connecting to the host, HTTP or HTTPS.
+	// Defaults to HTTP.
+	// +optional
+	Scheme URIScheme `json:"scheme,omitempty"`
+	// InsecureSkipVerify skips the verification of the server certificate if the scheme is HTTPS.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 	// Custom headers to set in the request. HTTP allows repeated headers.
 	// +optional
 	HTTPHeaders []HTTPHeader `json:"httpHeaders,omitempty"`
+	// The ranges of the status codes which are considered successful.
+	// Defaults to 200-399.
+	// +optional
+	ExpectedStatuses []HTTPStatusRange `json:"expectedStatuses,omitempty"`
+}
+
+// HTTPStatusRange is a range of HTTP status codes, both ends are inclusive.
+type HTTPStatusRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// IsExpectedStatus checks if the status code is expected.
+func (h *HTTPGetAction) IsExpectedStatus(code int) bool {
+	if len(h.ExpectedStatuses) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, r := range h.ExpectedStatuses {
+		if code >= r.Start && code <= r.End {
+			return true
+		}
+	}
+	return false
 }
 
 // HTTPHeader describes a custom header to be used in HTTP probes
@@ -162,40 +255,92 @@ const (
 	EndpointNotReady EndpointStatus = "NotReady"
 )
 
+// EndpointAddress is the address of an endpoint, in the form of host:port.
+// The host can be an IPv4 address, an IPv6 address enclosed in brackets or a domain name,
+// such as 10.0.0.1:8080, [2001:db8::1]:8080 or db.internal.example:5432
 type EndpointAddress string
 
+// GetHost returns the host of the address, the IPv6 address is returned without brackets.
+func (e EndpointAddress) GetHost() string {
+	host, _, err := net.SplitHostPort(string(e))
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// GetIP returns the IP of the address, or empty if the host of the address is a domain name.
 func (e EndpointAddress) GetIP() string {
-	info := strings.Split(string(e), ":")
-	if len(info) == 2 {
-		return info[0]
+	host := e.GetHost()
+	if net.ParseIP(host) == nil {
+		return ""
 	}
-	return ""
+	return host
+}
+
+// IsDomain checks if the host of the address is a domain name.
+func (e EndpointAddress) IsDomain() bool {
+	host := e.GetHost()
+	return host != "" && net.ParseIP(host) == nil
 }
 
 func (e EndpointAddress) GetPort() int {
-	info := strings.Split(string(e), ":")
-	if len(info) == 2 {
-		port, _ := strconv.Atoi(info[1])
-		return port
+	_, port, err := net.SplitHostPort(string(e))
+	if err != nil {
+		return 0
 	}
-	return 0
+	p, _ := strconv.Atoi(port)
+	return p
 }
 
+// NewEndpointAddress creates a new endpoint address with the host and port.
+// The host must be an IP address or a domain name, otherwise nil is returned.
 func NewEndpointAddress(host string, port int) *EndpointAddress {
-	if net.ParseIP(host) == nil {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if net.ParseIP(host) == nil && len(validation.IsDNS1123Subdomain(strings.ToLower(host))) > 0 {
 		return nil
 	}
-	if port < 0 || port > 65533 {
+	if port <= 0 || port > 65535 {
 		return nil
 	}
-	ea := EndpointAddress(fmt.Sprintf("%s:%d", host, port))
+	ea := EndpointAddress(net.JoinHostPort(host, strconv.Itoa(port)))
 	return &ea
 }
 
+// GetEndpointAddress parses the address of the endpoint, the scheme and path of the address are ignored.
+// The port defaults to 80 for http and 443 for https if not specified.
+func (t *ThirdComponentEndpoint) GetEndpointAddress() *EndpointAddress {
+	address := t.Address
+	defaultPort := 0
+	if strings.HasPrefix(address, "http://") {
+		address, defaultPort = strings.TrimPrefix(address, "http://"), 80
+	} else if strings.HasPrefix(address, "https://") {
+		address, defaultPort = strings.TrimPrefix(address, "https://"), 443
+	}
+	if idx := strings.Index(address, "/"); idx >= 0 {
+		address = address[:idx]
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		if defaultPort == 0 {
+			return nil
+		}
+		return NewEndpointAddress(address, defaultPort)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return nil
+	}
+	return NewEndpointAddress(host, p)
+}
+
 //ThirdComponentEndpointStatus endpoint status
 type ThirdComponentEndpointStatus struct {
 	// The address including the port number.
 	Address EndpointAddress `json:"address"`
+	// Hostname the domain name which the address is resolved from.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
 	// Reference to object providing the endpoint.
 	// +optional
 	TargetRef *v1.ObjectReference `json:"targetRef,omitempty" protobuf:"bytes,2,opt,name=targetRef"`
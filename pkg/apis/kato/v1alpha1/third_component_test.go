@@ -0,0 +1,62 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package v1alpha1
+
+import "testing"
+
+func TestThirdComponentEndpointGetEndpointAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		want     EndpointAddress
+		ip       string
+		host     string
+		port     int
+		isDomain bool
+	}{
+		{address: "10.0.0.1:8080", want: "10.0.0.1:8080", ip: "10.0.0.1", host: "10.0.0.1", port: 8080},
+		{address: "[2001:db8::1]:8080", want: "[2001:db8::1]:8080", ip: "2001:db8::1", host: "2001:db8::1", port: 8080},
+		{address: "db.internal.example:5432", want: "db.internal.example:5432", host: "db.internal.example", port: 5432, isDomain: true},
+		{address: "https://www.example.com/api", want: "www.example.com:443", host: "www.example.com", port: 443, isDomain: true},
+		{address: "http://[2001:db8::1]:81/", want: "[2001:db8::1]:81", ip: "2001:db8::1", host: "2001:db8::1", port: 81},
+		{address: "10.0.0.1"},
+		{address: "2001:db8::1:8080"},
+		{address: "db_internal.example:5432"},
+		{address: "10.0.0.1:65536"},
+	}
+	for _, tc := range tests {
+		ep := &ThirdComponentEndpoint{Address: tc.address}
+		got := ep.GetEndpointAddress()
+		if tc.want == "" {
+			if got != nil {
+				t.Errorf("%s: want nil, but got %s", tc.address, *got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: want %s, but got nil", tc.address, tc.want)
+			continue
+		}
+		if *got != tc.want {
+			t.Errorf("%s: want %s, but got %s", tc.address, tc.want, *got)
+		}
+		if got.GetIP() != tc.ip || got.GetHost() != tc.host || got.GetPort() != tc.port || got.IsDomain() != tc.isDomain {
+			t.Errorf("%s: unexpected ip %s, host %s, port %d, domain %v", tc.address, got.GetIP(), got.GetHost(), got.GetPort(), got.IsDomain())
+		}
+	}
+}
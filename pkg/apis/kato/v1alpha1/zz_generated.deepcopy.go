@@ -1,12 +1,13 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 // KATO, Application Management Platform
 // Copyright (C) 2021 Gridworkz Co., Ltd.
 
-// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this
 // software and associated documentation files (the "Software"), to deal in the Software
 // without restriction, including without limitation the rights to use, copy, modify, merge,
-// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons
 // to whom the Software is furnished to do so, subject to the following conditions:
 
 // The above copyright notice and this permission notice shall be included in all copies or
@@ -169,6 +170,11 @@ func (in *HTTPGetAction) DeepCopyInto(out *HTTPGetAction) {
 		*out = make([]HTTPHeader, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExpectedStatuses != nil {
+		in, out := &in.ExpectedStatuses, &out.ExpectedStatuses
+		*out = make([]HTTPStatusRange, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetAction.
@@ -196,6 +202,21 @@ func (in *HTTPHeader) DeepCopy() *HTTPHeader {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPStatusRange) DeepCopyInto(out *HTTPStatusRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPStatusRange.
+func (in *HTTPStatusRange) DeepCopy() *HTTPStatusRange {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPStatusRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthProbe) DeepCopyInto(out *HealthProbe) {
 	*out = *in
@@ -0,0 +1,184 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package consul is a minimal client of the consul HTTP API, which only
+// supports the health and catalog endpoints required by the third-party service discovery.
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServiceEntry is an entry of /v1/health/service/:service
+type ServiceEntry struct {
+	Node    Node           `json:"Node"`
+	Service AgentService   `json:"Service"`
+	Checks  []*HealthCheck `json:"Checks"`
+}
+
+// Node -
+type Node struct {
+	ID      string `json:"ID"`
+	Node    string `json:"Node"`
+	Address string `json:"Address"`
+}
+
+// AgentService -
+type AgentService struct {
+	ID      string   `json:"ID"`
+	Service string   `json:"Service"`
+	Tags    []string `json:"Tags"`
+	Address string   `json:"Address"`
+	Port    int      `json:"Port"`
+}
+
+// HealthCheck -
+type HealthCheck struct {
+	CheckID string `json:"CheckID"`
+	Status  string `json:"Status"`
+}
+
+// HealthPassing is the status of a passing health check.
+const HealthPassing = "passing"
+
+// Address returns the address of the service instance, which falls back to the address of the node.
+func (s *ServiceEntry) Address() string {
+	if s.Service.Address != "" {
+		return s.Service.Address
+	}
+	return s.Node.Address
+}
+
+// Passing checks if all the health checks of the service instance are passing.
+func (s *ServiceEntry) Passing() bool {
+	for _, check := range s.Checks {
+		if check.Status != HealthPassing {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryOptions -
+type QueryOptions struct {
+	// Tag filters the service instances by tag.
+	Tag string
+	// Datacenter defaults to the datacenter of the agent.
+	Datacenter string
+	// WaitIndex and WaitTime are used to perform a blocking query,
+	// which returns when the index of the result is greater than WaitIndex or after WaitTime.
+	WaitIndex uint64
+	WaitTime  time.Duration
+}
+
+// Client -
+type Client struct {
+	servers []string
+	token   string
+	client  *http.Client
+}
+
+// NewClient creates a new consul client. servers are the addresses of the consul agents,
+// such as http://127.0.0.1:8500, the scheme is http if not specified.
+func NewClient(servers []string, token string) (*Client, error) {
+	var addrs []string
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		if !strings.HasPrefix(server, "http://") && !strings.HasPrefix(server, "https://") {
+			server = "http://" + server
+		}
+		addrs = append(addrs, strings.TrimSuffix(server, "/"))
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no consul server is specified")
+	}
+	return &Client{
+		servers: addrs,
+		token:   token,
+		client:  &http.Client{},
+	}, nil
+}
+
+// HealthService returns the instances of the service and the index of the result.
+func (c *Client) HealthService(ctx context.Context, service string, opts *QueryOptions) ([]*ServiceEntry, uint64, error) {
+	if opts == nil {
+		opts = &QueryOptions{}
+	}
+	query := url.Values{}
+	if opts.Tag != "" {
+		query.Set("tag", opts.Tag)
+	}
+	if opts.Datacenter != "" {
+		query.Set("dc", opts.Datacenter)
+	}
+	if opts.WaitIndex > 0 {
+		query.Set("index", strconv.FormatUint(opts.WaitIndex, 10))
+		if opts.WaitTime > 0 {
+			query.Set("wait", fmt.Sprintf("%ds", int(opts.WaitTime.Seconds())))
+		}
+	}
+	path := "/v1/health/service/" + url.PathEscape(service) + "?" + query.Encode()
+
+	var lastErr error
+	for _, server := range c.servers {
+		entries, index, err := c.get(ctx, server+path)
+		if err == nil {
+			return entries, index, nil
+		}
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		lastErr = err
+	}
+	return nil, 0, lastErr
+}
+
+func (c *Client) get(ctx context.Context, u string) ([]*ServiceEntry, uint64, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req = req.WithContext(ctx)
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, u)
+	}
+	var entries []*ServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, 0, fmt.Errorf("decode response: %v", err)
+	}
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	return entries, index, nil
+}
@@ -0,0 +1,260 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package cron parses standard five-field cron expressions
+// (minute hour day-of-month month day-of-week) and computes their activation times.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are used to implement the cron rule that, when both
+	// day-of-month and day-of-week are restricted, a day matching either of them matches.
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	dom     = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dow = bounds{0, 6, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a five-field cron expression, such as '0 8 * * 1-5', or one of
+// the descriptors @yearly, @monthly, @weekly, @daily and @hourly.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected exactly 5 fields, found %d: %s", len(fields), spec)
+	}
+
+	var err error
+	s := &Schedule{}
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, fmt.Errorf("minute: %v", err)
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, fmt.Errorf("hour: %v", err)
+	}
+	if s.dom, err = parseField(fields[2], dom); err != nil {
+		return nil, fmt.Errorf("day of month: %v", err)
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, fmt.Errorf("month: %v", err)
+	}
+	// 7 is an alias of sunday
+	dowField := fields[4]
+	if s.dow, err = parseField(dowField, bounds{0, 7, dow.names}); err != nil {
+		return nil, fmt.Errorf("day of week: %v", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = dowField == "*" || dowField == "?"
+
+	return s, nil
+}
+
+// parseField parses a comma-separated list of ranges into a bit set.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := parseRange(expr, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= bit
+	}
+	return bits, nil
+}
+
+// parseRange parses expressions like '*', '5', '1-5', '*/15' or '0-30/10'.
+func parseRange(expr string, b bounds) (uint64, error) {
+	var start, end, step uint = 0, 0, 1
+	rangeAndStep := strings.Split(expr, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("too many slashes: %s", expr)
+	}
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	if len(lowAndHigh) > 2 {
+		return 0, fmt.Errorf("too many hyphens: %s", expr)
+	}
+
+	var err error
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		if len(lowAndHigh) > 1 {
+			return 0, fmt.Errorf("invalid range: %s", expr)
+		}
+		start, end = b.min, b.max
+	} else {
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+		end = start
+		if len(lowAndHigh) == 2 {
+			if end, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if len(rangeAndStep) == 2 {
+		if step, err = parseUint(rangeAndStep[1]); err != nil {
+			return 0, err
+		}
+		if step == 0 {
+			return 0, fmt.Errorf("step of range should be a positive number: %s", expr)
+		}
+		// 'N/step' means 'N-max/step'
+		if len(lowAndHigh) == 1 && lowAndHigh[0] != "*" && lowAndHigh[0] != "?" {
+			end = b.max
+		}
+	}
+
+	if start < b.min || end > b.max {
+		return 0, fmt.Errorf("%s is beyond range (%d-%d)", expr, b.min, b.max)
+	}
+	if start > end {
+		return 0, fmt.Errorf("beginning of range(%d) beyond end of range(%d): %s", start, end, expr)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (uint, error) {
+	if b.names != nil {
+		if v, ok := b.names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+	return parseUint(s)
+}
+
+func parseUint(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse '%s': %v", s, err)
+	}
+	return uint(v), nil
+}
+
+// searchLimit is how far Next and Prev look for an activation time, to make sure
+// that impossible schedules(e.g. '0 0 30 2 *') do not loop forever.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Next returns the next activation time later than t, or the zero time if there is none.
+func (s *Schedule) Next(t time.Time) time.Time {
+	limit := t.Add(searchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		if !matched(s.month, uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !matched(s.hour, uint(t.Hour())) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !matched(s.minute, uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Prev returns the latest activation time not later than t, or the zero time if there is none.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	limit := t.Add(-searchLimit)
+	t = t.Truncate(time.Minute)
+	for t.After(limit) {
+		if !matched(s.month, uint(t.Month())) {
+			// the last minute of the previous month
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !matched(s.hour, uint(t.Hour())) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !matched(s.minute, uint(t.Minute())) {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := matched(s.dom, uint(t.Day()))
+	dowMatch := matched(s.dow, uint(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func matched(bits uint64, v uint) bool {
+	return bits&(1<<v) != 0
+}
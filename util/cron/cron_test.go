@@ -0,0 +1,99 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "0 8 * * 1-5"},
+		{spec: "*/15 0-6,20-23 * jan-jun mon,fri"},
+		{spec: "0 0 1 * 7"},
+		{spec: "@daily"},
+		{spec: "0 8 * *", wantErr: true},
+		{spec: "60 8 * * *", wantErr: true},
+		{spec: "0 8-6 * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "0 8 * * foo", wantErr: true},
+	}
+	for _, tc := range tests {
+		_, err := Parse(tc.spec)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("spec %s: want error: %v, but got %v", tc.spec, tc.wantErr, err)
+		}
+	}
+}
+
+func TestNextAndPrev(t *testing.T) {
+	// Saturday
+	now := time.Date(2021, 5, 15, 10, 30, 20, 0, time.UTC)
+	tests := []struct {
+		spec string
+		next time.Time
+		prev time.Time
+	}{
+		{
+			spec: "0 8 * * 1-5",
+			next: time.Date(2021, 5, 17, 8, 0, 0, 0, time.UTC),
+			prev: time.Date(2021, 5, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "*/15 * * * *",
+			next: time.Date(2021, 5, 15, 10, 45, 0, 0, time.UTC),
+			prev: time.Date(2021, 5, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 1 * *",
+			next: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+			prev: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "30 2 29 2 *",
+			next: time.Date(2024, 2, 29, 2, 30, 0, 0, time.UTC),
+			prev: time.Date(2020, 2, 29, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			// day of month or day of week
+			spec: "0 12 1 * 0",
+			next: time.Date(2021, 5, 16, 12, 0, 0, 0, time.UTC),
+			prev: time.Date(2021, 5, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			spec: "0 0 30 2 *",
+		},
+	}
+	for _, tc := range tests {
+		s, err := Parse(tc.spec)
+		if err != nil {
+			t.Fatalf("spec %s: %v", tc.spec, err)
+		}
+		if next := s.Next(now); !next.Equal(tc.next) {
+			t.Errorf("spec %s: want next %s, but got %s", tc.spec, tc.next, next)
+		}
+		if prev := s.Prev(now); !prev.Equal(tc.prev) {
+			t.Errorf("spec %s: want prev %s, but got %s", tc.spec, tc.prev, prev)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package dns resolves endpoints from DNS SRV or A/AAAA records.
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint -
+type Endpoint struct {
+	IP   string
+	Port int
+	// Target the host name that the endpoint is resolved from
+	Target string
+}
+
+// Address returns the address of the endpoint, with the IPv6 address enclosed in brackets.
+func (e Endpoint) Address() string {
+	return net.JoinHostPort(e.IP, strconv.Itoa(e.Port))
+}
+
+// NewResolver creates a resolver which sends queries to the given DNS servers.
+// The system resolver is used if servers is empty.
+func NewResolver(servers []string) *net.Resolver {
+	var addrs []string
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+		}
+		addrs = append(addrs, server)
+	}
+	if len(addrs) == 0 {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			var lastErr error
+			for _, addr := range addrs {
+				conn, err := d.DialContext(ctx, network, addr)
+				if err == nil {
+					return conn, nil
+				}
+				lastErr = err
+			}
+			return nil, lastErr
+		},
+	}
+}
+
+// IsSRV checks if the name is the name of SRV records, such as _http._tcp.example.com
+func IsSRV(name string) bool {
+	return strings.HasPrefix(name, "_")
+}
+
+// Lookup resolves the endpoints of the name.
+// The name is either the name of SRV records, such as _http._tcp.example.com,
+// or a host name with port, such as db.example.com:5432.
+func Lookup(ctx context.Context, resolver *net.Resolver, name string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	if IsSRV(name) {
+		_, srvs, err := resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup SRV records of %s: %v", name, err)
+		}
+		for _, srv := range srvs {
+			target := strings.TrimSuffix(srv.Target, ".")
+			ips, err := lookupIP(ctx, resolver, target)
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range ips {
+				endpoints = append(endpoints, Endpoint{IP: ip, Port: int(srv.Port), Target: target})
+			}
+		}
+	} else {
+		host, portStr, err := net.SplitHostPort(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither the name of SRV records nor a host name with port: %v", name, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port: %s", portStr)
+		}
+		ips, err := lookupIP(ctx, resolver, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			endpoints = append(endpoints, Endpoint{IP: ip, Port: port, Target: host})
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Address() < endpoints[j].Address()
+	})
+	return endpoints, nil
+}
+
+func lookupIP(ctx context.Context, resolver *net.Resolver, host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}, nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("lookup ip addresses of %s: %v", host, err)
+	}
+	var ips []string
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP.String())
+	}
+	return ips, nil
+}
@@ -0,0 +1,87 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package dns
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gridworkz/kato/util/dns/dnstest"
+)
+
+func TestLookup(t *testing.T) {
+	server, err := dnstest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.SetIPs("db.internal.example", "10.0.0.2", "10.0.0.1", "2001:db8::1")
+	server.SetIPs("node1.example", "10.0.1.1")
+	server.SetIPs("node2.example", "10.0.1.2")
+	server.SetSRVs("_http._tcp.orders.example",
+		dnstest.SRV{Target: "node1.example", Port: 8080},
+		dnstest.SRV{Target: "node2.example", Port: 8081},
+	)
+
+	resolver := NewResolver([]string{server.Addr})
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "db.internal.example:5432",
+			want: []string{"10.0.0.1:5432", "10.0.0.2:5432", "[2001:db8::1]:5432"},
+		},
+		{
+			name: "_http._tcp.orders.example",
+			want: []string{"10.0.1.1:8080", "10.0.1.2:8081"},
+		},
+		{
+			name: "127.0.0.1:80",
+			want: []string{"127.0.0.1:80"},
+		},
+		{
+			name:    "db.internal.example",
+			wantErr: true,
+		},
+		{
+			name:    "notfound.example:80",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		endpoints, err := Lookup(ctx, resolver, tc.name)
+		cancel()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: want error: %v, but got %v", tc.name, tc.wantErr, err)
+			continue
+		}
+		var got []string
+		for _, ep := range endpoints {
+			got = append(got, ep.Address())
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: want %v, but got %v", tc.name, tc.want, got)
+		}
+	}
+}
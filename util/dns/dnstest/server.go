@@ -0,0 +1,183 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package dnstest provides an in-process DNS server for testing.
+package dnstest
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// SRV -
+type SRV struct {
+	Target   string
+	Port     uint16
+	Priority uint16
+	Weight   uint16
+}
+
+// Server is a DNS server listening on a UDP port of the loopback interface,
+// which answers the A, AAAA and SRV queries with the configured records.
+type Server struct {
+	Addr string
+
+	conn net.PacketConn
+	lock sync.RWMutex
+	ips  map[string][]net.IP
+	srvs map[string][]SRV
+}
+
+// NewServer starts a new DNS server.
+func NewServer() (*Server, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr: conn.LocalAddr().String(),
+		conn: conn,
+		ips:  make(map[string][]net.IP),
+		srvs: make(map[string][]SRV),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// SetIPs sets the A and AAAA records of the name.
+func (s *Server) SetIPs(name string, ips ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var res []net.IP
+	for _, ip := range ips {
+		res = append(res, net.ParseIP(ip))
+	}
+	s.ips[canonical(name)] = res
+}
+
+// SetSRVs sets the SRV records of the name.
+func (s *Server) SetSRVs(name string, srvs ...SRV) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.srvs[canonical(name)] = srvs
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	return s.conn.Close()
+}
+
+func canonical(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+func (s *Server) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		resp, err := s.answer(buf[:n])
+		if err != nil {
+			continue
+		}
+		s.conn.WriteTo(resp, addr)
+	}
+}
+
+func (s *Server) answer(req []byte) ([]byte, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(req)
+	if err != nil {
+		return nil, err
+	}
+	question, err := p.Question()
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	name := canonical(question.Name.String())
+	ips, hasIPs := s.ips[name]
+	srvs, hasSRVs := s.srvs[name]
+
+	rcode := dnsmessage.RCodeSuccess
+	if !hasIPs && !hasSRVs {
+		rcode = dnsmessage.RCodeNameError
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:            header.ID,
+		Response:      true,
+		Authoritative: true,
+		RCode:         rcode,
+	})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(question); err != nil {
+		return nil, err
+	}
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	rh := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 5}
+	switch question.Type {
+	case dnsmessage.TypeA:
+		for _, ip := range ips {
+			if ip4 := ip.To4(); ip4 != nil {
+				r := dnsmessage.AResource{}
+				copy(r.A[:], ip4)
+				if err := b.AResource(rh, r); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case dnsmessage.TypeAAAA:
+		for _, ip := range ips {
+			if ip.To4() == nil {
+				r := dnsmessage.AAAAResource{}
+				copy(r.AAAA[:], ip.To16())
+				if err := b.AAAAResource(rh, r); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case dnsmessage.TypeSRV:
+		for _, srv := range srvs {
+			target, err := dnsmessage.NewName(canonical(srv.Target))
+			if err != nil {
+				return nil, err
+			}
+			r := dnsmessage.SRVResource{Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: target}
+			if err := b.SRVResource(rh, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Finish()
+}
@@ -151,7 +151,7 @@ func (s *startController) startOne(app v1.AppService) error {
 	if ingresses := app.GetIngress(true); ingresses != nil {
 		for _, ingress := range ingresses {
 			if len(ingress.ResourceVersion) == 0 {
-				_, err := s.manager.client.ExtensionsV1beta1().Ingresses(app.TenantID).Create(ingress)
+				_, err := s.manager.client.NetworkingV1().Ingresses(app.TenantID).Create(ingress)
 				if err != nil && !errors.IsAlreadyExists(err) {
 					return fmt.Errorf("create ingress failure:%s", err.Error())
 				}
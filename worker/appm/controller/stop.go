@@ -94,7 +94,7 @@ func (s *stopController) stopOne(app v1.AppService) error {
 	if ingresses := app.GetIngress(true); ingresses != nil {
 		for _, ingress := range ingresses {
 			if ingress != nil && ingress.Name != "" {
-				err := s.manager.client.ExtensionsV1beta1().Ingresses(app.TenantID).Delete(ingress.Name, &metav1.DeleteOptions{
+				err := s.manager.client.NetworkingV1().Ingresses(app.TenantID).Delete(ingress.Name, &metav1.DeleteOptions{
 					GracePeriodSeconds: &zero,
 				})
 				if err != nil && !errors.IsNotFound(err) {
@@ -20,6 +20,7 @@ package conversion
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/gridworkz/kato/db/model"
 	"github.com/gridworkz/kato/util"
 	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	workerutil "github.com/gridworkz/kato/worker/util"
 )
 
 var str2ResourceName = map[string]corev1.ResourceName{
@@ -58,6 +60,17 @@ func newHPAs(as *v1.AppService, dbmanager db.Manager) ([]*autoscalingv2.Horizont
 		return nil, err
 	}
 
+	// the active scheduled scaling policies adjust the min and max replicas of the HPAs,
+	// so that the HPAs do not fight against them.
+	policies, err := dbmanager.TenantServiceScheduledScalingPolicyDao().ListByServiceID(as.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := workerutil.ActiveScalingBounds(policies, time.Now())
+	if err != nil {
+		logrus.Warningf("service id: %s; active scaling bounds: %v", as.ServiceID, err)
+	}
+
 	var hpas []*autoscalingv2.HorizontalPodAutoscaler
 	for _, rule := range xpaRules {
 		metrics, err := dbmanager.TenantServceAutoscalerRuleMetricsDao().ListByRuleID(rule.RuleID)
@@ -78,6 +91,10 @@ func newHPAs(as *v1.AppService, dbmanager db.Manager) ([]*autoscalingv2.Horizont
 		})
 
 		hpa := newHPA(as.TenantID, kind, name, labels, rule, metrics)
+		if hpa == nil {
+			continue
+		}
+		ApplyScalingBoundsToHPA(hpa, rule, bounds)
 
 		hpas = append(hpas, hpa)
 	}
@@ -85,6 +102,23 @@ func newHPAs(as *v1.AppService, dbmanager db.Manager) ([]*autoscalingv2.Horizont
 	return hpas, nil
 }
 
+// ApplyScalingBoundsToHPA limits the min and max replicas of the rule to the given bounds.
+// The min and max replicas of the rule will be restored if bounds is nil.
+// The max replicas is never less than the min replicas after clamping, which is at least 1.
+func ApplyScalingBoundsToHPA(hpa *autoscalingv2.HorizontalPodAutoscaler, rule *model.TenantServiceAutoscalerRules, bounds *workerutil.ScalingBounds) {
+	minReplicas := bounds.Clamp(rule.MinReplicas)
+	if minReplicas < 1 {
+		// the min replicas of HPA can not be less than 1
+		minReplicas = 1
+	}
+	maxReplicas := bounds.Clamp(rule.MaxReplicas)
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+	hpa.Spec.MinReplicas = util.Int32(int32(minReplicas))
+	hpa.Spec.MaxReplicas = int32(maxReplicas)
+}
+
 func createResourceMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) autoscalingv2.MetricSpec {
 	ms := autoscalingv2.MetricSpec{
 		Type: autoscalingv2.ResourceMetricSourceType,
@@ -93,14 +127,14 @@ func createResourceMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) aut
 		},
 	}
 
-	if metric.MetricTargetType == "utilization" {
+	if metric.MetricTargetType == model.UtilizationMetricTargetType {
 		value := int32(metric.MetricTargetValue)
 		ms.Resource.Target = autoscalingv2.MetricTarget{
 			Type:               autoscalingv2.UtilizationMetricType,
 			AverageUtilization: &value,
 		}
 	}
-	if metric.MetricTargetType == "average_value" {
+	if metric.MetricTargetType == model.AverageValueMetricTargetType {
 		ms.Resource.Target.Type = autoscalingv2.AverageValueMetricType
 		if metric.MetricsName == "cpu" {
 			ms.Resource.Target.AverageValue = resource.NewMilliQuantity(int64(metric.MetricTargetValue), resource.DecimalSI)
@@ -113,6 +147,102 @@ func createResourceMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) aut
 	return ms
 }
 
+func createPodsMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) (autoscalingv2.MetricSpec, error) {
+	identifier, err := createMetricIdentifier(metric)
+	if err != nil {
+		return autoscalingv2.MetricSpec{}, err
+	}
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: identifier,
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(int64(metric.MetricTargetValue), resource.DecimalSI),
+			},
+		},
+	}, nil
+}
+
+func createObjectMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) (autoscalingv2.MetricSpec, error) {
+	if metric.DescribedKind == "" || metric.DescribedName == "" {
+		return autoscalingv2.MetricSpec{}, fmt.Errorf("described object of metric %s is required", metric.MetricsName)
+	}
+	identifier, err := createMetricIdentifier(metric)
+	if err != nil {
+		return autoscalingv2.MetricSpec{}, err
+	}
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ObjectMetricSourceType,
+		Object: &autoscalingv2.ObjectMetricSource{
+			DescribedObject: autoscalingv2.CrossVersionObjectReference{
+				Kind:       metric.DescribedKind,
+				Name:       metric.DescribedName,
+				APIVersion: metric.DescribedAPIVersion,
+			},
+			Metric: identifier,
+			Target: createValueMetricTarget(metric),
+		},
+	}, nil
+}
+
+func createExternalMetrics(metric *model.TenantServiceAutoscalerRuleMetrics) (autoscalingv2.MetricSpec, error) {
+	identifier, err := createMetricIdentifier(metric)
+	if err != nil {
+		return autoscalingv2.MetricSpec{}, err
+	}
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: identifier,
+			Target: createValueMetricTarget(metric),
+		},
+	}, nil
+}
+
+func createMetricIdentifier(metric *model.TenantServiceAutoscalerRuleMetrics) (autoscalingv2.MetricIdentifier, error) {
+	identifier := autoscalingv2.MetricIdentifier{
+		Name: metric.MetricsName,
+	}
+	if metric.MetricSelector != "" {
+		selector, err := metav1.ParseToLabelSelector(metric.MetricSelector)
+		if err != nil {
+			return identifier, fmt.Errorf("parse metric selector '%s': %v", metric.MetricSelector, err)
+		}
+		identifier.Selector = selector
+	}
+	return identifier, nil
+}
+
+// createValueMetricTarget creates target for object and external metrics, which can be either value or average_value.
+func createValueMetricTarget(metric *model.TenantServiceAutoscalerRuleMetrics) autoscalingv2.MetricTarget {
+	value := resource.NewQuantity(int64(metric.MetricTargetValue), resource.DecimalSI)
+	if metric.MetricTargetType == model.AverageValueMetricTargetType {
+		return autoscalingv2.MetricTarget{
+			Type:         autoscalingv2.AverageValueMetricType,
+			AverageValue: value,
+		}
+	}
+	return autoscalingv2.MetricTarget{
+		Type:  autoscalingv2.ValueMetricType,
+		Value: value,
+	}
+}
+
+func createMetricSpec(metric *model.TenantServiceAutoscalerRuleMetrics) (autoscalingv2.MetricSpec, error) {
+	switch metric.MetricsType {
+	case model.ResourceMetricsType:
+		return createResourceMetrics(metric), nil
+	case model.PodsMetricsType:
+		return createPodsMetrics(metric)
+	case model.ObjectMetricsType:
+		return createObjectMetrics(metric)
+	case model.ExternalMetricsType:
+		return createExternalMetrics(metric)
+	}
+	return autoscalingv2.MetricSpec{}, fmt.Errorf("unsupported metric type: %s", metric.MetricsType)
+}
+
 func newHPA(namespace, kind, name string, labels map[string]string, rule *model.TenantServiceAutoscalerRules, metrics []*model.TenantServiceAutoscalerRuleMetrics) *autoscalingv2.HorizontalPodAutoscaler {
 	hpa := &autoscalingv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
@@ -133,17 +263,17 @@ func newHPA(namespace, kind, name string, labels map[string]string, rule *model.
 	}
 
 	for _, metric := range metrics {
-		if metric.MetricsType != "resource_metrics" {
-			logrus.Warningf("rule id:  %s; unsupported metric type: %s", rule.RuleID, metric.MetricsType)
+		if metric.MetricsType == model.ResourceMetricsType && metric.MetricTargetValue <= 0 {
+			// If the target value of cpu and memory is 0, it will not take effect.
+			// The target value of the custom metrics can be 0.
 			continue
 		}
-		if metric.MetricTargetValue <= 0 {
-			// TODO: If the target value of cpu and memory is 0, it will not take effect.
-			// TODO: The target value of the custom indicator can be 0.
+
+		ms, err := createMetricSpec(metric)
+		if err != nil {
+			logrus.Warningf("rule id: %s; create metric spec: %v", rule.RuleID, err)
 			continue
 		}
-
-		ms := createResourceMetrics(metric)
 		spec.Metrics = append(spec.Metrics, ms)
 	}
 	if len(spec.Metrics) == 0 {
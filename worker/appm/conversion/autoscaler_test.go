@@ -3,8 +3,11 @@ package conversion
 import (
 	"testing"
 
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
+
 	"github.com/gridworkz/kato/db/model"
 	k8sutil "github.com/gridworkz/kato/util/k8s"
+	workerutil "github.com/gridworkz/kato/worker/util"
 )
 
 func TestCreateMetricSpec(t *testing.T) {
@@ -56,3 +59,117 @@ func TestNewHPA(t *testing.T) {
 		t.Fatalf("create hpa: %v", err)
 	}
 }
+
+func TestApplyScalingBoundsToHPA(t *testing.T) {
+	tests := []struct {
+		name             string
+		min, max         int
+		bounds           *workerutil.ScalingBounds
+		wantMin, wantMax int32
+	}{
+		{name: "no bounds", min: 2, max: 5, wantMin: 2, wantMax: 5},
+		{name: "within bounds", min: 2, max: 5, bounds: &workerutil.ScalingBounds{MinReplicas: 1, MaxReplicas: 10}, wantMin: 2, wantMax: 5},
+		{name: "raised floor", min: 2, max: 5, bounds: &workerutil.ScalingBounds{MinReplicas: 4, MaxReplicas: 10}, wantMin: 4, wantMax: 5},
+		{name: "floor above max", min: 2, max: 5, bounds: &workerutil.ScalingBounds{MinReplicas: 8, MaxReplicas: 10}, wantMin: 8, wantMax: 8},
+		{name: "ceiling below min", min: 4, max: 6, bounds: &workerutil.ScalingBounds{MinReplicas: 1, MaxReplicas: 3}, wantMin: 3, wantMax: 3},
+		{name: "zero ceiling", min: 2, max: 5, bounds: &workerutil.ScalingBounds{MinReplicas: 0, MaxReplicas: 0}, wantMin: 1, wantMax: 1},
+		{name: "overlapped bounds", min: 2, max: 5, bounds: &workerutil.ScalingBounds{MinReplicas: 6, MaxReplicas: 3}, wantMin: 6, wantMax: 6},
+		{name: "rule min above max", min: 5, max: 3, wantMin: 5, wantMax: 5},
+	}
+	for _, tc := range tests {
+		rule := &model.TenantServiceAutoscalerRules{MinReplicas: tc.min, MaxReplicas: tc.max}
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		ApplyScalingBoundsToHPA(hpa, rule, tc.bounds)
+		if *hpa.Spec.MinReplicas != tc.wantMin || hpa.Spec.MaxReplicas != tc.wantMax {
+			t.Errorf("%s: want [%d, %d], got [%d, %d]", tc.name, tc.wantMin, tc.wantMax, *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+		}
+	}
+}
+
+func TestCreateCustomMetricSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  *model.TenantServiceAutoscalerRuleMetrics
+		want    autoscalingv2.MetricSourceType
+		wantErr bool
+	}{
+		{
+			name: "pods metrics",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType:       model.PodsMetricsType,
+				MetricsName:       "http_requests_per_second",
+				MetricTargetType:  model.AverageValueMetricTargetType,
+				MetricTargetValue: 100,
+			},
+			want: autoscalingv2.PodsMetricSourceType,
+		},
+		{
+			name: "object metrics",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType:       model.ObjectMetricsType,
+				MetricsName:       "request_latency_p95",
+				MetricTargetType:  model.ValueMetricTargetType,
+				MetricTargetValue: 200,
+				DescribedKind:     "Service",
+				DescribedName:     "gr2025a",
+			},
+			want: autoscalingv2.ObjectMetricSourceType,
+		},
+		{
+			name: "object metrics without described object",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType:       model.ObjectMetricsType,
+				MetricsName:       "request_latency_p95",
+				MetricTargetType:  model.ValueMetricTargetType,
+				MetricTargetValue: 200,
+			},
+			wantErr: true,
+		},
+		{
+			name: "external metrics",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType:       model.ExternalMetricsType,
+				MetricsName:       "queue_depth",
+				MetricTargetType:  model.AverageValueMetricTargetType,
+				MetricTargetValue: 30,
+				MetricSelector:    "queue=orders",
+			},
+			want: autoscalingv2.ExternalMetricSourceType,
+		},
+		{
+			name: "invalid selector",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType:       model.ExternalMetricsType,
+				MetricsName:       "queue_depth",
+				MetricTargetType:  model.ValueMetricTargetType,
+				MetricTargetValue: 30,
+				MetricSelector:    "queue in orders",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported metric type",
+			metric: &model.TenantServiceAutoscalerRuleMetrics{
+				MetricsType: "foobar",
+				MetricsName: "queue_depth",
+			},
+			wantErr: true,
+		},
+	}
+
+	for idx := range tests {
+		tc := tests[idx]
+		t.Run(tc.name, func(t *testing.T) {
+			ms, err := createMetricSpec(tc.metric)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("want error: %v, but got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if ms.Type != tc.want {
+				t.Errorf("want metric source type %s, but got %s", tc.want, ms.Type)
+			}
+		})
+	}
+}
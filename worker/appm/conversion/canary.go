@@ -0,0 +1,138 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conversion
+
+import (
+	"fmt"
+
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CanaryLabel marks the kubernetes resources of the canary version
+const CanaryLabel = "canary"
+
+const canarySuffix = "-canary"
+
+//CanaryResources converts the app service running the canary version into the resources of the canary release.
+//The pods of the canary version are selected only by the canary services, and the canary ingresses share
+//the hosts and paths of the http rules, so that the gateway splits the requests by weight, header or cookie.
+func CanaryResources(as *v1.AppService, canary *model.TenantServiceCanary) (*v1.CanaryResources, error) {
+	deployment := as.GetDeployment()
+	if deployment == nil {
+		return nil, fmt.Errorf("service %s is not a stateless component", as.ServiceAlias)
+	}
+	podName := as.ServiceAlias + canarySuffix
+	deployment = deployment.DeepCopy()
+	deployment.Name = as.ServiceID + canarySuffix
+	deployment.Labels = canaryLabels(as, deployment.Labels, podName)
+	deployment.Spec.Replicas = int32Ptr(canary.Replicas)
+	deployment.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"name":       podName,
+			"tenant_id":  as.TenantID,
+			"service_id": as.ServiceID,
+		},
+	}
+	deployment.Spec.Template.Labels = canaryLabels(as, deployment.Spec.Template.Labels, podName)
+	res := &v1.CanaryResources{Deployment: deployment}
+
+	services := make(map[string]string)
+	for _, svc := range as.GetServices(true) {
+		if svc.Labels["service_type"] != "outer" {
+			continue
+		}
+		svc = svc.DeepCopy()
+		services[svc.Name] = svc.Name + canarySuffix
+		svc.Name += canarySuffix
+		svc.Labels = canaryLabels(as, svc.Labels, "")
+		svc.Spec.ClusterIP = ""
+		svc.Spec.Selector = map[string]string{"name": podName}
+		res.Services = append(res.Services, svc)
+	}
+
+	for _, ing := range as.GetIngress(true) {
+		// tcp rules do not support canary release
+		if len(ing.Spec.Rules) == 0 {
+			continue
+		}
+		ing = ing.DeepCopy()
+		ing.Labels = canaryLabels(as, ing.Labels, "")
+		ing.ResourceVersion = ""
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for i := range rule.HTTP.Paths {
+				backend := rule.HTTP.Paths[i].Backend.Service
+				if backend != nil && services[backend.Name] != "" {
+					backend.Name = services[backend.Name]
+				}
+			}
+		}
+		annos := make(map[string]string, len(ing.Annotations))
+		for k, v := range ing.Annotations {
+			annos[k] = v
+		}
+		delete(annos, parser.GetAnnotationWithPrefix("weight"))
+		delete(annos, parser.GetAnnotationWithPrefix("header"))
+		delete(annos, parser.GetAnnotationWithPrefix("cookie"))
+		if canary.Weight > 0 {
+			res.Ingresses = append(res.Ingresses, canaryIngress(ing, "", annos, "weight", fmt.Sprintf("%d", canary.Weight)))
+		}
+		if canary.Header != "" {
+			res.Ingresses = append(res.Ingresses, canaryIngress(ing, "-header", annos, "header", canary.Header))
+		}
+		if canary.Cookie != "" {
+			res.Ingresses = append(res.Ingresses, canaryIngress(ing, "-cookie", annos, "cookie", canary.Cookie))
+		}
+	}
+	return res, nil
+}
+
+func canaryIngress(ing *networkingv1.Ingress, suffix string, annos map[string]string, key, value string) *networkingv1.Ingress {
+	ing = ing.DeepCopy()
+	ing.Name = ing.Name + canarySuffix + suffix
+	annotations := make(map[string]string, len(annos)+1)
+	for k, v := range annos {
+		annotations[k] = v
+	}
+	annotations[parser.GetAnnotationWithPrefix(key)] = value
+	ing.SetAnnotations(annotations)
+	return ing
+}
+
+//canaryLabels returns the labels of the canary resources. Without creater_id, the canary resources are not
+//taken as the resources of the service by the worker store, and the status of the service is not affected.
+func canaryLabels(as *v1.AppService, labels map[string]string, name string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	delete(result, "creater_id")
+	if name != "" {
+		result["name"] = name
+	}
+	result["version"] = as.DeployVersion
+	result[CanaryLabel] = "true"
+	return result
+}
@@ -0,0 +1,116 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/gateway/annotations/parser"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanaryResources(t *testing.T) {
+	as := &v1.AppService{
+		AppServiceBase: v1.AppServiceBase{
+			TenantID:     "tenant",
+			ServiceID:    "service",
+			ServiceAlias: "gr123456",
+		},
+	}
+	labels := map[string]string{"creator": "Kato", "creater_id": "1", "service_id": "service", "name": "gr123456", "version": "20210101"}
+	as.SetDeployment(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-deployment", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"name": "gr123456", "creater_id": "1", "version": "20210202"}},
+			},
+		},
+	})
+	as.SetService(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-1-80out", Labels: map[string]string{"service_type": "outer", "creater_id": "1"}},
+		Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1", Selector: map[string]string{"name": "gr123456"}},
+	})
+	as.SetService(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "service-1-80", Labels: map[string]string{"service_type": "inner"}},
+	})
+	as.SetIngress(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rule",
+			Labels:      map[string]string{"creator": "Kato", "creater_id": "1"},
+			Annotations: map[string]string{parser.GetAnnotationWithPrefix("weight"): "90"},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:    "/",
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "service-1-80out"}},
+					}},
+				}},
+			}},
+		},
+	})
+	as.SetIngress(&networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "tcp"},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "service-1-80out"}},
+		},
+	})
+
+	res, err := CanaryResources(as, &model.TenantServiceCanary{Weight: 10, Header: "X-Canary=true", Replicas: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deploy := res.Deployment
+	if deploy.Name != "service-canary" || *deploy.Spec.Replicas != 1 {
+		t.Errorf("unexpected deployment %s with %d replicas", deploy.Name, *deploy.Spec.Replicas)
+	}
+	podLabels := deploy.Spec.Template.Labels
+	if podLabels["name"] != "gr123456-canary" || podLabels["creater_id"] != "" || podLabels[CanaryLabel] != "true" {
+		t.Errorf("unexpected pod labels %v", podLabels)
+	}
+	if deploy.Labels["version"] != "20210202" {
+		t.Errorf("expected version 20210202, but got %s", deploy.Labels["version"])
+	}
+	if as.GetDeployment().Name != "service-deployment" {
+		t.Errorf("the deployment of the stable version is modified")
+	}
+	if len(res.Services) != 1 {
+		t.Fatalf("expected 1 service, but got %d", len(res.Services))
+	}
+	svc := res.Services[0]
+	if svc.Name != "service-1-80out-canary" || svc.Spec.Selector["name"] != "gr123456-canary" || svc.Spec.ClusterIP != "" {
+		t.Errorf("unexpected service %+v", svc)
+	}
+	if len(res.Ingresses) != 2 {
+		t.Fatalf("expected 2 ingresses, but got %d", len(res.Ingresses))
+	}
+	for _, want := range []struct {
+		name, key, value string
+	}{{"rule-canary", "weight", "10"}, {"rule-canary-header", "header", "X-Canary=true"}} {
+		var ing *networkingv1.Ingress
+		for _, item := range res.Ingresses {
+			if item.Name == want.name {
+				ing = item
+			}
+		}
+		if ing == nil {
+			t.Errorf("ingress %s not found", want.name)
+			continue
+		}
+		if len(ing.Annotations) != 1 || ing.Annotations[parser.GetAnnotationWithPrefix(want.key)] != want.value {
+			t.Errorf("unexpected annotations of ingress %s: %v", want.name, ing.Annotations)
+		}
+		if name := ing.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name; name != "service-1-80out-canary" {
+			t.Errorf("expected backend service-1-80out-canary, but got %s", name)
+		}
+	}
+	if name := as.GetIngress(false)[0].Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name; name != "service-1-80out" {
+		t.Errorf("the ingress of the stable version is modified")
+	}
+}
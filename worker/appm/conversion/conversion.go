@@ -67,6 +67,12 @@ func RegistConversion(name string, fun Conversion) {
 
 //InitAppService init a app service
 func InitAppService(dbmanager db.Manager, serviceID string, configs map[string]string, enableConversionList ...string) (*v1.AppService, error) {
+	return InitAppServiceWithVersion(dbmanager, serviceID, "", configs, enableConversionList...)
+}
+
+//InitAppServiceWithVersion init a app service running the given deploy version,
+//the current deploy version of the service is used if deployVersion is empty
+func InitAppServiceWithVersion(dbmanager db.Manager, serviceID, deployVersion string, configs map[string]string, enableConversionList ...string) (*v1.AppService, error) {
 	if configs == nil {
 		configs = make(map[string]string)
 	}
@@ -74,6 +80,7 @@ func InitAppService(dbmanager db.Manager, serviceID string, configs map[string]s
 	appService := &v1.AppService{
 		AppServiceBase: v1.AppServiceBase{
 			ServiceID:      serviceID,
+			DeployVersion:  deployVersion,
 			ExtensionSet:   configs,
 			GovernanceMode: model.GovernanceModeBuildInServiceMesh,
 		},
@@ -29,9 +29,10 @@ import (
 	"github.com/gridworkz/kato/event"
 	"github.com/gridworkz/kato/gateway/annotations/parser"
 	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -52,6 +53,15 @@ func createDefaultDomain(tenantName, serviceAlias string, servicePort int) strin
 	return fmt.Sprintf("%d.%s.%s.%s", servicePort, serviceAlias, tenantName, exDomain)
 }
 
+//ingressClassName returns the ingress class of the ingresses, which is set by INGRESS_CLASS
+func ingressClassName() *string {
+	class := strings.TrimSpace(os.Getenv("INGRESS_CLASS"))
+	if class == "" {
+		return nil
+	}
+	return &class
+}
+
 //TenantServiceRegist conv inner and outer service regist
 func TenantServiceRegist(as *v1.AppService, dbmanager db.Manager) error {
 	builder, err := AppServiceBuilder(as.ServiceID, string(as.ServiceType), dbmanager, as)
@@ -90,6 +100,8 @@ type AppServiceBuild struct {
 	replicationType    string
 	dbmanager          db.Manager
 	logger             event.Logger
+	// the canary release of the service, nil if there is no canary release
+	canary *model.TenantServiceCanary
 }
 
 //AppServiceBuilder returns a AppServiceBuild
@@ -139,9 +151,14 @@ func (a *AppServiceBuild) Build() (*v1.K8sResources, error) {
 		}
 		ports, pp, err = a.CreateUpstreamPluginMappingPort(ports, pluginPorts)
 	}
+	canary, err := a.dbmanager.TenantServiceCanaryDao().GetByServiceID(a.serviceID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("find service canary release from db error %s", err.Error())
+	}
+	a.canary = canary
 
 	var services []*corev1.Service
-	var ingresses []*extensions.Ingress
+	var ingresses []*networkingv1.Ingress
 	var secrets []*corev1.Secret
 	if ports != nil && len(ports) > 0 {
 		for i := range ports {
@@ -191,8 +208,8 @@ func (a *AppServiceBuild) Build() (*v1.K8sResources, error) {
 
 // ApplyRules applies http rules and tcp rules
 func (a AppServiceBuild) ApplyRules(serviceID string, containerPort, pluginContainerPort int,
-	service *corev1.Service) ([]*extensions.Ingress, []*corev1.Secret, error) {
-	var ingresses []*extensions.Ingress
+	service *corev1.Service) ([]*networkingv1.Ingress, []*corev1.Secret, error) {
+	var ingresses []*networkingv1.Ingress
 	var secrets []*corev1.Secret
 	httpRules, err := a.dbmanager.HTTPRuleDao().GetHTTPRuleByServiceIDAndContainerPort(serviceID, containerPort)
 	if err != nil {
@@ -236,7 +253,7 @@ func (a AppServiceBuild) ApplyRules(serviceID string, containerPort, pluginConta
 
 // applyTCPRule applies stream rule into ingress
 func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, pluginContainerPort int,
-	service *corev1.Service) (ing *extensions.Ingress, sec *corev1.Secret, err error) {
+	service *corev1.Service) (ing *networkingv1.Ingress, sec *corev1.Secret, err error) {
 	// deal with empty path and domain
 	path := strings.Replace(rule.Path, " ", "", -1)
 	if path == "" {
@@ -248,24 +265,30 @@ func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, plu
 	}
 
 	// create ingress
-	ing = &extensions.Ingress{
+	pathType := networkingv1.PathTypeImplementationSpecific
+	ing = &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rule.UUID,
 			Namespace: a.tenant.UUID,
 			Labels:    a.appService.GetCommonLabels(),
 		},
-		Spec: extensions.IngressSpec{
-			Rules: []extensions.IngressRule{
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ingressClassName(),
+			Rules: []networkingv1.IngressRule{
 				{
 					Host: domain,
-					IngressRuleValue: extensions.IngressRuleValue{
-						HTTP: &extensions.HTTPIngressRuleValue{
-							Paths: []extensions.HTTPIngressPath{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
 								{
 									Path: path,
-									Backend: extensions.IngressBackend{
-										ServiceName: service.Name,
-										ServicePort: intstr.FromInt(pluginContainerPort),
+									// the path is matched as the prefix of nginx location
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: service.Name,
+											Port: networkingv1.ServiceBackendPort{Number: int32(pluginContainerPort)},
+										},
 									},
 								},
 							},
@@ -279,7 +302,10 @@ func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, plu
 	// parse annotations
 	annos := make(map[string]string)
 	// weight
-	if rule.Weight > 1 {
+	if a.canary != nil && a.canary.Weight > 0 {
+		// the rest of the requests are routed to the stable version
+		annos[parser.GetAnnotationWithPrefix("weight")] = fmt.Sprintf("%d", 100-a.canary.Weight)
+	} else if rule.Weight > 1 {
 		annos[parser.GetAnnotationWithPrefix("weight")] = fmt.Sprintf("%d", rule.Weight)
 	}
 	// header
@@ -290,6 +316,31 @@ func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, plu
 	if rule.Cookie != "" {
 		annos[parser.GetAnnotationWithPrefix("cookie")] = rule.Cookie
 	}
+	// rate and connection limits
+	if rule.RateLimitRPS > 0 {
+		annos[parser.GetAnnotationWithPrefix("limit-rps")] = fmt.Sprintf("%d", rule.RateLimitRPS)
+		annos[parser.GetAnnotationWithPrefix("limit-burst")] = fmt.Sprintf("%d", rule.RateLimitBurst)
+	}
+	if rule.ConnectionLimit > 0 {
+		annos[parser.GetAnnotationWithPrefix("limit-connections")] = fmt.Sprintf("%d", rule.ConnectionLimit)
+	}
+	if (rule.RateLimitRPS > 0 || rule.ConnectionLimit > 0) && rule.RateLimitKey != "" {
+		annos[parser.GetAnnotationWithPrefix("limit-key")] = rule.RateLimitKey
+	}
+	// access policies
+	if rule.IPAllowList != "" {
+		annos[parser.GetAnnotationWithPrefix("whitelist-source-range")] = rule.IPAllowList
+	}
+	if rule.IPDenyList != "" {
+		annos[parser.GetAnnotationWithPrefix("denylist-source-range")] = rule.IPDenyList
+	}
+	if rule.BasicAuthSecret != "" {
+		annos[parser.GetAnnotationWithPrefix("auth-type")] = "basic"
+		annos[parser.GetAnnotationWithPrefix("auth-secret")] = rule.BasicAuthSecret
+	}
+	if rule.ForwardAuthURL != "" {
+		annos[parser.GetAnnotationWithPrefix("auth-url")] = rule.ForwardAuthURL
+	}
 	// certificate
 	if rule.CertificateID != "" {
 		cert, err := a.dbmanager.CertificateDao().GetCertificateByID(rule.CertificateID)
@@ -312,7 +363,7 @@ func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, plu
 			},
 			Type: corev1.SecretTypeOpaque,
 		}
-		ing.Spec.TLS = []extensions.IngressTLS{
+		ing.Spec.TLS = []networkingv1.IngressTLS{
 			{
 				Hosts:      []string{domain},
 				SecretName: sec.Name,
@@ -364,18 +415,21 @@ func (a *AppServiceBuild) applyHTTPRule(rule *model.HTTPRule, containerPort, plu
 }
 
 // applyTCPRule applies stream rule into ingress
-func (a *AppServiceBuild) applyTCPRule(rule *model.TCPRule, service *corev1.Service, namespace string) (ing *extensions.Ingress, err error) {
+func (a *AppServiceBuild) applyTCPRule(rule *model.TCPRule, service *corev1.Service, namespace string) (ing *networkingv1.Ingress, err error) {
 	// create ingress
-	ing = &extensions.Ingress{
+	ing = &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      rule.UUID,
 			Namespace: namespace,
 			Labels:    a.appService.GetCommonLabels(),
 		},
-		Spec: extensions.IngressSpec{
-			Backend: &extensions.IngressBackend{
-				ServiceName: service.Name,
-				ServicePort: intstr.FromInt(int(service.Spec.Ports[0].Port)),
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ingressClassName(),
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: service.Name,
+					Port: networkingv1.ServiceBackendPort{Number: service.Spec.Ports[0].Port},
+				},
 			},
 		},
 	}
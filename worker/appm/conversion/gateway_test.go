@@ -224,13 +224,13 @@ func TestApplyTcpRule(t *testing.T) {
 			parser.GetAnnotationWithPrefix("l4-port"),
 			ing.Annotations[parser.GetAnnotationWithPrefix("l4-port")])
 	}
-	if ing.Spec.Backend.ServiceName != testCase["serviceName"] {
+	if ing.Spec.DefaultBackend.Service.Name != testCase["serviceName"] {
 		t.Errorf("Expected %s for ServiceName but returned %s", testCase["serviceName"],
-			ing.Spec.Backend.ServiceName)
+			ing.Spec.DefaultBackend.Service.Name)
 	}
-	if ing.Spec.Backend.ServicePort.IntVal != int32(containerPort) {
+	if ing.Spec.DefaultBackend.Service.Port.Number != int32(containerPort) {
 		t.Errorf("Expected %v for ServicePort but returned %v", containerPort,
-			ing.Spec.Backend.ServicePort)
+			ing.Spec.DefaultBackend.Service.Port)
 	}
 
 	// create k8s resources
@@ -362,13 +362,13 @@ func TestAppServiceBuild_ApplyHttpRule(t *testing.T) {
 	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Path != testCase["path"] {
 		t.Errorf("Expected %s for path, but returned %s", testCase["path"], ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Path)
 	}
-	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName != testCase["serviceName"] {
+	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Name != testCase["serviceName"] {
 		t.Errorf("Expected %s for serviceName, but returned %s", testCase["serviceName"],
-			ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName)
+			ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Name)
 	}
-	if fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort.IntVal) != testCase["servicePort"] {
+	if fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Port.Number) != testCase["servicePort"] {
 		t.Errorf("Expected %s for servicePort, but returned %s", testCase["servicePort"],
-			fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort.IntVal))
+			fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Port.Number))
 	}
 
 	// create k8s resources
@@ -537,13 +537,13 @@ func TestAppServiceBuild_ApplyHttpRuleWithCertificate(t *testing.T) {
 	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Path != testCase["path"] {
 		t.Errorf("Expected %s for path, but returned %s", testCase["path"], ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Path)
 	}
-	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName != testCase["serviceName"] {
+	if ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Name != testCase["serviceName"] {
 		t.Errorf("Expected %s for serviceName, but returned %s", testCase["serviceName"],
-			ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServiceName)
+			ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Name)
 	}
-	if fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort.IntVal) != testCase["servicePort"] {
+	if fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Port.Number) != testCase["servicePort"] {
 		t.Errorf("Expected %s for servicePort, but returned %s", testCase["servicePort"],
-			fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.ServicePort.IntVal))
+			fmt.Sprintf("%v", ing.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend.Service.Port.Number))
 	}
 	if sec.Namespace != testCase["namespace"] {
 		t.Errorf("Expected %s for namespace, but returned %s", testCase["namespace"], sec.Namespace)
@@ -0,0 +1,79 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conversion
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/db/model"
+	typesv1 "github.com/gridworkz/kato/worker/appm/types/v1"
+)
+
+//getMeshTLSPolicy returns the mutual tls policy of the component, the policy of the application
+//takes precedence over the policy of the tenant. nil means no policy.
+func getMeshTLSPolicy(as *typesv1.AppService, dbmanager db.Manager) (*model.TenantMeshTLSPolicy, error) {
+	if as.AppID != "" {
+		policy, err := dbmanager.TenantMeshTLSPolicyDao().GetByTenantAndApp(as.TenantID, as.AppID)
+		if err == nil {
+			return policy, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+	policy, err := dbmanager.TenantMeshTLSPolicyDao().GetByTenantAndApp(as.TenantID, "")
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+//setMeshTLSConfig writes the mutual tls config of the component into the configmap of the mesh plugin,
+//the node reads it to configure the sidecar of the component and the sidecars calling the component
+func setMeshTLSConfig(as *typesv1.AppService, dbmanager db.Manager, data map[string]string) {
+	policy, err := getMeshTLSPolicy(as, dbmanager)
+	if err != nil {
+		logrus.Errorf("get mesh tls policy of component %s failure %s", as.ServiceID, err.Error())
+		return
+	}
+	if policy == nil || policy.Mode == "" || policy.Mode == api_model.MeshTLSModeDisabled {
+		return
+	}
+	config := api_model.MeshTLSConfig{Mode: policy.Mode}
+	for _, source := range strings.Split(policy.AllowedSources, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			config.AllowedSources = append(config.AllowedSources, source)
+		}
+	}
+	body, err := json.Marshal(config)
+	if err != nil {
+		logrus.Errorf("marshal mesh tls config of component %s failure %s", as.ServiceID, err.Error())
+		return
+	}
+	data[api_model.KeyMeshTLSConfig] = string(body)
+}
@@ -125,6 +125,10 @@ func conversionServicePlugin(as *typesv1.AppService, dbmanager db.Manager) ([]v1
 		if err != nil {
 			return nil, nil, nil, fmt.Errorf("get plugin model info failure %s", err.Error())
 		}
+		switch pluginModel {
+		case model.InBoundNetPlugin, model.OutBoundNetPlugin, model.InBoundAndOutBoundNetPlugin:
+			pc.Env = append(pc.Env, xdsAPIVersionEnv(as, dbmanager, pluginR.PluginID))
+		}
 		var preconatiner = false
 		if pluginModel == model.InBoundAndOutBoundNetPlugin || pluginModel == model.InBoundNetPlugin {
 			inBoundPlugin = pluginR
@@ -188,6 +192,7 @@ func conversionServicePlugin(as *typesv1.AppService, dbmanager db.Manager) ([]v1
 			logrus.Errorf("apply default mesh plugin config failure %s", err.Error())
 		}
 		defaultSidecarContainer := createTCPDefaultPluginContainer(as, pluginID, mainContainer.Env, pluginConfig)
+		defaultSidecarContainer.Env = append(defaultSidecarContainer.Env, xdsAPIVersionEnv(as, dbmanager, pluginID))
 		precontainers = append(precontainers, defaultSidecarContainer)
 		meshPluginID = pluginID
 	}
@@ -305,6 +310,10 @@ func ApplyPluginConfig(as *typesv1.AppService, servicePluginRelation *model.Tena
 				"plugin-model":  servicePluginRelation.PluginModel,
 			},
 		}
+		switch servicePluginRelation.PluginModel {
+		case model.InBoundNetPlugin, model.OutBoundNetPlugin, model.InBoundAndOutBoundNetPlugin:
+			setMeshTLSConfig(as, dbmanager, cm.Data)
+		}
 		as.SetConfigMap(cm)
 	}
 }
@@ -360,6 +369,7 @@ func applyDefaultMeshPluginConfig(as *typesv1.AppService, dbmanager db.Manager)
 			"plugin-model":  model.OutBoundNetPlugin,
 		},
 	}
+	setMeshTLSConfig(as, dbmanager, cm.Data)
 	as.SetConfigMap(cm)
 	return pluginID, res, nil
 }
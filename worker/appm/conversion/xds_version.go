@@ -0,0 +1,54 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package conversion
+
+import (
+	"encoding/json"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	api_model "github.com/gridworkz/kato/api/model"
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/node/nodem/envoy/conver"
+	typesv1 "github.com/gridworkz/kato/worker/appm/types/v1"
+)
+
+//xdsAPIVersionEnv tells the mesh sidecar which bootstrap to start envoy with, it is the xds api version the node
+//serves the sidecar on: v2 only if the plugin config pins it and mutual tls is disabled, which is only served on v3.
+func xdsAPIVersionEnv(as *typesv1.AppService, dbmanager db.Manager, pluginID string) corev1.EnvVar {
+	env := corev1.EnvVar{Name: conver.KeyXDSVersion, Value: conver.XDSVersionV3}
+	config, err := dbmanager.TenantPluginVersionConfigDao().GetPluginConfig(as.ServiceID, pluginID)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			logrus.Errorf("get plugin %s config of component %s failure %s", pluginID, as.ServiceID, err.Error())
+		}
+		return env
+	}
+	var spec api_model.ResourceSpec
+	if err := json.Unmarshal([]byte(config.ConfigStr), &spec); err != nil || conver.PinnedXDSVersion(&spec) != conver.XDSVersionV2 {
+		return env
+	}
+	// the mutual tls config is not written into the configmap if the policy can not be got, so the node serves v2
+	if policy, err := getMeshTLSPolicy(as, dbmanager); err != nil || policy == nil || policy.Mode == "" || policy.Mode == api_model.MeshTLSModeDisabled {
+		env.Value = conver.XDSVersionV2
+	}
+	return env
+}
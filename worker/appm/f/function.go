@@ -29,7 +29,7 @@ import (
 	"github.com/sirupsen/logrus"
 	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -105,7 +105,7 @@ func ApplyOne(clientset kubernetes.Interface, app *v1.AppService) error {
 	}
 	// delete delIngress
 	for _, ing := range app.GetDelIngs() {
-		err := clientset.ExtensionsV1beta1().Ingresses(ing.Namespace).Delete(ing.Name, &metav1.DeleteOptions{})
+		err := clientset.NetworkingV1().Ingresses(ing.Namespace).Delete(ing.Name, &metav1.DeleteOptions{})
 		if err != nil && !k8sErrors.IsNotFound(err) {
 			// don't return error, hope it is ok next time
 			logrus.Warningf("error deleting ingress(%v): %v", ing, err)
@@ -179,11 +179,11 @@ func persistUpdate(service *corev1.Service, clientSet kubernetes.Interface) erro
 	return err
 }
 
-func ensureIngress(ingress *extensions.Ingress, clientSet kubernetes.Interface) {
-	_, err := clientSet.ExtensionsV1beta1().Ingresses(ingress.Namespace).Update(ingress)
+func ensureIngress(ingress *networkingv1.Ingress, clientSet kubernetes.Interface) {
+	_, err := clientSet.NetworkingV1().Ingresses(ingress.Namespace).Update(ingress)
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
-			_, err := clientSet.ExtensionsV1beta1().Ingresses(ingress.Namespace).Create(ingress)
+			_, err := clientSet.NetworkingV1().Ingresses(ingress.Namespace).Create(ingress)
 			if err != nil && !k8sErrors.IsAlreadyExists(err) {
 				logrus.Errorf("error creating ingress %+v: %v", ingress, err)
 			}
@@ -287,12 +287,12 @@ func EnsureHPA(new *autoscalingv2.HorizontalPodAutoscaler, clientSet kubernetes.
 	}
 }
 
-// UpgradeIngress is used to update *extensions.Ingress.
+// UpgradeIngress is used to update *networkingv1.Ingress.
 func UpgradeIngress(clientset kubernetes.Interface,
 	as *v1.AppService,
-	old, new []*extensions.Ingress,
+	old, new []*networkingv1.Ingress,
 	handleErr func(msg string, err error) error) error {
-	var oldMap = make(map[string]*extensions.Ingress, len(old))
+	var oldMap = make(map[string]*networkingv1.Ingress, len(old))
 	for i, item := range old {
 		oldMap[item.Name] = old[i]
 	}
@@ -300,7 +300,7 @@ func UpgradeIngress(clientset kubernetes.Interface,
 		if o, ok := oldMap[n.Name]; ok {
 			n.UID = o.UID
 			n.ResourceVersion = o.ResourceVersion
-			ing, err := clientset.ExtensionsV1beta1().Ingresses(n.Namespace).Update(n)
+			ing, err := clientset.NetworkingV1().Ingresses(n.Namespace).Update(n)
 			if err != nil {
 				if err := handleErr(fmt.Sprintf("error updating ingress: %+v: err: %v",
 					ing, err), err); err != nil {
@@ -313,7 +313,7 @@ func UpgradeIngress(clientset kubernetes.Interface,
 			logrus.Debugf("ServiceID: %s; successfully update ingress: %s", as.ServiceID, ing.Name)
 		} else {
 			logrus.Debugf("ingress: %+v", n)
-			ing, err := clientset.ExtensionsV1beta1().Ingresses(n.Namespace).Create(n)
+			ing, err := clientset.NetworkingV1().Ingresses(n.Namespace).Create(n)
 			if err != nil {
 				if err := handleErr(fmt.Sprintf("error creating ingress: %+v: err: %v",
 					ing, err), err); err != nil {
@@ -327,7 +327,7 @@ func UpgradeIngress(clientset kubernetes.Interface,
 	}
 	for _, ing := range oldMap {
 		if ing != nil {
-			if err := clientset.ExtensionsV1beta1().Ingresses(ing.Namespace).Delete(ing.Name,
+			if err := clientset.NetworkingV1().Ingresses(ing.Namespace).Delete(ing.Name,
 				&metav1.DeleteOptions{}); err != nil {
 				if err := handleErr(fmt.Sprintf("error deleting ingress: %+v: err: %v",
 					ing, err), err); err != nil {
@@ -597,3 +597,80 @@ func CreateOrUpdateSecret(clientset kubernetes.Interface, secret *corev1.Secret)
 	_, err = clientset.CoreV1().Secrets(secret.Namespace).Update(secret)
 	return err
 }
+
+// ApplyCanary creates or updates the resources of the canary version,
+// and deletes the canary services and ingresses that are no longer needed.
+func ApplyCanary(clientset kubernetes.Interface, serviceID string, res *v1.CanaryResources) error {
+	deploy := res.Deployment
+	old, err := clientset.AppsV1().Deployments(deploy.Namespace).Get(deploy.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("get canary deployment: %v", err)
+		}
+		if _, err := clientset.AppsV1().Deployments(deploy.Namespace).Create(deploy); err != nil {
+			return fmt.Errorf("create canary deployment: %v", err)
+		}
+	} else {
+		deploy.ResourceVersion = old.ResourceVersion
+		if _, err := clientset.AppsV1().Deployments(deploy.Namespace).Update(deploy); err != nil {
+			return fmt.Errorf("update canary deployment: %v", err)
+		}
+	}
+
+	services := make(map[string]struct{}, len(res.Services))
+	for _, svc := range res.Services {
+		services[svc.Name] = struct{}{}
+		if err := ensureService(svc, clientset); err != nil {
+			return fmt.Errorf("ensure canary service: %v", err)
+		}
+	}
+	ingresses := make(map[string]struct{}, len(res.Ingresses))
+	for _, ing := range res.Ingresses {
+		ingresses[ing.Name] = struct{}{}
+		ensureIngress(ing, clientset)
+	}
+	return deleteCanary(clientset, deploy.Namespace, serviceID, services, ingresses)
+}
+
+// DeleteCanary deletes all the resources of the canary version of the service.
+func DeleteCanary(clientset kubernetes.Interface, namespace, serviceID string) error {
+	err := clientset.AppsV1().Deployments(namespace).Delete(serviceID+"-canary", &metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("delete canary deployment: %v", err)
+	}
+	return deleteCanary(clientset, namespace, serviceID, nil, nil)
+}
+
+// deleteCanary deletes the canary services and ingresses of the service except the given ones.
+func deleteCanary(clientset kubernetes.Interface, namespace, serviceID string, services, ingresses map[string]struct{}) error {
+	listOpts := metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("service_id=%s,canary=true", serviceID),
+	}
+	ingList, err := clientset.NetworkingV1().Ingresses(namespace).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("list canary ingresses: %v", err)
+	}
+	for _, ing := range ingList.Items {
+		if _, ok := ingresses[ing.Name]; ok {
+			continue
+		}
+		err := clientset.NetworkingV1().Ingresses(namespace).Delete(ing.Name, &metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("delete canary ingress %s: %v", ing.Name, err)
+		}
+	}
+	svcList, err := clientset.CoreV1().Services(namespace).List(listOpts)
+	if err != nil {
+		return fmt.Errorf("list canary services: %v", err)
+	}
+	for _, svc := range svcList.Items {
+		if _, ok := services[svc.Name]; ok {
+			continue
+		}
+		err := clientset.CoreV1().Services(namespace).Delete(svc.Name, &metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("delete canary service %s: %v", svc.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrResumeTokenExpired is returned when a resume token was issued by another
+// store, or the events after it are no longer kept. The watcher has to start
+// over from the current state.
+var ErrResumeTokenExpired = errors.New("resume token expired")
+
+const (
+	// podEventHistory is the number of pod events kept for resuming watches.
+	podEventHistory = 4096
+	// podWatchBuffer is the number of pod events a watcher may lag behind
+	// before it is dropped.
+	podWatchBuffer = 256
+)
+
+// PodEvent is a pod change observed by the pod informer.
+type PodEvent struct {
+	Type EventType
+	Pod  *corev1.Pod
+	// Token resumes a watch right after this event.
+	Token string
+	seq   uint64
+}
+
+// PodWatch receives the pod events of the store.
+type PodWatch struct {
+	// Token is the position the watch started at.
+	Token  string
+	ch     chan *PodEvent
+	hub    *podEventHub
+	closed bool
+}
+
+// ResultChan returns the events channel. The channel is closed when the watch
+// is stopped, or dropped because it fell behind.
+func (w *PodWatch) ResultChan() <-chan *PodEvent {
+	return w.ch
+}
+
+// Stop stops the watch.
+func (w *PodWatch) Stop() {
+	w.hub.remove(w)
+}
+
+// podEventHub broadcasts pod events to watchers and keeps a bounded history
+// of them, so that a watcher can resume after a reconnect.
+type podEventHub struct {
+	lock     sync.Mutex
+	epoch    string
+	seq      uint64
+	history  []*PodEvent
+	size     int
+	watchers map[*PodWatch]struct{}
+}
+
+func newPodEventHub(size int) *podEventHub {
+	return &podEventHub{
+		// tokens issued before a restart, or by another worker, must not resume
+		epoch:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		history:  make([]*PodEvent, 0, size),
+		size:     size,
+		watchers: make(map[*PodWatch]struct{}),
+	}
+}
+
+func (h *podEventHub) token(seq uint64) string {
+	return fmt.Sprintf("%s.%d", h.epoch, seq)
+}
+
+func (h *podEventHub) publish(evtType EventType, pod *corev1.Pod) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.seq++
+	evt := &PodEvent{Type: evtType, Pod: pod, Token: h.token(h.seq), seq: h.seq}
+	if len(h.history) < h.size {
+		h.history = append(h.history, evt)
+	} else {
+		h.history[int((h.seq-1)%uint64(h.size))] = evt
+	}
+	for w := range h.watchers {
+		select {
+		case w.ch <- evt:
+		default:
+			// drop the slow watcher instead of blocking the informer,
+			// it can resume from its last token.
+			h.closeWatch(w)
+		}
+	}
+}
+
+// watch registers a new watcher. If token is still resumable, the events
+// after it are returned, otherwise ErrResumeTokenExpired is returned along
+// with the watch and the caller has to send the current state first.
+func (h *podEventHub) watch(token string) (*PodWatch, []*PodEvent, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	w := &PodWatch{
+		Token: h.token(h.seq),
+		ch:    make(chan *PodEvent, podWatchBuffer),
+		hub:   h,
+	}
+	h.watchers[w] = struct{}{}
+	replay, err := h.since(token)
+	return w, replay, err
+}
+
+func (h *podEventHub) since(token string) ([]*PodEvent, error) {
+	if token == "" {
+		return nil, ErrResumeTokenExpired
+	}
+	i := strings.LastIndex(token, ".")
+	if i < 0 || token[:i] != h.epoch {
+		return nil, ErrResumeTokenExpired
+	}
+	seq, err := strconv.ParseUint(token[i+1:], 10, 64)
+	if err != nil || seq > h.seq {
+		return nil, ErrResumeTokenExpired
+	}
+	missed := h.seq - seq
+	if missed > uint64(len(h.history)) {
+		return nil, ErrResumeTokenExpired
+	}
+	replay := make([]*PodEvent, 0, missed)
+	for s := seq + 1; s <= h.seq; s++ {
+		replay = append(replay, h.history[int((s-1)%uint64(h.size))])
+	}
+	return replay, nil
+}
+
+func (h *podEventHub) remove(w *PodWatch) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.closeWatch(w)
+}
+
+func (h *podEventHub) closeWatch(w *PodWatch) {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	delete(h.watchers, w)
+	close(w.ch)
+}
@@ -0,0 +1,94 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package store
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestPodEventHubResume(t *testing.T) {
+	hub := newPodEventHub(4)
+	w, _, err := hub.watch("")
+	if err != ErrResumeTokenExpired {
+		t.Fatalf("want ErrResumeTokenExpired for a fresh watch, got %v", err)
+	}
+	defer w.Stop()
+
+	hub.publish(CreateEvent, newTestPod("a"))
+	hub.publish(UpdateEvent, newTestPod("a"))
+	first := <-w.ResultChan()
+	second := <-w.ResultChan()
+	if first.Type != CreateEvent || second.Type != UpdateEvent {
+		t.Fatalf("unexpected events %s, %s", first.Type, second.Type)
+	}
+
+	hub.publish(CreateEvent, newTestPod("b"))
+	hub.publish(DeleteEvent, newTestPod("a"))
+	resumed, replay, err := hub.watch(first.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Stop()
+	if len(replay) != 3 || replay[0].Token != second.Token || replay[2].Pod.Name != "a" || replay[2].Type != DeleteEvent {
+		t.Fatalf("unexpected replay %+v", replay)
+	}
+
+	// only 4 events are kept, the second one is gone
+	hub.publish(CreateEvent, newTestPod("c"))
+	hub.publish(CreateEvent, newTestPod("d"))
+	if _, _, err := hub.watch(first.Token); err != ErrResumeTokenExpired {
+		t.Fatalf("want ErrResumeTokenExpired, got %v", err)
+	}
+	if _, replay, err := hub.watch(second.Token); err != nil || len(replay) != 4 || replay[3].Pod.Name != "d" {
+		t.Fatalf("unexpected replay %+v, %v", replay, err)
+	}
+
+	// tokens of another store never resume
+	other := newPodEventHub(4)
+	other.epoch = "other"
+	if _, _, err := other.watch(second.Token); err != ErrResumeTokenExpired {
+		t.Fatalf("want ErrResumeTokenExpired, got %v", err)
+	}
+}
+
+func TestPodEventHubDropSlowWatcher(t *testing.T) {
+	hub := newPodEventHub(podWatchBuffer * 2)
+	w, _, _ := hub.watch("")
+	for i := 0; i <= podWatchBuffer; i++ {
+		hub.publish(UpdateEvent, newTestPod("a"))
+	}
+	var received int
+	for range w.ResultChan() {
+		received++
+	}
+	if received != podWatchBuffer {
+		t.Fatalf("want %d buffered events, got %d", podWatchBuffer, received)
+	}
+	if len(hub.watchers) != 0 {
+		t.Fatalf("slow watcher is still registered")
+	}
+	// stopping a dropped watch is a no-op
+	w.Stop()
+}
@@ -49,7 +49,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
-	extensions "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	internalclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -101,6 +101,7 @@ type Storer interface {
 	ListPods(namespace string, selector labels.Selector) ([]*corev1.Pod, error)
 	ListReplicaSets(namespace string, selector labels.Selector) ([]*appsv1.ReplicaSet, error)
 	ListServices(namespace string, selector labels.Selector) ([]*corev1.Service, error)
+	WatchPods(resumeToken string) (*PodWatch, []*PodEvent, error)
 
 	Informer() *Informer
 	Lister() *Lister
@@ -138,7 +139,7 @@ type appRuntimeStore struct {
 	kubeconfig             *rest.Config
 	clientset              kubernetes.Interface
 	crdClient              *internalclientset.Clientset
-	katoClient         katoversioned.Interface
+	katoClient             katoversioned.Interface
 	crClients              map[string]interface{}
 	ctx                    context.Context
 	cancel                 context.CancelFunc
@@ -156,6 +157,7 @@ type appRuntimeStore struct {
 	volumeTypeListeners    map[string]chan<- *model.TenantServiceVolumeType
 	volumeTypeListenerLock sync.Mutex
 	resourceCache          *ResourceCache
+	podEvents              *podEventHub
 }
 
 //NewStore new app runtime store
@@ -171,7 +173,7 @@ func NewStore(
 	store := &appRuntimeStore{
 		kubeconfig:          kubeconfig,
 		clientset:           clientset,
-		katoClient:      katoClient,
+		katoClient:          katoClient,
 		ctx:                 ctx,
 		cancel:              cancel,
 		informers:           &Informer{CRS: make(map[string]cache.SharedIndexInformer)},
@@ -184,6 +186,7 @@ func NewStore(
 		resourceCache:       NewResourceCache(),
 		podUpdateListeners:  make(map[string]chan<- *corev1.Pod, 1),
 		volumeTypeListeners: make(map[string]chan<- *model.TenantServiceVolumeType, 1),
+		podEvents:           newPodEventHub(podEventHistory),
 	}
 	crdClient, err := internalclientset.NewForConfig(kubeconfig)
 	if err != nil {
@@ -220,8 +223,8 @@ func NewStore(
 	store.informers.ConfigMap = infFactory.Core().V1().ConfigMaps().Informer()
 	store.listers.ConfigMap = infFactory.Core().V1().ConfigMaps().Lister()
 
-	store.informers.Ingress = infFactory.Extensions().V1beta1().Ingresses().Informer()
-	store.listers.Ingress = infFactory.Extensions().V1beta1().Ingresses().Lister()
+	store.informers.Ingress = infFactory.Networking().V1().Ingresses().Informer()
+	store.listers.Ingress = infFactory.Networking().V1().Ingresses().Lister()
 
 	store.informers.ReplicaSet = infFactory.Apps().V1().ReplicaSets().Informer()
 	store.listers.ReplicaSets = infFactory.Apps().V1().ReplicaSets().Lister()
@@ -601,14 +604,14 @@ func (a *appRuntimeStore) OnAdd(obj interface{}) {
 			}
 		}
 	}
-	if ingress, ok := obj.(*extensions.Ingress); ok {
+	if ingress, ok := obj.(*networkingv1.Ingress); ok {
 		serviceID := ingress.Labels["service_id"]
 		version := ingress.Labels["version"]
 		createrID := ingress.Labels["creater_id"]
 		if serviceID != "" && createrID != "" {
 			appservice, err := a.getAppService(serviceID, version, createrID, true)
 			if err == conversion.ErrServiceNotFound {
-				a.conf.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(context.Background(), ingress.Name, metav1.DeleteOptions{})
+				a.conf.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.Background(), ingress.Name, metav1.DeleteOptions{})
 			}
 			if appservice != nil {
 				appservice.SetIngress(ingress)
@@ -714,8 +717,8 @@ func (a *appRuntimeStore) getAppService(serviceID, version, createrID string, cr
 }
 func (a *appRuntimeStore) OnUpdate(oldObj, newObj interface{}) {
 	// ingress update maybe change owner component
-	if ingress, ok := newObj.(*extensions.Ingress); ok {
-		oldIngress := oldObj.(*extensions.Ingress)
+	if ingress, ok := newObj.(*networkingv1.Ingress); ok {
+		oldIngress := oldObj.(*networkingv1.Ingress)
 		if oldIngress.Labels["service_id"] != ingress.Labels["service_id"] {
 			logrus.Infof("ingress %s change owner component", oldIngress.Name)
 			serviceID := oldIngress.Labels["service_id"]
@@ -825,7 +828,7 @@ func (a *appRuntimeStore) OnDeletes(objs ...interface{}) {
 				}
 			}
 		}
-		if ingress, ok := obj.(*extensions.Ingress); ok {
+		if ingress, ok := obj.(*networkingv1.Ingress); ok {
 			serviceID := ingress.Labels["service_id"]
 			version := ingress.Labels["version"]
 			createrID := ingress.Labels["creater_id"]
@@ -1291,6 +1294,7 @@ func (a *appRuntimeStore) podEventHandler() cache.ResourceEventHandlerFuncs {
 					appservice.SetPods(pod)
 				}
 			}
+			a.podEvents.publish(CreateEvent, pod)
 		},
 		DeleteFunc: func(obj interface{}) {
 			pod := obj.(*corev1.Pod)
@@ -1305,6 +1309,7 @@ func (a *appRuntimeStore) podEventHandler() cache.ResourceEventHandlerFuncs {
 					}
 				}
 			}
+			a.podEvents.publish(DeleteEvent, pod)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			pod := cur.(*corev1.Pod)
@@ -1325,6 +1330,7 @@ func (a *appRuntimeStore) podEventHandler() cache.ResourceEventHandlerFuncs {
 				default:
 				}
 			}
+			a.podEvents.publish(UpdateEvent, pod)
 		},
 	}
 }
@@ -1464,6 +1470,13 @@ func (a *appRuntimeStore) UnRegistPodUpdateListener(name string) {
 	delete(a.podUpdateListeners, name)
 }
 
+// WatchPods watches the pod events after resumeToken. The events missed since
+// resumeToken are returned along with the watch, ErrResumeTokenExpired means
+// they are lost and the current pods have to be listed again.
+func (a *appRuntimeStore) WatchPods(resumeToken string) (*PodWatch, []*PodEvent, error) {
+	return a.podEvents.watch(resumeToken)
+}
+
 // RegisterVolumeTypeListener -
 func (a *appRuntimeStore) RegisterVolumeTypeListener(name string, ch chan<- *model.TenantServiceVolumeType) {
 	a.volumeTypeListenerLock.Lock()
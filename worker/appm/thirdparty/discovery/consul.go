@@ -0,0 +1,193 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eapache/channels"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util/consul"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const consulWaitTime = 5 * time.Minute
+
+type consulDiscoverier struct {
+	cli   *consul.Client
+	index uint64
+
+	sid     string
+	servers []string
+	service string
+	opts    consul.QueryOptions
+	token   string
+
+	updateCh *channels.RingChannel
+	stopCh   chan struct{}
+	records  map[string]*v1.RbdEndpoint
+}
+
+// NewConsul creates a new Discovery which is implemeted by consul.
+// The key of the configuration is the name of the consul service, which can be
+// followed by the query parameters tag and dc, such as orders?tag=v1&dc=dc1.
+// The password of the configuration is used as the ACL token.
+func NewConsul(cfg *model.ThirdPartySvcDiscoveryCfg,
+	updateCh *channels.RingChannel,
+	stopCh chan struct{}) (Discoverier, error) {
+	service, opts, err := parseConsulKey(cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &consulDiscoverier{
+		sid:      cfg.ServiceID,
+		servers:  strings.Split(cfg.Servers, ","),
+		service:  service,
+		opts:     opts,
+		token:    cfg.Password,
+		updateCh: updateCh,
+		stopCh:   stopCh,
+		records:  make(map[string]*v1.RbdEndpoint),
+	}, nil
+}
+
+func parseConsulKey(key string) (string, consul.QueryOptions, error) {
+	var opts consul.QueryOptions
+	service := key
+	if idx := strings.Index(key, "?"); idx >= 0 {
+		service = key[:idx]
+		query, err := url.ParseQuery(key[idx+1:])
+		if err != nil {
+			return "", opts, fmt.Errorf("invalid consul key %s: %v", key, err)
+		}
+		opts.Tag = query.Get("tag")
+		opts.Datacenter = query.Get("dc")
+	}
+	service = strings.Trim(service, "/ ")
+	if service == "" {
+		return "", opts, fmt.Errorf("consul service name can not be empty")
+	}
+	return service, opts, nil
+}
+
+// Connect creates a consul client with the given servers.
+func (c *consulDiscoverier) Connect() error {
+	cli, err := consul.NewClient(c.servers, c.token)
+	if err != nil {
+		return fmt.Errorf("error connecting consul: %v", err)
+	}
+	c.cli = cli
+	return nil
+}
+
+// Close -
+func (c *consulDiscoverier) Close() error {
+	return nil
+}
+
+// Fetch fetches the instances of the service from consul.
+func (c *consulDiscoverier) Fetch() ([]*v1.RbdEndpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	records, index, err := c.query(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	c.records = records
+	c.index = index
+	return recordsToEndpoints(records), nil
+}
+
+func (c *consulDiscoverier) query(ctx context.Context, waitIndex uint64) (map[string]*v1.RbdEndpoint, uint64, error) {
+	if c.cli == nil {
+		return nil, 0, fmt.Errorf("can't fetching data from consul without consul client")
+	}
+	opts := c.opts
+	opts.WaitIndex = waitIndex
+	opts.WaitTime = consulWaitTime
+	entries, index, err := c.cli.HealthService(ctx, c.service, &opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching endpoints from consul: %v", err)
+	}
+	records := make(map[string]*v1.RbdEndpoint)
+	for _, entry := range entries {
+		address := net.JoinHostPort(entry.Address(), strconv.Itoa(entry.Service.Port))
+		records[address] = &v1.RbdEndpoint{
+			UUID:     endpointUUID(c.sid, address),
+			Sid:      c.sid,
+			IP:       entry.Address(),
+			Port:     entry.Service.Port,
+			IsOnline: entry.Passing(),
+			IsDomain: net.ParseIP(entry.Address()) == nil,
+		}
+	}
+	return records, index, nil
+}
+
+// Watch watches the instances of the service with the blocking queries of consul.
+func (c *consulDiscoverier) Watch() {
+	logrus.Infof("Start watching third-party endpoints. Consul service: %s", c.service)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stopCh
+		cancel()
+	}()
+	for {
+		records, index, err := c.query(ctx, c.index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logrus.Errorf("error watching consul service %s: %v", c.service, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		if index != c.index {
+			for _, event := range diffRecords(c.records, records) {
+				c.updateCh.In() <- event
+			}
+			c.records = records
+		}
+		// reset the index if it goes backwards, such as consul is restored from a snapshot.
+		if index < c.index {
+			index = 0
+		}
+		c.index = index
+		if index == 0 {
+			// the query does not block without an index, avoid busy looping.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+}
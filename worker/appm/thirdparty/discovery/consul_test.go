@@ -0,0 +1,146 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eapache/channels"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util/consul"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+)
+
+type fakeConsul struct {
+	lock    sync.Mutex
+	index   uint64
+	entries []*consul.ServiceEntry
+	changed chan struct{}
+}
+
+func (f *fakeConsul) set(entries ...*consul.ServiceEntry) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.index++
+	f.entries = entries
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/health/service/orders" || r.URL.Query().Get("tag") != "v1" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.lock.Lock()
+	index, changed := f.index, f.changed
+	f.lock.Unlock()
+	if wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); wait >= index {
+		select {
+		case <-changed:
+		case <-time.After(2 * time.Second):
+		}
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	json.NewEncoder(w).Encode(f.entries)
+}
+
+func consulEntry(ip string, port int, status string) *consul.ServiceEntry {
+	return &consul.ServiceEntry{
+		Node:    consul.Node{Address: "192.168.0.1"},
+		Service: consul.AgentService{Service: "orders", Address: ip, Port: port},
+		Checks:  []*consul.HealthCheck{{Status: status}},
+	}
+}
+
+func TestConsulWatch(t *testing.T) {
+	fake := &fakeConsul{changed: make(chan struct{})}
+	fake.set(consulEntry("10.0.0.1", 8080, consul.HealthPassing), consulEntry("10.0.0.2", 8080, consul.HealthPassing))
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := &model.ThirdPartySvcDiscoveryCfg{
+		ServiceID: "45197f4936cf45efa2ac4831ce42025a",
+		Type:      model.DiscorveryTypeConsul.String(),
+		Servers:   server.URL,
+		Key:       "orders?tag=v1",
+	}
+	updateCh := channels.NewRingChannel(1024)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	discoverier, err := NewDiscoverier(cfg, updateCh, stopCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := discoverier.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	eps, err := discoverier.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 2 || eps[0].IP != "10.0.0.1" || !eps[0].IsOnline {
+		t.Fatalf("unexpected endpoints: %+v", eps)
+	}
+	go discoverier.Watch()
+
+	fake.set(consulEntry("10.0.0.1", 8080, "critical"), consulEntry("10.0.0.3", 8080, consul.HealthPassing))
+	want := map[EventType]string{
+		UnhealthyEvent: "10.0.0.1",
+		CreateEvent:    "10.0.0.3",
+		DeleteEvent:    "10.0.0.2",
+	}
+	for range want {
+		select {
+		case obj := <-updateCh.Out():
+			event := obj.(Event)
+			ep := event.Obj.(*v1.RbdEndpoint)
+			if want[event.Type] != ep.IP {
+				t.Errorf("unexpected event %s of endpoint %s", event.Type, ep.IP)
+			}
+			if ep.UUID != endpointUUID(cfg.ServiceID, ep.IP+":8080") {
+				t.Errorf("unexpected uuid %s of endpoint %s", ep.UUID, ep.IP)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for events")
+		}
+	}
+}
+
+func TestParseConsulKey(t *testing.T) {
+	service, opts, err := parseConsulKey("orders?tag=v1&dc=dc2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service != "orders" || opts.Tag != "v1" || opts.Datacenter != "dc2" {
+		t.Errorf("unexpected result: %s %+v", service, opts)
+	}
+	if _, _, err := parseConsulKey("?tag=v1"); err == nil {
+		t.Errorf("expect error for empty service name")
+	}
+}
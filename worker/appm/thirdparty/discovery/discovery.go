@@ -64,6 +64,10 @@ func NewDiscoverier(cfg *model.ThirdPartySvcDiscoveryCfg,
 	switch strings.ToLower(cfg.Type) {
 	case strings.ToLower(string(model.DiscorveryTypeEtcd)):
 		return NewEtcd(cfg, updateCh, stopCh), nil
+	case strings.ToLower(string(model.DiscorveryTypeConsul)):
+		return NewConsul(cfg, updateCh, stopCh)
+	case strings.ToLower(string(model.DiscorveryTypeDNS)):
+		return NewDNS(cfg, updateCh, stopCh)
 	default:
 		return nil, fmt.Errorf("Unsupported discovery type: %s", cfg.Type)
 	}
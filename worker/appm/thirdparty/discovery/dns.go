@@ -0,0 +1,142 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/eapache/channels"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util/dns"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	"github.com/sirupsen/logrus"
+)
+
+var dnsRefreshInterval = 30 * time.Second
+
+type dnsDiscoverier struct {
+	resolver *net.Resolver
+
+	sid     string
+	servers []string
+	name    string
+
+	updateCh *channels.RingChannel
+	stopCh   chan struct{}
+	records  map[string]*v1.RbdEndpoint
+}
+
+// NewDNS creates a new Discovery which is implemeted by DNS.
+// The key of the configuration is the name of the SRV records, such as _http._tcp.example.com,
+// or a domain name with port, such as example.com:8080.
+// The servers of the configuration are the DNS servers, the system resolver is used if empty.
+func NewDNS(cfg *model.ThirdPartySvcDiscoveryCfg,
+	updateCh *channels.RingChannel,
+	stopCh chan struct{}) (Discoverier, error) {
+	name := strings.TrimSpace(cfg.Key)
+	if name == "" {
+		return nil, fmt.Errorf("dns name can not be empty")
+	}
+	if !dns.IsSRV(name) {
+		if _, _, err := net.SplitHostPort(name); err != nil {
+			return nil, fmt.Errorf("dns name %s is neither a SRV name nor a host with port", name)
+		}
+	}
+	return &dnsDiscoverier{
+		sid:      cfg.ServiceID,
+		servers:  strings.Split(cfg.Servers, ","),
+		name:     name,
+		updateCh: updateCh,
+		stopCh:   stopCh,
+		records:  make(map[string]*v1.RbdEndpoint),
+	}, nil
+}
+
+// Connect creates a resolver with the given DNS servers.
+func (d *dnsDiscoverier) Connect() error {
+	d.resolver = dns.NewResolver(d.servers)
+	return nil
+}
+
+// Close -
+func (d *dnsDiscoverier) Close() error {
+	return nil
+}
+
+// Fetch resolves the endpoints from the DNS records.
+func (d *dnsDiscoverier) Fetch() ([]*v1.RbdEndpoint, error) {
+	records, err := d.lookup()
+	if err != nil {
+		return nil, err
+	}
+	d.records = records
+	return recordsToEndpoints(records), nil
+}
+
+func (d *dnsDiscoverier) lookup() (map[string]*v1.RbdEndpoint, error) {
+	if d.resolver == nil {
+		return nil, fmt.Errorf("can't resolving %s without dns resolver", d.name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	endpoints, err := dns.Lookup(ctx, d.resolver, d.name)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving endpoints from dns: %v", err)
+	}
+	records := make(map[string]*v1.RbdEndpoint)
+	for _, ep := range endpoints {
+		address := ep.Address()
+		records[address] = &v1.RbdEndpoint{
+			UUID:     endpointUUID(d.sid, address),
+			Sid:      d.sid,
+			IP:       ep.IP,
+			Port:     ep.Port,
+			IsOnline: true,
+		}
+	}
+	return records, nil
+}
+
+// Watch resolves the DNS records periodically, since DNS does not support watching.
+func (d *dnsDiscoverier) Watch() {
+	logrus.Infof("Start watching third-party endpoints. DNS name: %s", d.name)
+	ticker := time.NewTicker(dnsRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stopCh:
+			return
+		case <-ticker.C:
+		}
+		records, err := d.lookup()
+		if err != nil {
+			// keep the endpoints if the DNS server is temporarily unavailable.
+			logrus.Warningf("error watching dns name %s: %v", d.name, err)
+			continue
+		}
+		for _, event := range diffRecords(d.records, records) {
+			d.updateCh.In() <- event
+		}
+		d.records = records
+	}
+}
@@ -0,0 +1,95 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eapache/channels"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util/dns/dnstest"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+)
+
+func TestDNSWatch(t *testing.T) {
+	server, err := dnstest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.SetIPs("node1.example", "10.0.1.1")
+	server.SetIPs("node2.example", "10.0.1.2")
+	server.SetSRVs("_http._tcp.orders.example", dnstest.SRV{Target: "node1.example", Port: 8080})
+
+	cfg := &model.ThirdPartySvcDiscoveryCfg{
+		ServiceID: "45197f4936cf45efa2ac4831ce42025a",
+		Type:      model.DiscorveryTypeDNS.String(),
+		Servers:   server.Addr,
+		Key:       "_http._tcp.orders.example",
+	}
+	updateCh := channels.NewRingChannel(1024)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	dnsRefreshInterval = 100 * time.Millisecond
+	discoverier, err := NewDiscoverier(cfg, updateCh, stopCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := discoverier.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	eps, err := discoverier.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 1 || eps[0].IP != "10.0.1.1" || eps[0].Port != 8080 {
+		t.Fatalf("unexpected endpoints: %+v", eps)
+	}
+	go discoverier.Watch()
+
+	server.SetSRVs("_http._tcp.orders.example", dnstest.SRV{Target: "node2.example", Port: 8080})
+	want := map[EventType]string{
+		CreateEvent: "10.0.1.2",
+		DeleteEvent: "10.0.1.1",
+	}
+	for range want {
+		select {
+		case obj := <-updateCh.Out():
+			event := obj.(Event)
+			ep := event.Obj.(*v1.RbdEndpoint)
+			if want[event.Type] != ep.IP {
+				t.Errorf("unexpected event %s of endpoint %s", event.Type, ep.IP)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for events")
+		}
+	}
+}
+
+func TestNewDNSInvalidName(t *testing.T) {
+	cfg := &model.ThirdPartySvcDiscoveryCfg{
+		Type: model.DiscorveryTypeDNS.String(),
+		Key:  "orders.example",
+	}
+	if _, err := NewDiscoverier(cfg, nil, nil); err == nil {
+		t.Errorf("expect error for the name without port")
+	}
+}
@@ -0,0 +1,81 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discovery
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+)
+
+// endpointUUID generates a stable uuid for the endpoint of the given address,
+// which can be used as the name of the port of the kubernetes endpoints.
+func endpointUUID(sid, address string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(sid+"/"+address)))
+}
+
+// diffRecords compares the records of two fetches and returns the events
+// that transform the old records into the new ones.
+func diffRecords(old, new map[string]*v1.RbdEndpoint) []Event {
+	var events []Event
+	for _, key := range sortedKeys(new) {
+		ep := new[key]
+		oldEp, ok := old[key]
+		if !ok {
+			events = append(events, Event{Type: CreateEvent, Obj: ep})
+			continue
+		}
+		if oldEp.IP != ep.IP || oldEp.Port != ep.Port {
+			events = append(events, Event{Type: UpdateEvent, Obj: ep})
+			continue
+		}
+		if oldEp.IsOnline != ep.IsOnline {
+			if ep.IsOnline {
+				events = append(events, Event{Type: HealthEvent, Obj: ep})
+			} else {
+				events = append(events, Event{Type: UnhealthyEvent, Obj: ep})
+			}
+		}
+	}
+	for _, key := range sortedKeys(old) {
+		if _, ok := new[key]; !ok {
+			events = append(events, Event{Type: DeleteEvent, Obj: old[key]})
+		}
+	}
+	return events
+}
+
+func sortedKeys(records map[string]*v1.RbdEndpoint) []string {
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func recordsToEndpoints(records map[string]*v1.RbdEndpoint) []*v1.RbdEndpoint {
+	res := make([]*v1.RbdEndpoint, 0, len(records))
+	for _, key := range sortedKeys(records) {
+		res = append(res, records[key])
+	}
+	return res
+}
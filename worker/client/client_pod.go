@@ -49,3 +49,22 @@ func (a *AppRuntimeSyncClient) GetPodDetail(sid, name string) (*pb.PodDetail, er
 		PodName: name,
 	})
 }
+
+// WatchServicePods watches the pods of the given components until ctx is done.
+// resumeToken is the token of the last event received, empty to start over.
+func (a *AppRuntimeSyncClient) WatchServicePods(ctx context.Context, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchPodsClient, error) {
+	return a.AppRuntimeSyncClient.WatchPods(ctx, &pb.WatchPodsReq{
+		ServiceIds:  serviceIDs,
+		ResumeToken: resumeToken,
+	})
+}
+
+// WatchStatus watches the status of the given components, or of every component
+// of appID, until ctx is done.
+func (a *AppRuntimeSyncClient) WatchStatus(ctx context.Context, appID string, serviceIDs []string, resumeToken string) (pb.AppRuntimeSync_WatchAppStatusClient, error) {
+	return a.AppRuntimeSyncClient.WatchAppStatus(ctx, &pb.WatchAppStatusReq{
+		AppId:       appID,
+		ServiceIds:  serviceIDs,
+		ResumeToken: resumeToken,
+	})
+}
@@ -46,6 +46,10 @@ var TaskNum float64
 //TaskError exec error task number
 var TaskError float64
 
+//executedTaskTTL how long the ids of the executed tasks are remembered, which covers the redeliveries
+//of the tasks acknowledged after the visibility timeout of the mq
+const executedTaskTTL = time.Hour
+
 //TaskManager task
 type TaskManager struct {
 	ctx           context.Context
@@ -53,6 +57,7 @@ type TaskManager struct {
 	config        option.Config
 	handleManager *handle.Manager
 	client        client.MQClient
+	executed      *executedTasks
 }
 
 //NewTaskManager return *TaskManager
@@ -71,6 +76,7 @@ func NewTaskManager(cfg option.Config,
 		cancel:        cancel,
 		config:        cfg,
 		handleManager: handleManager,
+		executed:      newExecutedTasks(executedTaskTTL),
 	}
 }
 
@@ -127,11 +133,22 @@ func (t *TaskManager) Do() {
 			logrus.Debugf("receive a task: %v", data)
 			transData, err := model.TransTask(data)
 			if err != nil {
+				// the task is not acknowledged, it will be moved to the dead letter topic after redeliveries
 				logrus.Error("trans mq msg data error ", err.Error())
 				continue
 			}
+			// the execution, such as starting or upgrading the components, could take longer than the visibility
+			// timeout, then the task is redelivered before it is acknowledged, it is not executed again.
+			if t.executed.contains(data.TaskId, time.Now()) {
+				logrus.Infof("task %s has been executed, acknowledge the redelivered one", data.TaskId)
+				t.ackTask(data)
+				continue
+			}
 			rc := t.handleManager.AnalystToExec(transData)
 			if rc != nil && rc != handle.ErrCallback {
+				// the failed tasks are not retried
+				t.executed.add(data.TaskId, time.Now())
+				t.ackTask(data)
 				logrus.Warningf("execute task: %v", rc)
 				TaskError++
 			} else if rc != nil && rc == handle.ErrCallback {
@@ -144,18 +161,56 @@ func (t *TaskManager) Do() {
 				cancel()
 				logrus.Debugf("retry send task to mq ,reply is %v", reply)
 				if err != nil {
+					// the task is redelivered after the visibility timeout if it is not acknowledged
 					logrus.Errorf("enqueue task %v to mq topic %v Error", data, client.WorkerTopic)
 					continue
 				}
+				t.ackTask(data)
 				//if handle is waiting, sleep 3 second
 				time.Sleep(time.Second * 3)
 			} else {
+				t.executed.add(data.TaskId, time.Now())
+				t.ackTask(data)
 				TaskNum++
 			}
 		}
 	}
 }
 
+func (t *TaskManager) ackTask(data *pb.TaskMessage) {
+	if err := t.client.AckTask(client.WorkerTopic, data); err != nil {
+		logrus.Errorf("ack task %s: %v", data.TaskId, err)
+	}
+}
+
+//executedTasks remembers the ids of the tasks executed in the ttl, it is only used in the goroutine receiving the tasks
+type executedTasks struct {
+	ttl   time.Duration
+	tasks map[string]time.Time
+}
+
+func newExecutedTasks(ttl time.Duration) *executedTasks {
+	return &executedTasks{ttl: ttl, tasks: make(map[string]time.Time)}
+}
+
+func (e *executedTasks) add(taskID string, now time.Time) {
+	if taskID == "" {
+		return
+	}
+	e.tasks[taskID] = now
+}
+
+//contains returns whether the task has been executed in the ttl, the expired ids are removed
+func (e *executedTasks) contains(taskID string, now time.Time) bool {
+	for id, executed := range e.tasks {
+		if now.Sub(executed) > e.ttl {
+			delete(e.tasks, id)
+		}
+	}
+	_, ok := e.tasks[taskID]
+	return ok && taskID != ""
+}
+
 //Stop
 func (t *TaskManager) Stop() error {
 	logrus.Info("discover manager is stoping.")
@@ -0,0 +1,40 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package discover
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExecutedTasks(t *testing.T) {
+	executed := newExecutedTasks(time.Hour)
+	now := time.Now()
+	executed.add("task1", now)
+	executed.add("", now)
+	if !executed.contains("task1", now.Add(time.Minute)) {
+		t.Errorf("the redelivered task should be found executed")
+	}
+	if executed.contains("task2", now) || executed.contains("", now) {
+		t.Errorf("the tasks not executed should not be found")
+	}
+	if executed.contains("task1", now.Add(2*time.Hour)) || len(executed.tasks) != 0 {
+		t.Errorf("the expired task should be removed, but got %v", executed.tasks)
+	}
+}
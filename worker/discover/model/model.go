@@ -165,6 +165,13 @@ func NewTaskBody(taskType string, body []byte) TaskBody {
 			return nil
 		}
 		return b
+	case "canary":
+		b := &CanaryTaskBody{}
+		err := ffjson.Unmarshal(body, &b)
+		if err != nil {
+			return nil
+		}
+		return b
 	default:
 		return DefaultTaskBody{}
 	}
@@ -197,6 +204,8 @@ func CreateTaskBody(taskType string) TaskBody {
 		return DeleteTenantTaskBody{}
 	case "refreshhpa":
 		return RefreshHPATaskBody{}
+	case "canary":
+		return CanaryTaskBody{}
 	default:
 		return DefaultTaskBody{}
 	}
@@ -358,5 +367,23 @@ type RefreshHPATaskBody struct {
 	EventID   string `json:"eventID"`
 }
 
+// The actions of the canary task
+const (
+	// CanaryActionApply creates or updates the canary version and its routes
+	CanaryActionApply = "apply"
+	// CanaryActionPromote upgrades the component to the canary version and removes the canary version
+	CanaryActionPromote = "promote"
+	// CanaryActionAbort removes the canary version, all the requests are routed to the stable version
+	CanaryActionAbort = "abort"
+)
+
+// CanaryTaskBody -
+type CanaryTaskBody struct {
+	TenantID  string `json:"tenant_id"`
+	ServiceID string `json:"service_id"`
+	Action    string `json:"action"`
+	EventID   string `json:"event_id"`
+}
+
 //DefaultTaskBody
 type DefaultTaskBody map[string]interface{}
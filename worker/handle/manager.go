@@ -37,6 +37,7 @@ import (
 	"github.com/gridworkz/kato/util"
 	"github.com/gridworkz/kato/worker/appm/controller"
 	"github.com/gridworkz/kato/worker/appm/conversion"
+	"github.com/gridworkz/kato/worker/appm/f"
 	"github.com/gridworkz/kato/worker/appm/store"
 	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
 	"github.com/gridworkz/kato/worker/discover/model"
@@ -130,6 +131,9 @@ func (m *Manager) AnalystToExec(task *model.Task) error {
 	case "refreshhpa":
 		logrus.Info("start a 'refreshhpa' task worker")
 		return m.ExecRefreshHPATask(task)
+	case "canary":
+		logrus.Info("start a 'canary' task worker")
+		return m.canaryExec(task)
 	default:
 		logrus.Warning("task can not execute because no type is identified")
 		return nil
@@ -535,6 +539,14 @@ func (m *Manager) deleteTenant(task *model.Task) (err error) {
 		err = fmt.Errorf("delete tenant: %v", err)
 		return
 	}
+	if err = db.GetManager().TenantImagePolicyDao().DeleteByTenantID(body.TenantID); err != nil {
+		err = fmt.Errorf("delete image policy of tenant: %v", err)
+		return
+	}
+	if err = db.GetManager().TenantMeshTLSPolicyDao().DeleteByTenantID(body.TenantID); err != nil {
+		err = fmt.Errorf("delete mesh tls policies of tenant: %v", err)
+		return
+	}
 
 	return
 }
@@ -577,3 +589,114 @@ func (m *Manager) ExecRefreshHPATask(task *model.Task) error {
 	logrus.Infof("rule id: %s; successfully refresh hpa", body.RuleID)
 	return nil
 }
+
+//canaryExec runs, promotes or aborts the canary version of the service
+func (m *Manager) canaryExec(task *model.Task) error {
+	body, ok := task.Body.(*model.CanaryTaskBody)
+	if !ok {
+		logrus.Errorf("exec task 'canary'; wrong type: %v", reflect.TypeOf(task))
+		return fmt.Errorf("exec task 'canary': wrong input")
+	}
+	switch body.Action {
+	case model.CanaryActionApply:
+		return m.applyCanary(body)
+	case model.CanaryActionPromote:
+		// the deploy version of the service has been changed to the canary version,
+		// the routes of the stable version are restored by the upgrade.
+		err := m.rollingUpgradeExec(&model.Task{
+			Type: "rolling_upgrade",
+			Body: model.RollingUpgradeTaskBody{
+				TenantID:  body.TenantID,
+				ServiceID: body.ServiceID,
+				EventID:   body.EventID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := f.DeleteCanary(m.cfg.KubeClient, body.TenantID, body.ServiceID); err != nil {
+			logrus.Errorf("delete canary version of service %s: %v", body.ServiceID, err)
+			return err
+		}
+		return nil
+	case model.CanaryActionAbort:
+		return m.abortCanary(body)
+	default:
+		logrus.Warningf("unknown canary action: %s", body.Action)
+		return nil
+	}
+}
+
+func (m *Manager) applyCanary(body *model.CanaryTaskBody) error {
+	logger := event.GetManager().GetLogger(body.EventID)
+	defer event.GetManager().ReleaseLogger(logger)
+	oldAppService := m.store.GetAppService(body.ServiceID)
+	if oldAppService == nil || oldAppService.IsClosed() {
+		logger.Info("service is closed, the canary version will not be started", event.GetLastLoggerOption())
+		return nil
+	}
+	canary, err := m.dbmanager.TenantServiceCanaryDao().GetByServiceID(body.ServiceID)
+	if err != nil {
+		logger.Error("canary release not found", event.GetCallbackLoggerOption())
+		return fmt.Errorf("get canary release of service %s: %v", body.ServiceID, err)
+	}
+	canaryAppService, err := conversion.InitAppServiceWithVersion(m.dbmanager, body.ServiceID, canary.CanaryVersion, nil)
+	if err != nil {
+		logrus.Errorf("Application init create failure:%s", err.Error())
+		logger.Error("Application init create failure", event.GetCallbackLoggerOption())
+		return fmt.Errorf("Application init create failure")
+	}
+	res, err := conversion.CanaryResources(canaryAppService, canary)
+	if err != nil {
+		logger.Error(fmt.Sprintf("create canary resources failure: %v", err), event.GetCallbackLoggerOption())
+		return err
+	}
+	if err := f.ApplyCanary(m.cfg.KubeClient, body.ServiceID, res); err != nil {
+		logrus.Errorf("apply canary version of service %s: %v", body.ServiceID, err)
+		logger.Error("apply canary version failure", event.GetCallbackLoggerOption())
+		return err
+	}
+	// the weight of the stable version depends on the canary release
+	if err := m.applyStableRoutes(body.ServiceID, logger, oldAppService); err != nil {
+		logger.Error("apply rules of the stable version failure", event.GetCallbackLoggerOption())
+		return err
+	}
+	logger.Info(fmt.Sprintf("canary version %s is running", canary.CanaryVersion), event.GetLastLoggerOption())
+	return nil
+}
+
+func (m *Manager) abortCanary(body *model.CanaryTaskBody) error {
+	logger := event.GetManager().GetLogger(body.EventID)
+	defer event.GetManager().ReleaseLogger(logger)
+	oldAppService := m.store.GetAppService(body.ServiceID)
+	if oldAppService != nil && !oldAppService.IsClosed() {
+		if err := m.applyStableRoutes(body.ServiceID, logger, oldAppService); err != nil {
+			logger.Error("apply rules of the stable version failure", event.GetCallbackLoggerOption())
+			return err
+		}
+	}
+	if err := f.DeleteCanary(m.cfg.KubeClient, body.TenantID, body.ServiceID); err != nil {
+		logrus.Errorf("delete canary version of service %s: %v", body.ServiceID, err)
+		logger.Error("delete canary version failure", event.GetCallbackLoggerOption())
+		return err
+	}
+	logger.Info("canary version is removed", event.GetLastLoggerOption())
+	return nil
+}
+
+//applyStableRoutes applies the services and ingresses of the stable version of the service
+func (m *Manager) applyStableRoutes(serviceID string, logger event.Logger, oldAppService *v1.AppService) error {
+	newAppService, err := conversion.InitAppService(m.dbmanager, serviceID, nil)
+	if err != nil {
+		logrus.Errorf("Application init create failure:%s", err.Error())
+		logger.Error("Application init create failure", event.GetCallbackLoggerOption())
+		return fmt.Errorf("Application init create failure")
+	}
+	newAppService.Logger = logger
+	newAppService.SetDeletedResources(oldAppService)
+	if err := m.controllerManager.StartController(controller.TypeApplyRuleController, *newAppService); err != nil {
+		logrus.Errorf("Application apply rule controller failure:%s", err.Error())
+		return fmt.Errorf("Application apply rule controller failure:%s", err.Error())
+	}
+	return nil
+}
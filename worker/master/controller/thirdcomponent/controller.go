@@ -100,6 +100,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (res reconcile.Result, retErr e
 		return ctrl.Result{}, nil
 	}
 	r.discoverPool.AddDiscover(discover)
+	r.discoverPool.UpdateEndpoints(component, endpoints)
 
 	if len(endpoints) == 0 {
 		component.Status.Phase = v1alpha1.ComponentPending
@@ -208,7 +209,8 @@ func createEndpoint(component *v1alpha1.ThirdComponent, service *corev1.Service,
 					}(),
 					Addresses: func() (re []corev1.EndpointAddress) {
 						for _, se := range sourceEndpoint {
-							if se.Status == v1alpha1.EndpointReady {
+							// the domain endpoints which are not resolved can not be used as kubernetes endpoints
+							if se.Status == v1alpha1.EndpointReady && se.Address.GetIP() != "" {
 								re = append(re, corev1.EndpointAddress{
 									IP: se.Address.GetIP(),
 									TargetRef: &corev1.ObjectReference{
@@ -226,7 +228,8 @@ func createEndpoint(component *v1alpha1.ThirdComponent, service *corev1.Service,
 					}(),
 					NotReadyAddresses: func() (re []corev1.EndpointAddress) {
 						for _, se := range sourceEndpoint {
-							if se.Status == v1alpha1.EndpointNotReady {
+							// the domain endpoints which are not resolved can not be used as kubernetes endpoints
+							if se.Status == v1alpha1.EndpointNotReady && se.Address.GetIP() != "" {
 								re = append(re, corev1.EndpointAddress{
 									IP: se.Address.GetIP(),
 									TargetRef: &corev1.ObjectReference{
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/gridworkz/kato/pkg/apis/kato/v1alpha1"
+	"github.com/gridworkz/kato/util/dns"
 	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +51,12 @@ func NewDiscover(component *v1alpha1.ThirdComponent, restConfig *rest.Config) (D
 			client:    clientset,
 		}, nil
 	}
+	if len(component.Spec.EndpointSource.StaticEndpoints) > 0 {
+		return &staticEndpoint{
+			component: component,
+			resolver:  dns.NewResolver(nil),
+		}, nil
+	}
 	return nil, fmt.Errorf("not support source type")
 }
 
@@ -146,7 +153,7 @@ func (k *kubernetesDiscover) DiscoverOne(ctx context.Context) ([]*v1alpha1.Third
 						Address:     *ed,
 						ServicePort: getServicePort(port.Name),
 						TargetRef:   address.TargetRef,
-						Status:      v1alpha1.EndpointReady,
+						Status:      v1alpha1.EndpointNotReady,
 					})
 				}
 			}
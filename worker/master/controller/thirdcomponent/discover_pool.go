@@ -60,6 +60,7 @@ func (d *DiscoverPool) Start() {
 			func() {
 				ctx, cancel := context.WithTimeout(d.ctx, time.Second*10)
 				defer cancel()
+				d.applyProbeResults(component)
 				var old v1alpha1.ThirdComponent
 				name := client.ObjectKey{Name: component.Name, Namespace: component.Namespace}
 				d.reconciler.Client.Get(ctx, name, &old)
@@ -81,11 +82,15 @@ func (d *DiscoverPool) Start() {
 }
 
 type Worker struct {
+	lock       sync.Mutex
 	discover   Discover
 	cancel     context.CancelFunc
 	ctx        context.Context
 	updateChan chan *v1alpha1.ThirdComponent
 	stoped     bool
+	prober     *prober
+	// endpoints discovered from the source, before the probe results are applied.
+	endpoints []*v1alpha1.ThirdComponentEndpointStatus
 }
 
 func (w *Worker) Start() {
@@ -95,8 +100,12 @@ func (w *Worker) Start() {
 	}()
 	w.stoped = false
 	logrus.Infof("discover endpoint list worker %s/%s  started", w.discover.GetComponent().Namespace, w.discover.GetComponent().Name)
+	go w.probe()
 	for {
-		w.discover.Discover(w.ctx, w.updateChan)
+		endpoints, err := w.getDiscover().Discover(w.ctx, w.updateChan)
+		if err == nil && endpoints != nil {
+			w.setEndpoints(endpoints)
+		}
 		select {
 		case <-w.ctx.Done():
 			return
@@ -106,9 +115,71 @@ func (w *Worker) Start() {
 }
 
 func (w *Worker) UpdateDiscover(discover Discover) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
 	w.discover = discover
 }
 
+func (w *Worker) getDiscover() Discover {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.discover
+}
+
+func (w *Worker) setEndpoints(endpoints []*v1alpha1.ThirdComponentEndpointStatus) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.endpoints = copyEndpoints(endpoints)
+}
+
+func (w *Worker) getEndpoints() []*v1alpha1.ThirdComponentEndpointStatus {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return copyEndpoints(w.endpoints)
+}
+
+// probe probes the endpoints periodically, and updates the component status if any of the endpoints changes its status.
+func (w *Worker) probe() {
+	for {
+		component := w.getDiscover().GetComponent()
+		probe := component.Spec.Probe
+		period := 10 * time.Second
+		if probe != nil {
+			period = probe.GetPeriod()
+		}
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-time.After(period):
+		}
+		if probe == nil || (probe.HTTPGet == nil && probe.TCPSocket == nil) {
+			continue
+		}
+		endpoints := w.getEndpoints()
+		if w.prober.Probe(w.ctx, probe, endpoints) {
+			new := component.DeepCopy()
+			new.Status.Endpoints = endpoints
+			w.prober.Apply(new.Status.Endpoints)
+			select {
+			case w.updateChan <- new:
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func copyEndpoints(endpoints []*v1alpha1.ThirdComponentEndpointStatus) []*v1alpha1.ThirdComponentEndpointStatus {
+	if endpoints == nil {
+		return nil
+	}
+	res := make([]*v1alpha1.ThirdComponentEndpointStatus, 0, len(endpoints))
+	for _, ep := range endpoints {
+		res = append(res, ep.DeepCopy())
+	}
+	return res
+}
+
 func (w *Worker) Stop() {
 	w.cancel()
 }
@@ -124,6 +195,7 @@ func (d *DiscoverPool) newWorker(dis Discover) *Worker {
 		discover:   dis,
 		cancel:     cancel,
 		updateChan: d.updateChan,
+		prober:     newProber(),
 	}
 }
 
@@ -158,3 +230,33 @@ func (d *DiscoverPool) RemoveDiscover(component *v1alpha1.ThirdComponent) {
 		delete(d.discoverWorker, key)
 	}
 }
+
+// UpdateEndpoints records the endpoints discovered from the source of the component,
+// and sets the status of the endpoints by the probe results.
+func (d *DiscoverPool) UpdateEndpoints(component *v1alpha1.ThirdComponent, endpoints []*v1alpha1.ThirdComponentEndpointStatus) {
+	d.lock.Lock()
+	worker, exist := d.discoverWorker[component.Namespace+component.Name]
+	d.lock.Unlock()
+	if !exist {
+		return
+	}
+	worker.setEndpoints(endpoints)
+	if component.Spec.Probe == nil {
+		// the probe is removed, the endpoints are ready as the source says.
+		worker.prober.Reset()
+		return
+	}
+	worker.prober.Apply(endpoints)
+}
+
+func (d *DiscoverPool) applyProbeResults(component *v1alpha1.ThirdComponent) {
+	if component.Spec.Probe == nil {
+		return
+	}
+	d.lock.Lock()
+	worker, exist := d.discoverWorker[component.Namespace+component.Name]
+	d.lock.Unlock()
+	if exist {
+		worker.prober.Apply(component.Status.Endpoints)
+	}
+}
@@ -0,0 +1,127 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package thirdcomponent
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gridworkz/kato/pkg/apis/kato/v1alpha1"
+	"github.com/gridworkz/kato/util/dns"
+	"github.com/sirupsen/logrus"
+)
+
+// resolveInterval is the interval to resolve the domain names of the static endpoints.
+var resolveInterval = 30 * time.Second
+
+type staticEndpoint struct {
+	component *v1alpha1.ThirdComponent
+	resolver  *net.Resolver
+}
+
+func (s *staticEndpoint) GetComponent() *v1alpha1.ThirdComponent {
+	return s.component
+}
+
+// Discover resolves the static endpoints periodically, since the addresses of the domain names may change.
+func (s *staticEndpoint) Discover(ctx context.Context, update chan *v1alpha1.ThirdComponent) ([]*v1alpha1.ThirdComponentEndpointStatus, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	case <-time.After(resolveInterval):
+	}
+	endpoints, err := s.DiscoverOne(ctx)
+	if err != nil {
+		logrus.Errorf("discover static endpoints of component %s/%s failure %s", s.component.Namespace, s.component.Name, err.Error())
+		return nil, err
+	}
+	new := s.component.DeepCopy()
+	new.Status.Endpoints = endpoints
+	update <- new
+	return endpoints, nil
+}
+
+// DiscoverOne returns the status of the static endpoints, the address of the domain name
+// is resolved into the IP addresses, each of which has its own status.
+func (s *staticEndpoint) DiscoverOne(ctx context.Context) ([]*v1alpha1.ThirdComponentEndpointStatus, error) {
+	var es = []*v1alpha1.ThirdComponentEndpointStatus{}
+	exists := make(map[v1alpha1.EndpointAddress]bool)
+	add := func(status *v1alpha1.ThirdComponentEndpointStatus) {
+		if exists[status.Address] {
+			return
+		}
+		exists[status.Address] = true
+		es = append(es, status)
+	}
+	for _, ep := range s.component.Spec.EndpointSource.StaticEndpoints {
+		address := ep.GetEndpointAddress()
+		if address == nil {
+			add(&v1alpha1.ThirdComponentEndpointStatus{
+				Address: v1alpha1.EndpointAddress(ep.Address),
+				Status:  v1alpha1.EndpointNotReady,
+				Reason:  "invalid address, the address must be ip:port or domain:port",
+			})
+			continue
+		}
+		if !address.IsDomain() {
+			add(&v1alpha1.ThirdComponentEndpointStatus{
+				Address: *address,
+				Status:  v1alpha1.EndpointReady,
+			})
+			continue
+		}
+		hostname := address.GetHost()
+		resolved, err := s.resolve(ctx, *address)
+		if err != nil {
+			logrus.Warningf("resolve endpoint %s of component %s/%s failure %s", ep.Address, s.component.Namespace, s.component.Name, err.Error())
+			add(&v1alpha1.ThirdComponentEndpointStatus{
+				Address:  *address,
+				Hostname: hostname,
+				Status:   v1alpha1.EndpointNotReady,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+		for _, ra := range resolved {
+			add(&v1alpha1.ThirdComponentEndpointStatus{
+				Address:  ra,
+				Hostname: hostname,
+				Status:   v1alpha1.EndpointReady,
+			})
+		}
+	}
+	return es, nil
+}
+
+func (s *staticEndpoint) resolve(ctx context.Context, address v1alpha1.EndpointAddress) ([]v1alpha1.EndpointAddress, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	endpoints, err := dns.Lookup(ctx, s.resolver, string(address))
+	if err != nil {
+		return nil, err
+	}
+	var res []v1alpha1.EndpointAddress
+	for _, endpoint := range endpoints {
+		if ea := v1alpha1.NewEndpointAddress(endpoint.IP, endpoint.Port); ea != nil {
+			res = append(res, *ea)
+		}
+	}
+	return res, nil
+}
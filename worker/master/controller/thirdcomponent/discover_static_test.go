@@ -0,0 +1,75 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package thirdcomponent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gridworkz/kato/pkg/apis/kato/v1alpha1"
+	"github.com/gridworkz/kato/util/dns"
+	"github.com/gridworkz/kato/util/dns/dnstest"
+)
+
+func TestStaticEndpointDiscoverOne(t *testing.T) {
+	server, err := dnstest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	server.SetIPs("db.internal.example", "10.0.0.2", "2001:db8::2")
+
+	component := &v1alpha1.ThirdComponent{
+		Spec: v1alpha1.ThirdComponentSpec{
+			EndpointSource: v1alpha1.ThirdComponentEndpointSource{
+				StaticEndpoints: []*v1alpha1.ThirdComponentEndpoint{
+					{Address: "10.0.0.1:8080"},
+					{Address: "[2001:db8::1]:8080"},
+					{Address: "db.internal.example:5432"},
+					{Address: "notfound.example:5432"},
+					{Address: "10.0.0.1"},
+				},
+			},
+		},
+	}
+	discover := &staticEndpoint{
+		component: component,
+		resolver:  dns.NewResolver([]string{server.Addr}),
+	}
+	endpoints, err := discover.DiscoverOne(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []v1alpha1.ThirdComponentEndpointStatus{
+		{Address: "10.0.0.1:8080", Status: v1alpha1.EndpointReady},
+		{Address: "[2001:db8::1]:8080", Status: v1alpha1.EndpointReady},
+		{Address: "10.0.0.2:5432", Hostname: "db.internal.example", Status: v1alpha1.EndpointReady},
+		{Address: "[2001:db8::2]:5432", Hostname: "db.internal.example", Status: v1alpha1.EndpointReady},
+		{Address: "notfound.example:5432", Hostname: "notfound.example", Status: v1alpha1.EndpointNotReady},
+		{Address: "10.0.0.1", Status: v1alpha1.EndpointNotReady},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("want %d endpoints, but got %d", len(want), len(endpoints))
+	}
+	for i, ep := range endpoints {
+		if ep.Address != want[i].Address || ep.Hostname != want[i].Hostname || ep.Status != want[i].Status {
+			t.Errorf("want endpoint %+v, but got %+v", want[i], *ep)
+		}
+	}
+}
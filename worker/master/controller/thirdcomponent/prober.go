@@ -0,0 +1,233 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package thirdcomponent
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gridworkz/kato/pkg/apis/kato/v1alpha1"
+)
+
+type probeResult struct {
+	ready     bool
+	successes int32
+	failures  int32
+	reason    string
+}
+
+// prober probes the endpoints of a third component, and holds the probe results
+// of the endpoints to determine if they are ready.
+type prober struct {
+	lock    sync.Mutex
+	results map[v1alpha1.EndpointAddress]*probeResult
+}
+
+func newProber() *prober {
+	return &prober{
+		results: make(map[v1alpha1.EndpointAddress]*probeResult),
+	}
+}
+
+// Reset clears the probe results.
+func (p *prober) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.results = make(map[v1alpha1.EndpointAddress]*probeResult)
+}
+
+// Apply sets the status of the endpoints by the probe results. The endpoints which are not ready
+// from the source, or have not been probed yet, keep their status.
+func (p *prober) Apply(endpoints []*v1alpha1.ThirdComponentEndpointStatus) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	exists := make(map[v1alpha1.EndpointAddress]bool, len(endpoints))
+	for _, ep := range endpoints {
+		exists[ep.Address] = true
+		result, ok := p.results[ep.Address]
+		if !ok || ep.Status == v1alpha1.EndpointNotReady {
+			continue
+		}
+		if result.ready {
+			ep.Status = v1alpha1.EndpointReady
+			ep.Reason = ""
+		} else {
+			ep.Status = v1alpha1.EndpointNotReady
+			ep.Reason = result.reason
+		}
+	}
+	// the endpoints are removed from the source
+	for address := range p.results {
+		if !exists[address] {
+			delete(p.results, address)
+		}
+	}
+}
+
+// Probe probes the endpoints which are ready from the source, and returns
+// true if any of the endpoints changes its status.
+func (p *prober) Probe(ctx context.Context, probe *v1alpha1.HealthProbe, endpoints []*v1alpha1.ThirdComponentEndpointStatus) bool {
+	var wg sync.WaitGroup
+	errs := make([]error, len(endpoints))
+	for i, ep := range endpoints {
+		// not ready from the source, such as the domain name can not be resolved.
+		if ep.Status != v1alpha1.EndpointReady {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, ep *v1alpha1.ThirdComponentEndpointStatus) {
+			defer wg.Done()
+			errs[i] = probeEndpoint(ctx, probe, ep)
+		}(i, ep)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return false
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var changed bool
+	for i, ep := range endpoints {
+		if ep.Status != v1alpha1.EndpointReady {
+			continue
+		}
+		result, ok := p.results[ep.Address]
+		if !ok {
+			// the endpoint is ready before the first probe
+			result = &probeResult{ready: true}
+			p.results[ep.Address] = result
+		}
+		before := *result
+		if err := errs[i]; err != nil {
+			result.failures++
+			result.successes = 0
+			result.reason = err.Error()
+			if result.failures >= probe.GetFailureThreshold() {
+				result.ready = false
+			}
+		} else {
+			result.successes++
+			result.failures = 0
+			if result.successes >= probe.GetSuccessThreshold() {
+				result.ready = true
+				result.reason = ""
+			}
+		}
+		if before.ready != result.ready || (!result.ready && before.reason != result.reason) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func probeEndpoint(ctx context.Context, probe *v1alpha1.HealthProbe, ep *v1alpha1.ThirdComponentEndpointStatus) error {
+	host, port := ep.Address.GetHost(), ep.Address.GetPort()
+	if host == "" {
+		return fmt.Errorf("invalid address %s", ep.Address)
+	}
+	ctx, cancel := context.WithTimeout(ctx, probe.GetTimeout())
+	defer cancel()
+	if probe.HTTPGet != nil {
+		if probe.HTTPGet.Port > 0 {
+			port = probe.HTTPGet.Port
+		}
+		return httpProbe(ctx, probe.HTTPGet, host, port, ep.Hostname)
+	}
+	if probe.TCPSocket != nil {
+		if probe.TCPSocket.Port > 0 {
+			port = probe.TCPSocket.Port
+		}
+		return tcpProbe(ctx, host, port)
+	}
+	return nil
+}
+
+func tcpProbe(ctx context.Context, host string, port int) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("tcp probe failure: %v", err)
+	}
+	return conn.Close()
+}
+
+// httpProbe sends a GET request to the endpoint. hostname is used as the Host header and
+// the server name of TLS if the endpoint is resolved from a domain name.
+func httpProbe(ctx context.Context, action *v1alpha1.HTTPGetAction, host string, port int, hostname string) error {
+	scheme := "http"
+	if strings.EqualFold(string(action.Scheme), string(v1alpha1.URISchemeHTTPS)) {
+		scheme = "https"
+	}
+	path := action.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := &url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(port))}
+	req, err := http.NewRequest("GET", u.String()+path, nil)
+	if err != nil {
+		return fmt.Errorf("http probe failure: %v", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "kato-probe")
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+	if hostname != "" {
+		req.Host = hostname
+	}
+	if h := req.Header.Get("Host"); h != "" {
+		req.Host = h
+	}
+	serverName := hostname
+	if serverName == "" {
+		serverName = host
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				ServerName:         serverName,
+				InsecureSkipVerify: action.InsecureSkipVerify,
+			},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http probe failure: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 10*1024))
+	if !action.IsExpectedStatus(resp.StatusCode) {
+		return fmt.Errorf("http probe failure: unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package thirdcomponent
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gridworkz/kato/pkg/apis/kato/v1alpha1"
+)
+
+func TestProbeEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "www.example.com" {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/healthz":
+			w.WriteHeader(http.StatusOK)
+		case "/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+	u, _ := url.Parse(server.URL)
+	tlsu, _ := url.Parse(tlsServer.URL)
+	// a closed port
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed := listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name     string
+		probe    *v1alpha1.HealthProbe
+		address  string
+		hostname string
+		wantErr  bool
+	}{
+		{
+			name:     "http",
+			probe:    &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{Path: "/healthz"}},
+			address:  u.Host,
+			hostname: "www.example.com",
+		},
+		{
+			name:     "http with unexpected status",
+			probe:    &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{Path: "/unauthorized"}},
+			address:  u.Host,
+			hostname: "www.example.com",
+			wantErr:  true,
+		},
+		{
+			name: "http with expected status",
+			probe: &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{
+				Path:             "/unauthorized",
+				ExpectedStatuses: []v1alpha1.HTTPStatusRange{{Start: 200, End: 299}, {Start: 401, End: 401}},
+			}},
+			address:  u.Host,
+			hostname: "www.example.com",
+		},
+		{
+			name: "http with host header",
+			probe: &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{
+				Path:        "healthz",
+				HTTPHeaders: []v1alpha1.HTTPHeader{{Name: "Host", Value: "www.example.com"}},
+			}},
+			address: u.Host,
+		},
+		{
+			name:    "https with insecure skip verify",
+			probe:   &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{Scheme: v1alpha1.URISchemeHTTPS, InsecureSkipVerify: true}},
+			address: tlsu.Host,
+		},
+		{
+			name:    "https with unknown authority",
+			probe:   &v1alpha1.HealthProbe{HTTPGet: &v1alpha1.HTTPGetAction{Scheme: v1alpha1.URISchemeHTTPS}},
+			address: tlsu.Host,
+			wantErr: true,
+		},
+		{
+			name:    "tcp",
+			probe:   &v1alpha1.HealthProbe{TCPSocket: &v1alpha1.TCPSocketAction{}},
+			address: u.Host,
+		},
+		{
+			name:    "tcp with closed port",
+			probe:   &v1alpha1.HealthProbe{TCPSocket: &v1alpha1.TCPSocketAction{}},
+			address: closed,
+			wantErr: true,
+		},
+	}
+	for idx := range tests {
+		tc := tests[idx]
+		t.Run(tc.name, func(t *testing.T) {
+			ep := &v1alpha1.ThirdComponentEndpointStatus{
+				Address:  v1alpha1.EndpointAddress(tc.address),
+				Hostname: tc.hostname,
+				Status:   v1alpha1.EndpointReady,
+			}
+			err := probeEndpoint(context.Background(), tc.probe, ep)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("want error: %v, but got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProberThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u, _ := url.Parse(server.URL)
+	probe := &v1alpha1.HealthProbe{
+		TCPSocket:        &v1alpha1.TCPSocketAction{},
+		SuccessThreshold: 2,
+		FailureThreshold: 2,
+	}
+	newEndpoints := func() []*v1alpha1.ThirdComponentEndpointStatus {
+		return []*v1alpha1.ThirdComponentEndpointStatus{
+			{Address: v1alpha1.EndpointAddress(u.Host), Status: v1alpha1.EndpointReady},
+			{Address: "notfound.example:80", Status: v1alpha1.EndpointNotReady, Reason: "no such host"},
+		}
+	}
+	status := func(p *prober) v1alpha1.EndpointStatus {
+		endpoints := newEndpoints()
+		p.Apply(endpoints)
+		if endpoints[1].Status != v1alpha1.EndpointNotReady {
+			t.Fatalf("the endpoint not ready from the source should not be changed")
+		}
+		return endpoints[0].Status
+	}
+
+	p := newProber()
+	if changed := p.Probe(context.Background(), probe, newEndpoints()); changed || status(p) != v1alpha1.EndpointReady {
+		t.Fatalf("want the endpoint ready")
+	}
+	server.Close()
+	if changed := p.Probe(context.Background(), probe, newEndpoints()); changed || status(p) != v1alpha1.EndpointReady {
+		t.Fatalf("want the endpoint ready before reaching the failure threshold")
+	}
+	if changed := p.Probe(context.Background(), probe, newEndpoints()); !changed || status(p) != v1alpha1.EndpointNotReady {
+		t.Fatalf("want the endpoint not ready after reaching the failure threshold")
+	}
+
+	p.Apply(nil)
+	if len(p.results) != 0 {
+		t.Errorf("want the probe results of the removed endpoints to be cleaned")
+	}
+}
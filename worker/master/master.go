@@ -30,6 +30,7 @@ import (
 	"github.com/gridworkz/kato/util/leader"
 	"github.com/gridworkz/kato/worker/appm/store"
 	"github.com/gridworkz/kato/worker/master/podevent"
+	"github.com/gridworkz/kato/worker/master/scheduledscaling"
 	"github.com/gridworkz/kato/worker/master/volumes/provider"
 	"github.com/gridworkz/kato/worker/master/volumes/provider/lib/controller"
 	"github.com/gridworkz/kato/worker/master/volumes/statistical"
@@ -64,6 +65,7 @@ type Controller struct {
 	stopCh          chan struct{}
 	podEvent * podevent.PodEvent
 	volumeTypeEvent *sync.VolumeTypeEvent
+	scheduledScaling *scheduledscaling.Controller
 
 	version      *version.Info
 	katosssc controller.Provisions
@@ -149,6 +151,7 @@ func NewMasterController(conf option.Config, kubecfg *rest.Config, store store.S
 		diskCache:       statistical.CreatDiskCache(ctx),
 		podEvent:        podevent.New(conf.KubeClient, stopCh),
 		volumeTypeEvent: sync.New(stopCh),
+		scheduledScaling: scheduledscaling.New(kubeClient, store),
 		kubeClient: kubeClient,
 		katosssc:    katossscProvisioner,
 		katosslc:    katosslcProvisioner,
@@ -183,6 +186,7 @@ func (m *Controller) Start() error {
 		m.store.RegisterVolumeTypeListener("volumeTypeEvent", m.volumeTypeEvent.GetChan())
 		defer m.store.UnRegisterVolumeTypeListener("volumeTypeEvent")
 		go m.volumeTypeEvent.Handle()
+		go m.scheduledScaling.Run(ctx)
 
 		select {
 		case <-ctx.Done():
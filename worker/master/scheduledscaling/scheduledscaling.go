@@ -0,0 +1,226 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package scheduledscaling
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util"
+	"github.com/gridworkz/kato/worker/appm/conversion"
+	"github.com/gridworkz/kato/worker/appm/store"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	workerutil "github.com/gridworkz/kato/worker/util"
+)
+
+// defaultSyncPeriod the period of checking the scheduled scaling policies.
+// cron expressions are accurate to the minute.
+const defaultSyncPeriod = 30 * time.Second
+
+// Controller executes the scheduled scaling policies.
+// During the window of a policy, it keeps the replicas of the component in [MinReplicas, MaxReplicas].
+// For the components with HPAs, it adjusts the min and max replicas of the HPAs instead of the replicas.
+type Controller struct {
+	kubeClient kubernetes.Interface
+	store      store.Storer
+	dbmanager  db.Manager
+	// managed the components which have scheduled scaling policies,
+	// used to restore the components after their policies are disabled or deleted.
+	managed map[string]struct{}
+}
+
+// New creates a new scheduled scaling controller.
+func New(kubeClient kubernetes.Interface, store store.Storer) *Controller {
+	return &Controller{
+		kubeClient: kubeClient,
+		store:      store,
+		dbmanager:  db.GetManager(),
+		managed:    make(map[string]struct{}),
+	}
+}
+
+// Run runs the controller until the ctx is done.
+func (c *Controller) Run(ctx context.Context) {
+	logrus.Info("start scheduled scaling controller")
+	ticker := time.NewTicker(defaultSyncPeriod)
+	defer ticker.Stop()
+	for {
+		c.sync(time.Now())
+		select {
+		case <-ctx.Done():
+			logrus.Info("stop scheduled scaling controller")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Controller) sync(now time.Time) {
+	policies, err := c.dbmanager.TenantServiceScheduledScalingPolicyDao().ListEnableOnes()
+	if err != nil {
+		logrus.Errorf("list scheduled scaling policies: %v", err)
+		return
+	}
+	servicePolicies := make(map[string][]*model.TenantServiceScheduledScalingPolicy)
+	for _, policy := range policies {
+		servicePolicies[policy.ServiceID] = append(servicePolicies[policy.ServiceID], policy)
+	}
+	for serviceID := range c.managed {
+		// the policies of the service have been disabled or deleted, restore it.
+		if _, ok := servicePolicies[serviceID]; !ok {
+			servicePolicies[serviceID] = nil
+		}
+	}
+
+	for serviceID, policies := range servicePolicies {
+		bounds, err := workerutil.ActiveScalingBounds(policies, now)
+		if err != nil {
+			logrus.Warningf("service id: %s; active scaling bounds: %v", serviceID, err)
+			continue
+		}
+		if err := c.apply(serviceID, bounds); err != nil {
+			logrus.Warningf("service id: %s; apply scheduled scaling bounds %s: %v", serviceID, bounds.String(), err)
+			continue
+		}
+		if len(policies) == 0 {
+			delete(c.managed, serviceID)
+			continue
+		}
+		c.managed[serviceID] = struct{}{}
+	}
+}
+
+func (c *Controller) apply(serviceID string, bounds *workerutil.ScalingBounds) error {
+	as := c.store.GetAppService(serviceID)
+	if as == nil || as.IsClosed() {
+		return nil
+	}
+	if hpas := as.GetHPAs(); len(hpas) > 0 {
+		return c.applyToHPAs(as, hpas, bounds)
+	}
+	return c.applyToReplicas(as, bounds)
+}
+
+func (c *Controller) applyToHPAs(as *v1.AppService, hpas []*autoscalingv2.HorizontalPodAutoscaler, bounds *workerutil.ScalingBounds) error {
+	for _, hpa := range hpas {
+		rule, err := c.dbmanager.TenantServceAutoscalerRulesDao().GetByRuleID(hpa.GetName())
+		if err != nil {
+			return fmt.Errorf("get autoscaler rule %s: %v", hpa.GetName(), err)
+		}
+		newHPA := hpa.DeepCopy()
+		conversion.ApplyScalingBoundsToHPA(newHPA, rule, bounds)
+		// the min replicas of HPA defaults to 1
+		oldMin, newMin := int32(1), *newHPA.Spec.MinReplicas
+		if hpa.Spec.MinReplicas != nil {
+			oldMin = *hpa.Spec.MinReplicas
+		}
+		oldMax, newMax := hpa.Spec.MaxReplicas, newHPA.Spec.MaxReplicas
+		if oldMin == newMin && oldMax == newMax {
+			continue
+		}
+
+		_, err = c.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(newHPA.Namespace).Update(newHPA)
+		desc := fmt.Sprintf("the replicas range of HPA %s is changed from [%d, %d] to [%d, %d]", rule.RuleID, oldMin, oldMax, newMin, newMax)
+		c.record(as.ServiceID, bounds, desc, err)
+		if err != nil {
+			return fmt.Errorf("update hpa %s: %v", hpa.GetName(), err)
+		}
+		as.SetHPA(newHPA)
+	}
+	return nil
+}
+
+func (c *Controller) applyToReplicas(as *v1.AppService, bounds *workerutil.ScalingBounds) error {
+	service, err := c.dbmanager.TenantServiceDao().GetServiceByID(as.ServiceID)
+	if err != nil {
+		return fmt.Errorf("get service: %v", err)
+	}
+	// the replicas set by the user is the baseline, which will be restored after the window.
+	replicas := bounds.Clamp(service.Replicas)
+
+	var current int32
+	var patch func() error
+	patchData := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	if statefulset := as.GetStatefulSet(); statefulset != nil && statefulset.Spec.Replicas != nil {
+		current = *statefulset.Spec.Replicas
+		patch = func() error {
+			_, err := c.kubeClient.AppsV1().StatefulSets(statefulset.Namespace).Patch(statefulset.Name, types.StrategicMergePatchType, patchData)
+			return err
+		}
+	}
+	if deployment := as.GetDeployment(); deployment != nil && deployment.Spec.Replicas != nil {
+		current = *deployment.Spec.Replicas
+		patch = func() error {
+			_, err := c.kubeClient.AppsV1().Deployments(deployment.Namespace).Patch(deployment.Name, types.StrategicMergePatchType, patchData)
+			return err
+		}
+	}
+	if patch == nil || int(current) == replicas {
+		return nil
+	}
+
+	err = patch()
+	desc := fmt.Sprintf("the replicas is scaling from %d to %d", current, replicas)
+	c.record(as.ServiceID, bounds, desc, err)
+	if err != nil {
+		return fmt.Errorf("patch replicas: %v", err)
+	}
+	return nil
+}
+
+// record writes the scaling action into the scaling records.
+func (c *Controller) record(serviceID string, bounds *workerutil.ScalingBounds, desc string, err error) {
+	reason := "SuccessfulRescale"
+	if bounds == nil {
+		desc += ", the scheduled scaling window is over"
+	} else {
+		desc += fmt.Sprintf(", according to the scheduled scaling policies(%s)", strings.Join(bounds.PolicyIDs, ","))
+	}
+	if err != nil {
+		reason = "FailedRescale"
+		desc = fmt.Sprintf("%s: %v", desc, err)
+	}
+	var ruleID string
+	if bounds != nil {
+		ruleID = strings.Join(bounds.PolicyIDs, ",")
+	}
+	record := &model.TenantServiceScalingRecords{
+		ServiceID:   serviceID,
+		RuleID:      ruleID,
+		EventName:   util.NewUUID(),
+		RecordType:  model.ScheduledScalingRecordType,
+		Reason:      reason,
+		Count:       1,
+		Description: desc,
+		Operator:    "system",
+		LastTime:    time.Now(),
+	}
+	if err := c.dbmanager.TenantServiceScalingRecordsDao().AddModel(record); err != nil {
+		logrus.Warningf("save scaling record: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+// KATO, Application Management Platform
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package scheduledscaling
+
+import (
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/db/dao"
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	workerutil "github.com/gridworkz/kato/worker/util"
+)
+
+type fakeDBManager struct {
+	db.Manager
+	rules   *fakeRulesDao
+	records *fakeRecordsDao
+}
+
+func (m *fakeDBManager) TenantServceAutoscalerRulesDao() dao.TenantServceAutoscalerRulesDao {
+	return m.rules
+}
+
+func (m *fakeDBManager) TenantServiceScalingRecordsDao() dao.TenantServiceScalingRecordsDao {
+	return m.records
+}
+
+type fakeRulesDao struct {
+	dao.TenantServceAutoscalerRulesDao
+	rules map[string]*model.TenantServiceAutoscalerRules
+}
+
+func (d *fakeRulesDao) GetByRuleID(ruleID string) (*model.TenantServiceAutoscalerRules, error) {
+	return d.rules[ruleID], nil
+}
+
+type fakeRecordsDao struct {
+	dao.TenantServiceScalingRecordsDao
+	records []*model.TenantServiceScalingRecords
+}
+
+func (d *fakeRecordsDao) AddModel(m model.Interface) error {
+	d.records = append(d.records, m.(*model.TenantServiceScalingRecords))
+	return nil
+}
+
+func TestApplyToHPAs(t *testing.T) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule1", Namespace: "tenant1"},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: util.Int32(2), MaxReplicas: 5},
+	}
+	// the min replicas is not set, which defaults to 1
+	unsetHPA := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "rule2", Namespace: "tenant1"},
+		Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MaxReplicas: 4},
+	}
+	kubeClient := fake.NewSimpleClientset(hpa, unsetHPA)
+	records := &fakeRecordsDao{}
+	c := &Controller{
+		kubeClient: kubeClient,
+		dbmanager: &fakeDBManager{
+			rules: &fakeRulesDao{rules: map[string]*model.TenantServiceAutoscalerRules{
+				"rule1": {RuleID: "rule1", MinReplicas: 2, MaxReplicas: 5},
+				"rule2": {RuleID: "rule2", MinReplicas: 1, MaxReplicas: 4},
+			}},
+			records: records,
+		},
+		managed: make(map[string]struct{}),
+	}
+	as := &v1.AppService{}
+	as.ServiceID = "service1"
+	as.SetHPAs([]*autoscalingv2.HorizontalPodAutoscaler{hpa, unsetHPA})
+
+	tests := []struct {
+		name             string
+		bounds           *workerutil.ScalingBounds
+		wantMin, wantMax int32
+		wantRecords      int
+	}{
+		{name: "floor above max", bounds: &workerutil.ScalingBounds{MinReplicas: 8, MaxReplicas: 10, PolicyIDs: []string{"p1"}}, wantMin: 8, wantMax: 8, wantRecords: 2},
+		{name: "ceiling below max", bounds: &workerutil.ScalingBounds{MinReplicas: 1, MaxReplicas: 3, PolicyIDs: []string{"p2"}}, wantMin: 2, wantMax: 3, wantRecords: 4},
+		{name: "window is over", wantMin: 2, wantMax: 5, wantRecords: 6},
+		{name: "nothing changed", wantMin: 2, wantMax: 5, wantRecords: 6},
+	}
+	for _, tc := range tests {
+		if err := c.applyToHPAs(as, as.GetHPAs(), tc.bounds); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, err := kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers("tenant1").Get("rule1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if *got.Spec.MinReplicas != tc.wantMin || got.Spec.MaxReplicas != tc.wantMax {
+			t.Errorf("%s: want [%d, %d], got [%d, %d]", tc.name, tc.wantMin, tc.wantMax, *got.Spec.MinReplicas, got.Spec.MaxReplicas)
+		}
+		for _, h := range as.GetHPAs() {
+			if *h.Spec.MinReplicas > h.Spec.MaxReplicas {
+				t.Errorf("%s: the min replicas of hpa %s is greater than the max replicas", tc.name, h.GetName())
+			}
+		}
+		if len(records.records) != tc.wantRecords {
+			t.Errorf("%s: want %d scaling records, got %d", tc.name, tc.wantRecords, len(records.records))
+		}
+	}
+}
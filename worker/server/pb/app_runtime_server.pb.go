@@ -49,6 +49,38 @@ func (ServiceVolumeStatus) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_f94cf1a886c479d6, []int{0}
 }
 
+type WatchEventType int32
+
+const (
+	// RESET tells the watcher to drop everything it knows, the current state follows.
+	WatchEventType_RESET    WatchEventType = 0
+	WatchEventType_ADDED    WatchEventType = 1
+	WatchEventType_MODIFIED WatchEventType = 2
+	WatchEventType_DELETED  WatchEventType = 3
+)
+
+var WatchEventType_name = map[int32]string{
+	0: "RESET",
+	1: "ADDED",
+	2: "MODIFIED",
+	3: "DELETED",
+}
+
+var WatchEventType_value = map[string]int32{
+	"RESET":    0,
+	"ADDED":    1,
+	"MODIFIED": 2,
+	"DELETED":  3,
+}
+
+func (x WatchEventType) String() string {
+	return proto.EnumName(WatchEventType_name, int32(x))
+}
+
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f94cf1a886c479d6, []int{1}
+}
+
 type PodStatus_Type int32
 
 const (
@@ -1950,8 +1982,253 @@ func (m *AppStatus) GetMemory() int64 {
 	return 0
 }
 
+type WatchAppStatusReq struct {
+	AppId                string   `protobuf:"bytes,1,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	ServiceIds           []string `protobuf:"bytes,2,rep,name=service_ids,json=serviceIds,proto3" json:"service_ids,omitempty"`
+	ResumeToken          string   `protobuf:"bytes,3,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchAppStatusReq) Reset()         { *m = WatchAppStatusReq{} }
+func (m *WatchAppStatusReq) String() string { return proto.CompactTextString(m) }
+func (*WatchAppStatusReq) ProtoMessage()    {}
+func (*WatchAppStatusReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f94cf1a886c479d6, []int{31}
+}
+
+func (m *WatchAppStatusReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchAppStatusReq.Unmarshal(m, b)
+}
+func (m *WatchAppStatusReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchAppStatusReq.Marshal(b, m, deterministic)
+}
+func (m *WatchAppStatusReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchAppStatusReq.Merge(m, src)
+}
+func (m *WatchAppStatusReq) XXX_Size() int {
+	return xxx_messageInfo_WatchAppStatusReq.Size(m)
+}
+func (m *WatchAppStatusReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchAppStatusReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchAppStatusReq proto.InternalMessageInfo
+
+func (m *WatchAppStatusReq) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *WatchAppStatusReq) GetServiceIds() []string {
+	if m != nil {
+		return m.ServiceIds
+	}
+	return nil
+}
+
+func (m *WatchAppStatusReq) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+type AppStatusEvent struct {
+	Type                 WatchEventType   `protobuf:"varint,1,opt,name=type,proto3,enum=pb.WatchEventType" json:"type,omitempty"`
+	ServiceId            string           `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Status               string           `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	AppStatus            AppStatus_Status `protobuf:"varint,4,opt,name=app_status,json=appStatus,proto3,enum=pb.AppStatus_Status" json:"app_status,omitempty"`
+	ResumeToken          string           `protobuf:"bytes,5,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *AppStatusEvent) Reset()         { *m = AppStatusEvent{} }
+func (m *AppStatusEvent) String() string { return proto.CompactTextString(m) }
+func (*AppStatusEvent) ProtoMessage()    {}
+func (*AppStatusEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f94cf1a886c479d6, []int{32}
+}
+
+func (m *AppStatusEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppStatusEvent.Unmarshal(m, b)
+}
+func (m *AppStatusEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppStatusEvent.Marshal(b, m, deterministic)
+}
+func (m *AppStatusEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppStatusEvent.Merge(m, src)
+}
+func (m *AppStatusEvent) XXX_Size() int {
+	return xxx_messageInfo_AppStatusEvent.Size(m)
+}
+func (m *AppStatusEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppStatusEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppStatusEvent proto.InternalMessageInfo
+
+func (m *AppStatusEvent) GetType() WatchEventType {
+	if m != nil {
+		return m.Type
+	}
+	return WatchEventType_RESET
+}
+
+func (m *AppStatusEvent) GetServiceId() string {
+	if m != nil {
+		return m.ServiceId
+	}
+	return ""
+}
+
+func (m *AppStatusEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *AppStatusEvent) GetAppStatus() AppStatus_Status {
+	if m != nil {
+		return m.AppStatus
+	}
+	return AppStatus_NIL
+}
+
+func (m *AppStatusEvent) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+type WatchPodsReq struct {
+	ServiceIds           []string `protobuf:"bytes,1,rep,name=service_ids,json=serviceIds,proto3" json:"service_ids,omitempty"`
+	ResumeToken          string   `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchPodsReq) Reset()         { *m = WatchPodsReq{} }
+func (m *WatchPodsReq) String() string { return proto.CompactTextString(m) }
+func (*WatchPodsReq) ProtoMessage()    {}
+func (*WatchPodsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f94cf1a886c479d6, []int{33}
+}
+
+func (m *WatchPodsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchPodsReq.Unmarshal(m, b)
+}
+func (m *WatchPodsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchPodsReq.Marshal(b, m, deterministic)
+}
+func (m *WatchPodsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchPodsReq.Merge(m, src)
+}
+func (m *WatchPodsReq) XXX_Size() int {
+	return xxx_messageInfo_WatchPodsReq.Size(m)
+}
+func (m *WatchPodsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchPodsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchPodsReq proto.InternalMessageInfo
+
+func (m *WatchPodsReq) GetServiceIds() []string {
+	if m != nil {
+		return m.ServiceIds
+	}
+	return nil
+}
+
+func (m *WatchPodsReq) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
+type PodWatchEvent struct {
+	Type                 WatchEventType `protobuf:"varint,1,opt,name=type,proto3,enum=pb.WatchEventType" json:"type,omitempty"`
+	ServiceId            string         `protobuf:"bytes,2,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	Pod                  *ServiceAppPod `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
+	NewVersion           bool           `protobuf:"varint,4,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"`
+	ResumeToken          string         `protobuf:"bytes,5,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PodWatchEvent) Reset()         { *m = PodWatchEvent{} }
+func (m *PodWatchEvent) String() string { return proto.CompactTextString(m) }
+func (*PodWatchEvent) ProtoMessage()    {}
+func (*PodWatchEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f94cf1a886c479d6, []int{34}
+}
+
+func (m *PodWatchEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodWatchEvent.Unmarshal(m, b)
+}
+func (m *PodWatchEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodWatchEvent.Marshal(b, m, deterministic)
+}
+func (m *PodWatchEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodWatchEvent.Merge(m, src)
+}
+func (m *PodWatchEvent) XXX_Size() int {
+	return xxx_messageInfo_PodWatchEvent.Size(m)
+}
+func (m *PodWatchEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodWatchEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodWatchEvent proto.InternalMessageInfo
+
+func (m *PodWatchEvent) GetType() WatchEventType {
+	if m != nil {
+		return m.Type
+	}
+	return WatchEventType_RESET
+}
+
+func (m *PodWatchEvent) GetServiceId() string {
+	if m != nil {
+		return m.ServiceId
+	}
+	return ""
+}
+
+func (m *PodWatchEvent) GetPod() *ServiceAppPod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *PodWatchEvent) GetNewVersion() bool {
+	if m != nil {
+		return m.NewVersion
+	}
+	return false
+}
+
+func (m *PodWatchEvent) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("pb.ServiceVolumeStatus", ServiceVolumeStatus_name, ServiceVolumeStatus_value)
+	proto.RegisterEnum("pb.WatchEventType", WatchEventType_name, WatchEventType_value)
 	proto.RegisterEnum("pb.PodStatus_Type", PodStatus_Type_name, PodStatus_Type_value)
 	proto.RegisterEnum("pb.AppStatus_Status", AppStatus_Status_name, AppStatus_Status_value)
 	proto.RegisterType((*Empty)(nil), "pb.Empty")
@@ -1998,6 +2275,10 @@ func init() {
 	proto.RegisterType((*ServiceVolumeStatusMessage)(nil), "pb.ServiceVolumeStatusMessage")
 	proto.RegisterMapType((map[string]ServiceVolumeStatus)(nil), "pb.ServiceVolumeStatusMessage.StatusEntry")
 	proto.RegisterType((*AppStatus)(nil), "pb.AppStatus")
+	proto.RegisterType((*WatchAppStatusReq)(nil), "pb.WatchAppStatusReq")
+	proto.RegisterType((*AppStatusEvent)(nil), "pb.AppStatusEvent")
+	proto.RegisterType((*WatchPodsReq)(nil), "pb.WatchPodsReq")
+	proto.RegisterType((*PodWatchEvent)(nil), "pb.PodWatchEvent")
 }
 
 func init() {
@@ -2005,146 +2286,159 @@ func init() {
 }
 
 var fileDescriptor_f94cf1a886c479d6 = []byte{
-	// 2217 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x4b, 0x73, 0x1b, 0xc7,
-	0x11, 0x26, 0x00, 0xe2, 0xd5, 0x20, 0x40, 0x70, 0x24, 0x52, 0x30, 0x6c, 0x59, 0xf2, 0x46, 0x52,
-	0xa9, 0x24, 0x05, 0xb6, 0x19, 0xab, 0x2c, 0xd9, 0x8a, 0x53, 0x20, 0x00, 0x53, 0x48, 0x40, 0x10,
-	0xb5, 0x00, 0x93, 0x72, 0x55, 0xaa, 0x50, 0x4b, 0xec, 0x98, 0xde, 0x68, 0x1f, 0xa3, 0x7d, 0xd0,
-	0xc1, 0x31, 0xb9, 0xe5, 0x9c, 0x9f, 0x90, 0x43, 0x0e, 0xc9, 0x31, 0x97, 0x5c, 0xfc, 0x17, 0xf2,
-	0x53, 0x7c, 0xc9, 0x0f, 0x48, 0xf5, 0xcc, 0xec, 0x13, 0x4b, 0x33, 0x4c, 0x25, 0x95, 0xdb, 0x4e,
-	0x4f, 0x7f, 0xd3, 0x3d, 0x3d, 0x3d, 0xfd, 0x98, 0x85, 0x8e, 0xc6, 0xd8, 0xd2, 0x0d, 0x6c, 0xdf,
-	0xb0, 0xe8, 0xd2, 0xa3, 0xee, 0x25, 0x75, 0x7b, 0xcc, 0x75, 0x7c, 0x87, 0x14, 0xd9, 0xb9, 0x52,
-	0x85, 0xf2, 0xc8, 0x62, 0xfe, 0x5a, 0x79, 0x08, 0x3b, 0x7d, 0xc6, 0xe6, 0xbe, 0xe6, 0x07, 0x9e,
-	0x4a, 0xdf, 0x92, 0x7d, 0xa8, 0x20, 0xd0, 0xd0, 0x3b, 0x85, 0xfb, 0x85, 0xc7, 0x75, 0xb5, 0xac,
-	0x31, 0x36, 0xd6, 0x95, 0x0f, 0xa1, 0x35, 0xa7, 0xee, 0xa5, 0xb1, 0xa2, 0x2a, 0x7d, 0x1b, 0x50,
-	0xcf, 0x27, 0x77, 0x01, 0x3c, 0x41, 0x89, 0x99, 0xeb, 0x92, 0x32, 0xd6, 0x95, 0x43, 0xd8, 0x95,
-	0x00, 0x2f, 0x44, 0xdc, 0x83, 0x46, 0x8c, 0xf0, 0x24, 0x04, 0x22, 0x88, 0xa7, 0x3c, 0x83, 0xe6,
-	0x82, 0xda, 0x9a, 0xed, 0x87, 0x88, 0x77, 0xa1, 0xee, 0x73, 0x42, 0x2c, 0xa2, 0x26, 0x08, 0x63,
-	0x5d, 0xf9, 0x5d, 0x01, 0x9a, 0x42, 0xef, 0x13, 0xea, 0x79, 0xda, 0x05, 0x25, 0xcf, 0xa1, 0xe2,
-	0x71, 0x42, 0xa7, 0x70, 0xbf, 0xf4, 0xb8, 0x71, 0x78, 0xb7, 0xc7, 0xce, 0x7b, 0x29, 0x16, 0x39,
-	0x1a, 0xd9, 0xbe, 0xbb, 0x56, 0x25, 0x73, 0xf7, 0x25, 0x34, 0x12, 0x64, 0xd2, 0x86, 0xd2, 0x1b,
-	0xba, 0x96, 0xe2, 0xf0, 0x93, 0xdc, 0x86, 0xf2, 0xa5, 0x66, 0x06, 0xb4, 0x53, 0x14, 0x26, 0xe1,
-	0x83, 0xcf, 0x8a, 0x2f, 0x0a, 0xca, 0x1a, 0x1a, 0x43, 0xc3, 0x7b, 0x13, 0x2a, 0xf0, 0x11, 0x94,
-	0x75, 0xc3, 0x7b, 0x13, 0xca, 0xef, 0xa2, 0xfc, 0xc4, 0x3c, 0xff, 0x96, 0xc2, 0x05, 0x63, 0xf7,
-	0x05, 0x40, 0x4c, 0xbc, 0x4e, 0x74, 0x21, 0x29, 0xda, 0x82, 0x3d, 0x69, 0xe0, 0x3e, 0x63, 0x33,
-	0x47, 0x9f, 0x18, 0x9e, 0x4f, 0x9e, 0x42, 0xd5, 0x31, 0xf5, 0x99, 0xa3, 0x87, 0x2a, 0xec, 0x71,
-	0x13, 0x24, 0xf9, 0xd4, 0x90, 0x03, 0x99, 0x6d, 0xfa, 0x2d, 0x67, 0x2e, 0x5e, 0xc9, 0x2c, 0x39,
-	0x94, 0xef, 0x0a, 0x70, 0x70, 0x12, 0x98, 0xbe, 0xb1, 0x29, 0xf4, 0x24, 0x3a, 0xd7, 0x84, 0xe0,
-	0xa7, 0xb8, 0x56, 0x3e, 0x20, 0x14, 0x81, 0xdc, 0xc2, 0x18, 0x49, 0x7c, 0xf7, 0x0c, 0xda, 0x59,
-	0x86, 0x1c, 0xc3, 0x3c, 0x4d, 0x1a, 0xa6, 0x71, 0xb8, 0xbf, 0xa1, 0x3a, 0x4a, 0x4a, 0xda, 0xeb,
-	0xfb, 0x22, 0x34, 0x53, 0x0c, 0xd7, 0x78, 0x30, 0x3a, 0x9f, 0x4e, 0x99, 0xe9, 0xac, 0x71, 0x56,
-	0x9c, 0x7c, 0x4d, 0x10, 0xc6, 0x3a, 0xfa, 0xb2, 0x9c, 0xf4, 0xd7, 0x8c, 0x76, 0x4a, 0xc2, 0x97,
-	0x05, 0x69, 0xb1, 0x66, 0x94, 0xbc, 0x03, 0x35, 0xe6, 0xe8, 0x4b, 0x5b, 0xb3, 0x68, 0x67, 0x9b,
-	0xcf, 0x56, 0x99, 0xa3, 0x4f, 0x35, 0x8b, 0xe2, 0x15, 0xc3, 0x29, 0x83, 0x75, 0xca, 0xc2, 0x9f,
-	0x98, 0xa3, 0x8f, 0x19, 0xaa, 0x83, 0x64, 0xe9, 0xc1, 0x15, 0xa1, 0x0e, 0x73, 0x74, 0xe1, 0x9b,
-	0xa4, 0x0f, 0xb0, 0x72, 0x6c, 0x5f, 0x33, 0x6c, 0xea, 0x7a, 0x9d, 0x2a, 0x37, 0xf2, 0x07, 0x1b,
-	0xbb, 0xee, 0x0d, 0x22, 0x1e, 0x61, 0xda, 0x04, 0x08, 0x95, 0x46, 0x09, 0x97, 0x8e, 0x19, 0x58,
-	0xd4, 0xeb, 0xd4, 0xee, 0x97, 0x50, 0x69, 0xe6, 0xe8, 0xbf, 0x14, 0x94, 0xee, 0x04, 0x76, 0x33,
-	0xf8, 0x1c, 0xcb, 0xff, 0x28, 0x6d, 0xf9, 0x26, 0xea, 0x10, 0xa1, 0x92, 0x16, 0xbf, 0x84, 0x7a,
-	0x44, 0x27, 0x0f, 0xa1, 0x15, 0x69, 0x22, 0xac, 0x22, 0x96, 0x6c, 0x46, 0x54, 0x6e, 0x9b, 0x0f,
-	0x60, 0xc7, 0xa2, 0x96, 0xe3, 0xae, 0x97, 0xa6, 0x61, 0x19, 0x3e, 0x97, 0x51, 0x52, 0x1b, 0x82,
-	0x36, 0x41, 0x12, 0xee, 0x62, 0xc5, 0x82, 0xa5, 0x2b, 0x62, 0x04, 0x37, 0x7d, 0x49, 0x85, 0x15,
-	0x0b, 0x64, 0xd4, 0x50, 0xbe, 0xaf, 0x00, 0x0c, 0xc5, 0x41, 0xd9, 0x5f, 0x3b, 0xe4, 0x3d, 0xa8,
-	0xa3, 0x3c, 0x8f, 0x69, 0xab, 0x50, 0x68, 0x4c, 0x20, 0x0a, 0xec, 0xa0, 0xc5, 0xe9, 0xd7, 0x81,
-	0x49, 0x3d, 0xea, 0xcb, 0x83, 0x4e, 0xd1, 0xc8, 0xfb, 0x20, 0x4f, 0xd6, 0xa2, 0xb6, 0x9f, 0x3e,
-	0x6b, 0xa4, 0x70, 0x47, 0xf2, 0x35, 0xd7, 0x5f, 0x62, 0xac, 0x95, 0xa7, 0x5d, 0xe7, 0x94, 0x85,
-	0x61, 0x51, 0xf2, 0x0c, 0xb6, 0x19, 0x5e, 0x8c, 0x32, 0x3f, 0xb3, 0x0e, 0x0f, 0x0a, 0x91, 0x7a,
-	0xbd, 0xf8, 0x16, 0x70, 0x2e, 0xf2, 0x02, 0x6a, 0xd2, 0x07, 0xd1, 0x09, 0x10, 0xf1, 0x5e, 0x06,
-	0x11, 0xc6, 0x55, 0x81, 0x8a, 0xb8, 0xc9, 0xe7, 0x50, 0xa7, 0xb6, 0xce, 0x1c, 0xc3, 0xf6, 0x43,
-	0x07, 0xb9, 0x9b, 0x81, 0x8e, 0xc2, 0x79, 0x81, 0x8d, 0xf9, 0xc9, 0x73, 0xa8, 0x7a, 0x74, 0xe5,
-	0x52, 0x5f, 0xf8, 0x45, 0xe3, 0xf0, 0xdd, 0x0d, 0xa9, 0x7c, 0x56, 0x00, 0x43, 0x5e, 0x94, 0x69,
-	0xd8, 0x17, 0x2e, 0xf5, 0x3c, 0xea, 0x75, 0xea, 0xb9, 0x32, 0xc7, 0xe1, 0xbc, 0x94, 0x19, 0xf1,
-	0x93, 0x3e, 0x34, 0x5c, 0xca, 0x4c, 0x63, 0xa5, 0xf9, 0x68, 0x7a, 0xe0, 0xf0, 0x7b, 0x19, 0xb8,
-	0x1a, 0x73, 0xc8, 0x60, 0x91, 0xc0, 0x90, 0x83, 0x28, 0xe4, 0x37, 0xb8, 0xd9, 0xc3, 0x98, 0xfe,
-	0x29, 0xd4, 0x7f, 0x28, 0x7a, 0x5c, 0x19, 0xd1, 0xbb, 0x9f, 0x47, 0x51, 0xe2, 0x3f, 0x00, 0xbf,
-	0x82, 0x56, 0xda, 0xc2, 0x37, 0x42, 0x7f, 0x06, 0x3b, 0x49, 0x23, 0xdf, 0x54, 0x72, 0xda, 0xce,
-	0x37, 0x42, 0x7f, 0x01, 0xed, 0xac, 0x99, 0x6f, 0x94, 0x06, 0xff, 0x5a, 0x84, 0x56, 0x98, 0xb9,
-	0x3d, 0x27, 0x70, 0x57, 0x34, 0x7b, 0x4b, 0x0b, 0xd9, 0x5b, 0x8a, 0xe1, 0x15, 0x19, 0x92, 0xd7,
-	0xbc, 0xb6, 0x62, 0x81, 0xb8, 0xe3, 0x0f, 0xa1, 0x25, 0xc3, 0x40, 0xfa, 0x9a, 0x37, 0x05, 0x35,
-	0x5c, 0x23, 0x1b, 0x2d, 0xb6, 0x37, 0xa3, 0xc5, 0x23, 0xd8, 0x75, 0x03, 0xdb, 0x36, 0xec, 0x8b,
-	0x25, 0xd6, 0x35, 0x76, 0x60, 0xf1, 0xa8, 0x5b, 0x52, 0x9b, 0x92, 0xdc, 0x67, 0x6c, 0x1a, 0x58,
-	0xe4, 0x63, 0xd8, 0x4f, 0xf2, 0xf9, 0xdf, 0x18, 0xae, 0xce, 0xb9, 0x81, 0x73, 0x93, 0x98, 0x7b,
-	0x81, 0x53, 0x08, 0xf9, 0x14, 0x3a, 0x49, 0x88, 0x61, 0xfb, 0xd4, 0xb5, 0x35, 0x93, 0xa3, 0x1a,
-	0x1c, 0xb5, 0x1f, 0xa3, 0xc6, 0x72, 0x76, 0x1a, 0x58, 0xca, 0x5f, 0x0a, 0x40, 0xd2, 0xe6, 0xe2,
-	0x79, 0x74, 0x00, 0x75, 0x57, 0x8e, 0xc3, 0x2c, 0xfa, 0x10, 0x2f, 0xc3, 0x26, 0x6b, 0x2f, 0x1c,
-	0x84, 0x77, 0x2a, 0xc2, 0x75, 0x67, 0xd0, 0x4a, 0x4f, 0xe6, 0x1c, 0xe4, 0xe3, 0x74, 0x04, 0x27,
-	0x9b, 0x42, 0x92, 0x87, 0xfb, 0xfb, 0x02, 0xbc, 0xd3, 0xd7, 0x75, 0xbe, 0xed, 0x99, 0xe6, 0xfa,
-	0xeb, 0xc8, 0xc5, 0xb1, 0x5e, 0x24, 0xb0, 0x1d, 0x04, 0x51, 0xfa, 0xe4, 0xdf, 0x28, 0xd1, 0x8b,
-	0x72, 0x26, 0x7e, 0x92, 0x16, 0x14, 0x0d, 0x26, 0x23, 0x67, 0xd1, 0x60, 0x88, 0x62, 0x8e, 0x2b,
-	0x0e, 0xac, 0xac, 0xf2, 0x6f, 0x74, 0x08, 0xc3, 0x5b, 0x3a, 0xb6, 0x69, 0xd8, 0x94, 0x9f, 0x51,
-	0x4d, 0xad, 0x19, 0xde, 0x29, 0x1f, 0x73, 0x25, 0xce, 0xd8, 0xff, 0x59, 0x09, 0x0a, 0xef, 0x0c,
-	0xa9, 0xf9, 0xbf, 0xd6, 0x41, 0xf9, 0x23, 0xba, 0xc7, 0x86, 0x90, 0xff, 0xe2, 0x26, 0xe3, 0xa0,
-	0x59, 0x4e, 0x06, 0xcd, 0xf4, 0xe6, 0x2b, 0x99, 0xcd, 0xff, 0x0c, 0x6e, 0xe5, 0xec, 0x9c, 0x3c,
-	0x86, 0x92, 0x73, 0xfe, 0x1b, 0xe9, 0xae, 0x07, 0xdc, 0x93, 0x36, 0xb8, 0x54, 0x64, 0x51, 0x1e,
-	0x40, 0x1b, 0x7d, 0x17, 0xc3, 0xf2, 0xd1, 0x7a, 0x3e, 0x1e, 0xa2, 0xd1, 0xa4, 0xfe, 0x85, 0x48,
-	0x7f, 0xe5, 0x0b, 0xd8, 0x3d, 0xa6, 0xc8, 0x34, 0xa4, 0xbe, 0x66, 0x98, 0xb9, 0x4c, 0xa9, 0xe2,
-	0xaa, 0x98, 0x2a, 0xae, 0x94, 0x73, 0xa8, 0xcd, 0x1c, 0x7d, 0x74, 0x49, 0x85, 0xc5, 0x78, 0x75,
-	0x26, 0x2d, 0x86, 0xdf, 0xb8, 0x77, 0x97, 0x6a, 0x9e, 0x63, 0x4b, 0xa0, 0x1c, 0xa1, 0x10, 0xed,
-	0x22, 0x2c, 0xe4, 0xf0, 0x93, 0x74, 0xa0, 0x6a, 0x89, 0xba, 0x5d, 0x9a, 0x29, 0x1c, 0x2a, 0xdf,
-	0x15, 0x79, 0x76, 0x91, 0x85, 0xd9, 0xa3, 0x84, 0x94, 0x96, 0xb8, 0x4c, 0xd1, 0x64, 0x0f, 0x6b,
-	0xc1, 0x6b, 0x24, 0x27, 0xe4, 0x94, 0x52, 0x72, 0x10, 0xa1, 0xe9, 0x98, 0x8a, 0x64, 0x4d, 0x21,
-	0x47, 0xb8, 0x7d, 0x5c, 0x71, 0xe9, 0xf9, 0x6e, 0xa8, 0x1a, 0x8e, 0xe7, 0xbe, 0xab, 0xfc, 0xa9,
-	0x00, 0xdb, 0xbc, 0xfe, 0x6c, 0x40, 0x75, 0x36, 0x9a, 0x0e, 0xc7, 0xd3, 0xe3, 0xf6, 0x16, 0x0e,
-	0xd4, 0xb3, 0xe9, 0x14, 0x07, 0x05, 0xd2, 0x84, 0xfa, 0xfc, 0x6c, 0x30, 0x18, 0x8d, 0x86, 0xa3,
-	0x61, 0xbb, 0x48, 0x00, 0x2a, 0x5f, 0xf6, 0xc7, 0x93, 0xd1, 0xb0, 0x5d, 0x42, 0xbe, 0xb3, 0xe9,
-	0x2f, 0xa6, 0xa7, 0xbf, 0x9a, 0xb6, 0xb7, 0x49, 0x0b, 0x60, 0x31, 0x3a, 0x19, 0x4f, 0xfb, 0x0b,
-	0xc4, 0x95, 0xc9, 0x0e, 0xd4, 0xfa, 0x47, 0xd3, 0x53, 0xf5, 0xa4, 0x3f, 0x69, 0x57, 0x70, 0x76,
-	0x3c, 0x1d, 0x2f, 0xc6, 0x62, 0xb6, 0x8a, 0xe3, 0xf9, 0xe0, 0xf5, 0x68, 0x78, 0x36, 0xc1, 0x71,
-	0x0d, 0xb9, 0xa7, 0xa7, 0x0b, 0x75, 0xd4, 0x1f, 0x7e, 0xd5, 0xae, 0xa3, 0xcc, 0xb3, 0xe9, 0xeb,
-	0x51, 0x7f, 0xb2, 0x78, 0xfd, 0x55, 0x1b, 0x94, 0x7f, 0x16, 0x60, 0x67, 0xe6, 0xe8, 0x71, 0x75,
-	0x78, 0x1b, 0xca, 0x86, 0x85, 0x16, 0x90, 0x4d, 0x27, 0x1f, 0x20, 0x95, 0xd7, 0x61, 0x61, 0xc2,
-	0xe1, 0x83, 0x84, 0x1d, 0x4b, 0x59, 0x3b, 0xf2, 0x9a, 0x8b, 0xea, 0x61, 0xc1, 0x2d, 0x87, 0x98,
-	0x26, 0x78, 0x7e, 0x58, 0x8a, 0xc4, 0x20, 0x6d, 0xd6, 0xe0, 0xb4, 0x13, 0x4e, 0x42, 0xd7, 0x17,
-	0x2c, 0x2b, 0x16, 0xc8, 0xda, 0xbb, 0xc6, 0x09, 0x03, 0x16, 0x60, 0x36, 0x92, 0x69, 0x28, 0x5c,
-	0xa1, 0x2a, 0x6a, 0x57, 0x49, 0x95, 0x6b, 0xdc, 0xc3, 0x72, 0x46, 0xb0, 0xe1, 0x2a, 0x35, 0x51,
-	0x27, 0x4a, 0xd2, 0x80, 0x05, 0xca, 0x3f, 0x84, 0xdf, 0x08, 0xcf, 0x46, 0xef, 0x4c, 0xd4, 0xc1,
-	0xfc, 0x9b, 0xd3, 0x1c, 0x3d, 0xdc, 0x30, 0xff, 0xce, 0x54, 0x97, 0xa5, 0x6c, 0x75, 0xf9, 0x30,
-	0xba, 0xcc, 0xdb, 0x71, 0x3d, 0x1e, 0x39, 0x60, 0x74, 0xb7, 0x45, 0x5c, 0x28, 0x47, 0x71, 0xe1,
-	0x0e, 0x54, 0x71, 0x75, 0xec, 0x42, 0xc4, 0x76, 0x2b, 0x38, 0x1c, 0x33, 0x34, 0xe3, 0x25, 0x75,
-	0x3d, 0xc3, 0xb1, 0xe5, 0x2e, 0xc3, 0x21, 0x79, 0x09, 0xbb, 0x86, 0x8d, 0x26, 0x8a, 0xdb, 0x10,
-	0x51, 0x2a, 0xb6, 0xa5, 0xc8, 0xb8, 0x0b, 0x68, 0x21, 0x63, 0xdc, 0x4a, 0x90, 0x8f, 0x52, 0xcd,
-	0x4b, 0xfd, 0x0a, 0x54, 0xb2, 0x57, 0x79, 0x00, 0x15, 0x8a, 0x97, 0xd8, 0x93, 0x65, 0xe1, 0x8e,
-	0xe4, 0xe6, 0x37, 0x5b, 0x95, 0x73, 0xca, 0x2b, 0x68, 0xcd, 0x7d, 0xc7, 0xd5, 0x2e, 0xe8, 0xc0,
-	0xd4, 0x78, 0x4d, 0xf9, 0x04, 0xb6, 0x4d, 0x83, 0x17, 0x1c, 0x51, 0x40, 0x4a, 0x72, 0xc8, 0xa8,
-	0xc2, 0x79, 0x94, 0x3f, 0x97, 0x80, 0x6c, 0x4e, 0xe6, 0x1e, 0xcc, 0x7d, 0x68, 0x30, 0xd7, 0xb9,
-	0x34, 0xd0, 0x10, 0xd4, 0x95, 0xe7, 0x93, 0x24, 0x91, 0x2f, 0x01, 0x98, 0xe6, 0x6a, 0x16, 0xf5,
-	0x71, 0x8b, 0x25, 0x2e, 0xfe, 0x51, 0xbe, 0xf8, 0xde, 0x2c, 0x62, 0x94, 0x4d, 0x5a, 0x8c, 0x14,
-	0xce, 0xb6, 0x32, 0x35, 0xc3, 0x5a, 0x32, 0xc7, 0x34, 0x56, 0x6b, 0xe9, 0xcd, 0x4d, 0x49, 0x9d,
-	0x71, 0x22, 0xf9, 0x04, 0x0e, 0x34, 0xd3, 0x74, 0xbe, 0x95, 0xdd, 0xdc, 0x92, 0xfe, 0x96, 0x69,
-	0x36, 0x3f, 0x35, 0x91, 0xb5, 0x6e, 0xf3, 0x59, 0xd1, 0xd8, 0x8d, 0xc2, 0x39, 0xd2, 0x83, 0x5b,
-	0x92, 0xff, 0xdc, 0xb0, 0x75, 0xac, 0x5c, 0x2c, 0x74, 0x37, 0xe1, 0x01, 0x7b, 0x62, 0xea, 0x48,
-	0xcc, 0x9c, 0xa0, 0xef, 0x1d, 0x03, 0xe1, 0xeb, 0x50, 0x7d, 0xe9, 0x3b, 0xcc, 0x31, 0x9d, 0x0b,
-	0x83, 0x86, 0xbd, 0x05, 0x6f, 0x64, 0x16, 0x82, 0xba, 0x9e, 0x53, 0x93, 0xae, 0x7c, 0xc7, 0x5d,
-	0x50, 0xd7, 0x52, 0xf7, 0x24, 0x66, 0x11, 0x41, 0xba, 0x3f, 0x85, 0xdd, 0xcc, 0xa6, 0x6f, 0x54,
-	0x60, 0xfa, 0x70, 0x3b, 0x4f, 0x12, 0xf9, 0x35, 0xdc, 0xb1, 0x34, 0x7f, 0xf5, 0xcd, 0xd2, 0xd4,
-	0xce, 0xa9, 0x89, 0x46, 0xc0, 0x12, 0xd8, 0x70, 0xec, 0xb0, 0x80, 0x7a, 0x90, 0xa7, 0xe4, 0x04,
-	0x99, 0xb1, 0x86, 0x34, 0x5c, 0x8a, 0x0d, 0x9c, 0xba, 0xcf, 0x17, 0xe1, 0xe4, 0x51, 0xbc, 0x84,
-	0x32, 0x81, 0xfb, 0xd7, 0x41, 0x73, 0x76, 0x71, 0x00, 0x15, 0xae, 0xb8, 0x78, 0x55, 0xa9, 0xab,
-	0x72, 0xa4, 0xfc, 0xad, 0x00, 0x5d, 0xd9, 0x5a, 0x88, 0x63, 0x49, 0x3f, 0x5e, 0x1d, 0x65, 0x1e,
-	0xaf, 0x9e, 0x24, 0x7a, 0xfb, 0x1c, 0xfe, 0xdc, 0x97, 0x2c, 0xf5, 0xba, 0x97, 0xac, 0x1f, 0x27,
-	0x2d, 0xdc, 0x3a, 0xbc, 0x73, 0x85, 0x8c, 0xa4, 0xe9, 0xff, 0x5e, 0x80, 0x7a, 0xf4, 0x42, 0x48,
-	0x9e, 0x25, 0xb4, 0xc4, 0x15, 0x6e, 0xe3, 0x0a, 0xd1, 0x74, 0x2f, 0x13, 0x74, 0xda, 0x50, 0xc2,
-	0x48, 0x28, 0xaa, 0x7b, 0xfc, 0x44, 0xe3, 0xc8, 0x10, 0x2a, 0x0a, 0x7a, 0x39, 0x52, 0x16, 0x50,
-	0x91, 0x12, 0xaa, 0x50, 0x9a, 0x8e, 0x27, 0xd9, 0xa4, 0x05, 0x50, 0x19, 0x4c, 0x4e, 0xe7, 0x3c,
-	0x63, 0x25, 0x13, 0x51, 0x09, 0x47, 0xf3, 0x45, 0x5f, 0xe5, 0x69, 0x68, 0x5b, 0x8c, 0x4e, 0x67,
-	0x33, 0x9e, 0xb2, 0x9e, 0x7c, 0x08, 0xb7, 0x72, 0x76, 0x47, 0xea, 0x50, 0x16, 0x89, 0x69, 0x0b,
-	0x13, 0xd3, 0xf4, 0x74, 0xb1, 0x14, 0xc3, 0xc2, 0xe1, 0x1f, 0xaa, 0xd0, 0xea, 0x33, 0xa6, 0x8a,
-	0x67, 0xd3, 0xf9, 0xda, 0x5e, 0x91, 0x23, 0x38, 0x38, 0xa6, 0x7e, 0xb4, 0xc5, 0x21, 0x65, 0x2e,
-	0x5d, 0x69, 0x98, 0x56, 0x6e, 0x25, 0xac, 0x17, 0x3e, 0x72, 0x76, 0xf7, 0x36, 0xde, 0x1c, 0x95,
-	0x2d, 0xf2, 0x31, 0xec, 0x24, 0xd7, 0x20, 0xed, 0x94, 0xd5, 0x54, 0xfa, 0xb6, 0xdb, 0x4c, 0x51,
-	0x94, 0x2d, 0xf2, 0x12, 0x40, 0x40, 0xf8, 0x53, 0x1d, 0x49, 0x88, 0x0a, 0x25, 0xe5, 0x3f, 0x79,
-	0x29, 0x5b, 0x64, 0xc8, 0x4b, 0x28, 0xfe, 0xf6, 0x16, 0xe2, 0x73, 0x55, 0xed, 0x5e, 0xfd, 0x44,
-	0xa7, 0x6c, 0x91, 0xe7, 0xd0, 0x3c, 0xa6, 0x7e, 0xe2, 0x1d, 0x25, 0x4f, 0x87, 0x56, 0xba, 0x59,
-	0x57, 0xb6, 0xc8, 0x2b, 0xd8, 0x3b, 0xa6, 0x7e, 0xa6, 0x19, 0xdc, 0x4b, 0x76, 0x18, 0x02, 0x99,
-	0xd3, 0x74, 0xf0, 0x5d, 0x93, 0x0d, 0xb4, 0x47, 0xea, 0xc8, 0xcb, 0x9f, 0xab, 0xbb, 0x07, 0xf9,
-	0x0d, 0x91, 0xb2, 0x45, 0x5e, 0xc3, 0x1d, 0xfc, 0xca, 0xab, 0x51, 0xf3, 0x34, 0xbf, 0x93, 0x5f,
-	0xaa, 0xa2, 0xe9, 0x07, 0xb0, 0x9f, 0xdb, 0xef, 0x10, 0xfe, 0xb2, 0x71, 0x65, 0x2b, 0xd4, 0x8d,
-	0xd5, 0x14, 0x8b, 0xe4, 0xf6, 0x2b, 0x62, 0x91, 0x2b, 0x5b, 0x99, 0x8d, 0x45, 0x72, 0x1b, 0x0e,
-	0x22, 0xdf, 0x58, 0xcc, 0x7f, 0x67, 0x91, 0x4f, 0xb8, 0xf3, 0xc5, 0x75, 0x07, 0xf7, 0x85, 0x4c,
-	0x8d, 0xdd, 0x0d, 0xab, 0x06, 0x41, 0xe1, 0x28, 0x3c, 0xc7, 0x4c, 0x72, 0x4d, 0x1c, 0x04, 0xc9,
-	0xa6, 0x36, 0x8a, 0xa6, 0xfb, 0x39, 0x3f, 0xbf, 0x3e, 0x63, 0xa9, 0xfb, 0x96, 0x67, 0xff, 0xf7,
-	0x7f, 0x38, 0xbc, 0x29, 0x5b, 0xe7, 0x15, 0xfe, 0xb7, 0xe2, 0x27, 0xff, 0x0a, 0x00, 0x00, 0xff,
-	0xff, 0xd3, 0x7f, 0x3d, 0x80, 0xc9, 0x18, 0x00, 0x00,
+	// 2429 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x39, 0x4b, 0x73, 0x1b, 0xc7,
+	0xd1, 0x78, 0x10, 0xaf, 0x06, 0x01, 0x82, 0x23, 0x91, 0x82, 0x60, 0xcb, 0x92, 0xd7, 0x92, 0x4a,
+	0x25, 0xe9, 0x83, 0x65, 0x5a, 0x2a, 0x4b, 0xb6, 0x3e, 0xbb, 0x20, 0x02, 0xa2, 0x90, 0x80, 0x20,
+	0x6a, 0x01, 0xda, 0xe5, 0xaa, 0x54, 0xa1, 0x96, 0xd8, 0x31, 0xbd, 0xd1, 0x3e, 0x46, 0xbb, 0x0b,
+	0x2a, 0xb8, 0x25, 0xf9, 0x0b, 0xf9, 0x09, 0x39, 0xe4, 0x90, 0x1c, 0x73, 0xc9, 0xc5, 0x39, 0xe6,
+	0x98, 0x9f, 0xe2, 0x4b, 0x7e, 0x40, 0xaa, 0x67, 0x66, 0x9f, 0x58, 0x9a, 0x62, 0xca, 0xa9, 0xdc,
+	0xb6, 0x7b, 0xba, 0xa7, 0x7b, 0x7a, 0xfa, 0x35, 0xbd, 0xd0, 0xd6, 0x18, 0x9b, 0xbb, 0x4b, 0xdb,
+	0x37, 0x2c, 0x3a, 0xf7, 0xa8, 0x7b, 0x46, 0xdd, 0x2e, 0x73, 0x1d, 0xdf, 0x21, 0x05, 0x76, 0xa2,
+	0x54, 0xa0, 0x34, 0xb0, 0x98, 0xbf, 0x52, 0xee, 0xc0, 0x66, 0x8f, 0xb1, 0xa9, 0xaf, 0xf9, 0x4b,
+	0x4f, 0xa5, 0x6f, 0xc8, 0x0e, 0x94, 0x91, 0xd1, 0xd0, 0xdb, 0xf9, 0x5b, 0xf9, 0x7b, 0x35, 0xb5,
+	0xa4, 0x31, 0x36, 0xd4, 0x95, 0x8f, 0xa1, 0x39, 0xa5, 0xee, 0x99, 0xb1, 0xa0, 0x2a, 0x7d, 0xb3,
+	0xa4, 0x9e, 0x4f, 0x6e, 0x00, 0x78, 0x02, 0x13, 0x11, 0xd7, 0x24, 0x66, 0xa8, 0x2b, 0x7b, 0xb0,
+	0x25, 0x19, 0xbc, 0x80, 0xe3, 0x26, 0xd4, 0x23, 0x0e, 0x4f, 0xb2, 0x40, 0xc8, 0xe2, 0x29, 0x0f,
+	0xa1, 0x31, 0xa3, 0xb6, 0x66, 0xfb, 0x01, 0xc7, 0x7b, 0x50, 0xf3, 0x39, 0x22, 0x12, 0x51, 0x15,
+	0x88, 0xa1, 0xae, 0xfc, 0x2e, 0x0f, 0x0d, 0xa1, 0xf7, 0x21, 0xf5, 0x3c, 0xed, 0x94, 0x92, 0x27,
+	0x50, 0xf6, 0x38, 0xa2, 0x9d, 0xbf, 0x55, 0xbc, 0x57, 0xdf, 0xbb, 0xd1, 0x65, 0x27, 0xdd, 0x04,
+	0x89, 0x84, 0x06, 0xb6, 0xef, 0xae, 0x54, 0x49, 0xdc, 0x79, 0x06, 0xf5, 0x18, 0x9a, 0xb4, 0xa0,
+	0xf8, 0x9a, 0xae, 0xa4, 0x38, 0xfc, 0x24, 0x57, 0xa1, 0x74, 0xa6, 0x99, 0x4b, 0xda, 0x2e, 0x08,
+	0x93, 0x70, 0xe0, 0xf3, 0xc2, 0xd3, 0xbc, 0xb2, 0x82, 0x7a, 0xdf, 0xf0, 0x5e, 0x07, 0x0a, 0x3c,
+	0x82, 0x92, 0x6e, 0x78, 0xaf, 0x03, 0xf9, 0x1d, 0x94, 0x1f, 0x5b, 0xe7, 0xdf, 0x52, 0xb8, 0x20,
+	0xec, 0x3c, 0x05, 0x88, 0x90, 0x17, 0x89, 0xce, 0xc7, 0x45, 0x5b, 0xb0, 0x2d, 0x0d, 0xdc, 0x63,
+	0x6c, 0xe2, 0xe8, 0x23, 0xc3, 0xf3, 0xc9, 0x03, 0xa8, 0x38, 0xa6, 0x3e, 0x71, 0xf4, 0x40, 0x85,
+	0x6d, 0x6e, 0x82, 0x38, 0x9d, 0x1a, 0x50, 0x20, 0xb1, 0x4d, 0xdf, 0x72, 0xe2, 0xc2, 0xb9, 0xc4,
+	0x92, 0x42, 0xf9, 0x21, 0x0f, 0xbb, 0x87, 0x4b, 0xd3, 0x37, 0xd6, 0x85, 0x1e, 0x86, 0xf7, 0x1a,
+	0x13, 0xfc, 0x00, 0xf7, 0xca, 0x66, 0xe8, 0x4e, 0x23, 0x6a, 0x61, 0x8c, 0x38, 0x7f, 0xe7, 0x18,
+	0x5a, 0x69, 0x82, 0x0c, 0xc3, 0x3c, 0x88, 0x1b, 0xa6, 0xbe, 0xb7, 0xb3, 0xa6, 0x3a, 0x4a, 0x8a,
+	0xdb, 0xeb, 0xc7, 0x02, 0x34, 0x12, 0x04, 0x17, 0x78, 0x30, 0x3a, 0x9f, 0x4e, 0x99, 0xe9, 0xac,
+	0x70, 0x55, 0xdc, 0x7c, 0x55, 0x20, 0x86, 0x3a, 0xfa, 0xb2, 0x5c, 0xf4, 0x57, 0x8c, 0xb6, 0x8b,
+	0x7c, 0x19, 0x04, 0x6a, 0xb6, 0x62, 0x94, 0x5c, 0x87, 0x2a, 0x73, 0xf4, 0xb9, 0xad, 0x59, 0xb4,
+	0xbd, 0xc1, 0x57, 0x2b, 0xcc, 0xd1, 0xc7, 0x9a, 0x45, 0x31, 0xc4, 0x70, 0xc9, 0x60, 0xed, 0x92,
+	0xf0, 0x27, 0xe6, 0xe8, 0x43, 0x86, 0xea, 0x20, 0x5a, 0x7a, 0x70, 0x59, 0xa8, 0xc3, 0x1c, 0x5d,
+	0xf8, 0x26, 0xe9, 0x01, 0x2c, 0x1c, 0xdb, 0xd7, 0x0c, 0x9b, 0xba, 0x5e, 0xbb, 0xc2, 0x8d, 0xfc,
+	0xe1, 0xda, 0xa9, 0xbb, 0xfb, 0x21, 0x8d, 0x30, 0x6d, 0x8c, 0x09, 0x95, 0x46, 0x09, 0x67, 0x8e,
+	0xb9, 0xb4, 0xa8, 0xd7, 0xae, 0xde, 0x2a, 0xa2, 0xd2, 0xcc, 0xd1, 0xbf, 0x16, 0x98, 0xce, 0x08,
+	0xb6, 0x52, 0xfc, 0x19, 0x96, 0xff, 0x28, 0x69, 0xf9, 0x06, 0xea, 0x10, 0x72, 0xc5, 0x2d, 0x7e,
+	0x06, 0xb5, 0x10, 0x4f, 0xee, 0x40, 0x33, 0xd4, 0x44, 0x58, 0x45, 0x6c, 0xd9, 0x08, 0xb1, 0xdc,
+	0x36, 0x1f, 0xc2, 0xa6, 0x45, 0x2d, 0xc7, 0x5d, 0xcd, 0x4d, 0xc3, 0x32, 0x7c, 0x2e, 0xa3, 0xa8,
+	0xd6, 0x05, 0x6e, 0x84, 0x28, 0x3c, 0xc5, 0x82, 0x2d, 0xe7, 0xae, 0xc8, 0x11, 0xdc, 0xf4, 0x45,
+	0x15, 0x16, 0x6c, 0x29, 0xb3, 0x86, 0xf2, 0x63, 0x19, 0xa0, 0x2f, 0x2e, 0xca, 0xfe, 0xce, 0x21,
+	0xef, 0x43, 0x0d, 0xe5, 0x79, 0x4c, 0x5b, 0x04, 0x42, 0x23, 0x04, 0x51, 0x60, 0x13, 0x2d, 0x4e,
+	0xbf, 0x5b, 0x9a, 0xd4, 0xa3, 0xbe, 0xbc, 0xe8, 0x04, 0x8e, 0x7c, 0x00, 0xf2, 0x66, 0x2d, 0x6a,
+	0xfb, 0xc9, 0xbb, 0x46, 0x0c, 0x77, 0x24, 0x5f, 0x73, 0xfd, 0xb9, 0x6f, 0x84, 0xb7, 0x5d, 0xe3,
+	0x98, 0x99, 0x61, 0x51, 0xf2, 0x10, 0x36, 0x18, 0x06, 0x46, 0x89, 0xdf, 0x59, 0x9b, 0x27, 0x85,
+	0x50, 0xbd, 0x6e, 0x14, 0x05, 0x9c, 0x8a, 0x3c, 0x85, 0xaa, 0xf4, 0x41, 0x74, 0x02, 0xe4, 0x78,
+	0x3f, 0xc5, 0x11, 0xe4, 0x55, 0xc1, 0x15, 0x52, 0x93, 0x2f, 0xa0, 0x46, 0x6d, 0x9d, 0x39, 0x86,
+	0xed, 0x07, 0x0e, 0x72, 0x23, 0xc5, 0x3a, 0x08, 0xd6, 0x05, 0x6f, 0x44, 0x4f, 0x9e, 0x40, 0xc5,
+	0xa3, 0x0b, 0x97, 0xfa, 0xc2, 0x2f, 0xea, 0x7b, 0xef, 0xad, 0x49, 0xe5, 0xab, 0x82, 0x31, 0xa0,
+	0x45, 0x99, 0x86, 0x7d, 0xea, 0x52, 0xcf, 0xa3, 0x5e, 0xbb, 0x96, 0x29, 0x73, 0x18, 0xac, 0x4b,
+	0x99, 0x21, 0x3d, 0xe9, 0x41, 0xdd, 0xa5, 0xcc, 0x34, 0x16, 0x9a, 0x8f, 0xa6, 0x07, 0xce, 0x7e,
+	0x33, 0xc5, 0xae, 0x46, 0x14, 0x32, 0x59, 0xc4, 0x78, 0xc8, 0x6e, 0x98, 0xf2, 0xeb, 0xdc, 0xec,
+	0x41, 0x4e, 0xff, 0x0c, 0x6a, 0x3f, 0x95, 0x3d, 0xce, 0xcd, 0xe8, 0x9d, 0x2f, 0xc2, 0x2c, 0xf1,
+	0x1f, 0x30, 0x3f, 0x87, 0x66, 0xd2, 0xc2, 0x97, 0xe2, 0xfe, 0x1c, 0x36, 0xe3, 0x46, 0xbe, 0xac,
+	0xe4, 0xa4, 0x9d, 0x2f, 0xc5, 0xfd, 0x25, 0xb4, 0xd2, 0x66, 0xbe, 0x54, 0x19, 0xfc, 0x4b, 0x01,
+	0x9a, 0x41, 0xe5, 0xf6, 0x9c, 0xa5, 0xbb, 0xa0, 0xe9, 0x28, 0xcd, 0xa7, 0xa3, 0x14, 0xd3, 0x2b,
+	0x12, 0xc4, 0xc3, 0xbc, 0xba, 0x60, 0x4b, 0x11, 0xe3, 0x77, 0xa0, 0x29, 0xd3, 0x40, 0x32, 0xcc,
+	0x1b, 0x02, 0x1b, 0xec, 0x91, 0xce, 0x16, 0x1b, 0xeb, 0xd9, 0xe2, 0x2e, 0x6c, 0xb9, 0x4b, 0xdb,
+	0x36, 0xec, 0xd3, 0x39, 0xf6, 0x35, 0xf6, 0xd2, 0xe2, 0x59, 0xb7, 0xa8, 0x36, 0x24, 0xba, 0xc7,
+	0xd8, 0x78, 0x69, 0x91, 0x4f, 0x60, 0x27, 0x4e, 0xe7, 0x7f, 0x6f, 0xb8, 0x3a, 0xa7, 0x06, 0x4e,
+	0x4d, 0x22, 0xea, 0x19, 0x2e, 0x21, 0xcb, 0x67, 0xd0, 0x8e, 0xb3, 0x18, 0xb6, 0x4f, 0x5d, 0x5b,
+	0x33, 0x39, 0x57, 0x9d, 0x73, 0xed, 0x44, 0x5c, 0x43, 0xb9, 0x3a, 0x5e, 0x5a, 0xca, 0x9f, 0xf3,
+	0x40, 0x92, 0xe6, 0xe2, 0x75, 0x74, 0x1f, 0x6a, 0xae, 0x84, 0x83, 0x2a, 0x7a, 0x07, 0x83, 0x61,
+	0x9d, 0xb4, 0x1b, 0x00, 0x41, 0x4c, 0x85, 0x7c, 0x9d, 0x09, 0x34, 0x93, 0x8b, 0x19, 0x17, 0x79,
+	0x2f, 0x99, 0xc1, 0xc9, 0xba, 0x90, 0xf8, 0xe5, 0xfe, 0x3e, 0x0f, 0xd7, 0x7b, 0xba, 0xce, 0x8f,
+	0x3d, 0xd1, 0x5c, 0x7f, 0x15, 0xba, 0x38, 0xf6, 0x8b, 0x04, 0x36, 0x96, 0xcb, 0xb0, 0x7c, 0xf2,
+	0x6f, 0x94, 0xe8, 0x85, 0x35, 0x13, 0x3f, 0x49, 0x13, 0x0a, 0x06, 0x93, 0x99, 0xb3, 0x60, 0x30,
+	0xe4, 0x62, 0x8e, 0x2b, 0x2e, 0xac, 0xa4, 0xf2, 0x6f, 0x74, 0x08, 0xc3, 0x9b, 0x3b, 0xb6, 0x69,
+	0xd8, 0x94, 0xdf, 0x51, 0x55, 0xad, 0x1a, 0xde, 0x11, 0x87, 0xb9, 0x12, 0xc7, 0xec, 0x7f, 0xac,
+	0x04, 0x85, 0xeb, 0x7d, 0x6a, 0xfe, 0xb7, 0x75, 0x50, 0xfe, 0x80, 0xee, 0xb1, 0x26, 0xe4, 0x67,
+	0x3c, 0x64, 0x94, 0x34, 0x4b, 0xf1, 0xa4, 0x99, 0x3c, 0x7c, 0x39, 0x75, 0xf8, 0xaf, 0xe0, 0x4a,
+	0xc6, 0xc9, 0xc9, 0x3d, 0x28, 0x3a, 0x27, 0xbf, 0x96, 0xee, 0xba, 0xcb, 0x3d, 0x69, 0x8d, 0x4a,
+	0x45, 0x12, 0xe5, 0x36, 0xb4, 0xd0, 0x77, 0x31, 0x2d, 0xbf, 0x58, 0x4d, 0x87, 0x7d, 0x34, 0x9a,
+	0xd4, 0x3f, 0x1f, 0xea, 0xaf, 0x7c, 0x09, 0x5b, 0x07, 0x14, 0x89, 0xfa, 0xd4, 0xd7, 0x0c, 0x33,
+	0x93, 0x28, 0xd1, 0x5c, 0x15, 0x12, 0xcd, 0x95, 0x72, 0x02, 0xd5, 0x89, 0xa3, 0x0f, 0xce, 0xa8,
+	0xb0, 0x18, 0xef, 0xce, 0xa4, 0xc5, 0xf0, 0x1b, 0xcf, 0xee, 0x52, 0xcd, 0x73, 0x6c, 0xc9, 0x28,
+	0x21, 0x14, 0xa2, 0x9d, 0x06, 0x8d, 0x1c, 0x7e, 0x92, 0x36, 0x54, 0x2c, 0xd1, 0xb7, 0x4b, 0x33,
+	0x05, 0xa0, 0xf2, 0x43, 0x81, 0x57, 0x17, 0xd9, 0x98, 0xdd, 0x8d, 0x49, 0x69, 0x8a, 0x60, 0x0a,
+	0x17, 0xbb, 0xd8, 0x0b, 0x5e, 0x20, 0x39, 0x26, 0xa7, 0x98, 0x90, 0x83, 0x1c, 0x9a, 0x8e, 0xa5,
+	0x48, 0xf6, 0x14, 0x12, 0xc2, 0xe3, 0xe3, 0x8e, 0x73, 0xcf, 0x77, 0x03, 0xd5, 0x10, 0x9e, 0xfa,
+	0xae, 0xf2, 0xc7, 0x3c, 0x6c, 0xa0, 0x4c, 0x52, 0x87, 0xca, 0x64, 0x30, 0xee, 0x0f, 0xc7, 0x07,
+	0xad, 0x1c, 0x02, 0xea, 0xf1, 0x78, 0x8c, 0x40, 0x9e, 0x34, 0xa0, 0x36, 0x3d, 0xde, 0xdf, 0x1f,
+	0x0c, 0xfa, 0x83, 0x7e, 0xab, 0x40, 0x00, 0xca, 0x2f, 0x7b, 0xc3, 0xd1, 0xa0, 0xdf, 0x2a, 0x22,
+	0xdd, 0xf1, 0xf8, 0x97, 0xe3, 0xa3, 0x6f, 0xc6, 0xad, 0x0d, 0xd2, 0x04, 0x98, 0x0d, 0x0e, 0x87,
+	0xe3, 0xde, 0x0c, 0xf9, 0x4a, 0x64, 0x13, 0xaa, 0xbd, 0x17, 0xe3, 0x23, 0xf5, 0xb0, 0x37, 0x6a,
+	0x95, 0x71, 0x75, 0x38, 0x1e, 0xce, 0x86, 0x62, 0xb5, 0x82, 0xf0, 0x74, 0xff, 0xd5, 0xa0, 0x7f,
+	0x3c, 0x42, 0xb8, 0x8a, 0xd4, 0xe3, 0xa3, 0x99, 0x3a, 0xe8, 0xf5, 0xbf, 0x6d, 0xd5, 0x50, 0xe6,
+	0xf1, 0xf8, 0xd5, 0xa0, 0x37, 0x9a, 0xbd, 0xfa, 0xb6, 0x05, 0xca, 0xbf, 0xf2, 0xb0, 0x39, 0x71,
+	0xf4, 0xa8, 0x3b, 0xbc, 0x0a, 0x25, 0xc3, 0xd2, 0x4e, 0x85, 0x11, 0x6b, 0xaa, 0x00, 0x10, 0xcb,
+	0xfb, 0xb0, 0xa0, 0xe0, 0x70, 0x20, 0x66, 0xc7, 0x62, 0xda, 0x8e, 0xbc, 0xe7, 0xa2, 0x7a, 0xd0,
+	0x70, 0x4b, 0x10, 0xcb, 0x04, 0xaf, 0x0f, 0x73, 0x51, 0x18, 0xa4, 0xcd, 0xea, 0x1c, 0x77, 0xc8,
+	0x51, 0xe8, 0xfa, 0x82, 0x64, 0xc1, 0x96, 0xb2, 0xf7, 0xae, 0x72, 0xc4, 0x3e, 0x5b, 0x62, 0x35,
+	0x92, 0x65, 0x28, 0xd8, 0xa1, 0x22, 0x7a, 0x57, 0x89, 0x95, 0x7b, 0xdc, 0x84, 0xba, 0x44, 0xf0,
+	0x5d, 0xaa, 0x9c, 0x06, 0x24, 0x6a, 0x9f, 0x2d, 0x95, 0x7f, 0x0a, 0xbf, 0x11, 0x9e, 0x8d, 0xde,
+	0x19, 0xeb, 0x83, 0xf9, 0x37, 0xc7, 0x39, 0x7a, 0x70, 0x60, 0xfe, 0x9d, 0xea, 0x2e, 0x8b, 0xe9,
+	0xee, 0xf2, 0x4e, 0x18, 0xcc, 0x1b, 0x51, 0x3f, 0x1e, 0x3a, 0x60, 0x18, 0xdb, 0x22, 0x2f, 0x94,
+	0xc2, 0xbc, 0x70, 0x0d, 0x2a, 0xb8, 0x3b, 0xbe, 0x42, 0xc4, 0x71, 0xcb, 0x08, 0x0e, 0x19, 0x9a,
+	0xf1, 0x8c, 0xba, 0x9e, 0xe1, 0xd8, 0xf2, 0x94, 0x01, 0x48, 0x9e, 0xc1, 0x96, 0x61, 0xa3, 0x89,
+	0xa2, 0x67, 0x88, 0x68, 0x15, 0x5b, 0x52, 0x64, 0xf4, 0x0a, 0x68, 0x22, 0x61, 0x08, 0x7a, 0xe4,
+	0x51, 0xe2, 0xf1, 0x52, 0x3b, 0x87, 0x2b, 0x46, 0x43, 0x6e, 0x43, 0x99, 0x62, 0x10, 0x7b, 0xb2,
+	0x2d, 0xdc, 0x94, 0xd4, 0x3c, 0xb2, 0x55, 0xb9, 0xa6, 0x3c, 0x87, 0xe6, 0xd4, 0x77, 0x5c, 0xed,
+	0x94, 0xee, 0x9b, 0x1a, 0xef, 0x29, 0xef, 0xc3, 0x86, 0x69, 0xf0, 0x86, 0x23, 0x4c, 0x48, 0x71,
+	0x0a, 0x99, 0x55, 0x38, 0x8d, 0xf2, 0xa7, 0x22, 0x90, 0xf5, 0xc5, 0xcc, 0x8b, 0xb9, 0x05, 0x75,
+	0xe6, 0x3a, 0x67, 0x06, 0x1a, 0x82, 0xba, 0xf2, 0x7e, 0xe2, 0x28, 0xf2, 0x12, 0x80, 0x69, 0xae,
+	0x66, 0x51, 0x1f, 0x8f, 0x58, 0xe4, 0xe2, 0xef, 0x66, 0x8b, 0xef, 0x4e, 0x42, 0x42, 0xf9, 0x48,
+	0x8b, 0x38, 0x85, 0xb3, 0x2d, 0x4c, 0xcd, 0xb0, 0xe6, 0xcc, 0x31, 0x8d, 0xc5, 0x4a, 0x7a, 0x73,
+	0x43, 0x62, 0x27, 0x1c, 0x49, 0x1e, 0xc3, 0xae, 0x66, 0x9a, 0xce, 0x5b, 0xf9, 0x9a, 0x9b, 0xd3,
+	0xdf, 0x30, 0xcd, 0xe6, 0xb7, 0x26, 0xaa, 0xd6, 0x55, 0xbe, 0x2a, 0x1e, 0x76, 0x83, 0x60, 0x8d,
+	0x74, 0xe1, 0x8a, 0xa4, 0x3f, 0x31, 0x6c, 0x1d, 0x3b, 0x17, 0x0b, 0xdd, 0x4d, 0x78, 0xc0, 0xb6,
+	0x58, 0x7a, 0x21, 0x56, 0x0e, 0xd1, 0xf7, 0x0e, 0x80, 0xf0, 0x7d, 0xa8, 0x3e, 0xf7, 0x1d, 0xe6,
+	0x98, 0xce, 0xa9, 0x41, 0x83, 0xb7, 0x05, 0x7f, 0xc8, 0xcc, 0x04, 0x76, 0x35, 0xa5, 0x26, 0x5d,
+	0xf8, 0x8e, 0x3b, 0xa3, 0xae, 0xa5, 0x6e, 0x4b, 0x9e, 0x59, 0xc8, 0xd2, 0xf9, 0x7f, 0xd8, 0x4a,
+	0x1d, 0xfa, 0x52, 0x0d, 0xa6, 0x0f, 0x57, 0xb3, 0x24, 0x91, 0x5f, 0xc1, 0x35, 0x4b, 0xf3, 0x17,
+	0xdf, 0xcf, 0x4d, 0xed, 0x84, 0x9a, 0x68, 0x04, 0x97, 0x7a, 0x78, 0xd2, 0xa0, 0x81, 0xba, 0x9d,
+	0xa5, 0xe4, 0x08, 0x89, 0xb1, 0x87, 0x34, 0x5c, 0x8a, 0x0f, 0x38, 0x75, 0x87, 0x6f, 0xc2, 0xd1,
+	0x83, 0x68, 0x0b, 0x65, 0x04, 0xb7, 0x2e, 0x62, 0xcd, 0x38, 0xc5, 0x2e, 0x94, 0xb9, 0xe2, 0x62,
+	0xaa, 0x52, 0x53, 0x25, 0xa4, 0xfc, 0x35, 0x0f, 0x1d, 0xf9, 0xb4, 0x10, 0xd7, 0x92, 0x1c, 0x5e,
+	0xbd, 0x48, 0x0d, 0xaf, 0xee, 0xc7, 0xde, 0xf6, 0x19, 0xf4, 0x99, 0x93, 0x2c, 0xf5, 0xa2, 0x49,
+	0xd6, 0xff, 0xc5, 0x2d, 0xdc, 0xdc, 0xbb, 0x76, 0x8e, 0x8c, 0xb8, 0xe9, 0xff, 0x96, 0x87, 0x5a,
+	0x38, 0x21, 0x24, 0x0f, 0x63, 0x5a, 0xe2, 0x0e, 0x57, 0x71, 0x87, 0x70, 0xb9, 0x9b, 0x4a, 0x3a,
+	0x2d, 0x28, 0x62, 0x26, 0x14, 0xdd, 0x3d, 0x7e, 0xa2, 0x71, 0x64, 0x0a, 0x15, 0x0d, 0xbd, 0x84,
+	0x94, 0x19, 0x94, 0xa5, 0x84, 0x0a, 0x14, 0xc7, 0xc3, 0x51, 0xba, 0x68, 0x01, 0x94, 0xf7, 0x47,
+	0x47, 0x53, 0x5e, 0xb1, 0xe2, 0x85, 0xa8, 0x88, 0xd0, 0x74, 0xd6, 0x53, 0x79, 0x19, 0xda, 0x10,
+	0xd0, 0xd1, 0x64, 0x82, 0x50, 0x49, 0xb1, 0x61, 0xfb, 0x1b, 0xbc, 0xd9, 0x77, 0x98, 0x70, 0xa6,
+	0xa7, 0x93, 0xe2, 0xee, 0x62, 0xd3, 0x49, 0xac, 0x22, 0x2e, 0xf5, 0x30, 0x76, 0x7c, 0xe7, 0x35,
+	0x0d, 0xaa, 0x4f, 0x5d, 0xe0, 0x66, 0x88, 0x52, 0xfe, 0x91, 0x87, 0x66, 0x28, 0x4b, 0xf4, 0x20,
+	0x19, 0xdd, 0x01, 0x57, 0x89, 0xaf, 0xc6, 0xba, 0x83, 0xe4, 0x30, 0xaa, 0x90, 0x1e, 0x46, 0x45,
+	0x2d, 0x5b, 0x31, 0xd1, 0xb2, 0x7d, 0x0a, 0x80, 0x87, 0x89, 0x55, 0x80, 0xf3, 0xee, 0xa4, 0xa6,
+	0x85, 0x97, 0x98, 0x3e, 0x49, 0x69, 0xfd, 0x24, 0x2a, 0x6c, 0x72, 0x35, 0xb1, 0x5b, 0x43, 0xa3,
+	0xad, 0xcd, 0x6e, 0x2f, 0xb2, 0x4e, 0x61, 0x7d, 0xcf, 0xbf, 0xe7, 0xa1, 0x31, 0x71, 0xf4, 0xe8,
+	0xf8, 0x3f, 0x97, 0x71, 0x3e, 0x82, 0x22, 0x73, 0x74, 0x6e, 0x99, 0xcc, 0x21, 0x26, 0xae, 0xe2,
+	0x09, 0x6c, 0xfa, 0x76, 0x1e, 0xd4, 0xb6, 0x0d, 0x9e, 0x25, 0xc1, 0xa6, 0x6f, 0xbf, 0x16, 0x98,
+	0x77, 0xb0, 0xca, 0xfd, 0x8f, 0xe1, 0x4a, 0x46, 0xb4, 0x90, 0x1a, 0x94, 0x44, 0xa3, 0x93, 0xc3,
+	0x46, 0x67, 0x7c, 0x34, 0x9b, 0x0b, 0x30, 0x7f, 0xbf, 0x07, 0xcd, 0xe4, 0x81, 0x04, 0xed, 0x74,
+	0x30, 0x6b, 0xe5, 0xf0, 0xb3, 0xd7, 0xc7, 0x26, 0x2c, 0x8f, 0x6e, 0x7b, 0x78, 0xd4, 0x1f, 0xbe,
+	0x1c, 0x72, 0x07, 0xaf, 0x43, 0xa5, 0x3f, 0x18, 0x0d, 0x66, 0xd8, 0x93, 0xed, 0xfd, 0xb6, 0xca,
+	0x7d, 0x4a, 0x15, 0x93, 0xfc, 0xe9, 0xca, 0x5e, 0x90, 0x17, 0xb0, 0x7b, 0x40, 0xfd, 0xf0, 0x86,
+	0xfb, 0x94, 0xb9, 0x74, 0xa1, 0x61, 0xa7, 0x73, 0x25, 0x76, 0xf8, 0x60, 0xee, 0xde, 0xd9, 0x5e,
+	0x1b, 0x83, 0x2b, 0x39, 0xf2, 0x09, 0x6c, 0xc6, 0xf7, 0x20, 0xad, 0x84, 0xd3, 0xa8, 0xf4, 0x4d,
+	0xa7, 0x91, 0xc0, 0x28, 0x39, 0xf2, 0x0c, 0x40, 0xb0, 0xf0, 0xe9, 0x31, 0x89, 0x89, 0x0a, 0x24,
+	0x65, 0x4f, 0x61, 0x95, 0x1c, 0xe9, 0xf3, 0xae, 0x9e, 0x8f, 0x83, 0x03, 0xfe, 0x4c, 0x55, 0x3b,
+	0xe7, 0x4f, 0x8d, 0x95, 0x1c, 0x79, 0x02, 0x8d, 0x03, 0xea, 0xc7, 0x46, 0x7b, 0x59, 0x3a, 0x34,
+	0x93, 0xf3, 0x23, 0x25, 0x47, 0x9e, 0xc3, 0xf6, 0x01, 0xf5, 0x53, 0xf3, 0x89, 0xed, 0xf8, 0xa3,
+	0x57, 0x70, 0x66, 0xbc, 0x83, 0xf9, 0xa9, 0xc9, 0x1a, 0xb7, 0x47, 0x6a, 0x48, 0xcb, 0xff, 0xa0,
+	0x74, 0x76, 0xb3, 0xdf, 0xe8, 0x4a, 0x8e, 0xbc, 0x82, 0x6b, 0xf8, 0x95, 0xf5, 0x6c, 0xca, 0xd2,
+	0xfc, 0x5a, 0xf6, 0xeb, 0x09, 0x4d, 0xbf, 0x0f, 0x3b, 0x99, 0x4f, 0x70, 0xc2, 0x87, 0x6d, 0xe7,
+	0xbe, 0xce, 0x3b, 0x91, 0x9a, 0x62, 0x93, 0x63, 0x76, 0xee, 0x26, 0xc7, 0xec, 0x5d, 0x37, 0xc9,
+	0x7c, 0x03, 0x13, 0x39, 0xf6, 0x33, 0xdf, 0x65, 0x93, 0xc7, 0xdc, 0xf9, 0xa2, 0x56, 0x98, 0xfb,
+	0x42, 0xea, 0xd9, 0xd7, 0x09, 0x1a, 0x59, 0x81, 0xe1, 0x5c, 0x78, 0x8f, 0xa9, 0x7e, 0x2f, 0x76,
+	0x11, 0x24, 0xdd, 0x6d, 0x51, 0x34, 0xdd, 0x2f, 0xf8, 0xfd, 0xf5, 0x18, 0x4b, 0x84, 0x6c, 0x96,
+	0xfd, 0x3f, 0xf8, 0xe9, 0x8a, 0xab, 0xe4, 0xc8, 0x57, 0x32, 0x9c, 0xa3, 0xb0, 0xd9, 0x09, 0x73,
+	0x56, 0x22, 0x76, 0x48, 0x22, 0x76, 0x78, 0xf4, 0x2b, 0xb9, 0x47, 0x79, 0xf2, 0x18, 0x6a, 0x61,
+	0x5a, 0x15, 0x21, 0x17, 0xcf, 0xb2, 0x22, 0x52, 0x13, 0x29, 0x12, 0xb9, 0x4e, 0xca, 0xfc, 0xbf,
+	0xdd, 0xa7, 0xff, 0x1e, 0x00, 0x9e, 0xa4, 0x87, 0x7a, 0xd3, 0x1b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2174,6 +2468,8 @@ type AppRuntimeSyncClient interface {
 	GetPodDetail(ctx context.Context, in *GetPodDetailReq, opts ...grpc.CallOption) (*PodDetail, error)
 	GetStorageClasses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*StorageClasses, error)
 	GetAppVolumeStatus(ctx context.Context, in *ServiceRequest, opts ...grpc.CallOption) (*ServiceVolumeStatusMessage, error)
+	WatchAppStatus(ctx context.Context, in *WatchAppStatusReq, opts ...grpc.CallOption) (AppRuntimeSync_WatchAppStatusClient, error)
+	WatchPods(ctx context.Context, in *WatchPodsReq, opts ...grpc.CallOption) (AppRuntimeSync_WatchPodsClient, error)
 }
 
 type appRuntimeSyncClient struct {
@@ -2310,6 +2606,70 @@ func (c *appRuntimeSyncClient) GetAppVolumeStatus(ctx context.Context, in *Servi
 	return out, nil
 }
 
+func (c *appRuntimeSyncClient) WatchAppStatus(ctx context.Context, in *WatchAppStatusReq, opts ...grpc.CallOption) (AppRuntimeSync_WatchAppStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AppRuntimeSync_serviceDesc.Streams[0], "/pb.AppRuntimeSync/WatchAppStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &appRuntimeSyncWatchAppStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AppRuntimeSync_WatchAppStatusClient interface {
+	Recv() (*AppStatusEvent, error)
+	grpc.ClientStream
+}
+
+type appRuntimeSyncWatchAppStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *appRuntimeSyncWatchAppStatusClient) Recv() (*AppStatusEvent, error) {
+	m := new(AppStatusEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *appRuntimeSyncClient) WatchPods(ctx context.Context, in *WatchPodsReq, opts ...grpc.CallOption) (AppRuntimeSync_WatchPodsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AppRuntimeSync_serviceDesc.Streams[1], "/pb.AppRuntimeSync/WatchPods", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &appRuntimeSyncWatchPodsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AppRuntimeSync_WatchPodsClient interface {
+	Recv() (*PodWatchEvent, error)
+	grpc.ClientStream
+}
+
+type appRuntimeSyncWatchPodsClient struct {
+	grpc.ClientStream
+}
+
+func (x *appRuntimeSyncWatchPodsClient) Recv() (*PodWatchEvent, error) {
+	m := new(PodWatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AppRuntimeSyncServer is the server API for AppRuntimeSync service.
 type AppRuntimeSyncServer interface {
 	// Deprecated: -
@@ -2327,6 +2687,8 @@ type AppRuntimeSyncServer interface {
 	GetPodDetail(context.Context, *GetPodDetailReq) (*PodDetail, error)
 	GetStorageClasses(context.Context, *Empty) (*StorageClasses, error)
 	GetAppVolumeStatus(context.Context, *ServiceRequest) (*ServiceVolumeStatusMessage, error)
+	WatchAppStatus(*WatchAppStatusReq, AppRuntimeSync_WatchAppStatusServer) error
+	WatchPods(*WatchPodsReq, AppRuntimeSync_WatchPodsServer) error
 }
 
 // UnimplementedAppRuntimeSyncServer can be embedded to have forward compatible implementations.
@@ -2375,6 +2737,12 @@ func (*UnimplementedAppRuntimeSyncServer) GetStorageClasses(ctx context.Context,
 func (*UnimplementedAppRuntimeSyncServer) GetAppVolumeStatus(ctx context.Context, req *ServiceRequest) (*ServiceVolumeStatusMessage, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAppVolumeStatus not implemented")
 }
+func (*UnimplementedAppRuntimeSyncServer) WatchAppStatus(req *WatchAppStatusReq, srv AppRuntimeSync_WatchAppStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAppStatus not implemented")
+}
+func (*UnimplementedAppRuntimeSyncServer) WatchPods(req *WatchPodsReq, srv AppRuntimeSync_WatchPodsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPods not implemented")
+}
 
 func RegisterAppRuntimeSyncServer(s *grpc.Server, srv AppRuntimeSyncServer) {
 	s.RegisterService(&_AppRuntimeSync_serviceDesc, srv)
@@ -2632,6 +3000,48 @@ func _AppRuntimeSync_GetAppVolumeStatus_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AppRuntimeSync_WatchAppStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAppStatusReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppRuntimeSyncServer).WatchAppStatus(m, &appRuntimeSyncWatchAppStatusServer{stream})
+}
+
+type AppRuntimeSync_WatchAppStatusServer interface {
+	Send(*AppStatusEvent) error
+	grpc.ServerStream
+}
+
+type appRuntimeSyncWatchAppStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *appRuntimeSyncWatchAppStatusServer) Send(m *AppStatusEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AppRuntimeSync_WatchPods_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPodsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AppRuntimeSyncServer).WatchPods(m, &appRuntimeSyncWatchPodsServer{stream})
+}
+
+type AppRuntimeSync_WatchPodsServer interface {
+	Send(*PodWatchEvent) error
+	grpc.ServerStream
+}
+
+type appRuntimeSyncWatchPodsServer struct {
+	grpc.ServerStream
+}
+
+func (x *appRuntimeSyncWatchPodsServer) Send(m *PodWatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _AppRuntimeSync_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.AppRuntimeSync",
 	HandlerType: (*AppRuntimeSyncServer)(nil),
@@ -2693,6 +3103,17 @@ var _AppRuntimeSync_serviceDesc = grpc.ServiceDesc{
 			Handler:    _AppRuntimeSync_GetAppVolumeStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAppStatus",
+			Handler:       _AppRuntimeSync_WatchAppStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchPods",
+			Handler:       _AppRuntimeSync_WatchPods_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "app_runtime_server.proto",
 }
@@ -192,28 +192,7 @@ func (r *RuntimeServer) GetAppPods(ctx context.Context, re *pb.ServiceRequest) (
 		if v1.IsPodNodeLost(pod) {
 			continue
 		}
-		var containers = make(map[string]*pb.Container, len(pod.Spec.Containers))
-		volumes := make([]string, 0)
-		for _, container := range pod.Spec.Containers {
-			containers[container.Name] = &pb.Container{
-				ContainerName: container.Name,
-				MemoryLimit:   container.Resources.Limits.Memory().Value(),
-				CpuRequest:    container.Resources.Requests.Cpu().MilliValue(),
-			}
-			for _, vm := range container.VolumeMounts {
-				volumes = append(volumes, vm.Name)
-			}
-		}
-
-		sapod := &pb.ServiceAppPod{
-			PodIp:      pod.Status.PodIP,
-			PodName:    pod.Name,
-			Containers: containers,
-			PodVolumes: volumes,
-		}
-		podStatus := &pb.PodStatus{}
-		wutil.DescribePodStatus(r.clientset, pod, podStatus, k8s.DefListEventsByPod)
-		sapod.PodStatus = podStatus.Type.String()
+		sapod := r.describeAppPod(pod)
 		if app.DistinguishPod(pod) {
 			newpods = append(newpods, sapod)
 		} else {
@@ -227,6 +206,32 @@ func (r *RuntimeServer) GetAppPods(ctx context.Context, re *pb.ServiceRequest) (
 	}, nil
 }
 
+func (r *RuntimeServer) describeAppPod(pod *corev1.Pod) *pb.ServiceAppPod {
+	var containers = make(map[string]*pb.Container, len(pod.Spec.Containers))
+	volumes := make([]string, 0)
+	for _, container := range pod.Spec.Containers {
+		containers[container.Name] = &pb.Container{
+			ContainerName: container.Name,
+			MemoryLimit:   container.Resources.Limits.Memory().Value(),
+			CpuRequest:    container.Resources.Requests.Cpu().MilliValue(),
+		}
+		for _, vm := range container.VolumeMounts {
+			volumes = append(volumes, vm.Name)
+		}
+	}
+
+	sapod := &pb.ServiceAppPod{
+		PodIp:      pod.Status.PodIP,
+		PodName:    pod.Name,
+		Containers: containers,
+		PodVolumes: volumes,
+	}
+	podStatus := &pb.PodStatus{}
+	wutil.DescribePodStatus(r.clientset, pod, podStatus, k8s.DefListEventsByPod)
+	sapod.PodStatus = podStatus.Type.String()
+	return sapod
+}
+
 //GetMultiAppPods get multi app pods
 func (r *RuntimeServer) GetMultiAppPods(ctx context.Context, re *pb.ServicesRequest) (*pb.MultiServiceAppPodList, error) {
 	serviceIDs := strings.Split(re.ServiceIds, ",")
@@ -0,0 +1,227 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package server
+
+import (
+	"time"
+
+	"github.com/gridworkz/kato/db"
+	"github.com/gridworkz/kato/util/k8s"
+	"github.com/gridworkz/kato/worker/appm/store"
+	v1 "github.com/gridworkz/kato/worker/appm/types/v1"
+	"github.com/gridworkz/kato/worker/server/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// statusRecheckInterval is how often watched component statuses are checked
+// again, status also changes without pod events, e.g. when a component is
+// being closed.
+var statusRecheckInterval = 10 * time.Second
+
+var (
+	errWatchLagged   = status.Error(codes.Unavailable, "watch fell behind, resume it with the last token")
+	errServerStopped = status.Error(codes.Unavailable, "runtime server is stopped")
+)
+
+// WatchPods streams the pod changes of the given components. With a valid
+// resume token the missed events are replayed, otherwise a RESET event is
+// sent followed by the current pods.
+func (r *RuntimeServer) WatchPods(req *pb.WatchPodsReq, stream pb.AppRuntimeSync_WatchPodsServer) error {
+	if len(req.ServiceIds) == 0 {
+		return status.Error(codes.InvalidArgument, "service_ids is required")
+	}
+	services := make(map[string]struct{}, len(req.ServiceIds))
+	for _, sid := range req.ServiceIds {
+		services[sid] = struct{}{}
+	}
+
+	watch, replay, err := r.store.WatchPods(req.ResumeToken)
+	defer watch.Stop()
+	if err == store.ErrResumeTokenExpired {
+		if err := stream.Send(&pb.PodWatchEvent{Type: pb.WatchEventType_RESET, ResumeToken: watch.Token}); err != nil {
+			return err
+		}
+		for sid := range services {
+			app := r.store.GetAppService(sid)
+			if app == nil {
+				continue
+			}
+			for _, pod := range app.GetPods(false) {
+				evt := r.podWatchEvent(&store.PodEvent{Type: store.CreateEvent, Pod: pod, Token: watch.Token})
+				if evt.Type == pb.WatchEventType_DELETED {
+					continue
+				}
+				if err := stream.Send(evt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	send := func(evt *store.PodEvent) error {
+		if _, ok := services[serviceIDOf(evt.Pod)]; !ok {
+			return nil
+		}
+		return stream.Send(r.podWatchEvent(evt))
+	}
+	for _, evt := range replay {
+		if err := send(evt); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-r.ctx.Done():
+			return errServerStopped
+		case evt, ok := <-watch.ResultChan():
+			if !ok {
+				return errWatchLagged
+			}
+			if err := send(evt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *RuntimeServer) podWatchEvent(evt *store.PodEvent) *pb.PodWatchEvent {
+	pod := evt.Pod
+	sid := serviceIDOf(pod)
+	res := &pb.PodWatchEvent{
+		ServiceId:   sid,
+		ResumeToken: evt.Token,
+	}
+	// terminated pods and pods of lost nodes are hidden by GetAppPods as well
+	if evt.Type == store.DeleteEvent || v1.IsPodTerminated(pod) || v1.IsPodNodeLost(pod) {
+		res.Type = pb.WatchEventType_DELETED
+		res.Pod = &pb.ServiceAppPod{ServiceId: sid, PodName: pod.Name}
+		return res
+	}
+	res.Type = pb.WatchEventType_MODIFIED
+	if evt.Type == store.CreateEvent {
+		res.Type = pb.WatchEventType_ADDED
+	}
+	res.Pod = r.describeAppPod(pod)
+	res.Pod.ServiceId = sid
+	if app := r.store.GetAppService(sid); app != nil {
+		res.NewVersion = app.DistinguishPod(pod)
+	}
+	return res
+}
+
+// WatchAppStatus streams the status changes of the given components, or of
+// every component of app_id. The current statuses are always sent first; a
+// RESET event precedes them if the resume token can not be resumed.
+func (r *RuntimeServer) WatchAppStatus(req *pb.WatchAppStatusReq, stream pb.AppRuntimeSync_WatchAppStatusServer) error {
+	serviceIDs := req.ServiceIds
+	if req.AppId != "" && len(serviceIDs) == 0 {
+		components, err := db.GetManager().TenantServiceDao().ListByAppID(req.AppId)
+		if err != nil {
+			return status.Errorf(codes.Internal, "list components of app %s: %v", req.AppId, err)
+		}
+		for _, component := range components {
+			serviceIDs = append(serviceIDs, component.ServiceID)
+		}
+	}
+	if len(serviceIDs) == 0 {
+		return status.Error(codes.InvalidArgument, "app_id or service_ids is required")
+	}
+	services := make(map[string]string, len(serviceIDs))
+	for _, sid := range serviceIDs {
+		services[sid] = ""
+	}
+
+	// the replayed events only move the token forward, the current
+	// statuses are sent anyway.
+	watch, replay, err := r.store.WatchPods(req.ResumeToken)
+	defer watch.Stop()
+	token := watch.Token
+	if len(replay) > 0 {
+		token = replay[len(replay)-1].Token
+	}
+	if err == store.ErrResumeTokenExpired {
+		if err := stream.Send(&pb.AppStatusEvent{Type: pb.WatchEventType_RESET, ResumeToken: token}); err != nil {
+			return err
+		}
+	}
+
+	check := func(sid string) error {
+		current := r.store.GetAppServiceStatus(sid)
+		if services[sid] == current {
+			return nil
+		}
+		services[sid] = current
+		evt := &pb.AppStatusEvent{
+			Type:        pb.WatchEventType_MODIFIED,
+			ServiceId:   sid,
+			Status:      current,
+			ResumeToken: token,
+		}
+		if req.AppId != "" {
+			evt.AppStatus, _ = r.store.GetAppStatus(req.AppId)
+		}
+		return stream.Send(evt)
+	}
+	checkAll := func() error {
+		for sid := range services {
+			if err := check(sid); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := checkAll(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(statusRecheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-r.ctx.Done():
+			return errServerStopped
+		case <-ticker.C:
+			if err := checkAll(); err != nil {
+				return err
+			}
+		case evt, ok := <-watch.ResultChan():
+			if !ok {
+				return errWatchLagged
+			}
+			token = evt.Token
+			if sid := serviceIDOf(evt.Pod); sid != "" {
+				if _, ok := services[sid]; ok {
+					if err := check(sid); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+func serviceIDOf(pod *corev1.Pod) string {
+	_, sid, _, _ := k8s.ExtractLabels(pod.GetLabels())
+	return sid
+}
@@ -0,0 +1,125 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gridworkz/kato/db/model"
+	"github.com/gridworkz/kato/util/cron"
+)
+
+// ScalingBounds is the replica floor and ceiling imposed by the active scheduled scaling policies.
+type ScalingBounds struct {
+	MinReplicas int
+	MaxReplicas int
+	// PolicyIDs the ids of the active policies
+	PolicyIDs []string
+}
+
+// Clamp returns replicas limited to the bounds.
+func (s *ScalingBounds) Clamp(replicas int) int {
+	if s == nil {
+		return replicas
+	}
+	if replicas < s.MinReplicas {
+		return s.MinReplicas
+	}
+	if replicas > s.MaxReplicas {
+		return s.MaxReplicas
+	}
+	return replicas
+}
+
+// String -
+func (s *ScalingBounds) String() string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d(%s)", s.MinReplicas, s.MaxReplicas, strings.Join(s.PolicyIDs, ","))
+}
+
+// IsScheduledScalingPolicyActive checks if the time t is within the window of the policy,
+// that is, the last activation of StartCron is later than the last activation of EndCron.
+func IsScheduledScalingPolicyActive(policy *model.TenantServiceScheduledScalingPolicy, t time.Time) (bool, error) {
+	loc := time.Local
+	if policy.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(policy.Timezone)
+		if err != nil {
+			return false, fmt.Errorf("load location %s: %v", policy.Timezone, err)
+		}
+	}
+	start, err := cron.Parse(policy.StartCron)
+	if err != nil {
+		return false, fmt.Errorf("parse start cron: %v", err)
+	}
+	end, err := cron.Parse(policy.EndCron)
+	if err != nil {
+		return false, fmt.Errorf("parse end cron: %v", err)
+	}
+
+	t = t.In(loc)
+	lastStart := start.Prev(t)
+	if lastStart.IsZero() {
+		return false, nil
+	}
+	lastEnd := end.Prev(t)
+	return lastStart.After(lastEnd), nil
+}
+
+// ActiveScalingBounds returns the bounds of the policies that are active at time t, or nil if there is none.
+// If more than one policy is active, the highest floor and the lowest ceiling win,
+// the floor wins if they overlap.
+func ActiveScalingBounds(policies []*model.TenantServiceScheduledScalingPolicy, t time.Time) (*ScalingBounds, error) {
+	var bounds *ScalingBounds
+	for _, policy := range policies {
+		if !policy.Enable {
+			continue
+		}
+		active, err := IsScheduledScalingPolicyActive(policy, t)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %v", policy.PolicyID, err)
+		}
+		if !active {
+			continue
+		}
+		if bounds == nil {
+			bounds = &ScalingBounds{MinReplicas: policy.MinReplicas, MaxReplicas: policy.MaxReplicas}
+		}
+		if policy.MinReplicas > bounds.MinReplicas {
+			bounds.MinReplicas = policy.MinReplicas
+		}
+		if policy.MaxReplicas < bounds.MaxReplicas {
+			bounds.MaxReplicas = policy.MaxReplicas
+		}
+		bounds.PolicyIDs = append(bounds.PolicyIDs, policy.PolicyID)
+	}
+	if bounds == nil {
+		return nil, nil
+	}
+	if bounds.MaxReplicas < bounds.MinReplicas {
+		bounds.MaxReplicas = bounds.MinReplicas
+	}
+	sort.Strings(bounds.PolicyIDs)
+	return bounds, nil
+}
@@ -0,0 +1,105 @@
+// KATO, Application Management Platform
+// Copyright (C) 2021 Gridworkz Co., Ltd.
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this 
+// software and associated documentation files (the "Software"), to deal in the Software
+// without restriction, including without limitation the rights to use, copy, modify, merge,
+// publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons 
+// to whom the Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all copies or 
+// substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, 
+// INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR
+// PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE
+// FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gridworkz/kato/db/model"
+)
+
+func TestActiveScalingBounds(t *testing.T) {
+	dayTime := &model.TenantServiceScheduledScalingPolicy{
+		PolicyID:    "day",
+		Enable:      true,
+		StartCron:   "0 8 * * 1-5",
+		EndCron:     "0 20 * * 1-5",
+		Timezone:    "UTC",
+		MinReplicas: 10,
+		MaxReplicas: 20,
+	}
+	night := &model.TenantServiceScheduledScalingPolicy{
+		PolicyID:    "night",
+		Enable:      true,
+		StartCron:   "0 20 * * *",
+		EndCron:     "0 8 * * *",
+		Timezone:    "UTC",
+		MinReplicas: 1,
+		MaxReplicas: 2,
+	}
+	promotion := &model.TenantServiceScheduledScalingPolicy{
+		PolicyID:    "promotion",
+		Enable:      true,
+		StartCron:   "0 12 * * *",
+		EndCron:     "0 14 * * *",
+		Timezone:    "UTC",
+		MinReplicas: 15,
+		MaxReplicas: 30,
+	}
+	policies := []*model.TenantServiceScheduledScalingPolicy{dayTime, night, promotion}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want *ScalingBounds
+	}{
+		{
+			name: "weekday morning",
+			t:    time.Date(2021, 5, 14, 9, 0, 0, 0, time.UTC),
+			want: &ScalingBounds{MinReplicas: 10, MaxReplicas: 20, PolicyIDs: []string{"day"}},
+		},
+		{
+			name: "weekday noon",
+			t:    time.Date(2021, 5, 14, 12, 30, 0, 0, time.UTC),
+			want: &ScalingBounds{MinReplicas: 15, MaxReplicas: 20, PolicyIDs: []string{"day", "promotion"}},
+		},
+		{
+			name: "weekday night",
+			t:    time.Date(2021, 5, 14, 23, 0, 0, 0, time.UTC),
+			want: &ScalingBounds{MinReplicas: 1, MaxReplicas: 2, PolicyIDs: []string{"night"}},
+		},
+		{
+			name: "weekend afternoon",
+			t:    time.Date(2021, 5, 15, 16, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tc := range tests {
+		got, err := ActiveScalingBounds(policies, tc.t)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got.String() != tc.want.String() {
+			t.Errorf("%s: want %s, but got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestScalingBoundsClamp(t *testing.T) {
+	bounds := &ScalingBounds{MinReplicas: 2, MaxReplicas: 5}
+	for replicas, want := range map[int]int{1: 2, 3: 3, 8: 5} {
+		if got := bounds.Clamp(replicas); got != want {
+			t.Errorf("clamp %d: want %d, but got %d", replicas, want, got)
+		}
+	}
+	var nilBounds *ScalingBounds
+	if got := nilBounds.Clamp(3); got != 3 {
+		t.Errorf("nil bounds should not change the replicas, but got %d", got)
+	}
+}